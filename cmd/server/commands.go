@@ -15,6 +15,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -27,11 +28,14 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/streamallocator"
 )
 
 func generateKeys(_ *cli.Context) error {
@@ -245,3 +249,55 @@ func listNodes(c *cli.Context) error {
 
 	return nil
 }
+
+func replayAllocations(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("recording to replay is required")
+	}
+
+	f, err := os.Open(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	result, err := streamallocator.Replay(f, logger.GetLogger())
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{
+		"At", "Type", "Capacity",
+		"Track", "Recorded", "Replayed",
+	})
+
+	numReplayed := 0
+	for _, allocation := range result.Allocations {
+		for idx := range allocation.Tracks {
+			track := &allocation.Tracks[idx]
+			numReplayed++
+			if !c.Bool("all") && !track.IsChanged() {
+				continue
+			}
+
+			table.Append([]string{
+				allocation.At.String(), allocation.Type.String(), humanize.Comma(allocation.AvailableChannelCapacity),
+				string(track.TrackID), formatAllocation(&track.Recorded), formatAllocation(&track.Replayed),
+			})
+		}
+	}
+	table.Render()
+
+	fmt.Printf("estimates: %d, probes: %d, replayed: %d, changed: %d, not replayable: %d\n",
+		result.NumEstimates, result.NumProbes, numReplayed, result.NumChanged(), result.NumNotReplayed)
+	if result.IsTruncated {
+		fmt.Println("recording is truncated")
+	}
+	return nil
+}
+
+func formatAllocation(allocation *sfu.VideoAllocation) string {
+	return fmt.Sprintf("%s %s\n%sbps", allocation.TargetLayer, allocation.PauseReason, humanize.Comma(allocation.BandwidthRequested))
+}
@@ -173,6 +173,18 @@ func main() {
 				Usage:  "list all nodes",
 				Action: listNodes,
 			},
+			{
+				Name:      "replay-allocations",
+				Usage:     "replays a recording of stream allocator decisions with current allocation logic",
+				ArgsUsage: "<recording>",
+				Action:    replayAllocations,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "list all replayed decisions, not only the ones which changed",
+					},
+				},
+			},
 			{
 				Name:   "help-verbose",
 				Usage:  "prints app help, including all generated configuration flags",
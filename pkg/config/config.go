@@ -28,6 +28,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/livekit/mediatransportutil/pkg/rtcconfig"
+	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	redisLiveKit "github.com/livekit/protocol/redis"
 	"github.com/livekit/protocol/rpc"
@@ -115,8 +116,70 @@ type RTCConfig struct {
 	// force a reconnect on a data channel error
 	ReconnectOnDataChannelError *bool `yaml:"reconnect_on_data_channel_error,omitempty"`
 
+	// force a reconnect when a migrated track cannot be matched unambiguously
+	StrictMigration *bool `yaml:"strict_migration,omitempty"`
+
+	// prefer protocol capabilities resolved on the node a participant migrated from,
+	// over those derived from protocol version on this node
+	PreferMigratedCapabilities *bool `yaml:"prefer_migrated_capabilities,omitempty"`
+
 	// max number of bytes to buffer for data channel. 0 means unlimited
 	DataChannelMaxBufferedAmount uint64 `yaml:"data_channel_max_buffered_amount,omitempty"`
+	// data channel is considered backpressured above this buffered amount, lossy data is not sent
+	// and reliable data is held back till it drains. 0 disables
+	DataChannelLowBufferedAmount uint64 `yaml:"data_channel_low_buffered_amount,omitempty"`
+	// limits reliable data sent to a participant, in bytes per second. 0 means unlimited
+	MaxReliableDataRate uint64 `yaml:"max_reliable_data_rate,omitempty"`
+	// reliable data above the rate is held back till it is within the rate, instead of being rejected
+	QueueReliableDataAboveRate bool `yaml:"queue_reliable_data_above_rate,omitempty"`
+	// reliable data packets kept per participant, those not acknowledged by the client when it is asked to do a full reconnect
+	// are carried over by the router and sent to its next session. Bounded by count and bytes, 0 means no limit.
+	// Not kept when both are 0
+	ReliableDataHistoryPackets int `yaml:"reliable_data_history_packets,omitempty"`
+	ReliableDataHistoryBytes   int `yaml:"reliable_data_history_bytes,omitempty"`
+	// carried over reliable data is dropped when the next session does not join within this window, defaults to 30s
+	ReliableDataHistoryExpiry time.Duration `yaml:"reliable_data_history_expiry,omitempty"`
+	// number of media RTT samples kept per participant, defaults to 12
+	RTTHistorySize int `yaml:"rtt_history_size,omitempty"`
+	// recorders do not get sender reports on their subscriber transport, for recorders which do their own timing
+	DisableRecorderSenderReports bool `yaml:"disable_recorder_sender_reports,omitempty"`
+
+	// alert on persistent RTCP write failures
+	RTCPWriteFailure RTCPWriteFailureConfig `yaml:"rtcp_write_failure,omitempty"`
+
+	// start new subscriptions paused until subscriber sends track settings
+	StartPausedSubscriptions StartPausedSubscriptionsConfig `yaml:"start_paused_subscriptions,omitempty"`
+
+	// bounds of subscribed track settings requested by subscribers
+	SubscribedTrackSettings SubscribedTrackSettingsConfig `yaml:"subscribed_track_settings,omitempty"`
+
+	// limits data packets received from a participant, per data channel kind
+	DataChannelRateLimit DataChannelRateLimitConfig `yaml:"data_channel_rate_limit,omitempty"`
+
+	// handling of publishers with clock skew in sender reports
+	ClockSkew ClockSkewConfig `yaml:"clock_skew,omitempty"`
+
+	// bounds track publish requests of a participant
+	PublishLimit PublishLimitConfig `yaml:"publish_limit,omitempty"`
+
+	// interval of participant state heartbeats sent to telemetry, 0 disables
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval,omitempty"`
+
+	// how often sender reports of subscribed tracks are sent to a participant, defaults to 3s
+	SenderReportInterval time.Duration `yaml:"sender_report_interval,omitempty"`
+	// number of source description items sent per RTCP batch along with sender reports, defaults to 30
+	SenderReportBatchSize int `yaml:"sender_report_batch_size,omitempty"`
+
+	// time given to a migrating participant to connect the subscriber peer connection on new node
+	// before the old one is closed, defaults to 3s
+	MigrationWaitDuration time.Duration `yaml:"migration_wait_duration,omitempty"`
+
+	// number of subscriber ICE candidates gathered before migration syncs that are buffered and sent
+	// once it does, candidates are dropped when 0
+	MaxPendingICECandidates int `yaml:"max_pending_ice_candidates,omitempty"`
+
+	// write packets to subscribers on a pool of workers shared by all tracks of the node
+	FanOut FanOutConfig `yaml:"fan_out,omitempty"`
 }
 
 type TURNServer struct {
@@ -127,12 +190,101 @@ type TURNServer struct {
 	Credential string `yaml:"credential,omitempty"`
 }
 
+type FanOutConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// number of workers, defaults to number of CPUs
+	Workers int `yaml:"workers,omitempty"`
+	// packets queued per subscriber of a track, packets are dropped for subscribers falling further behind.
+	// defaults to 128
+	QueueSize int `yaml:"queue_size,omitempty"`
+}
+
 type PLIThrottleConfig struct {
 	LowQuality  time.Duration `yaml:"low_quality,omitempty"`
 	MidQuality  time.Duration `yaml:"mid_quality,omitempty"`
 	HighQuality time.Duration `yaml:"high_quality,omitempty"`
+	// PLIs from subscribers of a track within the throttle of a layer are aggregated into one PLI
+	// sent when the throttle expires, instead of being dropped
+	CoalesceSubscriberPLI bool `yaml:"coalesce_subscriber_pli,omitempty"`
+}
+
+type ClockSkewConfig struct {
+	// re-initialize propagation delay estimate when clock skew persists, similar to a path change
+	ResetOnPersistentClockSkew bool `yaml:"reset_on_persistent_clock_skew,omitempty"`
+	// number of consecutive sender reports with clock skew considered persistent
+	PersistentClockSkewThreshold int `yaml:"persistent_clock_skew_threshold,omitempty"`
+	// deviation of clock rate calculated from sender reports, as a fraction of clock rate, considered clock skew.
+	// 0 uses the default of 0.2
+	Threshold float64 `yaml:"threshold,omitempty"`
+	// rewrite timestamps forwarded to subscribers when actual clock rate of a stream deviates from
+	// codec clock rate by more than this fraction, i. e. streams from gateways advertising a wrong clock rate.
+	// 0 disables correction
+	CorrectionThreshold float64 `yaml:"correction_threshold,omitempty"`
+}
+
+type RTCPWriteFailureConfig struct {
+	// number of consecutive RTCP write failures considered persistent, 0 disables
+	MaxConsecutive int `yaml:"max_consecutive,omitempty"`
+	// consecutive failures have to happen within this window
+	Window time.Duration `yaml:"window,omitempty"`
+	// number of consecutive subscriber RTCP write failures after which sender reports are backed off, 0 disables
+	SubscriberBackoffThreshold int `yaml:"subscriber_backoff_threshold,omitempty"`
+	// upper bound of interval between sender reports while backed off
+	SubscriberMaxBackoff time.Duration `yaml:"subscriber_max_backoff,omitempty"`
+}
+
+// SubscribedTrackSettingsConfig limits are disabled when zero
+type SubscribedTrackSettingsConfig struct {
+	// highest frame rate a subscriber can request, higher values are clamped
+	MaxFps uint32 `yaml:"max_fps,omitempty"`
+	// highest width or height a subscriber can request, higher values are clamped
+	MaxDimension uint32 `yaml:"max_dimension,omitempty"`
+}
+
+type StartPausedSubscriptionsConfig struct {
+	// video subscriptions do not forward media until subscriber sends track settings,
+	// applies to standard participants, recorders and other participant kinds are not paused
+	Enabled bool `yaml:"enabled,omitempty"`
+	// audio subscriptions are exempt unless enabled
+	IncludeAudio bool `yaml:"include_audio,omitempty"`
 }
 
+// DataChannelRateLimitConfig limits are disabled when zero
+type DataChannelRateLimitConfig struct {
+	BytesPerSec   uint64 `yaml:"bytes_per_sec,omitempty"`
+	PacketsPerSec uint64 `yaml:"packets_per_sec,omitempty"`
+	// allowed burst above the rate, for initial state sync after join, defaults to one second at the rate
+	BurstBytes   uint64 `yaml:"burst_bytes,omitempty"`
+	BurstPackets uint64 `yaml:"burst_packets,omitempty"`
+}
+
+// PublishLimitConfig limits are disabled when zero
+type PublishLimitConfig struct {
+	// tracks requested to be published yet to receive media
+	MaxPendingTracks          int `yaml:"max_pending_tracks,omitempty"`
+	MaxPendingTracksPerSource int `yaml:"max_pending_tracks_per_source,omitempty"`
+	// AddTrack requests allowed per track source in window
+	AddTrackPerSource int           `yaml:"add_track_per_source,omitempty"`
+	AddTrackWindow    time.Duration `yaml:"add_track_window,omitempty"`
+	// participant is closed after these many consecutive rejected requests
+	MaxRejections int `yaml:"max_rejections,omitempty"`
+	// pending tracks without media for longer are dropped, muted tracks are kept. 0 disables expiry
+	PendingTrackTimeout time.Duration `yaml:"pending_track_timeout,omitempty"`
+	// handling of a request to publish a track with the same source and name as a published or pending track
+	DuplicatePolicy DuplicatePublishPolicy `yaml:"duplicate_policy,omitempty"`
+}
+
+type DuplicatePublishPolicy string
+
+const (
+	// duplicate is published as a separate track, default
+	DuplicatePublishPolicyAllow DuplicatePublishPolicy = "allow"
+	// duplicate is not published
+	DuplicatePublishPolicyReject DuplicatePublishPolicy = "reject"
+	// duplicate is answered with the existing track, no new track is published
+	DuplicatePublishPolicyMerge DuplicatePublishPolicy = "merge"
+)
+
 type CongestionControlProbeConfig struct {
 	BaseInterval  time.Duration `yaml:"base_interval,omitempty"`
 	BackoffFactor float64       `yaml:"backoff_factor,omitempty"`
@@ -175,6 +327,29 @@ type CongestionControlConfig struct {
 	ChannelObserverProbeConfig       CongestionControlChannelObserverConfig `yaml:"channel_observer_probe_config,omitempty"`
 	ChannelObserverNonProbeConfig    CongestionControlChannelObserverConfig `yaml:"channel_observer_non_probe_config,omitempty"`
 	DisableEstimationUnmanagedTracks bool                                   `yaml:"disable_etimation_unmanaged_tracks,omitempty"`
+	// coalesces track allocations triggered by track changes within the interval, disabled when zero.
+	// allocations due to channel capacity changes are not delayed.
+	AllocationDebounceInterval time.Duration `yaml:"allocation_debounce_interval,omitempty"`
+	// when constrained, give up frame rate before resolution of subscribed video
+	PreferTemporalLayers bool `yaml:"prefer_temporal_layers,omitempty"`
+	// rooms whose subscribers prefer temporal layers when it is not enabled node wide,
+	// a name ending with * matches rooms by prefix
+	PreferTemporalLayersRooms []string `yaml:"prefer_temporal_layers_rooms,omitempty"`
+	// records allocation decisions of each subscriber for offline replay
+	AllocationRecorder AllocationRecorderConfig `yaml:"allocation_recorder,omitempty"`
+	// downlink estimate in bps by network type reported by the client (wifi, wired, cellular, vpn),
+	// used to seed subscriber bandwidth estimation on join and resume, network types not listed are not seeded
+	NetworkBandwidthHints map[string]int64 `yaml:"network_bandwidth_hints,omitempty"`
+}
+
+type AllocationRecorderConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// directory to write recordings to, one file per subscriber
+	Directory string `yaml:"directory,omitempty"`
+	// recording of a subscriber stops after this many bytes, 0 for no limit
+	MaxBytes int64 `yaml:"max_bytes,omitempty"`
+	// records beyond this rate are dropped, 0 for no limit
+	MaxRecordsPerSecond int `yaml:"max_records_per_second,omitempty"`
 }
 
 type AudioConfig struct {
@@ -192,6 +367,23 @@ type AudioConfig struct {
 	ActiveREDEncoding bool `yaml:"active_red_encoding,omitempty"`
 	// enable proxying weakest subscriber loss to publisher in RTCP Receiver Report
 	EnableLossProxying bool `yaml:"enable_loss_proxying,omitempty"`
+	// fill gaps of up to this many lost opus packets with silence frames when forwarding to subscribers,
+	// so that their jitter buffer does not stall waiting for a retransmission, 0 to disable.
+	// A lost packet is filled only if it has not arrived shortly after the gap.
+	GapFillMaxPackets uint32 `yaml:"gap_fill_max_packets,omitempty"`
+	// adapt redundancy of red encoded for opus only audio up track to loss reported by each subscriber
+	AdaptiveRED AdaptiveREDConfig `yaml:"adaptive_red,omitempty"`
+}
+
+type AdaptiveREDConfig struct {
+	// when enabled, red encoded for opus only audio up track carries redundant encodings
+	// only while subscriber reports sustained loss, primary encoding only otherwise
+	Enabled bool `yaml:"enabled,omitempty"`
+	// redundancy is turned on when loss is at or above OnLossPercentage and turned off
+	// when it is at or below OffLossPercentage, for MinReports consecutive receiver reports
+	OnLossPercentage  float64 `yaml:"on_loss_percentage,omitempty"`
+	OffLossPercentage float64 `yaml:"off_loss_percentage,omitempty"`
+	MinReports        int     `yaml:"min_reports,omitempty"`
 }
 
 type StreamTrackerPacketConfig struct {
@@ -220,11 +412,72 @@ type PlayoutDelayConfig struct {
 	Enabled bool `yaml:"enabled,omitempty"`
 	Min     int  `yaml:"min,omitempty"`
 	Max     int  `yaml:"max,omitempty"`
+	// track sources playout delay is applied to, one or more of camera, microphone, screen_share, screen_share_audio.
+	// When empty, it is applied to all video tracks.
+	Sources []string `yaml:"sources,omitempty"`
+}
+
+// TrackSources returns the configured sources, nil when playout delay is not restricted to sources
+func (c PlayoutDelayConfig) TrackSources() ([]livekit.TrackSource, error) {
+	if len(c.Sources) == 0 {
+		return nil, nil
+	}
+
+	sources := make([]livekit.TrackSource, 0, len(c.Sources))
+	for _, s := range c.Sources {
+		source, ok := livekit.TrackSource_value[strings.ToUpper(s)]
+		if !ok || livekit.TrackSource(source) == livekit.TrackSource_UNKNOWN {
+			return nil, fmt.Errorf("unknown track source %q", s)
+		}
+		sources = append(sources, livekit.TrackSource(source))
+	}
+	return sources, nil
+}
+
+// PrefersTemporalLayers returns true if subscribers in the room give up frame rate before resolution when constrained
+func (c CongestionControlConfig) PrefersTemporalLayers(roomName livekit.RoomName) bool {
+	if c.PreferTemporalLayers {
+		return true
+	}
+
+	for _, room := range c.PreferTemporalLayersRooms {
+		if prefix, ok := strings.CutSuffix(room, "*"); ok {
+			if strings.HasPrefix(string(roomName), prefix) {
+				return true
+			}
+		} else if room == string(roomName) {
+			return true
+		}
+	}
+	return false
+}
+
+// BandwidthHint returns the downlink estimate for the network type reported by the client, 0 if not known
+func (c CongestionControlConfig) BandwidthHint(network string) int64 {
+	if network == "" {
+		return 0
+	}
+	return c.NetworkBandwidthHints[strings.ToLower(network)]
 }
 
 type VideoConfig struct {
 	DynacastPauseDelay time.Duration        `yaml:"dynacast_pause_delay,omitempty"`
 	StreamTracker      StreamTrackersConfig `yaml:"stream_tracker,omitempty"`
+	// when set, a published video track that stops receiving packets for this long (without a mute signal)
+	// is treated as muted by publisher till packets resume, 0 disables detection
+	SilenceMuteTimeout time.Duration `yaml:"silence_mute_timeout,omitempty"`
+	// for debugging, log every Nth layer selection decision when forwarding video to subscribers,
+	// logged at debug level, 0 disables
+	LayerSelectionLogSampling uint32 `yaml:"layer_selection_log_sampling,omitempty"`
+	// when a subscriber unmutes video muted for less than this, for example a tile hidden briefly with
+	// adaptive stream, forwarding resumes at the previous layer without requesting a key frame, 0 disables
+	FastResumeWindow time.Duration `yaml:"fast_resume_window,omitempty"`
+	// when reference timestamp of a subscribed track is off from expected by more than this on a switch,
+	// for example after publisher encoder restarts, timestamp offset of forwarding is re-anchored, 0 disables
+	RefTSDiscontinuityThreshold time.Duration `yaml:"ref_ts_discontinuity_threshold,omitempty"`
+	// a simulcast layer which becomes available has to stay available for this long before subscribers
+	// are switched up to it, avoids quality pumping when a publisher layer flaps. Switching down is immediate, 0 disables
+	LayerUpHysteresis time.Duration `yaml:"layer_up_hysteresis,omitempty"`
 }
 
 type RoomConfig struct {
@@ -240,6 +493,25 @@ type RoomConfig struct {
 	SyncStreams                  bool               `yaml:"sync_streams,omitempty"`
 	MaxRoomNameLength            int                `yaml:"max_room_name_length,omitempty"`
 	MaxParticipantIdentityLength int                `yaml:"max_participant_identity_length,omitempty"`
+	// participants with data channel activity within this window are not considered idle, 0 disables
+	DataActivityIdleWindow time.Duration `yaml:"data_activity_idle_window,omitempty"`
+	// maximum number of subscribers of a published track on a node, 0 for no limit
+	MaxSubscribersPerTrack uint32 `yaml:"max_subscribers_per_track,omitempty"`
+	// subscribers keep receiving an unpublished track for this long before it is removed, 0 removes right away
+	UnpublishDrainDuration time.Duration `yaml:"unpublish_drain_duration,omitempty"`
+	// number of recent updates of each other participant kept by a participant, for debugging update ordering
+	MaxCachedUpdatesPerParticipant uint32 `yaml:"max_cached_updates_per_participant,omitempty"`
+	// participant updates are batched for a window growing with room size from min to max, 0 max disables batching
+	UpdateBatchMinWindow time.Duration `yaml:"update_batch_min_window,omitempty"`
+	UpdateBatchMaxWindow time.Duration `yaml:"update_batch_max_window,omitempty"`
+	// track SIDs of a departed participant are reused by a participant joining with the same identity within this window, 0 disables
+	TrackSIDReuseWindow time.Duration `yaml:"track_sid_reuse_window,omitempty"`
+	// count of full reconnects issued to a departed participant is carried over by the router to a participant
+	// joining the room with the same identity on any node within this window, 0 disables
+	ReconnectCountExpiry time.Duration `yaml:"reconnect_count_expiry,omitempty"`
+	// when set, a draining node exports resume state of its participants and asks them to migrate,
+	// the state is kept by the router for this long for the node a participant resumes on. 0 disables
+	ResumeStateExpiry time.Duration `yaml:"resume_state_expiry,omitempty"`
 }
 
 type CodecSpec struct {
@@ -298,6 +570,15 @@ type LimitConfig struct {
 	BytesPerSec            float32 `yaml:"bytes_per_sec,omitempty"`
 	SubscriptionLimitVideo int32   `yaml:"subscription_limit_video,omitempty"`
 	SubscriptionLimitAudio int32   `yaml:"subscription_limit_audio,omitempty"`
+	// limit on subscriptions to screen share tracks, applied in addition to video limit
+	SubscriptionLimitScreenShare int32 `yaml:"subscription_limit_screen_share,omitempty"`
+	// maximum number of signal requests of a participant waiting to be handled,
+	// when reached, metadata updates are dropped and the participant is notified
+	SignalQueueSize int `yaml:"signal_queue_size,omitempty"`
+	// CPU load above which intervals of periodic jobs off the media path (connection quality, stats) are lengthened, 0 disables
+	PeriodicJobShedCPULoad float64 `yaml:"periodic_job_shed_cpu_load,omitempty"`
+	// factor by which intervals of those periodic jobs are lengthened at full CPU load
+	PeriodicJobMaxIntervalScale float64 `yaml:"periodic_job_max_interval_scale,omitempty"`
 }
 
 type IngressConfig struct {
@@ -345,6 +626,19 @@ var DefaultConfig = Config{
 			MidQuality:  time.Second,
 			HighQuality: time.Second,
 		},
+		RTCPWriteFailure: RTCPWriteFailureConfig{
+			Window:                     time.Minute,
+			SubscriberBackoffThreshold: 3,
+			SubscriberMaxBackoff:       30 * time.Second,
+		},
+		ClockSkew: ClockSkewConfig{
+			PersistentClockSkewThreshold: 10,
+		},
+		SubscribedTrackSettings: SubscribedTrackSettingsConfig{
+			MaxFps:       120,
+			MaxDimension: 7680,
+		},
+		ReliableDataHistoryExpiry: 30 * time.Second,
 		CongestionControl: CongestionControlConfig{
 			Enabled:                true,
 			AllowPause:             false,
@@ -390,6 +684,10 @@ var DefaultConfig = Config{
 				NackWindowMaxDuration:          3 * time.Second,
 				NackRatioThreshold:             0.08,
 			},
+			AllocationRecorder: AllocationRecorderConfig{
+				MaxBytes:            16 * 1024 * 1024,
+				MaxRecordsPerSecond: 50,
+			},
 		},
 	},
 	Audio: AudioConfig{
@@ -397,6 +695,11 @@ var DefaultConfig = Config{
 		MinPercentile:   40,
 		UpdateInterval:  400,
 		SmoothIntervals: 2,
+		AdaptiveRED: AdaptiveREDConfig{
+			OnLossPercentage:  5,
+			OffLossPercentage: 2,
+			MinReports:        3,
+		},
 	},
 	Video: VideoConfig{
 		DynacastPauseDelay: 5 * time.Second,
@@ -490,6 +793,7 @@ var DefaultConfig = Config{
 		DepartureTimeout:             20,
 		MaxRoomNameLength:            256,
 		MaxParticipantIdentityLength: 256,
+		ReconnectCountExpiry:         10 * time.Minute,
 	},
 	Logging: LoggingConfig{
 		PionLevel: "error",
@@ -509,6 +813,10 @@ var DefaultConfig = Config{
 		MaxRetryInterval: 4 * time.Second,
 		StreamBufferSize: 1000,
 	},
+	Limit: LimitConfig{
+		SignalQueueSize:             256,
+		PeriodicJobMaxIntervalScale: 4,
+	},
 	PSRPC: rpc.DefaultPSRPCConfig,
 	Keys:  map[string]string{},
 }
@@ -544,6 +852,10 @@ func NewConfig(confString string, strictMode bool, c *cli.Context, baseFlags []c
 		return nil, fmt.Errorf("could not validate RTC config: %v", err)
 	}
 
+	if _, err := conf.Room.PlayoutDelay.TrackSources(); err != nil {
+		return nil, fmt.Errorf("could not validate playout delay config: %v", err)
+	}
+
 	// expand env vars in filenames
 	file, err := homedir.Expand(os.ExpandEnv(conf.KeyFile))
 	if err != nil {
@@ -20,6 +20,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
+
+	"github.com/livekit/protocol/livekit"
 )
 
 func TestConfig_UnmarshalKeys(t *testing.T) {
@@ -47,6 +49,52 @@ room:
 	require.Error(t, err)
 }
 
+func TestConfig_PlayoutDelaySources(t *testing.T) {
+	const content = `room:
+  playout_delay:
+    enabled: true
+    sources: [screen_share, screen_share_audio]`
+	conf, err := NewConfig(content, true, nil, nil)
+	require.NoError(t, err)
+	sources, err := conf.Room.PlayoutDelay.TrackSources()
+	require.NoError(t, err)
+	require.Equal(t, []livekit.TrackSource{livekit.TrackSource_SCREEN_SHARE, livekit.TrackSource_SCREEN_SHARE_AUDIO}, sources)
+
+	_, err = NewConfig(`room:
+  playout_delay:
+    sources: [webcam]`, true, nil, nil)
+	require.Error(t, err)
+}
+
+func TestConfig_PreferTemporalLayersRooms(t *testing.T) {
+	const content = `rtc:
+  congestion_control:
+    prefer_temporal_layers_rooms: [lecture-*, town-hall]`
+	conf, err := NewConfig(content, true, nil, nil)
+	require.NoError(t, err)
+	require.True(t, conf.RTC.CongestionControl.PrefersTemporalLayers("lecture-101"))
+	require.True(t, conf.RTC.CongestionControl.PrefersTemporalLayers("town-hall"))
+	require.False(t, conf.RTC.CongestionControl.PrefersTemporalLayers("town-hall-2"))
+	require.False(t, conf.RTC.CongestionControl.PrefersTemporalLayers("standup"))
+
+	conf.RTC.CongestionControl.PreferTemporalLayers = true
+	require.True(t, conf.RTC.CongestionControl.PrefersTemporalLayers("standup"))
+}
+
+func TestConfig_NetworkBandwidthHints(t *testing.T) {
+	const content = `rtc:
+  congestion_control:
+    network_bandwidth_hints:
+      cellular: 1500000
+      wifi: 5000000`
+	conf, err := NewConfig(content, true, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1_500_000), conf.RTC.CongestionControl.BandwidthHint("cellular"))
+	require.Equal(t, int64(5_000_000), conf.RTC.CongestionControl.BandwidthHint("WiFi"))
+	require.Zero(t, conf.RTC.CongestionControl.BandwidthHint("vpn"))
+	require.Zero(t, conf.RTC.CongestionControl.BandwidthHint(""))
+}
+
 func TestGeneratedFlags(t *testing.T) {
 	generatedFlags, err := GenerateCLIFlags(nil, false)
 	require.NoError(t, err)
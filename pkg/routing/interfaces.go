@@ -17,6 +17,7 @@ package routing
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/protobuf/proto"
@@ -79,6 +80,16 @@ type Router interface {
 	SetNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeId livekit.NodeID) error
 	ClearRoomState(ctx context.Context, roomName livekit.RoomName) error
 
+	// count of full reconnects issued to a departed participant, taken by the next session of the identity on any node
+	StoreReconnectCount(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, count uint32, expiry time.Duration) error
+	TakeReconnectCount(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) (uint32, error)
+	// reliable data not acknowledged by a departed participant, taken by the next session of the identity on any node
+	StoreReliableData(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, packets [][]byte, expiry time.Duration) error
+	TakeReliableData(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) ([][]byte, error)
+	// resume state of a participant migrating out of a draining node, taken when it resumes on another node
+	StoreResumeState(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, state []byte, expiry time.Duration) error
+	TakeResumeState(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) ([]byte, error)
+
 	GetRegion() string
 
 	Start() error
@@ -37,7 +37,33 @@ type LocalRouter struct {
 	// channels for each participant
 	requestChannels  map[string]*MessageChannel
 	responseChannels map[string]*MessageChannel
-	isStarted        atomic.Bool
+	// reconnect counts of departed participants
+	reconnectCounts map[departedParticipantKey]*departedReconnectCount
+	// reliable data not acknowledged by departed participants
+	reliableData map[departedParticipantKey]*departedReliableData
+	// resume state of participants migrating out of a draining node
+	resumeStates map[departedParticipantKey]*departedResumeState
+	isStarted    atomic.Bool
+}
+
+type departedParticipantKey struct {
+	roomName livekit.RoomName
+	identity livekit.ParticipantIdentity
+}
+
+type departedReconnectCount struct {
+	count     uint32
+	expiresAt time.Time
+}
+
+type departedReliableData struct {
+	packets   [][]byte
+	expiresAt time.Time
+}
+
+type departedResumeState struct {
+	state     []byte
+	expiresAt time.Time
 }
 
 func NewLocalRouter(currentNode LocalNode, signalClient SignalClient) *LocalRouter {
@@ -46,6 +72,9 @@ func NewLocalRouter(currentNode LocalNode, signalClient SignalClient) *LocalRout
 		signalClient:     signalClient,
 		requestChannels:  make(map[string]*MessageChannel),
 		responseChannels: make(map[string]*MessageChannel),
+		reconnectCounts:  make(map[departedParticipantKey]*departedReconnectCount),
+		reliableData:     make(map[departedParticipantKey]*departedReliableData),
+		resumeStates:     make(map[departedParticipantKey]*departedResumeState),
 	}
 }
 
@@ -64,6 +93,144 @@ func (r *LocalRouter) ClearRoomState(_ context.Context, _ livekit.RoomName) erro
 	return nil
 }
 
+func (r *LocalRouter) StoreReconnectCount(
+	_ context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	count uint32,
+	expiry time.Duration,
+) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	for key, departed := range r.reconnectCounts {
+		if now.After(departed.expiresAt) {
+			delete(r.reconnectCounts, key)
+		}
+	}
+
+	key := departedParticipantKey{roomName, identity}
+	if count == 0 || expiry <= 0 {
+		delete(r.reconnectCounts, key)
+		return nil
+	}
+	r.reconnectCounts[key] = &departedReconnectCount{
+		count:     count,
+		expiresAt: now.Add(expiry),
+	}
+	return nil
+}
+
+func (r *LocalRouter) TakeReconnectCount(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) (uint32, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := departedParticipantKey{roomName, identity}
+	departed := r.reconnectCounts[key]
+	if departed == nil {
+		return 0, nil
+	}
+
+	delete(r.reconnectCounts, key)
+	if time.Now().After(departed.expiresAt) {
+		return 0, nil
+	}
+	return departed.count, nil
+}
+
+func (r *LocalRouter) StoreReliableData(
+	_ context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	packets [][]byte,
+	expiry time.Duration,
+) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	for key, departed := range r.reliableData {
+		if now.After(departed.expiresAt) {
+			delete(r.reliableData, key)
+		}
+	}
+
+	key := departedParticipantKey{roomName, identity}
+	if len(packets) == 0 || expiry <= 0 {
+		delete(r.reliableData, key)
+		return nil
+	}
+	r.reliableData[key] = &departedReliableData{
+		packets:   packets,
+		expiresAt: now.Add(expiry),
+	}
+	return nil
+}
+
+func (r *LocalRouter) TakeReliableData(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) ([][]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := departedParticipantKey{roomName, identity}
+	departed := r.reliableData[key]
+	if departed == nil {
+		return nil, nil
+	}
+
+	delete(r.reliableData, key)
+	if time.Now().After(departed.expiresAt) {
+		return nil, nil
+	}
+	return departed.packets, nil
+}
+
+func (r *LocalRouter) StoreResumeState(
+	_ context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	state []byte,
+	expiry time.Duration,
+) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	for key, departed := range r.resumeStates {
+		if now.After(departed.expiresAt) {
+			delete(r.resumeStates, key)
+		}
+	}
+
+	key := departedParticipantKey{roomName, identity}
+	if len(state) == 0 || expiry <= 0 {
+		delete(r.resumeStates, key)
+		return nil
+	}
+	r.resumeStates[key] = &departedResumeState{
+		state:     state,
+		expiresAt: now.Add(expiry),
+	}
+	return nil
+}
+
+func (r *LocalRouter) TakeResumeState(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) ([]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := departedParticipantKey{roomName, identity}
+	departed := r.resumeStates[key]
+	if departed == nil {
+		return nil, nil
+	}
+
+	delete(r.resumeStates, key)
+	if time.Now().After(departed.expiresAt) {
+		return nil, nil
+	}
+	return departed.state, nil
+}
+
 func (r *LocalRouter) RegisterNode() error {
 	return nil
 }
@@ -0,0 +1,147 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+func TestLocalRouterReconnectCount(t *testing.T) {
+	ctx := context.Background()
+	take := func(t *testing.T, r *LocalRouter, roomName livekit.RoomName, identity livekit.ParticipantIdentity) uint32 {
+		count, err := r.TakeReconnectCount(ctx, roomName, identity)
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("carried over once", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreReconnectCount(ctx, "room", "p0", 3, time.Minute))
+
+		require.Zero(t, take(t, r, "other", "p0"))
+		require.Zero(t, take(t, r, "room", "p1"))
+		require.Equal(t, uint32(3), take(t, r, "room", "p0"))
+		require.Zero(t, take(t, r, "room", "p0"))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreReconnectCount(ctx, "room", "p0", 3, time.Minute))
+		r.reconnectCounts[departedParticipantKey{"room", "p0"}].expiresAt = time.Now().Add(-time.Second)
+		require.Zero(t, take(t, r, "room", "p0"))
+
+		// expired counts are removed when another count is stored
+		require.NoError(t, r.StoreReconnectCount(ctx, "room", "p1", 1, time.Minute))
+		r.reconnectCounts[departedParticipantKey{"room", "p1"}].expiresAt = time.Now().Add(-time.Second)
+		require.NoError(t, r.StoreReconnectCount(ctx, "room", "p2", 1, time.Minute))
+		require.Len(t, r.reconnectCounts, 1)
+	})
+
+	t.Run("zero count or expiry clears", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreReconnectCount(ctx, "room", "p0", 3, time.Minute))
+		require.NoError(t, r.StoreReconnectCount(ctx, "room", "p0", 0, time.Minute))
+		require.Zero(t, take(t, r, "room", "p0"))
+
+		require.NoError(t, r.StoreReconnectCount(ctx, "room", "p0", 3, 0))
+		require.Zero(t, take(t, r, "room", "p0"))
+	})
+}
+
+func TestLocalRouterReliableData(t *testing.T) {
+	ctx := context.Background()
+	take := func(t *testing.T, r *LocalRouter, roomName livekit.RoomName, identity livekit.ParticipantIdentity) [][]byte {
+		packets, err := r.TakeReliableData(ctx, roomName, identity)
+		require.NoError(t, err)
+		return packets
+	}
+	packets := [][]byte{[]byte("first"), []byte("second")}
+
+	t.Run("carried over once", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreReliableData(ctx, "room", "p0", packets, time.Minute))
+
+		require.Empty(t, take(t, r, "other", "p0"))
+		require.Empty(t, take(t, r, "room", "p1"))
+		require.Equal(t, packets, take(t, r, "room", "p0"))
+		require.Empty(t, take(t, r, "room", "p0"))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreReliableData(ctx, "room", "p0", packets, time.Minute))
+		r.reliableData[departedParticipantKey{"room", "p0"}].expiresAt = time.Now().Add(-time.Second)
+		require.Empty(t, take(t, r, "room", "p0"))
+
+		// expired data is removed when other data is stored
+		require.NoError(t, r.StoreReliableData(ctx, "room", "p1", packets, time.Minute))
+		r.reliableData[departedParticipantKey{"room", "p1"}].expiresAt = time.Now().Add(-time.Second)
+		require.NoError(t, r.StoreReliableData(ctx, "room", "p2", packets, time.Minute))
+		require.Len(t, r.reliableData, 1)
+	})
+
+	t.Run("no data or expiry clears", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreReliableData(ctx, "room", "p0", packets, time.Minute))
+		require.NoError(t, r.StoreReliableData(ctx, "room", "p0", nil, time.Minute))
+		require.Empty(t, take(t, r, "room", "p0"))
+
+		require.NoError(t, r.StoreReliableData(ctx, "room", "p0", packets, 0))
+		require.Empty(t, take(t, r, "room", "p0"))
+	})
+}
+
+func TestLocalRouterResumeState(t *testing.T) {
+	ctx := context.Background()
+	take := func(t *testing.T, r *LocalRouter, roomName livekit.RoomName, identity livekit.ParticipantIdentity) []byte {
+		state, err := r.TakeResumeState(ctx, roomName, identity)
+		require.NoError(t, err)
+		return state
+	}
+	state := []byte("state")
+
+	t.Run("taken once", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreResumeState(ctx, "room", "p0", state, time.Minute))
+
+		require.Empty(t, take(t, r, "other", "p0"))
+		require.Empty(t, take(t, r, "room", "p1"))
+		require.Equal(t, state, take(t, r, "room", "p0"))
+		require.Empty(t, take(t, r, "room", "p0"))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreResumeState(ctx, "room", "p0", state, time.Minute))
+		r.resumeStates[departedParticipantKey{"room", "p0"}].expiresAt = time.Now().Add(-time.Second)
+		require.Empty(t, take(t, r, "room", "p0"))
+	})
+
+	t.Run("no state or expiry clears", func(t *testing.T) {
+		r := NewLocalRouter(nil, nil)
+		require.NoError(t, r.StoreResumeState(ctx, "room", "p0", state, time.Minute))
+		require.NoError(t, r.StoreResumeState(ctx, "room", "p0", nil, time.Minute))
+		require.Empty(t, take(t, r, "room", "p0"))
+
+		require.NoError(t, r.StoreResumeState(ctx, "room", "p0", state, 0))
+		require.Empty(t, take(t, r, "room", "p0"))
+	})
+}
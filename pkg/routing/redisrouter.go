@@ -17,6 +17,7 @@ package routing
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"runtime/pprof"
 	"sync"
 	"time"
@@ -45,6 +46,15 @@ const (
 
 	// hash of room_name => node_id
 	NodeRoomKey = "room_node_map"
+
+	// prefix of keys holding count of full reconnects issued to a departed participant, expiring with the count
+	ReconnectCountKeyPrefix = "reconnect_count:"
+
+	// prefix of list keys holding reliable data not acknowledged by a departed participant
+	ReliableDataKeyPrefix = "reliable_data:"
+
+	// prefix of keys holding resume state of a participant migrating out of a draining node
+	ResumeStateKeyPrefix = "resume_state:"
 )
 
 var _ Router = (*RedisRouter)(nil)
@@ -131,6 +141,132 @@ func (r *RedisRouter) ClearRoomState(_ context.Context, roomName livekit.RoomNam
 	return nil
 }
 
+func (r *RedisRouter) StoreReconnectCount(
+	_ context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	count uint32,
+	expiry time.Duration,
+) error {
+	key := reconnectCountRedisKey(roomName, identity)
+	if count == 0 || expiry <= 0 {
+		return r.rc.Del(r.ctx, key).Err()
+	}
+	if err := r.rc.Set(r.ctx, key, count, expiry).Err(); err != nil {
+		return errors.Wrap(err, "could not store reconnect count")
+	}
+	return nil
+}
+
+func (r *RedisRouter) TakeReconnectCount(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) (uint32, error) {
+	key := reconnectCountRedisKey(roomName, identity)
+
+	var get *redis.StringCmd
+	_, err := r.rc.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(r.ctx, key)
+		pipe.Del(r.ctx, key)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return 0, errors.Wrap(err, "could not take reconnect count")
+	}
+
+	count, err := get.Uint64()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Wrap(err, "could not take reconnect count")
+	}
+	return uint32(count), nil
+}
+
+func (r *RedisRouter) StoreReliableData(
+	_ context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	packets [][]byte,
+	expiry time.Duration,
+) error {
+	key := reliableDataRedisKey(roomName, identity)
+	if len(packets) == 0 || expiry <= 0 {
+		return r.rc.Del(r.ctx, key).Err()
+	}
+
+	values := make([]interface{}, 0, len(packets))
+	for _, pkt := range packets {
+		values = append(values, pkt)
+	}
+	_, err := r.rc.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(r.ctx, key)
+		pipe.RPush(r.ctx, key, values...)
+		pipe.Expire(r.ctx, key, expiry)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not store reliable data")
+	}
+	return nil
+}
+
+func (r *RedisRouter) TakeReliableData(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) ([][]byte, error) {
+	key := reliableDataRedisKey(roomName, identity)
+
+	var lrange *redis.StringSliceCmd
+	_, err := r.rc.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		lrange = pipe.LRange(r.ctx, key, 0, -1)
+		pipe.Del(r.ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not take reliable data")
+	}
+
+	var packets [][]byte
+	for _, pkt := range lrange.Val() {
+		packets = append(packets, []byte(pkt))
+	}
+	return packets, nil
+}
+
+func (r *RedisRouter) StoreResumeState(
+	_ context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	state []byte,
+	expiry time.Duration,
+) error {
+	key := resumeStateRedisKey(roomName, identity)
+	if len(state) == 0 || expiry <= 0 {
+		return r.rc.Del(r.ctx, key).Err()
+	}
+	if err := r.rc.Set(r.ctx, key, state, expiry).Err(); err != nil {
+		return errors.Wrap(err, "could not store resume state")
+	}
+	return nil
+}
+
+func (r *RedisRouter) TakeResumeState(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) ([]byte, error) {
+	key := resumeStateRedisKey(roomName, identity)
+
+	var get *redis.StringCmd
+	_, err := r.rc.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(r.ctx, key)
+		pipe.Del(r.ctx, key)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, errors.Wrap(err, "could not take resume state")
+	}
+
+	state, err := get.Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "could not take resume state")
+	}
+	return state, nil
+}
+
 func (r *RedisRouter) GetNode(nodeID livekit.NodeID) (*livekit.Node, error) {
 	data, err := r.rc.HGet(r.ctx, NodesKey, string(nodeID)).Result()
 	if err == redis.Nil {
@@ -271,3 +407,16 @@ func (r *RedisRouter) keepaliveWorker(startedChan chan error) {
 		}
 	}
 }
+
+// room name is length prefixed, so that names and identities containing the separator do not collide
+func reconnectCountRedisKey(roomName livekit.RoomName, identity livekit.ParticipantIdentity) string {
+	return fmt.Sprintf("%s%d:%s:%s", ReconnectCountKeyPrefix, len(roomName), roomName, identity)
+}
+
+func reliableDataRedisKey(roomName livekit.RoomName, identity livekit.ParticipantIdentity) string {
+	return fmt.Sprintf("%s%d:%s:%s", ReliableDataKeyPrefix, len(roomName), roomName, identity)
+}
+
+func resumeStateRedisKey(roomName livekit.RoomName, identity livekit.ParticipantIdentity) string {
+	return fmt.Sprintf("%s%d:%s:%s", ResumeStateKeyPrefix, len(roomName), roomName, identity)
+}
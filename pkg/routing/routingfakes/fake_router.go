@@ -4,6 +4,7 @@ package routingfakes
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/protocol/livekit"
@@ -124,6 +125,96 @@ type FakeRouter struct {
 	stopMutex       sync.RWMutex
 	stopArgsForCall []struct {
 	}
+	StoreReconnectCountStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, uint32, time.Duration) error
+	storeReconnectCountMutex       sync.RWMutex
+	storeReconnectCountArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 uint32
+		arg5 time.Duration
+	}
+	storeReconnectCountReturns struct {
+		result1 error
+	}
+	storeReconnectCountReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreReliableDataStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, [][]byte, time.Duration) error
+	storeReliableDataMutex       sync.RWMutex
+	storeReliableDataArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 [][]byte
+		arg5 time.Duration
+	}
+	storeReliableDataReturns struct {
+		result1 error
+	}
+	storeReliableDataReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreResumeStateStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, []byte, time.Duration) error
+	storeResumeStateMutex       sync.RWMutex
+	storeResumeStateArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 []byte
+		arg5 time.Duration
+	}
+	storeResumeStateReturns struct {
+		result1 error
+	}
+	storeResumeStateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	TakeReconnectCountStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (uint32, error)
+	takeReconnectCountMutex       sync.RWMutex
+	takeReconnectCountArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+	}
+	takeReconnectCountReturns struct {
+		result1 uint32
+		result2 error
+	}
+	takeReconnectCountReturnsOnCall map[int]struct {
+		result1 uint32
+		result2 error
+	}
+	TakeReliableDataStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) ([][]byte, error)
+	takeReliableDataMutex       sync.RWMutex
+	takeReliableDataArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+	}
+	takeReliableDataReturns struct {
+		result1 [][]byte
+		result2 error
+	}
+	takeReliableDataReturnsOnCall map[int]struct {
+		result1 [][]byte
+		result2 error
+	}
+	TakeResumeStateStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) ([]byte, error)
+	takeResumeStateMutex       sync.RWMutex
+	takeResumeStateArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+	}
+	takeResumeStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	takeResumeStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	UnregisterNodeStub        func() error
 	unregisterNodeMutex       sync.RWMutex
 	unregisterNodeArgsForCall []struct {
@@ -710,6 +801,409 @@ func (fake *FakeRouter) StopCalls(stub func()) {
 	fake.StopStub = stub
 }
 
+func (fake *FakeRouter) StoreReconnectCount(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity, arg4 uint32, arg5 time.Duration) error {
+	fake.storeReconnectCountMutex.Lock()
+	ret, specificReturn := fake.storeReconnectCountReturnsOnCall[len(fake.storeReconnectCountArgsForCall)]
+	fake.storeReconnectCountArgsForCall = append(fake.storeReconnectCountArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 uint32
+		arg5 time.Duration
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.StoreReconnectCountStub
+	fakeReturns := fake.storeReconnectCountReturns
+	fake.recordInvocation("StoreReconnectCount", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.storeReconnectCountMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) StoreReconnectCountCallCount() int {
+	fake.storeReconnectCountMutex.RLock()
+	defer fake.storeReconnectCountMutex.RUnlock()
+	return len(fake.storeReconnectCountArgsForCall)
+}
+
+func (fake *FakeRouter) StoreReconnectCountCalls(stub func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, uint32, time.Duration) error) {
+	fake.storeReconnectCountMutex.Lock()
+	defer fake.storeReconnectCountMutex.Unlock()
+	fake.StoreReconnectCountStub = stub
+}
+
+func (fake *FakeRouter) StoreReconnectCountArgsForCall(i int) (context.Context, livekit.RoomName, livekit.ParticipantIdentity, uint32, time.Duration) {
+	fake.storeReconnectCountMutex.RLock()
+	defer fake.storeReconnectCountMutex.RUnlock()
+	argsForCall := fake.storeReconnectCountArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRouter) StoreReconnectCountReturns(result1 error) {
+	fake.storeReconnectCountMutex.Lock()
+	defer fake.storeReconnectCountMutex.Unlock()
+	fake.StoreReconnectCountStub = nil
+	fake.storeReconnectCountReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) StoreReconnectCountReturnsOnCall(i int, result1 error) {
+	fake.storeReconnectCountMutex.Lock()
+	defer fake.storeReconnectCountMutex.Unlock()
+	fake.StoreReconnectCountStub = nil
+	if fake.storeReconnectCountReturnsOnCall == nil {
+		fake.storeReconnectCountReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeReconnectCountReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) StoreReliableData(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity, arg4 [][]byte, arg5 time.Duration) error {
+	var arg4Copy [][]byte
+	if arg4 != nil {
+		arg4Copy = make([][]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.storeReliableDataMutex.Lock()
+	ret, specificReturn := fake.storeReliableDataReturnsOnCall[len(fake.storeReliableDataArgsForCall)]
+	fake.storeReliableDataArgsForCall = append(fake.storeReliableDataArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 [][]byte
+		arg5 time.Duration
+	}{arg1, arg2, arg3, arg4Copy, arg5})
+	stub := fake.StoreReliableDataStub
+	fakeReturns := fake.storeReliableDataReturns
+	fake.recordInvocation("StoreReliableData", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
+	fake.storeReliableDataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) StoreReliableDataCallCount() int {
+	fake.storeReliableDataMutex.RLock()
+	defer fake.storeReliableDataMutex.RUnlock()
+	return len(fake.storeReliableDataArgsForCall)
+}
+
+func (fake *FakeRouter) StoreReliableDataCalls(stub func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, [][]byte, time.Duration) error) {
+	fake.storeReliableDataMutex.Lock()
+	defer fake.storeReliableDataMutex.Unlock()
+	fake.StoreReliableDataStub = stub
+}
+
+func (fake *FakeRouter) StoreReliableDataArgsForCall(i int) (context.Context, livekit.RoomName, livekit.ParticipantIdentity, [][]byte, time.Duration) {
+	fake.storeReliableDataMutex.RLock()
+	defer fake.storeReliableDataMutex.RUnlock()
+	argsForCall := fake.storeReliableDataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRouter) StoreReliableDataReturns(result1 error) {
+	fake.storeReliableDataMutex.Lock()
+	defer fake.storeReliableDataMutex.Unlock()
+	fake.StoreReliableDataStub = nil
+	fake.storeReliableDataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) StoreReliableDataReturnsOnCall(i int, result1 error) {
+	fake.storeReliableDataMutex.Lock()
+	defer fake.storeReliableDataMutex.Unlock()
+	fake.StoreReliableDataStub = nil
+	if fake.storeReliableDataReturnsOnCall == nil {
+		fake.storeReliableDataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeReliableDataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) StoreResumeState(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity, arg4 []byte, arg5 time.Duration) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.storeResumeStateMutex.Lock()
+	ret, specificReturn := fake.storeResumeStateReturnsOnCall[len(fake.storeResumeStateArgsForCall)]
+	fake.storeResumeStateArgsForCall = append(fake.storeResumeStateArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 []byte
+		arg5 time.Duration
+	}{arg1, arg2, arg3, arg4Copy, arg5})
+	stub := fake.StoreResumeStateStub
+	fakeReturns := fake.storeResumeStateReturns
+	fake.recordInvocation("StoreResumeState", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
+	fake.storeResumeStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) StoreResumeStateCallCount() int {
+	fake.storeResumeStateMutex.RLock()
+	defer fake.storeResumeStateMutex.RUnlock()
+	return len(fake.storeResumeStateArgsForCall)
+}
+
+func (fake *FakeRouter) StoreResumeStateCalls(stub func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, []byte, time.Duration) error) {
+	fake.storeResumeStateMutex.Lock()
+	defer fake.storeResumeStateMutex.Unlock()
+	fake.StoreResumeStateStub = stub
+}
+
+func (fake *FakeRouter) StoreResumeStateArgsForCall(i int) (context.Context, livekit.RoomName, livekit.ParticipantIdentity, []byte, time.Duration) {
+	fake.storeResumeStateMutex.RLock()
+	defer fake.storeResumeStateMutex.RUnlock()
+	argsForCall := fake.storeResumeStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRouter) StoreResumeStateReturns(result1 error) {
+	fake.storeResumeStateMutex.Lock()
+	defer fake.storeResumeStateMutex.Unlock()
+	fake.StoreResumeStateStub = nil
+	fake.storeResumeStateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) StoreResumeStateReturnsOnCall(i int, result1 error) {
+	fake.storeResumeStateMutex.Lock()
+	defer fake.storeResumeStateMutex.Unlock()
+	fake.StoreResumeStateStub = nil
+	if fake.storeResumeStateReturnsOnCall == nil {
+		fake.storeResumeStateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeResumeStateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) TakeReconnectCount(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) (uint32, error) {
+	fake.takeReconnectCountMutex.Lock()
+	ret, specificReturn := fake.takeReconnectCountReturnsOnCall[len(fake.takeReconnectCountArgsForCall)]
+	fake.takeReconnectCountArgsForCall = append(fake.takeReconnectCountArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+	}{arg1, arg2, arg3})
+	stub := fake.TakeReconnectCountStub
+	fakeReturns := fake.takeReconnectCountReturns
+	fake.recordInvocation("TakeReconnectCount", []interface{}{arg1, arg2, arg3})
+	fake.takeReconnectCountMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) TakeReconnectCountCallCount() int {
+	fake.takeReconnectCountMutex.RLock()
+	defer fake.takeReconnectCountMutex.RUnlock()
+	return len(fake.takeReconnectCountArgsForCall)
+}
+
+func (fake *FakeRouter) TakeReconnectCountCalls(stub func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (uint32, error)) {
+	fake.takeReconnectCountMutex.Lock()
+	defer fake.takeReconnectCountMutex.Unlock()
+	fake.TakeReconnectCountStub = stub
+}
+
+func (fake *FakeRouter) TakeReconnectCountArgsForCall(i int) (context.Context, livekit.RoomName, livekit.ParticipantIdentity) {
+	fake.takeReconnectCountMutex.RLock()
+	defer fake.takeReconnectCountMutex.RUnlock()
+	argsForCall := fake.takeReconnectCountArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) TakeReconnectCountReturns(result1 uint32, result2 error) {
+	fake.takeReconnectCountMutex.Lock()
+	defer fake.takeReconnectCountMutex.Unlock()
+	fake.TakeReconnectCountStub = nil
+	fake.takeReconnectCountReturns = struct {
+		result1 uint32
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) TakeReconnectCountReturnsOnCall(i int, result1 uint32, result2 error) {
+	fake.takeReconnectCountMutex.Lock()
+	defer fake.takeReconnectCountMutex.Unlock()
+	fake.TakeReconnectCountStub = nil
+	if fake.takeReconnectCountReturnsOnCall == nil {
+		fake.takeReconnectCountReturnsOnCall = make(map[int]struct {
+			result1 uint32
+			result2 error
+		})
+	}
+	fake.takeReconnectCountReturnsOnCall[i] = struct {
+		result1 uint32
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) TakeReliableData(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) ([][]byte, error) {
+	fake.takeReliableDataMutex.Lock()
+	ret, specificReturn := fake.takeReliableDataReturnsOnCall[len(fake.takeReliableDataArgsForCall)]
+	fake.takeReliableDataArgsForCall = append(fake.takeReliableDataArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+	}{arg1, arg2, arg3})
+	stub := fake.TakeReliableDataStub
+	fakeReturns := fake.takeReliableDataReturns
+	fake.recordInvocation("TakeReliableData", []interface{}{arg1, arg2, arg3})
+	fake.takeReliableDataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) TakeReliableDataCallCount() int {
+	fake.takeReliableDataMutex.RLock()
+	defer fake.takeReliableDataMutex.RUnlock()
+	return len(fake.takeReliableDataArgsForCall)
+}
+
+func (fake *FakeRouter) TakeReliableDataCalls(stub func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) ([][]byte, error)) {
+	fake.takeReliableDataMutex.Lock()
+	defer fake.takeReliableDataMutex.Unlock()
+	fake.TakeReliableDataStub = stub
+}
+
+func (fake *FakeRouter) TakeReliableDataArgsForCall(i int) (context.Context, livekit.RoomName, livekit.ParticipantIdentity) {
+	fake.takeReliableDataMutex.RLock()
+	defer fake.takeReliableDataMutex.RUnlock()
+	argsForCall := fake.takeReliableDataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) TakeReliableDataReturns(result1 [][]byte, result2 error) {
+	fake.takeReliableDataMutex.Lock()
+	defer fake.takeReliableDataMutex.Unlock()
+	fake.TakeReliableDataStub = nil
+	fake.takeReliableDataReturns = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) TakeReliableDataReturnsOnCall(i int, result1 [][]byte, result2 error) {
+	fake.takeReliableDataMutex.Lock()
+	defer fake.takeReliableDataMutex.Unlock()
+	fake.TakeReliableDataStub = nil
+	if fake.takeReliableDataReturnsOnCall == nil {
+		fake.takeReliableDataReturnsOnCall = make(map[int]struct {
+			result1 [][]byte
+			result2 error
+		})
+	}
+	fake.takeReliableDataReturnsOnCall[i] = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) TakeResumeState(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) ([]byte, error) {
+	fake.takeResumeStateMutex.Lock()
+	ret, specificReturn := fake.takeResumeStateReturnsOnCall[len(fake.takeResumeStateArgsForCall)]
+	fake.takeResumeStateArgsForCall = append(fake.takeResumeStateArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+	}{arg1, arg2, arg3})
+	stub := fake.TakeResumeStateStub
+	fakeReturns := fake.takeResumeStateReturns
+	fake.recordInvocation("TakeResumeState", []interface{}{arg1, arg2, arg3})
+	fake.takeResumeStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) TakeResumeStateCallCount() int {
+	fake.takeResumeStateMutex.RLock()
+	defer fake.takeResumeStateMutex.RUnlock()
+	return len(fake.takeResumeStateArgsForCall)
+}
+
+func (fake *FakeRouter) TakeResumeStateCalls(stub func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) ([]byte, error)) {
+	fake.takeResumeStateMutex.Lock()
+	defer fake.takeResumeStateMutex.Unlock()
+	fake.TakeResumeStateStub = stub
+}
+
+func (fake *FakeRouter) TakeResumeStateArgsForCall(i int) (context.Context, livekit.RoomName, livekit.ParticipantIdentity) {
+	fake.takeResumeStateMutex.RLock()
+	defer fake.takeResumeStateMutex.RUnlock()
+	argsForCall := fake.takeResumeStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) TakeResumeStateReturns(result1 []byte, result2 error) {
+	fake.takeResumeStateMutex.Lock()
+	defer fake.takeResumeStateMutex.Unlock()
+	fake.TakeResumeStateStub = nil
+	fake.takeResumeStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) TakeResumeStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.takeResumeStateMutex.Lock()
+	defer fake.takeResumeStateMutex.Unlock()
+	fake.TakeResumeStateStub = nil
+	if fake.takeResumeStateReturnsOnCall == nil {
+		fake.takeResumeStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.takeResumeStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRouter) UnregisterNode() error {
 	fake.unregisterNodeMutex.Lock()
 	ret, specificReturn := fake.unregisterNodeReturnsOnCall[len(fake.unregisterNodeArgsForCall)]
@@ -788,6 +1282,18 @@ func (fake *FakeRouter) Invocations() map[string][][]interface{} {
 	defer fake.startParticipantSignalMutex.RUnlock()
 	fake.stopMutex.RLock()
 	defer fake.stopMutex.RUnlock()
+	fake.storeReconnectCountMutex.RLock()
+	defer fake.storeReconnectCountMutex.RUnlock()
+	fake.storeReliableDataMutex.RLock()
+	defer fake.storeReliableDataMutex.RUnlock()
+	fake.storeResumeStateMutex.RLock()
+	defer fake.storeResumeStateMutex.RUnlock()
+	fake.takeReconnectCountMutex.RLock()
+	defer fake.takeReconnectCountMutex.RUnlock()
+	fake.takeReliableDataMutex.RLock()
+	defer fake.takeReliableDataMutex.RUnlock()
+	fake.takeResumeStateMutex.RLock()
+	defer fake.takeResumeStateMutex.RUnlock()
 	fake.unregisterNodeMutex.RLock()
 	defer fake.unregisterNodeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
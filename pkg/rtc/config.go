@@ -18,14 +18,17 @@ import (
 	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v3"
 
+	"github.com/livekit/protocol/logger"
+
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/mediatransportutil/pkg/rtcconfig"
 )
 
 const (
-	frameMarking        = "urn:ietf:params:rtp-hdrext:framemarking"
 	repairedRTPStreamID = "urn:ietf:params:rtp-hdrext:sdes:repaired-rtp-stream-id"
 )
 
@@ -36,11 +39,21 @@ type WebRTCConfig struct {
 	Receiver      ReceiverConfig
 	Publisher     DirectionConfig
 	Subscriber    DirectionConfig
+	// shared by all rooms and participants of the node for periodic jobs off the media path,
+	// required, set by the owner of the node scheduler
+	JobScheduler *sutils.JobScheduler
+	// clock of timestamps sent to clients, shared with signal pong responses, set by the owner of the node clock
+	ServerClock sutils.ServerClock
 }
 
 type ReceiverConfig struct {
 	PacketBufferSizeVideo int
 	PacketBufferSizeAudio int
+	ClockSkew             buffer.ClockSkewParams
+	// 0 disables clock rate correction of forwarded streams
+	ClockRateCorrectionThreshold float64
+	// shared by all receivers of the node, nil when packets are written on receiver goroutine
+	FanOut *sfu.FanOut
 }
 
 type RTPHeaderExtensionConfig struct {
@@ -94,7 +107,7 @@ func NewWebRTCConfig(conf *config.Config) (*WebRTCConfig, error) {
 				sdp.SDESMidURI,
 				sdp.SDESRTPStreamIDURI,
 				sdp.TransportCCURI,
-				frameMarking,
+				buffer.FrameMarkingURI,
 				dd.ExtensionURI,
 				repairedRTPStreamID,
 				//act.AbsCaptureTimeURI,
@@ -141,11 +154,27 @@ func NewWebRTCConfig(conf *config.Config) (*WebRTCConfig, error) {
 		subscriberConfig.RTCPFeedback.Video = append(subscriberConfig.RTCPFeedback.Video, webrtc.RTCPFeedback{Type: webrtc.TypeRTCPFBGoogREMB})
 	}
 
+	var fanOut *sfu.FanOut
+	if rtcConf.FanOut.Enabled {
+		fanOut = sfu.NewFanOut(sfu.FanOutParams{
+			Workers:   rtcConf.FanOut.Workers,
+			QueueSize: rtcConf.FanOut.QueueSize,
+			Logger:    logger.GetLogger(),
+		})
+	}
+
 	return &WebRTCConfig{
 		WebRTCConfig: *webRTCConfig,
 		Receiver: ReceiverConfig{
 			PacketBufferSizeVideo: rtcConf.PacketBufferSizeVideo,
 			PacketBufferSizeAudio: rtcConf.PacketBufferSizeAudio,
+			ClockSkew: buffer.ClockSkewParams{
+				ResetOnPersistentClockSkew:   rtcConf.ClockSkew.ResetOnPersistentClockSkew,
+				PersistentClockSkewThreshold: rtcConf.ClockSkew.PersistentClockSkewThreshold,
+				Threshold:                    rtcConf.ClockSkew.Threshold,
+			},
+			ClockRateCorrectionThreshold: rtcConf.ClockSkew.CorrectionThreshold,
+			FanOut:                       fanOut,
 		},
 		Publisher:  publisherConfig,
 		Subscriber: subscriberConfig,
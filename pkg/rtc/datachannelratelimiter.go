@@ -0,0 +1,145 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// tokenBucket refills at rate per second up to burst, a zero rate is unlimited
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+}
+
+func newTokenBucket(rate uint64, burst uint64) tokenBucket {
+	if burst == 0 {
+		burst = rate
+	}
+	// starts full, so that a burst right after join is allowed
+	return tokenBucket{
+		rate:   float64(rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+func (b *tokenBucket) refill(elapsed time.Duration) {
+	if b.rate == 0 {
+		return
+	}
+
+	b.tokens += elapsed.Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *tokenBucket) has(n float64) bool {
+	return b.rate == 0 || b.tokens >= n
+}
+
+func (b *tokenBucket) take(n float64) {
+	if b.rate != 0 {
+		b.tokens -= n
+	}
+}
+
+// wait returns time till n tokens are available
+func (b *tokenBucket) wait(n float64) time.Duration {
+	if b.has(n) {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+// ---------------------------------------------
+
+type dataChannelBucket struct {
+	bytes     tokenBucket
+	packets   tokenBucket
+	updatedAt time.Time
+	throttled bool
+}
+
+// dataChannelRateLimiter limits data packets received from a participant,
+// reliable and lossy data channels are limited independently
+type dataChannelRateLimiter struct {
+	lock    sync.Mutex
+	buckets map[livekit.DataPacket_Kind]*dataChannelBucket
+}
+
+// newDataChannelRateLimiter returns nil if limiting is disabled
+func newDataChannelRateLimiter(conf config.DataChannelRateLimitConfig) *dataChannelRateLimiter {
+	if conf.BytesPerSec == 0 && conf.PacketsPerSec == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	l := &dataChannelRateLimiter{
+		buckets: make(map[livekit.DataPacket_Kind]*dataChannelBucket),
+	}
+	for _, kind := range []livekit.DataPacket_Kind{livekit.DataPacket_RELIABLE, livekit.DataPacket_LOSSY} {
+		l.buckets[kind] = &dataChannelBucket{
+			bytes:     newTokenBucket(conf.BytesPerSec, conf.BurstBytes),
+			packets:   newTokenBucket(conf.PacketsPerSec, conf.BurstPackets),
+			updatedAt: now,
+		}
+	}
+	return l
+}
+
+// Allow consumes tokens for a packet of given size, returning false if it exceeds the limit.
+// startedThrottling is true only for the first packet rejected after packets were allowed.
+func (l *dataChannelRateLimiter) Allow(kind livekit.DataPacket_Kind, size int) (allowed bool, startedThrottling bool) {
+	return l.allowAt(kind, size, time.Now())
+}
+
+func (l *dataChannelRateLimiter) allowAt(kind livekit.DataPacket_Kind, size int, now time.Time) (bool, bool) {
+	if l == nil {
+		return true, false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	b := l.buckets[kind]
+	if b == nil {
+		return true, false
+	}
+
+	if elapsed := now.Sub(b.updatedAt); elapsed > 0 {
+		b.bytes.refill(elapsed)
+		b.packets.refill(elapsed)
+		b.updatedAt = now
+	}
+
+	if !b.bytes.has(float64(size)) || !b.packets.has(1) {
+		startedThrottling := !b.throttled
+		b.throttled = true
+		return false, startedThrottling
+	}
+
+	b.bytes.take(float64(size))
+	b.packets.take(1)
+	b.throttled = false
+	return true, false
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestDataChannelRateLimiter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		l := newDataChannelRateLimiter(config.DataChannelRateLimitConfig{})
+		require.Nil(t, l)
+
+		allowed, _ := l.Allow(livekit.DataPacket_RELIABLE, 1_000_000)
+		require.True(t, allowed)
+	})
+
+	t.Run("burst and refill", func(t *testing.T) {
+		l := newDataChannelRateLimiter(config.DataChannelRateLimitConfig{
+			BytesPerSec: 1000,
+			BurstBytes:  3000,
+		})
+		now := time.Now()
+
+		// burst is available right away
+		for i := 0; i < 3; i++ {
+			allowed, _ := l.allowAt(livekit.DataPacket_RELIABLE, 1000, now)
+			require.True(t, allowed)
+		}
+
+		allowed, startedThrottling := l.allowAt(livekit.DataPacket_RELIABLE, 1000, now)
+		require.False(t, allowed)
+		require.True(t, startedThrottling)
+
+		// throttling is reported once
+		allowed, startedThrottling = l.allowAt(livekit.DataPacket_RELIABLE, 1000, now.Add(100*time.Millisecond))
+		require.False(t, allowed)
+		require.False(t, startedThrottling)
+
+		// lossy is limited independently
+		allowed, _ = l.allowAt(livekit.DataPacket_LOSSY, 1000, now)
+		require.True(t, allowed)
+
+		allowed, _ = l.allowAt(livekit.DataPacket_RELIABLE, 1000, now.Add(time.Second))
+		require.True(t, allowed)
+
+		// refill does not exceed burst
+		for i := 0; i < 3; i++ {
+			allowed, _ = l.allowAt(livekit.DataPacket_RELIABLE, 1000, now.Add(time.Minute))
+			require.True(t, allowed)
+		}
+		allowed, startedThrottling = l.allowAt(livekit.DataPacket_RELIABLE, 1000, now.Add(time.Minute))
+		require.False(t, allowed)
+		require.True(t, startedThrottling)
+	})
+
+	t.Run("packets", func(t *testing.T) {
+		l := newDataChannelRateLimiter(config.DataChannelRateLimitConfig{
+			PacketsPerSec: 2,
+		})
+		now := time.Now()
+
+		// burst defaults to one second at the rate
+		for i := 0; i < 2; i++ {
+			allowed, _ := l.allowAt(livekit.DataPacket_LOSSY, 10_000, now)
+			require.True(t, allowed)
+		}
+		allowed, _ := l.allowAt(livekit.DataPacket_LOSSY, 1, now)
+		require.False(t, allowed)
+	})
+}
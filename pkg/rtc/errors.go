@@ -14,21 +14,35 @@
 
 package rtc
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/webrtc/v3"
+)
 
 var (
-	ErrRoomClosed              = errors.New("room has already closed")
-	ErrPermissionDenied        = errors.New("no permissions to access the room")
-	ErrMaxParticipantsExceeded = errors.New("room has exceeded its max participants")
-	ErrLimitExceeded           = errors.New("node has exceeded its configured limit")
-	ErrAlreadyJoined           = errors.New("a participant with the same identity is already in the room")
-	ErrDataChannelUnavailable  = errors.New("data channel is not available")
-	ErrDataChannelBufferFull   = errors.New("data channel buffer is full")
-	ErrTransportFailure        = errors.New("transport failure")
-	ErrEmptyIdentity           = errors.New("participant identity cannot be empty")
-	ErrEmptyParticipantID      = errors.New("participant ID cannot be empty")
-	ErrMissingGrants           = errors.New("VideoGrant is missing")
-	ErrInternalError           = errors.New("internal error")
+	ErrRoomClosed               = errors.New("room has already closed")
+	ErrPermissionDenied         = errors.New("no permissions to access the room")
+	ErrMaxParticipantsExceeded  = errors.New("room has exceeded its max participants")
+	ErrLimitExceeded            = errors.New("node has exceeded its configured limit")
+	ErrAlreadyJoined            = errors.New("a participant with the same identity is already in the room")
+	ErrDataChannelUnavailable   = errors.New("data channel is not available")
+	ErrDataChannelBufferFull    = errors.New("data channel buffer is full")
+	ErrDataChannelSendFailed    = errors.New("data channel send failed")
+	ErrReliableDataRateExceeded = errors.New("reliable data rate exceeded")
+	ErrTransportFailure         = errors.New("transport failure")
+	ErrEmptyIdentity            = errors.New("participant identity cannot be empty")
+	ErrEmptyParticipantID       = errors.New("participant ID cannot be empty")
+	ErrMissingGrants            = errors.New("VideoGrant is missing")
+	ErrMissingJobScheduler      = errors.New("job scheduler is missing")
+	ErrInternalError            = errors.New("internal error")
+	ErrSignalQueueFull          = errors.New("signal request queue is full")
+	ErrSignalQueueClosed        = errors.New("signal request queue is closed")
+	ErrParticipantNotReady      = errors.New("participant is not ready")
+	ErrPublishRateExceeded      = errors.New("track publish rate exceeded")
+	ErrTooManyPendingTracks     = errors.New("too many pending tracks")
+	ErrNoMigrationInProgress    = errors.New("no migration in progress")
 
 	// Track subscription related
 	ErrNoTrackPermission         = errors.New("participant is not allowed to subscribe to this track")
@@ -36,5 +50,11 @@ var (
 	ErrTrackNotFound             = errors.New("track cannot be found")
 	ErrTrackNotAttached          = errors.New("track is not yet attached")
 	ErrTrackNotBound             = errors.New("track not bound")
+	ErrTrackNotSubscribed        = errors.New("track is not subscribed")
+	ErrTrackNotVideo             = errors.New("track is not a video track")
+	ErrTemporalLayersUnsupported = errors.New("temporal layers not supported by codec")
 	ErrSubscriptionLimitExceeded = errors.New("participant has exceeded its subscription limit")
+	ErrSubscriberLimitExceeded   = errors.New("track has reached its subscriber limit")
+	ErrScreenShareLimitExceeded  = errors.New("participant has exceeded its screen share subscription limit")
+	ErrSubscriberNotDecoding     = fmt.Errorf("%w: subscriber is not decoding forwarded media", webrtc.ErrUnsupportedCodec)
 )
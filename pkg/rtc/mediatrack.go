@@ -16,7 +16,6 @@ package rtc
 
 import (
 	"context"
-	"math"
 	"strings"
 	"sync"
 	"time"
@@ -34,7 +33,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/livekit-server/pkg/telemetry"
-	util "github.com/livekit/mediatransportutil"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 // MediaTrack represents a WebRTC track that needs to be forwarded
@@ -51,7 +50,11 @@ type MediaTrack struct {
 
 	lock sync.RWMutex
 
+	onAvailableLayersChanged func(trackID livekit.TrackID, availableLayers []int32)
+
 	rttFromXR atomic.Bool
+
+	drainTimers []*time.Timer
 }
 
 type MediaTrackParams struct {
@@ -70,6 +73,14 @@ type MediaTrackParams struct {
 	Logger              logger.Logger
 	SimTracks           map[uint32]SimulcastTrackInfo
 	OnRTCP              func([]rtcp.Packet)
+	// round trip time measured with RTCP XR on publisher path
+	OnRTT              func(rtt uint32)
+	IsTransportHealthy func() bool
+	MaxSubscribers     int
+	// when publisher stops sending, subscribers are kept for this long before they are removed, 0 removes right away
+	DrainDuration time.Duration
+	// clock of sender reports sent to subscribers
+	ServerClock sutils.ServerClock
 }
 
 func NewMediaTrack(params MediaTrackParams, ti *livekit.TrackInfo) *MediaTrack {
@@ -86,8 +97,12 @@ func NewMediaTrack(params MediaTrackParams, ti *livekit.TrackInfo) *MediaTrack {
 		ReceiverConfig:      params.ReceiverConfig,
 		SubscriberConfig:    params.SubscriberConfig,
 		AudioConfig:         params.AudioConfig,
+		VideoConfig:         params.VideoConfig,
 		Telemetry:           params.Telemetry,
 		Logger:              params.Logger,
+		MaxSubscribers:      params.MaxSubscribers,
+		ServerClock:         params.ServerClock,
+		PLIThrottleConfig:   params.PLIThrottleConfig,
 	}, ti)
 
 	if ti.Type == livekit.TrackType_AUDIO {
@@ -194,7 +209,6 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 		return newCodec
 	}
 
-	var lastRR uint32
 	rtcpReader.OnPacket(func(bytes []byte) {
 		pkts, err := rtcp.Unmarshal(bytes)
 		if err != nil {
@@ -212,18 +226,20 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 			case *rtcp.ExtendedReport:
 			rttFromXR:
 				for _, report := range pkt.Reports {
-					if rr, ok := report.(*rtcp.DLRRReportBlock); ok {
-						for _, dlrrReport := range rr.Reports {
-							if dlrrReport.LastRR <= lastRR {
+					if dlrr, ok := report.(*rtcp.DLRRReportBlock); ok {
+						for _, dlrrReport := range dlrr.Reports {
+							// response to receiver reference time report sent by buffer of this track
+							if dlrrReport.SSRC != uint32(track.SSRC()) {
+								continue
+							}
+							rtt, err := buff.HandleDLRR(dlrrReport)
+							if err != nil {
 								continue
 							}
-							nowNTP := util.ToNtpTime(time.Now())
-							nowNTP32 := uint32(nowNTP >> 16)
-							ntpDiff := nowNTP32 - dlrrReport.LastRR - dlrrReport.DLRR
-							rtt := uint32(math.Ceil(float64(ntpDiff) * 1000.0 / 65536.0))
-							buff.SetRTT(rtt)
 							t.rttFromXR.Store(true)
-							lastRR = dlrrReport.LastRR
+							if t.params.OnRTT != nil {
+								t.params.OnRTT(rtt)
+							}
 							break rttFromXR
 						}
 					}
@@ -280,17 +296,20 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 			sfu.WithPliThrottleConfig(t.params.PLIThrottleConfig),
 			sfu.WithAudioConfig(t.params.AudioConfig),
 			sfu.WithLoadBalanceThreshold(20),
+			sfu.WithFanOut(t.params.ReceiverConfig.FanOut),
 			sfu.WithStreamTrackers(),
+			sfu.WithSilenceDetection(t.params.VideoConfig.SilenceMuteTimeout, t.params.IsTransportHealthy),
+			sfu.WithClockRateEstimation(t.params.ReceiverConfig.ClockRateCorrectionThreshold > 0),
 		)
 		newWR.OnCloseHandler(func() {
 			t.MediaTrackReceiver.SetClosing()
-			t.MediaTrackReceiver.ClearReceiver(mime, false)
-			if t.MediaTrackReceiver.TryClose() {
-				if t.dynacastManager != nil {
-					t.dynacastManager.Close()
-				}
-			}
+			t.drainReceiver(mime)
 		})
+		if pliCoalescer := t.MediaTrackReceiver.pliCoalescer; pliCoalescer != nil {
+			newWR.OnKeyFrame(func(layer int32) {
+				pliCoalescer.KeyFrameForwarded(newWR, layer)
+			})
+		}
 		// SIMULCAST-CODEC-TODO: these need to be receiver/mime aware, setting it up only for primary now
 		if priority == 0 {
 			newWR.OnStatsUpdate(func(_ *sfu.WebRTCReceiver, stat *livekit.AnalyticsStat) {
@@ -299,6 +318,7 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 			})
 
 			newWR.OnMaxLayerChange(t.onMaxLayerChange)
+			newWR.OnAvailableLayersChange(t.onAvailableLayersChange)
 		}
 		if t.PrimaryReceiver() == nil {
 			// primary codec published, set potential codecs
@@ -333,7 +353,8 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 	}
 	t.lock.Unlock()
 
-	if err := wr.(*sfu.WebRTCReceiver).AddUpTrack(track, buff); err != nil {
+	rebound, err := wr.(*sfu.WebRTCReceiver).AddUpTrack(track, buff)
+	if err != nil {
 		t.params.Logger.Warnw(
 			"adding up track failed", err,
 			"rid", track.RID(),
@@ -345,14 +366,19 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 		return false
 	}
 
-	// LK-TODO: can remove this completely when VideoLayers protocol becomes the default as it has info from client or if we decide to use TrackInfo.Simulcast
-	if t.numUpTracks.Inc() > 1 || track.RID() != "" {
-		// cannot only rely on numUpTracks since we fire metadata events immediately after the first layer
-		t.SetSimulcast(true)
-	}
+	if rebound {
+		// publisher re-created the layer with a new SSRC, for example after an ICE restart
+		t.MediaTrackReceiver.RebindLayerSsrc(mime, track.RID(), uint32(track.SSRC()))
+	} else {
+		// LK-TODO: can remove this completely when VideoLayers protocol becomes the default as it has info from client or if we decide to use TrackInfo.Simulcast
+		if t.numUpTracks.Inc() > 1 || track.RID() != "" {
+			// cannot only rely on numUpTracks since we fire metadata events immediately after the first layer
+			t.SetSimulcast(true)
+		}
 
-	if t.IsSimulcast() {
-		t.MediaTrackReceiver.SetLayerSsrc(mime, track.RID(), uint32(track.SSRC()))
+		if t.IsSimulcast() {
+			t.MediaTrackReceiver.SetLayerSsrc(mime, track.RID(), uint32(track.SSRC()))
+		}
 	}
 
 	buff.Bind(receiver.GetParameters(), track.Codec().RTPCodecCapability)
@@ -390,6 +416,14 @@ func (t *MediaTrack) SetRTT(rtt uint32) {
 	}
 }
 
+func (t *MediaTrack) SetPLIThrottleConfig(pliThrottleConfig config.PLIThrottleConfig) {
+	t.lock.Lock()
+	t.params.PLIThrottleConfig = pliThrottleConfig
+	t.lock.Unlock()
+
+	t.MediaTrackReceiver.SetPLIThrottleConfig(pliThrottleConfig)
+}
+
 func (t *MediaTrack) HasPendingCodec() bool {
 	return t.MediaTrackReceiver.PrimaryReceiver() == nil
 }
@@ -398,6 +432,24 @@ func (t *MediaTrack) onMaxLayerChange(maxLayer int32) {
 	t.MediaTrackReceiver.NotifyMaxLayerChange(maxLayer)
 }
 
+// OnAvailableLayersChanged sets a callback invoked with spatial layers of primary codec
+// currently live, when the stream tracker declares a layer started or stopped
+func (t *MediaTrack) OnAvailableLayersChanged(f func(trackID livekit.TrackID, availableLayers []int32)) {
+	t.lock.Lock()
+	t.onAvailableLayersChanged = f
+	t.lock.Unlock()
+}
+
+func (t *MediaTrack) onAvailableLayersChange(availableLayers []int32) {
+	t.lock.RLock()
+	onAvailableLayersChanged := t.onAvailableLayersChanged
+	t.lock.RUnlock()
+
+	if onAvailableLayersChanged != nil {
+		onAvailableLayersChanged(t.ID(), availableLayers)
+	}
+}
+
 func (t *MediaTrack) Restart() {
 	t.MediaTrackReceiver.Restart()
 
@@ -406,7 +458,45 @@ func (t *MediaTrack) Restart() {
 	}
 }
 
+// drainReceiver removes subscribers of a closed receiver once the drain duration elapses
+func (t *MediaTrack) drainReceiver(mime string) {
+	clearReceiver := func() {
+		t.MediaTrackReceiver.ClearReceiver(mime, false)
+		if t.MediaTrackReceiver.TryClose() {
+			if t.dynacastManager != nil {
+				t.dynacastManager.Close()
+			}
+		}
+	}
+
+	drain := t.params.DrainDuration
+	if drain <= 0 {
+		clearReceiver()
+		return
+	}
+
+	t.params.Logger.Infow("draining track", "mime", mime, "drain", drain)
+	t.lock.Lock()
+	t.drainTimers = append(t.drainTimers, time.AfterFunc(drain, clearReceiver))
+	t.lock.Unlock()
+}
+
+// IsDraining returns true if the publisher stopped sending and subscribers are being drained
+func (t *MediaTrack) IsDraining() bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return len(t.drainTimers) != 0
+}
+
 func (t *MediaTrack) Close(willBeResumed bool) {
+	t.lock.Lock()
+	for _, timer := range t.drainTimers {
+		timer.Stop()
+	}
+	t.drainTimers = nil
+	t.lock.Unlock()
+
 	t.MediaTrackReceiver.SetClosing()
 	if t.dynacastManager != nil {
 		t.dynacastManager.Close()
@@ -425,3 +515,11 @@ func (t *MediaTrack) SetMuted(muted bool) {
 
 	t.MediaTrackReceiver.SetMuted(muted)
 }
+
+func (t *MediaTrack) SetPaused(paused bool) {
+	if !paused && t.dynacastManager != nil {
+		t.dynacastManager.ForceUpdate()
+	}
+
+	t.MediaTrackReceiver.SetPaused(paused)
+}
@@ -16,10 +16,15 @@ package rtc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 
 	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
 )
 
 func TestTrackInfo(t *testing.T) {
@@ -172,3 +177,97 @@ func TestGetQualityForDimension(t *testing.T) {
 	})
 
 }
+
+func TestSubscriberLimit(t *testing.T) {
+	mt := &typesfakes.FakeMediaTrack{}
+	mt.IDReturns("track")
+	ts := NewMediaTrackSubscriptions(MediaTrackSubscriptionsParams{
+		MediaTrack:     mt,
+		Logger:         logger.GetLogger(),
+		MaxSubscribers: 2,
+	})
+
+	sub := &typesfakes.FakeLocalParticipant{}
+	sub.IDReturns("sub")
+
+	// existing subscriber and one being added take up the limit
+	ts.subscribedTracks["existing"] = &typesfakes.FakeSubscribedTrack{}
+	ts.numAddingSubscribers = 1
+	_, err := ts.AddSubscriber(sub, nil)
+	require.ErrorIs(t, err, ErrSubscriberLimitExceeded)
+
+	// already subscribed takes precedence over the limit
+	ts.subscribedTracks["sub"] = &typesfakes.FakeSubscribedTrack{}
+	_, err = ts.AddSubscriber(sub, nil)
+	require.ErrorIs(t, err, errAlreadySubscribed)
+	require.Equal(t, 1, ts.numAddingSubscribers)
+
+	// subscribers are reported as they change and taken out when the track closes
+	ts.updateSubscriberStatsLocked()
+	require.Equal(t, 2, ts.numReportedSubscribers)
+	delete(ts.subscribedTracks, "sub")
+	ts.updateSubscriberStatsLocked()
+	require.Equal(t, 1, ts.numReportedSubscribers)
+	ts.closeSubscriberStats()
+	require.Zero(t, ts.numReportedSubscribers)
+	ts.updateSubscriberStatsLocked()
+	require.Zero(t, ts.numReportedSubscribers)
+}
+
+func TestPausedForwarding(t *testing.T) {
+	mt := &typesfakes.FakeMediaTrack{}
+	mt.IDReturns("track")
+	ts := NewMediaTrackSubscriptions(MediaTrackSubscriptionsParams{
+		MediaTrack: mt,
+		Logger:     logger.GetLogger(),
+	})
+	st := &typesfakes.FakeSubscribedTrack{}
+	ts.subscribedTracks["sub"] = st
+
+	// publisher pause holds forwarding like moderation
+	ts.SetPaused(true)
+	require.Equal(t, 1, st.SetModeratedCallCount())
+	require.True(t, st.SetModeratedArgsForCall(0))
+	require.True(t, ts.isForwardingHeld())
+
+	// still held by publisher when moderation ends
+	ts.SetModerated(true)
+	ts.SetModerated(false)
+	require.True(t, st.SetModeratedArgsForCall(st.SetModeratedCallCount()-1))
+	require.False(t, ts.IsModerated())
+
+	ts.SetPaused(false)
+	require.False(t, st.SetModeratedArgsForCall(st.SetModeratedCallCount()-1))
+	require.False(t, ts.isForwardingHeld())
+}
+
+func TestDrainReceiver(t *testing.T) {
+	newTrack := func() (*MediaTrack, *atomic.Bool) {
+		mt := NewMediaTrack(MediaTrackParams{
+			Logger:        logger.GetLogger(),
+			DrainDuration: 50 * time.Millisecond,
+		}, &livekit.TrackInfo{Sid: "track", Type: livekit.TrackType_VIDEO})
+		closed := atomic.NewBool(false)
+		mt.AddOnClose(func() {
+			closed.Store(true)
+		})
+		return mt, closed
+	}
+
+	t.Run("closed after drain", func(t *testing.T) {
+		mt, closed := newTrack()
+		mt.drainReceiver("video/vp8")
+		require.True(t, mt.IsDraining())
+		require.False(t, closed.Load())
+
+		require.Eventually(t, closed.Load, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("cancelled on close", func(t *testing.T) {
+		mt, closed := newTrack()
+		mt.drainReceiver("video/vp8")
+		mt.Close(false)
+		require.True(t, closed.Load())
+		require.False(t, mt.IsDraining())
+	})
+}
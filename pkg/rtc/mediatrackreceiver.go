@@ -21,6 +21,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pion/rtcp"
 	"github.com/pion/webrtc/v3"
@@ -36,6 +37,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 const (
@@ -90,8 +92,12 @@ type MediaTrackReceiverParams struct {
 	ReceiverConfig      ReceiverConfig
 	SubscriberConfig    DirectionConfig
 	AudioConfig         config.AudioConfig
+	VideoConfig         config.VideoConfig
 	Telemetry           telemetry.TelemetryService
 	Logger              logger.Logger
+	MaxSubscribers      int
+	PLIThrottleConfig   config.PLIThrottleConfig
+	ServerClock         sutils.ServerClock
 }
 
 type MediaTrackReceiver struct {
@@ -103,12 +109,15 @@ type MediaTrackReceiver struct {
 	potentialCodecs []webrtc.RTPCodecParameters
 	state           mediaTrackReceiverState
 	willBeResumed   bool
+	paused          bool
 
 	onSetupReceiver     func(mime string)
 	onMediaLossFeedback func(dt *sfu.DownTrack, report *rtcp.ReceiverReport)
 	onClose             []func()
 
 	*MediaTrackSubscriptions
+
+	pliCoalescer *subscriberPLICoalescer
 }
 
 func NewMediaTrackReceiver(params MediaTrackReceiverParams, ti *livekit.TrackInfo) *MediaTrackReceiver {
@@ -117,14 +126,22 @@ func NewMediaTrackReceiver(params MediaTrackReceiverParams, ti *livekit.TrackInf
 		trackInfo: proto.Clone(ti).(*livekit.TrackInfo),
 		state:     mediaTrackReceiverStateOpen,
 	}
+	if t.trackInfo.Type == livekit.TrackType_VIDEO {
+		t.pliCoalescer = newSubscriberPLICoalescer(params.PLIThrottleConfig, params.Logger)
+	}
 
 	t.MediaTrackSubscriptions = NewMediaTrackSubscriptions(MediaTrackSubscriptionsParams{
 		MediaTrack:       params.MediaTrack,
 		IsRelayed:        params.IsRelayed,
 		ReceiverConfig:   params.ReceiverConfig,
 		SubscriberConfig: params.SubscriberConfig,
+		AudioConfig:      params.AudioConfig,
+		VideoConfig:      params.VideoConfig,
 		Telemetry:        params.Telemetry,
 		Logger:           params.Logger,
+		MaxSubscribers:   params.MaxSubscribers,
+		PLICoalescer:     t.pliCoalescer,
+		ServerClock:      params.ServerClock,
 	})
 	t.MediaTrackSubscriptions.OnDownTrackCreated(t.onDownTrackCreated)
 
@@ -352,6 +369,11 @@ func (t *MediaTrackReceiver) Close() {
 	onclose := t.onClose
 	t.lock.Unlock()
 
+	t.MediaTrackSubscriptions.closeSubscriberStats()
+	if t.pliCoalescer != nil {
+		t.pliCoalescer.Close()
+	}
+
 	for _, f := range onclose {
 		f()
 	}
@@ -428,15 +450,46 @@ func (t *MediaTrackReceiver) IsMuted() bool {
 func (t *MediaTrackReceiver) SetMuted(muted bool) {
 	t.lock.Lock()
 	t.trackInfo.Muted = muted
+	upTrackPaused := muted || t.paused
 	t.lock.Unlock()
 
 	for _, receiver := range t.loadReceivers() {
-		receiver.SetUpTrackPaused(muted)
+		receiver.SetUpTrackPaused(upTrackPaused)
 	}
 
 	t.MediaTrackSubscriptions.SetMuted(muted)
 }
 
+func (t *MediaTrackReceiver) IsPaused() bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.paused
+}
+
+// SetPaused stops forwarding to subscribers without changing muted state of the track.
+// Subscribers see the stream as paused, like a moderated track.
+// On resume, a key frame is requested from the publisher so that subscribers can start decoding.
+func (t *MediaTrackReceiver) SetPaused(paused bool) {
+	t.lock.Lock()
+	t.paused = paused
+	muted := t.trackInfo.Muted
+	t.lock.Unlock()
+
+	receivers := t.loadReceivers()
+	for _, receiver := range receivers {
+		receiver.SetUpTrackPaused(paused || muted)
+	}
+
+	t.MediaTrackSubscriptions.SetPaused(paused)
+
+	if !paused && !muted {
+		for _, receiver := range receivers {
+			receiver.SendPLI(0, true)
+		}
+	}
+}
+
 func (t *MediaTrackReceiver) AddOnClose(f func()) {
 	if f == nil {
 		return
@@ -565,6 +618,15 @@ func (t *MediaTrackReceiver) updateTrackInfoOfReceivers() {
 }
 
 func (t *MediaTrackReceiver) SetLayerSsrc(mime string, rid string, ssrc uint32) {
+	t.setLayerSsrc(mime, rid, ssrc, false)
+}
+
+// RebindLayerSsrc replaces SSRC of a layer, unlike SetLayerSsrc it overrides an already set SSRC
+func (t *MediaTrackReceiver) RebindLayerSsrc(mime string, rid string, ssrc uint32) {
+	t.setLayerSsrc(mime, rid, ssrc, true)
+}
+
+func (t *MediaTrackReceiver) setLayerSsrc(mime string, rid string, ssrc uint32, override bool) {
 	t.lock.Lock()
 	layer := buffer.RidToSpatialLayer(rid, t.trackInfo)
 	if layer == buffer.InvalidLayerSpatial {
@@ -590,7 +652,7 @@ func (t *MediaTrackReceiver) SetLayerSsrc(mime string, rid string, ssrc uint32)
 				break
 			}
 		}
-		if !ssrcFound && matchingLayer != nil {
+		if (!ssrcFound || override) && matchingLayer != nil {
 			matchingLayer.Ssrc = ssrc
 		}
 
@@ -813,6 +875,7 @@ func (t *MediaTrackReceiver) DebugInfo() map[string]interface{} {
 		"ID":       t.ID(),
 		"Kind":     t.Kind().String(),
 		"PubMuted": t.IsMuted(),
+		"Paused":   t.IsPaused(),
 	}
 
 	info["DownTracks"] = t.MediaTrackSubscriptions.DebugInfo()
@@ -876,6 +939,50 @@ func (t *MediaTrackReceiver) SetRTT(rtt uint32) {
 	}
 }
 
+// LastPacketAt returns time of the last packet received from publisher on any codec, zero if none
+func (t *MediaTrackReceiver) LastPacketAt() time.Time {
+	var last time.Time
+	for _, r := range t.loadReceivers() {
+		if wr, ok := r.TrackReceiver.(*sfu.WebRTCReceiver); ok {
+			if at := wr.LastPacketAt(); at.After(last) {
+				last = at
+			}
+		}
+	}
+	return last
+}
+
+// GetBitrate returns bitrate received on all codecs over the last connection quality interval
+func (t *MediaTrackReceiver) GetBitrate() int64 {
+	bitrate := int64(0)
+	for _, r := range t.loadReceivers() {
+		if wr, ok := r.TrackReceiver.(*sfu.WebRTCReceiver); ok {
+			bitrate += wr.GetBitrate()
+		}
+	}
+	return bitrate
+}
+
+func (t *MediaTrackReceiver) SetPLIThrottleConfig(pliThrottleConfig config.PLIThrottleConfig) {
+	if t.pliCoalescer != nil {
+		t.pliCoalescer.SetConfig(pliThrottleConfig)
+	}
+	for _, r := range t.loadReceivers() {
+		if wr, ok := r.TrackReceiver.(*sfu.WebRTCReceiver); ok {
+			wr.SetPLIThrottleConfig(pliThrottleConfig)
+		}
+	}
+}
+
+// GetTemporalLayerDistribution returns temporal layer distribution per spatial layer of the primary codec,
+// nil if primary codec has not been received yet
+func (t *MediaTrackReceiver) GetTemporalLayerDistribution() []buffer.TemporalLayerDistribution {
+	if wr, ok := t.PrimaryReceiver().(*sfu.WebRTCReceiver); ok {
+		return wr.GetTemporalLayerDistribution()
+	}
+	return nil
+}
+
 func (t *MediaTrackReceiver) GetTemporalLayerForSpatialFps(spatial int32, fps uint32, mime string) int32 {
 	receiver := t.Receiver(mime)
 	if receiver == nil {
@@ -892,6 +999,29 @@ func (t *MediaTrackReceiver) GetTemporalLayerForSpatialFps(spatial int32, fps ui
 	return buffer.DefaultMaxLayerTemporal
 }
 
+// SupportsTemporalLayers returns false when temporal layer of codec cannot be selected,
+// H.264 temporal layers can be selected only when the publisher marks frames or the stream has no B-frames
+func (t *MediaTrackReceiver) SupportsTemporalLayers(mime string) bool {
+	if sfu.CodecSupportsTemporalLayers(mime) {
+		return true
+	}
+
+	receiver := t.Receiver(mime)
+	if receiver == nil {
+		return false
+	}
+	ddAvailable := false
+	for _, ext := range receiver.HeaderExtensions() {
+		switch ext.URI {
+		case buffer.FrameMarkingURI:
+			return true
+		case dependencydescriptor.ExtensionURI:
+			ddAvailable = true
+		}
+	}
+	return !ddAvailable && buffer.IsH264WithoutBFrames(receiver.Codec().SDPFmtpLine)
+}
+
 func (t *MediaTrackReceiver) IsEncrypted() bool {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
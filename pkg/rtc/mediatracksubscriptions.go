@@ -27,9 +27,12 @@ import (
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
 )
 
 var (
@@ -41,8 +44,18 @@ var (
 type MediaTrackSubscriptions struct {
 	params MediaTrackSubscriptionsParams
 
-	subscribedTracksMu sync.RWMutex
-	subscribedTracks   map[livekit.ParticipantID]types.SubscribedTrack
+	subscribedTracksMu     sync.RWMutex
+	subscribedTracks       map[livekit.ParticipantID]types.SubscribedTrack
+	numAddingSubscribers   int
+	numReportedSubscribers int
+	subscriberStatsClosed  bool
+
+	// forwarding is held for subscribers when moderated or paused by the publisher
+	forwardingLock sync.Mutex
+	moderated      bool
+	paused         bool
+	// cap on spatial layer forwarded to subscribers
+	maxSpatialLayer atomic.Int32
 
 	onDownTrackCreated           func(downTrack *sfu.DownTrack)
 	onSubscriberMaxQualityChange func(subscriberID livekit.ParticipantID, codec webrtc.RTPCodecCapability, layer int32)
@@ -54,17 +67,30 @@ type MediaTrackSubscriptionsParams struct {
 
 	ReceiverConfig   ReceiverConfig
 	SubscriberConfig DirectionConfig
+	AudioConfig      config.AudioConfig
+	VideoConfig      config.VideoConfig
 
 	Telemetry telemetry.TelemetryService
 
 	Logger logger.Logger
+
+	// maximum number of subscribers, 0 for no limit
+	MaxSubscribers int
+
+	// aggregates PLIs from subscribers, PLIs are sent to receiver directly when nil
+	PLICoalescer *subscriberPLICoalescer
+
+	// clock of sender reports sent to subscribers
+	ServerClock sutils.ServerClock
 }
 
 func NewMediaTrackSubscriptions(params MediaTrackSubscriptionsParams) *MediaTrackSubscriptions {
-	return &MediaTrackSubscriptions{
+	t := &MediaTrackSubscriptions{
 		params:           params,
 		subscribedTracks: make(map[livekit.ParticipantID]types.SubscribedTrack),
 	}
+	t.maxSpatialLayer.Store(buffer.InvalidLayerSpatial)
+	return t
 }
 
 func (t *MediaTrackSubscriptions) OnDownTrackCreated(f func(downTrack *sfu.DownTrack)) {
@@ -82,6 +108,73 @@ func (t *MediaTrackSubscriptions) SetMuted(muted bool) {
 	}
 }
 
+// SetModerated pauses or resumes forwarding to all subscribers, including those subscribing later
+func (t *MediaTrackSubscriptions) SetModerated(moderated bool) {
+	t.forwardingLock.Lock()
+	defer t.forwardingLock.Unlock()
+
+	if t.moderated == moderated {
+		return
+	}
+	t.moderated = moderated
+	t.updateForwardingHeldLocked()
+}
+
+func (t *MediaTrackSubscriptions) IsModerated() bool {
+	t.forwardingLock.Lock()
+	defer t.forwardingLock.Unlock()
+
+	return t.moderated
+}
+
+// SetPaused pauses or resumes forwarding to all subscribers on behalf of the publisher,
+// subscribers see the stream as paused like for a moderated track
+func (t *MediaTrackSubscriptions) SetPaused(paused bool) {
+	t.forwardingLock.Lock()
+	defer t.forwardingLock.Unlock()
+
+	if t.paused == paused {
+		return
+	}
+	t.paused = paused
+	t.updateForwardingHeldLocked()
+}
+
+func (t *MediaTrackSubscriptions) isForwardingHeld() bool {
+	t.forwardingLock.Lock()
+	defer t.forwardingLock.Unlock()
+
+	return t.moderated || t.paused
+}
+
+func (t *MediaTrackSubscriptions) updateForwardingHeldLocked() {
+	held := t.moderated || t.paused
+	for _, st := range t.getAllSubscribedTracks() {
+		st.SetModerated(held)
+	}
+}
+
+// SetMaxSubscriberSpatialLayer caps spatial layer forwarded to all subscribers, including those subscribing later,
+// regardless of subscriber settings. buffer.InvalidLayerSpatial removes the cap.
+// Returns the highest layer of the track which can be forwarded.
+func (t *MediaTrackSubscriptions) SetMaxSubscriberSpatialLayer(layer int32) int32 {
+	if t.maxSpatialLayer.Swap(layer) != layer {
+		for _, st := range t.getAllSubscribedTracks() {
+			st.SetMaxSpatialLayerCap(layer)
+		}
+	}
+
+	maxLayer := buffer.VideoQualityToSpatialLayer(livekit.VideoQuality_HIGH, t.params.MediaTrack.ToProto())
+	if layer != buffer.InvalidLayerSpatial && layer < maxLayer {
+		maxLayer = layer
+	}
+	return maxLayer
+}
+
+func (t *MediaTrackSubscriptions) GetMaxSubscriberSpatialLayer() int32 {
+	return t.maxSpatialLayer.Load()
+}
+
 func (t *MediaTrackSubscriptions) IsSubscriber(subID livekit.ParticipantID) bool {
 	t.subscribedTracksMu.RLock()
 	defer t.subscribedTracksMu.RUnlock()
@@ -101,8 +194,20 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 		t.subscribedTracksMu.Unlock()
 		return nil, errAlreadySubscribed
 	}
+	// subscribers being added count towards the limit till they are added or fail
+	if t.params.MaxSubscribers > 0 && len(t.subscribedTracks)+t.numAddingSubscribers >= t.params.MaxSubscribers {
+		t.subscribedTracksMu.Unlock()
+		return nil, ErrSubscriberLimitExceeded
+	}
+	t.numAddingSubscribers++
 	t.subscribedTracksMu.Unlock()
 
+	defer func() {
+		t.subscribedTracksMu.Lock()
+		t.numAddingSubscribers--
+		t.subscribedTracksMu.Unlock()
+	}()
+
 	var rtcpFeedback []webrtc.RTCPFeedback
 	var maxTrack int
 	switch t.params.MediaTrack.Kind() {
@@ -128,19 +233,42 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 		trailer = sub.GetTrailer()
 	}
 
+	var adaptiveRED config.AdaptiveREDConfig
+	if wr.IsREDEncoded() {
+		adaptiveRED = t.params.AudioConfig.AdaptiveRED
+	}
+
+	var subscriberPLIHandler func(layer int32)
+	if t.params.PLICoalescer != nil {
+		subscriberPLIHandler = func(layer int32) {
+			t.params.PLICoalescer.Request(wr, layer)
+		}
+	}
+
 	downTrack, err := sfu.NewDownTrack(sfu.DowntrackParams{
-		Codecs:            codecs,
-		Source:            t.params.MediaTrack.Source(),
-		Receiver:          wr,
-		BufferFactory:     sub.GetBufferFactory(),
-		SubID:             subscriberID,
-		StreamID:          streamID,
-		MaxTrack:          maxTrack,
-		PlayoutDelayLimit: sub.GetPlayoutDelayConfig(),
-		Pacer:             sub.GetPacer(),
-		Trailer:           trailer,
-		Logger:            LoggerWithTrack(sub.GetLogger().WithComponent(sutils.ComponentSub), trackID, t.params.IsRelayed),
-		RTCPWriter:        sub.WriteSubscriberRTCP,
+		Codecs:                       codecs,
+		Source:                       t.params.MediaTrack.Source(),
+		Receiver:                     wr,
+		BufferFactory:                sub.GetBufferFactory(),
+		SubID:                        subscriberID,
+		StreamID:                     streamID,
+		MaxTrack:                     maxTrack,
+		PlayoutDelayLimit:            sub.GetPlayoutDelayConfig(t.params.MediaTrack.Source()),
+		Pacer:                        sub.GetPacer(),
+		Trailer:                      trailer,
+		Logger:                       LoggerWithTrack(sub.GetLogger().WithComponent(sutils.ComponentSub), trackID, t.params.IsRelayed),
+		RTCPWriter:                   sub.WriteSubscriberRTCP,
+		MaxAudioGapFill:              int(t.params.AudioConfig.GapFillMaxPackets),
+		ClockRateCorrectionThreshold: t.params.ReceiverConfig.ClockRateCorrectionThreshold,
+		LayerSelectionLogSampling:    int(t.params.VideoConfig.LayerSelectionLogSampling),
+		FastResumeWindow:             t.params.VideoConfig.FastResumeWindow,
+		RefTSDiscontinuityThreshold:  t.params.VideoConfig.RefTSDiscontinuityThreshold,
+		LayerUpHysteresis:            t.params.VideoConfig.LayerUpHysteresis,
+		SubscriberPLIHandler:         subscriberPLIHandler,
+		AllocationPreference:         sub.GetAllocationPreference(),
+		KeyFrameRequestThrottle:      sub.GetSubscriberPLIThrottle(),
+		AdaptiveRED:                  adaptiveRED,
+		ServerClock:                  t.params.ServerClock,
 	})
 	if err != nil {
 		return nil, err
@@ -162,6 +290,7 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 
 	// Bind callback can happen from replaceTrack, so set it up early
 	var reusingTransceiver atomic.Bool
+	var resumingState atomic.Bool
 	var dtState sfu.DownTrackState
 	downTrack.OnBinding(func(err error) {
 		if err != nil {
@@ -169,7 +298,7 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 			return
 		}
 		wr.DetermineReceiver(downTrack.Codec())
-		if reusingTransceiver.Load() {
+		if reusingTransceiver.Load() || resumingState.Load() {
 			downTrack.SeedState(dtState)
 		}
 		if err = wr.AddDownTrack(downTrack); err != nil && err != sfu.ErrReceiverClosed {
@@ -180,10 +309,18 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 				"trackID", trackID,
 			)
 		}
+		if err == nil && resumingState.Load() {
+			// seeded a bound down track, resumed state should not be used again
+			sub.ClearResumedDownTrack(trackID)
+		}
 
 		go subTrack.Bound(nil)
 
 		subTrack.SetPublisherMuted(t.params.MediaTrack.IsMuted())
+		subTrack.SetModerated(t.isForwardingHeld())
+		if layer := t.maxSpatialLayer.Load(); layer != buffer.InvalidLayerSpatial {
+			subTrack.SetMaxSpatialLayerCap(layer)
+		}
 	})
 
 	downTrack.OnStatsUpdate(func(_ *sfu.DownTrack, stat *livekit.AnalyticsStat) {
@@ -201,6 +338,12 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 		go sub.UpdateMediaRTT(rtt)
 	})
 
+	if t.params.MediaTrack.Kind() == livekit.TrackType_VIDEO {
+		downTrack.OnKeyFrameRequest(func(_ *sfu.DownTrack) {
+			go sub.HandleDecodeFeedback(trackID)
+		})
+	}
+
 	downTrack.AddReceiverReportListener(func(dt *sfu.DownTrack, report *rtcp.ReceiverReport) {
 		sub.HandleReceiverReport(dt, report)
 	})
@@ -212,6 +355,13 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 	var existingTransceiver *webrtc.RTPTransceiver
 	replacedTrack := false
 	existingTransceiver, dtState = sub.GetCachedDownTrack(trackID)
+	if existingTransceiver == nil {
+		if resumedState, ok := sub.GetResumedDownTrack(trackID); ok {
+			// state imported from another node, seed it on new transceiver to keep sequence numbers contiguous
+			dtState = resumedState
+			resumingState.Store(true)
+		}
+	}
 	if existingTransceiver != nil {
 		sub.GetLogger().Debugw(
 			"trying to use existing transceiver",
@@ -298,6 +448,7 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 
 	t.subscribedTracksMu.Lock()
 	t.subscribedTracks[subscriberID] = subTrack
+	t.updateSubscriberStatsLocked()
 	t.subscribedTracksMu.Unlock()
 
 	return subTrack, nil
@@ -371,6 +522,25 @@ func (t *MediaTrackSubscriptions) GetNumSubscribers() int {
 	return len(t.subscribedTracks)
 }
 
+// subscriber counts are exported only for tracks with a subscriber limit
+func (t *MediaTrackSubscriptions) updateSubscriberStatsLocked() {
+	if t.params.MaxSubscribers > 0 && !t.subscriberStatsClosed {
+		prometheus.AddTrackSubscribers(len(t.subscribedTracks) - t.numReportedSubscribers)
+		t.numReportedSubscribers = len(t.subscribedTracks)
+	}
+}
+
+func (t *MediaTrackSubscriptions) closeSubscriberStats() {
+	t.subscribedTracksMu.Lock()
+	defer t.subscribedTracksMu.Unlock()
+
+	if t.params.MaxSubscribers > 0 && !t.subscriberStatsClosed {
+		prometheus.AddTrackSubscribers(-t.numReportedSubscribers)
+		t.numReportedSubscribers = 0
+	}
+	t.subscriberStatsClosed = true
+}
+
 func (t *MediaTrackSubscriptions) UpdateVideoLayers() {
 	for _, st := range t.getAllSubscribedTracks() {
 		st.UpdateVideoLayer()
@@ -434,6 +604,7 @@ func (t *MediaTrackSubscriptions) downTrackClosed(
 
 		t.subscribedTracksMu.Lock()
 		delete(t.subscribedTracks, subscriberID)
+		t.updateSubscriberStatsLocked()
 		t.subscribedTracksMu.Unlock()
 
 		subTrack.Close(willBeResumed)
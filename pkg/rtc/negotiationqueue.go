@@ -0,0 +1,162 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/transport"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+const (
+	negotiationOutcomeSuccess = "success"
+	negotiationOutcomeRetried = "retried"
+	negotiationOutcomeFailed  = "failed"
+	negotiationOutcomeAborted = "aborted"
+)
+
+type PendingNegotiation struct {
+	Cause    transport.NegotiationCause
+	QueuedAt time.Time
+	// included in an offer/answer exchange which has not completed yet
+	InFlight bool
+	// corrective action was already taken for this negotiation
+	Retried bool
+}
+
+// negotiationQueue holds causes of negotiation of a transport until an offer/answer exchange
+// including them completes. A cause is queued at most once while it is waiting for the next exchange.
+type negotiationQueue struct {
+	target livekit.SignalTarget
+
+	lock    sync.Mutex
+	pending []PendingNegotiation
+}
+
+func newNegotiationQueue(target livekit.SignalTarget) *negotiationQueue {
+	return &negotiationQueue{
+		target: target,
+	}
+}
+
+// add returns true if the cause was not already waiting for the next offer/answer exchange
+func (q *negotiationQueue) add(cause transport.NegotiationCause) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for _, pn := range q.pending {
+		if pn.Cause == cause && !pn.InFlight {
+			return false
+		}
+	}
+
+	q.pending = append(q.pending, PendingNegotiation{Cause: cause, QueuedAt: time.Now()})
+	return true
+}
+
+// start marks queued negotiations as included in an offer/answer exchange
+func (q *negotiationQueue) start() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for i := range q.pending {
+		q.pending[i].InFlight = true
+	}
+}
+
+// complete removes negotiations included in the completed offer/answer exchange
+func (q *negotiationQueue) complete() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	remaining := q.pending[:0]
+	for _, pn := range q.pending {
+		if pn.InFlight {
+			prometheus.RecordNegotiation(q.target, pn.Cause.String(), negotiationOutcomeSuccess)
+		} else {
+			remaining = append(remaining, pn)
+		}
+	}
+	q.pending = remaining
+}
+
+// fail removes all negotiations, in flight or not
+func (q *negotiationQueue) fail(outcome string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for _, pn := range q.pending {
+		prometheus.RecordNegotiation(q.target, pn.Cause.String(), outcome)
+	}
+	q.pending = nil
+}
+
+// retry returns true if any negotiation has been pending for longer than timeout, then all pending negotiations
+// are marked as retried as one corrective action covers all of them. Otherwise, it returns when the oldest
+// negotiation will reach the timeout, zero if there is nothing to retry.
+func (q *negotiationQueue) retry(timeout time.Duration) (bool, time.Duration) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	var oldest *PendingNegotiation
+	for i := range q.pending {
+		if !q.pending[i].Retried && (oldest == nil || q.pending[i].QueuedAt.Before(oldest.QueuedAt)) {
+			oldest = &q.pending[i]
+		}
+	}
+	if oldest == nil {
+		return false, 0
+	}
+	if remaining := timeout - time.Since(oldest.QueuedAt); remaining > 0 {
+		return false, remaining
+	}
+
+	for i := range q.pending {
+		pn := &q.pending[i]
+		if !pn.Retried {
+			pn.Retried = true
+			prometheus.RecordNegotiation(q.target, pn.Cause.String(), negotiationOutcomeRetried)
+		}
+	}
+	return true, 0
+}
+
+func (q *negotiationQueue) get() []PendingNegotiation {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return append([]PendingNegotiation{}, q.pending...)
+}
+
+func (q *negotiationQueue) DebugInfo() []map[string]interface{} {
+	return pendingNegotiationsDebugInfo(q.get())
+}
+
+func pendingNegotiationsDebugInfo(pending []PendingNegotiation) []map[string]interface{} {
+	info := make([]map[string]interface{}, 0, len(pending))
+	for _, pn := range pending {
+		info = append(info, map[string]interface{}{
+			"Cause":    pn.Cause.String(),
+			"AgeMs":    time.Since(pn.QueuedAt).Milliseconds(),
+			"InFlight": pn.InFlight,
+			"Retried":  pn.Retried,
+		})
+	}
+	return info
+}
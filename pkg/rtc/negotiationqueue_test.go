@@ -0,0 +1,78 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/transport"
+)
+
+func TestNegotiationQueue(t *testing.T) {
+	t.Run("completes in flight negotiations", func(t *testing.T) {
+		q := newNegotiationQueue(livekit.SignalTarget_SUBSCRIBER)
+		require.True(t, q.add(transport.NegotiationCauseSubscriptionChange))
+		require.False(t, q.add(transport.NegotiationCauseSubscriptionChange))
+		require.True(t, q.add(transport.NegotiationCauseICERestart))
+		require.Len(t, q.get(), 2)
+
+		q.start()
+		// same cause is queued again while an exchange is in progress
+		require.True(t, q.add(transport.NegotiationCauseSubscriptionChange))
+
+		q.complete()
+		pending := q.get()
+		require.Len(t, pending, 1)
+		require.Equal(t, transport.NegotiationCauseSubscriptionChange, pending[0].Cause)
+		require.False(t, pending[0].InFlight)
+
+		debugInfo := q.DebugInfo()
+		require.Len(t, debugInfo, 1)
+		require.Equal(t, "SUBSCRIPTION_CHANGE", debugInfo[0]["Cause"])
+
+		q.fail(negotiationOutcomeFailed)
+		require.Empty(t, q.get())
+	})
+
+	t.Run("retries once after timeout", func(t *testing.T) {
+		q := newNegotiationQueue(livekit.SignalTarget_SUBSCRIBER)
+		retried, nextCheck := q.retry(time.Second)
+		require.False(t, retried)
+		require.Zero(t, nextCheck)
+
+		q.add(transport.NegotiationCauseMigration)
+		retried, nextCheck = q.retry(time.Second)
+		require.False(t, retried)
+		require.Greater(t, nextCheck, time.Duration(0))
+
+		q.pending[0].QueuedAt = time.Now().Add(-2 * time.Second)
+		q.add(transport.NegotiationCauseICERestart)
+		retried, _ = q.retry(time.Second)
+		require.True(t, retried)
+		for _, pn := range q.get() {
+			require.True(t, pn.Retried)
+		}
+
+		// already retried
+		retried, nextCheck = q.retry(time.Second)
+		require.False(t, retried)
+		require.Zero(t, nextCheck)
+	})
+}
@@ -18,7 +18,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
+	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -55,19 +58,56 @@ import (
 )
 
 const (
-	sdBatchSize       = 30
-	rttUpdateInterval = 5 * time.Second
+	defaultSenderReportBatchSize = 30
+	minSenderReportBatchSize     = 1
+	defaultSenderReportInterval  = 3 * time.Second
+	minSenderReportInterval      = 500 * time.Millisecond
 
-	disconnectCleanupDuration = 5 * time.Second
-	migrationWaitDuration     = 3 * time.Second
+	rttUpdateInterval     = 5 * time.Second
+	defaultRTTHistorySize = 12
+
+	disconnectCleanupDuration    = 5 * time.Second
+	defaultMigrationWaitDuration = 3 * time.Second
+
+	// published track without packets for longer than this is not considered active
+	activeMediaTimeout = 5 * time.Second
+
+	adminAuditLogMaxEntries = 100
+
+	// reliable data packets held back while data channel is backpressured
+	maxDeferredReliableDataPackets = 256
+	// held back reliable data which could not be sent is retried after this long
+	reliableDataRetryInterval = 100 * time.Millisecond
+
+	dataPacketFilterLogInterval = time.Second
 
 	PingIntervalSeconds = 5
 	PingTimeoutSeconds  = 15
+
+	// signaling RTT above which connection quality of participants without tracks is degraded
+	signalingRTTGoodThreshold = 250
+	signalingRTTPoorThreshold = 600
+
+	// a new connection quality level has to persist this long to be reported as changed
+	connectionQualitySettleDuration = connectionquality.UpdateInterval
+
+	// bounds of client provided downlink estimate used to seed subscriber bandwidth estimation, in bps
+	minBandwidthHint = 100_000
+	maxBandwidthHint = streamallocator.ChannelCapacityInfinity
 )
 
 type pendingTrackInfo struct {
 	trackInfos []*livekit.TrackInfo
 	migrated   bool
+	// time of last publish request for the cid
+	requestedAt time.Time
+}
+
+// published track which is kept forwarding to subscribers for a while after being removed
+type drainingTrack struct {
+	track     types.MediaTrack
+	timer     *time.Timer
+	onRemoved func()
 }
 
 type downTrackState struct {
@@ -82,6 +122,101 @@ type postRtcpOp struct {
 
 // ---------------------------------------------------------------
 
+// connectionQualityChange tracks connection quality level reported for a participant,
+// a new level is reported once it has persisted for connectionQualitySettleDuration
+type connectionQualityChange struct {
+	reported     livekit.ConnectionQuality
+	pending      livekit.ConnectionQuality
+	pendingSince time.Time
+}
+
+func newConnectionQualityChange() *connectionQualityChange {
+	return &connectionQualityChange{
+		reported: livekit.ConnectionQuality_EXCELLENT,
+	}
+}
+
+// update returns true when quality has settled at a different level than last reported
+func (c *connectionQualityChange) update(quality livekit.ConnectionQuality, at time.Time) bool {
+	if quality == c.reported {
+		c.pendingSince = time.Time{}
+		return false
+	}
+
+	if c.pendingSince.IsZero() || quality != c.pending {
+		c.pending = quality
+		c.pendingSince = at
+		return false
+	}
+
+	if at.Sub(c.pendingSince) < connectionQualitySettleDuration {
+		return false
+	}
+
+	c.reported = quality
+	c.pendingSince = time.Time{}
+	return true
+}
+
+// ---------------------------------------------------------------
+
+// rtcpWriteFailureStreak tracks consecutive RTCP write failures on a transport
+type rtcpWriteFailureStreak struct {
+	lock      sync.Mutex
+	count     int
+	startedAt time.Time
+	// failures since last successful write, irrespective of window
+	numConsecutive int
+}
+
+// update records result of a write and returns true when the streak reaches maxConsecutive
+// failures within window. It fires once per streak, any successful write resets the streak.
+func (r *rtcpWriteFailureStreak) update(err error, maxConsecutive int, window time.Duration) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err == nil {
+		r.count = 0
+		r.startedAt = time.Time{}
+		r.numConsecutive = 0
+		return false
+	}
+	r.numConsecutive++
+
+	now := time.Now()
+	if r.count == 0 || (window > 0 && now.Sub(r.startedAt) > window) {
+		// failures spread out over a longer period are not treated as persistent
+		r.count = 0
+		r.startedAt = now
+	}
+	r.count++
+
+	return maxConsecutive > 0 && r.count == maxConsecutive
+}
+
+func (r *rtcpWriteFailureStreak) get() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.count
+}
+
+func (r *rtcpWriteFailureStreak) getNumConsecutive() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.numConsecutive
+}
+
+// ---------------------------------------------------------------
+
+type unpublishingCid struct {
+	numTracks int
+	published []*livekit.TrackInfo
+}
+
+// ---------------------------------------------------------------
+
 type participantUpdateInfo struct {
 	identity  livekit.ParticipantIdentity
 	version   uint32
@@ -93,6 +228,34 @@ func (p participantUpdateInfo) String() string {
 	return fmt.Sprintf("identity: %s, version: %d, state: %s, updatedAt: %s", p.identity, p.version, p.state.String(), p.updatedAt.String())
 }
 
+// ParticipantUpdateRecord is an update of another participant as received by a participant
+type ParticipantUpdateRecord struct {
+	Identity  livekit.ParticipantIdentity
+	Version   uint32
+	State     livekit.ParticipantInfo_State
+	UpdatedAt time.Time
+	// not sent as a more recent version had already been sent
+	Outdated bool
+}
+
+// ---------------------------------------------------------------
+
+// TrackSIDKey identifies a published track of a participant across sessions,
+// name is included to tell apart multiple tracks of the same source, e. g. two cameras
+type TrackSIDKey struct {
+	Type   livekit.TrackType
+	Source livekit.TrackSource
+	Name   string
+}
+
+func TrackSIDKeyFromInfo(ti *livekit.TrackInfo) TrackSIDKey {
+	return TrackSIDKey{
+		Type:   ti.Type,
+		Source: ti.Source,
+		Name:   ti.Name,
+	}
+}
+
 // ---------------------------------------------------------------
 
 type ParticipantParams struct {
@@ -108,6 +271,7 @@ type ParticipantParams struct {
 	Telemetry               telemetry.TelemetryService
 	Trailer                 []byte
 	PLIThrottleConfig       config.PLIThrottleConfig
+	RTCPWriteFailureConfig  config.RTCPWriteFailureConfig
 	CongestionControlConfig config.CongestionControlConfig
 	// codecs that are enabled for this room
 	PublishEnabledCodecs         []*livekit.Codec
@@ -131,16 +295,66 @@ type ParticipantParams struct {
 	ReconnectOnPublicationError  bool
 	ReconnectOnSubscriptionError bool
 	ReconnectOnDataChannelError  bool
+	StrictMigration              bool
+	PreferMigratedCapabilities   bool
 	DataChannelMaxBufferedAmount uint64
+	DataChannelLowBufferedAmount uint64
+	MaxReliableDataRate          uint64
+	QueueReliableDataAboveRate   bool
+	// reliable data packets kept to be sent to the next session when not acknowledged by the client
+	// on a full reconnect, bounded by count and bytes, 0 means no limit. History is not kept when both are 0
+	ReliableDataHistoryPackets int
+	ReliableDataHistoryBytes   int
+	// reliable data not acknowledged by previous session of this identity, sent once data channels are open
+	PendingReliableData [][]byte
+	// number of media RTT samples kept, defaults to defaultRTTHistorySize
+	RTTHistorySize               int
 	VersionGenerator             utils.TimedVersionGenerator
 	TrackResolver                types.MediaTrackResolver
 	DisableDynacast              bool
 	SubscriberAllowPause         bool
 	SubscriptionLimitAudio       int32
 	SubscriptionLimitVideo       int32
+	SubscriptionLimitScreenShare int32
 	PlayoutDelay                 *livekit.PlayoutDelay
+	PlayoutDelaySources          []livekit.TrackSource
 	SyncStreams                  bool
 	EnableTrafficLoadTracking    bool
+	DataActivityIdleWindow       time.Duration
+	StartPausedSubscriptions     []livekit.TrackType
+	SubscribedTrackSettings      config.SubscribedTrackSettingsConfig
+	DataChannelRateLimit         config.DataChannelRateLimitConfig
+	PublishLimit                 config.PublishLimitConfig
+	UnpublishDrainDuration       time.Duration
+	MaxSubscribersPerTrack       int
+	MaxCachedUpdates             int
+	// downlink estimate from the client, in bps, 0 if not known
+	BandwidthHint int64
+	// returns window to batch participant updates for, nil or 0 sends updates immediately
+	GetUpdateBatchWindow func() time.Duration
+	HeartbeatInterval    time.Duration
+	// interval of sender reports of subscribed tracks, defaults to defaultSenderReportInterval
+	SenderReportInterval time.Duration
+	// source description items per RTCP batch, defaults to defaultSenderReportBatchSize
+	SenderReportBatchSize int
+	// skips sending sender reports and source descriptions of subscribed tracks
+	DisableSenderReports bool
+	// node level scheduler of periodic jobs off the media path
+	JobScheduler *sutils.JobScheduler
+	// time to wait for migration before closing subscriber peer connection, defaults to defaultMigrationWaitDuration
+	MigrationWaitDuration time.Duration
+	// subscriber ICE candidates buffered while migration is in init state, dropped when 0
+	MaxPendingICECandidates int
+	// SIDs of tracks published by previous session of this identity, reused by matching tracks when published again
+	PreviousTrackSIDs map[TrackSIDKey]livekit.TrackID
+	// full reconnects issued to previous sessions of this identity, counting continues from here
+	ReconnectCount uint32
+	// state exported by the node participant is migrating from, imported once participant is set up
+	ResumeState *ResumeState
+	// layers given up first by subscribed video tracks when constrained
+	AllocationPreference sfu.AllocationPreference
+	// consulted before forwarding a data packet, returns false to drop it. nil forwards all
+	DataPacketFilter func(sender types.LocalParticipant, dp *livekit.DataPacket) bool
 }
 
 type ParticipantImpl struct {
@@ -169,9 +383,24 @@ type ParticipantImpl struct {
 	// timer that's set when disconnect is detected on primary PC
 	disconnectTimer *time.Timer
 	migrationTimer  *time.Timer
+	// migration out of this node starts at MaybeStartMigration,
+	// migration into this node starts at participant creation
+	migrationStartedAt   time.Time
+	migrationCompletedAt time.Time
+	// subscriber ICE candidates gathered while migration is in init state
+	pendingICECandidates []*webrtc.ICECandidate
 
 	pubRTCPQueue *sutils.TypedOpsQueue[postRtcpOp]
 
+	pubRTCPWriteFailures rtcpWriteFailureStreak
+	subRTCPWriteFailures rtcpWriteFailureStreak
+
+	// append-only, bounded log of admin actions applied to this participant
+	adminAuditLock sync.Mutex
+	adminAuditLog  []types.AdminAuditEntry
+	// origin of an admin requested close, guarded by lock
+	closeAdminOpts *types.AdminActionOptions
+
 	// hold reference for MediaTrack
 	twcc *twcc.Responder
 
@@ -179,6 +408,16 @@ type ParticipantImpl struct {
 	pendingTracksLock       utils.RWMutex
 	pendingTracks           map[string]*pendingTrackInfo
 	pendingPublishingTracks map[livekit.TrackID]*pendingTrackInfo
+	// requested -> actual codec mime of codecs substituted at publish, guarded by pendingTracksLock
+	codecFallbacks map[livekit.TrackID]map[string]string
+	// applied to tracks published later, guarded by pendingTracksLock
+	pliThrottleConfig config.PLIThrottleConfig
+	// cap on spatial layer forwarded to subscribers by source, guarded by pendingTracksLock
+	maxSpatialLayerBySource map[livekit.TrackSource]int32
+	// guarded by pendingTracksLock
+	publishLimiter *publishLimiter
+	// SIDs of previous session not reused yet, guarded by pendingTracksLock
+	previousTrackSIDs map[TrackSIDKey]livekit.TrackID
 
 	// supported codecs
 	enabledPublishCodecs   []*livekit.Codec
@@ -189,8 +428,17 @@ type ParticipantImpl struct {
 	*SubscriptionManager
 	*ParticipantTrafficLoad
 
+	heartbeatJob *sutils.ScheduledJob
+
 	// keeps track of unpublished tracks in order to reuse trackID
 	unpublishedTracks []*livekit.TrackInfo
+	// removed tracks still forwarding to subscribers, keyed by signal cid, guarded by lock
+	drainingTracks map[string]*drainingTrack
+
+	// signal cids with tracks being unpublished, TrackPublished for those cids is held back till
+	// TrackUnpublished is sent so that client sees them in order. Signals are written under the lock
+	unpublishingLock sync.Mutex
+	unpublishingCids map[string]*unpublishingCid
 
 	requireBroadcast bool
 	// queued participant updates before join response is sent
@@ -199,12 +447,48 @@ type ParticipantImpl struct {
 	// cache of recently sent updates, to ensuring ordering by version
 	// guarded by updateLock
 	updateCache *lru.Cache[livekit.ParticipantID, participantUpdateInfo]
-	updateLock  utils.Mutex
-
-	dataChannelStats *telemetry.BytesTrackStats
+	// recent updates, oldest first, only kept when more than one update is cached per participant
+	// guarded by updateLock
+	updateHistory *lru.Cache[livekit.ParticipantID, []ParticipantUpdateRecord]
+	// batched updates of other participants, newest version of each, flushed on timer
+	// guarded by updateLock
+	batchedUpdates     map[livekit.ParticipantID]*livekit.ParticipantInfo
+	batchedUpdateTimer *time.Timer
+	updateLock         utils.Mutex
+
+	dataChannelStats       *telemetry.BytesTrackStats
+	dataChannelRateLimiter *dataChannelRateLimiter
+	// last time a filtered data packet was logged, unix nano
+	dataPacketFilterLoggedAt atomic.Int64
+	// reliable data packets are sent in order under lock when backpressure is enabled
+	reliableDataLock     sync.Mutex
+	deferredReliableData [][]byte
+	reliableDataHistory  *reliableDataHistory
+	// kept when a full reconnect is issued, for the next session of this identity
+	unacknowledgedReliableData [][]byte
+	pendingReliableData        [][]byte
+	dataChannelErrorReconnect  atomic.Bool
+	// rate limit of reliable data, held back data is flushed on timer when it is within the rate
+	reliableDataBucket     tokenBucket
+	reliableDataBucketAt   time.Time
+	reliableDataFlushTimer *time.Timer
+
+	// clamped bandwidth hint the subscriber transport was last seeded with
+	bandwidthHint atomic.Int64
+
+	// minimum interval between key frame requests on behalf of this subscriber, 0 disables
+	subscriberPLIThrottle atomic.Duration
+
+	// full reconnects issued, including those of previous sessions
+	reconnectCount atomic.Uint32
+
+	// custom subscriber pacing, guarded by lock
+	pacerSettings types.PacerSettings
 
 	rttUpdatedAt time.Time
 	lastRTT      uint32
+	// distinct media RTT samples, oldest first, bounded by RTTHistorySize
+	rttHistory []types.RTTSample
 
 	lock utils.RWMutex
 
@@ -214,9 +498,11 @@ type ParticipantImpl struct {
 	// callbacks & handlers
 	onTrackPublished     func(types.LocalParticipant, types.MediaTrack)
 	onTrackUpdated       func(types.LocalParticipant, types.MediaTrack)
+	onTrackMuteChanged   func(types.LocalParticipant, types.MediaTrack, bool)
 	onTrackUnpublished   func(types.LocalParticipant, types.MediaTrack)
 	onStateChange        func(p types.LocalParticipant, state livekit.ParticipantInfo_State)
 	onMigrateStateChange func(p types.LocalParticipant, migrateState types.MigrateState)
+	onMigrationTimeout   func(p types.LocalParticipant)
 	onParticipantUpdate  func(types.LocalParticipant)
 	onDataPacket         func(types.LocalParticipant, livekit.DataPacket_Kind, *livekit.DataPacket)
 
@@ -225,8 +511,23 @@ type ParticipantImpl struct {
 	onClose            func(types.LocalParticipant)
 	onClaimsChanged    func(participant types.LocalParticipant)
 	onICEConfigChanged func(participant types.LocalParticipant, iceConfig *livekit.ICEConfig)
+	onRTCPWriteFailure func(participant types.LocalParticipant, target livekit.SignalTarget, err error)
+
+	onDataChannelBufferedAmountLow func(participant types.LocalParticipant, kind livekit.DataPacket_Kind)
+	onAvailableLayersChanged       func(participant types.LocalParticipant, trackID livekit.TrackID, availableLayers []int32)
+
+	onConnectionQualityChanged func(participant types.LocalParticipant, info *livekit.ConnectionQualityInfo)
+
+	// settles level changes seen in GetConnectionQuality, changes are notified on the queue
+	connectionQualityLock        sync.Mutex
+	connectionQualityChange      *connectionQualityChange
+	connectionQualityNotifyQueue *sutils.OpsQueue
 
 	cachedDownTracks map[livekit.TrackID]*downTrackState
+	// down track states imported via ImportResumeState, consumed on first successful bind
+	resumedDownTracks map[livekit.TrackID]sfu.DownTrackState
+	// protocol capabilities resolved on the node participant migrated from
+	migratedCapabilities map[string]bool
 
 	supervisor *supervisor.ParticipantSupervisor
 
@@ -247,6 +548,17 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 	if params.Grants == nil || params.Grants.Video == nil {
 		return nil, ErrMissingGrants
 	}
+	if params.JobScheduler == nil {
+		return nil, ErrMissingJobScheduler
+	}
+	if params.MigrationWaitDuration <= 0 {
+		params.MigrationWaitDuration = defaultMigrationWaitDuration
+	}
+	params.SenderReportInterval, params.SenderReportBatchSize = validateSenderReportParams(
+		params.Logger,
+		params.SenderReportInterval,
+		params.SenderReportBatchSize,
+	)
 	p := &ParticipantImpl{
 		params:       params,
 		disconnected: make(chan struct{}),
@@ -255,26 +567,48 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 			MinSize: 64,
 			Logger:  params.Logger,
 		}),
+		connectionQualityChange: newConnectionQualityChange(),
+		connectionQualityNotifyQueue: sutils.NewOpsQueue(sutils.OpsQueueParams{
+			Name:    "connection-quality-notify",
+			MinSize: 4,
+			Logger:  params.Logger,
+		}),
 		pendingTracks:           make(map[string]*pendingTrackInfo),
 		pendingPublishingTracks: make(map[livekit.TrackID]*pendingTrackInfo),
+		codecFallbacks:          make(map[livekit.TrackID]map[string]string),
+		pliThrottleConfig:       params.PLIThrottleConfig,
+		maxSpatialLayerBySource: make(map[livekit.TrackSource]int32),
+		previousTrackSIDs:       maps.Clone(params.PreviousTrackSIDs),
 		connectedAt:             time.Now(),
 		rttUpdatedAt:            time.Now(),
 		cachedDownTracks:        make(map[livekit.TrackID]*downTrackState),
+		resumedDownTracks:       make(map[livekit.TrackID]sfu.DownTrackState),
 		dataChannelStats: telemetry.NewBytesTrackStats(
 			telemetry.BytesTrackIDForParticipantID(telemetry.BytesTrackTypeData, params.SID),
 			params.SID,
-			params.Telemetry),
-		tracksQuality: make(map[livekit.TrackID]livekit.ConnectionQuality),
-		pubLogger:     params.Logger.WithComponent(sutils.ComponentPub),
-		subLogger:     params.Logger.WithComponent(sutils.ComponentSub),
+			params.Telemetry,
+			params.JobScheduler),
+		dataChannelRateLimiter: newDataChannelRateLimiter(params.DataChannelRateLimit),
+		reliableDataBucket:     newTokenBucket(params.MaxReliableDataRate, 0),
+		reliableDataBucketAt:   time.Now(),
+		reliableDataHistory:    newReliableDataHistory(params.ReliableDataHistoryPackets, params.ReliableDataHistoryBytes),
+		pendingReliableData:    params.PendingReliableData,
+		publishLimiter:         newPublishLimiter(params.PublishLimit),
+		tracksQuality:          make(map[livekit.TrackID]livekit.ConnectionQuality),
+		pubLogger:              params.Logger.WithComponent(sutils.ComponentPub),
+		subLogger:              params.Logger.WithComponent(sutils.ComponentSub),
 	}
 	if !params.DisableSupervisor {
 		p.supervisor = supervisor.NewParticipantSupervisor(supervisor.ParticipantSupervisorParams{Logger: params.Logger})
 	}
 	p.closeReason.Store(types.ParticipantCloseReasonNone)
 	p.version.Store(params.InitialVersion)
+	p.reconnectCount.Store(params.ReconnectCount)
 	p.timedVersion.Update(params.VersionGenerator.Next())
 	p.migrateState.Store(types.MigrateStateInit)
+	if params.Migration {
+		p.migrationStartedAt = time.Now()
+	}
 	p.state.Store(livekit.ParticipantInfo_JOINING)
 	p.grants = params.Grants
 	p.hidden.Store(p.grants.Video.Hidden)
@@ -290,6 +624,11 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 	if p.updateCache, err = lru.New[livekit.ParticipantID, participantUpdateInfo](128); err != nil {
 		return nil, err
 	}
+	if params.MaxCachedUpdates > 1 {
+		if p.updateHistory, err = lru.New[livekit.ParticipantID, []ParticipantUpdateRecord](128); err != nil {
+			return nil, err
+		}
+	}
 
 	err = p.setupTransportManager()
 	if err != nil {
@@ -299,10 +638,52 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 	p.setupUpTrackManager()
 	p.setupSubscriptionManager()
 	p.setupParticipantTrafficLoad()
+	p.connectionQualityNotifyQueue.Start()
+
+	if params.ResumeState != nil {
+		p.ImportResumeState(params.ResumeState)
+	}
+
+	if params.HeartbeatInterval > 0 {
+		p.heartbeatJob = params.JobScheduler.Schedule(
+			"heartbeat-"+string(params.SID),
+			params.HeartbeatInterval,
+			true,
+			p.sendHeartbeat,
+		)
+	}
 
 	return p, nil
 }
 
+// validateSenderReportParams applies defaults to unset values and raises values below minimum,
+// a zero interval would make the sender report worker spin
+func validateSenderReportParams(lgr logger.Logger, interval time.Duration, batchSize int) (time.Duration, int) {
+	if interval == 0 {
+		interval = defaultSenderReportInterval
+	} else if interval < minSenderReportInterval {
+		lgr.Warnw(
+			"sender report interval too low, using minimum", nil,
+			"interval", interval,
+			"minimum", minSenderReportInterval,
+		)
+		interval = minSenderReportInterval
+	}
+
+	if batchSize == 0 {
+		batchSize = defaultSenderReportBatchSize
+	} else if batchSize < minSenderReportBatchSize {
+		lgr.Warnw(
+			"sender report batch size too low, using minimum", nil,
+			"batchSize", batchSize,
+			"minimum", minSenderReportBatchSize,
+		)
+		batchSize = minSenderReportBatchSize
+	}
+
+	return interval, batchSize
+}
+
 func (p *ParticipantImpl) GetTrailer() []byte {
 	trailer := make([]byte, len(p.params.Trailer))
 	copy(trailer, p.params.Trailer)
@@ -321,6 +702,84 @@ func (p *ParticipantImpl) GetPacer() pacer.Pacer {
 	return p.TransportManager.GetSubscriberPacer()
 }
 
+// GetPacerSendRate returns the rate subscriber pacer is achieving, in bps,
+// lower than allocated bandwidth indicates a pacing backlog
+func (p *ParticipantImpl) GetPacerSendRate() int64 {
+	if pacer := p.GetPacer(); pacer != nil {
+		return pacer.GetSendRate()
+	}
+	return 0
+}
+
+// SetPacerBurstLimit limits bytes subscriber pacer sends in a pacing interval, 0 removes the limit.
+// Packets already queued are kept and sent under the new limit.
+func (p *ParticipantImpl) SetPacerBurstLimit(bytes int) {
+	bytes = max(bytes, 0)
+
+	p.lock.Lock()
+	p.pacerSettings.BurstLimit = bytes
+	p.lock.Unlock()
+
+	p.params.Logger.Debugw("setting pacer burst limit", "bytes", bytes)
+	if pacer := p.GetPacer(); pacer != nil {
+		pacer.SetBurstLimit(bytes)
+	}
+}
+
+// SetPacerInterval sets interval at which subscriber pacer sends queued packets, 0 restores default pacing.
+// Packets already queued are kept and sent at the new interval.
+func (p *ParticipantImpl) SetPacerInterval(d time.Duration) {
+	d = max(d, 0)
+
+	p.lock.Lock()
+	p.pacerSettings.Interval = d
+	p.lock.Unlock()
+
+	p.params.Logger.Debugw("setting pacer interval", "interval", d)
+	if pacer := p.GetPacer(); pacer != nil {
+		pacer.SetInterval(d)
+	}
+}
+
+func (p *ParticipantImpl) GetPacerSettings() types.PacerSettings {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.pacerSettings
+}
+
+// SetBandwidthHint seeds subscriber bandwidth estimation with a downlink estimate from the client,
+// on join or after a network change. The hint is clamped to sane bounds, non-positive hints are ignored.
+func (p *ParticipantImpl) SetBandwidthHint(hint int64) {
+	if hint <= 0 {
+		return
+	}
+
+	seed := hint
+	if seed < minBandwidthHint {
+		seed = minBandwidthHint
+	} else if seed > maxBandwidthHint {
+		seed = maxBandwidthHint
+	}
+
+	// previous hint is replaced, record how far estimation had moved from it
+	p.recordBandwidthHintDivergence()
+	p.bandwidthHint.Store(seed)
+
+	p.subLogger.Debugw("seeding subscriber bandwidth estimate from hint", "hint", hint, "seed", seed)
+	p.TransportManager.SeedSubscriberChannelCapacity(seed)
+}
+
+func (p *ParticipantImpl) recordBandwidthHintDivergence() {
+	hint := p.bandwidthHint.Load()
+	estimate := p.TransportManager.GetSubscriberReceivedEstimate()
+	if hint == 0 || estimate == 0 {
+		return
+	}
+
+	prometheus.RecordBandwidthHintDivergence(hint, estimate)
+}
+
 func (p *ParticipantImpl) ID() livekit.ParticipantID {
 	return p.params.SID
 }
@@ -392,6 +851,13 @@ func (p *ParticipantImpl) IsIdle() bool {
 		}
 	}
 
+	// data only participants are active as long as data is flowing
+	if window := p.params.DataActivityIdleWindow; window > 0 {
+		if lastActivityAt := p.dataChannelStats.LastActivityAt(); !lastActivityAt.IsZero() && time.Since(lastActivityAt) < window {
+			return false
+		}
+	}
+
 	return !p.SubscriptionManager.HasSubscriptions()
 }
 
@@ -417,6 +883,11 @@ func (p *ParticipantImpl) GetBufferFactory() *buffer.Factory {
 
 // SetName attaches name to the participant
 func (p *ParticipantImpl) SetName(name string) {
+	if p.IsClosed() || p.IsDisconnected() {
+		p.params.Logger.Infow("ignoring name update of closed participant", "name", name)
+		return
+	}
+
 	p.lock.Lock()
 	if p.grants.Name == name {
 		p.lock.Unlock()
@@ -440,6 +911,11 @@ func (p *ParticipantImpl) SetName(name string) {
 
 // SetMetadata attaches metadata to the participant
 func (p *ParticipantImpl) SetMetadata(metadata string) {
+	if p.IsClosed() || p.IsDisconnected() {
+		p.params.Logger.Infow("ignoring metadata update of closed participant", "metadataSize", len(metadata))
+		return
+	}
+
 	p.lock.Lock()
 	if p.grants.Metadata == metadata {
 		p.lock.Unlock()
@@ -469,7 +945,7 @@ func (p *ParticipantImpl) ClaimGrants() *auth.ClaimGrants {
 	return p.grants.Clone()
 }
 
-func (p *ParticipantImpl) SetPermission(permission *livekit.ParticipantPermission) bool {
+func (p *ParticipantImpl) SetPermission(permission *livekit.ParticipantPermission, adminOpts *types.AdminActionOptions) bool {
 	if permission == nil {
 		return false
 	}
@@ -497,10 +973,13 @@ func (p *ParticipantImpl) SetPermission(permission *livekit.ParticipantPermissio
 	p.requireBroadcast = p.requireBroadcast || isPublisher
 	p.lock.Unlock()
 
+	p.recordAdminAction(adminOpts, types.AdminActionUpdatePermission, "")
+
 	// publish permission has been revoked then remove offending tracks
 	for _, track := range p.GetPublishedTracks() {
 		if !video.GetCanPublishSource(track.Source()) {
-			p.removePublishedTrack(track)
+			p.removePublishedTrack(track, p.params.UnpublishDrainDuration)
+			p.recordAdminAction(adminOpts, types.AdminActionRemoveTrack, track.ID())
 		}
 	}
 
@@ -650,6 +1129,18 @@ func (p *ParticipantImpl) getOnMigrateStateChange() func(p types.LocalParticipan
 	return p.onMigrateStateChange
 }
 
+func (p *ParticipantImpl) OnMigrationTimeout(callback func(p types.LocalParticipant)) {
+	p.lock.Lock()
+	p.onMigrationTimeout = callback
+	p.lock.Unlock()
+}
+
+func (p *ParticipantImpl) getOnMigrationTimeout() func(p types.LocalParticipant) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.onMigrationTimeout
+}
+
 func (p *ParticipantImpl) OnTrackUpdated(callback func(types.LocalParticipant, types.MediaTrack)) {
 	p.lock.Lock()
 	p.onTrackUpdated = callback
@@ -662,6 +1153,20 @@ func (p *ParticipantImpl) getOnTrackUpdated() func(types.LocalParticipant, types
 	return p.onTrackUpdated
 }
 
+// OnTrackMuteChanged is called when mute state of a published or pending track flips,
+// track is nil for a track pending publication
+func (p *ParticipantImpl) OnTrackMuteChanged(callback func(types.LocalParticipant, types.MediaTrack, bool)) {
+	p.lock.Lock()
+	p.onTrackMuteChanged = callback
+	p.lock.Unlock()
+}
+
+func (p *ParticipantImpl) getOnTrackMuteChanged() func(types.LocalParticipant, types.MediaTrack, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.onTrackMuteChanged
+}
+
 func (p *ParticipantImpl) OnParticipantUpdate(callback func(types.LocalParticipant)) {
 	p.lock.Lock()
 	p.onParticipantUpdate = callback
@@ -674,6 +1179,35 @@ func (p *ParticipantImpl) OnDataPacket(callback func(types.LocalParticipant, liv
 	p.lock.Unlock()
 }
 
+// OnDataChannelBufferedAmountLow sets a callback invoked when buffered amount of a data channel
+// drains to the low buffered amount, after having been above it
+func (p *ParticipantImpl) OnDataChannelBufferedAmountLow(callback func(participant types.LocalParticipant, kind livekit.DataPacket_Kind)) {
+	p.lock.Lock()
+	p.onDataChannelBufferedAmountLow = callback
+	p.lock.Unlock()
+}
+
+func (p *ParticipantImpl) getOnDataChannelBufferedAmountLow() func(participant types.LocalParticipant, kind livekit.DataPacket_Kind) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.onDataChannelBufferedAmountLow
+}
+
+// OnAvailableLayersChanged sets a callback invoked when spatial layers of a published track start or stop
+func (p *ParticipantImpl) OnAvailableLayersChanged(callback func(participant types.LocalParticipant, trackID livekit.TrackID, availableLayers []int32)) {
+	p.lock.Lock()
+	p.onAvailableLayersChanged = callback
+	p.lock.Unlock()
+}
+
+func (p *ParticipantImpl) getOnAvailableLayersChanged() func(participant types.LocalParticipant, trackID livekit.TrackID, availableLayers []int32) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.onAvailableLayersChanged
+}
+
 func (p *ParticipantImpl) OnClose(callback func(types.LocalParticipant)) {
 	p.lock.Lock()
 	p.onClose = callback
@@ -686,6 +1220,20 @@ func (p *ParticipantImpl) OnClaimsChanged(callback func(types.LocalParticipant))
 	p.lock.Unlock()
 }
 
+// OnRTCPWriteFailure sets a callback invoked when RTCP writes on a transport fail persistently.
+// When not set, it is handled as a transport failure.
+func (p *ParticipantImpl) OnRTCPWriteFailure(callback func(participant types.LocalParticipant, target livekit.SignalTarget, err error)) {
+	p.lock.Lock()
+	p.onRTCPWriteFailure = callback
+	p.lock.Unlock()
+}
+
+func (p *ParticipantImpl) getOnRTCPWriteFailure() func(participant types.LocalParticipant, target livekit.SignalTarget, err error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.onRTCPWriteFailure
+}
+
 func (p *ParticipantImpl) HandleSignalSourceClose() {
 	p.TransportManager.SetSignalSourceValid(false)
 
@@ -750,6 +1298,11 @@ func (p *ParticipantImpl) handleMigrateTracks() {
 
 		if len(pti.trackInfos) > 1 {
 			p.pubLogger.Warnw("too many pending migrated tracks", nil, "trackID", pti.trackInfos[0].Sid, "count", len(pti.trackInfos), "cid", cid)
+			if p.params.StrictMigration {
+				p.pendingTracksLock.Unlock()
+				p.IssueFullReconnect(types.ParticipantCloseReasonMigrateTooManyTracks)
+				return
+			}
 		}
 
 		mt := p.addMigratedTrack(cid, pti.trackInfos[0])
@@ -785,21 +1338,35 @@ func (p *ParticipantImpl) AddTrack(req *livekit.AddTrackRequest) {
 		return
 	}
 
+	// re-publishing a draining track, stop forwarding the draining one
+	p.cancelTrackDrain(req.Cid)
+
 	p.lock.Lock()
-	defer p.lock.Unlock()
-	ti := p.addPendingTrackLocked(req)
-	if ti == nil {
-		return
+	ti, rejected, rejectedRepeatedly := p.addPendingTrackLocked(req)
+	if ti != nil {
+		p.sendTrackPublishedOrdered(req.Cid, ti)
 	}
+	p.lock.Unlock()
 
-	p.sendTrackPublished(req.Cid, ti)
+	if rejected {
+		// there is no error in publish responses, a response without track info lets the client
+		// fail the publication right away instead of waiting for the response to time out
+		p.sendTrackPublished(req.Cid, nil)
+	}
+	if rejectedRepeatedly {
+		p.pubLogger.Warnw("closing participant, publish requests rejected repeatedly", nil)
+		p.Close(true, types.ParticipantCloseReasonPublishRateExceeded, false)
+	}
 }
 
 func (p *ParticipantImpl) SetMigrateInfo(
 	previousOffer, previousAnswer *webrtc.SessionDescription,
 	mediaTracks []*livekit.TrackPublishedResponse,
 	dataChannels []*livekit.DataChannelInfo,
+	capabilities map[string]bool,
 ) {
+	p.setMigratedCapabilities(capabilities)
+
 	p.pendingTracksLock.Lock()
 	for _, t := range mediaTracks {
 		ti := t.GetTrack()
@@ -821,17 +1388,94 @@ func (p *ParticipantImpl) SetMigrateInfo(
 	p.TransportManager.SetMigrateInfo(previousOffer, previousAnswer, dataChannels)
 }
 
+// setMigratedCapabilities holds capabilities resolved on the node participant migrated from.
+// A mismatch with capabilities derived on this node is logged, it usually means client info
+// was not carried over or nodes run different versions during a rolling upgrade.
+func (p *ParticipantImpl) setMigratedCapabilities(capabilities map[string]bool) {
+	if len(capabilities) == 0 {
+		return
+	}
+
+	derived := p.ProtocolVersion().Capabilities()
+	// decided when transports are created, migrated value cannot be applied
+	derived[types.CapabilitySubscriberAsPrimary] = p.SubscriberAsPrimary()
+
+	var mismatched []string
+	for _, name := range []string{
+		types.CapabilitySubscriberAsPrimary,
+		types.CapabilityUnpublish,
+		types.CapabilityConnectionQualityLost,
+	} {
+		if supported, ok := capabilities[name]; ok && supported != derived[name] {
+			mismatched = append(mismatched, name)
+		}
+	}
+	if len(mismatched) != 0 {
+		p.params.Logger.Warnw(
+			"migrated capabilities mismatch", nil,
+			"mismatched", mismatched,
+			"protocolVersion", p.ProtocolVersion(),
+			"preferMigrated", p.params.PreferMigratedCapabilities,
+		)
+	}
+
+	migrated := make(map[string]bool, len(capabilities))
+	for name, supported := range capabilities {
+		migrated[name] = supported
+	}
+	p.lock.Lock()
+	p.migratedCapabilities = migrated
+	p.lock.Unlock()
+}
+
+// hasCapability returns capability resolved before migration if preferred and available,
+// else the given value derived from protocol version
+func (p *ParticipantImpl) hasCapability(name string, derived bool) bool {
+	if !p.params.PreferMigratedCapabilities {
+		return derived
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if supported, ok := p.migratedCapabilities[name]; ok {
+		return supported
+	}
+	return derived
+}
+
+func (p *ParticipantImpl) resolvedCapabilities() map[string]bool {
+	capabilities := p.ProtocolVersion().Capabilities()
+	for name, supported := range capabilities {
+		capabilities[name] = p.hasCapability(name, supported)
+	}
+	capabilities[types.CapabilitySubscriberAsPrimary] = p.SubscriberAsPrimary()
+	return capabilities
+}
+
 func (p *ParticipantImpl) Close(sendLeave bool, reason types.ParticipantCloseReason, isExpectedToResume bool) error {
 	if p.isClosed.Swap(true) {
 		// already closed
 		return nil
 	}
 
+	if reason == types.ParticipantCloseReasonServiceRequestRemoveParticipant ||
+		reason == types.ParticipantCloseReasonServiceRequestDeleteRoom {
+		p.lock.RLock()
+		closeAdminOpts := p.closeAdminOpts
+		p.lock.RUnlock()
+		if closeAdminOpts == nil {
+			closeAdminOpts = &types.AdminActionOptions{}
+		}
+		p.recordAdminAction(closeAdminOpts, types.AdminActionRemoveParticipant, "")
+	}
+
 	p.params.Logger.Infow(
 		"participant closing",
 		"sendLeave", sendLeave,
 		"reason", reason.String(),
 		"isExpectedToResume", isExpectedToResume,
+		"adminActions", p.adminAuditLogInfo(),
 	)
 	p.closeReason.Store(reason)
 	p.clearDisconnectTimer()
@@ -847,9 +1491,26 @@ func (p *ParticipantImpl) Close(sendLeave bool, reason types.ParticipantCloseRea
 
 	p.pendingTracksLock.Lock()
 	p.pendingTracks = make(map[string]*pendingTrackInfo)
+	p.codecFallbacks = make(map[livekit.TrackID]map[string]string)
 	p.pendingPublishingTracks = make(map[livekit.TrackID]*pendingTrackInfo)
 	p.pendingTracksLock.Unlock()
 
+	// draining tracks are closed along with other published tracks
+	p.lock.Lock()
+	for _, dt := range p.drainingTracks {
+		dt.timer.Stop()
+	}
+	p.drainingTracks = nil
+	p.lock.Unlock()
+
+	p.reliableDataLock.Lock()
+	if p.reliableDataFlushTimer != nil {
+		p.reliableDataFlushTimer.Stop()
+		p.reliableDataFlushTimer = nil
+	}
+	p.deferredReliableData = nil
+	p.reliableDataLock.Unlock()
+
 	p.UpTrackManager.Close(isExpectedToResume)
 
 	p.updateState(livekit.ParticipantInfo_DISCONNECTED)
@@ -868,16 +1529,75 @@ func (p *ParticipantImpl) Close(sendLeave bool, reason types.ParticipantCloseRea
 	// Close will block.
 	go func() {
 		p.SubscriptionManager.Close(isExpectedToResume)
+		p.recordBandwidthHintDivergence()
 		p.TransportManager.Close()
 		if p.ParticipantTrafficLoad != nil {
 			p.ParticipantTrafficLoad.Close()
 		}
 	}()
 
+	p.heartbeatJob.Stop()
+	p.connectionQualityNotifyQueue.Stop()
+
 	p.dataChannelStats.Stop()
 	return nil
 }
 
+// SetCloseAdminOptions records the origin of an admin requested close, to be used in the audit log on Close
+func (p *ParticipantImpl) SetCloseAdminOptions(adminOpts *types.AdminActionOptions) {
+	p.lock.Lock()
+	p.closeAdminOpts = adminOpts
+	p.lock.Unlock()
+}
+
+// GetAdminAuditLog returns admin actions applied to this participant, oldest first
+func (p *ParticipantImpl) GetAdminAuditLog() []types.AdminAuditEntry {
+	p.adminAuditLock.Lock()
+	defer p.adminAuditLock.Unlock()
+
+	if len(p.adminAuditLog) == 0 {
+		return nil
+	}
+	entries := make([]types.AdminAuditEntry, len(p.adminAuditLog))
+	copy(entries, p.adminAuditLog)
+	return entries
+}
+
+func (p *ParticipantImpl) adminAuditLogInfo() []map[string]interface{} {
+	var adminActions []map[string]interface{}
+	for _, entry := range p.GetAdminAuditLog() {
+		adminActions = append(adminActions, map[string]interface{}{
+			"At":      entry.At.String(),
+			"Action":  entry.Action.String(),
+			"TrackID": entry.TrackID,
+			"Actor":   entry.Actor,
+		})
+	}
+	return adminActions
+}
+
+func (p *ParticipantImpl) recordAdminAction(adminOpts *types.AdminActionOptions, action types.AdminAction, trackID livekit.TrackID) {
+	if adminOpts == nil {
+		return
+	}
+
+	entry := types.AdminAuditEntry{
+		At:      time.Now(),
+		Action:  action,
+		TrackID: trackID,
+		Actor:   adminOpts.Actor,
+	}
+	p.params.Logger.Infow("admin action", "action", action.String(), "trackID", trackID, "actor", adminOpts.Actor)
+
+	p.adminAuditLock.Lock()
+	if len(p.adminAuditLog) >= adminAuditLogMaxEntries {
+		// drop oldest
+		p.adminAuditLog = append(p.adminAuditLog[:0], p.adminAuditLog[1:]...)
+	}
+	p.adminAuditLog = append(p.adminAuditLog, entry)
+	p.adminAuditLock.Unlock()
+}
+
 func (p *ParticipantImpl) IsClosed() bool {
 	return p.isClosed.Load()
 }
@@ -889,9 +1609,35 @@ func (p *ParticipantImpl) CloseReason() types.ParticipantCloseReason {
 // Negotiate subscriber SDP with client, if force is true, will cancel pending
 // negotiate task and negotiate immediately
 func (p *ParticipantImpl) Negotiate(force bool) {
-	if p.MigrateState() != types.MigrateStateInit {
-		p.TransportManager.NegotiateSubscriber(force)
+	migrateState := p.MigrateState()
+	if migrateState == types.MigrateStateInit {
+		return
+	}
+
+	cause := transport.NegotiationCauseSubscriptionChange
+	if migrateState == types.MigrateStateSync {
+		cause = transport.NegotiationCauseMigration
 	}
+	p.TransportManager.NegotiateSubscriber(force, cause)
+}
+
+// ForceSubscriberRenegotiation sends a subscriber offer immediately regardless of migration state,
+// it is meant to recover a stuck subscriber and should be used with care.
+//
+// Unlike Negotiate, it also sends an offer while migration has not synced (MigrateStateInit).
+// The client may then receive an offer before it has restored subscriber state from the previous node,
+// and subscriber ICE candidates are still held back until migration syncs, so the offer may not connect
+// by itself. Migration state is not changed by a forced renegotiation. An offer/answer exchange in progress
+// is not disrupted, the offer is sent after it completes.
+func (p *ParticipantImpl) ForceSubscriberRenegotiation() error {
+	// an offer before the join response would not be understood by the client
+	if p.IsClosed() || !p.IsReady() {
+		return ErrParticipantNotReady
+	}
+
+	p.subLogger.Infow("forcing subscriber renegotiation", "migrateState", p.MigrateState().String())
+	p.TransportManager.NegotiateSubscriber(true, transport.NegotiationCauseForced)
+	return nil
 }
 
 func (p *ParticipantImpl) clearMigrationTimer() {
@@ -915,13 +1661,14 @@ func (p *ParticipantImpl) setupMigrationTimerLocked() {
 	// to try and succeed. If not, close the subscriber peer connection
 	// and help the remote side to narrow down its ICE candidate pool.
 	//
-	p.migrationTimer = time.AfterFunc(migrationWaitDuration, func() {
+	p.migrationTimer = time.AfterFunc(p.params.MigrationWaitDuration, func() {
 		p.clearMigrationTimer()
 
 		if p.IsClosed() || p.IsDisconnected() {
 			return
 		}
 		p.subLogger.Debugw("closing subscriber peer connection to aid migration")
+		prometheus.RecordMigrationTimedOut()
 
 		//
 		// Close all down tracks before closing subscriber peer connection.
@@ -931,6 +1678,9 @@ func (p *ParticipantImpl) setupMigrationTimerLocked() {
 		//
 		p.SubscriptionManager.Close(true)
 
+		if onMigrationTimeout := p.getOnMigrationTimeout(); onMigrationTimeout != nil {
+			onMigrationTimeout(p)
+		}
 		p.TransportManager.SubscriberClose()
 	})
 }
@@ -954,9 +1704,11 @@ func (p *ParticipantImpl) MaybeStartMigration(force bool, onStart func()) bool {
 	p.clearMigrationTimer()
 
 	p.lock.Lock()
+	p.migrationStartedAt = time.Now()
 	p.setupMigrationTimerLocked()
 	p.lock.Unlock()
 
+	prometheus.RecordMigrationStarted()
 	return true
 }
 
@@ -981,10 +1733,23 @@ func (p *ParticipantImpl) SetMigrateState(s types.MigrateState) {
 	p.params.Logger.Debugw("SetMigrateState", "state", s)
 	if s == types.MigrateStateComplete {
 		p.handleMigrateTracks()
+
+		if p.params.Migration {
+			p.lock.Lock()
+			p.migrationCompletedAt = time.Now()
+			migrationDuration := p.migrationCompletedAt.Sub(p.migrationStartedAt)
+			p.lock.Unlock()
+
+			prometheus.RecordMigrationCompleted(migrationDuration)
+		}
 	}
 	p.migrateState.Store(s)
 	p.dirty.Store(true)
 
+	if preState == types.MigrateStateInit {
+		p.flushPendingICECandidates()
+	}
+
 	switch s {
 	case types.MigrateStateSync:
 		p.TransportManager.ProcessPendingPublisherOffer()
@@ -1002,6 +1767,55 @@ func (p *ParticipantImpl) MigrateState() types.MigrateState {
 	return p.migrateState.Load().(types.MigrateState)
 }
 
+// a participant is migrating in till migration completes, and migrating out till the migration timer fires
+func (p *ParticipantImpl) isMigrating() bool {
+	if p.params.Migration && p.MigrateState() != types.MigrateStateComplete {
+		return true
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.migrationTimer != nil
+}
+
+// AbortMigration gives up on a migration in progress and issues a full reconnect,
+// the client connects back with a new session instead of waiting on stalled migration.
+func (p *ParticipantImpl) AbortMigration(reason types.ParticipantCloseReason) error {
+	if p.IsClosed() || !p.isMigrating() {
+		return ErrNoMigrationInProgress
+	}
+
+	p.params.Logger.Infow("aborting migration", "reason", reason, "migrateState", p.MigrateState().String())
+	p.clearMigrationTimer()
+
+	// migrate state is left as is, session is closed by the full reconnect
+	switch reason {
+	case types.ParticipantCloseReasonMigrateCodecMismatch, types.ParticipantCloseReasonMigrateTooManyTracks:
+		// recorded as failed migration by full reconnect
+	default:
+		prometheus.RecordMigrationFailed()
+	}
+	p.IssueFullReconnect(reason)
+	return nil
+}
+
+// ForceCompleteMigration declares a participant migrating in as migrated without waiting for transports,
+// migrated tracks are published as on regular migration completion.
+func (p *ParticipantImpl) ForceCompleteMigration() error {
+	if p.IsClosed() || !p.params.Migration || p.MigrateState() == types.MigrateStateComplete {
+		return ErrNoMigrationInProgress
+	}
+
+	p.params.Logger.Infow("forcing migration complete", "migrateState", p.MigrateState().String())
+	p.clearMigrationTimer()
+	if p.MigrateState() == types.MigrateStateInit {
+		// go through sync so that a publisher offer held back till sync is processed
+		p.SetMigrateState(types.MigrateStateSync)
+	}
+	p.SetMigrateState(types.MigrateStateComplete)
+	return nil
+}
+
 // ICERestart restarts subscriber ICE connections
 func (p *ParticipantImpl) ICERestart(iceConfig *livekit.ICEConfig) {
 	p.clearDisconnectTimer()
@@ -1022,6 +1836,32 @@ func (p *ParticipantImpl) OnICEConfigChanged(f func(participant types.LocalParti
 	p.lock.Unlock()
 }
 
+// OnConnectionQualityChanged is called when connection quality of the participant changes level.
+// Levels are computed in GetConnectionQuality, a new level is reported once it has persisted for
+// a while to avoid flapping. Callback is invoked on a queue of the participant.
+func (p *ParticipantImpl) OnConnectionQualityChanged(callback func(participant types.LocalParticipant, info *livekit.ConnectionQualityInfo)) {
+	p.lock.Lock()
+	p.onConnectionQualityChanged = callback
+	p.lock.Unlock()
+}
+
+func (p *ParticipantImpl) getOnConnectionQualityChanged() func(participant types.LocalParticipant, info *livekit.ConnectionQualityInfo) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.onConnectionQualityChanged
+}
+
+func (p *ParticipantImpl) notifyConnectionQualityChanged(info *livekit.ConnectionQualityInfo) {
+	onConnectionQualityChanged := p.getOnConnectionQualityChanged()
+	if onConnectionQualityChanged == nil {
+		return
+	}
+
+	p.connectionQualityNotifyQueue.Enqueue(func() {
+		onConnectionQualityChanged(p, info)
+	})
+}
+
 func (p *ParticipantImpl) GetConnectionQuality() *livekit.ConnectionQualityInfo {
 	numTracks := 0
 	minQuality := livekit.ConnectionQuality_EXCELLENT
@@ -1080,6 +1920,12 @@ func (p *ParticipantImpl) GetConnectionQuality() *livekit.ConnectionQualityInfo
 		availableTracks[trackID] = true
 	}
 
+	if numTracks == 0 {
+		// without tracks there is no media to score, signaling RTT is the only indicator
+		minQuality = signalingRTTToQuality(p.GetSignalingRTT())
+		minScore = connectionquality.MaxMOSForQuality(minQuality)
+	}
+
 	prometheus.RecordQuality(minQuality, minScore, numUpDrops, numDownDrops)
 
 	// remove unavailable tracks from track quality cache
@@ -1091,21 +1937,160 @@ func (p *ParticipantImpl) GetConnectionQuality() *livekit.ConnectionQualityInfo
 	}
 	p.lock.Unlock()
 
-	if minQuality == livekit.ConnectionQuality_LOST && !p.ProtocolVersion().SupportsConnectionQualityLost() {
+	if minQuality == livekit.ConnectionQuality_LOST &&
+		!p.hasCapability(types.CapabilityConnectionQualityLost, p.ProtocolVersion().SupportsConnectionQualityLost()) {
 		minQuality = livekit.ConnectionQuality_POOR
 	}
 
-	return &livekit.ConnectionQualityInfo{
+	info := &livekit.ConnectionQualityInfo{
 		ParticipantSid: string(p.ID()),
 		Quality:        minQuality,
 		Score:          minScore,
 	}
+
+	// settling is based on time, so that callers other than the room update do not shift it
+	p.connectionQualityLock.Lock()
+	changed := p.connectionQualityChange.update(minQuality, time.Now())
+	p.connectionQualityLock.Unlock()
+	if changed {
+		p.notifyConnectionQualityChanged(proto.Clone(info).(*livekit.ConnectionQualityInfo))
+	}
+
+	return info
+}
+
+// GetBitrateSummary returns current bitrate of published and subscribed tracks.
+// Video uses stream tracker bitrates and the last allocation of each down track,
+// audio is not tracked per layer and uses bitrate of the last connection quality interval.
+func (p *ParticipantImpl) GetBitrateSummary() *types.BitrateSummary {
+	summary := &types.BitrateSummary{
+		Tracks: make(map[livekit.TrackID]types.TrackBitrate),
+	}
+
+	for _, pt := range p.GetPublishedTracks() {
+		tb := types.TrackBitrate{
+			Kind: pt.Kind(),
+		}
+		if tb.Kind == livekit.TrackType_VIDEO {
+			for _, receiver := range pt.Receivers() {
+				tb.Bps += receiverBitrate(receiver)
+			}
+		} else if lmt, ok := pt.(types.LocalMediaTrack); ok {
+			tb.Bps = lmt.GetBitrate()
+		}
+
+		summary.PublishBps += tb.Bps
+		summary.Tracks[pt.ID()] = tb
+	}
+
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		dt := subTrack.DownTrack()
+		if dt == nil {
+			continue
+		}
+
+		tb := types.TrackBitrate{
+			Kind:       subTrack.MediaTrack().Kind(),
+			Subscribed: true,
+		}
+		if tb.Kind == livekit.TrackType_VIDEO {
+			tb.Bps = dt.BandwidthRequested()
+			tb.PauseReason = dt.PauseReason()
+		} else {
+			tb.Bps = dt.GetBitrate()
+		}
+
+		summary.SubscribeBps += tb.Bps
+		summary.Tracks[subTrack.ID()] = tb
+	}
+
+	return summary
+}
+
+// GetSubscriberReportedLoss aggregates fraction lost reported by the subscriber for its down tracks,
+// i. e. loss on the path from this node to the subscriber. Tracks without a recent report are skipped.
+func (p *ParticipantImpl) GetSubscriberReportedLoss() *types.SubscriberLossSummary {
+	var reports []subscriberLossReport
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		dt := subTrack.DownTrack()
+		if dt == nil {
+			continue
+		}
+
+		rr, at := dt.GetLastReceiverReport()
+		reports = append(reports, subscriberLossReport{
+			trackID:      subTrack.ID(),
+			fractionLost: rr.FractionLost,
+			at:           at,
+		})
+	}
+	return summarizeSubscriberLoss(reports, time.Now())
+}
+
+// RequestKeyFrame requests a key frame from publisher of a subscribed video track, for example for an
+// external recorder joining mid stream. Requests are subject to PLI throttling of the publisher,
+// so repeated requests within the throttle window result in one PLI.
+func (p *ParticipantImpl) RequestKeyFrame(trackID livekit.TrackID) error {
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		if subTrack.ID() != trackID {
+			continue
+		}
+
+		if subTrack.MediaTrack().Kind() != livekit.TrackType_VIDEO {
+			return ErrTrackNotVideo
+		}
+
+		dt := subTrack.DownTrack()
+		if dt == nil {
+			return ErrTrackNotSubscribed
+		}
+		return dt.RequestKeyFrame()
+	}
+	return ErrTrackNotSubscribed
+}
+
+// GetVideoAllocation returns a copy of the last stream allocation decision for a subscribed video track,
+// false if the track is not a subscribed video track
+func (p *ParticipantImpl) GetVideoAllocation(trackID livekit.TrackID) (*sfu.VideoAllocation, bool) {
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		if subTrack.ID() != trackID {
+			continue
+		}
+
+		dt := subTrack.DownTrack()
+		if dt == nil || subTrack.MediaTrack().Kind() != livekit.TrackType_VIDEO {
+			return nil, false
+		}
+
+		allocation := dt.LastAllocation()
+		return &allocation, true
+	}
+	return nil, false
 }
 
 func (p *ParticipantImpl) IsPublisher() bool {
 	return p.isPublisher.Load()
 }
 
+// HasActiveMedia returns true if at least one published track is unmuted, not paused and has received a packet recently.
+// Unlike IsPublisher, it is false when all published tracks are muted or have stopped flowing.
+func (p *ParticipantImpl) HasActiveMedia() bool {
+	for _, track := range p.GetPublishedTracks() {
+		if track.IsMuted() || track.IsPaused() {
+			continue
+		}
+
+		lt, ok := track.(types.LocalMediaTrack)
+		if !ok {
+			continue
+		}
+		if lastPacketAt := lt.LastPacketAt(); !lastPacketAt.IsZero() && time.Since(lastPacketAt) < activeMediaTimeout {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *ParticipantImpl) CanPublishSource(source livekit.TrackSource) bool {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
@@ -1168,6 +2153,13 @@ func (p *ParticipantImpl) onTrackUnsubscribed(subTrack types.SubscribedTrack) {
 	p.TransportManager.RemoveSubscribedTrack(subTrack)
 }
 
+// UpdateSubscriptionLimits changes subscription limits of the participant without a reconnect,
+// subscriptions exceeding a reduced limit are removed and the subscriber is notified of the error.
+func (p *ParticipantImpl) UpdateSubscriptionLimits(audio, video int32) {
+	p.SubscriptionManager.UpdateSubscriptionLimits(audio, video)
+	p.dirty.Store(true)
+}
+
 func (p *ParticipantImpl) SubscriptionPermissionUpdate(publisherID livekit.ParticipantID, trackID livekit.TrackID, allowed bool) {
 	p.subLogger.Debugw("sending subscription permission update", "publisherID", publisherID, "trackID", trackID, "allowed", allowed)
 	err := p.writeMessage(&livekit.SignalResponse{
@@ -1187,6 +2179,7 @@ func (p *ParticipantImpl) SubscriptionPermissionUpdate(publisherID livekit.Parti
 func (p *ParticipantImpl) UpdateMediaRTT(rtt uint32) {
 	now := time.Now()
 	p.lock.Lock()
+	p.addRTTSampleLocked(now, rtt)
 	if now.Sub(p.rttUpdatedAt) < rttUpdateInterval || p.lastRTT == rtt {
 		p.lock.Unlock()
 		return
@@ -1201,6 +2194,86 @@ func (p *ParticipantImpl) UpdateMediaRTT(rtt uint32) {
 	}
 }
 
+// records every distinct sample, independent of throttling of RTT updates to transport and tracks
+func (p *ParticipantImpl) addRTTSampleLocked(at time.Time, rtt uint32) {
+	if len(p.rttHistory) != 0 && p.rttHistory[len(p.rttHistory)-1].RTT == rtt {
+		return
+	}
+
+	size := p.params.RTTHistorySize
+	if size <= 0 {
+		size = defaultRTTHistorySize
+	}
+	if len(p.rttHistory) >= size {
+		n := copy(p.rttHistory, p.rttHistory[len(p.rttHistory)-size+1:])
+		p.rttHistory = p.rttHistory[:n]
+	}
+	p.rttHistory = append(p.rttHistory, types.RTTSample{At: at, RTT: rtt})
+}
+
+// GetRTTHistory returns recent distinct media RTT samples, oldest first
+func (p *ParticipantImpl) GetRTTHistory() []types.RTTSample {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return slices.Clone(p.rttHistory)
+}
+
+// GetTemporalLayerDistribution returns, per spatial layer, how packets of a published track
+// are spread across temporal layers, to verify the temporal structure publisher is producing
+func (p *ParticipantImpl) GetTemporalLayerDistribution(trackID livekit.TrackID) ([]buffer.TemporalLayerDistribution, error) {
+	mt, ok := p.GetPublishedTrack(trackID).(*MediaTrack)
+	if !ok {
+		return nil, ErrTrackNotFound
+	}
+
+	return mt.GetTemporalLayerDistribution(), nil
+}
+
+func (p *ParticipantImpl) GetSignalingRTT() uint32 {
+	signalingRTT, _ := p.TransportManager.GetRTT()
+	return signalingRTT
+}
+
+// SetPLIThrottleConfig updates the PLI throttle of published tracks and of tracks published later.
+// Throttle is picked by spatial layer and not by source, so screen share and camera tracks
+// share the intervals. A screen share published without simulcast has a single layer and
+// uses LowQuality, which can be lowered for faster recovery of screen share keyframes.
+func (p *ParticipantImpl) SetPLIThrottleConfig(pliThrottleConfig config.PLIThrottleConfig) {
+	p.pendingTracksLock.Lock()
+	p.pliThrottleConfig = pliThrottleConfig
+	p.pendingTracksLock.Unlock()
+
+	for _, pt := range p.GetPublishedTracks() {
+		pt.(types.LocalMediaTrack).SetPLIThrottleConfig(pliThrottleConfig)
+	}
+}
+
+// SetMaxSpatialLayerForSource caps spatial layer forwarded to subscribers of published video tracks
+// of a source, for example to limit screen share without affecting camera. The cap also applies
+// to tracks of that source published later, buffer.InvalidLayerSpatial removes it.
+// Returns the highest layer which can be forwarded for each track currently published.
+func (p *ParticipantImpl) SetMaxSpatialLayerForSource(source livekit.TrackSource, layer int32) map[livekit.TrackID]int32 {
+	p.pendingTracksLock.Lock()
+	if layer == buffer.InvalidLayerSpatial {
+		delete(p.maxSpatialLayerBySource, source)
+	} else {
+		p.maxSpatialLayerBySource[source] = layer
+	}
+	p.pendingTracksLock.Unlock()
+
+	maxLayers := make(map[livekit.TrackID]int32)
+	for _, pt := range p.GetPublishedTracks() {
+		if pt.Kind() != livekit.TrackType_VIDEO || pt.Source() != source {
+			continue
+		}
+		maxLayers[pt.ID()] = pt.(types.LocalMediaTrack).SetMaxSubscriberSpatialLayer(layer)
+	}
+
+	p.pubLogger.Infow("setting max spatial layer for source", "source", source, "layer", layer, "maxLayers", maxLayers)
+	return maxLayers
+}
+
 // ----------------------------------------------------------
 
 type AnyTransportHandler struct {
@@ -1276,6 +2349,10 @@ func (h PrimaryTransportHandler) OnFullyEstablished() {
 	h.p.onPrimaryTransportFullyEstablished()
 }
 
+func (h PrimaryTransportHandler) OnDataChannelBufferedAmountLow(kind livekit.DataPacket_Kind) {
+	h.p.handleDataChannelBufferedAmountLow(kind)
+}
+
 // ----------------------------------------------------------
 
 func (p *ParticipantImpl) setupTransportManager() error {
@@ -1313,8 +2390,10 @@ func (p *ParticipantImpl) setupTransportManager() error {
 		TCPFallbackRTTThreshold:      p.params.TCPFallbackRTTThreshold,
 		AllowUDPUnstableFallback:     p.params.AllowUDPUnstableFallback,
 		TURNSEnabled:                 p.params.TURNSEnabled,
-		AllowPlayoutDelay:            p.params.PlayoutDelay.GetEnabled(),
+		AllowPlayoutDelay:            p.params.PlayoutDelay.GetEnabled() && p.playoutDelayForKind(livekit.TrackType_VIDEO),
+		AllowPlayoutDelayAudio:       p.params.PlayoutDelay.GetEnabled() && p.playoutDelayForKind(livekit.TrackType_AUDIO),
 		DataChannelMaxBufferedAmount: p.params.DataChannelMaxBufferedAmount,
+		DataChannelLowBufferedAmount: p.params.DataChannelLowBufferedAmount,
 		Logger:                       p.params.Logger.WithComponent(sutils.ComponentTransport),
 		PublisherHandler:             pth,
 		SubscriberHandler:            sth,
@@ -1323,6 +2402,7 @@ func (p *ParticipantImpl) setupTransportManager() error {
 		// we will disable playout delay for Firefox if the user is expecting
 		// the streams to be synced. Firefox doesn't support SyncStreams
 		params.AllowPlayoutDelay = false
+		params.AllowPlayoutDelayAudio = false
 	}
 	tm, err := NewTransportManager(params)
 	if err != nil {
@@ -1351,6 +2431,7 @@ func (p *ParticipantImpl) setupTransportManager() error {
 
 	tm.SetSubscriberAllowPause(p.params.SubscriberAllowPause)
 	p.TransportManager = tm
+	p.SetBandwidthHint(p.params.BandwidthHint)
 	return nil
 }
 
@@ -1373,15 +2454,18 @@ func (p *ParticipantImpl) setupUpTrackManager() {
 
 func (p *ParticipantImpl) setupSubscriptionManager() {
 	p.SubscriptionManager = NewSubscriptionManager(SubscriptionManagerParams{
-		Participant:            p,
-		Logger:                 p.subLogger.WithoutSampler(),
-		TrackResolver:          p.params.TrackResolver,
-		Telemetry:              p.params.Telemetry,
-		OnTrackSubscribed:      p.onTrackSubscribed,
-		OnTrackUnsubscribed:    p.onTrackUnsubscribed,
-		OnSubscriptionError:    p.onSubscriptionError,
-		SubscriptionLimitVideo: p.params.SubscriptionLimitVideo,
-		SubscriptionLimitAudio: p.params.SubscriptionLimitAudio,
+		Participant:                  p,
+		Logger:                       p.subLogger.WithoutSampler(),
+		TrackResolver:                p.params.TrackResolver,
+		Telemetry:                    p.params.Telemetry,
+		OnTrackSubscribed:            p.onTrackSubscribed,
+		OnTrackUnsubscribed:          p.onTrackUnsubscribed,
+		OnSubscriptionError:          p.onSubscriptionError,
+		SubscriptionLimitVideo:       p.params.SubscriptionLimitVideo,
+		SubscriptionLimitAudio:       p.params.SubscriptionLimitAudio,
+		SubscriptionLimitScreenShare: p.params.SubscriptionLimitScreenShare,
+		StartPaused:                  p.params.StartPausedSubscriptions,
+		SettingsLimits:               p.params.SubscribedTrackSettings,
 	})
 }
 
@@ -1390,6 +2474,7 @@ func (p *ParticipantImpl) setupParticipantTrafficLoad() {
 		p.ParticipantTrafficLoad = NewParticipantTrafficLoad(ParticipantTrafficLoadParams{
 			Participant:      p,
 			DataChannelStats: p.dataChannelStats,
+			JobScheduler:     p.params.JobScheduler,
 			Logger:           p.params.Logger,
 		})
 	}
@@ -1442,14 +2527,93 @@ func (p *ParticipantImpl) onSubscriberOffer(offer webrtc.SessionDescription) err
 	})
 }
 
-func (p *ParticipantImpl) removePublishedTrack(track types.MediaTrack) {
+// removePublishedTrack removes a track published by the participant. With a drain duration, subscribers
+// keep receiving the track till the duration elapses and the down tracks are closed with blank frames.
+// The participant is notified of the unpublish once the track is removed.
+func (p *ParticipantImpl) removePublishedTrack(track types.MediaTrack, drain time.Duration) {
+	supportsUnpublish := p.hasCapability(types.CapabilityUnpublish, p.ProtocolVersion().SupportsUnpublish())
+	lmt, isLocal := track.(types.LocalMediaTrack)
+	signalCid := ""
+	if isLocal && supportsUnpublish {
+		// closing the track announces a pending track of the same cid, hold it back till this one is unpublished
+		signalCid = lmt.SignalCid()
+		p.beginTrackUnpublish(signalCid)
+	}
+
+	onRemoved := func() {
+		if supportsUnpublish {
+			p.endTrackUnpublish(signalCid, track.ID())
+		} else {
+			// for older clients that don't support unpublish, mute to avoid them sending data
+			p.sendTrackMuted(track.ID(), true)
+		}
+	}
+
+	if isLocal && drain > 0 {
+		p.drainPublishedTrack(lmt, drain, onRemoved)
+		return
+	}
+
 	p.RemovePublishedTrack(track, false, true)
-	if p.ProtocolVersion().SupportsUnpublish() {
-		p.sendTrackUnpublished(track.ID())
-	} else {
-		// for older clients that don't support unpublish, mute to avoid them sending data
-		p.sendTrackMuted(track.ID(), true)
+	onRemoved()
+}
+
+func (p *ParticipantImpl) drainPublishedTrack(track types.LocalMediaTrack, drain time.Duration, onRemoved func()) {
+	signalCid := track.SignalCid()
+
+	p.lock.Lock()
+	if p.drainingTracks == nil {
+		p.drainingTracks = make(map[string]*drainingTrack)
+	}
+	if dt := p.drainingTracks[signalCid]; dt != nil {
+		p.lock.Unlock()
+		return
+	}
+	p.drainingTracks[signalCid] = &drainingTrack{
+		track:     track,
+		onRemoved: onRemoved,
+		timer: time.AfterFunc(drain, func() {
+			p.lock.Lock()
+			dt := p.drainingTracks[signalCid]
+			if dt == nil || dt.track != track {
+				p.lock.Unlock()
+				return
+			}
+			delete(p.drainingTracks, signalCid)
+			p.lock.Unlock()
+
+			p.pubLogger.Debugw("track drained", "trackID", track.ID())
+			p.RemovePublishedTrack(track, false, true)
+			onRemoved()
+		}),
+	}
+	p.lock.Unlock()
+
+	p.pubLogger.Infow("draining track", "trackID", track.ID(), "drain", drain)
+}
+
+// cancelTrackDrain removes a draining track right away, returns false if the track is not draining
+func (p *ParticipantImpl) cancelTrackDrain(signalCid string) bool {
+	p.lock.Lock()
+	dt := p.drainingTracks[signalCid]
+	delete(p.drainingTracks, signalCid)
+	p.lock.Unlock()
+
+	if dt == nil {
+		// publisher may have stopped sending the track which is draining on its own
+		if mt, ok := p.getPublishedTrackBySignalCid(signalCid).(*MediaTrack); ok && mt.IsDraining() {
+			p.pubLogger.Infow("track drain cancelled", "trackID", mt.ID())
+			mt.Close(false)
+			return true
+		}
+		return false
 	}
+	dt.timer.Stop()
+
+	p.pubLogger.Infow("track drain cancelled", "trackID", dt.track.ID())
+	p.RemovePublishedTrack(dt.track, false, true)
+	dt.onRemoved()
+	return true
 }
 
 // when a new remoteTrack is created, creates a Track and adds it to room
@@ -1474,7 +2638,7 @@ func (p *ParticipantImpl) onMediaTrack(track *webrtc.TrackRemote, rtpReceiver *w
 		p.pubLogger.Warnw("no permission to publish mediaTrack", nil,
 			"source", publishedTrack.Source(),
 		)
-		p.removePublishedTrack(publishedTrack)
+		p.removePublishedTrack(publishedTrack, 0)
 		return
 	}
 
@@ -1505,6 +2669,15 @@ func (p *ParticipantImpl) onDataMessage(kind livekit.DataPacket_Kind, data []byt
 
 	p.dataChannelStats.AddBytes(uint64(len(data)), false)
 
+	if allowed, startedThrottling := p.dataChannelRateLimiter.Allow(kind, len(data)); !allowed {
+		p.dataChannelStats.AddDroppedBytes(uint64(len(data)))
+		// lossy data is expected to be dropped, log reliable only and once per throttling period
+		if startedThrottling && kind == livekit.DataPacket_RELIABLE {
+			p.pubLogger.Warnw("throttling reliable data packets", nil, "limit", p.params.DataChannelRateLimit)
+		}
+		return
+	}
+
 	dp := &livekit.DataPacket{}
 	if err := proto.Unmarshal(data, dp); err != nil {
 		p.pubLogger.Warnw("could not parse data packet", err)
@@ -1554,6 +2727,19 @@ func (p *ParticipantImpl) onDataMessage(kind livekit.DataPacket_Kind, data []byt
 	default:
 		p.pubLogger.Warnw("received unsupported data packet", nil, "payload", payload)
 	}
+	if shouldForward && p.params.DataPacketFilter != nil && !p.params.DataPacketFilter(p, dp) {
+		p.dataChannelStats.AddFilteredBytes(uint64(len(data)))
+		if now := time.Now(); now.UnixNano()-p.dataPacketFilterLoggedAt.Load() >= int64(dataPacketFilterLogInterval) {
+			p.dataPacketFilterLoggedAt.Store(now.UnixNano())
+			p.pubLogger.Debugw(
+				"data packet filtered",
+				"kind", kind,
+				"payload", fmt.Sprintf("%T", dp.Value),
+				"numFiltered", p.dataChannelStats.GetTrafficTotals().RecvFilteredMessages,
+			)
+		}
+		shouldForward = false
+	}
 	if shouldForward {
 		p.lock.RLock()
 		onDataPacket := p.onDataPacket
@@ -1571,13 +2757,51 @@ func (p *ParticipantImpl) onICECandidate(c *webrtc.ICECandidate, target livekit.
 		return nil
 	}
 
-	if target == livekit.SignalTarget_SUBSCRIBER && p.MigrateState() == types.MigrateStateInit {
+	if target == livekit.SignalTarget_SUBSCRIBER && p.maybeBufferICECandidate(c) {
 		return nil
 	}
 
 	return p.sendICECandidate(c, target)
 }
 
+// maybeBufferICECandidate returns true if subscriber candidate is held back as migration is in init state,
+// buffered candidates are sent once migration advances, others are dropped
+func (p *ParticipantImpl) maybeBufferICECandidate(c *webrtc.ICECandidate) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// checked under lock to not buffer after flush
+	if p.MigrateState() != types.MigrateStateInit {
+		return false
+	}
+
+	if len(p.pendingICECandidates) < p.params.MaxPendingICECandidates {
+		p.pendingICECandidates = append(p.pendingICECandidates, c)
+	} else {
+		p.subLogger.Debugw("dropping ICE candidate during migration", "candidate", c.String())
+	}
+	return true
+}
+
+func (p *ParticipantImpl) flushPendingICECandidates() {
+	p.lock.Lock()
+	candidates := p.pendingICECandidates
+	p.pendingICECandidates = nil
+	p.lock.Unlock()
+
+	if len(candidates) == 0 || p.IsDisconnected() || p.IsClosed() {
+		return
+	}
+
+	p.subLogger.Debugw("sending ICE candidates buffered during migration", "count", len(candidates))
+	for _, c := range candidates {
+		if err := p.sendICECandidate(c, livekit.SignalTarget_SUBSCRIBER); err != nil {
+			p.subLogger.Warnw("could not send buffered ICE candidate", err)
+			return
+		}
+	}
+}
+
 func (p *ParticipantImpl) onPublisherInitialConnected() {
 	p.SetMigrateState(types.MigrateStateComplete)
 
@@ -1589,7 +2813,9 @@ func (p *ParticipantImpl) onPublisherInitialConnected() {
 }
 
 func (p *ParticipantImpl) onSubscriberInitialConnected() {
-	go p.subscriberRTCPWorker()
+	if !p.params.DisableSenderReports {
+		go p.subscriberRTCPWorker()
+	}
 
 	p.setDowntracksConnected()
 }
@@ -1606,6 +2832,8 @@ func (p *ParticipantImpl) onPrimaryTransportFullyEstablished() {
 	if !p.sessionStartRecorded.Swap(true) {
 		prometheus.RecordSessionStartTime(int(p.ProtocolVersion()), time.Since(p.params.SessionStartTime))
 	}
+	// data channels are open, nothing else is sent before participant is active
+	p.sendPendingReliableData()
 	p.updateState(livekit.ParticipantInfo_ACTIVE)
 }
 
@@ -1657,7 +2885,7 @@ func (p *ParticipantImpl) subscriberRTCPWorker() {
 
 		subscribedTracks := p.SubscriptionManager.GetSubscribedTracks()
 
-		// send in batches of sdBatchSize
+		// send in batches of SenderReportBatchSize
 		batchSize := 0
 		var pkts []rtcp.Packet
 		var sd []rtcp.SourceDescriptionChunk
@@ -1675,16 +2903,18 @@ func (p *ParticipantImpl) subscriberRTCPWorker() {
 				numItems += len(chunk.Items)
 			}
 			batchSize = batchSize + 1 + numItems
-			if batchSize >= sdBatchSize {
+			if batchSize >= p.params.SenderReportBatchSize {
 				if len(sd) != 0 {
 					pkts = append(pkts, &rtcp.SourceDescription{Chunks: sd})
 				}
-				if err := p.TransportManager.WriteSubscriberRTCP(pkts); err != nil {
+				err := p.TransportManager.WriteSubscriberRTCP(pkts)
+				if err != nil {
 					if IsEOF(err) {
 						return
 					}
 					p.subLogger.Errorw("could not send down track reports", err)
 				}
+				p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, err)
 
 				pkts = pkts[:0]
 				sd = sd[:0]
@@ -1696,16 +2926,37 @@ func (p *ParticipantImpl) subscriberRTCPWorker() {
 			if len(sd) != 0 {
 				pkts = append(pkts, &rtcp.SourceDescription{Chunks: sd})
 			}
-			if err := p.TransportManager.WriteSubscriberRTCP(pkts); err != nil {
+			err := p.TransportManager.WriteSubscriberRTCP(pkts)
+			if err != nil {
 				if IsEOF(err) {
 					return
 				}
 				p.subLogger.Errorw("could not send down track reports", err)
 			}
+			p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, err)
 		}
 
-		time.Sleep(3 * time.Second)
+		time.Sleep(p.getSubscriberRTCPInterval())
+	}
+}
+
+// getSubscriberRTCPInterval returns the interval between sender reports of subscribed tracks,
+// backing off exponentially while writes on subscriber transport keep failing.
+func (p *ParticipantImpl) getSubscriberRTCPInterval() time.Duration {
+	interval := p.params.SenderReportInterval
+	conf := p.params.RTCPWriteFailureConfig
+	if conf.SubscriberBackoffThreshold <= 0 || conf.SubscriberMaxBackoff <= interval {
+		return interval
+	}
+
+	numBackoffs := p.subRTCPWriteFailures.getNumConsecutive() - conf.SubscriberBackoffThreshold + 1
+	for i := 0; i < numBackoffs && interval < conf.SubscriberMaxBackoff; i++ {
+		interval *= 2
+	}
+	if interval > conf.SubscriberMaxBackoff {
+		interval = conf.SubscriberMaxBackoff
 	}
+	return interval
 }
 
 func (p *ParticipantImpl) onStreamStateChange(update *streamallocator.StreamStateUpdate) error {
@@ -1733,6 +2984,14 @@ func (p *ParticipantImpl) onStreamStateChange(update *streamallocator.StreamStat
 	})
 }
 
+func (p *ParticipantImpl) onPublishedTrackAvailableLayersChanged(trackID livekit.TrackID, availableLayers []int32) {
+	p.pubLogger.Debugw("available layers changed", "trackID", trackID, "availableLayers", availableLayers)
+
+	if onAvailableLayersChanged := p.getOnAvailableLayersChanged(); onAvailableLayersChanged != nil {
+		onAvailableLayersChanged(p, trackID, availableLayers)
+	}
+}
+
 func (p *ParticipantImpl) onSubscribedMaxQualityChange(
 	trackID livekit.TrackID,
 	trackInfo *livekit.TrackInfo,
@@ -1793,7 +3052,10 @@ func (p *ParticipantImpl) onSubscribedMaxQualityChange(
 	})
 }
 
-func (p *ParticipantImpl) addPendingTrackLocked(req *livekit.AddTrackRequest) *livekit.TrackInfo {
+// addPendingTrackLocked returns track info to be sent to client, nil when the request is queued or rejected.
+// rejected is true when the request is rejected by publish limits or duplicate policy,
+// rejectedRepeatedly is true when the participant keeps exceeding publish limits.
+func (p *ParticipantImpl) addPendingTrackLocked(req *livekit.AddTrackRequest) (ti *livekit.TrackInfo, rejected bool, rejectedRepeatedly bool) {
 	p.pendingTracksLock.Lock()
 	defer p.pendingTracksLock.Unlock()
 
@@ -1801,15 +3063,47 @@ func (p *ParticipantImpl) addPendingTrackLocked(req *livekit.AddTrackRequest) *l
 		track := p.GetPublishedTrack(livekit.TrackID(req.Sid))
 		if track == nil {
 			p.pubLogger.Infow("could not find existing track for multi-codec simulcast", "trackID", req.Sid)
-			return nil
+			return nil, false, false
 		}
 
 		track.(*MediaTrack).UpdateCodecCid(req.SimulcastCodecs)
-		ti := track.ToProto()
-		return ti
+		return track.ToProto(), false, false
+	}
+
+	now := time.Now()
+	p.expirePendingTracksLocked(now)
+
+	if duplicate := p.getDuplicateTrackLocked(req); duplicate != nil {
+		switch p.params.PublishLimit.DuplicatePolicy {
+		case config.DuplicatePublishPolicyReject:
+			p.pubLogger.Warnw(
+				"publish request rejected, duplicate of existing track", nil,
+				"cid", req.Cid,
+				"source", req.Source,
+				"name", req.Name,
+				"trackID", duplicate.Sid,
+			)
+			return nil, true, false
+
+		case config.DuplicatePublishPolicyMerge:
+			p.pubLogger.Infow(
+				"publish request merged into existing track",
+				"cid", req.Cid,
+				"source", req.Source,
+				"name", req.Name,
+				"trackID", duplicate.Sid,
+			)
+			return duplicate, false, false
+		}
+	}
+
+	numPending, numPendingSource := p.numPendingTracksLocked(req.Source)
+	if err := p.publishLimiter.allow(req.Source, numPending, numPendingSource, now); err != nil {
+		p.pubLogger.Warnw("publish request rejected", err, "cid", req.Cid, "source", req.Source)
+		return nil, true, p.publishLimiter.shouldClose()
 	}
 
-	ti := &livekit.TrackInfo{
+	ti = &livekit.TrackInfo{
 		Type:       req.Type,
 		Name:       req.Name,
 		Width:      req.Width,
@@ -1857,6 +3151,7 @@ func (p *ParticipantImpl) addPendingTrackLocked(req *livekit.AddTrackRequest) *l
 						"altCodec", altCodec,
 						"trackID", ti.Sid,
 					)
+					p.recordCodecFallbackLocked(livekit.TrackID(ti.Sid), mime, altCodec)
 					// select an alternative MIME type that's generally supported
 					mime = altCodec
 				}
@@ -1888,31 +3183,136 @@ func (p *ParticipantImpl) addPendingTrackLocked(req *livekit.AddTrackRequest) *l
 	}
 	if p.getPublishedTrackBySignalCid(req.Cid) != nil || p.getPublishedTrackBySdpCid(req.Cid) != nil || p.pendingTracks[req.Cid] != nil {
 		if p.pendingTracks[req.Cid] == nil {
-			p.pendingTracks[req.Cid] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}}
+			p.pendingTracks[req.Cid] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}, requestedAt: now}
 		} else {
 			p.pendingTracks[req.Cid].trackInfos = append(p.pendingTracks[req.Cid].trackInfos, ti)
+			p.pendingTracks[req.Cid].requestedAt = now
 		}
 		p.pubLogger.Infow("pending track queued", "trackID", ti.Sid, "track", logger.Proto(ti), "request", logger.Proto(req))
-		return nil
+		return nil, false, false
 	}
 
-	p.pendingTracks[req.Cid] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}}
+	p.pendingTracks[req.Cid] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}, requestedAt: now}
 	p.pubLogger.Debugw("pending track added", "trackID", ti.Sid, "track", logger.Proto(ti), "request", logger.Proto(req))
-	return ti
+	return ti, false, false
 }
 
-func (p *ParticipantImpl) GetPendingTrack(trackID livekit.TrackID) *livekit.TrackInfo {
-	p.pendingTracksLock.RLock()
-	defer p.pendingTracksLock.RUnlock()
+// returns info of a published or pending track with the same source and name as the request,
+// nil when duplicates are allowed. Requests for the same cid and unnamed tracks are not considered duplicates.
+//
+// should be called with pendingTracksLock held
+func (p *ParticipantImpl) getDuplicateTrackLocked(req *livekit.AddTrackRequest) *livekit.TrackInfo {
+	switch p.params.PublishLimit.DuplicatePolicy {
+	case config.DuplicatePublishPolicyReject, config.DuplicatePublishPolicyMerge:
+	default:
+		return nil
+	}
 
-	for _, t := range p.pendingTracks {
-		if livekit.TrackID(t.trackInfos[0].Sid) == trackID {
-			return t.trackInfos[0]
+	if req.Name == "" {
+		return nil
+	}
+
+	for _, track := range p.GetPublishedTracks() {
+		lmt := track.(types.LocalMediaTrack)
+		if lmt.SignalCid() == req.Cid || lmt.HasSdpCid(req.Cid) {
+			continue
+		}
+		if ti := track.ToProto(); ti.Source == req.Source && ti.Name == req.Name {
+			return ti
 		}
 	}
 
-	return nil
-}
+	for cid, pti := range p.pendingTracks {
+		if cid == req.Cid {
+			continue
+		}
+		if ti := pti.trackInfos[0]; ti.Source == req.Source && ti.Name == req.Name {
+			return proto.Clone(ti).(*livekit.TrackInfo)
+		}
+	}
+
+	return nil
+}
+
+// should be called with pendingTracksLock held
+func (p *ParticipantImpl) numPendingTracksLocked(source livekit.TrackSource) (numPending int, numPendingSource int) {
+	for _, pti := range p.pendingTracks {
+		for _, ti := range pti.trackInfos {
+			numPending++
+			if ti.Source == source {
+				numPendingSource++
+			}
+		}
+	}
+	return
+}
+
+// drops pending tracks which did not receive media in time, so that a leaked cid does not hold up
+// publishing of the source. Migrated and muted tracks, and those queued behind a published track are kept.
+// A track published muted may not send media till it is unmuted.
+//
+// should be called with pendingTracksLock held
+func (p *ParticipantImpl) expirePendingTracksLocked(now time.Time) {
+	timeout := p.params.PublishLimit.PendingTrackTimeout
+	if timeout <= 0 {
+		return
+	}
+
+	for cid, pti := range p.pendingTracks {
+		if pti.migrated || pti.trackInfos[0].Muted || now.Sub(pti.requestedAt) < timeout {
+			continue
+		}
+		if p.getPublishedTrackBySignalCid(cid) != nil || p.getPublishedTrackBySdpCid(cid) != nil {
+			continue
+		}
+
+		p.pubLogger.Infow("pending track expired", "cid", cid, "trackID", pti.trackInfos[0].Sid, "age", now.Sub(pti.requestedAt))
+		delete(p.pendingTracks, cid)
+		for _, ti := range pti.trackInfos {
+			delete(p.codecFallbacks, livekit.TrackID(ti.Sid))
+		}
+	}
+}
+
+// should be called with pendingTracksLock held
+func (p *ParticipantImpl) recordCodecFallbackLocked(trackID livekit.TrackID, requested string, actual string) {
+	fallbacks := p.codecFallbacks[trackID]
+	if fallbacks == nil {
+		fallbacks = make(map[string]string)
+		p.codecFallbacks[trackID] = fallbacks
+	}
+	fallbacks[requested] = actual
+}
+
+// GetCodecFallbacks returns, per track, the codecs requested by the client that were
+// not enabled and the alternative codec substituted for each of them.
+func (p *ParticipantImpl) GetCodecFallbacks() map[livekit.TrackID]map[string]string {
+	p.pendingTracksLock.RLock()
+	defer p.pendingTracksLock.RUnlock()
+
+	codecFallbacks := make(map[livekit.TrackID]map[string]string, len(p.codecFallbacks))
+	for trackID, fallbacks := range p.codecFallbacks {
+		cloned := make(map[string]string, len(fallbacks))
+		for requested, actual := range fallbacks {
+			cloned[requested] = actual
+		}
+		codecFallbacks[trackID] = cloned
+	}
+	return codecFallbacks
+}
+
+func (p *ParticipantImpl) GetPendingTrack(trackID livekit.TrackID) *livekit.TrackInfo {
+	p.pendingTracksLock.RLock()
+	defer p.pendingTracksLock.RUnlock()
+
+	for _, t := range p.pendingTracks {
+		if livekit.TrackID(t.trackInfos[0].Sid) == trackID {
+			return t.trackInfos[0]
+		}
+	}
+
+	return nil
+}
 
 func (p *ParticipantImpl) HasConnected() bool {
 	return p.TransportManager.HasSubscriberEverConnected() || p.TransportManager.HasPublisherEverConnected()
@@ -1930,13 +3330,198 @@ func (p *ParticipantImpl) sendTrackPublished(cid string, ti *livekit.TrackInfo)
 	})
 }
 
-func (p *ParticipantImpl) SetTrackMuted(trackID livekit.TrackID, muted bool, fromAdmin bool) *livekit.TrackInfo {
+// sendTrackPublishedOrdered sends TrackPublished unless a track with the same signal cid is being unpublished,
+// in which case it is sent after TrackUnpublished of that track
+func (p *ParticipantImpl) sendTrackPublishedOrdered(cid string, ti *livekit.TrackInfo) {
+	p.unpublishingLock.Lock()
+	defer p.unpublishingLock.Unlock()
+
+	if uc := p.unpublishingCids[cid]; uc != nil {
+		p.pubLogger.Debugw("holding back track published till previous track is unpublished", "cid", cid, "trackID", ti.Sid)
+		uc.published = append(uc.published, ti)
+		return
+	}
+
+	p.sendTrackPublished(cid, ti)
+}
+
+func (p *ParticipantImpl) beginTrackUnpublish(cid string) {
+	p.unpublishingLock.Lock()
+	defer p.unpublishingLock.Unlock()
+
+	if p.unpublishingCids == nil {
+		p.unpublishingCids = make(map[string]*unpublishingCid)
+	}
+	uc := p.unpublishingCids[cid]
+	if uc == nil {
+		uc = &unpublishingCid{}
+		p.unpublishingCids[cid] = uc
+	}
+	uc.numTracks++
+}
+
+// endTrackUnpublish sends TrackUnpublished followed by TrackPublished held back for the cid
+func (p *ParticipantImpl) endTrackUnpublish(cid string, trackID livekit.TrackID) {
+	p.unpublishingLock.Lock()
+	defer p.unpublishingLock.Unlock()
+
+	p.sendTrackUnpublished(trackID)
+
+	uc := p.unpublishingCids[cid]
+	if uc == nil {
+		return
+	}
+	if uc.numTracks--; uc.numTracks > 0 {
+		return
+	}
+	delete(p.unpublishingCids, cid)
+
+	for _, ti := range uc.published {
+		p.sendTrackPublished(cid, ti)
+	}
+}
+
+func (p *ParticipantImpl) SetTrackMuted(trackID livekit.TrackID, muted bool, adminOpts *types.AdminActionOptions) *livekit.TrackInfo {
 	// when request is coming from admin, send message to current participant
-	if fromAdmin {
+	if adminOpts != nil {
 		p.sendTrackMuted(trackID, muted)
 	}
 
-	return p.setTrackMuted(trackID, muted)
+	trackInfo := p.setTrackMuted(trackID, muted)
+	if trackInfo != nil {
+		action := types.AdminActionMuteTrack
+		if !muted {
+			action = types.AdminActionUnmuteTrack
+		}
+		p.recordAdminAction(adminOpts, action, trackID)
+	}
+	return trackInfo
+}
+
+// SetAllTracksMuted applies mute state to all published and pending tracks of the participant
+// and notifies a single participant update. Returns info of tracks whose mute state changed.
+func (p *ParticipantImpl) SetAllTracksMuted(muted bool, adminOpts *types.AdminActionOptions) []*livekit.TrackInfo {
+	// pending tracks are muted first, a track moving from pending to published after that
+	// is published with the mute of its pending info, one which moved before is muted below
+	var changedPendingTracks []*livekit.TrackInfo
+	p.pendingTracksLock.Lock()
+	for _, pti := range p.pendingTracks {
+		for _, ti := range pti.trackInfos {
+			if ti.Muted == muted {
+				continue
+			}
+
+			ti.Muted = muted
+			changedPendingTracks = append(changedPendingTracks, ti)
+		}
+	}
+	p.pendingTracksLock.Unlock()
+
+	changedTracks := p.UpTrackManager.SetAllPublishedTracksMuted(muted)
+
+	trackInfos := make([]*livekit.TrackInfo, 0, len(changedTracks)+len(changedPendingTracks))
+	seen := make(map[livekit.TrackID]bool, len(changedTracks)+len(changedPendingTracks))
+	for _, track := range changedTracks {
+		trackInfos = append(trackInfos, track.ToProto())
+		seen[track.ID()] = true
+	}
+	for _, ti := range changedPendingTracks {
+		if !seen[livekit.TrackID(ti.Sid)] {
+			trackInfos = append(trackInfos, ti)
+			seen[livekit.TrackID(ti.Sid)] = true
+		}
+	}
+
+	if len(trackInfos) == 0 {
+		return nil
+	}
+
+	p.dirty.Store(true)
+	p.pubLogger.Infow("all tracks mute changed", "muted", muted, "numTracks", len(trackInfos))
+
+	action := types.AdminActionMuteTrack
+	if !muted {
+		action = types.AdminActionUnmuteTrack
+	}
+	onTrackMuteChanged := p.getOnTrackMuteChanged()
+	for _, ti := range trackInfos {
+		trackID := livekit.TrackID(ti.Sid)
+		if p.supervisor != nil {
+			p.supervisor.SetPublicationMute(trackID, muted)
+		}
+
+		// MuteTrackRequest carries a single track and clients reconcile mute in their own participant info
+		// back to the server, so the client is asked to mute each affected track in a message of its own
+		if adminOpts != nil {
+			p.sendTrackMuted(trackID, muted)
+		}
+		p.recordAdminAction(adminOpts, action, trackID)
+
+		if onTrackMuteChanged != nil {
+			// nil track for pending tracks
+			onTrackMuteChanged(p, p.UpTrackManager.GetPublishedTrack(trackID), muted)
+		}
+
+		if muted {
+			p.params.Telemetry.TrackMuted(context.Background(), p.ID(), ti)
+		} else {
+			p.params.Telemetry.TrackUnmuted(context.Background(), p.ID(), ti)
+		}
+	}
+
+	p.lock.RLock()
+	onParticipantUpdate := p.onParticipantUpdate
+	p.lock.RUnlock()
+	if onParticipantUpdate != nil {
+		onParticipantUpdate(p)
+	}
+
+	return trackInfos
+}
+
+// PauseTrackForwarding stops forwarding a published track to all subscribers without unpublishing it.
+// Subscribers are notified of a paused stream state for video.
+func (p *ParticipantImpl) PauseTrackForwarding(trackID livekit.TrackID) error {
+	return p.setTrackModerated(trackID, true)
+}
+
+func (p *ParticipantImpl) ResumeTrackForwarding(trackID livekit.TrackID) error {
+	return p.setTrackModerated(trackID, false)
+}
+
+// SetTrackPaused stops forwarding a published track while it stays published and unmuted,
+// subscribers see the stream as paused. Resuming requests a key frame from the publisher.
+func (p *ParticipantImpl) SetTrackPaused(trackID livekit.TrackID, paused bool) error {
+	if p.UpTrackManager.SetPublishedTrackPaused(trackID, paused) == nil {
+		return fmt.Errorf("%w: %s", ErrTrackNotFound, trackID)
+	}
+	return nil
+}
+
+// HandleTrackPauseRequest applies a pause request of the publisher,
+// it is ignored for clients which do not support it.
+func (p *ParticipantImpl) HandleTrackPauseRequest(trackID livekit.TrackID, paused bool) {
+	if !p.ProtocolVersion().SupportsTrackPause() {
+		p.pubLogger.Debugw("ignoring track pause request, not supported by client", "protocolVersion", p.ProtocolVersion())
+		return
+	}
+
+	if err := p.SetTrackPaused(trackID, paused); err != nil {
+		p.pubLogger.Debugw("could not set track paused", "error", err, "trackID", trackID, "paused", paused)
+	}
+}
+
+func (p *ParticipantImpl) setTrackModerated(trackID livekit.TrackID, moderated bool) error {
+	track, ok := p.GetPublishedTrack(trackID).(types.LocalMediaTrack)
+	if !ok {
+		return ErrTrackNotFound
+	}
+
+	if track.IsModerated() != moderated {
+		p.pubLogger.Infow("setting track forwarding", "trackID", trackID, "paused", moderated)
+		track.SetModerated(moderated)
+	}
+	return nil
 }
 
 func (p *ParticipantImpl) setTrackMuted(trackID livekit.TrackID, muted bool) *livekit.TrackInfo {
@@ -1945,7 +3530,7 @@ func (p *ParticipantImpl) setTrackMuted(trackID livekit.TrackID, muted bool) *li
 		p.supervisor.SetPublicationMute(trackID, muted)
 	}
 
-	track := p.UpTrackManager.SetPublishedTrackMuted(trackID, muted)
+	track, changed := p.UpTrackManager.SetPublishedTrackMuted(trackID, muted)
 	var trackInfo *livekit.TrackInfo
 	if track != nil {
 		trackInfo = track.ToProto()
@@ -1956,6 +3541,9 @@ func (p *ParticipantImpl) setTrackMuted(trackID livekit.TrackID, muted bool) *li
 	for _, pti := range p.pendingTracks {
 		for _, ti := range pti.trackInfos {
 			if livekit.TrackID(ti.Sid) == trackID {
+				if ti.Muted != muted {
+					changed = true
+				}
 				ti.Muted = muted
 				isPending = true
 				trackInfo = ti
@@ -1964,6 +3552,12 @@ func (p *ParticipantImpl) setTrackMuted(trackID livekit.TrackID, muted bool) *li
 	}
 	p.pendingTracksLock.RUnlock()
 
+	if changed {
+		if onTrackMuteChanged := p.getOnTrackMuteChanged(); onTrackMuteChanged != nil {
+			onTrackMuteChanged(p, track, muted)
+		}
+	}
+
 	if trackInfo != nil {
 		if muted {
 			p.params.Telemetry.TrackMuted(context.Background(), p.ID(), trackInfo)
@@ -2058,6 +3652,15 @@ func (p *ParticipantImpl) mediaTrackReceived(track *webrtc.TrackRemote, rtpRecei
 
 func (p *ParticipantImpl) addMigratedTrack(cid string, ti *livekit.TrackInfo) *MediaTrack {
 	p.pubLogger.Infow("add migrated track", "cid", cid, "trackID", ti.Sid, "track", logger.Proto(ti))
+	if ti.Mid == "" {
+		// older nodes do not populate mid, find it from session descriptions
+		if !p.backfillMigratedTrackMid(cid, ti) {
+			p.pubLogger.Warnw("could not backfill mid of migrated track", nil, "cid", cid, "trackID", ti.Sid)
+			return nil
+		}
+	} else {
+		p.pubLogger.Debugw("mid of migrated track from track info", "cid", cid, "trackID", ti.Sid, "mid", ti.Mid)
+	}
 	rtpReceiver := p.TransportManager.GetPublisherRTPReceiver(ti.Mid)
 	if rtpReceiver == nil {
 		p.pubLogger.Errorw("could not find receiver for migrated track", nil, "trackID", ti.Sid, "mid", ti.Mid)
@@ -2109,6 +3712,37 @@ func (p *ParticipantImpl) addMigratedTrack(cid string, ti *livekit.TrackInfo) *M
 	return mt
 }
 
+// should be called with pendingTracksLock held
+func (p *ParticipantImpl) backfillMigratedTrackMid(cid string, ti *livekit.TrackInfo) bool {
+	trackIDs := []string{cid}
+	var ssrcs []uint32
+	for _, layer := range ti.Layers {
+		ssrcs = append(ssrcs, layer.Ssrc)
+	}
+	for _, c := range ti.Codecs {
+		trackIDs = append(trackIDs, c.Cid)
+		for _, layer := range c.Layers {
+			ssrcs = append(ssrcs, layer.Ssrc)
+		}
+	}
+
+	// publisher offer re-sent by the client on migration carries the tracks being published,
+	// previous session descriptions of migration are those of the subscriber transport
+	mid, err := midForTrackFromSDP(p.TransportManager.LastPublisherOffer(), trackIDs, ssrcs)
+	if err != nil {
+		p.pubLogger.Warnw("could not parse publisher offer for mid", err)
+		return false
+	}
+	if mid == "" {
+		return false
+	}
+
+	p.pubLogger.Infow("mid of migrated track backfilled from publisher offer", "cid", cid, "trackID", ti.Sid, "mid", mid)
+	ti.Mid = mid
+	return true
+}
+
+// should be called with pendingTracksLock held
 func (p *ParticipantImpl) addMediaTrack(signalCid string, sdpCid string, ti *livekit.TrackInfo) *MediaTrack {
 	mt := NewMediaTrack(MediaTrackParams{
 		SignalCid:           signalCid,
@@ -2118,17 +3752,26 @@ func (p *ParticipantImpl) addMediaTrack(signalCid string, sdpCid string, ti *liv
 		ParticipantVersion:  p.version.Load(),
 		BufferFactory:       p.params.Config.BufferFactory,
 		ReceiverConfig:      p.params.Config.Receiver,
+		ServerClock:         p.params.Config.ServerClock,
 		AudioConfig:         p.params.AudioConfig,
 		VideoConfig:         p.params.VideoConfig,
 		Telemetry:           p.params.Telemetry,
 		Logger:              LoggerWithTrack(p.pubLogger, livekit.TrackID(ti.Sid), false),
 		SubscriberConfig:    p.params.Config.Subscriber,
-		PLIThrottleConfig:   p.params.PLIThrottleConfig,
+		PLIThrottleConfig:   p.pliThrottleConfig,
 		SimTracks:           p.params.SimTracks,
 		OnRTCP:              p.postRtcp,
+		OnRTT:               p.UpdateMediaRTT,
+		IsTransportHealthy:  p.IsPublisherConnected,
+		MaxSubscribers:      p.params.MaxSubscribersPerTrack,
+		DrainDuration:       p.params.UnpublishDrainDuration,
 	}, ti)
 
 	mt.OnSubscribedMaxQualityChange(p.onSubscribedMaxQualityChange)
+	mt.OnAvailableLayersChanged(p.onPublishedTrackAvailableLayersChanged)
+	if layer, ok := p.maxSpatialLayerBySource[ti.Source]; ok && ti.Type == livekit.TrackType_VIDEO {
+		mt.SetMaxSubscriberSpatialLayer(layer)
+	}
 
 	// add to published and clean up pending
 	if p.supervisor != nil {
@@ -2170,9 +3813,11 @@ func (p *ParticipantImpl) addMediaTrack(signalCid string, sdpCid string, ti *liv
 		// re-use Track sid
 		p.pendingTracksLock.Lock()
 		if pti := p.pendingTracks[signalCid]; pti != nil {
-			p.sendTrackPublished(signalCid, pti.trackInfos[0])
+			pti.requestedAt = time.Now()
+			p.sendTrackPublishedOrdered(signalCid, pti.trackInfos[0])
 		} else {
 			p.unpublishedTracks = append(p.unpublishedTracks, ti)
+			delete(p.codecFallbacks, trackID)
 		}
 		p.pendingTracksLock.Unlock()
 
@@ -2306,6 +3951,11 @@ func (p *ParticipantImpl) setStableTrackID(cid string, info *livekit.TrackInfo)
 		}
 	}
 
+	// check tracks published by previous session of this identity
+	if trackID == "" {
+		trackID = string(p.takePreviousTrackSIDLocked(info))
+	}
+
 	// otherwise generate
 	if trackID == "" {
 		trackPrefix := utils.TrackPrefix
@@ -2329,6 +3979,32 @@ func (p *ParticipantImpl) setStableTrackID(cid string, info *livekit.TrackInfo)
 	info.Sid = trackID
 }
 
+// should be called with pendingTracksLock held
+func (p *ParticipantImpl) takePreviousTrackSIDLocked(info *livekit.TrackInfo) livekit.TrackID {
+	key := TrackSIDKeyFromInfo(info)
+	trackID, ok := p.previousTrackSIDs[key]
+	if !ok {
+		return ""
+	}
+	// reused only once
+	delete(p.previousTrackSIDs, key)
+
+	// SID could have been claimed by another participant in the mean time
+	if p.params.TrackResolver != nil {
+		if res := p.params.TrackResolver(p.Identity(), trackID); res.Track != nil {
+			p.pubLogger.Infow(
+				"not reusing previous track SID, already published",
+				"trackID", trackID,
+				"publisherID", res.PublisherID,
+			)
+			return ""
+		}
+	}
+
+	p.pubLogger.Debugw("reusing previous track SID", "trackID", trackID, "trackInfo", logger.Proto(info))
+	return trackID
+}
+
 func (p *ParticipantImpl) getPublishedTrackBySignalCid(clientId string) types.MediaTrack {
 	for _, publishedTrack := range p.GetPublishedTracks() {
 		if publishedTrack.(types.LocalMediaTrack).SignalCid() == clientId {
@@ -2372,11 +4048,76 @@ func (p *ParticipantImpl) DebugInfo() map[string]interface{} {
 	p.pendingTracksLock.RUnlock()
 	info["PendingTracks"] = pendingTrackInfo
 
+	p.lock.RLock()
+	if !p.migrationStartedAt.IsZero() {
+		migrationInfo := map[string]interface{}{
+			"StartedAt": p.migrationStartedAt.String(),
+		}
+		if !p.migrationCompletedAt.IsZero() {
+			migrationInfo["CompletedAt"] = p.migrationCompletedAt.String()
+			migrationInfo["Duration"] = p.migrationCompletedAt.Sub(p.migrationStartedAt).String()
+		}
+		info["Migration"] = migrationInfo
+	}
+	p.lock.RUnlock()
+
 	info["UpTrackManager"] = p.UpTrackManager.DebugInfo()
 
+	publishedTrackStats := make(map[livekit.TrackID]interface{})
+	for _, pt := range p.GetPublishedTracks() {
+		if lmt, ok := pt.(types.LocalMediaTrack); ok {
+			publishedTrackStats[pt.ID()] = rtpStatsDebugInfo(lmt.GetTrackStats())
+		}
+	}
+	info["PublishedTrackStats"] = publishedTrackStats
+
+	subscribedTrackInfo := make(map[livekit.TrackID]interface{})
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		if dt := subTrack.DownTrack(); dt != nil {
+			dtInfo := dt.DebugInfo()
+			dtInfo["Stats"] = rtpStatsDebugInfo(dt.GetTrackStats())
+			subscribedTrackInfo[subTrack.ID()] = dtInfo
+		}
+	}
+	info["SubscribedTracks"] = subscribedTrackInfo
+
+	info["DTLSState"] = map[string]interface{}{
+		"Publisher":  p.TransportManager.GetDTLSState(livekit.SignalTarget_PUBLISHER).String(),
+		"Subscriber": p.TransportManager.GetDTLSState(livekit.SignalTarget_SUBSCRIBER).String(),
+	}
+
+	info["PendingNegotiations"] = map[string]interface{}{
+		"Publisher":  pendingNegotiationsDebugInfo(p.TransportManager.GetPendingNegotiations(livekit.SignalTarget_PUBLISHER)),
+		"Subscriber": pendingNegotiationsDebugInfo(p.TransportManager.GetPendingNegotiations(livekit.SignalTarget_SUBSCRIBER)),
+	}
+
+	info["RTCPWriteFailureStreak"] = map[string]interface{}{
+		"Publisher":  p.pubRTCPWriteFailures.get(),
+		"Subscriber": p.subRTCPWriteFailures.get(),
+	}
+	info["SubscriberRTCPInterval"] = p.getSubscriberRTCPInterval().String()
+
+	info["ReconnectCount"] = p.GetReconnectCount()
+	info["AdminActions"] = p.adminAuditLogInfo()
+
 	return info
 }
 
+// rtpStatsDebugInfo returns jitter and loss, nil when there are no stats, for example a track muted before any media
+func rtpStatsDebugInfo(stats *livekit.RTPStats) map[string]interface{} {
+	if stats == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"Packets":              stats.Packets,
+		"PacketsLost":          stats.PacketsLost,
+		"PacketLossPercentage": stats.PacketLossPercentage,
+		"JitterCurrent":        stats.JitterCurrent,
+		"JitterMax":            stats.JitterMax,
+	}
+}
+
 func (p *ParticipantImpl) postRtcp(pkts []rtcp.Packet) {
 	p.lock.RLock()
 	migrationTimer := p.migrationTimer
@@ -2398,12 +4139,44 @@ func (p *ParticipantImpl) postRtcp(pkts []rtcp.Packet) {
 	}
 
 	p.pubRTCPQueue.Enqueue(func(op postRtcpOp) {
-		if err := op.TransportManager.WritePublisherRTCP(op.pkts); err != nil && !IsEOF(err) {
+		err := op.TransportManager.WritePublisherRTCP(op.pkts)
+		if IsEOF(err) {
+			return
+		}
+		if err != nil {
 			op.pubLogger.Errorw("could not write RTCP to participant", err)
 		}
+		op.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, err)
 	}, postRtcpOp{p, pkts})
 }
 
+func (p *ParticipantImpl) handleRTCPWriteResult(target livekit.SignalTarget, err error) {
+	streak := &p.pubRTCPWriteFailures
+	if target == livekit.SignalTarget_SUBSCRIBER {
+		streak = &p.subRTCPWriteFailures
+	}
+
+	conf := p.params.RTCPWriteFailureConfig
+	if !streak.update(err, conf.MaxConsecutive, conf.Window) {
+		return
+	}
+
+	category := rtcpWriteErrorCategory(err)
+	p.params.Logger.Warnw(
+		"persistent RTCP write failures", err,
+		"target", target,
+		"streak", conf.MaxConsecutive,
+		"category", category,
+	)
+	p.params.Telemetry.ParticipantRTCPWriteFailed(context.Background(), p.ID(), target, category)
+
+	if onRTCPWriteFailure := p.getOnRTCPWriteFailure(); onRTCPWriteFailure != nil {
+		onRTCPWriteFailure(p, target, err)
+	} else {
+		p.onAnyTransportFailed()
+	}
+}
+
 func (p *ParticipantImpl) setDowntracksConnected() {
 	for _, t := range p.SubscriptionManager.GetSubscribedTracks() {
 		if dt := t.DownTrack(); dt != nil {
@@ -2448,11 +4221,18 @@ func (p *ParticipantImpl) GetCachedDownTrack(trackID livekit.TrackID) (*webrtc.R
 }
 
 func (p *ParticipantImpl) IssueFullReconnect(reason types.ParticipantCloseReason) {
+	reconnectCount := p.reconnectCount.Inc()
+	p.params.Logger.Infow("issuing full reconnect", "reason", reason, "reconnectCount", reconnectCount)
+
 	p.sendLeaveRequest(reason, false, true, false)
 
+	if reason == types.ParticipantCloseReasonMigrateCodecMismatch || reason == types.ParticipantCloseReasonMigrateTooManyTracks {
+		prometheus.RecordMigrationFailed()
+	}
+
 	scr := types.SignallingCloseReasonUnknown
 	switch reason {
-	case types.ParticipantCloseReasonPublicationError, types.ParticipantCloseReasonMigrateCodecMismatch:
+	case types.ParticipantCloseReasonPublicationError, types.ParticipantCloseReasonMigrateCodecMismatch, types.ParticipantCloseReasonMigrateTooManyTracks:
 		scr = types.SignallingCloseReasonFullReconnectPublicationError
 	case types.ParticipantCloseReasonSubscriptionError:
 		scr = types.SignallingCloseReasonFullReconnectSubscriptionError
@@ -2463,10 +4243,22 @@ func (p *ParticipantImpl) IssueFullReconnect(reason types.ParticipantCloseReason
 	}
 	p.CloseSignalConnection(scr)
 
+	p.keepUnacknowledgedReliableData()
+
 	// a full reconnect == client should connect back with a new session, close current one
 	p.Close(false, reason, false)
 }
 
+// GetReconnectCount returns number of full reconnects issued to this identity, across sessions.
+// A high count is indicative of a problem client.
+func (p *ParticipantImpl) GetReconnectCount() uint32 {
+	return p.reconnectCount.Load()
+}
+
+func (p *ParticipantImpl) ResetReconnectCount() {
+	p.reconnectCount.Store(0)
+}
+
 func (p *ParticipantImpl) onPublicationError(trackID livekit.TrackID) {
 	if p.params.ReconnectOnPublicationError {
 		p.pubLogger.Infow("issuing full reconnect on publication error", "trackID", trackID)
@@ -2474,10 +4266,61 @@ func (p *ParticipantImpl) onPublicationError(trackID livekit.TrackID) {
 	}
 }
 
+// SetSubscriberAudioOnly disables forwarding of all current and future video subscriptions when enabled,
+// which is cheaper than disabling each video track with track settings. When disabled again, track settings
+// sent by the subscriber, including those sent in audio only mode, are restored.
+func (p *ParticipantImpl) SetSubscriberAudioOnly(enabled bool) {
+	p.subLogger.Debugw("setting subscriber audio only", "enabled", enabled)
+	p.SubscriptionManager.SetAudioOnly(enabled)
+}
+
+// HandleSubscriberAudioOnlyRequest applies an audio only request of the subscriber,
+// it is ignored for clients which do not support it.
+func (p *ParticipantImpl) HandleSubscriberAudioOnlyRequest(enabled bool) {
+	if !p.ProtocolVersion().SupportsSubscriberAudioOnly() {
+		p.subLogger.Debugw("ignoring audio only request, not supported by client", "protocolVersion", p.ProtocolVersion())
+		return
+	}
+
+	p.SetSubscriberAudioOnly(enabled)
+}
+
+// SetTrackPlayoutDelay overrides playout delay limits of a subscribed track, nil restores the default.
+// Ignored for Firefox when streams are synced as playout delay is disabled for it then.
+func (p *ParticipantImpl) SetTrackPlayoutDelay(trackID livekit.TrackID, delay *livekit.PlayoutDelay) {
+	if p.params.SyncStreams && p.params.ClientInfo.isFirefox() {
+		p.subLogger.Debugw("ignoring playout delay override, not supported by client", "trackID", trackID)
+		return
+	}
+
+	p.SubscriptionManager.SetTrackPlayoutDelay(trackID, delay)
+}
+
+// HandleDecodeFeedback takes a key frame request of the subscriber for a subscribed track. A subscriber which
+// negotiated a codec it cannot decode is notified with a codec unsupported subscription error.
+func (p *ParticipantImpl) HandleDecodeFeedback(trackID livekit.TrackID) {
+	if !p.ProtocolVersion().SupportsDecodeFeedback() {
+		return
+	}
+
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		if subTrack.ID() != trackID {
+			continue
+		}
+
+		if subTrack.UpdateDecodeFeedback() {
+			p.subLogger.Warnw("subscriber is not decoding track", ErrSubscriberNotDecoding, "trackID", trackID)
+			p.params.Telemetry.TrackSubscribeFailed(context.Background(), p.ID(), trackID, ErrSubscriberNotDecoding, false)
+			p.onSubscriptionError(trackID, false, ErrSubscriberNotDecoding)
+		}
+		return
+	}
+}
+
 func (p *ParticipantImpl) onSubscriptionError(trackID livekit.TrackID, fatal bool, err error) {
 	signalErr := livekit.SubscriptionError_SE_UNKNOWN
 	switch {
-	case errors.Is(err, webrtc.ErrUnsupportedCodec):
+	case errors.Is(err, webrtc.ErrUnsupportedCodec), errors.Is(err, ErrTemporalLayersUnsupported):
 		signalErr = livekit.SubscriptionError_SE_CODEC_UNSUPPORTED
 	case errors.Is(err, ErrTrackNotFound):
 		signalErr = livekit.SubscriptionError_SE_TRACK_NOTFOUND
@@ -2509,7 +4352,7 @@ func (p *ParticipantImpl) UpdateSubscribedQuality(nodeID livekit.NodeID, trackID
 	track := p.GetPublishedTrack(trackID)
 	if track == nil {
 		p.pubLogger.Debugw("could not find track", "trackID", trackID)
-		return errors.New("could not find published track")
+		return fmt.Errorf("%w: %s", ErrTrackNotFound, trackID)
 	}
 
 	track.(types.LocalMediaTrack).NotifySubscriberNodeMaxQuality(nodeID, maxQualities)
@@ -2520,17 +4363,64 @@ func (p *ParticipantImpl) UpdateMediaLoss(nodeID livekit.NodeID, trackID livekit
 	track := p.GetPublishedTrack(trackID)
 	if track == nil {
 		p.pubLogger.Debugw("could not find track", "trackID", trackID)
-		return errors.New("could not find published track")
+		return fmt.Errorf("%w: %s", ErrTrackNotFound, trackID)
 	}
 
 	track.(types.LocalMediaTrack).NotifySubscriberNodeMediaLoss(nodeID, uint8(fractionalLoss))
 	return nil
 }
 
-func (p *ParticipantImpl) GetPlayoutDelayConfig() *livekit.PlayoutDelay {
+func (p *ParticipantImpl) GetAllocationPreference() sfu.AllocationPreference {
+	return p.params.AllocationPreference
+}
+
+// SetSubscriberPLIThrottle sets minimum interval between key frame requests made on behalf of this
+// subscriber, on top of PLI throttle of publisher, for subscribed and later subscribed tracks.
+// Requests within the interval are coalesced and dropped if a key frame arrives meanwhile, 0 disables.
+func (p *ParticipantImpl) SetSubscriberPLIThrottle(min time.Duration) {
+	p.subscriberPLIThrottle.Store(min)
+	p.subLogger.Infow("setting subscriber PLI throttle", "throttle", min)
+
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		if dt := subTrack.DownTrack(); dt != nil {
+			dt.SetKeyFrameRequestThrottle(min)
+		}
+	}
+}
+
+func (p *ParticipantImpl) GetSubscriberPLIThrottle() time.Duration {
+	return p.subscriberPLIThrottle.Load()
+}
+
+// GetPlayoutDelayConfig returns playout delay for a subscribed track of given source, nil if not applied to the source
+func (p *ParticipantImpl) GetPlayoutDelayConfig(source livekit.TrackSource) *livekit.PlayoutDelay {
+	if len(p.params.PlayoutDelaySources) != 0 && !slices.Contains(p.params.PlayoutDelaySources, source) {
+		return nil
+	}
 	return p.params.PlayoutDelay
 }
 
+// playoutDelayForKind returns true if playout delay could be applied to subscribed tracks of given kind
+func (p *ParticipantImpl) playoutDelayForKind(kind livekit.TrackType) bool {
+	if len(p.params.PlayoutDelaySources) == 0 {
+		return kind == livekit.TrackType_VIDEO
+	}
+
+	for _, source := range p.params.PlayoutDelaySources {
+		switch source {
+		case livekit.TrackSource_MICROPHONE, livekit.TrackSource_SCREEN_SHARE_AUDIO:
+			if kind == livekit.TrackType_AUDIO {
+				return true
+			}
+		default:
+			if kind == livekit.TrackType_VIDEO {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (p *ParticipantImpl) SupportsSyncStreamID() bool {
 	return p.ProtocolVersion().SupportSyncStreamID() && !p.params.ClientInfo.isFirefox() && p.params.SyncStreams
 }
@@ -2569,18 +4459,195 @@ func (p *ParticipantImpl) SendDataPacket(kind livekit.DataPacket_Kind, encoded [
 		return ErrDataChannelUnavailable
 	}
 
+	return p.sendDataPacketOfKind(kind, encoded)
+}
+
+func (p *ParticipantImpl) sendDataPacketOfKind(kind livekit.DataPacket_Kind, encoded []byte) error {
+	if kind == livekit.DataPacket_RELIABLE && (p.params.DataChannelLowBufferedAmount != 0 || p.params.MaxReliableDataRate != 0) {
+		return p.sendReliableDataPacket(encoded)
+	}
+	return p.sendDataPacket(kind, encoded)
+}
+
+// DataChannelBufferedAmount returns number of bytes queued to be sent to participant on data channel of kind
+func (p *ParticipantImpl) DataChannelBufferedAmount(kind livekit.DataPacket_Kind) uint64 {
+	return p.TransportManager.DataChannelBufferedAmount(kind)
+}
+
+// IsDataChannelBackpressured returns true when buffered amount of data channel is above the low buffered amount,
+// always false when low buffered amount is not configured
+func (p *ParticipantImpl) IsDataChannelBackpressured(kind livekit.DataPacket_Kind) bool {
+	return p.params.DataChannelLowBufferedAmount != 0 &&
+		p.DataChannelBufferedAmount(kind) > p.params.DataChannelLowBufferedAmount
+}
+
+// sendReliableDataPacket holds back reliable data while data channel is backpressured, to be sent in order when it drains.
+// Data above the reliable data rate is rejected, or held back till it is within the rate when queueing is enabled.
+func (p *ParticipantImpl) sendReliableDataPacket(encoded []byte) error {
+	p.reliableDataLock.Lock()
+	defer p.reliableDataLock.Unlock()
+
+	withinRate := p.isReliableDataWithinRateLocked(len(encoded))
+	if len(p.deferredReliableData) == 0 && !p.IsDataChannelBackpressured(livekit.DataPacket_RELIABLE) && withinRate {
+		p.reliableDataBucket.take(float64(len(encoded)))
+		return p.sendDataPacket(livekit.DataPacket_RELIABLE, encoded)
+	}
+
+	if !withinRate && !p.params.QueueReliableDataAboveRate {
+		return ErrReliableDataRateExceeded
+	}
+
+	if len(p.deferredReliableData) >= maxDeferredReliableDataPackets {
+		return ErrDataChannelBufferFull
+	}
+	p.deferredReliableData = append(p.deferredReliableData, encoded)
+	if !withinRate {
+		p.scheduleReliableDataFlushLocked()
+	}
+	return nil
+}
+
+func (p *ParticipantImpl) flushDeferredReliableData() {
+	p.reliableDataLock.Lock()
+	defer p.reliableDataLock.Unlock()
+
+	for len(p.deferredReliableData) != 0 && !p.IsDataChannelBackpressured(livekit.DataPacket_RELIABLE) {
+		encoded := p.deferredReliableData[0]
+		if !p.isReliableDataWithinRateLocked(len(encoded)) {
+			p.scheduleReliableDataFlushLocked()
+			return
+		}
+
+		if err := p.sendDataPacket(livekit.DataPacket_RELIABLE, encoded); err != nil {
+			p.params.Logger.Infow("could not send deferred data packet", "error", err, "remaining", len(p.deferredReliableData))
+			if p.State() != livekit.ParticipantInfo_ACTIVE {
+				p.deferredReliableData = nil
+				return
+			}
+
+			// keep the packet at the head to preserve order and retry it
+			p.scheduleReliableDataFlushAfterLocked(reliableDataRetryInterval)
+			return
+		}
+
+		p.reliableDataBucket.take(float64(len(encoded)))
+		p.deferredReliableData[0] = nil
+		p.deferredReliableData = p.deferredReliableData[1:]
+	}
+}
+
+// a packet larger than the burst is sent when the bucket is full, it is always within the rate when not limited
+func (p *ParticipantImpl) isReliableDataWithinRateLocked(size int) bool {
+	now := time.Now()
+	if elapsed := now.Sub(p.reliableDataBucketAt); elapsed > 0 {
+		p.reliableDataBucket.refill(elapsed)
+		p.reliableDataBucketAt = now
+	}
+	return p.reliableDataBucket.has(math.Min(float64(size), p.reliableDataBucket.burst))
+}
+
+func (p *ParticipantImpl) scheduleReliableDataFlushLocked() {
+	if len(p.deferredReliableData) == 0 {
+		return
+	}
+
+	size := float64(len(p.deferredReliableData[0]))
+	p.scheduleReliableDataFlushAfterLocked(p.reliableDataBucket.wait(math.Min(size, p.reliableDataBucket.burst)))
+}
+
+func (p *ParticipantImpl) scheduleReliableDataFlushAfterLocked(wait time.Duration) {
+	if p.reliableDataFlushTimer != nil || len(p.deferredReliableData) == 0 {
+		return
+	}
+
+	p.reliableDataFlushTimer = time.AfterFunc(wait, func() {
+		p.reliableDataLock.Lock()
+		p.reliableDataFlushTimer = nil
+		p.reliableDataLock.Unlock()
+
+		p.flushDeferredReliableData()
+	})
+}
+
+func (p *ParticipantImpl) handleDataChannelBufferedAmountLow(kind livekit.DataPacket_Kind) {
+	// called from SCTP stack, send from another goroutine
+	go func() {
+		if kind == livekit.DataPacket_RELIABLE {
+			p.flushDeferredReliableData()
+		}
+
+		if onDataChannelBufferedAmountLow := p.getOnDataChannelBufferedAmountLow(); onDataChannelBufferedAmountLow != nil {
+			onDataChannelBufferedAmountLow(p, kind)
+		}
+	}()
+}
+
+func (p *ParticipantImpl) sendDataPacket(kind livekit.DataPacket_Kind, encoded []byte) error {
 	err := p.TransportManager.SendDataPacket(kind, encoded)
 	if err != nil {
-		if (errors.Is(err, sctp.ErrStreamClosed) || errors.Is(err, io.ErrClosedPipe)) && p.params.ReconnectOnDataChannelError {
-			p.params.Logger.Infow("issuing full reconnect on data channel error", "error", err)
-			p.IssueFullReconnect(types.ParticipantCloseReasonDataChannelError)
+		if errors.Is(err, sctp.ErrStreamClosed) || errors.Is(err, io.ErrClosedPipe) {
+			if kind == livekit.DataPacket_RELIABLE && p.reliableDataHistory != nil {
+				p.reliableDataHistory.add(encoded, false)
+			}
+			if p.params.ReconnectOnDataChannelError && !p.dataChannelErrorReconnect.Swap(true) {
+				p.params.Logger.Infow("issuing full reconnect on data channel error", "error", err)
+				// reliable data may be sent under reliable data lock, which closing takes
+				go p.IssueFullReconnect(types.ParticipantCloseReasonDataChannelError)
+			}
 		}
 	} else {
+		if kind == livekit.DataPacket_RELIABLE && p.reliableDataHistory != nil {
+			p.reliableDataHistory.add(encoded, true)
+		}
 		p.dataChannelStats.AddBytes(uint64(len(encoded)), true)
 	}
 	return err
 }
 
+// keepUnacknowledgedReliableData keeps reliable data the client has not acknowledged yet, including held back data,
+// to be sent to the next session of this identity
+func (p *ParticipantImpl) keepUnacknowledgedReliableData() {
+	if p.reliableDataHistory == nil {
+		return
+	}
+
+	p.reliableDataLock.Lock()
+	defer p.reliableDataLock.Unlock()
+
+	unacknowledged := p.reliableDataHistory.unacknowledged(p.DataChannelBufferedAmount(livekit.DataPacket_RELIABLE))
+	p.unacknowledgedReliableData = append(unacknowledged, p.deferredReliableData...)
+	if len(p.unacknowledgedReliableData) != 0 {
+		p.params.Logger.Infow("keeping unacknowledged reliable data", "count", len(p.unacknowledgedReliableData))
+	}
+}
+
+// UnacknowledgedReliableData returns reliable data not acknowledged by the client when a full reconnect was issued,
+// nil otherwise or when reliable data history is not kept
+func (p *ParticipantImpl) UnacknowledgedReliableData() [][]byte {
+	p.reliableDataLock.Lock()
+	defer p.reliableDataLock.Unlock()
+
+	return p.unacknowledgedReliableData
+}
+
+// sendPendingReliableData sends reliable data not acknowledged by previous session, once, ahead of any other data
+func (p *ParticipantImpl) sendPendingReliableData() {
+	p.reliableDataLock.Lock()
+	pending := p.pendingReliableData
+	p.pendingReliableData = nil
+	p.reliableDataLock.Unlock()
+
+	for idx, encoded := range pending {
+		if err := p.sendDataPacketOfKind(livekit.DataPacket_RELIABLE, encoded); err != nil {
+			p.params.Logger.Warnw("could not send pending reliable data", err, "sent", idx, "count", len(pending))
+			return
+		}
+	}
+	if len(pending) != 0 {
+		p.params.Logger.Infow("sent pending reliable data", "count", len(pending))
+	}
+}
+
 func (p *ParticipantImpl) setupEnabledCodecs(publishEnabledCodecs []*livekit.Codec, subscribeEnabledCodecs []*livekit.Codec, disabledCodecs *livekit.DisabledCodecs) {
 	shouldDisable := func(c *livekit.Codec, disabled []*livekit.Codec) bool {
 		for _, disableCodec := range disabled {
@@ -2610,3 +4677,14 @@ func (p *ParticipantImpl) setupEnabledCodecs(publishEnabledCodecs []*livekit.Cod
 	}
 	p.enabledSubscribeCodecs = subscribeCodecs
 }
+
+func signalingRTTToQuality(rtt uint32) livekit.ConnectionQuality {
+	switch {
+	case rtt > signalingRTTPoorThreshold:
+		return livekit.ConnectionQuality_POOR
+	case rtt > signalingRTTGoodThreshold:
+		return livekit.ConnectionQuality_GOOD
+	default:
+		return livekit.ConnectionQuality_EXCELLENT
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+type PublishedTrackDiagnostics struct {
+	TrackInfo *livekit.TrackInfo
+	Score     float32
+	Quality   livekit.ConnectionQuality
+	Stats     *livekit.RTPStats
+}
+
+type SubscribedTrackDiagnostics struct {
+	TrackID     livekit.TrackID
+	PublisherID livekit.ParticipantID
+	Kind        livekit.TrackType
+	Bound       bool
+	Muted       bool
+	Score       float32
+	Quality     livekit.ConnectionQuality
+	Stats       *livekit.RTPStats
+	// includes last allocation and pause reason
+	Forwarder sfu.ForwarderSnapshot
+}
+
+// ParticipantDiagnosticBundle is a snapshot of participant state for support,
+// sections are collected one after another and are not atomic with each other
+type ParticipantDiagnosticBundle struct {
+	At              time.Time
+	ID              livekit.ParticipantID
+	Identity        livekit.ParticipantIdentity
+	State           livekit.ParticipantInfo_State
+	ConnectedAt     time.Time
+	MigrateState    types.MigrateState
+	ProtocolVersion types.ProtocolVersion
+	Capabilities    map[string]bool
+	ClientInfo      *livekit.ClientInfo
+
+	SignalingRTT uint32
+	MediaRTT     uint32
+	ICEConfig    *livekit.ICEConfig
+	// ICE config when a transport was last fully established
+	LastGoodICEConfig *livekit.ICEConfig
+
+	PublishedTracks  []PublishedTrackDiagnostics
+	SubscribedTracks []SubscribedTrackDiagnostics
+	Bitrate          *types.BitrateSummary
+	// number of subscribed video tracks by pause reason
+	PauseSummary map[sfu.VideoPauseReason]int
+}
+
+// GetDiagnosticBundle aggregates participant, transport and per track state in one call.
+// It only reads state, unlike GetConnectionQuality it does not update quality tracking.
+func (p *ParticipantImpl) GetDiagnosticBundle() *ParticipantDiagnosticBundle {
+	bundle := &ParticipantDiagnosticBundle{
+		At:                time.Now(),
+		ID:                p.ID(),
+		Identity:          p.Identity(),
+		State:             p.State(),
+		ConnectedAt:       p.ConnectedAt(),
+		MigrateState:      p.MigrateState(),
+		ProtocolVersion:   p.ProtocolVersion(),
+		Capabilities:      p.resolvedCapabilities(),
+		ClientInfo:        p.GetClientInfo(),
+		ICEConfig:         p.TransportManager.GetICEConfig(),
+		LastGoodICEConfig: p.TransportManager.GetLastGoodICEConfig(),
+		PauseSummary:      make(map[sfu.VideoPauseReason]int),
+	}
+	bundle.SignalingRTT, bundle.MediaRTT = p.TransportManager.GetRTT()
+
+	for _, pt := range p.GetPublishedTracks() {
+		ptd := PublishedTrackDiagnostics{
+			TrackInfo: pt.ToProto(),
+		}
+		if lmt, ok := pt.(types.LocalMediaTrack); ok {
+			ptd.Score, ptd.Quality = lmt.GetConnectionScoreAndQuality()
+			ptd.Stats = lmt.GetTrackStats()
+		}
+		bundle.PublishedTracks = append(bundle.PublishedTracks, ptd)
+	}
+
+	for _, subTrack := range p.SubscriptionManager.GetSubscribedTracks() {
+		std := SubscribedTrackDiagnostics{
+			TrackID:     subTrack.ID(),
+			PublisherID: subTrack.PublisherID(),
+			Kind:        subTrack.MediaTrack().Kind(),
+			Bound:       subTrack.IsBound(),
+			Muted:       subTrack.IsMuted(),
+		}
+		if dt := subTrack.DownTrack(); dt != nil {
+			std.Score, std.Quality = dt.GetConnectionScoreAndQuality()
+			std.Stats = dt.GetTrackStats()
+			std.Forwarder = dt.GetForwarderSnapshot()
+			if std.Kind == livekit.TrackType_VIDEO {
+				bundle.PauseSummary[std.Forwarder.LastAllocation.PauseReason]++
+			}
+		}
+		bundle.SubscribedTracks = append(bundle.SubscribedTracks, std)
+	}
+
+	bundle.Bitrate = p.GetBitrateSummary()
+	return bundle
+}
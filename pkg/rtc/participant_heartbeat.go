@@ -0,0 +1,52 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"context"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+// sendHeartbeat sends a compact snapshot of participant state to telemetry,
+// it is scheduled only when heartbeat interval is configured
+func (p *ParticipantImpl) sendHeartbeat() {
+	if p.IsClosed() {
+		return
+	}
+
+	p.params.Telemetry.ParticipantHeartbeat(context.Background(), p.ID(), p.Identity(), p.getHeartbeat())
+}
+
+func (p *ParticipantImpl) getHeartbeat() *telemetry.ParticipantHeartbeat {
+	signalingRTT, mediaRTT := p.TransportManager.GetRTT()
+	rtt := mediaRTT
+	if rtt == 0 {
+		rtt = signalingRTT
+	}
+
+	return &telemetry.ParticipantHeartbeat{
+		State:               p.State(),
+		MigrateState:        p.MigrateState().String(),
+		PublisherState:      p.TransportManager.GetConnectionState(livekit.SignalTarget_PUBLISHER),
+		SubscriberState:     p.TransportManager.GetConnectionState(livekit.SignalTarget_SUBSCRIBER),
+		NumPublishedTracks:  len(p.GetPublishedTracks()),
+		NumSubscribedTracks: len(p.SubscriptionManager.GetSubscribedTracks()),
+		RTT:                 rtt,
+		Congested:           p.TransportManager.IsSubscriberCongested(),
+	}
+}
@@ -15,6 +15,7 @@
 package rtc
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -25,7 +26,10 @@ import (
 	"go.uber.org/atomic"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+	"github.com/livekit/livekit-server/pkg/sfu/pacer"
 	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
@@ -35,9 +39,11 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/routingfakes"
+	"github.com/livekit/livekit-server/pkg/rtc/transport"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
 	"github.com/livekit/livekit-server/pkg/testutils"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 func TestIsReady(t *testing.T) {
@@ -199,95 +205,2253 @@ func TestTrackPublishing(t *testing.T) {
 			CanPublishSources: []livekit.TrackSource{
 				livekit.TrackSource_CAMERA,
 			},
-		})
+		}, nil)
 		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
 		p.AddTrack(&livekit.AddTrackRequest{
 			Cid:    "cid",
 			Name:   "webcam",
 			Source: livekit.TrackSource_CAMERA,
 			Type:   livekit.TrackType_VIDEO,
-		})
-		require.Equal(t, 1, sink.WriteMessageCallCount())
+		})
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    "cid2",
+			Name:   "rejected source",
+			Type:   livekit.TrackType_AUDIO,
+			Source: livekit.TrackSource_MICROPHONE,
+		})
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+	})
+}
+
+func TestOutOfOrderUpdates(t *testing.T) {
+	p := newParticipantForTest("test")
+	p.updateState(livekit.ParticipantInfo_JOINED)
+	p.SetMetadata("initial metadata")
+	sink := p.getResponseSink().(*routingfakes.FakeMessageSink)
+	pi1 := p.ToProto()
+	p.SetMetadata("second update")
+	pi2 := p.ToProto()
+
+	require.Greater(t, pi2.Version, pi1.Version)
+
+	// send the second update first
+	require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{pi2}))
+	require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{pi1}))
+
+	// only sent once, and it's the earlier message
+	require.Equal(t, 1, sink.WriteMessageCallCount())
+	sent := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse)
+	require.Equal(t, "second update", sent.GetUpdate().Participants[0].Metadata)
+}
+
+func TestRecentUpdates(t *testing.T) {
+	updateForTest := func(version uint32) *livekit.ParticipantInfo {
+		return &livekit.ParticipantInfo{
+			Sid:      "PA_other",
+			Identity: "other",
+			State:    livekit.ParticipantInfo_ACTIVE,
+			Version:  version,
+		}
+	}
+	versions := func(records []ParticipantUpdateRecord) []uint32 {
+		var v []uint32
+		for _, r := range records {
+			v = append(v, r.Version)
+		}
+		return v
+	}
+
+	t.Run("keeps last update by default", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.updateState(livekit.ParticipantInfo_JOINED)
+		require.Empty(t, p.GetRecentUpdates("PA_other"))
+
+		for _, version := range []uint32{1, 3, 2} {
+			require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest(version)}))
+		}
+		records := p.GetRecentUpdates("PA_other")
+		require.Equal(t, []uint32{3}, versions(records))
+		require.Equal(t, livekit.ParticipantIdentity("other"), records[0].Identity)
+		require.False(t, records[0].Outdated)
+	})
+
+	t.Run("keeps bounded history", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{maxCachedUpdates: 3})
+		p.updateState(livekit.ParticipantInfo_JOINED)
+
+		for _, version := range []uint32{1, 2, 4, 3} {
+			require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest(version)}))
+		}
+		records := p.GetRecentUpdates("PA_other")
+		require.Equal(t, []uint32{2, 4, 3}, versions(records))
+		require.Equal(t, []bool{false, false, true}, []bool{records[0].Outdated, records[1].Outdated, records[2].Outdated})
+
+		require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest(5)}))
+		require.Equal(t, []uint32{4, 3, 5}, versions(p.GetRecentUpdates("PA_other")))
+		require.Empty(t, p.GetRecentUpdates("PA_unknown"))
+	})
+}
+
+func TestBandwidthHint(t *testing.T) {
+	p := newParticipantForTest("test")
+	require.Zero(t, p.bandwidthHint.Load())
+
+	p.SetBandwidthHint(0)
+	p.SetBandwidthHint(-1)
+	require.Zero(t, p.bandwidthHint.Load())
+
+	p.SetBandwidthHint(2_000_000)
+	require.Equal(t, int64(2_000_000), p.bandwidthHint.Load())
+
+	// clamped to bounds
+	p.SetBandwidthHint(1_000)
+	require.Equal(t, int64(minBandwidthHint), p.bandwidthHint.Load())
+	p.SetBandwidthHint(10 * maxBandwidthHint)
+	require.Equal(t, int64(maxBandwidthHint), p.bandwidthHint.Load())
+}
+
+func TestParticipantUpdateBatching(t *testing.T) {
+	updateForTest := func(sid string, version uint32, state livekit.ParticipantInfo_State) *livekit.ParticipantInfo {
+		return &livekit.ParticipantInfo{
+			Sid:      sid,
+			Identity: sid,
+			State:    state,
+			Version:  version,
+		}
+	}
+	newBatchingParticipant := func() (*ParticipantImpl, *routingfakes.FakeMessageSink) {
+		p := newParticipantForTest("test")
+		p.updateState(livekit.ParticipantInfo_JOINED)
+		// long window, batches are flushed by the test
+		p.params.GetUpdateBatchWindow = func() time.Duration { return time.Hour }
+		return p, p.getResponseSink().(*routingfakes.FakeMessageSink)
+	}
+	sentUpdates := func(sink *routingfakes.FakeMessageSink, idx int) map[string]uint32 {
+		sent := make(map[string]uint32)
+		for _, pi := range sink.WriteMessageArgsForCall(idx).(*livekit.SignalResponse).GetUpdate().Participants {
+			sent[pi.Sid] = pi.Version
+		}
+		return sent
+	}
+
+	t.Run("coalesces versions of a participant", func(t *testing.T) {
+		p, sink := newBatchingParticipant()
+		for _, version := range []uint32{1, 3, 2} {
+			require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest("PA_a", version, livekit.ParticipantInfo_ACTIVE)}))
+		}
+		require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest("PA_b", 1, livekit.ParticipantInfo_ACTIVE)}))
+		require.Zero(t, sink.WriteMessageCallCount())
+
+		p.flushBatchedUpdates()
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+		require.Equal(t, map[string]uint32{"PA_a": 3, "PA_b": 1}, sentUpdates(sink, 0))
+
+		// nothing left to flush
+		p.flushBatchedUpdates()
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+	})
+
+	t.Run("self updates and disconnects are not batched", func(t *testing.T) {
+		p, sink := newBatchingParticipant()
+		require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{p.ToProto()}))
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+
+		require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest("PA_a", 2, livekit.ParticipantInfo_DISCONNECTED)}))
+		require.Equal(t, 2, sink.WriteMessageCallCount())
+		require.Equal(t, map[string]uint32{"PA_a": 2}, sentUpdates(sink, 1))
+	})
+
+	t.Run("keeps version ordering with updates sent right away", func(t *testing.T) {
+		p, sink := newBatchingParticipant()
+		require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{
+			updateForTest("PA_a", 4, livekit.ParticipantInfo_ACTIVE),
+			updateForTest("PA_b", 1, livekit.ParticipantInfo_ACTIVE),
+		}))
+		require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest("PA_a", 5, livekit.ParticipantInfo_DISCONNECTED)}))
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+
+		// batched update of PA_a is older than the disconnect already sent
+		p.flushBatchedUpdates()
+		require.Equal(t, 2, sink.WriteMessageCallCount())
+		require.Equal(t, map[string]uint32{"PA_b": 1}, sentUpdates(sink, 1))
+
+		records := p.GetRecentUpdates("PA_a")
+		require.Equal(t, uint32(5), records[len(records)-1].Version)
+	})
+
+	t.Run("flushes after window", func(t *testing.T) {
+		p, sink := newBatchingParticipant()
+		p.params.GetUpdateBatchWindow = func() time.Duration { return 10 * time.Millisecond }
+		require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{updateForTest("PA_a", 1, livekit.ParticipantInfo_ACTIVE)}))
+		require.Eventually(t, func() bool {
+			return sink.WriteMessageCallCount() == 1
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("window scales with room size", func(t *testing.T) {
+		require.Zero(t, UpdateBatchWindow(50*time.Millisecond, 0, 100))
+		require.Equal(t, 50*time.Millisecond, UpdateBatchWindow(50*time.Millisecond, 250*time.Millisecond, 0))
+		require.Equal(t, 150*time.Millisecond, UpdateBatchWindow(50*time.Millisecond, 250*time.Millisecond, 250))
+		require.Equal(t, 250*time.Millisecond, UpdateBatchWindow(50*time.Millisecond, 250*time.Millisecond, 1000))
+	})
+}
+
+func TestForceSubscriberRenegotiation(t *testing.T) {
+	hasForcedNegotiation := func(p *ParticipantImpl) bool {
+		for _, pn := range p.TransportManager.GetPendingNegotiations(livekit.SignalTarget_SUBSCRIBER) {
+			if pn.Cause == transport.NegotiationCauseForced {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("negotiates before migration syncs", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		defer p.Close(false, types.ParticipantCloseReasonNone, false)
+		require.Equal(t, types.MigrateStateInit, p.MigrateState())
+
+		// regular negotiation is gated on migration state
+		p.Negotiate(true)
+		require.Empty(t, p.TransportManager.GetPendingNegotiations(livekit.SignalTarget_SUBSCRIBER))
+
+		require.NoError(t, p.ForceSubscriberRenegotiation())
+		require.True(t, hasForcedNegotiation(p))
+		require.Equal(t, types.MigrateStateInit, p.MigrateState())
+
+		// migration proceeds as usual
+		p.SetMigrateState(types.MigrateStateSync)
+		require.Equal(t, types.MigrateStateSync, p.MigrateState())
+	})
+
+	t.Run("not before join response", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		defer p.Close(false, types.ParticipantCloseReasonNone, false)
+		p.state.Store(livekit.ParticipantInfo_JOINING)
+
+		require.ErrorIs(t, p.ForceSubscriberRenegotiation(), ErrParticipantNotReady)
+		require.False(t, hasForcedNegotiation(p))
+	})
+
+	t.Run("not when closed", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		require.NoError(t, p.Close(false, types.ParticipantCloseReasonNone, false))
+
+		require.ErrorIs(t, p.ForceSubscriberRenegotiation(), ErrParticipantNotReady)
+	})
+}
+
+func TestIsIdleWithDataActivity(t *testing.T) {
+	p := newParticipantForTest("test")
+	require.True(t, p.IsIdle())
+
+	// data-only participant
+	p.dataChannelStats.AddBytes(100, false)
+
+	// data activity not considered by default
+	require.True(t, p.IsIdle())
+
+	p.params.DataActivityIdleWindow = time.Minute
+	require.False(t, p.IsIdle())
+
+	// idle once data activity falls outside the window
+	p.params.DataActivityIdleWindow = 20 * time.Millisecond
+	time.Sleep(30 * time.Millisecond)
+	require.True(t, p.IsIdle())
+
+	// sending data also counts as activity
+	p.dataChannelStats.AddBytes(100, true)
+	require.False(t, p.IsIdle())
+}
+
+// after disconnection, things should continue to function and not panic
+func TestDisconnectTiming(t *testing.T) {
+	t.Run("Negotiate doesn't panic after channel closed", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		msg := routing.NewMessageChannel(livekit.ConnectionID("test"), routing.DefaultMessageChannelSize)
+		p.params.Sink = msg
+		go func() {
+			for msg := range msg.ReadChan() {
+				t.Log("received message from chan", msg)
+			}
+		}()
+		track := &typesfakes.FakeMediaTrack{}
+		p.UpTrackManager.AddPublishedTrack(track)
+		p.handleTrackPublished(track)
+
+		// close channel and then try to Negotiate
+		msg.Close()
+	})
+}
+
+func TestDataChannelRateLimit(t *testing.T) {
+	p := newParticipantForTest("test")
+	p.dataChannelRateLimiter = newDataChannelRateLimiter(config.DataChannelRateLimitConfig{
+		PacketsPerSec: 1,
+		BurstPackets:  2,
+	})
+	// hold back data sent to participant, so that it can be inspected
+	p.params.DataChannelLowBufferedAmount = 1024
+	p.updateState(livekit.ParticipantInfo_ACTIVE)
+	p.deferredReliableData = [][]byte{[]byte("held")}
+
+	forwarded := atomic.NewInt32(0)
+	p.OnDataPacket(func(_ types.LocalParticipant, _ livekit.DataPacket_Kind, _ *livekit.DataPacket) {
+		forwarded.Inc()
+	})
+
+	data, err := proto.Marshal(&livekit.DataPacket{
+		Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Payload: []byte("hello")}},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		p.onDataMessage(livekit.DataPacket_RELIABLE, data)
+	}
+	require.Equal(t, int32(2), forwarded.Load())
+
+	totals := p.dataChannelStats.GetTrafficTotals()
+	require.Equal(t, uint32(4), totals.RecvMessages)
+	require.Equal(t, uint32(2), totals.RecvDroppedMessages)
+	require.Equal(t, uint64(2*len(data)), totals.RecvDroppedBytes)
+
+	// throttling is not reported on the data channel of the participant
+	p.reliableDataLock.Lock()
+	require.Len(t, p.deferredReliableData, 1)
+	p.reliableDataLock.Unlock()
+}
+
+func TestDataPacketFilter(t *testing.T) {
+	p := newParticipantForTest("test")
+	var filtered []*livekit.DataPacket
+	p.params.DataPacketFilter = func(sender types.LocalParticipant, dp *livekit.DataPacket) bool {
+		require.Equal(t, p, sender)
+		filtered = append(filtered, dp)
+		return dp.GetUser().GetTopic() != "blocked"
+	}
+
+	forwarded := atomic.NewInt32(0)
+	p.OnDataPacket(func(_ types.LocalParticipant, _ livekit.DataPacket_Kind, _ *livekit.DataPacket) {
+		forwarded.Inc()
+	})
+
+	blocked, err := proto.Marshal(&livekit.DataPacket{
+		Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Payload: []byte("hello"), Topic: proto.String("blocked")}},
+	})
+	require.NoError(t, err)
+	allowed, err := proto.Marshal(&livekit.DataPacket{
+		Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Payload: []byte("hello")}},
+	})
+	require.NoError(t, err)
+
+	p.onDataMessage(livekit.DataPacket_RELIABLE, blocked)
+	p.onDataMessage(livekit.DataPacket_RELIABLE, allowed)
+	p.onDataMessage(livekit.DataPacket_LOSSY, blocked)
+	require.Equal(t, int32(1), forwarded.Load())
+
+	// filter sees the packet after identity is set
+	require.Len(t, filtered, 3)
+	require.Equal(t, "test", filtered[0].ParticipantIdentity)
+	require.Equal(t, string(p.ID()), filtered[0].GetUser().ParticipantSid)
+
+	totals := p.dataChannelStats.GetTrafficTotals()
+	require.Equal(t, uint32(3), totals.RecvMessages)
+	require.Equal(t, uint32(2), totals.RecvFilteredMessages)
+	require.Equal(t, uint64(2*len(blocked)), totals.RecvFilteredBytes)
+	require.Zero(t, totals.RecvDroppedMessages)
+}
+
+func TestPublishLimit(t *testing.T) {
+	newParticipant := func(conf config.PublishLimitConfig) *ParticipantImpl {
+		p := newParticipantForTest("test")
+		p.params.PublishLimit = conf
+		p.publishLimiter = newPublishLimiter(conf)
+		return p
+	}
+	addTrack := func(p *ParticipantImpl, cid string, source livekit.TrackSource) {
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    cid,
+			Type:   livekit.TrackType_VIDEO,
+			Source: source,
+		})
+	}
+	numPending := func(p *ParticipantImpl) int {
+		p.pendingTracksLock.RLock()
+		defer p.pendingTracksLock.RUnlock()
+		n, _ := p.numPendingTracksLocked(livekit.TrackSource_CAMERA)
+		return n
+	}
+
+	t.Run("bounds pending tracks", func(t *testing.T) {
+		p := newParticipant(config.PublishLimitConfig{
+			MaxPendingTracks:          3,
+			MaxPendingTracksPerSource: 2,
+		})
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		// same cid is queued, counting towards the source
+		addTrack(p, "cid1", livekit.TrackSource_CAMERA)
+		addTrack(p, "cid1", livekit.TrackSource_CAMERA)
+		addTrack(p, "cid2", livekit.TrackSource_CAMERA)
+		require.Equal(t, 2, numPending(p))
+
+		addTrack(p, "cid3", livekit.TrackSource_SCREEN_SHARE)
+		addTrack(p, "cid4", livekit.TrackSource_MICROPHONE)
+		require.Equal(t, 3, numPending(p))
+		require.False(t, p.IsClosed())
+
+		// rejected requests are responded to without track info
+		addTrack(p, "cid5", livekit.TrackSource_MICROPHONE)
+		require.Equal(t, 3, numPending(p))
+		require.Equal(t, 5, sink.WriteMessageCallCount())
+		response := sink.WriteMessageArgsForCall(4).(*livekit.SignalResponse).GetTrackPublished()
+		require.Equal(t, "cid5", response.GetCid())
+		require.Nil(t, response.GetTrack())
+	})
+
+	t.Run("closes after repeated rejections", func(t *testing.T) {
+		p := newParticipant(config.PublishLimitConfig{
+			AddTrackPerSource: 2,
+			AddTrackWindow:    time.Minute,
+			MaxRejections:     3,
+		})
+		for i := 0; i < 4; i++ {
+			addTrack(p, fmt.Sprintf("cid%d", i), livekit.TrackSource_CAMERA)
+		}
+		require.Equal(t, 2, numPending(p))
+		require.False(t, p.IsClosed())
+
+		addTrack(p, "cid4", livekit.TrackSource_CAMERA)
+		require.True(t, p.IsClosed())
+		require.Equal(t, types.ParticipantCloseReasonPublishRateExceeded, p.CloseReason())
+	})
+
+	t.Run("expires pending tracks without media", func(t *testing.T) {
+		p := newParticipant(config.PublishLimitConfig{
+			MaxPendingTracksPerSource: 1,
+			PendingTrackTimeout:       time.Minute,
+		})
+		addTrack(p, "leaked", livekit.TrackSource_CAMERA)
+		addTrack(p, "cid", livekit.TrackSource_CAMERA)
+		require.Equal(t, 1, numPending(p))
+
+		// migrated and muted tracks are not expired
+		p.pendingTracksLock.Lock()
+		p.pendingTracks["leaked"].requestedAt = time.Now().Add(-2 * time.Minute)
+		p.pendingTracks["migrated"] = &pendingTrackInfo{
+			trackInfos: []*livekit.TrackInfo{{Sid: "migrated", Source: livekit.TrackSource_MICROPHONE}},
+			migrated:   true,
+		}
+		p.pendingTracks["muted"] = &pendingTrackInfo{
+			trackInfos:  []*livekit.TrackInfo{{Sid: "muted", Source: livekit.TrackSource_SCREEN_SHARE, Muted: true}},
+			requestedAt: time.Now().Add(-2 * time.Minute),
+		}
+		p.pendingTracksLock.Unlock()
+
+		addTrack(p, "cid", livekit.TrackSource_CAMERA)
+		p.pendingTracksLock.RLock()
+		require.Nil(t, p.pendingTracks["leaked"])
+		require.NotNil(t, p.pendingTracks["cid"])
+		require.NotNil(t, p.pendingTracks["migrated"])
+		require.NotNil(t, p.pendingTracks["muted"])
+		p.pendingTracksLock.RUnlock()
+	})
+
+	t.Run("does not expire pending tracks by default", func(t *testing.T) {
+		p := newParticipant(config.DefaultConfig.RTC.PublishLimit)
+		addTrack(p, "cid", livekit.TrackSource_CAMERA)
+
+		p.pendingTracksLock.Lock()
+		p.expirePendingTracksLocked(time.Now().Add(time.Hour))
+		require.NotNil(t, p.pendingTracks["cid"])
+		p.pendingTracksLock.Unlock()
+	})
+}
+
+func TestDuplicatePublishPolicy(t *testing.T) {
+	newParticipant := func(policy config.DuplicatePublishPolicy) *ParticipantImpl {
+		p := newParticipantForTest("test")
+		p.params.PublishLimit.DuplicatePolicy = policy
+		return p
+	}
+	addCamera := func(p *ParticipantImpl, cid string, name string) {
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    cid,
+			Name:   name,
+			Type:   livekit.TrackType_VIDEO,
+			Source: livekit.TrackSource_CAMERA,
+		})
+	}
+	publishedTracks := func(p *ParticipantImpl) []*livekit.TrackPublishedResponse {
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		var published []*livekit.TrackPublishedResponse
+		for i := 0; i < sink.WriteMessageCallCount(); i++ {
+			res := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse)
+			if tp, ok := res.Message.(*livekit.SignalResponse_TrackPublished); ok {
+				published = append(published, tp.TrackPublished)
+			}
+		}
+		return published
+	}
+	numPending := func(p *ParticipantImpl) int {
+		p.pendingTracksLock.RLock()
+		defer p.pendingTracksLock.RUnlock()
+		return len(p.pendingTracks)
+	}
+
+	t.Run("allow", func(t *testing.T) {
+		for _, policy := range []config.DuplicatePublishPolicy{"", config.DuplicatePublishPolicyAllow} {
+			p := newParticipant(policy)
+			addCamera(p, "cid1", "camera")
+			addCamera(p, "cid2", "camera")
+
+			published := publishedTracks(p)
+			require.Len(t, published, 2)
+			require.NotEqual(t, published[0].Track.Sid, published[1].Track.Sid)
+			require.Equal(t, 2, numPending(p))
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		p := newParticipant(config.DuplicatePublishPolicyReject)
+		addCamera(p, "cid1", "camera")
+		addCamera(p, "cid2", "camera")
+
+		// duplicate is answered without track info
+		published := publishedTracks(p)
+		require.Len(t, published, 2)
+		require.Equal(t, "cid1", published[0].Cid)
+		require.NotNil(t, published[0].Track)
+		require.Equal(t, "cid2", published[1].Cid)
+		require.Nil(t, published[1].Track)
+		require.Equal(t, 1, numPending(p))
+
+		// different name is not a duplicate, nor are unnamed tracks
+		addCamera(p, "cid3", "other camera")
+		addCamera(p, "cid4", "")
+		addCamera(p, "cid5", "")
+		require.Len(t, publishedTracks(p), 5)
+		require.False(t, p.IsClosed())
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		p := newParticipant(config.DuplicatePublishPolicyMerge)
+		addCamera(p, "cid1", "camera")
+		addCamera(p, "cid2", "camera")
+
+		// answered with pending track
+		published := publishedTracks(p)
+		require.Len(t, published, 2)
+		require.Equal(t, "cid2", published[1].Cid)
+		require.Equal(t, published[0].Track.Sid, published[1].Track.Sid)
+		require.Equal(t, 1, numPending(p))
+	})
+
+	t.Run("merge into published track", func(t *testing.T) {
+		p := newParticipant(config.DuplicatePublishPolicyMerge)
+
+		track := &typesfakes.FakeLocalMediaTrack{}
+		track.SignalCidReturns("cid1")
+		track.ToProtoReturns(&livekit.TrackInfo{Sid: "TR_camera", Name: "camera", Source: livekit.TrackSource_CAMERA})
+		// directly add to publishedTracks without lock - for testing purpose only
+		p.UpTrackManager.publishedTracks["TR_camera"] = track
+
+		addCamera(p, "cid2", "camera")
+		published := publishedTracks(p)
+		require.Len(t, published, 1)
+		require.Equal(t, "cid2", published[0].Cid)
+		require.Equal(t, "TR_camera", published[0].Track.Sid)
+		require.Zero(t, numPending(p))
+
+		// re-publishing the same cid is not a duplicate, it is queued as before
+		addCamera(p, "cid1", "camera")
+		require.Len(t, publishedTracks(p), 1)
+		require.Equal(t, 1, numPending(p))
+	})
+}
+
+func TestDeferredReliableData(t *testing.T) {
+	p := newParticipantForTest("test")
+	p.params.DataChannelLowBufferedAmount = 1024
+	p.updateState(livekit.ParticipantInfo_ACTIVE)
+	require.False(t, p.IsDataChannelBackpressured(livekit.DataPacket_RELIABLE))
+
+	// held back data is sent first, later packets queue behind it to keep order
+	p.deferredReliableData = [][]byte{[]byte("first")}
+	require.NoError(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, []byte("second")))
+	require.Equal(t, [][]byte{[]byte("first"), []byte("second")}, p.deferredReliableData)
+
+	// lossy is not held back
+	require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_LOSSY, []byte("lossy")), ErrDataChannelSendFailed)
+
+	for len(p.deferredReliableData) < maxDeferredReliableDataPackets {
+		p.deferredReliableData = append(p.deferredReliableData, []byte("data"))
+	}
+	require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, []byte("dropped")), ErrDataChannelBufferFull)
+
+	// drained, held back data is flushed
+	callbackKind := make(chan livekit.DataPacket_Kind, 1)
+	p.OnDataChannelBufferedAmountLow(func(_ types.LocalParticipant, kind livekit.DataPacket_Kind) {
+		callbackKind <- kind
+	})
+	p.handleDataChannelBufferedAmountLow(livekit.DataPacket_RELIABLE)
+	select {
+	case kind := <-callbackKind:
+		require.Equal(t, livekit.DataPacket_RELIABLE, kind)
+	case <-time.After(time.Second):
+		require.Fail(t, "buffered amount low callback not invoked")
+	}
+
+	// without a data channel sending fails, packets are kept in order to be retried
+	p.reliableDataLock.Lock()
+	require.Len(t, p.deferredReliableData, maxDeferredReliableDataPackets)
+	require.Equal(t, []byte("first"), p.deferredReliableData[0])
+	require.NotNil(t, p.reliableDataFlushTimer)
+	p.reliableDataLock.Unlock()
+
+	// dropped when participant is no longer active
+	p.updateState(livekit.ParticipantInfo_DISCONNECTED)
+	p.flushDeferredReliableData()
+	p.reliableDataLock.Lock()
+	require.Empty(t, p.deferredReliableData)
+	p.reliableDataLock.Unlock()
+}
+
+func TestUnacknowledgedReliableData(t *testing.T) {
+	t.Run("not kept", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.updateState(livekit.ParticipantInfo_ACTIVE)
+
+		require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, []byte("data")), ErrDataChannelSendFailed)
+		p.IssueFullReconnect(types.ParticipantCloseReasonDataChannelError)
+		require.Empty(t, p.UnacknowledgedReliableData())
+	})
+
+	t.Run("kept on full reconnect", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.reliableDataHistory = newReliableDataHistory(10, 0)
+		p.updateState(livekit.ParticipantInfo_ACTIVE)
+
+		// nothing buffered on data channel, written data has been acknowledged
+		p.reliableDataHistory.add([]byte("written"), true)
+		p.reliableDataHistory.add([]byte("not written"), false)
+		p.deferredReliableData = [][]byte{[]byte("held")}
+		require.Empty(t, p.UnacknowledgedReliableData())
+
+		p.IssueFullReconnect(types.ParticipantCloseReasonDataChannelError)
+		require.Equal(t, [][]byte{[]byte("not written"), []byte("held")}, p.UnacknowledgedReliableData())
+	})
+
+	t.Run("pending sent before active", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.DataChannelLowBufferedAmount = 1024
+		p.pendingReliableData = [][]byte{[]byte("first"), []byte("second")}
+
+		// queued behind held back data
+		p.deferredReliableData = [][]byte{[]byte("held")}
+		p.onPrimaryTransportFullyEstablished()
+		require.Equal(t, livekit.ParticipantInfo_ACTIVE, p.State())
+		require.Equal(t, [][]byte{[]byte("held"), []byte("first"), []byte("second")}, p.deferredReliableData)
+
+		// sent once
+		p.onPrimaryTransportFullyEstablished()
+		require.Len(t, p.deferredReliableData, 3)
+	})
+}
+
+func TestReliableDataRate(t *testing.T) {
+	newParticipant := func(queue bool) *ParticipantImpl {
+		p := newParticipantForTest("test")
+		p.params.MaxReliableDataRate = 1000
+		p.params.QueueReliableDataAboveRate = queue
+		p.reliableDataBucket = newTokenBucket(p.params.MaxReliableDataRate, 0)
+		p.updateState(livekit.ParticipantInfo_ACTIVE)
+		return p
+	}
+	numDeferred := func(p *ParticipantImpl) int {
+		p.reliableDataLock.Lock()
+		defer p.reliableDataLock.Unlock()
+		return len(p.deferredReliableData)
+	}
+
+	t.Run("rejects above rate", func(t *testing.T) {
+		p := newParticipant(false)
+
+		// below the rate, sent to data channel which is not connected in test
+		for i := 0; i < 2; i++ {
+			err := p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 400))
+			require.ErrorIs(t, err, ErrDataChannelSendFailed)
+		}
+
+		require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 400)), ErrReliableDataRateExceeded)
+		require.Zero(t, numDeferred(p))
+
+		// lossy is not limited
+		require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_LOSSY, make([]byte, 400)), ErrDataChannelSendFailed)
+
+		// within rate again after refill, a packet larger than the burst needs a full bucket
+		p.reliableDataLock.Lock()
+		p.reliableDataBucketAt = p.reliableDataBucketAt.Add(-time.Second)
+		p.reliableDataLock.Unlock()
+		require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 2000)), ErrDataChannelSendFailed)
+		require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 400)), ErrReliableDataRateExceeded)
+	})
+
+	t.Run("queues above rate", func(t *testing.T) {
+		p := newParticipant(true)
+
+		require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 900)), ErrDataChannelSendFailed)
+		require.NoError(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 200)))
+		require.NoError(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 100)))
+		require.Equal(t, 2, numDeferred(p))
+
+		// flushed once within the rate, data channel is not connected in test so packets are kept and retried
+		time.Sleep(500 * time.Millisecond)
+		require.Equal(t, 2, numDeferred(p))
+
+		// retries stop and held back data is dropped when participant is no longer active
+		p.updateState(livekit.ParticipantInfo_DISCONNECTED)
+		require.Eventually(t, func() bool {
+			return numDeferred(p) == 0
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("flush stopped on close", func(t *testing.T) {
+		p := newParticipant(true)
+
+		require.ErrorIs(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 900)), ErrDataChannelSendFailed)
+		require.NoError(t, p.SendDataPacket(livekit.DataPacket_RELIABLE, make([]byte, 200)))
+		p.reliableDataLock.Lock()
+		require.NotNil(t, p.reliableDataFlushTimer)
+		p.reliableDataLock.Unlock()
+
+		require.NoError(t, p.Close(false, types.ParticipantCloseReasonNone, false))
+		p.reliableDataLock.Lock()
+		require.Nil(t, p.reliableDataFlushTimer)
+		p.reliableDataLock.Unlock()
+		require.Zero(t, numDeferred(p))
+	})
+}
+
+func TestParticipantHeartbeat(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		defer p.Close(false, types.ParticipantCloseReasonNone, false)
+		telemetry := p.params.Telemetry.(*telemetryfakes.FakeTelemetryService)
+
+		time.Sleep(50 * time.Millisecond)
+		require.Zero(t, telemetry.ParticipantHeartbeatCallCount())
+	})
+
+	t.Run("sent periodically till close", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{heartbeat: 10 * time.Millisecond})
+		telemetry := p.params.Telemetry.(*telemetryfakes.FakeTelemetryService)
+
+		require.Eventually(t, func() bool {
+			return telemetry.ParticipantHeartbeatCallCount() >= 2
+		}, time.Second, 5*time.Millisecond)
+		_, participantID, identity, heartbeat := telemetry.ParticipantHeartbeatArgsForCall(0)
+		require.Equal(t, p.ID(), participantID)
+		require.Equal(t, p.Identity(), identity)
+		require.Equal(t, livekit.ParticipantInfo_ACTIVE, heartbeat.State)
+		require.Equal(t, types.MigrateStateInit.String(), heartbeat.MigrateState)
+		require.Equal(t, webrtc.PeerConnectionStateNew, heartbeat.SubscriberState)
+		require.Zero(t, heartbeat.NumPublishedTracks)
+		require.False(t, heartbeat.Congested)
+
+		require.NoError(t, p.Close(false, types.ParticipantCloseReasonNone, false))
+		numHeartbeats := telemetry.ParticipantHeartbeatCallCount()
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, numHeartbeats, telemetry.ParticipantHeartbeatCallCount())
+	})
+}
+
+func TestSetNameMetadataAfterClose(t *testing.T) {
+	p := newParticipantForTest("test")
+	numUpdates := 0
+	p.OnParticipantUpdate(func(_ types.LocalParticipant) {
+		numUpdates++
+	})
+	numClaimsChanges := 0
+	p.OnClaimsChanged(func(_ types.LocalParticipant) {
+		numClaimsChanges++
+	})
+
+	p.SetName("name")
+	p.SetMetadata("metadata")
+	require.Equal(t, 2, numUpdates)
+	require.Equal(t, 2, numClaimsChanges)
+
+	require.NoError(t, p.Close(false, types.ParticipantCloseReasonNone, false))
+	numUpdates, numClaimsChanges = 0, 0
+
+	p.SetName("new name")
+	p.SetMetadata("new metadata")
+	require.Zero(t, numUpdates)
+	require.Zero(t, numClaimsChanges)
+	require.Equal(t, "name", p.ClaimGrants().Name)
+	require.Equal(t, "metadata", p.ClaimGrants().Metadata)
+}
+
+func TestCorrectJoinedAt(t *testing.T) {
+	p := newParticipantForTest("test")
+	info := p.ToProto()
+	require.NotZero(t, info.JoinedAt)
+	require.True(t, time.Now().Unix()-info.JoinedAt <= 1)
+}
+
+func TestMuteSetting(t *testing.T) {
+	t.Run("can set mute when track is pending", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		ti := &livekit.TrackInfo{Sid: "testTrack"}
+		p.pendingTracks["cid"] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}}
+
+		p.SetTrackMuted(livekit.TrackID(ti.Sid), true, nil)
+		require.True(t, ti.Muted)
+	})
+
+	t.Run("can publish a muted track", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:   "cid",
+			Type:  livekit.TrackType_AUDIO,
+			Muted: true,
+		})
+
+		_, ti, _ := p.getPendingTrack("cid", livekit.TrackType_AUDIO)
+		require.NotNil(t, ti)
+		require.True(t, ti.Muted)
+	})
+
+	t.Run("notifies only when mute state flips", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		ti := &livekit.TrackInfo{Sid: "pendingTrack"}
+		p.pendingTracks["cid"] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}}
+
+		muted := false
+		track := &typesfakes.FakeLocalMediaTrack{}
+		track.IDReturns("publishedTrack")
+		track.IsMutedStub = func() bool { return muted }
+		track.SetMutedStub = func(m bool) { muted = m }
+		// directly add to publishedTracks without lock - for testing purpose only
+		p.UpTrackManager.publishedTracks["publishedTrack"] = track
+
+		type muteChange struct {
+			track types.MediaTrack
+			muted bool
+		}
+		var changes []muteChange
+		p.OnTrackMuteChanged(func(_ types.LocalParticipant, mt types.MediaTrack, muted bool) {
+			changes = append(changes, muteChange{track: mt, muted: muted})
+		})
+
+		p.SetTrackMuted("pendingTrack", true, nil)
+		p.SetTrackMuted("pendingTrack", true, nil)
+		require.Equal(t, []muteChange{{track: nil, muted: true}}, changes)
+
+		changes = nil
+		p.SetTrackMuted("publishedTrack", false, nil)
+		require.Empty(t, changes)
+		p.SetTrackMuted("publishedTrack", true, nil)
+		p.SetTrackMuted("publishedTrack", true, nil)
+		p.SetTrackMuted("publishedTrack", false, nil)
+		require.Equal(t, []muteChange{{track: track, muted: true}, {track: track, muted: false}}, changes)
+	})
+
+	t.Run("mutes all tracks with a single update", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		pending := &livekit.TrackInfo{Sid: "pendingTrack"}
+		p.pendingTracks["cid"] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{pending}}
+
+		for _, trackID := range []livekit.TrackID{"audio", "video"} {
+			muted := false
+			track := &typesfakes.FakeLocalMediaTrack{}
+			track.IDReturns(trackID)
+			track.IsMutedStub = func() bool { return muted }
+			track.SetMutedStub = func(m bool) { muted = m }
+			track.ToProtoReturns(&livekit.TrackInfo{Sid: string(trackID), Muted: true})
+			// directly add to publishedTracks without lock - for testing purpose only
+			p.UpTrackManager.publishedTracks[trackID] = track
+		}
+
+		numUpdates := 0
+		p.OnParticipantUpdate(func(_ types.LocalParticipant) {
+			numUpdates++
+		})
+		numTrackUpdates := 0
+		p.OnTrackUpdated(func(_ types.LocalParticipant, _ types.MediaTrack) {
+			numTrackUpdates++
+		})
+
+		trackInfos := p.SetAllTracksMuted(true, &types.AdminActionOptions{Actor: "admin"})
+		require.Len(t, trackInfos, 3)
+		require.True(t, pending.Muted)
+		for _, track := range p.GetPublishedTracks() {
+			require.True(t, track.IsMuted())
+		}
+		require.Equal(t, 1, numUpdates)
+		require.Zero(t, numTrackUpdates)
+
+		// client is asked to mute each track, one track per mute request
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		require.Equal(t, 3, sink.WriteMessageCallCount())
+		var mutedTrackIDs []string
+		for i := 0; i < sink.WriteMessageCallCount(); i++ {
+			mute := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse).GetMute()
+			require.NotNil(t, mute)
+			require.True(t, mute.Muted)
+			mutedTrackIDs = append(mutedTrackIDs, mute.Sid)
+		}
+		require.ElementsMatch(t, []string{"audio", "video", "pendingTrack"}, mutedTrackIDs)
+		require.Len(t, p.GetAdminAuditLog(), 3)
+
+		// no change, no update
+		require.Empty(t, p.SetAllTracksMuted(true, nil))
+		require.Equal(t, 1, numUpdates)
+	})
+}
+
+func TestGetBitrateSummary(t *testing.T) {
+	p := newParticipantForTest("test")
+
+	audio := &typesfakes.FakeLocalMediaTrack{}
+	audio.IDReturns("audio")
+	audio.KindReturns(livekit.TrackType_AUDIO)
+	audio.GetBitrateReturns(32_000)
+
+	receiver := &bitrateTestReceiver{mimeType: webrtc.MimeTypeVP8}
+	receiver.bitrates[0] = [4]int64{100_000, 150_000, 0, 0}
+	receiver.bitrates[1] = [4]int64{500_000, 0, 0, 0}
+	video := &typesfakes.FakeLocalMediaTrack{}
+	video.IDReturns("video")
+	video.KindReturns(livekit.TrackType_VIDEO)
+	video.ReceiversReturns([]sfu.TrackReceiver{receiver})
+
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["audio"] = audio
+	p.UpTrackManager.publishedTracks["video"] = video
+
+	summary := p.GetBitrateSummary()
+	require.Equal(t, int64(682_000), summary.PublishBps)
+	require.Zero(t, summary.SubscribeBps)
+	require.Equal(t, types.TrackBitrate{Kind: livekit.TrackType_AUDIO, Bps: 32_000}, summary.Tracks["audio"])
+	require.Equal(t, types.TrackBitrate{Kind: livekit.TrackType_VIDEO, Bps: 650_000}, summary.Tracks["video"])
+}
+
+func TestSetPLIThrottleConfig(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.IDReturns("video")
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["video"] = track
+
+	pliThrottleConfig := config.PLIThrottleConfig{
+		LowQuality:  200 * time.Millisecond,
+		MidQuality:  400 * time.Millisecond,
+		HighQuality: 800 * time.Millisecond,
+	}
+	p.SetPLIThrottleConfig(pliThrottleConfig)
+
+	// published tracks are updated in place
+	require.Equal(t, 1, track.SetPLIThrottleConfigCallCount())
+	require.Equal(t, pliThrottleConfig, track.SetPLIThrottleConfigArgsForCall(0))
+
+	// tracks published later use the updated config
+	p.AddTrack(&livekit.AddTrackRequest{Cid: "cid", Type: livekit.TrackType_VIDEO})
+	_, ti, _ := p.getPendingTrack("cid", livekit.TrackType_VIDEO)
+	require.NotNil(t, ti)
+	mt := p.addMediaTrack("cid", "cid", ti)
+	require.Equal(t, pliThrottleConfig, mt.params.PLIThrottleConfig)
+}
+
+func TestRepublishSignalOrder(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: 9, publisher: true})
+	sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+	p.AddTrack(&livekit.AddTrackRequest{Cid: "cid", Type: livekit.TrackType_AUDIO, Source: livekit.TrackSource_MICROPHONE})
+	_, ti, _ := p.getPendingTrack("cid", livekit.TrackType_AUDIO)
+	require.NotNil(t, ti)
+	mt := p.addMediaTrack("cid", "cid", ti)
+
+	expected := []string{"published " + ti.Sid}
+	for i := 0; i < 100; i++ {
+		// re-publish with the same cid is queued while the track is published
+		p.AddTrack(&livekit.AddTrackRequest{Cid: "cid", Type: livekit.TrackType_AUDIO, Source: livekit.TrackSource_MICROPHONE})
+		_, next, _ := p.getPendingTrack("cid", livekit.TrackType_AUDIO)
+		require.NotNil(t, next)
+
+		// closing the track announces the queued one, which has to come after unpublish of the closed one
+		p.removePublishedTrack(mt, 0)
+		expected = append(expected, "unpublished "+string(mt.ID()), "published "+next.Sid)
+
+		mt = p.addMediaTrack("cid", "cid", next)
+	}
+
+	var signals []string
+	for i := 0; i < sink.WriteMessageCallCount(); i++ {
+		msg := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse)
+		switch {
+		case msg.GetTrackPublished() != nil:
+			require.Equal(t, "cid", msg.GetTrackPublished().Cid)
+			signals = append(signals, "published "+msg.GetTrackPublished().Track.Sid)
+		case msg.GetTrackUnpublished() != nil:
+			signals = append(signals, "unpublished "+msg.GetTrackUnpublished().TrackSid)
+		}
+	}
+	require.Equal(t, expected, signals)
+	require.Empty(t, p.unpublishingCids)
+}
+
+func TestPauseTrackForwarding(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.IDReturns("video")
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["video"] = track
+
+	require.ErrorIs(t, p.PauseTrackForwarding("unknown"), ErrTrackNotFound)
+
+	require.NoError(t, p.PauseTrackForwarding("video"))
+	require.Equal(t, 1, track.SetModeratedCallCount())
+	require.True(t, track.SetModeratedArgsForCall(0))
+
+	// already paused
+	track.IsModeratedReturns(true)
+	require.NoError(t, p.PauseTrackForwarding("video"))
+	require.Equal(t, 1, track.SetModeratedCallCount())
+
+	require.NoError(t, p.ResumeTrackForwarding("video"))
+	require.Equal(t, 2, track.SetModeratedCallCount())
+	require.False(t, track.SetModeratedArgsForCall(1))
+}
+
+func TestHasActiveMedia(t *testing.T) {
+	t.Run("no published tracks", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+		require.False(t, p.HasActiveMedia())
+	})
+
+	t.Run("muted only publisher", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+		for _, trackID := range []livekit.TrackID{"audio", "video"} {
+			track := &typesfakes.FakeLocalMediaTrack{}
+			track.IDReturns(trackID)
+			track.IsMutedReturns(true)
+			track.LastPacketAtReturns(time.Now())
+			// directly add to publishedTracks without lock - for testing purpose only
+			p.UpTrackManager.publishedTracks[trackID] = track
+		}
+		require.False(t, p.HasActiveMedia())
+	})
+
+	t.Run("actively flowing publisher", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+		muted := &typesfakes.FakeLocalMediaTrack{}
+		muted.IsMutedReturns(true)
+		muted.LastPacketAtReturns(time.Now())
+		p.UpTrackManager.publishedTracks["audio"] = muted
+
+		video := &typesfakes.FakeLocalMediaTrack{}
+		p.UpTrackManager.publishedTracks["video"] = video
+
+		// unmuted, but no packets yet
+		require.False(t, p.HasActiveMedia())
+
+		video.LastPacketAtReturns(time.Now())
+		require.True(t, p.HasActiveMedia())
+
+		// packets stopped flowing
+		video.LastPacketAtReturns(time.Now().Add(-2 * activeMediaTimeout))
+		require.False(t, p.HasActiveMedia())
+
+		// paused tracks do not count
+		video.LastPacketAtReturns(time.Now())
+		video.IsPausedReturns(true)
+		require.False(t, p.HasActiveMedia())
+	})
+}
+
+func TestSenderReportParams(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		require.Equal(t, defaultSenderReportInterval, p.params.SenderReportInterval)
+		require.Equal(t, defaultSenderReportBatchSize, p.params.SenderReportBatchSize)
+	})
+
+	t.Run("below minimum", func(t *testing.T) {
+		interval, batchSize := validateSenderReportParams(logger.GetLogger(), time.Millisecond, -1)
+		require.Equal(t, minSenderReportInterval, interval)
+		require.Equal(t, minSenderReportBatchSize, batchSize)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		interval, batchSize := validateSenderReportParams(logger.GetLogger(), 5*time.Second, 10)
+		require.Equal(t, 5*time.Second, interval)
+		require.Equal(t, 10, batchSize)
+	})
+}
+
+func TestMigrationTiming(t *testing.T) {
+	t.Run("default wait duration", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		require.Equal(t, defaultMigrationWaitDuration, p.params.MigrationWaitDuration)
+		require.NotContains(t, p.DebugInfo(), "Migration")
+	})
+
+	t.Run("migrated in", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{migration: true})
+		require.False(t, p.migrationStartedAt.IsZero())
+		require.True(t, p.migrationCompletedAt.IsZero())
+
+		p.SetMigrateState(types.MigrateStateComplete)
+		require.False(t, p.migrationCompletedAt.IsZero())
+
+		migrationInfo := p.DebugInfo()["Migration"].(map[string]interface{})
+		require.Contains(t, migrationInfo, "Duration")
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.MigrationWaitDuration = 10 * time.Millisecond
+
+		var timedOut atomic.Bool
+		p.OnMigrationTimeout(func(participant types.LocalParticipant) {
+			require.Equal(t, types.LocalParticipant(p), participant)
+			timedOut.Store(true)
+		})
+		p.NotifyMigration()
+
+		require.Eventually(t, timedOut.Load, time.Second, 5*time.Millisecond)
+	})
+}
+
+func TestManualMigrationLevers(t *testing.T) {
+	t.Run("no migration", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.SetMigrateState(types.MigrateStateComplete)
+		require.ErrorIs(t, p.ForceCompleteMigration(), ErrNoMigrationInProgress)
+		require.ErrorIs(t, p.AbortMigration(types.ParticipantCloseReasonMigrationRequested), ErrNoMigrationInProgress)
+		require.False(t, p.IsClosed())
+	})
+
+	t.Run("force complete", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{migration: true})
+		p.SetMigrateState(types.MigrateStateSync)
+		p.NotifyMigration()
+
+		require.NoError(t, p.ForceCompleteMigration())
+		require.Equal(t, types.MigrateStateComplete, p.MigrateState())
+		require.Nil(t, p.migrationTimer)
+		require.False(t, p.migrationCompletedAt.IsZero())
+
+		require.ErrorIs(t, p.ForceCompleteMigration(), ErrNoMigrationInProgress)
+	})
+
+	t.Run("force complete processes pending publisher offer", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{migration: true})
+		require.Equal(t, types.MigrateStateInit, p.MigrateState())
+
+		pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+		require.NoError(t, err)
+		defer pc.Close()
+		_, err = pc.CreateDataChannel("test", nil)
+		require.NoError(t, err)
+		offer, err := pc.CreateOffer(nil)
+		require.NoError(t, err)
+
+		p.TransportManager.HandleOffer(offer, true)
+		require.Empty(t, p.TransportManager.LastPublisherOffer().SDP)
+
+		require.NoError(t, p.ForceCompleteMigration())
+		require.Equal(t, types.MigrateStateComplete, p.MigrateState())
+		require.Equal(t, offer.SDP, p.TransportManager.LastPublisherOffer().SDP)
+	})
+
+	t.Run("abort migrating in", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{migration: true})
+		p.SetMigrateState(types.MigrateStateSync)
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		require.NoError(t, p.AbortMigration(types.ParticipantCloseReasonMigrationRequested))
+		require.True(t, p.IsClosed())
+		require.Equal(t, types.ParticipantCloseReasonMigrationRequested, p.CloseReason())
+		require.Equal(t, types.MigrateStateSync, p.MigrateState())
+
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+		res := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse)
+		require.IsType(t, &livekit.SignalResponse_Leave{}, res.Message)
+
+		require.ErrorIs(t, p.AbortMigration(types.ParticipantCloseReasonMigrationRequested), ErrNoMigrationInProgress)
+	})
+
+	t.Run("abort migrating out", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.SetMigrateState(types.MigrateStateComplete)
+		p.NotifyMigration()
+
+		require.NoError(t, p.AbortMigration(types.ParticipantCloseReasonMigrationRequested))
+		require.True(t, p.IsClosed())
+		require.Nil(t, p.migrationTimer)
+	})
+}
+
+func TestPendingICECandidates(t *testing.T) {
+	candidate := func(port uint16) *webrtc.ICECandidate {
+		return &webrtc.ICECandidate{
+			Foundation: "foundation",
+			Priority:   1,
+			Address:    "10.0.0.1",
+			Protocol:   webrtc.ICEProtocolUDP,
+			Port:       port,
+			Typ:        webrtc.ICECandidateTypeHost,
+			Component:  1,
+		}
+	}
+	trickles := func(sink *routingfakes.FakeMessageSink) []*livekit.TrickleRequest {
+		var trickles []*livekit.TrickleRequest
+		for i := 0; i < sink.WriteMessageCallCount(); i++ {
+			if res, ok := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse); ok && res.GetTrickle() != nil {
+				trickles = append(trickles, res.GetTrickle())
+			}
+		}
+		return trickles
+	}
+
+	t.Run("dropped when buffering disabled", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		require.NoError(t, p.onICECandidate(candidate(5000), livekit.SignalTarget_SUBSCRIBER))
+		p.SetMigrateState(types.MigrateStateSync)
+		require.Empty(t, trickles(sink))
+	})
+
+	t.Run("buffered till migration syncs", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{maxPendingICE: 2})
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		for port := uint16(5000); port < 5003; port++ {
+			require.NoError(t, p.onICECandidate(candidate(port), livekit.SignalTarget_SUBSCRIBER))
+		}
+		// publisher candidates are not held back
+		require.NoError(t, p.onICECandidate(candidate(6000), livekit.SignalTarget_PUBLISHER))
+		require.Len(t, trickles(sink), 1)
+
+		// buffered candidates up to limit flushed in order when migration advances past init
+		p.SetMigrateState(types.MigrateStateSync)
+		sent := trickles(sink)
+		require.Len(t, sent, 3)
+		require.Equal(t, livekit.SignalTarget_SUBSCRIBER, sent[1].Target)
+		require.Contains(t, sent[1].CandidateInit, "5000")
+		require.Contains(t, sent[2].CandidateInit, "5001")
+
+		// sent directly after migration syncs
+		require.NoError(t, p.onICECandidate(candidate(5003), livekit.SignalTarget_SUBSCRIBER))
+		require.Len(t, trickles(sink), 4)
+	})
+}
+
+type sendRatePacer struct {
+	pacer.Pacer
+	sendRate int64
+}
+
+func (s *sendRatePacer) GetSendRate() int64 {
+	return s.sendRate
+}
+
+func TestGetPacerSendRate(t *testing.T) {
+	p := newParticipantForTest("test")
+	require.Zero(t, p.GetPacerSendRate())
+
+	p.TransportManager.subscriber.pacer = &sendRatePacer{sendRate: 1_500_000}
+	require.Equal(t, int64(1_500_000), p.GetPacerSendRate())
+}
+
+func TestRTTHistory(t *testing.T) {
+	p := newParticipantForTest("test")
+	p.params.RTTHistorySize = 3
+	p.rttUpdatedAt = time.Time{}
+	require.Empty(t, p.GetRTTHistory())
+
+	// every distinct sample is recorded, though updates to transport are throttled
+	for _, rtt := range []uint32{10, 10, 20, 30, 30, 40} {
+		p.UpdateMediaRTT(rtt)
+	}
+	_, mediaRTT := p.TransportManager.GetRTT()
+	require.Equal(t, uint32(10), mediaRTT)
+
+	history := p.GetRTTHistory()
+	require.Len(t, history, 3)
+	for idx, rtt := range []uint32{20, 30, 40} {
+		require.Equal(t, rtt, history[idx].RTT)
+		if idx != 0 {
+			require.False(t, history[idx].At.Before(history[idx-1].At))
+		}
+	}
+
+	// returns a copy
+	history[0].RTT = 0
+	require.Equal(t, uint32(20), p.GetRTTHistory()[0].RTT)
+
+	// defaults when size is not set
+	p = newParticipantForTest("test")
+	for rtt := uint32(1); rtt <= 20; rtt++ {
+		p.UpdateMediaRTT(rtt)
+	}
+	history = p.GetRTTHistory()
+	require.Len(t, history, defaultRTTHistorySize)
+	require.Equal(t, uint32(20), history[len(history)-1].RTT)
+}
+
+func TestPacerSettings(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{trafficLoad: true})
+	require.False(t, p.GetPacerSettings().IsCustom())
+
+	p.SetPacerInterval(10 * time.Millisecond)
+	p.SetPacerBurstLimit(-1)
+	require.Equal(t, types.PacerSettings{Interval: 10 * time.Millisecond}, p.GetPacerSettings())
+
+	p.SetPacerBurstLimit(20_000)
+	require.True(t, p.GetPacerSettings().IsCustom())
+	require.Equal(t, types.PacerSettings{Interval: 10 * time.Millisecond, BurstLimit: 20_000}, p.ParticipantTrafficLoad.updateTrafficLoad().PacerSettings)
+
+	p.SetPacerInterval(0)
+	p.SetPacerBurstLimit(0)
+	require.False(t, p.ParticipantTrafficLoad.updateTrafficLoad().PacerSettings.IsCustom())
+}
+
+func TestSetTrackPaused(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.IDReturns("video")
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["video"] = track
+
+	updated := 0
+	p.OnTrackUpdated(func(_ types.LocalParticipant, _ types.MediaTrack) {
+		updated++
+	})
+
+	require.ErrorIs(t, p.SetTrackPaused("unknown", true), ErrTrackNotFound)
+
+	p.dirty.Store(false)
+	require.NoError(t, p.SetTrackPaused("video", true))
+	require.Equal(t, 1, track.SetPausedCallCount())
+	require.True(t, track.SetPausedArgsForCall(0))
+	require.Equal(t, 1, updated)
+	require.True(t, p.dirty.Load())
+	require.Equal(t, 0, track.SetMutedCallCount())
+
+	// already paused
+	track.IsPausedReturns(true)
+	require.NoError(t, p.SetTrackPaused("video", true))
+	require.Equal(t, 1, track.SetPausedCallCount())
+	require.Equal(t, 1, updated)
+
+	require.NoError(t, p.SetTrackPaused("video", false))
+	require.Equal(t, 2, track.SetPausedCallCount())
+	require.False(t, track.SetPausedArgsForCall(1))
+	require.Equal(t, 2, updated)
+}
+
+func TestTrackPauseRequest(t *testing.T) {
+	newPublisher := func(protocolVersion types.ProtocolVersion) (*ParticipantImpl, *typesfakes.FakeLocalMediaTrack) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true, protocolVersion: protocolVersion})
+		track := &typesfakes.FakeLocalMediaTrack{}
+		track.IDReturns("video")
+		// directly add to publishedTracks without lock - for testing purpose only
+		p.UpTrackManager.publishedTracks["video"] = track
+		return p, track
+	}
+
+	t.Run("ignored for clients without support", func(t *testing.T) {
+		p, track := newPublisher(13)
+		p.HandleTrackPauseRequest("video", true)
+		require.Zero(t, track.SetPausedCallCount())
+	})
+
+	t.Run("pauses and resumes", func(t *testing.T) {
+		p, track := newPublisher(types.CurrentProtocol)
+		p.HandleTrackPauseRequest("video", true)
+		require.Equal(t, 1, track.SetPausedCallCount())
+		require.True(t, track.SetPausedArgsForCall(0))
+
+		track.IsPausedReturns(true)
+		p.HandleTrackPauseRequest("video", false)
+		require.Equal(t, 2, track.SetPausedCallCount())
+		require.False(t, track.SetPausedArgsForCall(1))
+
+		// unknown tracks are ignored
+		p.HandleTrackPauseRequest("unknown", true)
+		require.Equal(t, 2, track.SetPausedCallCount())
+	})
+}
+
+func TestPublishedTrackNotFoundErrors(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.IDReturns("video")
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["video"] = track
+
+	require.ErrorIs(t, p.UpdateSubscribedQuality("node", "unknown", nil), ErrTrackNotFound)
+	require.ErrorIs(t, p.UpdateMediaLoss("node", "unknown", 10), ErrTrackNotFound)
+	require.ErrorIs(t, p.UpdateAudioTrack(&livekit.UpdateLocalAudioTrack{TrackSid: "unknown"}), ErrTrackNotFound)
+	require.ErrorIs(t, p.UpdateVideoTrack(&livekit.UpdateLocalVideoTrack{TrackSid: "unknown"}), ErrTrackNotFound)
+
+	require.NoError(t, p.UpdateSubscribedQuality("node", "video", nil))
+	require.Equal(t, 1, track.NotifySubscriberNodeMaxQualityCallCount())
+	require.NoError(t, p.UpdateMediaLoss("node", "video", 10))
+	require.Equal(t, 1, track.NotifySubscriberNodeMediaLossCallCount())
+}
+
+func TestAvailableLayersChanged(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+
+	var gotTrackID livekit.TrackID
+	var gotLayers []int32
+	p.OnAvailableLayersChanged(func(participant types.LocalParticipant, trackID livekit.TrackID, availableLayers []int32) {
+		require.Equal(t, p, participant)
+		gotTrackID = trackID
+		gotLayers = availableLayers
+	})
+
+	mt := NewMediaTrack(MediaTrackParams{Logger: p.GetLogger()}, &livekit.TrackInfo{Sid: "video", Type: livekit.TrackType_VIDEO})
+	mt.OnAvailableLayersChanged(p.onPublishedTrackAvailableLayersChanged)
+
+	// layer stopped
+	mt.onAvailableLayersChange([]int32{0, 1})
+	require.Equal(t, livekit.TrackID("video"), gotTrackID)
+	require.Equal(t, []int32{0, 1}, gotLayers)
+
+	// all layers stopped
+	mt.onAvailableLayersChange([]int32{})
+	require.Empty(t, gotLayers)
+}
+
+func TestDebugInfoTrackStats(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+
+	video := &typesfakes.FakeLocalMediaTrack{}
+	video.IDReturns("video")
+	video.GetTrackStatsReturns(&livekit.RTPStats{
+		Packets:       1000,
+		PacketsLost:   10,
+		JitterCurrent: 2.5,
+	})
+	// muted before any media, no stats
+	audio := &typesfakes.FakeLocalMediaTrack{}
+	audio.IDReturns("audio")
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["video"] = video
+	p.UpTrackManager.publishedTracks["audio"] = audio
+
+	stats := p.DebugInfo()["PublishedTrackStats"].(map[livekit.TrackID]interface{})
+	require.Len(t, stats, 2)
+	videoStats := stats["video"].(map[string]interface{})
+	require.Equal(t, uint32(10), videoStats["PacketsLost"])
+	require.Equal(t, 2.5, videoStats["JitterCurrent"])
+	require.Nil(t, stats["audio"])
+}
+
+func TestSetMaxSpatialLayerForSource(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{publisher: true})
+
+	screenShare := &typesfakes.FakeLocalMediaTrack{}
+	screenShare.IDReturns("screen")
+	screenShare.KindReturns(livekit.TrackType_VIDEO)
+	screenShare.SourceReturns(livekit.TrackSource_SCREEN_SHARE)
+	screenShare.SetMaxSubscriberSpatialLayerReturns(1)
+	camera := &typesfakes.FakeLocalMediaTrack{}
+	camera.IDReturns("camera")
+	camera.KindReturns(livekit.TrackType_VIDEO)
+	camera.SourceReturns(livekit.TrackSource_CAMERA)
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["screen"] = screenShare
+	p.UpTrackManager.publishedTracks["camera"] = camera
+
+	maxLayers := p.SetMaxSpatialLayerForSource(livekit.TrackSource_SCREEN_SHARE, 1)
+	require.Equal(t, map[livekit.TrackID]int32{"screen": 1}, maxLayers)
+	require.Equal(t, 1, screenShare.SetMaxSubscriberSpatialLayerCallCount())
+	require.Equal(t, int32(1), screenShare.SetMaxSubscriberSpatialLayerArgsForCall(0))
+	require.Zero(t, camera.SetMaxSubscriberSpatialLayerCallCount())
+
+	// tracks of the source published later are capped
+	p.AddTrack(&livekit.AddTrackRequest{Cid: "cid", Type: livekit.TrackType_VIDEO, Source: livekit.TrackSource_SCREEN_SHARE})
+	_, ti, _ := p.getPendingTrack("cid", livekit.TrackType_VIDEO)
+	require.NotNil(t, ti)
+	mt := p.addMediaTrack("cid", "cid", ti)
+	require.Equal(t, int32(1), mt.GetMaxSubscriberSpatialLayer())
+
+	// removing the cap applies to published tracks and is not persisted
+	p.SetMaxSpatialLayerForSource(livekit.TrackSource_SCREEN_SHARE, buffer.InvalidLayerSpatial)
+	require.Equal(t, buffer.InvalidLayerSpatial, mt.GetMaxSubscriberSpatialLayer())
+	require.Empty(t, p.maxSpatialLayerBySource)
+}
+
+func TestUnpublishDrain(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{
+		protocolVersion: types.CurrentProtocol,
+		publisher:       true,
+		permissions:     &livekit.ParticipantPermission{CanPublish: true},
+	})
+	p.updateState(livekit.ParticipantInfo_ACTIVE)
+	sink := p.getResponseSink().(*routingfakes.FakeMessageSink)
+
+	newTrack := func(trackID livekit.TrackID, signalCid string) *typesfakes.FakeLocalMediaTrack {
+		track := &typesfakes.FakeLocalMediaTrack{}
+		track.IDReturns(trackID)
+		track.SignalCidReturns(signalCid)
+		// directly add to publishedTracks without lock - for testing purpose only
+		p.UpTrackManager.publishedTracks[trackID] = track
+		return track
+	}
+
+	unpublishedSent := func(trackID livekit.TrackID) bool {
+		for i := 0; i < sink.WriteMessageCallCount(); i++ {
+			res := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse)
+			if res.GetTrackUnpublished().GetTrackSid() == string(trackID) {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("closed after drain", func(t *testing.T) {
+		track := newTrack("audio", "audio_cid")
+		p.removePublishedTrack(track, 50*time.Millisecond)
+		require.Zero(t, track.CloseCallCount())
+		require.NotNil(t, p.GetPublishedTrack("audio"))
+		require.False(t, unpublishedSent("audio"))
+
+		require.Eventually(t, func() bool {
+			return track.CloseCallCount() == 1 && p.GetPublishedTrack("audio") == nil
+		}, time.Second, 10*time.Millisecond)
+		require.True(t, unpublishedSent("audio"))
+	})
+
+	t.Run("cancelled on re-publish", func(t *testing.T) {
+		track := newTrack("video", "video_cid")
+		p.removePublishedTrack(track, time.Hour)
+		require.Zero(t, track.CloseCallCount())
+		require.False(t, unpublishedSent("video"))
+
+		p.AddTrack(&livekit.AddTrackRequest{Cid: "video_cid", Type: livekit.TrackType_VIDEO, Name: "video"})
+		require.Equal(t, 1, track.CloseCallCount())
+		require.Nil(t, p.GetPublishedTrack("video"))
+		require.True(t, unpublishedSent("video"))
+		require.False(t, p.cancelTrackDrain("video_cid"))
+	})
+
+	t.Run("no drain", func(t *testing.T) {
+		track := newTrack("screen", "screen_cid")
+		p.removePublishedTrack(track, 0)
+		require.Equal(t, 1, track.CloseCallCount())
+		require.True(t, unpublishedSent("screen"))
+	})
+}
+
+func TestConnectionQualityLostForOldClients(t *testing.T) {
+	getQuality := func(protocolVersion types.ProtocolVersion) livekit.ConnectionQuality {
+		p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: protocolVersion})
+
+		track := &typesfakes.FakeLocalMediaTrack{}
+		track.IDReturns("track")
+		track.GetConnectionScoreAndQualityReturns(0, livekit.ConnectionQuality_LOST)
+		// directly add to publishedTracks without lock - for testing purpose only
+		p.UpTrackManager.publishedTracks["track"] = track
+
+		return p.GetConnectionQuality().Quality
+	}
+
+	require.Equal(t, livekit.ConnectionQuality_LOST, getQuality(12))
+	require.Equal(t, livekit.ConnectionQuality_POOR, getQuality(10))
+}
+
+func TestConnectionQualityChange(t *testing.T) {
+	t.Run("settles after duration", func(t *testing.T) {
+		c := newConnectionQualityChange()
+		now := time.Now()
+
+		require.False(t, c.update(livekit.ConnectionQuality_EXCELLENT, now))
+
+		require.False(t, c.update(livekit.ConnectionQuality_GOOD, now))
+		require.False(t, c.update(livekit.ConnectionQuality_GOOD, now.Add(connectionQualitySettleDuration/2)))
+		require.True(t, c.update(livekit.ConnectionQuality_GOOD, now.Add(connectionQualitySettleDuration)))
+
+		// staying at the same level does not fire again
+		require.False(t, c.update(livekit.ConnectionQuality_GOOD, now.Add(2*connectionQualitySettleDuration)))
+	})
+
+	t.Run("flapping restarts settling", func(t *testing.T) {
+		c := newConnectionQualityChange()
+		now := time.Now()
+
+		require.False(t, c.update(livekit.ConnectionQuality_POOR, now))
+		require.False(t, c.update(livekit.ConnectionQuality_GOOD, now.Add(connectionQualitySettleDuration/2)))
+		require.False(t, c.update(livekit.ConnectionQuality_POOR, now.Add(connectionQualitySettleDuration)))
+		require.False(t, c.update(livekit.ConnectionQuality_POOR, now.Add(3*connectionQualitySettleDuration/2)))
+		require.True(t, c.update(livekit.ConnectionQuality_POOR, now.Add(2*connectionQualitySettleDuration)))
+	})
+
+	t.Run("going back to reported level cancels pending", func(t *testing.T) {
+		c := newConnectionQualityChange()
+		now := time.Now()
+
+		require.False(t, c.update(livekit.ConnectionQuality_POOR, now))
+		require.False(t, c.update(livekit.ConnectionQuality_EXCELLENT, now.Add(connectionQualitySettleDuration/2)))
+		require.False(t, c.update(livekit.ConnectionQuality_EXCELLENT, now.Add(2*connectionQualitySettleDuration)))
+	})
+}
+
+func TestConnectionQualityChangedNotify(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: 10})
+	t.Cleanup(func() { p.Close(false, types.ParticipantCloseReasonNone, false) })
+
+	var notified atomic.Value
+	numNotified := atomic.Int32{}
+	p.OnConnectionQualityChanged(func(_ types.LocalParticipant, info *livekit.ConnectionQualityInfo) {
+		notified.Store(info.Quality)
+		numNotified.Inc()
+	})
+
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.IDReturns("track")
+	track.GetConnectionScoreAndQualityReturns(0, livekit.ConnectionQuality_LOST)
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["track"] = track
+
+	p.GetConnectionQuality()
+	p.GetConnectionQuality()
+	require.Zero(t, numNotified.Load())
+
+	// level persisted long enough
+	p.connectionQualityLock.Lock()
+	p.connectionQualityChange.pendingSince = time.Now().Add(-connectionQualitySettleDuration)
+	p.connectionQualityLock.Unlock()
+	p.GetConnectionQuality()
+	require.Eventually(t, func() bool { return numNotified.Load() == 1 }, time.Second, 10*time.Millisecond)
+	// old clients get LOST downgraded to POOR
+	require.Equal(t, livekit.ConnectionQuality_POOR, notified.Load())
+
+	// staying at the same level does not notify again
+	p.GetConnectionQuality()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(1), numNotified.Load())
+}
+
+func TestMigratedCapabilities(t *testing.T) {
+	// connection quality LOST is gated on capabilities, clients without it are sent POOR
+	connectionQualityAfterMigration := func(t *testing.T, opts *participantOpts) (*ParticipantImpl, livekit.ConnectionQuality) {
+		opts.protocolVersion = types.CurrentProtocol
+		source := newParticipantForTestWithOpts("test", opts)
+		capabilities := source.resolvedCapabilities()
+		require.True(t, capabilities[types.CapabilityConnectionQualityLost])
+		// as resolved by a source node running an older version during a rolling upgrade
+		capabilities[types.CapabilityConnectionQualityLost] = false
+
+		// client info is carried over, destination derives the same capabilities from protocol version
+		opts.migration = true
+		p := newParticipantForTestWithOpts("test", opts)
+		require.True(t, p.ProtocolVersion().SupportsConnectionQualityLost())
+		p.SetMigrateInfo(
+			&webrtc.SessionDescription{Type: webrtc.SDPTypeOffer},
+			&webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer},
+			[]*livekit.TrackPublishedResponse{
+				{
+					Cid:   "cid",
+					Track: &livekit.TrackInfo{Sid: "track", Type: livekit.TrackType_AUDIO},
+				},
+			},
+			nil,
+			capabilities,
+		)
+		require.NotNil(t, p.GetPendingTrack("track"))
+
+		track := &typesfakes.FakeLocalMediaTrack{}
+		track.IDReturns("track")
+		track.GetConnectionScoreAndQualityReturns(0, livekit.ConnectionQuality_LOST)
+		// directly add to publishedTracks without lock - for testing purpose only
+		p.UpTrackManager.publishedTracks["track"] = track
+
+		p.GetConnectionQuality()
+		return p, p.GetConnectionQuality().Quality
+	}
+
+	t.Run("prefers migrated capabilities by default", func(t *testing.T) {
+		p, quality := connectionQualityAfterMigration(t, &participantOpts{})
+		require.Equal(t, livekit.ConnectionQuality_POOR, quality)
+		require.True(t, p.hasCapability(types.CapabilityUnpublish, p.ProtocolVersion().SupportsUnpublish()))
+
+		// carried over on further migrations
+		require.False(t, p.resolvedCapabilities()[types.CapabilityConnectionQualityLost])
+		require.False(t, p.GetDiagnosticBundle().Capabilities[types.CapabilityConnectionQualityLost])
+	})
+
+	t.Run("derives capabilities when not preferring migrated", func(t *testing.T) {
+		p, quality := connectionQualityAfterMigration(t, &participantOpts{ignoreMigrated: true})
+		require.Equal(t, livekit.ConnectionQuality_LOST, quality)
+		require.True(t, p.resolvedCapabilities()[types.CapabilityConnectionQualityLost])
+	})
+
+	t.Run("derives capabilities without migration", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: types.CurrentProtocol})
+		require.True(t, p.hasCapability(types.CapabilityUnpublish, p.ProtocolVersion().SupportsUnpublish()))
+		require.True(t, p.resolvedCapabilities()[types.CapabilityConnectionQualityLost])
+	})
+}
+
+func TestBackfillMigratedTrackMid(t *testing.T) {
+	p := newParticipantForTest("test")
+	// migration payload of an older node, track infos without mid
+	tracks := []*livekit.TrackPublishedResponse{
+		{Cid: "audio_cid", Track: &livekit.TrackInfo{Sid: "audio", Type: livekit.TrackType_AUDIO}},
+		{Cid: "video_cid", Track: &livekit.TrackInfo{Sid: "video", Type: livekit.TrackType_VIDEO}},
+		{Cid: "screen_cid", Track: &livekit.TrackInfo{
+			Sid:    "screen",
+			Type:   livekit.TrackType_VIDEO,
+			Layers: []*livekit.VideoLayer{{Quality: livekit.VideoQuality_HIGH, Ssrc: 2222}},
+		}},
+		{Cid: "unknown_cid", Track: &livekit.TrackInfo{Sid: "unknown", Type: livekit.TrackType_VIDEO}},
+	}
+	// directly set migration state without negotiating previous session - for testing purpose only
+	for _, t := range tracks {
+		p.pendingTracks[t.Cid] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{t.Track}, migrated: true}
+	}
+	p.pendingTracksLock.Lock()
+	defer p.pendingTracksLock.Unlock()
+
+	// subscriber session descriptions of previous node are not used
+	p.TransportManager.SetMigrateInfo(
+		&webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: midTestSDP},
+		&webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: midTestSDP},
+		nil,
+	)
+	require.False(t, p.backfillMigratedTrackMid("audio_cid", p.pendingTracks["audio_cid"].trackInfos[0]))
+
+	p.TransportManager.lastPublisherOffer.Store(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: midTestSDP})
+
+	expectedMids := map[string]string{"audio_cid": "0", "video_cid": "1", "screen_cid": "2"}
+	for cid, mid := range expectedMids {
+		ti := p.pendingTracks[cid].trackInfos[0]
+		require.True(t, p.backfillMigratedTrackMid(cid, ti))
+		require.Equal(t, mid, ti.Mid)
+	}
+
+	// no mapping, migration of the track fails
+	ti := p.pendingTracks["unknown_cid"].trackInfos[0]
+	require.False(t, p.backfillMigratedTrackMid("unknown_cid", ti))
+	require.Empty(t, ti.Mid)
+	require.Nil(t, p.addMigratedTrack("unknown_cid", ti))
+}
+
+func TestSignalingRTTConnectionQuality(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: 12})
+	require.Equal(t, livekit.ConnectionQuality_EXCELLENT, p.GetConnectionQuality().Quality)
+
+	// measured by transport manager, for example from join to first answer
+	p.TransportManager.UpdateSignalingRTT(300)
+	require.Equal(t, uint32(300), p.GetSignalingRTT())
+	info := p.GetConnectionQuality()
+	require.Equal(t, livekit.ConnectionQuality_GOOD, info.Quality)
+	require.Less(t, info.Score, connectionquality.MaxMOS)
+
+	p.UpdateSignalingRTT(700)
+	require.Equal(t, uint32(700), p.GetSignalingRTT())
+	require.Equal(t, livekit.ConnectionQuality_POOR, p.GetConnectionQuality().Quality)
+
+	// media quality takes over once there are tracks
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.IDReturns("track")
+	track.GetConnectionScoreAndQualityReturns(4.5, livekit.ConnectionQuality_EXCELLENT)
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["track"] = track
+	require.Equal(t, livekit.ConnectionQuality_EXCELLENT, p.GetConnectionQuality().Quality)
+}
+
+func TestGetDiagnosticBundle(t *testing.T) {
+	p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: 12})
+	p.updateState(livekit.ParticipantInfo_ACTIVE)
+	p.TransportManager.SetICEConfig(&livekit.ICEConfig{PreferenceSubscriber: livekit.ICECandidateType_ICT_TCP})
+	p.TransportManager.UpdateSignalingRTT(40)
+	p.TransportManager.UpdateMediaRTT(20)
+
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.IDReturns("audio")
+	track.KindReturns(livekit.TrackType_AUDIO)
+	track.ToProtoReturns(&livekit.TrackInfo{Sid: "audio", Type: livekit.TrackType_AUDIO})
+	track.GetConnectionScoreAndQualityReturns(4.5, livekit.ConnectionQuality_EXCELLENT)
+	track.GetTrackStatsReturns(&livekit.RTPStats{Packets: 100, Bitrate: 32_000})
+	track.GetBitrateReturns(32_000)
+	// directly add to publishedTracks without lock - for testing purpose only
+	p.UpTrackManager.publishedTracks["audio"] = track
+
+	subTrack := newSubscribedTrackForVisibilityTest(t, &livekit.TrackInfo{Sid: "video", Type: livekit.TrackType_VIDEO}, webrtc.MimeTypeVP8)
+	sub := newTrackSubscription(p.ID(), "video", p.GetLogger())
+	sub.setSubscribedTrack(subTrack)
+	// directly add to subscriptions without lock - for testing purpose only
+	p.SubscriptionManager.subscriptions["video"] = sub
+
+	bundle := p.GetDiagnosticBundle()
+	require.Equal(t, p.ID(), bundle.ID)
+	require.Equal(t, livekit.ParticipantInfo_ACTIVE, bundle.State)
+	require.Equal(t, types.ProtocolVersion(12), bundle.ProtocolVersion)
+	require.True(t, bundle.Capabilities["ConnectionQualityLost"])
+	require.False(t, bundle.Capabilities["RegionsInLeaveRequest"])
+	require.Equal(t, uint32(40), bundle.SignalingRTT)
+	require.Equal(t, uint32(20), bundle.MediaRTT)
+	require.Equal(t, livekit.ICECandidateType_ICT_TCP, bundle.ICEConfig.PreferenceSubscriber)
+
+	require.Len(t, bundle.PublishedTracks, 1)
+	require.Equal(t, "audio", bundle.PublishedTracks[0].TrackInfo.Sid)
+	require.Equal(t, livekit.ConnectionQuality_EXCELLENT, bundle.PublishedTracks[0].Quality)
+	require.Equal(t, uint32(100), bundle.PublishedTracks[0].Stats.Packets)
+	require.Len(t, bundle.SubscribedTracks, 1)
+	require.Equal(t, livekit.TrackID("video"), bundle.SubscribedTracks[0].TrackID)
+	require.Equal(t, livekit.ParticipantID("pub"), bundle.SubscribedTracks[0].PublisherID)
+	require.Equal(t, livekit.TrackType_VIDEO, bundle.SubscribedTracks[0].Kind)
+	require.False(t, bundle.SubscribedTracks[0].Bound)
+	require.False(t, bundle.SubscribedTracks[0].Forwarder.Started)
+	require.Equal(t, map[sfu.VideoPauseReason]int{
+		bundle.SubscribedTracks[0].Forwarder.LastAllocation.PauseReason: 1,
+	}, bundle.PauseSummary)
+	require.Contains(t, bundle.Bitrate.Tracks, livekit.TrackID("video"))
+	require.Equal(t, int64(32_000), bundle.Bitrate.PublishBps)
+
+	// ICE config is a copy
+	bundle.ICEConfig.PreferenceSubscriber = livekit.ICECandidateType_ICT_NONE
+	require.Equal(t, livekit.ICECandidateType_ICT_TCP, p.TransportManager.GetICEConfig().PreferenceSubscriber)
+}
+
+func TestRequestKeyFrame(t *testing.T) {
+	p := newParticipantForTest("test")
+	require.ErrorIs(t, p.RequestKeyFrame("video"), ErrTrackNotSubscribed)
+
+	audioTrack := &typesfakes.FakeMediaTrack{}
+	audioTrack.KindReturns(livekit.TrackType_AUDIO)
+	audioSubTrack := &typesfakes.FakeSubscribedTrack{}
+	audioSubTrack.IDReturns("audio")
+	audioSubTrack.MediaTrackReturns(audioTrack)
+	sub := newTrackSubscription(p.ID(), "audio", p.GetLogger())
+	sub.setSubscribedTrack(audioSubTrack)
+	// directly add to subscriptions without lock - for testing purpose only
+	p.SubscriptionManager.subscriptions["audio"] = sub
+	require.ErrorIs(t, p.RequestKeyFrame("audio"), ErrTrackNotVideo)
+
+	videoSubTrack := newSubscribedTrackForVisibilityTest(t, &livekit.TrackInfo{Sid: "video", Type: livekit.TrackType_VIDEO}, webrtc.MimeTypeVP8)
+	sub = newTrackSubscription(p.ID(), "video", p.GetLogger())
+	sub.setSubscribedTrack(videoSubTrack)
+	p.SubscriptionManager.subscriptions["video"] = sub
+	// no layer allocated to the subscriber yet
+	require.ErrorIs(t, p.RequestKeyFrame("video"), sfu.ErrNoKeyFrameLayer)
+}
+
+func TestGetVideoAllocation(t *testing.T) {
+	p := newParticipantForTest("test")
+	_, ok := p.GetVideoAllocation("video")
+	require.False(t, ok)
+
+	audioTrack := &typesfakes.FakeMediaTrack{}
+	audioTrack.KindReturns(livekit.TrackType_AUDIO)
+	audioSubTrack := &typesfakes.FakeSubscribedTrack{}
+	audioSubTrack.IDReturns("audio")
+	audioSubTrack.MediaTrackReturns(audioTrack)
+	sub := newTrackSubscription(p.ID(), "audio", p.GetLogger())
+	sub.setSubscribedTrack(audioSubTrack)
+	// directly add to subscriptions without lock - for testing purpose only
+	p.SubscriptionManager.subscriptions["audio"] = sub
+	_, ok = p.GetVideoAllocation("audio")
+	require.False(t, ok)
+
+	videoSubTrack := newSubscribedTrackForVisibilityTest(t, &livekit.TrackInfo{Sid: "video", Type: livekit.TrackType_VIDEO}, webrtc.MimeTypeVP8)
+	sub = newTrackSubscription(p.ID(), "video", p.GetLogger())
+	sub.setSubscribedTrack(videoSubTrack)
+	p.SubscriptionManager.subscriptions["video"] = sub
+
+	allocation, ok := p.GetVideoAllocation("video")
+	require.True(t, ok)
+	require.Equal(t, sfu.VideoAllocationDefault, *allocation)
+
+	// a copy is returned
+	allocation.PauseReason = sfu.VideoPauseReasonBandwidth
+	allocation, ok = p.GetVideoAllocation("video")
+	require.True(t, ok)
+	require.Equal(t, sfu.VideoAllocationDefault.PauseReason, allocation.PauseReason)
+}
+
+func TestHandleDecodeFeedback(t *testing.T) {
+	newParticipantWithSubscription := func(protocolVersion types.ProtocolVersion) (*ParticipantImpl, *typesfakes.FakeSubscribedTrack) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: protocolVersion})
+
+		subTrack := &typesfakes.FakeSubscribedTrack{}
+		subTrack.IDReturns("video")
+		subTrack.UpdateDecodeFeedbackReturns(true)
+		subTrack.MediaTrackReturns(&typesfakes.FakeMediaTrack{})
+		sub := newTrackSubscription(p.ID(), "video", p.GetLogger())
+		sub.setDesired(true)
+		sub.setSubscribedTrack(subTrack)
+		// directly add to subscriptions without lock - for testing purpose only
+		p.SubscriptionManager.subscriptions["video"] = sub
+		return p, subTrack
+	}
+
+	t.Run("ignored without protocol support", func(t *testing.T) {
+		p, subTrack := newParticipantWithSubscription(13)
+		p.HandleDecodeFeedback("video")
+		require.Zero(t, subTrack.UpdateDecodeFeedbackCallCount())
+	})
+
+	t.Run("notifies subscriber", func(t *testing.T) {
+		p, subTrack := newParticipantWithSubscription(types.CurrentProtocol)
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		telemetry := p.params.Telemetry.(*telemetryfakes.FakeTelemetryService)
+
+		p.HandleDecodeFeedback("unknown")
+		require.Zero(t, subTrack.UpdateDecodeFeedbackCallCount())
+
+		p.HandleDecodeFeedback("video")
+		require.Equal(t, 1, subTrack.UpdateDecodeFeedbackCallCount())
+
+		require.Equal(t, 1, telemetry.TrackSubscribeFailedCallCount())
+		_, pID, trackID, err, isUserError := telemetry.TrackSubscribeFailedArgsForCall(0)
+		require.Equal(t, p.ID(), pID)
+		require.Equal(t, livekit.TrackID("video"), trackID)
+		require.ErrorIs(t, err, ErrSubscriberNotDecoding)
+		require.False(t, isUserError)
 
-		p.AddTrack(&livekit.AddTrackRequest{
-			Cid:    "cid2",
-			Name:   "rejected source",
-			Type:   livekit.TrackType_AUDIO,
-			Source: livekit.TrackSource_MICROPHONE,
-		})
 		require.Equal(t, 1, sink.WriteMessageCallCount())
+		msg := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse)
+		require.True(t, proto.Equal(&livekit.SubscriptionResponse{
+			TrackSid: "video",
+			Err:      livekit.SubscriptionError_SE_CODEC_UNSUPPORTED,
+		}, msg.GetSubscriptionResponse()))
 	})
 }
 
-func TestOutOfOrderUpdates(t *testing.T) {
+func TestAdminAuditLog(t *testing.T) {
 	p := newParticipantForTest("test")
-	p.updateState(livekit.ParticipantInfo_JOINED)
-	p.SetMetadata("initial metadata")
-	sink := p.getResponseSink().(*routingfakes.FakeMessageSink)
-	pi1 := p.ToProto()
-	p.SetMetadata("second update")
-	pi2 := p.ToProto()
+	ti := &livekit.TrackInfo{Sid: "testTrack"}
+	p.pendingTracks["cid"] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}}
+
+	// participant initiated actions are not audited
+	p.SetTrackMuted(livekit.TrackID(ti.Sid), true, nil)
+	require.Empty(t, p.GetAdminAuditLog())
+
+	adminOpts := &types.AdminActionOptions{Actor: "admin"}
+	// unknown track is not affected, so not audited
+	p.SetTrackMuted("unknown", true, adminOpts)
+	require.Empty(t, p.GetAdminAuditLog())
+
+	p.SetTrackMuted(livekit.TrackID(ti.Sid), false, adminOpts)
+	p.SetPermission(&livekit.ParticipantPermission{CanSubscribe: true}, adminOpts)
+	p.SetCloseAdminOptions(adminOpts)
+	require.NoError(t, p.Close(false, types.ParticipantCloseReasonServiceRequestRemoveParticipant, false))
+
+	entries := p.GetAdminAuditLog()
+	require.Len(t, entries, 3)
+	require.Equal(t, types.AdminActionUnmuteTrack, entries[0].Action)
+	require.Equal(t, livekit.TrackID(ti.Sid), entries[0].TrackID)
+	require.Equal(t, types.AdminActionUpdatePermission, entries[1].Action)
+	require.Equal(t, types.AdminActionRemoveParticipant, entries[2].Action)
+	for _, entry := range entries {
+		require.Equal(t, "admin", entry.Actor)
+		require.False(t, entry.At.IsZero())
+	}
 
-	require.Greater(t, pi2.Version, pi1.Version)
+	// log is bounded, oldest entries are dropped
+	for i := 0; i < adminAuditLogMaxEntries; i++ {
+		p.recordAdminAction(adminOpts, types.AdminActionMuteTrack, livekit.TrackID(ti.Sid))
+	}
+	entries = p.GetAdminAuditLog()
+	require.Len(t, entries, adminAuditLogMaxEntries)
+	require.Equal(t, types.AdminActionMuteTrack, entries[0].Action)
+}
 
-	// send the second update first
-	require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{pi2}))
-	require.NoError(t, p.SendParticipantUpdate([]*livekit.ParticipantInfo{pi1}))
+func TestStrictMigration(t *testing.T) {
+	addAmbiguousMigratedTrack := func(p *ParticipantImpl) {
+		p.pendingTracks["cid"] = &pendingTrackInfo{
+			trackInfos: []*livekit.TrackInfo{
+				{Sid: "track1", Type: livekit.TrackType_VIDEO, Mid: "0"},
+				{Sid: "track2", Type: livekit.TrackType_VIDEO, Mid: "1"},
+			},
+			migrated: true,
+		}
+	}
 
-	// only sent once, and it's the earlier message
-	require.Equal(t, 1, sink.WriteMessageCallCount())
-	sent := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse)
-	require.Equal(t, "second update", sent.GetUpdate().Participants[0].Metadata)
+	t.Run("lenient proceeds with first track info", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{
+			permissions: &livekit.ParticipantPermission{CanPublish: true},
+		})
+		defer p.Close(false, types.ParticipantCloseReasonNone, false)
+
+		// negotiate publisher so that there is a receiver for mid of first track info
+		pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+		require.NoError(t, err)
+		defer pc.Close()
+		_, err = pc.AddTransceiverFromKind(webrtc.RTPCodecTypeVideo, webrtc.RTPTransceiverInit{
+			Direction: webrtc.RTPTransceiverDirectionSendonly,
+		})
+		require.NoError(t, err)
+		offer, err := pc.CreateOffer(nil)
+		require.NoError(t, err)
+		require.NoError(t, pc.SetLocalDescription(offer))
+
+		p.SetMigrateState(types.MigrateStateSync)
+		p.HandleOffer(offer)
+		require.Eventually(t, func() bool {
+			return p.TransportManager.GetPublisherRTPReceiver("0") != nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		addAmbiguousMigratedTrack(p)
+		p.handleMigrateTracks()
+		require.False(t, p.IsClosed())
+
+		require.NotNil(t, p.GetPublishedTrack("track1"))
+		require.Nil(t, p.GetPublishedTrack("track2"))
+	})
+
+	t.Run("strict issues full reconnect", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.StrictMigration = true
+		addAmbiguousMigratedTrack(p)
+
+		p.handleMigrateTracks()
+		require.True(t, p.IsClosed())
+		require.Equal(t, types.ParticipantCloseReasonMigrateTooManyTracks, p.CloseReason())
+
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		require.Less(t, 0, sink.WriteMessageCallCount())
+		leave := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse).GetLeave()
+		require.NotNil(t, leave)
+		require.True(t, leave.GetCanReconnect())
+	})
 }
 
-// after disconnection, things should continue to function and not panic
-func TestDisconnectTiming(t *testing.T) {
-	t.Run("Negotiate doesn't panic after channel closed", func(t *testing.T) {
+func TestRTCPWriteFailureStreak(t *testing.T) {
+	writeErr := errors.New("write failed")
+
+	t.Run("callback fires once per streak", func(t *testing.T) {
 		p := newParticipantForTest("test")
-		msg := routing.NewMessageChannel(livekit.ConnectionID("test"), routing.DefaultMessageChannelSize)
-		p.params.Sink = msg
-		go func() {
-			for msg := range msg.ReadChan() {
-				t.Log("received message from chan", msg)
-			}
-		}()
-		track := &typesfakes.FakeMediaTrack{}
-		p.UpTrackManager.AddPublishedTrack(track)
-		p.handleTrackPublished(track)
+		p.params.RTCPWriteFailureConfig = config.RTCPWriteFailureConfig{
+			MaxConsecutive: 3,
+			Window:         time.Minute,
+		}
 
-		// close channel and then try to Negotiate
-		msg.Close()
+		var targets []livekit.SignalTarget
+		p.OnRTCPWriteFailure(func(_ types.LocalParticipant, target livekit.SignalTarget, err error) {
+			require.Equal(t, writeErr, err)
+			targets = append(targets, target)
+		})
+
+		// success resets streak
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+		require.Equal(t, 2, p.pubRTCPWriteFailures.get())
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, nil)
+		require.Equal(t, 0, p.pubRTCPWriteFailures.get())
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+		require.Empty(t, targets)
+
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+		require.Equal(t, []livekit.SignalTarget{livekit.SignalTarget_PUBLISHER}, targets)
+
+		telemetry := p.params.Telemetry.(*telemetryfakes.FakeTelemetryService)
+		require.Equal(t, 1, telemetry.ParticipantRTCPWriteFailedCallCount())
+		_, participantID, target, category := telemetry.ParticipantRTCPWriteFailedArgsForCall(0)
+		require.Equal(t, p.ID(), participantID)
+		require.Equal(t, livekit.SignalTarget_PUBLISHER, target)
+		require.Equal(t, "other", category)
+
+		// continued failures do not fire again
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+		require.Len(t, targets, 1)
+		require.Equal(t, 4, p.pubRTCPWriteFailures.get())
+
+		// subscriber is tracked independently
+		for i := 0; i < 3; i++ {
+			p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+		}
+		require.Equal(t, []livekit.SignalTarget{livekit.SignalTarget_PUBLISHER, livekit.SignalTarget_SUBSCRIBER}, targets)
+
+		streaks := p.DebugInfo()["RTCPWriteFailureStreak"].(map[string]interface{})
+		require.Equal(t, 4, streaks["Publisher"])
+		require.Equal(t, 3, streaks["Subscriber"])
+	})
+
+	t.Run("failures outside window restart streak", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.RTCPWriteFailureConfig = config.RTCPWriteFailureConfig{
+			MaxConsecutive: 2,
+			Window:         10 * time.Millisecond,
+		}
+
+		fired := 0
+		p.OnRTCPWriteFailure(func(_ types.LocalParticipant, _ livekit.SignalTarget, _ error) {
+			fired++
+		})
+
+		p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+		time.Sleep(20 * time.Millisecond)
+		p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+		require.Equal(t, 0, fired)
+		require.Equal(t, 1, p.subRTCPWriteFailures.get())
+	})
+
+	t.Run("defaults to transport failure handling", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.RTCPWriteFailureConfig = config.RTCPWriteFailureConfig{
+			MaxConsecutive: 1,
+		}
+
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+
+		// signal connection is closed for client to resume
+		require.Equal(t, 1, sink.CloseCallCount())
+		require.Nil(t, p.getResponseSink())
 	})
 }
 
-func TestCorrectJoinedAt(t *testing.T) {
+func TestDisableSenderReports(t *testing.T) {
+	for _, disabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disabled: %v", disabled), func(t *testing.T) {
+			p := newParticipantForTest("test")
+			p.params.SenderReportInterval = 10 * time.Millisecond
+			p.params.DisableSenderReports = disabled
+
+			subTrack := &typesfakes.FakeSubscribedTrack{}
+			subTrack.MediaTrackReturns(&typesfakes.FakeMediaTrack{})
+			subTrack.DownTrackReturns(newSubscribedTrackForVisibilityTest(t, &livekit.TrackInfo{Sid: "video", Type: livekit.TrackType_VIDEO}, webrtc.MimeTypeVP8).DownTrack())
+			sub := newTrackSubscription(p.ID(), "video", p.GetLogger())
+			sub.setSubscribedTrack(subTrack)
+			// directly add to subscriptions without lock - for testing purpose only
+			p.SubscriptionManager.subscriptions["video"] = sub
+
+			p.onSubscriberInitialConnected()
+			time.Sleep(50 * time.Millisecond)
+			p.updateState(livekit.ParticipantInfo_DISCONNECTED)
+
+			// down track is looked up once to mark it connected, reports look it up on every interval
+			if disabled {
+				require.Equal(t, 1, subTrack.DownTrackCallCount())
+			} else {
+				require.Greater(t, subTrack.DownTrackCallCount(), 1)
+			}
+		})
+	}
+}
+
+func TestSubscriberRTCPBackoff(t *testing.T) {
+	writeErr := errors.New("write failed")
+
 	p := newParticipantForTest("test")
-	info := p.ToProto()
-	require.NotZero(t, info.JoinedAt)
-	require.True(t, time.Now().Unix()-info.JoinedAt <= 1)
+	p.params.SenderReportInterval = time.Second
+	p.params.RTCPWriteFailureConfig = config.RTCPWriteFailureConfig{
+		MaxConsecutive:             5,
+		Window:                     10 * time.Millisecond,
+		SubscriberBackoffThreshold: 2,
+		SubscriberMaxBackoff:       10 * time.Second,
+	}
+
+	fired := 0
+	p.OnRTCPWriteFailure(func(_ types.LocalParticipant, target livekit.SignalTarget, _ error) {
+		require.Equal(t, livekit.SignalTarget_SUBSCRIBER, target)
+		fired++
+	})
+
+	p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+	require.Equal(t, time.Second, p.getSubscriberRTCPInterval())
+
+	// doubles on every failure from threshold onwards
+	p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+	require.Equal(t, 2*time.Second, p.getSubscriberRTCPInterval())
+	p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+	require.Equal(t, 4*time.Second, p.getSubscriberRTCPInterval())
+
+	// backed off failures fall outside window, but continue to back off
+	time.Sleep(20 * time.Millisecond)
+	p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+	require.Equal(t, 8*time.Second, p.getSubscriberRTCPInterval())
+	p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+	require.Equal(t, 10*time.Second, p.getSubscriberRTCPInterval())
+	require.Equal(t, "10s", p.DebugInfo()["SubscriberRTCPInterval"])
+
+	// publisher failures do not affect subscriber reports
+	p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, nil)
+	p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+	p.handleRTCPWriteResult(livekit.SignalTarget_PUBLISHER, writeErr)
+	require.Equal(t, time.Second, p.getSubscriberRTCPInterval())
+
+	// persistent failures within window still trigger health check while backing off
+	for i := 0; i < 5; i++ {
+		p.handleRTCPWriteResult(livekit.SignalTarget_SUBSCRIBER, writeErr)
+	}
+	require.Equal(t, 1, fired)
+	require.Equal(t, 10*time.Second, p.getSubscriberRTCPInterval())
+
+	// disabled
+	p.params.RTCPWriteFailureConfig.SubscriberBackoffThreshold = 0
+	require.Equal(t, time.Second, p.getSubscriberRTCPInterval())
 }
 
-func TestMuteSetting(t *testing.T) {
-	t.Run("can set mute when track is pending", func(t *testing.T) {
-		p := newParticipantForTest("test")
-		ti := &livekit.TrackInfo{Sid: "testTrack"}
-		p.pendingTracks["cid"] = &pendingTrackInfo{trackInfos: []*livekit.TrackInfo{ti}}
+func TestReconnectCount(t *testing.T) {
+	t.Run("continues from previous sessions", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{reconnectCount: 2})
+		require.Equal(t, uint32(2), p.GetReconnectCount())
 
-		p.SetTrackMuted(livekit.TrackID(ti.Sid), true, false)
-		require.True(t, ti.Muted)
+		p.ResetReconnectCount()
+		require.Zero(t, p.GetReconnectCount())
 	})
 
-	t.Run("can publish a muted track", func(t *testing.T) {
+	t.Run("increments per reconnect", func(t *testing.T) {
 		p := newParticipantForTest("test")
-		p.AddTrack(&livekit.AddTrackRequest{
-			Cid:   "cid",
-			Type:  livekit.TrackType_AUDIO,
-			Muted: true,
-		})
+		require.Zero(t, p.GetReconnectCount())
+
+		reasons := []types.ParticipantCloseReason{
+			types.ParticipantCloseReasonPublicationError,
+			types.ParticipantCloseReasonSubscriptionError,
+			types.ParticipantCloseReasonDataChannelError,
+			types.ParticipantCloseReasonNegotiateFailed,
+			types.ParticipantCloseReasonMigrateCodecMismatch,
+		}
+		for i, reason := range reasons {
+			p.IssueFullReconnect(reason)
+			require.Equal(t, uint32(i+1), p.GetReconnectCount(), reason.String())
+		}
+		require.Equal(t, uint32(len(reasons)), p.DebugInfo()["ReconnectCount"])
+	})
+}
 
-		_, ti, _ := p.getPendingTrack("cid", livekit.TrackType_AUDIO)
-		require.NotNil(t, ti)
-		require.True(t, ti.Muted)
+func TestParticipantPlayoutDelayOverride(t *testing.T) {
+	delay := &livekit.PlayoutDelay{Enabled: true, Min: 200, Max: 400}
+	hasSubscription := func(p *ParticipantImpl) bool {
+		p.SubscriptionManager.lock.RLock()
+		defer p.SubscriptionManager.lock.RUnlock()
+		return p.SubscriptionManager.subscriptions["track"] != nil
+	}
+
+	t.Run("override is stored", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.SyncStreams = true
+		p.SetTrackPlayoutDelay("track", delay)
+		require.True(t, hasSubscription(p))
+	})
+
+	t.Run("ignored for firefox with synced streams", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{
+			clientInfo: &livekit.ClientInfo{Browser: "firefox"},
+		})
+		p.params.SyncStreams = true
+		p.SetTrackPlayoutDelay("track", delay)
+		require.False(t, hasSubscription(p))
 	})
 }
 
@@ -326,7 +2490,7 @@ func TestSubscriberAsPrimary(t *testing.T) {
 		p.SetPermission(&livekit.ParticipantPermission{
 			CanSubscribe: true,
 			CanPublish:   true,
-		})
+		}, nil)
 		require.False(t, p.SubscriberAsPrimary())
 	})
 }
@@ -402,6 +2566,56 @@ func TestSetStableTrackID(t *testing.T) {
 	}
 }
 
+func TestPreviousTrackSIDs(t *testing.T) {
+	camera := func(name string) *livekit.TrackInfo {
+		return &livekit.TrackInfo{
+			Type:   livekit.TrackType_VIDEO,
+			Source: livekit.TrackSource_CAMERA,
+			Name:   name,
+		}
+	}
+
+	t.Run("reused once, matched on name", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.previousTrackSIDs = map[TrackSIDKey]livekit.TrackID{
+			TrackSIDKeyFromInfo(camera("front")): "TR_front",
+			TrackSIDKeyFromInfo(camera("back")):  "TR_back",
+		}
+
+		back := camera("back")
+		p.setStableTrackID("cid1", back)
+		require.Equal(t, "TR_back", back.Sid)
+
+		front := camera("front")
+		p.setStableTrackID("cid2", front)
+		require.Equal(t, "TR_front", front.Sid)
+
+		again := camera("front")
+		p.setStableTrackID("cid3", again)
+		require.NotEqual(t, "TR_front", again.Sid)
+		require.Contains(t, again.Sid, "TR_VC")
+		require.Empty(t, p.previousTrackSIDs)
+	})
+
+	t.Run("collision with published track", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.previousTrackSIDs = map[TrackSIDKey]livekit.TrackID{
+			TrackSIDKeyFromInfo(camera("front")): "TR_front",
+		}
+		p.params.TrackResolver = func(_ livekit.ParticipantIdentity, trackID livekit.TrackID) types.MediaResolverResult {
+			if trackID == "TR_front" {
+				return types.MediaResolverResult{Track: &typesfakes.FakeMediaTrack{}, PublisherID: "PA_other"}
+			}
+			return types.MediaResolverResult{}
+		}
+
+		front := camera("front")
+		p.setStableTrackID("cid1", front)
+		require.NotEqual(t, "TR_front", front.Sid)
+		require.Contains(t, front.Sid, "TR_VC")
+	})
+}
+
 func TestDisableCodecs(t *testing.T) {
 	participant := newParticipantForTestWithOpts("123", &participantOpts{
 		publisher: false,
@@ -532,6 +2746,220 @@ func TestDisablePublishCodec(t *testing.T) {
 	require.Eventually(t, func() bool { return publishReceived.Load() }, 5*time.Second, 10*time.Millisecond)
 }
 
+func TestCodecFallbacks(t *testing.T) {
+	participant := newParticipantForTestWithOpts("123", &participantOpts{
+		publisher: true,
+		clientConf: &livekit.ClientConfiguration{
+			DisabledCodecs: &livekit.DisabledCodecs{
+				Publish: []*livekit.Codec{
+					{Mime: "video/h264"},
+				},
+			},
+		},
+	})
+	require.Empty(t, participant.GetCodecFallbacks())
+
+	// requesting a disabled codec should record the fallback
+	participant.AddTrack(&livekit.AddTrackRequest{
+		Cid:  "cid1",
+		Type: livekit.TrackType_VIDEO,
+		SimulcastCodecs: []*livekit.SimulcastCodec{{
+			Codec: "h264",
+			Cid:   "cid1",
+		}},
+	})
+	_, ti, _ := participant.getPendingTrack("cid1", livekit.TrackType_VIDEO)
+	require.NotNil(t, ti)
+
+	fallbacks := participant.GetCodecFallbacks()
+	require.Len(t, fallbacks, 1)
+	require.Equal(t, map[string]string{"video/h264": webrtc.MimeTypeVP8}, fallbacks[livekit.TrackID(ti.Sid)])
+
+	// requesting an enabled codec should not record a fallback
+	participant.AddTrack(&livekit.AddTrackRequest{
+		Cid:  "cid2",
+		Type: livekit.TrackType_VIDEO,
+		SimulcastCodecs: []*livekit.SimulcastCodec{{
+			Codec: "vp8",
+			Cid:   "cid2",
+		}},
+	})
+	require.Len(t, participant.GetCodecFallbacks(), 1)
+
+	// fallbacks of unpublished track should be cleared
+	mt := participant.addMediaTrack("cid1", "cid1", ti)
+	participant.UpTrackManager.AddPublishedTrack(mt)
+	participant.removePublishedTrack(mt, 0)
+	require.Eventually(t, func() bool {
+		return len(participant.GetCodecFallbacks()) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// fallbacks of expired pending track should be cleared
+	participant.params.PublishLimit.PendingTrackTimeout = time.Minute
+	participant.AddTrack(&livekit.AddTrackRequest{
+		Cid:  "cid3",
+		Type: livekit.TrackType_VIDEO,
+		SimulcastCodecs: []*livekit.SimulcastCodec{{
+			Codec: "h264",
+			Cid:   "cid3",
+		}},
+	})
+	require.Len(t, participant.GetCodecFallbacks(), 1)
+
+	participant.pendingTracksLock.Lock()
+	participant.expirePendingTracksLocked(time.Now().Add(2 * time.Minute))
+	participant.pendingTracksLock.Unlock()
+	require.Empty(t, participant.GetCodecFallbacks())
+}
+
+func TestResumeState(t *testing.T) {
+	p := newParticipantForTestWithOpts("123", &participantOpts{
+		publisher: true,
+	})
+	p.AddTrack(&livekit.AddTrackRequest{
+		Cid:  "cid1",
+		Type: livekit.TrackType_AUDIO,
+	})
+	_, ti, _ := p.getPendingTrack("cid1", livekit.TrackType_AUDIO)
+	require.NotNil(t, ti)
+
+	// resuming needs the previous subscriber offer/answer
+	negotiateSubscriberForTest(t, p)
+
+	dtState := sfu.DownTrackState{
+		DeltaStatsSenderSnapshotId: 2,
+		ForwarderState: sfu.ForwarderState{
+			Started:               true,
+			ReferenceLayerSpatial: buffer.InvalidLayerSpatial,
+			PreStartTime:          time.Unix(0, 1234567890),
+			ExtFirstTS:            0xabcd00,
+			RTP: sfu.RTPMungerState{
+				ExtLastSN:       23333,
+				ExtSecondLastSN: 23332,
+				ExtLastTS:       0xabcdef,
+				ExtSecondLastTS: 0xabcbef,
+				LastMarker:      true,
+			},
+		},
+	}
+	p.CacheDownTrack("TR_cached", nil, dtState)
+
+	exported := p.ExportResumeState()
+	require.NotNil(t, exported.SyncState.Answer)
+	require.Len(t, exported.SyncState.PublishTracks, 1)
+	require.Equal(t, "cid1", exported.SyncState.PublishTracks[0].Cid)
+	require.Equal(t, ti.Sid, exported.SyncState.PublishTracks[0].Track.Sid)
+	require.Equal(t, dtState, exported.DownTracks["TR_cached"])
+
+	// round trip through wire format
+	data, err := exported.Marshal()
+	require.NoError(t, err)
+	imported, err := UnmarshalResumeState(data)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(exported.SyncState, imported.SyncState))
+	require.Equal(t, dtState, imported.DownTracks["TR_cached"])
+
+	// imported on migrating to another node
+	resumed := newParticipantForTestWithOpts("123", &participantOpts{
+		publisher:   true,
+		migration:   true,
+		resumeState: imported,
+	})
+	require.False(t, resumed.IsClosed())
+
+	// published track should be pending as a migrated track
+	_, resumedTI, migrated := resumed.getPendingTrack("cid1", livekit.TrackType_AUDIO)
+	require.NotNil(t, resumedTI)
+	require.Equal(t, ti.Sid, resumedTI.Sid)
+	require.True(t, migrated)
+
+	// down track state should be available to seed a new down track until it is bound,
+	// looking up the cache does not consume it
+	transceiver, _ := resumed.GetCachedDownTrack("TR_cached")
+	require.Nil(t, transceiver)
+	resumedState, ok := resumed.GetResumedDownTrack("TR_cached")
+	require.True(t, ok)
+	require.Equal(t, dtState, resumedState)
+
+	resumedState, ok = resumed.GetResumedDownTrack("TR_cached")
+	require.True(t, ok)
+	require.Equal(t, uint64(23333), resumedState.ForwarderState.RTP.ExtLastSN)
+
+	resumed.ClearResumedDownTrack("TR_cached")
+	_, ok = resumed.GetResumedDownTrack("TR_cached")
+	require.False(t, ok)
+}
+
+func negotiateSubscriberForTest(t *testing.T, p *ParticipantImpl) {
+	p.SetMigrateState(types.MigrateStateComplete)
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+	p.Negotiate(true)
+
+	var offer *livekit.SessionDescription
+	require.Eventually(t, func() bool {
+		for i := 0; i < sink.WriteMessageCallCount(); i++ {
+			if res, ok := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse); ok && res.GetOffer() != nil {
+				offer = res.GetOffer()
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, pc.SetRemoteDescription(FromProtoSessionDescription(offer)))
+	answer, err := pc.CreateAnswer(nil)
+	require.NoError(t, err)
+	require.NoError(t, pc.SetLocalDescription(answer))
+	p.HandleAnswer(answer)
+
+	require.Eventually(t, func() bool {
+		_, previousAnswer, _ := p.TransportManager.GetMigrateInfo()
+		return previousAnswer != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPlayoutDelaySources(t *testing.T) {
+	playoutDelay := &livekit.PlayoutDelay{Enabled: true, Min: 100, Max: 2000}
+
+	t.Run("defaults to video tracks", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.PlayoutDelay = playoutDelay
+
+		require.Equal(t, playoutDelay, p.GetPlayoutDelayConfig(livekit.TrackSource_CAMERA))
+		require.True(t, p.playoutDelayForKind(livekit.TrackType_VIDEO))
+		require.False(t, p.playoutDelayForKind(livekit.TrackType_AUDIO))
+	})
+
+	t.Run("only configured sources", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.PlayoutDelay = playoutDelay
+		p.params.PlayoutDelaySources = []livekit.TrackSource{livekit.TrackSource_SCREEN_SHARE, livekit.TrackSource_SCREEN_SHARE_AUDIO}
+
+		require.Equal(t, playoutDelay, p.GetPlayoutDelayConfig(livekit.TrackSource_SCREEN_SHARE))
+		require.Equal(t, playoutDelay, p.GetPlayoutDelayConfig(livekit.TrackSource_SCREEN_SHARE_AUDIO))
+		require.Nil(t, p.GetPlayoutDelayConfig(livekit.TrackSource_CAMERA))
+		require.Nil(t, p.GetPlayoutDelayConfig(livekit.TrackSource_MICROPHONE))
+		require.True(t, p.playoutDelayForKind(livekit.TrackType_VIDEO))
+		require.True(t, p.playoutDelayForKind(livekit.TrackType_AUDIO))
+	})
+
+	t.Run("audio only", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.PlayoutDelay = playoutDelay
+		p.params.PlayoutDelaySources = []livekit.TrackSource{livekit.TrackSource_MICROPHONE}
+
+		require.Equal(t, playoutDelay, p.GetPlayoutDelayConfig(livekit.TrackSource_MICROPHONE))
+		require.Nil(t, p.GetPlayoutDelayConfig(livekit.TrackSource_CAMERA))
+		require.False(t, p.playoutDelayForKind(livekit.TrackType_VIDEO))
+		require.True(t, p.playoutDelayForKind(livekit.TrackType_AUDIO))
+	})
+}
+
 func TestPreferVideoCodecForPublisher(t *testing.T) {
 	participant := newParticipantForTestWithOpts("123", &participantOpts{
 		publisher: true,
@@ -709,12 +3137,37 @@ func TestPreferAudioCodecForRed(t *testing.T) {
 	}
 }
 
+func TestSubscriberAudioOnlyRequest(t *testing.T) {
+	t.Run("ignored for clients without support", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: 13})
+		p.HandleSubscriberAudioOnlyRequest(true)
+		require.False(t, p.SubscriptionManager.IsAudioOnly())
+	})
+
+	t.Run("toggles audio only mode", func(t *testing.T) {
+		p := newParticipantForTestWithOpts("test", &participantOpts{protocolVersion: types.CurrentProtocol})
+		p.HandleSubscriberAudioOnlyRequest(true)
+		require.True(t, p.SubscriptionManager.IsAudioOnly())
+
+		p.HandleSubscriberAudioOnlyRequest(false)
+		require.False(t, p.SubscriptionManager.IsAudioOnly())
+	})
+}
+
 type participantOpts struct {
-	permissions     *livekit.ParticipantPermission
-	protocolVersion types.ProtocolVersion
-	publisher       bool
-	clientConf      *livekit.ClientConfiguration
-	clientInfo      *livekit.ClientInfo
+	permissions      *livekit.ParticipantPermission
+	protocolVersion  types.ProtocolVersion
+	publisher        bool
+	clientConf       *livekit.ClientConfiguration
+	clientInfo       *livekit.ClientInfo
+	maxCachedUpdates int
+	ignoreMigrated   bool
+	heartbeat        time.Duration
+	migration        bool
+	maxPendingICE    int
+	trafficLoad      bool
+	reconnectCount   uint32
+	resumeState      *ResumeState
 }
 
 func newParticipantForTestWithOpts(identity livekit.ParticipantIdentity, opts *participantOpts) *ParticipantImpl {
@@ -731,8 +3184,9 @@ func newParticipantForTestWithOpts(identity livekit.ParticipantIdentity, opts *p
 	if err != nil {
 		panic(err)
 	}
-	ff := buffer.NewFactoryOfBufferFactory(500, 200)
+	ff := buffer.NewFactoryOfBufferFactory(500, 200, buffer.ClockSkewParams{})
 	rtcConf.SetBufferFactory(ff.CreateBufferFactory())
+	rtcConf.JobScheduler = testJobScheduler
 	grants := &auth.ClaimGrants{
 		Video: &auth.VideoGrant{},
 	}
@@ -749,23 +3203,37 @@ func newParticipantForTestWithOpts(identity livekit.ParticipantIdentity, opts *p
 			FmtpLine: c.FmtpLine,
 		})
 	}
+	// node default, as resolved by room manager
+	preferMigratedCapabilities := conf.RTC.PreferMigratedCapabilities == nil || *conf.RTC.PreferMigratedCapabilities
+	if opts.ignoreMigrated {
+		preferMigratedCapabilities = false
+	}
 	sid := livekit.ParticipantID(utils.NewGuid(utils.ParticipantPrefix))
 	p, _ := NewParticipant(ParticipantParams{
-		SID:                    sid,
-		Identity:               identity,
-		Config:                 rtcConf,
-		Sink:                   &routingfakes.FakeMessageSink{},
-		ProtocolVersion:        opts.protocolVersion,
-		SessionStartTime:       time.Now(),
-		PLIThrottleConfig:      conf.RTC.PLIThrottle,
-		Grants:                 grants,
-		PublishEnabledCodecs:   enabledCodecs,
-		SubscribeEnabledCodecs: enabledCodecs,
-		ClientConf:             opts.clientConf,
-		ClientInfo:             ClientInfo{ClientInfo: opts.clientInfo},
-		Logger:                 LoggerWithParticipant(logger.GetLogger(), identity, sid, false),
-		Telemetry:              &telemetryfakes.FakeTelemetryService{},
-		VersionGenerator:       utils.NewDefaultTimedVersionGenerator(),
+		SID:                        sid,
+		Identity:                   identity,
+		Config:                     rtcConf,
+		Sink:                       &routingfakes.FakeMessageSink{},
+		ProtocolVersion:            opts.protocolVersion,
+		SessionStartTime:           time.Now(),
+		PLIThrottleConfig:          conf.RTC.PLIThrottle,
+		Grants:                     grants,
+		PublishEnabledCodecs:       enabledCodecs,
+		SubscribeEnabledCodecs:     enabledCodecs,
+		ClientConf:                 opts.clientConf,
+		ClientInfo:                 ClientInfo{ClientInfo: opts.clientInfo},
+		Logger:                     LoggerWithParticipant(logger.GetLogger(), identity, sid, false),
+		Telemetry:                  &telemetryfakes.FakeTelemetryService{},
+		VersionGenerator:           utils.NewDefaultTimedVersionGenerator(),
+		MaxCachedUpdates:           opts.maxCachedUpdates,
+		PreferMigratedCapabilities: preferMigratedCapabilities,
+		HeartbeatInterval:          opts.heartbeat,
+		Migration:                  opts.migration,
+		MaxPendingICECandidates:    opts.maxPendingICE,
+		EnableTrafficLoadTracking:  opts.trafficLoad,
+		ReconnectCount:             opts.reconnectCount,
+		ResumeState:                opts.resumeState,
+		JobScheduler:               rtcConf.JobScheduler,
 	})
 	p.isPublisher.Store(opts.publisher)
 	p.updateState(livekit.ParticipantInfo_ACTIVE)
@@ -773,6 +3241,9 @@ func newParticipantForTestWithOpts(identity livekit.ParticipantIdentity, opts *p
 	return p
 }
 
+// shared by participants and rooms of tests, like the scheduler of a node
+var testJobScheduler = sutils.NewJobScheduler(sutils.JobSchedulerParams{})
+
 func newParticipantForTest(identity livekit.ParticipantIdentity) *ParticipantImpl {
 	return newParticipantForTestWithOpts(identity, nil)
 }
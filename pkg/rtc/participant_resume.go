@@ -0,0 +1,275 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"github.com/pion/webrtc/v3"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/utils"
+	"github.com/livekit/protocol/livekit"
+)
+
+// ResumeState is a snapshot of everything needed to resume a participant on another node,
+// i. e. when draining a node, state can be pre-built on the replacement node before clients reconnect.
+type ResumeState struct {
+	// SyncState holds migration relevant SDP, published tracks, subscriptions and data channels.
+	SyncState *livekit.SyncState
+
+	// DownTracks holds down track (forwarder + RTP stats) state of subscribed and cached tracks.
+	DownTracks map[livekit.TrackID]sfu.DownTrackState
+
+	// Capabilities holds protocol capabilities resolved for the participant, so that they do not change
+	// when the node resuming the participant derives them differently.
+	Capabilities map[string]bool
+}
+
+// Marshal encodes resume state in protobuf wire format
+//
+//	message ResumeState {
+//	  SyncState sync_state = 1;
+//	  repeated DownTrack down_tracks = 2;
+//	  repeated Capability capabilities = 3;
+//	}
+//	message DownTrack {
+//	  string track_id = 1;
+//	  bytes state = 2; // sfu.DownTrackState.MarshalBinary
+//	}
+//	message Capability {
+//	  string name = 1;
+//	  bool supported = 2;
+//	}
+func (r *ResumeState) Marshal() ([]byte, error) {
+	syncState, err := proto.Marshal(r.SyncState)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &utils.WireEncoder{}
+	e.Bytes(1, syncState)
+	for trackID, dts := range r.DownTracks {
+		state, err := dts.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		de := &utils.WireEncoder{}
+		de.String(1, string(trackID))
+		de.Bytes(2, state)
+		e.Bytes(2, de.Encoded())
+	}
+	for name, supported := range r.Capabilities {
+		ce := &utils.WireEncoder{}
+		ce.String(1, name)
+		ce.Bool(2, supported)
+		e.Bytes(3, ce.Encoded())
+	}
+	return e.Encoded(), nil
+}
+
+func UnmarshalResumeState(data []byte) (*ResumeState, error) {
+	r := &ResumeState{
+		SyncState:    &livekit.SyncState{},
+		DownTracks:   make(map[livekit.TrackID]sfu.DownTrackState),
+		Capabilities: make(map[string]bool),
+	}
+	err := utils.DecodeWire(data, func(num protowire.Number, f utils.WireField) error {
+		switch num {
+		case 1:
+			return proto.Unmarshal(f.Bytes(), r.SyncState)
+		case 2:
+			var trackID livekit.TrackID
+			var dts sfu.DownTrackState
+			if err := utils.DecodeWire(f.Bytes(), func(num protowire.Number, f utils.WireField) error {
+				switch num {
+				case 1:
+					trackID = livekit.TrackID(f.String())
+				case 2:
+					return dts.UnmarshalBinary(f.Bytes())
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if trackID != "" {
+				r.DownTracks[trackID] = dts
+			}
+		case 3:
+			var name string
+			var supported bool
+			if err := utils.DecodeWire(f.Bytes(), func(num protowire.Number, f utils.WireField) error {
+				switch num {
+				case 1:
+					name = f.String()
+				case 2:
+					supported = f.Bool()
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if name != "" {
+				r.Capabilities[name] = supported
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ExportResumeState captures resumable state of the participant in one pass.
+// Only read locks are taken, media continues to flow while exporting.
+//
+// Each kind of state is read under its own lock, so the snapshot is not atomic across them.
+// Reads are ordered so that a track changing state during export is not lost:
+//   - pending tracks are read before published tracks, a track published in between is
+//     de-duplicated by client id
+//   - subscribed tracks are read before cached down tracks, a track unsubscribed in between
+//     has its state cached and is picked up from the cache
+//
+// A track published or subscribed for the first time after its category has been read is not
+// included, so signalling should be quiesced (i. e. node marked as draining) before exporting.
+func (p *ParticipantImpl) ExportResumeState() *ResumeState {
+	syncState := &livekit.SyncState{
+		Subscription: &livekit.UpdateSubscription{
+			Subscribe: true,
+		},
+	}
+
+	previousOffer, previousAnswer, dataChannels := p.TransportManager.GetMigrateInfo()
+	if previousOffer != nil {
+		syncState.Offer = ToProtoSessionDescription(*previousOffer)
+	}
+	if previousAnswer != nil {
+		syncState.Answer = ToProtoSessionDescription(*previousAnswer)
+	}
+	syncState.DataChannels = dataChannels
+
+	publishTracks := make(map[string]*livekit.TrackPublishedResponse)
+	p.pendingTracksLock.RLock()
+	for cid, pti := range p.pendingTracks {
+		if len(pti.trackInfos) == 0 {
+			continue
+		}
+		publishTracks[cid] = &livekit.TrackPublishedResponse{
+			Cid:   cid,
+			Track: proto.Clone(pti.trackInfos[0]).(*livekit.TrackInfo),
+		}
+	}
+	p.pendingTracksLock.RUnlock()
+
+	for _, t := range p.GetPublishedTracks() {
+		lt, ok := t.(types.LocalMediaTrack)
+		if !ok {
+			continue
+		}
+		// published state is more recent than pending
+		publishTracks[lt.SignalCid()] = &livekit.TrackPublishedResponse{
+			Cid:   lt.SignalCid(),
+			Track: lt.ToProto(),
+		}
+	}
+	for _, tpr := range publishTracks {
+		syncState.PublishTracks = append(syncState.PublishTracks, tpr)
+	}
+
+	downTracks := make(map[livekit.TrackID]sfu.DownTrackState)
+	for _, st := range p.SubscriptionManager.GetSubscribedTracks() {
+		syncState.Subscription.TrackSids = append(syncState.Subscription.TrackSids, string(st.ID()))
+		if dt := st.DownTrack(); dt != nil {
+			downTracks[st.ID()] = dt.GetState()
+		}
+	}
+
+	p.lock.RLock()
+	for trackID, dts := range p.cachedDownTracks {
+		// live down track state is more recent than a cached one
+		if _, ok := downTracks[trackID]; !ok {
+			downTracks[trackID] = dts.downTrack
+		}
+	}
+	for trackID, dts := range p.resumedDownTracks {
+		// imported, but not subscribed yet
+		if _, ok := downTracks[trackID]; !ok {
+			downTracks[trackID] = dts
+		}
+	}
+	p.lock.RUnlock()
+
+	return &ResumeState{
+		SyncState:    syncState,
+		DownTracks:   downTracks,
+		Capabilities: p.resolvedCapabilities(),
+	}
+}
+
+// MarshalResumeState exports resume state in wire format, to be carried to the node participant migrates to
+func (p *ParticipantImpl) MarshalResumeState() ([]byte, error) {
+	return p.ExportResumeState().Marshal()
+}
+
+// ImportResumeState pre-builds state exported from another node using ExportResumeState.
+// Down track states are held so that they are seeded when the track is subscribed again.
+func (p *ParticipantImpl) ImportResumeState(state *ResumeState) {
+	if state == nil {
+		return
+	}
+
+	p.lock.Lock()
+	for trackID, dts := range state.DownTracks {
+		p.resumedDownTracks[trackID] = dts
+	}
+	p.lock.Unlock()
+
+	var previousOffer, previousAnswer *webrtc.SessionDescription
+	if offer := state.SyncState.GetOffer(); offer != nil {
+		sd := FromProtoSessionDescription(offer)
+		previousOffer = &sd
+	}
+	if answer := state.SyncState.GetAnswer(); answer != nil {
+		sd := FromProtoSessionDescription(answer)
+		previousAnswer = &sd
+	}
+
+	p.SetMigrateInfo(
+		previousOffer,
+		previousAnswer,
+		state.SyncState.GetPublishTracks(),
+		state.SyncState.GetDataChannels(),
+		state.Capabilities,
+	)
+}
+
+// GetResumedDownTrack returns down track state imported for the track, if it has not been consumed yet
+func (p *ParticipantImpl) GetResumedDownTrack(trackID livekit.TrackID) (sfu.DownTrackState, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	dts, ok := p.resumedDownTracks[trackID]
+	return dts, ok
+}
+
+// ClearResumedDownTrack consumes imported down track state once it has seeded a bound down track
+func (p *ParticipantImpl) ClearResumedDownTrack(trackID livekit.TrackID) {
+	p.lock.Lock()
+	delete(p.resumedDownTracks, trackID)
+	p.lock.Unlock()
+}
@@ -29,6 +29,9 @@ import (
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 )
 
+// room size at which participant updates are batched for the longest window
+const updateBatchMaxWindowParticipants = 500
+
 func (p *ParticipantImpl) getResponseSink() routing.MessageSink {
 	p.resSinkMu.Lock()
 	defer p.resSinkMu.Unlock()
@@ -45,12 +48,7 @@ func (p *ParticipantImpl) SendJoinResponse(joinResponse *livekit.JoinResponse) e
 	// keep track of participant updates and versions
 	p.updateLock.Lock()
 	for _, op := range joinResponse.OtherParticipants {
-		p.updateCache.Add(livekit.ParticipantID(op.Sid), participantUpdateInfo{
-			identity:  livekit.ParticipantIdentity(op.Identity),
-			version:   op.Version,
-			state:     op.State,
-			updatedAt: time.Now(),
-		})
+		p.cacheUpdateLocked(op)
 	}
 	p.updateLock.Unlock()
 
@@ -81,6 +79,9 @@ func (p *ParticipantImpl) SendJoinResponse(joinResponse *livekit.JoinResponse) e
 }
 
 func (p *ParticipantImpl) SendParticipantUpdate(participantsToUpdate []*livekit.ParticipantInfo) error {
+	// window is looked up before locking as it may depend on room state
+	window := p.getUpdateBatchWindow()
+
 	p.updateLock.Lock()
 	if p.IsDisconnected() {
 		p.updateLock.Unlock()
@@ -93,6 +94,16 @@ func (p *ParticipantImpl) SendParticipantUpdate(participantsToUpdate []*livekit.
 		p.updateLock.Unlock()
 		return nil
 	}
+	if window > 0 {
+		participantsToUpdate = p.batchUpdatesLocked(participantsToUpdate, window)
+	}
+	validUpdates := p.validateUpdatesLocked(participantsToUpdate)
+	p.updateLock.Unlock()
+
+	return p.writeParticipantUpdates(validUpdates)
+}
+
+func (p *ParticipantImpl) validateUpdatesLocked(participantsToUpdate []*livekit.ParticipantInfo) []*livekit.ParticipantInfo {
 	validUpdates := make([]*livekit.ParticipantInfo, 0, len(participantsToUpdate))
 	for _, pi := range participantsToUpdate {
 		isValid := true
@@ -108,6 +119,7 @@ func (p *ParticipantImpl) SendParticipantUpdate(participantsToUpdate []*livekit.
 					"version", pi.Version,
 					"lastVersion", lastVersion,
 				)
+				p.addUpdateHistoryLocked(pi, time.Now(), true)
 				isValid = false
 			}
 		}
@@ -116,30 +128,96 @@ func (p *ParticipantImpl) SendParticipantUpdate(participantsToUpdate []*livekit.
 			isValid = false
 		}
 		if isValid {
-			p.updateCache.Add(pID, participantUpdateInfo{
-				identity:  livekit.ParticipantIdentity(pi.Identity),
-				version:   pi.Version,
-				state:     pi.State,
-				updatedAt: time.Now(),
-			})
+			p.cacheUpdateLocked(pi)
 			validUpdates = append(validUpdates, pi)
 		}
 	}
-	p.updateLock.Unlock()
+	return validUpdates
+}
 
-	if len(validUpdates) == 0 {
+func (p *ParticipantImpl) writeParticipantUpdates(updates []*livekit.ParticipantInfo) error {
+	if len(updates) == 0 {
 		return nil
 	}
 
 	return p.writeMessage(&livekit.SignalResponse{
 		Message: &livekit.SignalResponse_Update{
 			Update: &livekit.ParticipantUpdate{
-				Participants: validUpdates,
+				Participants: updates,
 			},
 		},
 	})
 }
 
+func (p *ParticipantImpl) getUpdateBatchWindow() time.Duration {
+	if p.params.GetUpdateBatchWindow == nil {
+		return 0
+	}
+	return p.params.GetUpdateBatchWindow()
+}
+
+// batchUpdatesLocked holds back updates of other participants till the batch window elapses, keeping only the
+// newest version of each participant. Updates of this participant and disconnects are returned to be sent
+// right away. Batched updates go through the same version check as other updates when flushed, so an update
+// superseded by one sent right away is dropped.
+func (p *ParticipantImpl) batchUpdatesLocked(updates []*livekit.ParticipantInfo, window time.Duration) []*livekit.ParticipantInfo {
+	immediate := make([]*livekit.ParticipantInfo, 0, len(updates))
+	for _, pi := range updates {
+		if pi.Sid == string(p.params.SID) || pi.State == livekit.ParticipantInfo_DISCONNECTED {
+			immediate = append(immediate, pi)
+			continue
+		}
+
+		pID := livekit.ParticipantID(pi.Sid)
+		if batched, ok := p.batchedUpdates[pID]; ok && batched.Version > pi.Version {
+			continue
+		}
+		if p.batchedUpdates == nil {
+			p.batchedUpdates = make(map[livekit.ParticipantID]*livekit.ParticipantInfo)
+		}
+		p.batchedUpdates[pID] = pi
+	}
+
+	if len(p.batchedUpdates) != 0 && p.batchedUpdateTimer == nil {
+		p.batchedUpdateTimer = time.AfterFunc(window, p.flushBatchedUpdates)
+	}
+	return immediate
+}
+
+func (p *ParticipantImpl) flushBatchedUpdates() {
+	p.updateLock.Lock()
+	batchedUpdates := p.batchedUpdates
+	p.batchedUpdates = nil
+	p.batchedUpdateTimer = nil
+	if p.IsDisconnected() || len(batchedUpdates) == 0 {
+		p.updateLock.Unlock()
+		return
+	}
+
+	updates := make([]*livekit.ParticipantInfo, 0, len(batchedUpdates))
+	for _, pi := range batchedUpdates {
+		updates = append(updates, pi)
+	}
+	validUpdates := p.validateUpdatesLocked(updates)
+	p.updateLock.Unlock()
+
+	if err := p.writeParticipantUpdates(validUpdates); err != nil {
+		p.params.Logger.Warnw("could not send batched participant updates", err)
+	}
+}
+
+// UpdateBatchWindow scales participant update batch window linearly with room size,
+// from minWindow for an empty room to maxWindow at updateBatchMaxWindowParticipants
+func UpdateBatchWindow(minWindow, maxWindow time.Duration, numParticipants int) time.Duration {
+	if maxWindow <= 0 {
+		return 0
+	}
+	if numParticipants >= updateBatchMaxWindowParticipants {
+		return maxWindow
+	}
+	return minWindow + (maxWindow-minWindow)*time.Duration(numParticipants)/updateBatchMaxWindowParticipants
+}
+
 // SendSpeakerUpdate notifies participant changes to speakers. only send members that have changed since last update
 func (p *ParticipantImpl) SendSpeakerUpdate(speakers []*livekit.SpeakerInfo, force bool) error {
 	if !p.IsReady() {
@@ -218,6 +296,63 @@ func (p *ParticipantImpl) HandleReconnectAndSendResponse(reconnectReason livekit
 	return nil
 }
 
+func (p *ParticipantImpl) cacheUpdateLocked(pi *livekit.ParticipantInfo) {
+	now := time.Now()
+	p.updateCache.Add(livekit.ParticipantID(pi.Sid), participantUpdateInfo{
+		identity:  livekit.ParticipantIdentity(pi.Identity),
+		version:   pi.Version,
+		state:     pi.State,
+		updatedAt: now,
+	})
+	p.addUpdateHistoryLocked(pi, now, false)
+}
+
+func (p *ParticipantImpl) addUpdateHistoryLocked(pi *livekit.ParticipantInfo, at time.Time, outdated bool) {
+	if p.updateHistory == nil {
+		return
+	}
+
+	pID := livekit.ParticipantID(pi.Sid)
+	history, _ := p.updateHistory.Get(pID)
+	if len(history) >= p.params.MaxCachedUpdates {
+		// copy instead of reslicing so that the backing array does not keep growing
+		history = append([]ParticipantUpdateRecord(nil), history[len(history)-p.params.MaxCachedUpdates+1:]...)
+	}
+	history = append(history, ParticipantUpdateRecord{
+		Identity:  livekit.ParticipantIdentity(pi.Identity),
+		Version:   pi.Version,
+		State:     pi.State,
+		UpdatedAt: at,
+		Outdated:  outdated,
+	})
+	p.updateHistory.Add(pID, history)
+}
+
+// GetRecentUpdates returns updates of another participant that were recently handled, oldest first.
+// Only the last sent update is returned unless more updates are cached per participant.
+func (p *ParticipantImpl) GetRecentUpdates(pID livekit.ParticipantID) []ParticipantUpdateRecord {
+	p.updateLock.Lock()
+	defer p.updateLock.Unlock()
+
+	if p.updateHistory != nil {
+		history, _ := p.updateHistory.Peek(pID)
+		return append([]ParticipantUpdateRecord(nil), history...)
+	}
+
+	info, ok := p.updateCache.Peek(pID)
+	if !ok {
+		return nil
+	}
+	return []ParticipantUpdateRecord{
+		{
+			Identity:  info.identity,
+			Version:   info.version,
+			State:     info.state,
+			UpdatedAt: info.updatedAt,
+		},
+	}
+}
+
 func (p *ParticipantImpl) sendDisconnectUpdatesForReconnect() error {
 	lastSignalAt := p.TransportManager.LastSeenSignalAt()
 	var disconnectedParticipants []*livekit.ParticipantInfo
@@ -18,12 +18,12 @@ import (
 	"sync"
 	"time"
 
-	"github.com/frostbyte73/core"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 const (
@@ -33,6 +33,7 @@ const (
 type ParticipantTrafficLoadParams struct {
 	Participant      *ParticipantImpl
 	DataChannelStats *telemetry.BytesTrackStats
+	JobScheduler     *sutils.JobScheduler
 	Logger           logger.Logger
 }
 
@@ -45,7 +46,7 @@ type ParticipantTrafficLoad struct {
 	dataChannelTraffic *telemetry.TrafficTotals
 	trafficLoad        *types.TrafficLoad
 
-	closed core.Fuse
+	reportJob *sutils.ScheduledJob
 }
 
 func NewParticipantTrafficLoad(params ParticipantTrafficLoadParams) *ParticipantTrafficLoad {
@@ -53,12 +54,17 @@ func NewParticipantTrafficLoad(params ParticipantTrafficLoadParams) *Participant
 		params:           params,
 		tracksStatsMedia: make(map[livekit.TrackID]*livekit.RTPStats),
 	}
-	go p.reporter()
+	p.reportJob = params.JobScheduler.Schedule(
+		"traffic-load-"+string(params.Participant.ID()),
+		reportInterval,
+		true,
+		p.report,
+	)
 	return p
 }
 
 func (p *ParticipantTrafficLoad) Close() {
-	p.closed.Break()
+	p.reportJob.Stop()
 }
 
 func (p *ParticipantTrafficLoad) OnTrafficLoad(f func(trafficLoad *types.TrafficLoad)) {
@@ -196,24 +202,16 @@ func (p *ParticipantTrafficLoad) updateTrafficLoad() *types.TrafficLoad {
 
 	p.trafficLoad = &types.TrafficLoad{
 		TrafficTypeStats: trafficTypeStats,
+		DataChannelBufferedAmount: p.params.Participant.DataChannelBufferedAmount(livekit.DataPacket_RELIABLE) +
+			p.params.Participant.DataChannelBufferedAmount(livekit.DataPacket_LOSSY),
+		PacerSettings: p.params.Participant.GetPacerSettings(),
 	}
 	return p.trafficLoad
 }
 
-func (p *ParticipantTrafficLoad) reporter() {
-	ticker := time.NewTicker(reportInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-p.closed.Watch():
-			return
-
-		case <-ticker.C:
-			trafficLoad := p.updateTrafficLoad()
-			if onTrafficLoad := p.getOnTrafficLoad(); onTrafficLoad != nil {
-				onTrafficLoad(trafficLoad)
-			}
-		}
+func (p *ParticipantTrafficLoad) report() {
+	trafficLoad := p.updateTrafficLoad()
+	if onTrafficLoad := p.getOnTrafficLoad(); onTrafficLoad != nil {
+		onTrafficLoad(trafficLoad)
 	}
 }
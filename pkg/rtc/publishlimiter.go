@@ -0,0 +1,82 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// publishLimiter bounds AddTrack requests of a participant, a client looping
+// publish requests would otherwise queue pending tracks indefinitely.
+// Not safe for concurrent use, participant guards it with pendingTracksLock.
+type publishLimiter struct {
+	conf config.PublishLimitConfig
+
+	attempts   map[livekit.TrackSource][]time.Time
+	rejections int
+}
+
+func newPublishLimiter(conf config.PublishLimitConfig) *publishLimiter {
+	return &publishLimiter{
+		conf:     conf,
+		attempts: make(map[livekit.TrackSource][]time.Time),
+	}
+}
+
+// allow records an AddTrack attempt for source and checks it against the rate limit and
+// pending track bounds, given number of pending tracks in total and of the source
+func (l *publishLimiter) allow(source livekit.TrackSource, numPending int, numPendingSource int, now time.Time) error {
+	err := l.check(source, numPending, numPendingSource, now)
+	if err != nil {
+		l.rejections++
+	} else {
+		l.rejections = 0
+	}
+	return err
+}
+
+func (l *publishLimiter) check(source livekit.TrackSource, numPending int, numPendingSource int, now time.Time) error {
+	if l.conf.AddTrackPerSource > 0 && l.conf.AddTrackWindow > 0 {
+		attempts := l.attempts[source]
+		expired := 0
+		for expired < len(attempts) && now.Sub(attempts[expired]) >= l.conf.AddTrackWindow {
+			expired++
+		}
+		attempts = append(attempts[:0], attempts[expired:]...)
+		if len(attempts) >= l.conf.AddTrackPerSource {
+			l.attempts[source] = attempts
+			return fmt.Errorf("%w, %d requests in %s", ErrPublishRateExceeded, len(attempts), l.conf.AddTrackWindow)
+		}
+		l.attempts[source] = append(attempts, now)
+	}
+
+	if l.conf.MaxPendingTracks > 0 && numPending >= l.conf.MaxPendingTracks {
+		return fmt.Errorf("%w, %d pending", ErrTooManyPendingTracks, numPending)
+	}
+	if l.conf.MaxPendingTracksPerSource > 0 && numPendingSource >= l.conf.MaxPendingTracksPerSource {
+		return fmt.Errorf("%w, %d pending for source", ErrTooManyPendingTracks, numPendingSource)
+	}
+	return nil
+}
+
+// shouldClose returns true when requests have been rejected consistently
+func (l *publishLimiter) shouldClose() bool {
+	return l.conf.MaxRejections > 0 && l.rejections >= l.conf.MaxRejections
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestPublishLimiter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		l := newPublishLimiter(config.PublishLimitConfig{})
+		now := time.Now()
+		for i := 0; i < 100; i++ {
+			require.NoError(t, l.allow(livekit.TrackSource_CAMERA, i, i, now))
+		}
+		require.False(t, l.shouldClose())
+	})
+
+	t.Run("rate per source", func(t *testing.T) {
+		l := newPublishLimiter(config.PublishLimitConfig{
+			AddTrackPerSource: 2,
+			AddTrackWindow:    10 * time.Second,
+			MaxRejections:     2,
+		})
+		now := time.Now()
+		require.NoError(t, l.allow(livekit.TrackSource_CAMERA, 0, 0, now))
+		require.NoError(t, l.allow(livekit.TrackSource_CAMERA, 0, 0, now.Add(time.Second)))
+		require.ErrorIs(t, l.allow(livekit.TrackSource_CAMERA, 0, 0, now.Add(2*time.Second)), ErrPublishRateExceeded)
+		require.False(t, l.shouldClose())
+
+		// other sources are limited independently
+		require.NoError(t, l.allow(livekit.TrackSource_MICROPHONE, 0, 0, now.Add(2*time.Second)))
+
+		// rejections are counted consecutively
+		require.ErrorIs(t, l.allow(livekit.TrackSource_CAMERA, 0, 0, now.Add(3*time.Second)), ErrPublishRateExceeded)
+		require.ErrorIs(t, l.allow(livekit.TrackSource_CAMERA, 0, 0, now.Add(4*time.Second)), ErrPublishRateExceeded)
+		require.True(t, l.shouldClose())
+
+		// allowed once the first attempt leaves the window
+		require.NoError(t, l.allow(livekit.TrackSource_CAMERA, 0, 0, now.Add(10*time.Second)))
+		require.False(t, l.shouldClose())
+		require.ErrorIs(t, l.allow(livekit.TrackSource_CAMERA, 0, 0, now.Add(10*time.Second)), ErrPublishRateExceeded)
+	})
+
+	t.Run("pending bounds", func(t *testing.T) {
+		l := newPublishLimiter(config.PublishLimitConfig{
+			MaxPendingTracks:          4,
+			MaxPendingTracksPerSource: 2,
+		})
+		now := time.Now()
+		require.NoError(t, l.allow(livekit.TrackSource_CAMERA, 3, 1, now))
+		require.ErrorIs(t, l.allow(livekit.TrackSource_CAMERA, 3, 2, now), ErrTooManyPendingTracks)
+		require.ErrorIs(t, l.allow(livekit.TrackSource_CAMERA, 4, 0, now), ErrTooManyPendingTracks)
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/gammazero/deque"
+)
+
+type reliableDataPacket struct {
+	encoded []byte
+	// false when the packet could not be written as the data channel was closed
+	isWritten bool
+}
+
+// reliableDataHistory keeps the most recent reliable data packets sent to a participant,
+// so that packets the client has not acknowledged when its session ends can be sent to its next session.
+// Oldest packets are evicted when either the packet or the byte limit is exceeded, 0 means no limit.
+type reliableDataHistory struct {
+	maxPackets int
+	maxBytes   int
+
+	lock    sync.Mutex
+	packets deque.Deque[reliableDataPacket]
+	bytes   int
+}
+
+// newReliableDataHistory returns nil when both limits are 0, history is not kept then
+func newReliableDataHistory(maxPackets int, maxBytes int) *reliableDataHistory {
+	if maxPackets <= 0 && maxBytes <= 0 {
+		return nil
+	}
+
+	h := &reliableDataHistory{
+		maxPackets: max(maxPackets, 0),
+		maxBytes:   max(maxBytes, 0),
+	}
+	h.packets.SetMinCapacity(6)
+	return h
+}
+
+func (h *reliableDataHistory) add(encoded []byte, isWritten bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.packets.PushBack(reliableDataPacket{encoded: encoded, isWritten: isWritten})
+	h.bytes += len(encoded)
+
+	for h.packets.Len() != 0 &&
+		((h.maxPackets != 0 && h.packets.Len() > h.maxPackets) || (h.maxBytes != 0 && h.bytes > h.maxBytes)) {
+		evicted := h.packets.PopFront()
+		h.bytes -= len(evicted.encoded)
+	}
+}
+
+// unacknowledged returns, in order, the most recent written packets covering the given number of bytes
+// still buffered on the data channel, i.e. not acknowledged by the client yet, and the packets not written.
+func (h *reliableDataHistory) unacknowledged(bufferedAmount uint64) [][]byte {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var packets [][]byte
+	covered := uint64(0)
+	for i := h.packets.Len() - 1; i >= 0; i-- {
+		p := h.packets.At(i)
+		if p.isWritten {
+			if covered >= bufferedAmount {
+				continue
+			}
+			covered += uint64(len(p.encoded))
+		}
+		packets = append(packets, p.encoded)
+	}
+	slices.Reverse(packets)
+	return packets
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReliableDataHistory(t *testing.T) {
+	packet := func(b byte, size int) []byte {
+		encoded := make([]byte, size)
+		encoded[0] = b
+		return encoded
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		require.Nil(t, newReliableDataHistory(0, 0))
+	})
+
+	t.Run("unacknowledged", func(t *testing.T) {
+		h := newReliableDataHistory(10, 0)
+		for i := byte(1); i <= 3; i++ {
+			h.add(packet(i, 100), true)
+		}
+
+		require.Empty(t, h.unacknowledged(0))
+		require.Equal(t, [][]byte{packet(3, 100)}, h.unacknowledged(100))
+		// partially acknowledged packet is sent again in full
+		require.Equal(t, [][]byte{packet(2, 100), packet(3, 100)}, h.unacknowledged(150))
+		require.Equal(t, [][]byte{packet(1, 100), packet(2, 100), packet(3, 100)}, h.unacknowledged(1000))
+
+		// not written is always unacknowledged
+		h.add(packet(4, 100), false)
+		require.Equal(t, [][]byte{packet(4, 100)}, h.unacknowledged(0))
+		require.Equal(t, [][]byte{packet(3, 100), packet(4, 100)}, h.unacknowledged(100))
+	})
+
+	t.Run("packet limit", func(t *testing.T) {
+		h := newReliableDataHistory(3, 0)
+		for i := byte(1); i <= 5; i++ {
+			h.add(packet(i, 100), true)
+		}
+
+		require.Equal(t, [][]byte{packet(3, 100), packet(4, 100), packet(5, 100)}, h.unacknowledged(1000))
+	})
+
+	t.Run("byte limit", func(t *testing.T) {
+		h := newReliableDataHistory(0, 250)
+		for i := byte(1); i <= 5; i++ {
+			h.add(packet(i, 100), true)
+		}
+		require.Equal(t, [][]byte{packet(4, 100), packet(5, 100)}, h.unacknowledged(1000))
+
+		// larger than limit is not kept
+		h.add(packet(6, 300), true)
+		require.Empty(t, h.unacknowledged(1000))
+	})
+}
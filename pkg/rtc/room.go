@@ -116,8 +116,14 @@ type Room struct {
 	batchedUpdates   map[livekit.ParticipantIdentity]*participantUpdate
 	batchedUpdatesMu sync.Mutex
 
+	// track SIDs of departed participants, kept for reuse on reconnect, guarded by lock
+	trackSIDReuseWindow time.Duration
+	departedTrackSIDs   map[livekit.ParticipantIdentity]*departedTrackSIDs
+
 	closed chan struct{}
 
+	connectionQualityJob *sutils.ScheduledJob
+
 	trailer []byte
 
 	onParticipantChanged func(p types.LocalParticipant)
@@ -131,6 +137,14 @@ type Room struct {
 
 type ParticipantOptions struct {
 	AutoSubscribe bool
+	// participant migrated in with resume state exported by a draining node, client resumes its session
+	Migration       bool
+	ReconnectReason livekit.ReconnectReason
+}
+
+type departedTrackSIDs struct {
+	trackSIDs map[TrackSIDKey]livekit.TrackID
+	expiresAt time.Time
 }
 
 func NewRoom(
@@ -163,8 +177,10 @@ func NewRoom(
 		participantOpts:                      make(map[livekit.ParticipantIdentity]*ParticipantOptions),
 		participantRequestSources:            make(map[livekit.ParticipantIdentity]routing.MessageSource),
 		hasPublished:                         make(map[livekit.ParticipantIdentity]bool),
-		bufferFactory:                        buffer.NewFactoryOfBufferFactory(config.Receiver.PacketBufferSizeVideo, config.Receiver.PacketBufferSizeAudio),
+		bufferFactory:                        buffer.NewFactoryOfBufferFactory(config.Receiver.PacketBufferSizeVideo, config.Receiver.PacketBufferSizeAudio, config.Receiver.ClockSkew),
 		batchedUpdates:                       make(map[livekit.ParticipantIdentity]*participantUpdate),
+		trackSIDReuseWindow:                  roomConfig.TrackSIDReuseWindow,
+		departedTrackSIDs:                    make(map[livekit.ParticipantIdentity]*departedTrackSIDs),
 		closed:                               make(chan struct{}),
 		trailer:                              []byte(utils.RandomSecret()),
 		disconnectSignalOnResumeParticipants: make(map[livekit.ParticipantIdentity]time.Time),
@@ -183,7 +199,7 @@ func NewRoom(
 	r.protoProxy = utils.NewProtoProxy[*livekit.Room](roomUpdateInterval, r.updateProto)
 
 	go r.audioUpdateWorker()
-	go r.connectionQualityWorker()
+	r.scheduleConnectionQualityUpdates()
 	go r.changeUpdateWorker()
 	go r.simulationCleanupWorker()
 
@@ -439,13 +455,21 @@ func (r *Room) Join(participant types.LocalParticipant, requestSource routing.Me
 		}
 	})
 
-	joinResponse := r.createJoinResponseLocked(participant, iceServers)
-	if err := participant.SendJoinResponse(joinResponse); err != nil {
-		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "send_response").Add(1)
-		return err
-	}
+	if opts != nil && opts.Migration {
+		// there is no join response when migrating, migration completes once transports are connected
+		if err := r.sendMigratedParticipantResponseLocked(participant, iceServers, opts.ReconnectReason); err != nil {
+			prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "send_response").Add(1)
+			return err
+		}
+	} else {
+		joinResponse := r.createJoinResponseLocked(participant, iceServers)
+		if err := participant.SendJoinResponse(joinResponse); err != nil {
+			prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "send_response").Add(1)
+			return err
+		}
 
-	participant.SetMigrateState(types.MigrateStateComplete)
+		participant.SetMigrateState(types.MigrateStateComplete)
+	}
 
 	if participant.SubscriberAsPrimary() {
 		// initiates sub connection as primary
@@ -594,9 +618,11 @@ func (r *Room) RemoveParticipant(identity livekit.ParticipantIdentity, pID livek
 	sendUpdates := !p.IsDisconnected()
 
 	// remove all published tracks
-	for _, t := range p.GetPublishedTracks() {
+	publishedTracks := p.GetPublishedTracks()
+	for _, t := range publishedTracks {
 		r.trackManager.RemoveTrack(t)
 	}
+	r.recordDepartedTrackSIDs(identity, publishedTracks)
 
 	p.OnTrackUpdated(nil)
 	p.OnTrackPublished(nil)
@@ -697,6 +723,11 @@ func (r *Room) SyncState(participant types.LocalParticipant, state *livekit.Sync
 		state.Subscription.ParticipantTracks,
 		state.Subscription.Subscribe,
 	)
+
+	// a participant migrating in is waiting on client state to process its publisher offer
+	if participant.MigrateState() == types.MigrateStateInit {
+		participant.SetMigrateState(types.MigrateStateSync)
+	}
 	return nil
 }
 
@@ -710,6 +741,54 @@ func (r *Room) UpdateSubscriptionPermission(participant types.LocalParticipant,
 	return nil
 }
 
+// TakePreviousTrackSIDs returns track SIDs published by a departed participant with the given identity
+// within the reuse window. SIDs are handed out only once.
+func (r *Room) TakePreviousTrackSIDs(identity livekit.ParticipantIdentity) map[TrackSIDKey]livekit.TrackID {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	departed := r.departedTrackSIDs[identity]
+	if departed == nil {
+		return nil
+	}
+
+	delete(r.departedTrackSIDs, identity)
+	if time.Now().After(departed.expiresAt) {
+		return nil
+	}
+	return departed.trackSIDs
+}
+
+func (r *Room) recordDepartedTrackSIDs(identity livekit.ParticipantIdentity, publishedTracks []types.MediaTrack) {
+	if r.trackSIDReuseWindow <= 0 {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	for id, departed := range r.departedTrackSIDs {
+		if now.After(departed.expiresAt) {
+			delete(r.departedTrackSIDs, id)
+		}
+	}
+
+	if len(publishedTracks) == 0 {
+		delete(r.departedTrackSIDs, identity)
+		return
+	}
+
+	trackSIDs := make(map[TrackSIDKey]livekit.TrackID, len(publishedTracks))
+	for _, t := range publishedTracks {
+		trackSIDs[TrackSIDKeyFromInfo(t.ToProto())] = t.ID()
+	}
+	r.departedTrackSIDs[identity] = &departedTrackSIDs{
+		trackSIDs: trackSIDs,
+		expiresAt: now.Add(r.trackSIDReuseWindow),
+	}
+}
+
 func (r *Room) ResolveMediaTrackForSubscriber(subIdentity livekit.ParticipantIdentity, trackID livekit.TrackID) types.MediaResolverResult {
 	res := types.MediaResolverResult{}
 
@@ -794,6 +873,7 @@ func (r *Room) Close(reason types.ParticipantCloseReason) {
 	}
 
 	r.protoProxy.Stop()
+	r.connectionQualityJob.Stop()
 
 	if r.onClose != nil {
 		r.onClose()
@@ -932,19 +1012,22 @@ func (r *Room) autoSubscribe(participant types.LocalParticipant) bool {
 	return true
 }
 
-func (r *Room) createJoinResponseLocked(participant types.LocalParticipant, iceServers []*livekit.ICEServer) *livekit.JoinResponse {
-	// gather other participants and send join response
+func (r *Room) getOtherParticipantInfoLocked(participant types.LocalParticipant) []*livekit.ParticipantInfo {
 	otherParticipants := make([]*livekit.ParticipantInfo, 0, len(r.participants))
 	for _, p := range r.participants {
 		if p.ID() != participant.ID() && !p.Hidden() {
 			otherParticipants = append(otherParticipants, p.ToProto())
 		}
 	}
+	return otherParticipants
+}
 
+func (r *Room) createJoinResponseLocked(participant types.LocalParticipant, iceServers []*livekit.ICEServer) *livekit.JoinResponse {
+	// gather other participants and send join response
 	return &livekit.JoinResponse{
 		Room:              r.ToProto(),
 		Participant:       participant.ToProto(),
-		OtherParticipants: otherParticipants,
+		OtherParticipants: r.getOtherParticipantInfoLocked(participant),
 		IceServers:        iceServers,
 		// indicates both server and client support subscriber as primary
 		SubscriberPrimary:   participant.SubscriberAsPrimary(),
@@ -959,6 +1042,29 @@ func (r *Room) createJoinResponseLocked(participant types.LocalParticipant, iceS
 	}
 }
 
+// sendMigratedParticipantResponseLocked responds to a participant migrated in as to a resumed one
+func (r *Room) sendMigratedParticipantResponseLocked(
+	participant types.LocalParticipant,
+	iceServers []*livekit.ICEServer,
+	reason livekit.ReconnectReason,
+) error {
+	if err := participant.HandleReconnectAndSendResponse(reason, &livekit.ReconnectResponse{
+		IceServers:          iceServers,
+		ClientConfiguration: participant.GetClientConfiguration(),
+	}); err != nil {
+		return err
+	}
+
+	// include the local participant's info as well, since it has a new node
+	updates := append(r.getOtherParticipantInfoLocked(participant), participant.ToProto())
+	if err := participant.SendParticipantUpdate(updates); err != nil {
+		return err
+	}
+
+	_ = participant.SendRoomUpdate(r.ToProto())
+	return nil
+}
+
 // a ParticipantImpl in the room added a new track, subscribe other participants to it
 func (r *Room) onTrackPublished(participant types.LocalParticipant, track types.MediaTrack) {
 	// publish participant update, since track state is changed
@@ -1307,86 +1413,106 @@ func (r *Room) audioUpdateWorker() {
 	}
 }
 
-func (r *Room) connectionQualityWorker() {
-	ticker := time.NewTicker(connectionquality.UpdateInterval)
-	defer ticker.Stop()
-
+func (r *Room) scheduleConnectionQualityUpdates() {
 	prevConnectionInfos := make(map[livekit.ParticipantID]*livekit.ConnectionQualityInfo)
-	// send updates to only users that are subscribed to each other
-	for !r.IsClosed() {
-		<-ticker.C
+	r.connectionQualityJob = r.config.JobScheduler.Schedule(
+		fmt.Sprintf("connection-quality-%s", r.protoRoom.Sid),
+		connectionquality.UpdateInterval,
+		true,
+		func() {
+			prevConnectionInfos = r.updateConnectionQuality(prevConnectionInfos)
+		},
+	)
+}
 
-		participants := r.GetParticipants()
-		nowConnectionInfos := make(map[livekit.ParticipantID]*livekit.ConnectionQualityInfo, len(participants))
+// updateConnectionQuality sends updates to only users that are subscribed to each other,
+// returns connection quality of ACTIVE participants to compare against in next update
+func (r *Room) updateConnectionQuality(
+	prevConnectionInfos map[livekit.ParticipantID]*livekit.ConnectionQualityInfo,
+) map[livekit.ParticipantID]*livekit.ConnectionQualityInfo {
+	if r.IsClosed() {
+		return prevConnectionInfos
+	}
 
-		for _, p := range participants {
-			if p.State() != livekit.ParticipantInfo_ACTIVE {
-				continue
-			}
+	participants := r.GetParticipants()
+	nowConnectionInfos := make(map[livekit.ParticipantID]*livekit.ConnectionQualityInfo, len(participants))
 
-			if q := p.GetConnectionQuality(); q != nil {
-				nowConnectionInfos[p.ID()] = q
-			}
+	for _, p := range participants {
+		if p.State() != livekit.ParticipantInfo_ACTIVE {
+			continue
 		}
 
-		// send an update if there is a change
-		//   - new participant
-		//   - quality change
-		// NOTE: participant leaving is explicitly omitted as `leave` signal notifies that a participant is not in the room anymore
-		sendUpdate := false
-		for _, p := range participants {
-			pID := p.ID()
-			prevInfo, prevOk := prevConnectionInfos[pID]
-			nowInfo, nowOk := nowConnectionInfos[pID]
-			if !nowOk {
-				// participant is not ACTIVE any more
-				continue
-			}
-			if !prevOk || nowInfo.Quality != prevInfo.Quality {
-				// new entrant OR change in quality
-				sendUpdate = true
-				break
+		if q := p.GetConnectionQuality(); q != nil {
+			if prevInfo, ok := prevConnectionInfos[p.ID()]; ok && prevInfo.Quality != q.Quality {
+				r.Logger.Debugw("connection quality changed",
+					"pID", p.ID(),
+					"participant", p.Identity(),
+					"quality", q.Quality,
+					"signalingRTT", p.GetSignalingRTT(),
+				)
 			}
+			nowConnectionInfos[p.ID()] = q
 		}
+	}
 
-		if !sendUpdate {
-			prevConnectionInfos = nowConnectionInfos
+	// send an update if there is a change
+	//   - new participant
+	//   - quality change
+	// NOTE: participant leaving is explicitly omitted as `leave` signal notifies that a participant is not in the room anymore
+	sendUpdate := false
+	for _, p := range participants {
+		pID := p.ID()
+		prevInfo, prevOk := prevConnectionInfos[pID]
+		nowInfo, nowOk := nowConnectionInfos[pID]
+		if !nowOk {
+			// participant is not ACTIVE any more
 			continue
 		}
-
-		maybeAddToUpdate := func(pID livekit.ParticipantID, update *livekit.ConnectionQualityUpdate) {
-			if nowInfo, nowOk := nowConnectionInfos[pID]; nowOk {
-				update.Updates = append(update.Updates, nowInfo)
-			}
+		if !prevOk || nowInfo.Quality != prevInfo.Quality {
+			// new entrant OR change in quality
+			sendUpdate = true
+			break
 		}
+	}
 
-		for _, op := range participants {
-			if !op.ProtocolVersion().SupportsConnectionQuality() || op.State() != livekit.ParticipantInfo_ACTIVE {
-				continue
-			}
-			update := &livekit.ConnectionQualityUpdate{}
+	if !sendUpdate {
+		return nowConnectionInfos
+	}
 
-			// send to user itself
-			maybeAddToUpdate(op.ID(), update)
+	maybeAddToUpdate := func(pID livekit.ParticipantID, update *livekit.ConnectionQualityUpdate) {
+		if nowInfo, nowOk := nowConnectionInfos[pID]; nowOk {
+			update.Updates = append(update.Updates, nowInfo)
+		}
+	}
 
-			// add connection quality of other participants its subscribed to
-			for _, sid := range op.GetSubscribedParticipants() {
-				maybeAddToUpdate(sid, update)
-			}
-			if len(update.Updates) == 0 {
-				// no change
-				continue
-			}
-			if err := op.SendConnectionQualityUpdate(update); err != nil {
-				r.Logger.Warnw("could not send connection quality update", err,
-					"participant", op.Identity())
-			}
+	for _, op := range participants {
+		if !op.ProtocolVersion().SupportsConnectionQuality() || op.State() != livekit.ParticipantInfo_ACTIVE {
+			continue
 		}
+		update := &livekit.ConnectionQualityUpdate{}
 
-		prevConnectionInfos = nowConnectionInfos
+		// send to user itself
+		maybeAddToUpdate(op.ID(), update)
+
+		// add connection quality of other participants its subscribed to
+		for _, sid := range op.GetSubscribedParticipants() {
+			maybeAddToUpdate(sid, update)
+		}
+		if len(update.Updates) == 0 {
+			// no change
+			continue
+		}
+		if err := op.SendConnectionQualityUpdate(update); err != nil {
+			r.Logger.Warnw("could not send connection quality update", err,
+				"participant", op.Identity())
+		}
 	}
+
+	return nowConnectionInfos
 }
 
+// ------------------------------------------------------------
+
 func (r *Room) simulationCleanupWorker() {
 	for {
 		if r.IsClosed() {
@@ -1495,9 +1621,15 @@ func BroadcastDataPacketForRoom(r types.Room, source types.LocalParticipant, kin
 	}
 
 	utils.ParallelExec(destParticipants, dataForwardLoadBalanceThreshold, 1, func(op types.LocalParticipant) {
+		// lossy data is dropped for congested participants, reliable data is held back by participant till it drains
+		if kind == livekit.DataPacket_LOSSY && op.IsDataChannelBackpressured(kind) {
+			return
+		}
+
 		err := op.SendDataPacket(kind, dpData)
 		if err != nil && !errors.Is(err, io.ErrClosedPipe) && !errors.Is(err, sctp.ErrStreamClosed) &&
-			!errors.Is(err, ErrTransportFailure) && !errors.Is(err, ErrDataChannelBufferFull) {
+			!errors.Is(err, ErrTransportFailure) && !errors.Is(err, ErrDataChannelBufferFull) &&
+			!errors.Is(err, ErrReliableDataRateExceeded) {
 			op.GetLogger().Infow("send data packet error", "error", err)
 		}
 	})
@@ -84,6 +84,52 @@ func TestJoinedState(t *testing.T) {
 	})
 }
 
+func TestRoomPreviousTrackSIDs(t *testing.T) {
+	setup := func(t *testing.T) (*Room, types.LocalParticipant) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		rm.trackSIDReuseWindow = time.Minute
+
+		p0 := rm.GetParticipants()[0]
+		track := &typesfakes.FakeMediaTrack{}
+		track.IDReturns("TR_camera")
+		track.ToProtoReturns(&livekit.TrackInfo{
+			Sid:    "TR_camera",
+			Type:   livekit.TrackType_VIDEO,
+			Source: livekit.TrackSource_CAMERA,
+			Name:   "camera",
+		})
+		p0.(*typesfakes.FakeLocalParticipant).GetPublishedTracksReturns([]types.MediaTrack{track})
+		return rm, p0
+	}
+
+	t.Run("handed out once", func(t *testing.T) {
+		rm, p0 := setup(t)
+		rm.RemoveParticipant(p0.Identity(), p0.ID(), types.ParticipantCloseReasonDuplicateIdentity)
+
+		trackSIDs := rm.TakePreviousTrackSIDs(p0.Identity())
+		require.Equal(t, map[TrackSIDKey]livekit.TrackID{
+			{Type: livekit.TrackType_VIDEO, Source: livekit.TrackSource_CAMERA, Name: "camera"}: "TR_camera",
+		}, trackSIDs)
+		require.Nil(t, rm.TakePreviousTrackSIDs(p0.Identity()))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		rm, p0 := setup(t)
+		rm.RemoveParticipant(p0.Identity(), p0.ID(), types.ParticipantCloseReasonDuplicateIdentity)
+		rm.departedTrackSIDs[p0.Identity()].expiresAt = time.Now().Add(-time.Second)
+
+		require.Nil(t, rm.TakePreviousTrackSIDs(p0.Identity()))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rm, p0 := setup(t)
+		rm.trackSIDReuseWindow = 0
+		rm.RemoveParticipant(p0.Identity(), p0.ID(), types.ParticipantCloseReasonDuplicateIdentity)
+
+		require.Nil(t, rm.TakePreviousTrackSIDs(p0.Identity()))
+	})
+}
+
 func TestRoomJoin(t *testing.T) {
 	t.Run("joining returns existing participant data", func(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: numParticipants})
@@ -152,6 +198,34 @@ func TestRoomJoin(t *testing.T) {
 		require.Equal(t, numParticipants-2, numUpdates)
 	})
 
+	t.Run("migrated participant resumes its session", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: numParticipants})
+		pNew := NewMockParticipant("new", types.CurrentProtocol, false, false)
+
+		require.NoError(t, rm.Join(pNew, nil, &ParticipantOptions{
+			AutoSubscribe:   true,
+			Migration:       true,
+			ReconnectReason: livekit.ReconnectReason_RR_SIGNAL_DISCONNECTED,
+		}, iceServersForRoom))
+
+		// no join response, migration completes once transports are connected
+		require.Zero(t, pNew.SendJoinResponseCallCount())
+		require.Zero(t, pNew.SetMigrateStateCallCount())
+
+		require.Equal(t, 1, pNew.HandleReconnectAndSendResponseCallCount())
+		reason, res := pNew.HandleReconnectAndSendResponseArgsForCall(0)
+		require.Equal(t, livekit.ReconnectReason_RR_SIGNAL_DISCONNECTED, reason)
+		require.NotEmpty(t, res.IceServers)
+		require.Len(t, pNew.SendParticipantUpdateArgsForCall(0), numParticipants+1)
+		require.Equal(t, 1, pNew.SendRoomUpdateCallCount())
+
+		// client state moves migration to sync
+		pNew.MigrateStateReturns(types.MigrateStateInit)
+		require.NoError(t, rm.SyncState(pNew, &livekit.SyncState{Subscription: &livekit.UpdateSubscription{}}))
+		require.Equal(t, 1, pNew.SetMigrateStateCallCount())
+		require.Equal(t, types.MigrateStateSync, pNew.SetMigrateStateArgsForCall(0))
+	})
+
 	t.Run("cannot exceed max participants", func(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
 		rm.lock.Lock()
@@ -175,7 +249,7 @@ func TestParticipantUpdate(t *testing.T) {
 			"track mutes are sent to everyone",
 			true,
 			func(p types.LocalParticipant) {
-				p.SetTrackMuted("", true, false)
+				p.SetTrackMuted("", true, nil)
 			},
 		},
 		{
@@ -719,6 +793,31 @@ func TestDataChannel(t *testing.T) {
 	})
 }
 
+func TestDataChannelBackpressure(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 3})
+	defer rm.Close(types.ParticipantCloseReasonNone)
+	participants := rm.GetParticipants()
+	p := participants[0].(*typesfakes.FakeLocalParticipant)
+	congested := participants[1].(*typesfakes.FakeLocalParticipant)
+	congested.IsDataChannelBackpressuredReturns(true)
+	other := participants[2].(*typesfakes.FakeLocalParticipant)
+
+	for _, kind := range []livekit.DataPacket_Kind{livekit.DataPacket_LOSSY, livekit.DataPacket_RELIABLE} {
+		p.OnDataPacketArgsForCall(0)(p, kind, &livekit.DataPacket{
+			Kind: kind,
+			Value: &livekit.DataPacket_User{
+				User: &livekit.UserPacket{Payload: []byte("message..")},
+			},
+		})
+	}
+
+	// lossy is skipped for the congested participant, reliable is left to participant to hold back
+	require.Equal(t, 2, other.SendDataPacketCallCount())
+	require.Equal(t, 1, congested.SendDataPacketCallCount())
+	kind, _ := congested.SendDataPacketArgsForCall(0)
+	require.Equal(t, livekit.DataPacket_RELIABLE, kind)
+}
+
 func TestHiddenParticipants(t *testing.T) {
 	t.Run("other participants don't receive hidden updates", func(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: 2, numHidden: 1})
@@ -796,7 +895,7 @@ func newRoomWithParticipants(t *testing.T, opts testRoomOpts) *Room {
 	rm := NewRoom(
 		&livekit.Room{Name: "room"},
 		nil,
-		WebRTCConfig{},
+		WebRTCConfig{JobScheduler: testJobScheduler},
 		config.RoomConfig{
 			EmptyTimeout:     5 * 60,
 			DepartureTimeout: 1,
@@ -44,7 +44,11 @@ func HandleParticipantSignal(room types.Room, participant types.LocalParticipant
 		participant.AddTrack(msg.AddTrack)
 
 	case *livekit.SignalRequest_Mute:
-		participant.SetTrackMuted(livekit.TrackID(msg.Mute.Sid), msg.Mute.Muted, false)
+		participant.SetTrackMuted(livekit.TrackID(msg.Mute.Sid), msg.Mute.Muted, nil)
+
+	case *livekit.SignalRequest_UpdateLayers:
+		// a publisher sending none of the layers of a track has paused it
+		participant.HandleTrackPauseRequest(livekit.TrackID(msg.UpdateLayers.TrackSid), len(msg.UpdateLayers.Layers) == 0)
 
 	case *livekit.SignalRequest_Subscription:
 		// allow participant to indicate their interest in the subscription
@@ -57,6 +61,10 @@ func HandleParticipantSignal(room types.Room, participant types.LocalParticipant
 		)
 
 	case *livekit.SignalRequest_TrackSetting:
+		if len(msg.TrackSetting.TrackSids) == 0 {
+			// settings without tracks toggle audio only mode, disabling all video subscriptions
+			participant.HandleSubscriberAudioOnlyRequest(msg.TrackSetting.Disabled)
+		}
 		for _, sid := range livekit.StringsAsIDs[livekit.TrackID](msg.TrackSetting.TrackSids) {
 			participant.UpdateSubscribedTrackSettings(sid, msg.TrackSetting)
 		}
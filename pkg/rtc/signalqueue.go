@@ -0,0 +1,230 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+type signalPriority int
+
+const (
+	// negotiation, publishing and leave, never dropped
+	signalPriorityCritical signalPriority = iota
+	signalPrioritySubscription
+	signalPrioritySettings
+	signalPriorityMetadata
+
+	numSignalPriorities
+
+	// only requests of the lowest priority are dropped when the queue is full
+	signalPriorityLowest = numSignalPriorities - 1
+)
+
+func signalRequestPriority(req *livekit.SignalRequest) signalPriority {
+	switch req.GetMessage().(type) {
+	case *livekit.SignalRequest_Subscription, *livekit.SignalRequest_SubscriptionPermission:
+		return signalPrioritySubscription
+
+	case *livekit.SignalRequest_TrackSetting, *livekit.SignalRequest_UpdateAudioTrack, *livekit.SignalRequest_UpdateVideoTrack:
+		return signalPrioritySettings
+
+	case *livekit.SignalRequest_UpdateMetadata:
+		return signalPriorityMetadata
+
+	default:
+		return signalPriorityCritical
+	}
+}
+
+// SignalRequestType returns the name of the message set in the request, for example "offer"
+func SignalRequestType(req *livekit.SignalRequest) string {
+	m := req.ProtoReflect()
+	if fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("message")); fd != nil {
+		return string(fd.Name())
+	}
+	return "unknown"
+}
+
+// ---------------------------------------------
+
+type SignalQueueParams struct {
+	// maximum number of queued requests, 0 is unbounded
+	MaxSize int
+	// called with requests dropped because the queue is full
+	OnDropped func(req *livekit.SignalRequest)
+}
+
+type queuedSignalRequest struct {
+	req        *livekit.SignalRequest
+	enqueuedAt time.Time
+}
+
+// SignalQueue holds incoming signal requests of a participant so that a burst of
+// low priority requests does not delay negotiation. Requests are handled by priority
+// and in arrival order within a priority.
+type SignalQueue struct {
+	params SignalQueueParams
+
+	lock   sync.Mutex
+	queues [numSignalPriorities][]queuedSignalRequest
+	size   int
+	closed bool
+	notify chan struct{}
+}
+
+func NewSignalQueue(params SignalQueueParams) *SignalQueue {
+	return &SignalQueue{
+		params: params,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Enqueue adds a request to the queue. Only requests of the lowest priority are dropped,
+// when the queue is full the newest one of those is dropped, which may be the given request,
+// and an error is returned. Requests of other priorities are added even if the queue is full.
+// Requests are not accepted once the queue is drained or closed.
+func (q *SignalQueue) Enqueue(req *livekit.SignalRequest) error {
+	priority := signalRequestPriority(req)
+
+	q.lock.Lock()
+	if q.closed {
+		q.lock.Unlock()
+		return fmt.Errorf("%w, dropped %s", ErrSignalQueueClosed, SignalRequestType(req))
+	}
+
+	if q.params.MaxSize > 0 && q.size >= q.params.MaxSize {
+		if priority == signalPriorityLowest {
+			q.lock.Unlock()
+			return q.dropped(req)
+		}
+
+		if last := len(q.queues[signalPriorityLowest]) - 1; last >= 0 {
+			dropped := q.queues[signalPriorityLowest][last].req
+			q.queues[signalPriorityLowest][last] = queuedSignalRequest{}
+			q.queues[signalPriorityLowest] = q.queues[signalPriorityLowest][:last]
+			q.queues[priority] = append(q.queues[priority], queuedSignalRequest{req: req, enqueuedAt: time.Now()})
+			q.lock.Unlock()
+
+			q.signal()
+			return q.dropped(dropped)
+		}
+	}
+
+	q.queues[priority] = append(q.queues[priority], queuedSignalRequest{req: req, enqueuedAt: time.Now()})
+	q.size++
+	q.lock.Unlock()
+
+	q.signal()
+	prometheus.AddSignalQueueDepth(1)
+	return nil
+}
+
+// Process calls handle for queued requests until the queue is closed and drained,
+// or handle returns an error.
+func (q *SignalQueue) Process(handle func(req *livekit.SignalRequest) error) error {
+	for {
+		qr, ok, closed := q.dequeue()
+		if !ok {
+			if closed {
+				return nil
+			}
+			<-q.notify
+			continue
+		}
+
+		err := handle(qr.req)
+		prometheus.RecordSignalRequestHandled(SignalRequestType(qr.req), time.Since(qr.enqueuedAt))
+		if err != nil {
+			q.discard()
+			return err
+		}
+	}
+}
+
+// Drain stops accepting requests, already queued requests are still processed
+func (q *SignalQueue) Drain() {
+	q.lock.Lock()
+	q.closed = true
+	q.lock.Unlock()
+
+	q.signal()
+}
+
+// Close stops accepting requests and flushes queued requests without handling them
+func (q *SignalQueue) Close() {
+	q.discard()
+	q.signal()
+}
+
+func (q *SignalQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.size
+}
+
+func (q *SignalQueue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *SignalQueue) dequeue() (queuedSignalRequest, bool, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for p := range q.queues {
+		if len(q.queues[p]) == 0 {
+			continue
+		}
+
+		qr := q.queues[p][0]
+		q.queues[p][0] = queuedSignalRequest{}
+		q.queues[p] = q.queues[p][1:]
+		q.size--
+		prometheus.AddSignalQueueDepth(-1)
+		return qr, true, q.closed
+	}
+	return queuedSignalRequest{}, false, q.closed
+}
+
+// discard closes the queue and drops queued requests without handling them
+func (q *SignalQueue) discard() {
+	q.lock.Lock()
+	q.closed = true
+	size := q.size
+	q.queues = [numSignalPriorities][]queuedSignalRequest{}
+	q.size = 0
+	q.lock.Unlock()
+
+	prometheus.AddSignalQueueDepth(-size)
+}
+
+func (q *SignalQueue) dropped(req *livekit.SignalRequest) error {
+	requestType := SignalRequestType(req)
+	prometheus.RecordSignalRequestDropped(requestType)
+	if onDropped := q.params.OnDropped; onDropped != nil {
+		onDropped(req)
+	}
+	return fmt.Errorf("%w, dropped %s", ErrSignalQueueFull, requestType)
+}
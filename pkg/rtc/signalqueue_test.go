@@ -0,0 +1,165 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+func signalRequestForTest(t *testing.T, requestType string, n int) *livekit.SignalRequest {
+	switch requestType {
+	case "offer":
+		return &livekit.SignalRequest{Message: &livekit.SignalRequest_Offer{Offer: &livekit.SessionDescription{Sdp: string(rune('a' + n))}}}
+	case "leave":
+		return &livekit.SignalRequest{Message: &livekit.SignalRequest_Leave{Leave: &livekit.LeaveRequest{}}}
+	case "subscription":
+		return &livekit.SignalRequest{Message: &livekit.SignalRequest_Subscription{Subscription: &livekit.UpdateSubscription{
+			TrackSids: []string{string(rune('a' + n))},
+		}}}
+	case "track_setting":
+		return &livekit.SignalRequest{Message: &livekit.SignalRequest_TrackSetting{TrackSetting: &livekit.UpdateTrackSettings{
+			Width: uint32(n),
+		}}}
+	case "update_metadata":
+		return &livekit.SignalRequest{Message: &livekit.SignalRequest_UpdateMetadata{UpdateMetadata: &livekit.UpdateParticipantMetadata{
+			Name: string(rune('a' + n)),
+		}}}
+	}
+	require.FailNow(t, "unknown request type", requestType)
+	return nil
+}
+
+func drainSignalQueue(t *testing.T, q *SignalQueue) []*livekit.SignalRequest {
+	var handled []*livekit.SignalRequest
+	q.Drain()
+	require.NoError(t, q.Process(func(req *livekit.SignalRequest) error {
+		handled = append(handled, req)
+		return nil
+	}))
+	return handled
+}
+
+func TestSignalQueue(t *testing.T) {
+	t.Run("handles by priority, in order within a priority", func(t *testing.T) {
+		q := NewSignalQueue(SignalQueueParams{})
+		expected := []*livekit.SignalRequest{
+			signalRequestForTest(t, "offer", 0),
+			signalRequestForTest(t, "leave", 0),
+			signalRequestForTest(t, "subscription", 0),
+			signalRequestForTest(t, "subscription", 1),
+			signalRequestForTest(t, "track_setting", 0),
+			signalRequestForTest(t, "track_setting", 1),
+			signalRequestForTest(t, "update_metadata", 0),
+		}
+		for _, idx := range []int{6, 4, 2, 0, 5, 3, 1} {
+			require.NoError(t, q.Enqueue(expected[idx]))
+		}
+		require.Equal(t, len(expected), q.Len())
+
+		require.Equal(t, expected, drainSignalQueue(t, q))
+		require.Zero(t, q.Len())
+	})
+
+	t.Run("drops only lowest priority when full", func(t *testing.T) {
+		var dropped []*livekit.SignalRequest
+		q := NewSignalQueue(SignalQueueParams{
+			MaxSize: 3,
+			OnDropped: func(req *livekit.SignalRequest) {
+				dropped = append(dropped, req)
+			},
+		})
+		subscription := signalRequestForTest(t, "subscription", 0)
+		metadata := signalRequestForTest(t, "update_metadata", 0)
+		metadata2 := signalRequestForTest(t, "update_metadata", 1)
+		require.NoError(t, q.Enqueue(subscription))
+		require.NoError(t, q.Enqueue(metadata))
+		require.NoError(t, q.Enqueue(metadata2))
+
+		// lowest priority incoming is dropped
+		metadata3 := signalRequestForTest(t, "update_metadata", 2)
+		err := q.Enqueue(metadata3)
+		require.ErrorIs(t, err, ErrSignalQueueFull)
+		require.Contains(t, err.Error(), "update_metadata")
+		require.Equal(t, []*livekit.SignalRequest{metadata3}, dropped)
+
+		// higher priority evicts the newest of the lowest priority
+		setting := signalRequestForTest(t, "track_setting", 0)
+		err = q.Enqueue(setting)
+		require.ErrorIs(t, err, ErrSignalQueueFull)
+		require.Equal(t, []*livekit.SignalRequest{metadata3, metadata2}, dropped)
+		require.Equal(t, 3, q.Len())
+
+		setting2 := signalRequestForTest(t, "track_setting", 1)
+		require.ErrorIs(t, q.Enqueue(setting2), ErrSignalQueueFull)
+		require.Equal(t, []*livekit.SignalRequest{metadata3, metadata2, metadata}, dropped)
+
+		// other priorities are added over the limit when no lowest priority request is queued
+		subscription2 := signalRequestForTest(t, "subscription", 1)
+		require.NoError(t, q.Enqueue(subscription2))
+		offer := signalRequestForTest(t, "offer", 0)
+		require.NoError(t, q.Enqueue(offer))
+		require.Equal(t, 5, q.Len())
+		require.Len(t, dropped, 3)
+
+		require.Equal(t, []*livekit.SignalRequest{offer, subscription, subscription2, setting, setting2}, drainSignalQueue(t, q))
+	})
+
+	t.Run("close flushes queued requests", func(t *testing.T) {
+		q := NewSignalQueue(SignalQueueParams{})
+		require.NoError(t, q.Enqueue(signalRequestForTest(t, "offer", 0)))
+		require.NoError(t, q.Enqueue(signalRequestForTest(t, "subscription", 0)))
+
+		q.Close()
+		require.Zero(t, q.Len())
+		require.NoError(t, q.Process(func(req *livekit.SignalRequest) error {
+			require.FailNow(t, "flushed request handled")
+			return nil
+		}))
+
+		// closed, further requests are rejected
+		require.ErrorIs(t, q.Enqueue(signalRequestForTest(t, "offer", 1)), ErrSignalQueueClosed)
+		require.Zero(t, q.Len())
+	})
+
+	t.Run("handler error stops processing", func(t *testing.T) {
+		q := NewSignalQueue(SignalQueueParams{})
+		require.NoError(t, q.Enqueue(signalRequestForTest(t, "offer", 0)))
+		require.NoError(t, q.Enqueue(signalRequestForTest(t, "offer", 1)))
+
+		errHandle := errors.New("handle failed")
+		numHandled := 0
+		err := q.Process(func(req *livekit.SignalRequest) error {
+			numHandled++
+			return errHandle
+		})
+		require.ErrorIs(t, err, errHandle)
+		require.Equal(t, 1, numHandled)
+		require.Zero(t, q.Len())
+
+		// closed, further requests are rejected
+		require.ErrorIs(t, q.Enqueue(signalRequestForTest(t, "offer", 2)), ErrSignalQueueClosed)
+		require.Zero(t, q.Len())
+	})
+
+	t.Run("request type", func(t *testing.T) {
+		require.Equal(t, "track_setting", SignalRequestType(signalRequestForTest(t, "track_setting", 0)))
+		require.Equal(t, "unknown", SignalRequestType(&livekit.SignalRequest{}))
+	})
+}
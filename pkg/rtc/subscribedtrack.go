@@ -35,8 +35,17 @@ import (
 
 const (
 	subscriptionDebounceInterval = 100 * time.Millisecond
+
+	// subscriber is considered unable to decode if it keeps requesting key frames while media is forwarded
+	decodeStallTimeout = 10 * time.Second
+	// key frame requests further apart than this are not part of the same decode stall
+	decodeStallRequestGap = 3 * time.Second
 )
 
+var defaultSubscriberSettings = &livekit.UpdateTrackSettings{
+	Quality: livekit.VideoQuality_HIGH,
+}
+
 type SubscribedTrackParams struct {
 	PublisherID       livekit.ParticipantID
 	PublisherIdentity livekit.ParticipantIdentity
@@ -56,7 +65,13 @@ type SubscribedTrack struct {
 	versionGenerator utils.TimedVersionGenerator
 	settingsLock     sync.Mutex
 	settings         *livekit.UpdateTrackSettings
-	settingsVersion  utils.TimedVersion
+	visibility       *types.TrackVisibility
+	// visibility was used in last applied settings, needs to be reverted if cleared
+	visibilityApplied bool
+	// cap on spatial layer set by publisher, applied on top of settings
+	maxSpatialLayerCap        int32
+	maxSpatialLayerCapApplied bool
+	settingsVersion           utils.TimedVersion
 
 	bindLock        sync.Mutex
 	bound           bool
@@ -64,6 +79,14 @@ type SubscribedTrack struct {
 
 	onClose atomic.Value // func(bool)
 
+	decodeLock sync.Mutex
+	// first key frame request of a stall and packets/key frames forwarded at that time
+	decodeStallSince     time.Time
+	decodeStallPackets   uint32
+	decodeStallKeyFrames uint32
+	decodeStallReported  bool
+	decodeLastRequestAt  time.Time
+
 	debouncer func(func())
 }
 
@@ -75,8 +98,9 @@ func NewSubscribedTrack(params SubscribedTrackParams) *SubscribedTrack {
 			"publisherID", params.PublisherID,
 			"publisher", params.PublisherIdentity,
 		),
-		versionGenerator: utils.NewDefaultTimedVersionGenerator(),
-		debouncer:        debounce.New(subscriptionDebounceInterval),
+		versionGenerator:   utils.NewDefaultTimedVersionGenerator(),
+		maxSpatialLayerCap: buffer.InvalidLayerSpatial,
+		debouncer:          debounce.New(subscriptionDebounceInterval),
 	}
 
 	return s
@@ -212,6 +236,14 @@ func (t *SubscribedTrack) SetPublisherMuted(muted bool) {
 	t.DownTrack().PubMute(muted)
 }
 
+func (t *SubscribedTrack) SetModerated(moderated bool) {
+	t.DownTrack().SetModerated(moderated)
+}
+
+func (t *SubscribedTrack) SetStartPaused(startPaused bool) {
+	t.DownTrack().SetStartPaused(startPaused)
+}
+
 func (t *SubscribedTrack) UpdateSubscriberSettings(settings *livekit.UpdateTrackSettings, isImmediate bool) {
 	t.settingsLock.Lock()
 	if proto.Equal(t.settings, settings) {
@@ -231,18 +263,76 @@ func (t *SubscribedTrack) UpdateSubscriberSettings(settings *livekit.UpdateTrack
 	}
 }
 
+// SetVisibility applies client reported visibility of the track on top of subscriber settings.
+// A track that is not visible is paused and video layers are capped to rendered tile size.
+func (t *SubscribedTrack) SetVisibility(visibility *types.TrackVisibility) {
+	t.settingsLock.Lock()
+	if t.visibility.Equal(visibility) {
+		t.settingsLock.Unlock()
+		return
+	}
+
+	isImmediate := visibility.IsVisible() && !t.visibility.IsVisible()
+	t.visibility = visibility.Clone()
+	t.settingsLock.Unlock()
+
+	if isImmediate {
+		t.applySettings()
+	} else {
+		// same as settings, avoid frequent changes unless it became visible
+		t.debouncer(t.applySettings)
+	}
+}
+
+// SetPlayoutDelay overrides playout delay limits of the subscription, nil restores subscriber default.
+func (t *SubscribedTrack) SetPlayoutDelay(delay *livekit.PlayoutDelay) {
+	if err := t.DownTrack().SetPlayoutDelay(delay); err != nil {
+		t.logger.Warnw("could not set playout delay", err, "playoutDelay", logger.Proto(delay))
+	}
+}
+
+// SetMaxBitrate caps bitrate of a video subscription, layers above the cap are not forwarded
+// even if channel capacity allows. 0 removes the cap.
+func (t *SubscribedTrack) SetMaxBitrate(maxBitrate int64) {
+	if dt := t.DownTrack(); dt.Kind() == webrtc.RTPCodecTypeVideo {
+		dt.SetMaxBitrate(maxBitrate)
+	}
+}
+
+// SetMaxSpatialLayerCap caps spatial layer forwarded regardless of subscriber settings,
+// buffer.InvalidLayerSpatial removes the cap.
+func (t *SubscribedTrack) SetMaxSpatialLayerCap(layer int32) {
+	t.settingsLock.Lock()
+	if t.maxSpatialLayerCap == layer {
+		t.settingsLock.Unlock()
+		return
+	}
+	t.maxSpatialLayerCap = layer
+	t.settingsLock.Unlock()
+
+	t.applySettings()
+}
+
 func (t *SubscribedTrack) UpdateVideoLayer() {
 	t.applySettings()
 }
 
 func (t *SubscribedTrack) applySettings() {
 	t.settingsLock.Lock()
-	if t.settings == nil {
-		t.settingsLock.Unlock()
-		return
+	settings := t.settings
+	visibility := t.visibility
+	maxSpatialLayerCap := t.maxSpatialLayerCap
+	if settings == nil {
+		if visibility == nil && !t.visibilityApplied &&
+			maxSpatialLayerCap == buffer.InvalidLayerSpatial && !t.maxSpatialLayerCapApplied {
+			t.settingsLock.Unlock()
+			return
+		}
+		// visibility and layer cap are applied on top of default settings when subscriber has not sent any
+		settings = defaultSubscriberSettings
 	}
 
-	t.logger.Debugw("updating subscriber track settings", "settings", logger.Proto(t.settings))
+	t.logger.Debugw("updating subscriber track settings", "settings", logger.Proto(settings), "visibility", visibility)
 	t.settingsVersion = t.versionGenerator.Next()
 	settingsVersion := t.settingsVersion
 	t.settingsLock.Unlock()
@@ -252,14 +342,24 @@ func (t *SubscribedTrack) applySettings() {
 	temporal := buffer.InvalidLayerTemporal
 	if dt.Kind() == webrtc.RTPCodecTypeVideo {
 		mt := t.MediaTrack()
-		quality := t.settings.Quality
-		if t.settings.Width > 0 {
-			quality = mt.GetQualityForDimension(t.settings.Width, t.settings.Height)
+		quality := settings.Quality
+		if settings.Width > 0 {
+			quality = mt.GetQualityForDimension(settings.Width, settings.Height)
 		}
+		quality = capQualityForVisibility(mt, quality, visibility)
 
 		spatial = buffer.VideoQualityToSpatialLayer(quality, mt.ToProto())
-		if t.settings.Fps > 0 {
-			temporal = mt.GetTemporalLayerForSpatialFps(spatial, t.settings.Fps, dt.Codec().MimeType)
+		if maxSpatialLayerCap != buffer.InvalidLayerSpatial && spatial > maxSpatialLayerCap {
+			spatial = maxSpatialLayerCap
+		}
+		if settings.Fps > 0 {
+			if mime := dt.Codec().MimeType; dt.SupportsTemporalLayers() {
+				temporal = mt.GetTemporalLayerForSpatialFps(spatial, settings.Fps, mime)
+			} else {
+				// clamp explicitly rather than leaving it to forwarder, base layer is forwarded irrespective of fps
+				t.logger.Debugw("temporal layer not supported by forwarded stream, using base layer", "mime", mime, "fps", settings.Fps)
+				temporal = 0
+			}
 		}
 	}
 
@@ -269,8 +369,11 @@ func (t *SubscribedTrack) applySettings() {
 		t.settingsLock.Unlock()
 		return
 	}
+	t.visibilityApplied = visibility != nil
+	t.maxSpatialLayerCapApplied = maxSpatialLayerCap != buffer.InvalidLayerSpatial
 
-	if t.settings.Disabled {
+	// visibility is reported for rendered video, it does not pause audio
+	if settings.Disabled || (dt.Kind() == webrtc.RTPCodecTypeVideo && !visibility.IsVisible()) {
 		dt.Mute(true)
 		t.settingsLock.Unlock()
 		return
@@ -302,3 +405,48 @@ func (t *SubscribedTrack) RTPSender() *webrtc.RTPSender {
 func (t *SubscribedTrack) SetRTPSender(sender *webrtc.RTPSender) {
 	t.sender.Store(sender)
 }
+
+// UpdateDecodeFeedback takes a key frame request of the subscriber, returns true once when the subscriber
+// has kept requesting key frames for decodeStallTimeout although media, including key frames, was forwarded.
+func (t *SubscribedTrack) UpdateDecodeFeedback() bool {
+	stats := t.DownTrack().GetTrackStats()
+	return t.updateDecodeFeedbackAt(stats.GetPackets(), stats.GetKeyFrames(), time.Now())
+}
+
+func (t *SubscribedTrack) updateDecodeFeedbackAt(packetsSent uint32, keyFramesSent uint32, at time.Time) bool {
+	t.decodeLock.Lock()
+	defer t.decodeLock.Unlock()
+
+	lastRequestAt := t.decodeLastRequestAt
+	t.decodeLastRequestAt = at
+	if t.decodeStallSince.IsZero() || at.Sub(lastRequestAt) > decodeStallRequestGap {
+		// subscriber decoded in between requests, start over
+		t.decodeStallSince = at
+		t.decodeStallPackets = packetsSent
+		t.decodeStallKeyFrames = keyFramesSent
+		t.decodeStallReported = false
+		return false
+	}
+
+	if t.decodeStallReported ||
+		packetsSent <= t.decodeStallPackets ||
+		keyFramesSent <= t.decodeStallKeyFrames ||
+		at.Sub(t.decodeStallSince) < decodeStallTimeout {
+		return false
+	}
+
+	t.decodeStallReported = true
+	return true
+}
+
+// capQualityForVisibility lowers quality to what is needed for the rendered tile size
+func capQualityForVisibility(mt types.MediaTrack, quality livekit.VideoQuality, visibility *types.TrackVisibility) livekit.VideoQuality {
+	if visibility == nil || visibility.Width == 0 || visibility.Height == 0 || quality == livekit.VideoQuality_OFF {
+		return quality
+	}
+
+	if visibleQuality := mt.GetQualityForDimension(visibility.Width, visibility.Height); visibleQuality < quality {
+		return visibleQuality
+	}
+	return quality
+}
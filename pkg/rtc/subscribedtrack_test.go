@@ -0,0 +1,275 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+func TestTrackVisibility(t *testing.T) {
+	var visibility *types.TrackVisibility
+	require.True(t, visibility.IsVisible())
+
+	// tile size without visible fraction is unknown visibility, not hidden
+	require.True(t, (&types.TrackVisibility{Width: 320, Height: 180}).IsVisible())
+
+	hiddenFraction, visibleFraction := float32(0), float32(0.1)
+	require.False(t, (&types.TrackVisibility{VisibleFraction: &hiddenFraction, Width: 320, Height: 180}).IsVisible())
+	require.True(t, (&types.TrackVisibility{VisibleFraction: &visibleFraction}).IsVisible())
+
+	// equality compares values
+	visibleFractionCopy := visibleFraction
+	require.True(t, (&types.TrackVisibility{VisibleFraction: &visibleFraction}).Equal(&types.TrackVisibility{VisibleFraction: &visibleFractionCopy}))
+	require.False(t, (&types.TrackVisibility{VisibleFraction: &visibleFraction}).Equal(&types.TrackVisibility{}))
+	require.False(t, visibility.Equal(&types.TrackVisibility{}))
+}
+
+func TestCapQualityForVisibility(t *testing.T) {
+	ti := &livekit.TrackInfo{
+		Type:   livekit.TrackType_VIDEO,
+		Width:  1280,
+		Height: 720,
+		Layers: []*livekit.VideoLayer{
+			{Quality: livekit.VideoQuality_LOW, Width: 320, Height: 180},
+			{Quality: livekit.VideoQuality_MEDIUM, Width: 640, Height: 360},
+			{Quality: livekit.VideoQuality_HIGH, Width: 1280, Height: 720},
+		},
+	}
+	mt := NewMediaTrack(MediaTrackParams{}, ti)
+
+	// no visibility report, settings are used as is
+	require.Equal(t, livekit.VideoQuality_HIGH, capQualityForVisibility(mt, livekit.VideoQuality_HIGH, nil))
+
+	// thumbnail only gets base layer
+	fullyVisible := float32(1.0)
+	thumbnail := &types.TrackVisibility{VisibleFraction: &fullyVisible, Width: 80, Height: 60}
+	quality := capQualityForVisibility(mt, livekit.VideoQuality_HIGH, thumbnail)
+	require.Equal(t, livekit.VideoQuality_LOW, quality)
+	require.Equal(t, int32(0), buffer.VideoQualityToSpatialLayer(quality, mt.ToProto()))
+
+	// medium sized tile
+	tile := &types.TrackVisibility{VisibleFraction: &fullyVisible, Width: 600, Height: 340}
+	quality = capQualityForVisibility(mt, livekit.VideoQuality_HIGH, tile)
+	require.Equal(t, livekit.VideoQuality_MEDIUM, quality)
+	require.Equal(t, int32(1), buffer.VideoQualityToSpatialLayer(quality, mt.ToProto()))
+
+	// large tile does not raise quality above what subscriber asked for
+	fullscreen := &types.TrackVisibility{VisibleFraction: &fullyVisible, Width: 1920, Height: 1080}
+	require.Equal(t, livekit.VideoQuality_LOW, capQualityForVisibility(mt, livekit.VideoQuality_LOW, fullscreen))
+	require.Equal(t, livekit.VideoQuality_HIGH, capQualityForVisibility(mt, livekit.VideoQuality_HIGH, fullscreen))
+
+	// off stays off
+	require.Equal(t, livekit.VideoQuality_OFF, capQualityForVisibility(mt, livekit.VideoQuality_OFF, thumbnail))
+}
+
+type visibilityTestReceiver struct {
+	sfu.TrackReceiver
+}
+
+func (r *visibilityTestReceiver) TrackID() livekit.TrackID                { return "video" }
+func (r *visibilityTestReceiver) DeleteDownTrack(_ livekit.ParticipantID) {}
+func (r *visibilityTestReceiver) SendPLI(_ int32, _ bool)                 {}
+func (r *visibilityTestReceiver) GetLayeredBitrate() ([]int32, sfu.Bitrates) {
+	return nil, sfu.Bitrates{}
+}
+
+func newSubscribedTrackForVisibilityTest(t *testing.T, ti *livekit.TrackInfo, mimeType string) *SubscribedTrack {
+	dt, err := sfu.NewDownTrack(sfu.DowntrackParams{
+		Codecs: []webrtc.RTPCodecParameters{
+			{RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: mimeType, ClockRate: 90000}},
+		},
+		Receiver: &visibilityTestReceiver{},
+		SubID:    "sub",
+		Logger:   logger.GetLogger(),
+	})
+	require.NoError(t, err)
+	t.Cleanup(dt.Close)
+
+	subscriber := &typesfakes.FakeLocalParticipant{}
+	subscriber.GetLoggerReturns(logger.GetLogger())
+
+	return NewSubscribedTrack(SubscribedTrackParams{
+		PublisherID:       "pub",
+		PublisherIdentity: "pub",
+		Subscriber:        subscriber,
+		MediaTrack:        NewMediaTrack(MediaTrackParams{}, ti),
+		DownTrack:         dt,
+	})
+}
+
+func TestSubscribedTrackVisibility(t *testing.T) {
+	ti := &livekit.TrackInfo{
+		Type:   livekit.TrackType_VIDEO,
+		Width:  1280,
+		Height: 720,
+		Layers: []*livekit.VideoLayer{
+			{Quality: livekit.VideoQuality_LOW, Width: 320, Height: 180},
+			{Quality: livekit.VideoQuality_MEDIUM, Width: 640, Height: 360},
+			{Quality: livekit.VideoQuality_HIGH, Width: 1280, Height: 720},
+		},
+	}
+	isMuted := func(st *SubscribedTrack) bool {
+		return st.DownTrack().DebugInfo()["Muted"].(bool)
+	}
+	hiddenFraction, visibleFraction := float32(0), float32(1.0)
+
+	t.Run("applies without subscriber settings", func(t *testing.T) {
+		st := newSubscribedTrackForVisibilityTest(t, ti, webrtc.MimeTypeVP8)
+
+		// thumbnail only gets base layer
+		st.SetVisibility(&types.TrackVisibility{Width: 80, Height: 60})
+		require.Eventually(t, func() bool {
+			return st.DownTrack().MaxLayer().Spatial == 0
+		}, time.Second, 10*time.Millisecond)
+		require.False(t, isMuted(st))
+
+		// hidden track is paused
+		st.SetVisibility(&types.TrackVisibility{VisibleFraction: &hiddenFraction, Width: 80, Height: 60})
+		require.Eventually(t, func() bool {
+			return isMuted(st)
+		}, time.Second, 10*time.Millisecond)
+
+		// visible again is immediate, layer is capped to tile size
+		st.SetVisibility(&types.TrackVisibility{VisibleFraction: &visibleFraction, Width: 600, Height: 340})
+		require.False(t, isMuted(st))
+		require.Equal(t, int32(1), st.DownTrack().MaxLayer().Spatial)
+
+		// clearing visibility restores defaults
+		st.SetVisibility(nil)
+		require.Eventually(t, func() bool {
+			return st.DownTrack().MaxLayer().Spatial == 2
+		}, time.Second, 10*time.Millisecond)
+		require.False(t, isMuted(st))
+	})
+
+	t.Run("caps subscriber settings", func(t *testing.T) {
+		st := newSubscribedTrackForVisibilityTest(t, ti, webrtc.MimeTypeVP8)
+
+		st.UpdateSubscriberSettings(&livekit.UpdateTrackSettings{Width: 1280, Height: 720}, true)
+		require.Equal(t, int32(2), st.DownTrack().MaxLayer().Spatial)
+
+		st.SetVisibility(&types.TrackVisibility{VisibleFraction: &visibleFraction, Width: 80, Height: 60})
+		require.Eventually(t, func() bool {
+			return st.DownTrack().MaxLayer().Spatial == 0
+		}, time.Second, 10*time.Millisecond)
+
+		// subscriber disabling the track mutes regardless of visibility
+		st.UpdateSubscriberSettings(&livekit.UpdateTrackSettings{Disabled: true, Width: 1280, Height: 720}, true)
+		require.True(t, isMuted(st))
+	})
+
+	t.Run("does not pause audio", func(t *testing.T) {
+		st := newSubscribedTrackForVisibilityTest(t, &livekit.TrackInfo{Type: livekit.TrackType_AUDIO}, webrtc.MimeTypeOpus)
+
+		st.SetVisibility(&types.TrackVisibility{VisibleFraction: &hiddenFraction})
+		st.UpdateVideoLayer()
+		require.False(t, isMuted(st))
+	})
+}
+
+func TestSubscribedTrackMaxSpatialLayerCap(t *testing.T) {
+	ti := &livekit.TrackInfo{
+		Type:   livekit.TrackType_VIDEO,
+		Source: livekit.TrackSource_SCREEN_SHARE,
+		Width:  1280,
+		Height: 720,
+		Layers: []*livekit.VideoLayer{
+			{Quality: livekit.VideoQuality_LOW, Width: 320, Height: 180},
+			{Quality: livekit.VideoQuality_MEDIUM, Width: 640, Height: 360},
+			{Quality: livekit.VideoQuality_HIGH, Width: 1280, Height: 720},
+		},
+	}
+
+	t.Run("caps subscriber settings", func(t *testing.T) {
+		st := newSubscribedTrackForVisibilityTest(t, ti, webrtc.MimeTypeVP8)
+
+		// applies without subscriber settings
+		st.SetMaxSpatialLayerCap(1)
+		require.Equal(t, int32(1), st.DownTrack().MaxLayer().Spatial)
+
+		st.UpdateSubscriberSettings(&livekit.UpdateTrackSettings{Width: 1280, Height: 720}, true)
+		require.Equal(t, int32(1), st.DownTrack().MaxLayer().Spatial)
+
+		// subscriber asking for lower layer than cap is honoured
+		st.UpdateSubscriberSettings(&livekit.UpdateTrackSettings{Width: 320, Height: 180}, true)
+		require.Equal(t, int32(0), st.DownTrack().MaxLayer().Spatial)
+
+		st.UpdateSubscriberSettings(&livekit.UpdateTrackSettings{Width: 1280, Height: 720}, true)
+		st.SetMaxSpatialLayerCap(buffer.InvalidLayerSpatial)
+		require.Equal(t, int32(2), st.DownTrack().MaxLayer().Spatial)
+	})
+
+	t.Run("removing cap restores defaults", func(t *testing.T) {
+		st := newSubscribedTrackForVisibilityTest(t, ti, webrtc.MimeTypeVP8)
+
+		st.SetMaxSpatialLayerCap(0)
+		require.Equal(t, int32(0), st.DownTrack().MaxLayer().Spatial)
+
+		st.SetMaxSpatialLayerCap(buffer.InvalidLayerSpatial)
+		require.Equal(t, int32(2), st.DownTrack().MaxLayer().Spatial)
+	})
+
+	t.Run("returns highest layer which can be forwarded", func(t *testing.T) {
+		mt := NewMediaTrack(MediaTrackParams{}, ti)
+
+		require.Equal(t, int32(1), mt.SetMaxSubscriberSpatialLayer(1))
+		require.Equal(t, int32(1), mt.GetMaxSubscriberSpatialLayer())
+		require.Equal(t, int32(2), mt.SetMaxSubscriberSpatialLayer(5))
+		require.Equal(t, int32(2), mt.SetMaxSubscriberSpatialLayer(buffer.InvalidLayerSpatial))
+	})
+}
+
+func TestUpdateDecodeFeedback(t *testing.T) {
+	st := newSubscribedTrackForVisibilityTest(t, &livekit.TrackInfo{Type: livekit.TrackType_VIDEO}, webrtc.MimeTypeH264)
+	now := time.Now()
+	step := decodeStallRequestGap / 2
+
+	// first request starts tracking
+	require.False(t, st.updateDecodeFeedbackAt(100, 1, now))
+
+	// not reported before timeout
+	at := now
+	for at.Sub(now) < decodeStallTimeout-step {
+		at = at.Add(step)
+		require.False(t, st.updateDecodeFeedbackAt(200, 2, at))
+	}
+
+	// not reported if no key frame was forwarded
+	at = at.Add(step)
+	require.False(t, st.updateDecodeFeedbackAt(300, 1, at))
+
+	require.True(t, st.updateDecodeFeedbackAt(300, 3, at))
+	// reported only once
+	at = at.Add(step)
+	require.False(t, st.updateDecodeFeedbackAt(400, 4, at))
+
+	// requests far apart start over
+	for i := 0; i < 10; i++ {
+		at = at.Add(2 * decodeStallRequestGap)
+		require.False(t, st.updateDecodeFeedbackAt(uint32(500+i*100), uint32(5+i), at))
+	}
+}
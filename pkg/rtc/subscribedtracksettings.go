@@ -0,0 +1,101 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// validateTrackSettings clamps subscriber requested settings to limits and clears fields which do not apply
+// to the kind of track. Returns the settings to apply and a description of each adjustment made,
+// settings are returned as is when no adjustment is needed.
+func validateTrackSettings(
+	settings *livekit.UpdateTrackSettings,
+	kind livekit.TrackType,
+	kindKnown bool,
+	limits config.SubscribedTrackSettingsConfig,
+) (*livekit.UpdateTrackSettings, []string) {
+	if settings == nil {
+		return nil, nil
+	}
+
+	var adjustments []string
+	var validated *livekit.UpdateTrackSettings
+	adjust := func(adjustment string) *livekit.UpdateTrackSettings {
+		if validated == nil {
+			validated = proto.Clone(settings).(*livekit.UpdateTrackSettings)
+		}
+		adjustments = append(adjustments, adjustment)
+		return validated
+	}
+
+	if kindKnown && kind == livekit.TrackType_AUDIO {
+		// video only fields
+		if settings.Quality != livekit.VideoQuality_LOW {
+			adjust(fmt.Sprintf("quality %s ignored for audio", settings.Quality)).Quality = livekit.VideoQuality_LOW
+		}
+		if settings.Width != 0 || settings.Height != 0 {
+			a := adjust(fmt.Sprintf("dimensions %dx%d ignored for audio", settings.Width, settings.Height))
+			a.Width = 0
+			a.Height = 0
+		}
+		if settings.Fps != 0 {
+			adjust(fmt.Sprintf("fps %d ignored for audio", settings.Fps)).Fps = 0
+		}
+	} else {
+		validateVideoTrackSettings(settings, limits, adjust)
+	}
+
+	if validated == nil {
+		return settings, nil
+	}
+	return validated, adjustments
+}
+
+func validateVideoTrackSettings(
+	settings *livekit.UpdateTrackSettings,
+	limits config.SubscribedTrackSettingsConfig,
+	adjust func(adjustment string) *livekit.UpdateTrackSettings,
+) {
+	quality := settings.Quality
+	if _, ok := livekit.VideoQuality_name[int32(quality)]; !ok {
+		quality = livekit.VideoQuality_HIGH
+		adjust(fmt.Sprintf("unknown quality %d, using HIGH", settings.Quality)).Quality = quality
+	}
+
+	if settings.Width == 0 || settings.Height == 0 {
+		// a visible track without a size picks layer by quality, a lone dimension cannot be used to pick one
+		if !settings.Disabled && (settings.Width != 0 || settings.Height != 0) {
+			a := adjust(fmt.Sprintf("dimensions %dx%d incomplete, using quality %s", settings.Width, settings.Height, quality))
+			a.Width = 0
+			a.Height = 0
+		}
+	} else if maxDimension := max(settings.Width, settings.Height); limits.MaxDimension != 0 && maxDimension > limits.MaxDimension {
+		// scale both by the same factor to keep aspect ratio
+		a := adjust(fmt.Sprintf("dimensions %dx%d scaled down to fit %d", settings.Width, settings.Height, limits.MaxDimension))
+		a.Width = max(1, uint32(uint64(settings.Width)*uint64(limits.MaxDimension)/uint64(maxDimension)))
+		a.Height = max(1, uint32(uint64(settings.Height)*uint64(limits.MaxDimension)/uint64(maxDimension)))
+	}
+
+	if limits.MaxFps != 0 && settings.Fps > limits.MaxFps {
+		adjust(fmt.Sprintf("fps %d clamped to %d", settings.Fps, limits.MaxFps)).Fps = limits.MaxFps
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestValidateTrackSettings(t *testing.T) {
+	limits := config.SubscribedTrackSettingsConfig{
+		MaxFps:       60,
+		MaxDimension: 3840,
+	}
+
+	testCases := []struct {
+		name            string
+		settings        *livekit.UpdateTrackSettings
+		kind            livekit.TrackType
+		kindKnown       bool
+		limits          config.SubscribedTrackSettingsConfig
+		expected        *livekit.UpdateTrackSettings
+		expectedAdjusts int
+	}{
+		{
+			name:      "valid video settings",
+			settings:  &livekit.UpdateTrackSettings{Width: 1280, Height: 720, Fps: 30},
+			kind:      livekit.TrackType_VIDEO,
+			kindKnown: true,
+			limits:    limits,
+			expected:  &livekit.UpdateTrackSettings{Width: 1280, Height: 720, Fps: 30},
+		},
+		{
+			name:            "fps clamped",
+			settings:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_HIGH, Fps: 1000},
+			kind:            livekit.TrackType_VIDEO,
+			kindKnown:       true,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_HIGH, Fps: 60},
+			expectedAdjusts: 1,
+		},
+		{
+			name:            "dimensions clamped",
+			settings:        &livekit.UpdateTrackSettings{Width: 10000, Height: 2160},
+			kind:            livekit.TrackType_VIDEO,
+			kindKnown:       true,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Width: 3840, Height: 829},
+			expectedAdjusts: 1,
+		},
+		{
+			name:            "dimensions scaled keeping aspect ratio",
+			settings:        &livekit.UpdateTrackSettings{Width: 7680, Height: 4320},
+			kind:            livekit.TrackType_VIDEO,
+			kindKnown:       true,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Width: 3840, Height: 2160},
+			expectedAdjusts: 1,
+		},
+		{
+			name:      "visible without dimensions uses quality",
+			settings:  &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_MEDIUM},
+			kind:      livekit.TrackType_VIDEO,
+			kindKnown: true,
+			limits:    limits,
+			expected:  &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_MEDIUM},
+		},
+		{
+			name:            "visible with incomplete dimensions uses quality",
+			settings:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_MEDIUM, Width: 1280},
+			kind:            livekit.TrackType_VIDEO,
+			kindKnown:       true,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_MEDIUM},
+			expectedAdjusts: 1,
+		},
+		{
+			name:            "unknown quality",
+			settings:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality(10)},
+			kind:            livekit.TrackType_VIDEO,
+			kindKnown:       true,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_HIGH},
+			expectedAdjusts: 1,
+		},
+		{
+			name:      "limits disabled",
+			settings:  &livekit.UpdateTrackSettings{Width: 10000, Height: 10000, Fps: 1000},
+			kind:      livekit.TrackType_VIDEO,
+			kindKnown: true,
+			expected:  &livekit.UpdateTrackSettings{Width: 10000, Height: 10000, Fps: 1000},
+		},
+		{
+			name:            "video fields ignored for audio",
+			settings:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_HIGH, Width: 640, Height: 360, Fps: 30, Priority: 2},
+			kind:            livekit.TrackType_AUDIO,
+			kindKnown:       true,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Priority: 2},
+			expectedAdjusts: 3,
+		},
+		{
+			name:            "kind not known yet",
+			settings:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_HIGH, Fps: 1000},
+			kind:            livekit.TrackType_AUDIO,
+			kindKnown:       false,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_HIGH, Fps: 60},
+			expectedAdjusts: 1,
+		},
+		{
+			name:            "disabled is kept",
+			settings:        &livekit.UpdateTrackSettings{Disabled: true, Fps: 1000},
+			kind:            livekit.TrackType_VIDEO,
+			kindKnown:       true,
+			limits:          limits,
+			expected:        &livekit.UpdateTrackSettings{Disabled: true, Fps: 60},
+			expectedAdjusts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := proto.Clone(tc.settings).(*livekit.UpdateTrackSettings)
+
+			validated, adjustments := validateTrackSettings(tc.settings, tc.kind, tc.kindKnown, tc.limits)
+			require.True(t, proto.Equal(tc.expected, validated), "expected: %v, got: %v", tc.expected, validated)
+			require.Len(t, adjustments, tc.expectedAdjusts)
+
+			// requested settings are not modified
+			require.True(t, proto.Equal(original, tc.settings))
+		})
+	}
+}
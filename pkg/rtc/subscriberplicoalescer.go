@@ -0,0 +1,185 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+type subscriberPLIKey struct {
+	receiver sfu.TrackReceiver
+	layer    int32
+}
+
+type subscriberPLIState struct {
+	lastSentAt time.Time
+	// flushes held back PLIs when throttle expires
+	timer      *time.Timer
+	numPending int
+}
+
+// subscriberPLICoalescer aggregates PLIs from subscribers of a track so that publisher gets
+// at most one subscriber PLI per layer in a throttle period of that layer. A PLI within the throttle
+// period is held back and sent when the period expires, along with any others received meanwhile.
+// When coalescing is disabled or there is no throttle for the layer, PLIs are sent as they come.
+// A key frame forwarded while PLIs are held back satisfies them, they are dropped instead of being sent.
+type subscriberPLICoalescer struct {
+	logger logger.Logger
+
+	lock         sync.Mutex
+	config       config.PLIThrottleConfig
+	states       map[subscriberPLIKey]*subscriberPLIState
+	numCoalesced uint64
+	numCleared   uint64
+	isClosed     bool
+}
+
+func newSubscriberPLICoalescer(pliThrottleConfig config.PLIThrottleConfig, logger logger.Logger) *subscriberPLICoalescer {
+	return &subscriberPLICoalescer{
+		logger: logger,
+		config: pliThrottleConfig,
+		states: make(map[subscriberPLIKey]*subscriberPLIState),
+	}
+}
+
+func (c *subscriberPLICoalescer) SetConfig(pliThrottleConfig config.PLIThrottleConfig) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.config = pliThrottleConfig
+}
+
+func (c *subscriberPLICoalescer) Request(receiver sfu.TrackReceiver, layer int32) {
+	receiver = publisherReceiver(receiver)
+
+	c.lock.Lock()
+	throttle := sfu.PLIThrottleForLayer(c.config, layer)
+	if c.isClosed || !c.config.CoalesceSubscriberPLI || throttle == 0 {
+		c.lock.Unlock()
+		receiver.SendPLI(layer, false)
+		return
+	}
+
+	key := subscriberPLIKey{receiver: receiver, layer: layer}
+	state := c.states[key]
+	if state == nil {
+		state = &subscriberPLIState{}
+		c.states[key] = state
+	}
+
+	now := time.Now()
+	if state.timer == nil && now.Sub(state.lastSentAt) >= throttle {
+		state.lastSentAt = now
+		c.lock.Unlock()
+
+		receiver.SendPLI(layer, false)
+		return
+	}
+
+	state.numPending++
+	c.numCoalesced++
+	if state.timer == nil {
+		state.timer = time.AfterFunc(state.lastSentAt.Add(throttle).Sub(now), func() {
+			c.flush(key)
+		})
+	}
+	c.lock.Unlock()
+}
+
+// KeyFrameForwarded drops PLIs held back for the layer, the key frame is what they were asking for
+func (c *subscriberPLICoalescer) KeyFrameForwarded(receiver sfu.TrackReceiver, layer int32) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	state := c.states[subscriberPLIKey{receiver: receiver, layer: layer}]
+	if state == nil || state.timer == nil {
+		return
+	}
+
+	state.timer.Stop()
+	state.timer = nil
+	if state.numPending != 0 {
+		c.numCleared++
+		state.numPending = 0
+	}
+}
+
+// Close stops held back PLIs from being sent
+func (c *subscriberPLICoalescer) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.isClosed = true
+	for _, state := range c.states {
+		if state.timer != nil {
+			state.timer.Stop()
+		}
+	}
+	c.states = make(map[subscriberPLIKey]*subscriberPLIState)
+}
+
+func (c *subscriberPLICoalescer) NumCoalesced() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.numCoalesced
+}
+
+// NumCleared returns number of times held back PLIs were dropped due to a forwarded key frame
+func (c *subscriberPLICoalescer) NumCleared() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.numCleared
+}
+
+func (c *subscriberPLICoalescer) flush(key subscriberPLIKey) {
+	c.lock.Lock()
+	state := c.states[key]
+	// a flush racing with a forwarded key frame has nothing left to send
+	if c.isClosed || state == nil || state.numPending == 0 {
+		c.lock.Unlock()
+		return
+	}
+
+	numPending := state.numPending
+	state.numPending = 0
+	state.timer = nil
+	state.lastSentAt = time.Now()
+	c.lock.Unlock()
+
+	c.logger.Debugw("sending coalesced subscriber PLI", "layer", key.layer, "numCoalesced", numPending)
+	key.receiver.SendPLI(key.layer, false)
+}
+
+// publisherReceiver returns receiver of the publisher which subscriber receivers wrap,
+// PLIs of all subscribers of a layer are coalesced on it
+func publisherReceiver(receiver sfu.TrackReceiver) sfu.TrackReceiver {
+	if wr, ok := receiver.(*WrappedReceiver); ok && wr.TrackReceiver != nil {
+		receiver = wr.TrackReceiver
+	}
+	if dr, ok := receiver.(*DummyReceiver); ok {
+		if r := dr.Receiver(); r != nil {
+			receiver = r
+		}
+	}
+	return receiver
+}
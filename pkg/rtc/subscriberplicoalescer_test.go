@@ -0,0 +1,190 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+type pliRecordingReceiver struct {
+	sfu.TrackReceiver
+
+	lock   sync.Mutex
+	sentAt map[int32][]time.Time
+}
+
+func newPLIRecordingReceiver() *pliRecordingReceiver {
+	return &pliRecordingReceiver{
+		sentAt: make(map[int32][]time.Time),
+	}
+}
+
+func (r *pliRecordingReceiver) SendPLI(layer int32, _ bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.sentAt[layer] = append(r.sentAt[layer], time.Now())
+}
+
+func (r *pliRecordingReceiver) numSent(layer int32) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.sentAt[layer])
+}
+
+func (r *pliRecordingReceiver) sent(layer int32) []time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]time.Time{}, r.sentAt[layer]...)
+}
+
+func TestSubscriberPLICoalescer(t *testing.T) {
+	pliThrottleConfig := config.PLIThrottleConfig{
+		LowQuality:            50 * time.Millisecond,
+		MidQuality:            100 * time.Millisecond,
+		HighQuality:           100 * time.Millisecond,
+		CoalesceSubscriberPLI: true,
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := pliThrottleConfig
+		disabled.CoalesceSubscriberPLI = false
+		c := newSubscriberPLICoalescer(disabled, logger.GetLogger())
+		defer c.Close()
+
+		r := newPLIRecordingReceiver()
+		for i := 0; i < 10; i++ {
+			c.Request(r, 2)
+		}
+		require.Equal(t, 10, r.numSent(2))
+		require.Zero(t, c.NumCoalesced())
+	})
+
+	t.Run("multiple subscribers", func(t *testing.T) {
+		c := newSubscriberPLICoalescer(pliThrottleConfig, logger.GetLogger())
+		defer c.Close()
+
+		r := newPLIRecordingReceiver()
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.Request(r, 2)
+			}()
+		}
+		wg.Wait()
+
+		// first one goes through, rest are held back till throttle expires and sent as one
+		require.Equal(t, 1, r.numSent(2))
+		require.Equal(t, uint64(19), c.NumCoalesced())
+		require.Eventually(t, func() bool { return r.numSent(2) == 2 }, time.Second, 5*time.Millisecond)
+
+		// another burst within throttle of the coalesced PLI waits for throttle again
+		for i := 0; i < 5; i++ {
+			c.Request(r, 2)
+		}
+		require.Eventually(t, func() bool { return r.numSent(2) == 3 }, time.Second, 5*time.Millisecond)
+
+		time.Sleep(150 * time.Millisecond)
+		sent := r.sent(2)
+		require.Len(t, sent, 3)
+		for i := 1; i < len(sent); i++ {
+			// allow for time between throttle check and recording of the PLI
+			require.GreaterOrEqual(t, sent[i].Sub(sent[i-1]), pliThrottleConfig.HighQuality-5*time.Millisecond)
+		}
+	})
+
+	t.Run("layers and receivers are independent", func(t *testing.T) {
+		c := newSubscriberPLICoalescer(pliThrottleConfig, logger.GetLogger())
+		defer c.Close()
+
+		r1 := newPLIRecordingReceiver()
+		r2 := newPLIRecordingReceiver()
+		c.Request(r1, 0)
+		c.Request(r1, 2)
+		c.Request(r2, 2)
+		require.Equal(t, 1, r1.numSent(0))
+		require.Equal(t, 1, r1.numSent(2))
+		require.Equal(t, 1, r2.numSent(2))
+
+		// low quality has a shorter throttle
+		c.Request(r1, 0)
+		c.Request(r1, 2)
+		require.Eventually(t, func() bool { return r1.numSent(0) == 2 }, time.Second, 5*time.Millisecond)
+		require.Equal(t, 1, r1.numSent(2))
+		require.Eventually(t, func() bool { return r1.numSent(2) == 2 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("held back PLIs are dropped on key frame", func(t *testing.T) {
+		c := newSubscriberPLICoalescer(pliThrottleConfig, logger.GetLogger())
+		defer c.Close()
+
+		r := newPLIRecordingReceiver()
+		c.Request(r, 2)
+		c.Request(r, 2)
+		c.Request(r, 2)
+		require.Equal(t, 1, r.numSent(2))
+
+		// key frame of another layer does not satisfy them
+		c.KeyFrameForwarded(r, 1)
+		require.Zero(t, c.NumCleared())
+
+		c.KeyFrameForwarded(r, 2)
+		require.Equal(t, uint64(1), c.NumCleared())
+
+		time.Sleep(150 * time.Millisecond)
+		require.Equal(t, 1, r.numSent(2))
+	})
+
+	t.Run("subscribers of a receiver are coalesced", func(t *testing.T) {
+		c := newSubscriberPLICoalescer(pliThrottleConfig, logger.GetLogger())
+		defer c.Close()
+
+		r := newPLIRecordingReceiver()
+		wr1 := &WrappedReceiver{TrackReceiver: r}
+		wr2 := &WrappedReceiver{TrackReceiver: r}
+		c.Request(wr1, 2)
+		c.Request(wr2, 2)
+		require.Equal(t, 1, r.numSent(2))
+		require.Equal(t, uint64(1), c.NumCoalesced())
+
+		c.KeyFrameForwarded(r, 2)
+		require.Equal(t, uint64(1), c.NumCleared())
+	})
+
+	t.Run("held back PLIs are dropped on close", func(t *testing.T) {
+		c := newSubscriberPLICoalescer(pliThrottleConfig, logger.GetLogger())
+
+		r := newPLIRecordingReceiver()
+		c.Request(r, 1)
+		c.Request(r, 1)
+		c.Close()
+
+		time.Sleep(150 * time.Millisecond)
+		require.Equal(t, 1, r.numSent(1))
+	})
+}
@@ -19,15 +19,21 @@ package rtc
 import (
 	"context"
 	"errors"
+	"math"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/pion/webrtc/v3/pkg/rtcerr"
 	"go.uber.org/atomic"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 )
@@ -48,6 +54,12 @@ const (
 	trackIDForReconcileSubscriptions = livekit.TrackID("subscriptions_reconcile")
 )
 
+// settings applied to video subscriptions in audio only mode,
+// subscriber settings are kept and restored when audio only mode ends
+var audioOnlySettings = &livekit.UpdateTrackSettings{
+	Disabled: true,
+}
+
 type SubscriptionManagerParams struct {
 	Logger              logger.Logger
 	Participant         types.LocalParticipant
@@ -58,6 +70,14 @@ type SubscriptionManagerParams struct {
 	Telemetry           telemetry.TelemetryService
 
 	SubscriptionLimitVideo, SubscriptionLimitAudio int32
+	// limit on tracks with SCREEN_SHARE source, counted in addition to video limit
+	SubscriptionLimitScreenShare int32
+
+	// kinds of new subscriptions which do not forward media until subscriber sends track settings
+	StartPaused []livekit.TrackType
+
+	// bounds of settings requested by subscriber, out of bound settings are adjusted
+	SettingsLimits config.SubscribedTrackSettingsConfig
 }
 
 // SubscriptionManager manages a participant's subscriptions
@@ -68,8 +88,12 @@ type SubscriptionManager struct {
 	pendingUnsubscribes atomic.Int32
 
 	subscribedVideoCount, subscribedAudioCount atomic.Int32
+	subscribedScreenShareCount                 atomic.Int32
+	// initialized from params, can be updated at runtime
+	subscriptionLimitVideo, subscriptionLimitAudio atomic.Int32
 
 	subscribedTo map[livekit.ParticipantID]map[livekit.TrackID]struct{}
+	audioOnly    bool
 	reconcileCh  chan livekit.TrackID
 	closeCh      chan struct{}
 	doneCh       chan struct{}
@@ -86,6 +110,8 @@ func NewSubscriptionManager(params SubscriptionManagerParams) *SubscriptionManag
 		closeCh:       make(chan struct{}),
 		doneCh:        make(chan struct{}),
 	}
+	m.subscriptionLimitVideo.Store(params.SubscriptionLimitVideo)
+	m.subscriptionLimitAudio.Store(params.SubscriptionLimitAudio)
 
 	go m.reconcileWorker()
 	return m
@@ -143,6 +169,7 @@ func (m *SubscriptionManager) SubscribeToTrack(trackID livekit.TrackID) {
 		sub = newTrackSubscription(m.params.Participant.ID(), trackID, sLogger)
 
 		m.lock.Lock()
+		sub.audioOnly = m.audioOnly
 		m.subscriptions[trackID] = sub
 		m.lock.Unlock()
 
@@ -202,6 +229,22 @@ func (m *SubscriptionManager) GetSubscribedParticipants() []livekit.ParticipantI
 	return participantIDs
 }
 
+// GetDeniedSubscriptions returns tracks the participant wants to subscribe to,
+// but could not be subscribed to as subscription limits have been reached
+func (m *SubscriptionManager) GetDeniedSubscriptions() []livekit.TrackID {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var trackIDs []livekit.TrackID
+	for trackID, sub := range m.subscriptions {
+		if sub.isDesired() && sub.isDeniedByLimit() && sub.getSubscribedTrack() == nil {
+			trackIDs = append(trackIDs, trackID)
+		}
+	}
+	slices.Sort(trackIDs)
+	return trackIDs
+}
+
 func (m *SubscriptionManager) IsSubscribedTo(participantID livekit.ParticipantID) bool {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -218,11 +261,93 @@ func (m *SubscriptionManager) UpdateSubscribedTrackSettings(trackID livekit.Trac
 			"trackID", trackID,
 		)
 		sub = newTrackSubscription(m.params.Participant.ID(), trackID, sLogger)
+		sub.audioOnly = m.audioOnly
 		m.subscriptions[trackID] = sub
 	}
 	m.lock.Unlock()
 
+	kind, kindKnown := sub.getKind()
+	settings, adjustments := validateTrackSettings(settings, kind, kindKnown, m.params.SettingsLimits)
+	if len(adjustments) != 0 {
+		// there is no signal message to report adjustments, client sees the effect in stream state and quality
+		sub.logger.Infow("adjusted subscribed track settings", "adjustments", adjustments, "settings", logger.Proto(settings))
+	}
+
 	sub.setSettings(settings)
+
+	// frame rate is met by selecting a temporal layer, the base layer is forwarded when codec
+	// does not support that, let the subscriber know that the requested frame rate is not applied
+	if settings.GetFps() > 0 {
+		if st := sub.getSubscribedTrack(); st != nil && st.DownTrack() != nil {
+			if mime := st.DownTrack().Codec().MimeType; !st.MediaTrack().SupportsTemporalLayers(mime) {
+				sub.logger.Debugw("temporal layers not supported, requested frame rate not applied", "mime", mime, "fps", settings.Fps)
+				m.params.OnSubscriptionError(trackID, false, ErrTemporalLayersUnsupported)
+			}
+		}
+	}
+}
+
+// SetTrackVisibility applies client reported visibility of a subscribed track,
+// visibility is retained and restored across re-subscriptions like settings.
+func (m *SubscriptionManager) SetTrackVisibility(trackID livekit.TrackID, visibility *types.TrackVisibility) {
+	m.lock.Lock()
+	sub, ok := m.subscriptions[trackID]
+	if !ok {
+		sLogger := m.params.Logger.WithValues(
+			"trackID", trackID,
+		)
+		sub = newTrackSubscription(m.params.Participant.ID(), trackID, sLogger)
+		sub.audioOnly = m.audioOnly
+		m.subscriptions[trackID] = sub
+	}
+	m.lock.Unlock()
+
+	sub.setVisibility(visibility)
+}
+
+// SetTrackPlayoutDelay overrides playout delay limits of a subscribed track, nil restores subscriber default.
+// Like visibility, the override is retained and restored across re-subscriptions.
+func (m *SubscriptionManager) SetTrackPlayoutDelay(trackID livekit.TrackID, delay *livekit.PlayoutDelay) {
+	m.lock.Lock()
+	sub, ok := m.subscriptions[trackID]
+	if !ok {
+		sLogger := m.params.Logger.WithValues(
+			"trackID", trackID,
+		)
+		sub = newTrackSubscription(m.params.Participant.ID(), trackID, sLogger)
+		sub.audioOnly = m.audioOnly
+		m.subscriptions[trackID] = sub
+	}
+	m.lock.Unlock()
+
+	sub.setPlayoutDelay(delay)
+}
+
+// SetAudioOnly disables all current and future video subscriptions when enabled, so that their bandwidth is
+// reclaimed. Subscriber settings received in the meantime are kept and applied when audio only mode ends.
+func (m *SubscriptionManager) SetAudioOnly(audioOnly bool) {
+	m.lock.Lock()
+	if m.audioOnly == audioOnly {
+		m.lock.Unlock()
+		return
+	}
+	m.audioOnly = audioOnly
+	subs := make([]*trackSubscription, 0, len(m.subscriptions))
+	for _, sub := range m.subscriptions {
+		subs = append(subs, sub)
+	}
+	m.lock.Unlock()
+
+	for _, sub := range subs {
+		sub.setAudioOnly(audioOnly)
+	}
+}
+
+func (m *SubscriptionManager) IsAudioOnly() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.audioOnly
 }
 
 // OnSubscribeStatusChanged callback will be notified when a participant subscribes or unsubscribes to another participant
@@ -259,6 +384,33 @@ func (m *SubscriptionManager) ReconcileAll() {
 	m.queueReconcile(trackIDForReconcileSubscriptions)
 }
 
+// UpdateSubscriptionLimits changes the maximum number of subscribed tracks per kind, 0 is unlimited.
+// When a limit is reduced, subscriptions beyond the new limit are unsubscribed on next reconcile,
+// starting with the lowest priority and then the oldest. Those are still wanted and are subscribed
+// again when a limit is increased.
+func (m *SubscriptionManager) UpdateSubscriptionLimits(audio, video int32) {
+	m.subscriptionLimitAudio.Store(audio)
+	m.subscriptionLimitVideo.Store(video)
+	m.params.Logger.Infow("updating subscription limits", "audio", audio, "video", video)
+
+	// enforces reduced limits and subscribes to denied tracks when limits are increased
+	m.ReconcileAll()
+}
+
+func (m *SubscriptionManager) GetSubscriptionLimits() (audio int32, video int32) {
+	return m.subscriptionLimitAudio.Load(), m.subscriptionLimitVideo.Load()
+}
+
+func (m *SubscriptionManager) getSubscriptionLimit(kind livekit.TrackType) int32 {
+	switch kind {
+	case livekit.TrackType_VIDEO:
+		return m.subscriptionLimitVideo.Load()
+	case livekit.TrackType_AUDIO:
+		return m.subscriptionLimitAudio.Load()
+	}
+	return 0
+}
+
 func (m *SubscriptionManager) setDesired(trackID livekit.TrackID, desired bool) (*trackSubscription, bool) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -280,6 +432,8 @@ func (m *SubscriptionManager) canReconcile() bool {
 }
 
 func (m *SubscriptionManager) reconcileSubscriptions() {
+	m.enforceSubscriptionLimits()
+
 	var needsToReconcile []*trackSubscription
 	m.lock.RLock()
 	for _, sub := range m.subscriptions {
@@ -320,9 +474,15 @@ func (m *SubscriptionManager) reconcileSubscription(s *trackSubscription) {
 		}
 		if err := m.subscribe(s); err != nil {
 			s.recordAttempt(false)
-
-			switch err {
-			case ErrNoTrackPermission, ErrNoSubscribePermission, ErrNoReceiver, ErrNotOpen, ErrTrackNotAttached, ErrSubscriptionLimitExceeded:
+			s.setDeniedByLimit(errors.Is(err, ErrSubscriptionLimitExceeded) || errors.Is(err, ErrScreenShareLimitExceeded))
+
+			switch {
+			case errors.Is(err, ErrNoTrackPermission),
+				errors.Is(err, ErrNoSubscribePermission),
+				errors.Is(err, ErrNoReceiver),
+				errors.Is(err, ErrNotOpen),
+				errors.Is(err, ErrTrackNotAttached),
+				errors.Is(err, ErrSubscriptionLimitExceeded):
 				// these are errors that are outside of our control, so we'll keep trying
 				// - ErrNoTrackPermission: publisher did not grant subscriber permission, may change any moment
 				// - ErrNoSubscribePermission: participant was not granted canSubscribe, may change any moment
@@ -334,7 +494,18 @@ func (m *SubscriptionManager) reconcileSubscription(s *trackSubscription) {
 				if s.durationSinceStart() > subscriptionTimeout {
 					s.maybeRecordError(m.params.Telemetry, m.params.Participant.ID(), err, true)
 				}
-			case ErrTrackNotFound:
+			case errors.Is(err, ErrSubscriberLimitExceeded),
+				errors.Is(err, ErrScreenShareLimitExceeded):
+				// track has reached its subscriber limit or participant has reached its screen share
+				// subscription limit, keep trying as other subscriptions may end,
+				// subscriber is notified on first rejection
+				if s.maybeRecordError(m.params.Telemetry, m.params.Participant.ID(), err, false) {
+					if errors.Is(err, ErrSubscriberLimitExceeded) {
+						prometheus.RecordTrackSubscriberLimitRejection()
+					}
+					m.params.OnSubscriptionError(s.trackID, false, err)
+				}
+			case errors.Is(err, ErrTrackNotFound):
 				// source track was never published or closed
 				// if after timeout we'd unsubscribe from it.
 				// this is the *only* case we'd change desired state
@@ -362,6 +533,7 @@ func (m *SubscriptionManager) reconcileSubscription(s *trackSubscription) {
 			}
 		} else {
 			s.recordAttempt(true)
+			s.setDeniedByLimit(false)
 		}
 
 		return
@@ -439,18 +611,61 @@ func (m *SubscriptionManager) reconcileWorker() {
 func (m *SubscriptionManager) hasCapacityForSubscription(kind livekit.TrackType) bool {
 	switch kind {
 	case livekit.TrackType_VIDEO:
-		if m.params.SubscriptionLimitVideo > 0 && m.subscribedVideoCount.Load() >= m.params.SubscriptionLimitVideo {
+		if limit := m.subscriptionLimitVideo.Load(); limit > 0 && m.subscribedVideoCount.Load() >= limit {
 			return false
 		}
 
 	case livekit.TrackType_AUDIO:
-		if m.params.SubscriptionLimitAudio > 0 && m.subscribedAudioCount.Load() >= m.params.SubscriptionLimitAudio {
+		if limit := m.subscriptionLimitAudio.Load(); limit > 0 && m.subscribedAudioCount.Load() >= limit {
 			return false
 		}
 	}
 	return true
 }
 
+func (m *SubscriptionManager) hasCapacityForScreenShareSubscription() bool {
+	limit := m.params.SubscriptionLimitScreenShare
+	return limit <= 0 || m.subscribedScreenShareCount.Load() < limit
+}
+
+// enforceSubscriptionLimits unsubscribes from tracks exceeding the subscription limits, which can happen
+// when limits are reduced. It runs in the reconcile worker so that it does not race with subscribe.
+// Evicted subscriptions stay desired and are denied by limit, so that they are subscribed again
+// once there is capacity.
+func (m *SubscriptionManager) enforceSubscriptionLimits() {
+	subscribed := make(map[livekit.TrackType][]*trackSubscription)
+	m.lock.RLock()
+	for _, sub := range m.subscriptions {
+		// denied by limit with a subscribed track is already being evicted
+		if !sub.isDesired() || sub.isDeniedByLimit() || sub.getSubscribedTrack() == nil {
+			continue
+		}
+		if kind, ok := sub.getKind(); ok {
+			subscribed[kind] = append(subscribed[kind], sub)
+		}
+	}
+	m.lock.RUnlock()
+
+	for kind, subs := range subscribed {
+		limit := m.getSubscriptionLimit(kind)
+		if limit <= 0 || len(subs) <= int(limit) {
+			continue
+		}
+
+		sort.Slice(subs, func(i, j int) bool {
+			return subs[i].evictsBefore(subs[j])
+		})
+		for _, sub := range subs[:len(subs)-int(limit)] {
+			sub.logger.Infow("unsubscribing from track, exceeds subscription limit", "kind", kind, "limit", limit)
+			sub.setDeniedByLimit(true)
+			if err := m.unsubscribe(sub); err != nil {
+				sub.logger.Warnw("failed to unsubscribe", err)
+			}
+			m.params.OnSubscriptionError(sub.trackID, false, ErrSubscriptionLimitExceeded)
+		}
+	}
+}
+
 func (m *SubscriptionManager) subscribe(s *trackSubscription) error {
 	s.logger.Debugw("executing subscribe")
 
@@ -494,6 +709,10 @@ func (m *SubscriptionManager) subscribe(s *trackSubscription) error {
 	if !m.hasCapacityForSubscription(track.Kind()) {
 		return ErrSubscriptionLimitExceeded
 	}
+	isScreenShare := track.Source() == livekit.TrackSource_SCREEN_SHARE
+	if isScreenShare && !m.hasCapacityForScreenShareSubscription() {
+		return ErrScreenShareLimitExceeded
+	}
 
 	s.setPublisher(res.PublisherIdentity, res.PublisherID)
 
@@ -508,13 +727,13 @@ func (m *SubscriptionManager) subscribe(s *trackSubscription) error {
 	subTrack, err := track.AddSubscriber(m.params.Participant)
 	if err != nil && !errors.Is(err, errAlreadySubscribed) {
 		// ignore error(s): already subscribed
-		if !errors.Is(err, ErrTrackNotAttached) && !errors.Is(err, ErrNoReceiver) {
+		if !errors.Is(err, ErrTrackNotAttached) && !errors.Is(err, ErrNoReceiver) && !errors.Is(err, ErrSubscriberLimitExceeded) {
 			// as track resolution could take some time, not logging errors due to waiting for track resolution
 			m.params.Logger.Warnw("add subscriber failed", err, "trackID", trackID)
 		}
 		return err
 	}
-	if err == errAlreadySubscribed {
+	if errors.Is(err, errAlreadySubscribed) {
 		m.params.Logger.Debugw(
 			"already subscribed to track",
 			"trackID", trackID,
@@ -538,6 +757,9 @@ func (m *SubscriptionManager) subscribe(s *trackSubscription) error {
 			s.maybeRecordSuccess(m.params.Telemetry, m.params.Participant.ID())
 		})
 		s.setSubscribedTrack(subTrack)
+		if m.shouldStartPaused(track.Kind()) && s.startPaused(subTrack) {
+			s.logger.Debugw("subscription started paused")
+		}
 
 		switch track.Kind() {
 		case livekit.TrackType_VIDEO:
@@ -545,6 +767,9 @@ func (m *SubscriptionManager) subscribe(s *trackSubscription) error {
 		case livekit.TrackType_AUDIO:
 			m.subscribedAudioCount.Inc()
 		}
+		if isScreenShare {
+			m.subscribedScreenShareCount.Inc()
+		}
 
 		if subTrack.NeedsNegotiation() {
 			m.params.Participant.Negotiate(false)
@@ -580,6 +805,10 @@ func (m *SubscriptionManager) subscribe(s *trackSubscription) error {
 	return nil
 }
 
+func (m *SubscriptionManager) shouldStartPaused(kind livekit.TrackType) bool {
+	return slices.Contains(m.params.StartPaused, kind)
+}
+
 func (m *SubscriptionManager) unsubscribe(s *trackSubscription) error {
 	s.logger.Debugw("executing unsubscribe")
 
@@ -631,10 +860,17 @@ func (m *SubscriptionManager) handleSubscribedTrackClose(s *trackSubscription, w
 	switch subTrack.MediaTrack().Kind() {
 	case livekit.TrackType_VIDEO:
 		videoCount := m.subscribedVideoCount.Dec()
-		relieveFromLimits = m.params.SubscriptionLimitVideo > 0 && videoCount == m.params.SubscriptionLimitVideo-1
+		limit := m.subscriptionLimitVideo.Load()
+		relieveFromLimits = limit > 0 && videoCount == limit-1
 	case livekit.TrackType_AUDIO:
 		audioCount := m.subscribedAudioCount.Dec()
-		relieveFromLimits = m.params.SubscriptionLimitAudio > 0 && audioCount == m.params.SubscriptionLimitAudio-1
+		limit := m.subscriptionLimitAudio.Load()
+		relieveFromLimits = limit > 0 && audioCount == limit-1
+	}
+	if subTrack.MediaTrack().Source() == livekit.TrackSource_SCREEN_SHARE {
+		screenShareCount := m.subscribedScreenShareCount.Dec()
+		limit := m.params.SubscriptionLimitScreenShare
+		relieveFromLimits = relieveFromLimits || (limit > 0 && screenShareCount == limit-1)
 	}
 
 	// remove from subscribedTo
@@ -725,15 +961,22 @@ type trackSubscription struct {
 	publisherID              livekit.ParticipantID
 	publisherIdentity        livekit.ParticipantIdentity
 	settings                 *livekit.UpdateTrackSettings
+	visibility               *types.TrackVisibility
+	playoutDelay             *livekit.PlayoutDelay
+	pausedAtStart            bool
+	audioOnly                bool
 	changedNotifier          types.ChangeNotifier
 	removedNotifier          types.ChangeNotifier
 	hasPermissionInitialized bool
 	hasPermission            bool
 	subscribedTrack          types.SubscribedTrack
+	subscribedAt             time.Time
 	eventSent                atomic.Bool
 	numAttempts              atomic.Int32
 	bound                    bool
 	kind                     atomic.Pointer[livekit.TrackType]
+	// last subscribe attempt failed as subscription limits have been reached
+	deniedByLimit atomic.Bool
 
 	// the later of when subscription was requested OR when the first failure was encountered OR when permission is granted
 	// this timestamp determines when failures are reported
@@ -824,9 +1067,25 @@ func (s *trackSubscription) setSubscribedTrack(track types.SubscribedTrack) {
 	oldTrack := s.subscribedTrack
 	s.subscribedTrack = track
 	s.bound = false
+	if track != nil && track != oldTrack {
+		s.subscribedAt = time.Now()
+	}
 	settings := s.settings
+	if s.isAudioOnlyLocked() {
+		settings = audioOnlySettings
+	}
+	visibility := s.visibility
+	playoutDelay := s.playoutDelay
 	s.lock.Unlock()
 
+	if visibility != nil && track != nil {
+		s.logger.Debugw("restoring track visibility", "visibility", visibility)
+		track.SetVisibility(visibility)
+	}
+	if playoutDelay != nil && track != nil {
+		s.logger.Debugw("restoring playout delay", "playoutDelay", logger.Proto(playoutDelay))
+		track.SetPlayoutDelay(playoutDelay)
+	}
 	if settings != nil && track != nil {
 		s.logger.Debugw("restoring subscriber settings", "settings", logger.Proto(settings))
 		track.UpdateSubscriberSettings(settings, true)
@@ -836,6 +1095,32 @@ func (s *trackSubscription) setSubscribedTrack(track types.SubscribedTrack) {
 	}
 }
 
+// evictsBefore returns true if this subscription should be removed before other when over subscription limit,
+// lower priority goes first, unset priority being the lowest, then the older subscription
+func (s *trackSubscription) evictsBefore(other *trackSubscription) bool {
+	priority, subscribedAt := s.getPriorityAndSubscribedAt()
+	otherPriority, otherSubscribedAt := other.getPriorityAndSubscribedAt()
+	if priority != otherPriority {
+		return priority > otherPriority
+	}
+	if !subscribedAt.Equal(otherSubscribedAt) {
+		return subscribedAt.Before(otherSubscribedAt)
+	}
+	return s.trackID < other.trackID
+}
+
+// getPriorityAndSubscribedAt returns subscriber set priority with 1 being the highest, unset is returned as lowest
+func (s *trackSubscription) getPriorityAndSubscribedAt() (uint32, time.Time) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	priority := s.settings.GetPriority()
+	if priority == 0 {
+		priority = math.MaxUint32
+	}
+	return priority, s.subscribedAt
+}
+
 func (s *trackSubscription) trySetKind(kind livekit.TrackType) {
 	s.kind.CompareAndSwap(nil, &kind)
 }
@@ -894,13 +1179,111 @@ func (s *trackSubscription) setRemovedNotifierLocked(notifier types.ChangeNotifi
 	return true
 }
 
+// setSettings applies subscriber settings, the first settings received resume
+// a subscription which started paused, whatever they request
 func (s *trackSubscription) setSettings(settings *livekit.UpdateTrackSettings) {
 	s.lock.Lock()
 	s.settings = settings
+	if s.isAudioOnlyLocked() {
+		// applied when audio only mode ends
+		s.lock.Unlock()
+		return
+	}
 	subTrack := s.subscribedTrack
+	resume := s.pausedAtStart
+	s.pausedAtStart = false
 	s.lock.Unlock()
 	if subTrack != nil {
-		subTrack.UpdateSubscriberSettings(settings, false)
+		// first settings are applied immediately so that resume is not delayed by debouncing
+		subTrack.UpdateSubscriberSettings(settings, resume)
+		if resume {
+			// after settings so that a disabled track goes from paused to muted
+			subTrack.SetStartPaused(false)
+		}
+	}
+}
+
+func (s *trackSubscription) setAudioOnly(audioOnly bool) {
+	s.lock.Lock()
+	if s.audioOnly == audioOnly {
+		s.lock.Unlock()
+		return
+	}
+	s.audioOnly = audioOnly
+
+	kind, ok := s.getKind()
+	subTrack := s.subscribedTrack
+	if !ok || kind != livekit.TrackType_VIDEO || subTrack == nil {
+		s.lock.Unlock()
+		return
+	}
+
+	var settings *livekit.UpdateTrackSettings
+	resume := false
+	switch {
+	case audioOnly:
+		settings = audioOnlySettings
+	case s.settings != nil:
+		settings = s.settings
+		resume = s.pausedAtStart
+		s.pausedAtStart = false
+	case !s.pausedAtStart:
+		// subscriber has not sent settings, restore defaults
+		settings = defaultSubscriberSettings
+	}
+	s.lock.Unlock()
+
+	if settings != nil {
+		subTrack.UpdateSubscriberSettings(settings, true)
+	}
+	if resume {
+		subTrack.SetStartPaused(false)
+	}
+}
+
+func (s *trackSubscription) isAudioOnlyLocked() bool {
+	kind, ok := s.getKind()
+	return s.audioOnly && ok && kind == livekit.TrackType_VIDEO
+}
+
+// startPaused pauses a new subscription if subscriber has not sent settings for it,
+// forwarding starts when the first settings are received. The pause is applied to the
+// down track, so that stream allocation reports it and does not resume the track.
+func (s *trackSubscription) startPaused(track types.SubscribedTrack) bool {
+	// hold lock so that settings received concurrently are applied after
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.settings != nil || s.subscribedTrack != track {
+		return false
+	}
+
+	s.pausedAtStart = true
+	track.SetStartPaused(true)
+	return true
+}
+
+func (s *trackSubscription) setVisibility(visibility *types.TrackVisibility) {
+	s.lock.Lock()
+	s.visibility = visibility
+	subTrack := s.subscribedTrack
+	s.lock.Unlock()
+	if subTrack != nil {
+		subTrack.SetVisibility(visibility)
+	}
+}
+
+func (s *trackSubscription) setPlayoutDelay(delay *livekit.PlayoutDelay) {
+	if delay != nil {
+		delay = proto.Clone(delay).(*livekit.PlayoutDelay)
+	}
+
+	s.lock.Lock()
+	s.playoutDelay = delay
+	subTrack := s.subscribedTrack
+	s.lock.Unlock()
+	if subTrack != nil {
+		subTrack.SetPlayoutDelay(delay)
 	}
 }
 
@@ -930,6 +1313,14 @@ func (s *trackSubscription) recordAttempt(success bool) {
 	}
 }
 
+func (s *trackSubscription) setDeniedByLimit(denied bool) {
+	s.deniedByLimit.Store(denied)
+}
+
+func (s *trackSubscription) isDeniedByLimit() bool {
+	return s.deniedByLimit.Load()
+}
+
 func (s *trackSubscription) getNumAttempts() int32 {
 	return s.numAttempts.Load()
 }
@@ -955,12 +1346,14 @@ func (s *trackSubscription) handleSourceTrackRemoved() {
 	s.setRemovedNotifierLocked(nil)
 }
 
-func (s *trackSubscription) maybeRecordError(ts telemetry.TelemetryService, pID livekit.ParticipantID, err error, isUserError bool) {
+// maybeRecordError returns true if the error was recorded, only the first error or success of a subscription is recorded
+func (s *trackSubscription) maybeRecordError(ts telemetry.TelemetryService, pID livekit.ParticipantID, err error, isUserError bool) bool {
 	if s.eventSent.Swap(true) {
-		return
+		return false
 	}
 
 	ts.TrackSubscribeFailed(context.Background(), pID, s.trackID, err, isUserError)
+	return true
 }
 
 func (s *trackSubscription) maybeRecordSuccess(ts telemetry.TelemetryService, pID livekit.ParticipantID) {
@@ -17,15 +17,19 @@
 package rtc
 
 import (
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pion/webrtc/v3"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
 	"github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/livekit"
@@ -350,6 +354,240 @@ func TestUpdateSettingsBeforeSubscription(t *testing.T) {
 	require.Equal(t, settings.Height, applied.Height)
 }
 
+func TestSetTrackVisibility(t *testing.T) {
+	sm := newTestSubscriptionManager(t)
+	defer sm.Close(false)
+	resolver := newTestResolver(true, true, "pub", "pubID")
+	sm.params.TrackResolver = resolver.Resolve
+
+	// visibility reported before subscription is restored on subscribe
+	hiddenFraction, visibleFraction := float32(0), float32(0.5)
+	hidden := &types.TrackVisibility{VisibleFraction: &hiddenFraction, Width: 80, Height: 60}
+	sm.SetTrackVisibility("track", hidden)
+
+	sm.SubscribeToTrack("track")
+
+	s := sm.subscriptions["track"]
+	require.Eventually(t, func() bool {
+		return !s.needsSubscribe()
+	}, subSettleTimeout, subCheckInterval, "Track should be subscribed")
+
+	st := s.getSubscribedTrack().(*typesfakes.FakeSubscribedTrack)
+	require.Eventually(t, func() bool {
+		return st.SetVisibilityCallCount() == 1
+	}, subSettleTimeout, subCheckInterval, "SetVisibility should be called once")
+	require.Equal(t, hidden, st.SetVisibilityArgsForCall(0))
+
+	// subsequent reports are forwarded to subscribed track
+	visible := &types.TrackVisibility{VisibleFraction: &visibleFraction, Width: 80, Height: 60}
+	sm.SetTrackVisibility("track", visible)
+	require.Equal(t, 2, st.SetVisibilityCallCount())
+	require.Equal(t, visible, st.SetVisibilityArgsForCall(1))
+}
+
+func TestSetTrackPlayoutDelay(t *testing.T) {
+	sm := newTestSubscriptionManager(t)
+	defer sm.Close(false)
+	resolver := newTestResolver(true, true, "pub", "pubID")
+	sm.params.TrackResolver = resolver.Resolve
+
+	// override set before subscription is applied on subscribe
+	delay := &livekit.PlayoutDelay{Enabled: true, Min: 200, Max: 400}
+	sm.SetTrackPlayoutDelay("track", delay)
+
+	sm.SubscribeToTrack("track")
+
+	s := sm.subscriptions["track"]
+	require.Eventually(t, func() bool {
+		return !s.needsSubscribe()
+	}, subSettleTimeout, subCheckInterval, "Track should be subscribed")
+
+	st := s.getSubscribedTrack().(*typesfakes.FakeSubscribedTrack)
+	require.Eventually(t, func() bool {
+		return st.SetPlayoutDelayCallCount() == 1
+	}, subSettleTimeout, subCheckInterval, "SetPlayoutDelay should be called once")
+	require.True(t, proto.Equal(delay, st.SetPlayoutDelayArgsForCall(0)))
+
+	// pausing does not drop the override, it is restored on the next subscribed track
+	sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{Disabled: true})
+	s.setSubscribedTrack(st)
+	require.Equal(t, 2, st.SetPlayoutDelayCallCount())
+	require.True(t, proto.Equal(delay, st.SetPlayoutDelayArgsForCall(1)))
+
+	// clearing the override restores default
+	sm.SetTrackPlayoutDelay("track", nil)
+	require.Equal(t, 3, st.SetPlayoutDelayCallCount())
+	require.Nil(t, st.SetPlayoutDelayArgsForCall(2))
+}
+
+func TestStartPausedSubscriptions(t *testing.T) {
+	newStartPausedTest := func(t *testing.T, kind livekit.TrackType) *SubscriptionManager {
+		sm := newTestSubscriptionManager(t)
+		t.Cleanup(func() { sm.Close(false) })
+		resolver := newTestResolver(true, true, "pub", "pubID")
+		resolver.kind = kind
+		sm.params.TrackResolver = resolver.Resolve
+		sm.params.StartPaused = []livekit.TrackType{livekit.TrackType_VIDEO}
+		return sm
+	}
+	subscribe := func(t *testing.T, sm *SubscriptionManager) *typesfakes.FakeSubscribedTrack {
+		sm.SubscribeToTrack("track")
+		s := sm.subscriptions["track"]
+		require.Eventually(t, func() bool {
+			return !s.needsSubscribe()
+		}, subSettleTimeout, subCheckInterval, "Track should be subscribed")
+		return s.getSubscribedTrack().(*typesfakes.FakeSubscribedTrack)
+	}
+
+	t.Run("video starts paused until settings are received", func(t *testing.T) {
+		sm := newStartPausedTest(t, livekit.TrackType_VIDEO)
+		st := subscribe(t, sm)
+
+		// nothing is forwarded at join, pause is left to the down track and reported by stream allocation
+		require.Equal(t, 1, st.SetStartPausedCallCount())
+		require.True(t, st.SetStartPausedArgsForCall(0))
+		require.Zero(t, st.UpdateSubscriberSettingsCallCount())
+
+		// first settings resume forwarding right away
+		settings := &livekit.UpdateTrackSettings{Width: 320, Height: 180}
+		sm.UpdateSubscribedTrackSettings("track", settings)
+		require.Equal(t, 1, st.UpdateSubscriberSettingsCallCount())
+		applied, isImmediate := st.UpdateSubscriberSettingsArgsForCall(0)
+		require.Equal(t, settings, applied)
+		require.True(t, isImmediate)
+		require.Equal(t, 2, st.SetStartPausedCallCount())
+		require.False(t, st.SetStartPausedArgsForCall(1))
+
+		// subsequent settings do not resume again
+		sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{Width: 640, Height: 360})
+		require.Equal(t, 2, st.SetStartPausedCallCount())
+	})
+
+	t.Run("disabled first settings resume", func(t *testing.T) {
+		sm := newStartPausedTest(t, livekit.TrackType_VIDEO)
+		st := subscribe(t, sm)
+		require.Equal(t, 1, st.SetStartPausedCallCount())
+
+		// the pause is cleared after the settings are applied, so that the track goes from paused to muted
+		settings := &livekit.UpdateTrackSettings{Disabled: true}
+		sm.UpdateSubscribedTrackSettings("track", settings)
+		require.Equal(t, 1, st.UpdateSubscriberSettingsCallCount())
+		applied, _ := st.UpdateSubscriberSettingsArgsForCall(0)
+		require.Equal(t, settings, applied)
+		require.Equal(t, 2, st.SetStartPausedCallCount())
+		require.False(t, st.SetStartPausedArgsForCall(1))
+
+		// enabling later is a regular settings update
+		sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{Width: 320, Height: 180})
+		require.Equal(t, 2, st.UpdateSubscriberSettingsCallCount())
+		require.Equal(t, 2, st.SetStartPausedCallCount())
+	})
+
+	t.Run("settings before subscription", func(t *testing.T) {
+		sm := newStartPausedTest(t, livekit.TrackType_VIDEO)
+
+		settings := &livekit.UpdateTrackSettings{Width: 320, Height: 180}
+		sm.UpdateSubscribedTrackSettings("track", settings)
+		st := subscribe(t, sm)
+
+		require.Equal(t, 1, st.UpdateSubscriberSettingsCallCount())
+		applied, _ := st.UpdateSubscriberSettingsArgsForCall(0)
+		require.Equal(t, settings, applied)
+		require.Zero(t, st.SetStartPausedCallCount())
+	})
+
+	t.Run("audio is exempt", func(t *testing.T) {
+		sm := newStartPausedTest(t, livekit.TrackType_AUDIO)
+		st := subscribe(t, sm)
+
+		require.Zero(t, st.UpdateSubscriberSettingsCallCount())
+		require.Zero(t, st.SetStartPausedCallCount())
+	})
+}
+
+func TestSubscriberAudioOnly(t *testing.T) {
+	subscribe := func(t *testing.T, sm *SubscriptionManager, trackID livekit.TrackID) *typesfakes.FakeSubscribedTrack {
+		sm.SubscribeToTrack(trackID)
+		sm.lock.RLock()
+		s := sm.subscriptions[trackID]
+		sm.lock.RUnlock()
+		require.Eventually(t, func() bool {
+			return !s.needsSubscribe()
+		}, subSettleTimeout, subCheckInterval, "Track should be subscribed")
+		return s.getSubscribedTrack().(*typesfakes.FakeSubscribedTrack)
+	}
+	lastApplied := func(st *typesfakes.FakeSubscribedTrack) *livekit.UpdateTrackSettings {
+		applied, _ := st.UpdateSubscriberSettingsArgsForCall(st.UpdateSubscriberSettingsCallCount() - 1)
+		return applied
+	}
+
+	t.Run("disables current and future video subscriptions", func(t *testing.T) {
+		sm := newTestSubscriptionManager(t)
+		defer sm.Close(false)
+		resolver := newTestResolver(true, true, "pub", "pubID")
+		resolver.kind = livekit.TrackType_VIDEO
+		sm.params.TrackResolver = resolver.Resolve
+
+		settings := &livekit.UpdateTrackSettings{Width: 320, Height: 180}
+		sm.UpdateSubscribedTrackSettings("track1", settings)
+		st1 := subscribe(t, sm, "track1")
+		require.Equal(t, 1, st1.UpdateSubscriberSettingsCallCount())
+
+		sm.SetAudioOnly(true)
+		require.True(t, sm.IsAudioOnly())
+		require.Equal(t, 2, st1.UpdateSubscriberSettingsCallCount())
+		require.True(t, lastApplied(st1).Disabled)
+
+		// settings are kept, but not applied
+		updated := &livekit.UpdateTrackSettings{Quality: livekit.VideoQuality_LOW}
+		sm.UpdateSubscribedTrackSettings("track1", updated)
+		require.Equal(t, 2, st1.UpdateSubscriberSettingsCallCount())
+
+		st2 := subscribe(t, sm, "track2")
+		require.Equal(t, 1, st2.UpdateSubscriberSettingsCallCount())
+		require.True(t, lastApplied(st2).Disabled)
+
+		// last settings of subscriber are restored, defaults without settings
+		sm.SetAudioOnly(false)
+		require.False(t, sm.IsAudioOnly())
+		require.Equal(t, updated, lastApplied(st1))
+		require.Equal(t, defaultSubscriberSettings, lastApplied(st2))
+	})
+
+	t.Run("subscription started paused stays paused", func(t *testing.T) {
+		sm := newTestSubscriptionManager(t)
+		defer sm.Close(false)
+		resolver := newTestResolver(true, true, "pub", "pubID")
+		resolver.kind = livekit.TrackType_VIDEO
+		sm.params.TrackResolver = resolver.Resolve
+		sm.params.StartPaused = []livekit.TrackType{livekit.TrackType_VIDEO}
+
+		sm.SetAudioOnly(true)
+		st := subscribe(t, sm, "track")
+		require.Eventually(t, func() bool {
+			return st.SetStartPausedCallCount() == 1
+		}, subSettleTimeout, subCheckInterval)
+
+		sm.SetAudioOnly(false)
+		require.Equal(t, 1, st.SetStartPausedCallCount())
+		require.True(t, lastApplied(st).Disabled)
+	})
+
+	t.Run("audio is not affected", func(t *testing.T) {
+		sm := newTestSubscriptionManager(t)
+		defer sm.Close(false)
+		resolver := newTestResolver(true, true, "pub", "pubID")
+		sm.params.TrackResolver = resolver.Resolve
+
+		st := subscribe(t, sm, "track")
+		sm.SetAudioOnly(true)
+		sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{})
+		sm.SetAudioOnly(false)
+		require.Equal(t, 1, st.UpdateSubscriberSettingsCallCount())
+		require.False(t, lastApplied(st).Disabled)
+	})
+}
+
 func TestSubscriptionLimits(t *testing.T) {
 	sm := newTestSubscriptionManagerWithParams(t, testSubscriptionParams{
 		SubscriptionLimitAudio: 1,
@@ -447,9 +685,247 @@ func TestSubscriptionLimits(t *testing.T) {
 	require.Len(t, sm.GetSubscribedTracks(), 1)
 }
 
+func TestUpdateSubscriptionLimits(t *testing.T) {
+	sm := newTestSubscriptionManager(t)
+	defer sm.Close(false)
+	resolver := newTestResolver(true, true, "pub", "pubID")
+	sm.params.TrackResolver = resolver.Resolve
+	subCount := atomic.Int32{}
+	sm.params.OnTrackSubscribed = func(subTrack types.SubscribedTrack) {
+		subCount.Add(1)
+	}
+	var errLock sync.Mutex
+	var errTrackIDs []livekit.TrackID
+	sm.params.OnSubscriptionError = func(trackID livekit.TrackID, fatal bool, err error) {
+		require.False(t, fatal)
+		require.ErrorIs(t, err, ErrSubscriptionLimitExceeded)
+		errLock.Lock()
+		errTrackIDs = append(errTrackIDs, trackID)
+		errLock.Unlock()
+	}
+	getErrTrackIDs := func() []livekit.TrackID {
+		errLock.Lock()
+		defer errLock.Unlock()
+		return append([]livekit.TrackID{}, errTrackIDs...)
+	}
+
+	// track2 and track4 have unset priority, which is the lowest
+	priorities := map[livekit.TrackID]uint32{"track1": 1, "track2": 0, "track3": 2, "track4": 0}
+	for i, trackID := range []livekit.TrackID{"track1", "track2", "track3", "track4"} {
+		sm.UpdateSubscribedTrackSettings(trackID, &livekit.UpdateTrackSettings{TrackSids: []string{string(trackID)}, Priority: priorities[trackID]})
+		sm.SubscribeToTrack(trackID)
+		require.Eventually(t, func() bool {
+			return subCount.Load() == int32(i+1)
+		}, subSettleTimeout, subCheckInterval, "track was not subscribed")
+	}
+
+	s1 := sm.subscriptions["track1"]
+	s2 := sm.subscriptions["track2"]
+	closeEvicted := func(trackIDs ...livekit.TrackID) {
+		for _, trackID := range trackIDs {
+			setTestSubscribedTrackClosed(t, sm.subscriptions[trackID].getSubscribedTrack(), false)
+		}
+	}
+
+	// older of the lowest priority is removed first
+	sm.UpdateSubscriptionLimits(3, 0)
+	require.Eventually(t, func() bool {
+		return len(getErrTrackIDs()) == 1
+	}, subSettleTimeout, subCheckInterval, "subscription limit was not enforced")
+	require.Equal(t, []livekit.TrackID{"track2"}, getErrTrackIDs())
+	// evicted subscription is still wanted
+	require.True(t, s2.isDesired())
+	closeEvicted("track2")
+	require.Eventually(t, func() bool {
+		return slices.Equal([]livekit.TrackID{"track2"}, sm.GetDeniedSubscriptions())
+	}, subSettleTimeout, subCheckInterval, "evicted subscription was not denied")
+
+	sm.UpdateSubscriptionLimits(1, 0)
+	require.Eventually(t, func() bool {
+		return len(getErrTrackIDs()) == 3
+	}, subSettleTimeout, subCheckInterval, "subscription limit was not enforced")
+	require.Equal(t, []livekit.TrackID{"track2", "track4", "track3"}, getErrTrackIDs())
+	require.True(t, s1.isDesired())
+	closeEvicted("track4", "track3")
+	require.Eventually(t, func() bool {
+		return len(sm.GetDeniedSubscriptions()) == 3
+	}, subSettleTimeout, subCheckInterval, "evicted subscriptions were not denied")
+
+	// evicted subscriptions are not notified again
+	time.Sleep(reconcileInterval)
+	require.Len(t, getErrTrackIDs(), 3)
+
+	audio, video := sm.GetSubscriptionLimits()
+	require.EqualValues(t, 1, audio)
+	require.EqualValues(t, 0, video)
+
+	// raising limit brings back evicted subscriptions without removing more
+	subCount.Store(0)
+	sm.UpdateSubscriptionLimits(3, 0)
+	require.Eventually(t, func() bool {
+		return subCount.Load() == 2 && len(sm.GetDeniedSubscriptions()) == 1
+	}, subSettleTimeout, subCheckInterval, "evicted subscriptions did not return")
+	require.Len(t, getErrTrackIDs(), 3)
+}
+
+func TestGetDeniedSubscriptions(t *testing.T) {
+	sm := newTestSubscriptionManagerWithParams(t, testSubscriptionParams{
+		SubscriptionLimitAudio: 1,
+	})
+	defer sm.Close(false)
+	resolver := newTestResolver(true, true, "pub", "pubID")
+	sm.params.TrackResolver = resolver.Resolve
+	subCount := atomic.Int32{}
+	sm.params.OnTrackSubscribed = func(subTrack types.SubscribedTrack) {
+		subCount.Add(1)
+	}
+
+	sm.SubscribeToTrack("track1")
+	require.Eventually(t, func() bool {
+		return subCount.Load() == 1
+	}, subSettleTimeout, subCheckInterval, "track was not subscribed")
+	require.Empty(t, sm.GetDeniedSubscriptions())
+
+	for _, trackID := range []livekit.TrackID{"track2", "track3", "track4"} {
+		sm.SubscribeToTrack(trackID)
+	}
+	require.Eventually(t, func() bool {
+		return len(sm.GetDeniedSubscriptions()) == 3
+	}, subSettleTimeout, subCheckInterval, "subscriptions were not denied")
+	require.Equal(t, []livekit.TrackID{"track2", "track3", "track4"}, sm.GetDeniedSubscriptions())
+
+	// unsubscribed track is not wanted anymore
+	sm.UnsubscribeFromTrack("track4")
+	require.Equal(t, []livekit.TrackID{"track2", "track3"}, sm.GetDeniedSubscriptions())
+
+	// raising limit lets one more through
+	sm.UpdateSubscriptionLimits(2, 0)
+	require.Eventually(t, func() bool {
+		return subCount.Load() == 2 && len(sm.GetDeniedSubscriptions()) == 1
+	}, subSettleTimeout, subCheckInterval, "denied subscriptions were not updated on limit change")
+
+	// releasing a subscription lets the last one through
+	s1 := sm.subscriptions["track1"]
+	sm.UnsubscribeFromTrack("track1")
+	time.Sleep(reconcileInterval)
+	setTestSubscribedTrackClosed(t, s1.getSubscribedTrack(), false)
+	require.Eventually(t, func() bool {
+		return subCount.Load() == 3 && len(sm.GetDeniedSubscriptions()) == 0
+	}, subSettleTimeout, subCheckInterval, "denied subscriptions were not updated on release")
+}
+
+func TestScreenShareSubscriptionLimit(t *testing.T) {
+	sm := newTestSubscriptionManagerWithParams(t, testSubscriptionParams{
+		SubscriptionLimitScreenShare: 1,
+	})
+	defer sm.Close(false)
+	resolver := newTestResolver(true, true, "pub", "pubID")
+	resolver.kind = livekit.TrackType_VIDEO
+	resolver.sources = map[livekit.TrackID]livekit.TrackSource{
+		"screen1": livekit.TrackSource_SCREEN_SHARE,
+		"screen2": livekit.TrackSource_SCREEN_SHARE,
+	}
+	sm.params.TrackResolver = resolver.Resolve
+	subCount := atomic.Int32{}
+	sm.params.OnTrackSubscribed = func(subTrack types.SubscribedTrack) {
+		subCount.Add(1)
+	}
+	var errLock sync.Mutex
+	var errTrackIDs []livekit.TrackID
+	sm.params.OnSubscriptionError = func(trackID livekit.TrackID, fatal bool, err error) {
+		require.False(t, fatal)
+		require.ErrorIs(t, err, ErrScreenShareLimitExceeded)
+		errLock.Lock()
+		errTrackIDs = append(errTrackIDs, trackID)
+		errLock.Unlock()
+	}
+	getErrTrackIDs := func() []livekit.TrackID {
+		errLock.Lock()
+		defer errLock.Unlock()
+		return append([]livekit.TrackID{}, errTrackIDs...)
+	}
+
+	// camera tracks are not limited
+	for i, trackID := range []livekit.TrackID{"camera1", "camera2", "screen1"} {
+		sm.SubscribeToTrack(trackID)
+		require.Eventually(t, func() bool {
+			return subCount.Load() == int32(i+1)
+		}, subSettleTimeout, subCheckInterval, "track was not subscribed")
+	}
+
+	// second screen share is denied and subscriber is notified once
+	sm.SubscribeToTrack("screen2")
+	s2 := sm.subscriptions["screen2"]
+	require.Eventually(t, func() bool {
+		return len(getErrTrackIDs()) == 1
+	}, subSettleTimeout, subCheckInterval, "screen share subscription was not denied")
+	require.Equal(t, []livekit.TrackID{"screen2"}, getErrTrackIDs())
+	time.Sleep(reconcileInterval)
+	require.True(t, s2.needsSubscribe())
+	require.Len(t, getErrTrackIDs(), 1)
+	require.Len(t, sm.GetSubscribedTracks(), 3)
+
+	// when first screen share ends, second one is subscribed
+	s1 := sm.subscriptions["screen1"]
+	sm.UnsubscribeFromTrack("screen1")
+	time.Sleep(reconcileInterval)
+	setTestSubscribedTrackClosed(t, s1.getSubscribedTrack(), false)
+	require.Eventually(t, func() bool {
+		return subCount.Load() == 4
+	}, subSettleTimeout, subCheckInterval, "screen share was not subscribed after limit relieved")
+	require.NotNil(t, s2.getSubscribedTrack())
+}
+
 type testSubscriptionParams struct {
-	SubscriptionLimitAudio int32
-	SubscriptionLimitVideo int32
+	SubscriptionLimitAudio       int32
+	SubscriptionLimitVideo       int32
+	SubscriptionLimitScreenShare int32
+}
+
+func TestTemporalLayersUnsupported(t *testing.T) {
+	sm := newTestSubscriptionManager(t)
+	defer sm.Close(false)
+	var errs []error
+	sm.params.OnSubscriptionError = func(trackID livekit.TrackID, fatal bool, err error) {
+		require.Equal(t, livekit.TrackID("track"), trackID)
+		require.False(t, fatal)
+		errs = append(errs, err)
+	}
+
+	// not subscribed yet
+	sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{Fps: 15})
+	require.Empty(t, errs)
+
+	dt, err := sfu.NewDownTrack(sfu.DowntrackParams{
+		Codecs: []webrtc.RTPCodecParameters{
+			{RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeH264, ClockRate: 90000}},
+		},
+		Receiver: &visibilityTestReceiver{},
+		SubID:    "sub",
+		Logger:   logger.GetLogger(),
+	})
+	require.NoError(t, err)
+	defer dt.Close()
+
+	mt := &typesfakes.FakeMediaTrack{}
+	st := &typesfakes.FakeSubscribedTrack{}
+	st.DownTrackReturns(dt)
+	st.MediaTrackReturns(mt)
+	sm.subscriptions["track"].setSubscribedTrack(st)
+
+	mt.SupportsTemporalLayersReturns(true)
+	sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{Fps: 15})
+	require.Empty(t, errs)
+
+	// subscriber is told that frame rate cannot be applied
+	mt.SupportsTemporalLayersReturns(false)
+	sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{Fps: 15})
+	require.Equal(t, []error{ErrTemporalLayersUnsupported}, errs)
+	require.Equal(t, webrtc.MimeTypeH264, mt.SupportsTemporalLayersArgsForCall(1))
+
+	// no frame rate requested
+	sm.UpdateSubscribedTrackSettings("track", &livekit.UpdateTrackSettings{Width: 640, Height: 360})
+	require.Len(t, errs, 1)
 }
 
 func newTestSubscriptionManager(t *testing.T) *SubscriptionManager {
@@ -470,9 +946,10 @@ func newTestSubscriptionManagerWithParams(t *testing.T, params testSubscriptionP
 		TrackResolver: func(identity livekit.ParticipantIdentity, trackID livekit.TrackID) types.MediaResolverResult {
 			return types.MediaResolverResult{}
 		},
-		Telemetry:              &telemetryfakes.FakeTelemetryService{},
-		SubscriptionLimitAudio: params.SubscriptionLimitAudio,
-		SubscriptionLimitVideo: params.SubscriptionLimitVideo,
+		Telemetry:                    &telemetryfakes.FakeTelemetryService{},
+		SubscriptionLimitAudio:       params.SubscriptionLimitAudio,
+		SubscriptionLimitVideo:       params.SubscriptionLimitVideo,
+		SubscriptionLimitScreenShare: params.SubscriptionLimitScreenShare,
 	})
 }
 
@@ -483,7 +960,9 @@ type testResolver struct {
 	pubIdentity   livekit.ParticipantIdentity
 	pubID         livekit.ParticipantID
 
-	paused bool
+	paused  bool
+	kind    livekit.TrackType
+	sources map[livekit.TrackID]livekit.TrackSource
 }
 
 func newTestResolver(hasPermission bool, hasTrack bool, pubIdentity livekit.ParticipantIdentity, pubID livekit.ParticipantID) *testResolver {
@@ -513,6 +992,8 @@ func (t *testResolver) Resolve(identity livekit.ParticipantIdentity, trackID liv
 	}
 	if t.hasTrack && !t.paused {
 		mt := &typesfakes.FakeMediaTrack{}
+		mt.KindReturns(t.kind)
+		mt.SourceReturns(t.sources[trackID])
 		st := &typesfakes.FakeSubscribedTrack{}
 		st.IDReturns(trackID)
 		st.PublisherIDReturns(t.pubID)
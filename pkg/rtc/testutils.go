@@ -66,7 +66,7 @@ func NewMockParticipant(identity livekit.ParticipantIdentity, protocol types.Pro
 		}
 	}
 
-	p.SetTrackMutedCalls(func(sid livekit.TrackID, muted bool, fromServer bool) *livekit.TrackInfo {
+	p.SetTrackMutedCalls(func(sid livekit.TrackID, muted bool, adminOpts *types.AdminActionOptions) *livekit.TrackInfo {
 		updateTrack()
 		return nil
 	})
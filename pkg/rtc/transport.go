@@ -17,6 +17,8 @@ package rtc
 import (
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,7 +38,6 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/atomic"
 
-	lkinterceptor "github.com/livekit/mediatransportutil/pkg/interceptor"
 	lktwcc "github.com/livekit/mediatransportutil/pkg/twcc"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -61,6 +62,7 @@ const (
 
 	negotiationFrequency       = 150 * time.Millisecond
 	negotiationFailedTimeout   = 15 * time.Second
+	negotiationPendingTimeout  = 5 * time.Second // time after which a pending negotiation without offer in progress is retried
 	dtlsRetransmissionInterval = 100 * time.Millisecond
 
 	iceDisconnectedTimeout = 10 * time.Second                          // compatible for ice-lite with firefox client
@@ -96,6 +98,7 @@ const (
 	signalSendOffer
 	signalRemoteDescriptionReceived
 	signalICERestart
+	signalNegotiationPendingCheck
 )
 
 func (s signal) String() string {
@@ -112,6 +115,8 @@ func (s signal) String() string {
 		return "REMOTE_DESCRIPTION_RECEIVED"
 	case signalICERestart:
 		return "ICE_RESTART"
+	case signalNegotiationPendingCheck:
+		return "NEGOTIATION_PENDING_CHECK"
 	default:
 		return fmt.Sprintf("%d", int(s))
 	}
@@ -170,6 +175,9 @@ type PCTransport struct {
 
 	onNegotiationStateChanged func(state transport.NegotiationState)
 
+	negotiationQueue        *negotiationQueue
+	negotiationPendingTimer *time.Timer
+
 	// stream allocator for subscriber PC
 	streamAllocator *streamallocator.StreamAllocator
 
@@ -218,7 +226,39 @@ type TransportParams struct {
 	IsOfferer                    bool
 	IsSendSide                   bool
 	AllowPlayoutDelay            bool
+	AllowPlayoutDelayAudio       bool
 	DataChannelMaxBufferedAmount uint64
+	// buffered amount low notifications are enabled when non-zero
+	DataChannelLowBufferedAmount uint64
+}
+
+func newAllocationRecorder(params TransportParams) *streamallocator.AllocationRecorder {
+	recorderConfig := params.CongestionControlConfig.AllocationRecorder
+	if !recorderConfig.Enabled || recorderConfig.Directory == "" {
+		return nil
+	}
+
+	path := filepath.Join(recorderConfig.Directory, fmt.Sprintf("%s_%d.sarec", params.ParticipantID, time.Now().Unix()))
+	f, err := os.Create(path)
+	if err != nil {
+		params.Logger.Warnw("could not create allocation recording", err, "path", path)
+		return nil
+	}
+
+	recorder, err := streamallocator.NewAllocationRecorder(streamallocator.AllocationRecorderParams{
+		Writer:              f,
+		MaxBytes:            recorderConfig.MaxBytes,
+		MaxRecordsPerSecond: recorderConfig.MaxRecordsPerSecond,
+		Logger:              params.Logger,
+	})
+	if err != nil {
+		params.Logger.Warnw("could not start allocation recording", err, "path", path)
+		_ = f.Close()
+		return nil
+	}
+
+	params.Logger.Infow("recording allocations", "path", path)
+	return recorder
 }
 
 func newPeerConnection(params TransportParams, onBandwidthEstimator func(estimator cc.BandwidthEstimator)) (*webrtc.PeerConnection, *webrtc.MediaEngine, error) {
@@ -226,6 +266,9 @@ func newPeerConnection(params TransportParams, onBandwidthEstimator func(estimat
 	if params.AllowPlayoutDelay {
 		directionConfig.RTPHeaderExtension.Video = append(directionConfig.RTPHeaderExtension.Video, pd.PlayoutDelayURI)
 	}
+	if params.AllowPlayoutDelayAudio {
+		directionConfig.RTPHeaderExtension.Audio = append(directionConfig.RTPHeaderExtension.Audio, pd.PlayoutDelayURI)
+	}
 
 	// Some of the browser clients do not handle H.264 High Profile in signalling properly.
 	// They still decode if the actual stream is H.264 High Profile, but do not handle it well in signalling.
@@ -326,10 +369,6 @@ func newPeerConnection(params TransportParams, onBandwidthEstimator func(estimat
 				}
 			}
 		}
-	} else {
-		// sfu only use interceptor to send XR but don't read response from it (use buffer instead),
-		// so use a empty callback here
-		ir.Add(lkinterceptor.NewRTTFromXRFactory(func(rtt uint32) {}))
 	}
 	if len(params.SimTracks) > 0 {
 		f, err := NewUnhandleSimulcastInterceptorFactory(UnhandleSimulcastTracks(params.SimTracks))
@@ -390,6 +429,7 @@ func NewPCTransport(params TransportParams) (*PCTransport, error) {
 		params:             params,
 		debouncedNegotiate: debounce.New(negotiationFrequency),
 		negotiationState:   transport.NegotiationStateNone,
+		negotiationQueue:   newNegotiationQueue(params.Transport),
 		eventsQueue: utils.NewTypedOpsQueue[event](utils.OpsQueueParams{
 			Name:    "transport",
 			MinSize: 64,
@@ -401,12 +441,14 @@ func NewPCTransport(params TransportParams) (*PCTransport, error) {
 	}
 	if params.IsSendSide {
 		t.streamAllocator = streamallocator.NewStreamAllocator(streamallocator.StreamAllocatorParams{
-			Config: params.CongestionControlConfig,
-			Logger: params.Logger.WithComponent(utils.ComponentCongestionControl),
+			Config:   params.CongestionControlConfig,
+			Recorder: newAllocationRecorder(params),
+			Logger:   params.Logger.WithComponent(utils.ComponentCongestionControl),
 		})
 		t.streamAllocator.OnStreamStateChange(params.Handler.OnStreamStateChange)
 		t.streamAllocator.Start()
-		t.pacer = pacer.NewPassThrough(params.Logger)
+		// passes packets through without a worker or lock till participant sets custom pacing
+		t.pacer = pacer.NewBurst(params.Logger)
 	}
 
 	if err := t.createPeerConnection(); err != nil {
@@ -666,6 +708,7 @@ func (t *PCTransport) onDataChannel(dc *webrtc.DataChannel) {
 		t.reliableDC = dc
 		t.reliableDCOpened = true
 		t.lock.Unlock()
+		t.setupBufferedAmountLow(dc, livekit.DataPacket_RELIABLE)
 		dc.OnMessage(func(msg webrtc.DataChannelMessage) {
 			t.params.Handler.OnDataPacket(livekit.DataPacket_RELIABLE, msg.Data)
 		})
@@ -676,6 +719,7 @@ func (t *PCTransport) onDataChannel(dc *webrtc.DataChannel) {
 		t.lossyDC = dc
 		t.lossyDCOpened = true
 		t.lock.Unlock()
+		t.setupBufferedAmountLow(dc, livekit.DataPacket_LOSSY)
 		dc.OnMessage(func(msg webrtc.DataChannelMessage) {
 			t.params.Handler.OnDataPacket(livekit.DataPacket_LOSSY, msg.Data)
 		})
@@ -829,9 +873,11 @@ func (t *PCTransport) CreateDataChannel(label string, dci *webrtc.DataChannelIni
 	case ReliableDataChannel:
 		dcPtr = &t.reliableDC
 		dcReady = &t.reliableDCOpened
+		t.setupBufferedAmountLow(dc, livekit.DataPacket_RELIABLE)
 	case LossyDataChannel:
 		dcPtr = &t.lossyDC
 		dcReady = &t.lossyDCOpened
+		t.setupBufferedAmountLow(dc, livekit.DataPacket_LOSSY)
 	}
 
 	dcReadyHandler := func() {
@@ -915,6 +961,35 @@ func (t *PCTransport) IsEstablished() bool {
 	return t.pc.ConnectionState() != webrtc.PeerConnectionStateNew
 }
 
+// IsConnected returns true if the PeerConnection is currently connected
+func (t *PCTransport) IsConnected() bool {
+	return t.pc.ConnectionState() == webrtc.PeerConnectionStateConnected
+}
+
+// GetCurrentSdp returns the current local and remote descriptions,
+// either of which could be nil if negotiation has not completed
+func (t *PCTransport) GetCurrentSdp() (local, remote *webrtc.SessionDescription) {
+	return t.pc.CurrentLocalDescription(), t.pc.CurrentRemoteDescription()
+}
+
+// GetDataChannelInfos returns label and ID of opened data channels
+func (t *PCTransport) GetDataChannelInfos() []*livekit.DataChannelInfo {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var infos []*livekit.DataChannelInfo
+	for _, dc := range []*webrtc.DataChannel{t.reliableDC, t.lossyDC} {
+		if dc == nil || dc.ID() == nil {
+			continue
+		}
+		infos = append(infos, &livekit.DataChannelInfo{
+			Label: dc.Label(),
+			Id:    uint32(*dc.ID()),
+		})
+	}
+	return infos
+}
+
 func (t *PCTransport) HasEverConnected() bool {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
@@ -926,6 +1001,25 @@ func (t *PCTransport) GetICEConnectionDetails() *types.ICEConnectionDetails {
 	return t.connectionDetails
 }
 
+func (t *PCTransport) GetConnectionState() webrtc.PeerConnectionState {
+	return t.pc.ConnectionState()
+}
+
+// GetDTLSState returns state of the DTLS transport, it can be connecting or failed while ICE is connected
+func (t *PCTransport) GetDTLSState() webrtc.DTLSTransportState {
+	s := t.pc.SCTP()
+	if s == nil {
+		return webrtc.DTLSTransportStateNew
+	}
+
+	dtlsTransport := s.Transport()
+	if dtlsTransport == nil {
+		return webrtc.DTLSTransportStateNew
+	}
+
+	return dtlsTransport.State()
+}
+
 func (t *PCTransport) WriteRTCP(pkts []rtcp.Packet) error {
 	return t.pc.WriteRTCP(pkts)
 }
@@ -952,7 +1046,36 @@ func (t *PCTransport) SendDataPacket(kind livekit.DataPacket_Kind, encoded []byt
 		return ErrDataChannelBufferFull
 	}
 
-	return dc.Send(encoded)
+	if err := dc.Send(encoded); err != nil {
+		return fmt.Errorf("%w: %w", ErrDataChannelSendFailed, err)
+	}
+	return nil
+}
+
+// DataChannelBufferedAmount returns number of bytes queued to be sent on data channel of kind
+func (t *PCTransport) DataChannelBufferedAmount(kind livekit.DataPacket_Kind) uint64 {
+	t.lock.RLock()
+	dc := t.lossyDC
+	if kind == livekit.DataPacket_RELIABLE {
+		dc = t.reliableDC
+	}
+	t.lock.RUnlock()
+
+	if dc == nil {
+		return 0
+	}
+	return dc.BufferedAmount()
+}
+
+func (t *PCTransport) setupBufferedAmountLow(dc *webrtc.DataChannel, kind livekit.DataPacket_Kind) {
+	if t.params.DataChannelLowBufferedAmount == 0 {
+		return
+	}
+
+	dc.SetBufferedAmountLowThreshold(t.params.DataChannelLowBufferedAmount)
+	dc.OnBufferedAmountLow(func() {
+		t.params.Handler.OnDataChannelBufferedAmountLow(kind)
+	})
 }
 
 func (t *PCTransport) Close() {
@@ -963,6 +1086,14 @@ func (t *PCTransport) Close() {
 	<-t.eventsQueue.Stop()
 	t.clearSignalStateCheckTimer()
 
+	t.lock.Lock()
+	if t.negotiationPendingTimer != nil {
+		t.negotiationPendingTimer.Stop()
+		t.negotiationPendingTimer = nil
+	}
+	t.lock.Unlock()
+	t.negotiationQueue.fail(negotiationOutcomeAborted)
+
 	if t.streamAllocator != nil {
 		t.streamAllocator.Stop()
 	}
@@ -1042,12 +1173,52 @@ func (t *PCTransport) Negotiate(force bool) {
 	}
 }
 
+// NegotiateForCause negotiates and keeps the cause pending until an offer/answer exchange including it completes
+func (t *PCTransport) NegotiateForCause(force bool, cause transport.NegotiationCause) {
+	t.QueueNegotiation(cause)
+	t.Negotiate(force)
+}
+
+// QueueNegotiation adds a cause of negotiation which will be completed by the next offer/answer exchange,
+// negotiation pending longer than negotiationPendingTimeout is retried once.
+func (t *PCTransport) QueueNegotiation(cause transport.NegotiationCause) {
+	if t.isClosed.Load() || !t.negotiationQueue.add(cause) {
+		return
+	}
+
+	t.lock.Lock()
+	t.setNegotiationPendingTimerLocked(negotiationPendingTimeout)
+	t.lock.Unlock()
+}
+
+func (t *PCTransport) GetPendingNegotiations() []PendingNegotiation {
+	return t.negotiationQueue.get()
+}
+
+func (t *PCTransport) setNegotiationPendingTimerLocked(after time.Duration) {
+	if t.negotiationPendingTimer != nil {
+		return
+	}
+
+	t.negotiationPendingTimer = time.AfterFunc(after, func() {
+		t.lock.Lock()
+		t.negotiationPendingTimer = nil
+		t.lock.Unlock()
+
+		t.postEvent(event{
+			signal: signalNegotiationPendingCheck,
+		})
+	})
+}
+
 func (t *PCTransport) ICERestart() error {
 	if t.pc.ConnectionState() == webrtc.PeerConnectionStateClosed {
 		t.params.Logger.Warnw("trying to restart ICE on closed peer connection", nil)
 		return ErrIceRestartOnClosedPeerConnection
 	}
 
+	t.QueueNegotiation(transport.NegotiationCauseICERestart)
+
 	t.postEvent(event{
 		signal: signalICERestart,
 	})
@@ -1086,6 +1257,14 @@ func (t *PCTransport) SetAllowPauseOfStreamAllocator(allowPause bool) {
 	t.streamAllocator.SetAllowPause(allowPause)
 }
 
+func (t *PCTransport) IsStreamAllocatorDeficient() bool {
+	if t.streamAllocator == nil {
+		return false
+	}
+
+	return t.streamAllocator.IsDeficient()
+}
+
 func (t *PCTransport) SetChannelCapacityOfStreamAllocator(channelCapacity int64) {
 	if t.streamAllocator == nil {
 		return
@@ -1094,6 +1273,22 @@ func (t *PCTransport) SetChannelCapacityOfStreamAllocator(channelCapacity int64)
 	t.streamAllocator.SetChannelCapacity(channelCapacity)
 }
 
+func (t *PCTransport) SeedChannelCapacityOfStreamAllocator(channelCapacity int64) {
+	if t.streamAllocator == nil {
+		return
+	}
+
+	t.streamAllocator.SeedChannelCapacity(channelCapacity)
+}
+
+func (t *PCTransport) GetReceivedEstimateOfStreamAllocator() int64 {
+	if t.streamAllocator == nil {
+		return 0
+	}
+
+	return t.streamAllocator.GetReceivedEstimate()
+}
+
 func (t *PCTransport) preparePC(previousAnswer webrtc.SessionDescription) error {
 	// sticky data channel to first m-lines, if someday we don't send sdp without media streams to
 	// client's subscribe pc after joining, should change this step
@@ -1288,10 +1483,13 @@ func (t *PCTransport) postEvent(e event) {
 			err = e.handleRemoteDescriptionReceived(e)
 		case signalICERestart:
 			err = e.handleICERestart(e)
+		case signalNegotiationPendingCheck:
+			err = e.handleNegotiationPendingCheck(e)
 		}
 		if err != nil {
 			if !e.isClosed.Load() {
 				e.params.Logger.Warnw("error handling event", err, "event", e.String())
+				e.negotiationQueue.fail(negotiationOutcomeFailed)
 				e.params.Handler.OnNegotiationFailed()
 			}
 		}
@@ -1485,7 +1683,9 @@ func (t *PCTransport) setupSignalStateCheckTimer() {
 				"localPending", t.pc.PendingLocalDescription(),
 				"remoteCurrent", t.pc.CurrentRemoteDescription(),
 				"remotePending", t.pc.PendingRemoteDescription(),
+				"pendingNegotiations", t.negotiationQueue.DebugInfo(),
 			)
+			t.negotiationQueue.fail(negotiationOutcomeFailed)
 			t.params.Handler.OnNegotiationFailed()
 		}
 	})
@@ -1582,6 +1782,7 @@ func (t *PCTransport) createAndSendOffer(options *webrtc.OfferOptions) error {
 	}
 
 	prometheus.ServiceOperationCounter.WithLabelValues("offer", "success", "").Add(1)
+	t.negotiationQueue.start()
 	return t.localDescriptionSent()
 }
 
@@ -1589,6 +1790,41 @@ func (t *PCTransport) handleSendOffer(_ event) error {
 	return t.createAndSendOffer(nil)
 }
 
+// handleNegotiationPendingCheck takes corrective action for negotiations pending too long. Only a negotiation
+// which did not result in an offer is retried, an offer waiting for answer is not sent again as the remote
+// may have received it, such an exchange which does not complete is failed by the signal state check.
+func (t *PCTransport) handleNegotiationPendingCheck(_ event) error {
+	if t.negotiationState != transport.NegotiationStateNone {
+		// check again once the exchange in progress has completed
+		t.lock.Lock()
+		t.setNegotiationPendingTimerLocked(negotiationPendingTimeout)
+		t.lock.Unlock()
+		return nil
+	}
+
+	retried, nextCheck := t.negotiationQueue.retry(negotiationPendingTimeout)
+	if !retried {
+		if nextCheck != 0 {
+			t.lock.Lock()
+			t.setNegotiationPendingTimerLocked(nextCheck)
+			t.lock.Unlock()
+		}
+		return nil
+	}
+
+	t.params.Logger.Infow(
+		"negotiation pending too long",
+		"negotiationState", t.negotiationState,
+		"pendingNegotiations", t.negotiationQueue.DebugInfo(),
+	)
+	if !t.params.IsOfferer || t.pc.ConnectionState() == webrtc.PeerConnectionStateClosed {
+		return nil
+	}
+
+	// no offer in progress, negotiation request did not result in an offer
+	return t.createAndSendOffer(nil)
+}
+
 func (t *PCTransport) handleRemoteDescriptionReceived(e event) error {
 	sd := e.data.(*webrtc.SessionDescription)
 	if sd.Type == webrtc.SDPTypeOffer {
@@ -1694,6 +1930,9 @@ func (t *PCTransport) createAndSendAnswer() error {
 	}
 
 	prometheus.ServiceOperationCounter.WithLabelValues("answer", "success", "").Add(1)
+	// answer completes all negotiations queued until the offer was answered
+	t.negotiationQueue.start()
+	t.negotiationQueue.complete()
 	return t.localDescriptionSent()
 }
 
@@ -1766,6 +2005,7 @@ func (t *PCTransport) handleRemoteAnswerReceived(sd *webrtc.SessionDescription)
 			return err
 		}
 	}
+	t.negotiationQueue.complete()
 
 	if t.negotiationState == transport.NegotiationStateRetry {
 		t.setNegotiationState(transport.NegotiationStateNone)
@@ -38,3 +38,30 @@ func (n NegotiationState) String() string {
 		return fmt.Sprintf("%d", int(n))
 	}
 }
+
+// -------------------------------------------------------
+
+// NegotiationCause is the reason a transport needs to negotiate
+type NegotiationCause int
+
+const (
+	NegotiationCauseSubscriptionChange NegotiationCause = iota
+	NegotiationCauseICERestart
+	NegotiationCauseMigration
+	NegotiationCauseForced
+)
+
+func (n NegotiationCause) String() string {
+	switch n {
+	case NegotiationCauseSubscriptionChange:
+		return "SUBSCRIPTION_CHANGE"
+	case NegotiationCauseICERestart:
+		return "ICE_RESTART"
+	case NegotiationCauseMigration:
+		return "MIGRATION"
+	case NegotiationCauseForced:
+		return "FORCED"
+	default:
+		return fmt.Sprintf("%d", int(n))
+	}
+}
@@ -22,6 +22,11 @@ type FakeHandler struct {
 	onAnswerReturnsOnCall map[int]struct {
 		result1 error
 	}
+	OnDataChannelBufferedAmountLowStub        func(livekit.DataPacket_Kind)
+	onDataChannelBufferedAmountLowMutex       sync.RWMutex
+	onDataChannelBufferedAmountLowArgsForCall []struct {
+		arg1 livekit.DataPacket_Kind
+	}
 	OnDataPacketStub        func(livekit.DataPacket_Kind, []byte)
 	onDataPacketMutex       sync.RWMutex
 	onDataPacketArgsForCall []struct {
@@ -155,6 +160,38 @@ func (fake *FakeHandler) OnAnswerReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeHandler) OnDataChannelBufferedAmountLow(arg1 livekit.DataPacket_Kind) {
+	fake.onDataChannelBufferedAmountLowMutex.Lock()
+	fake.onDataChannelBufferedAmountLowArgsForCall = append(fake.onDataChannelBufferedAmountLowArgsForCall, struct {
+		arg1 livekit.DataPacket_Kind
+	}{arg1})
+	stub := fake.OnDataChannelBufferedAmountLowStub
+	fake.recordInvocation("OnDataChannelBufferedAmountLow", []interface{}{arg1})
+	fake.onDataChannelBufferedAmountLowMutex.Unlock()
+	if stub != nil {
+		fake.OnDataChannelBufferedAmountLowStub(arg1)
+	}
+}
+
+func (fake *FakeHandler) OnDataChannelBufferedAmountLowCallCount() int {
+	fake.onDataChannelBufferedAmountLowMutex.RLock()
+	defer fake.onDataChannelBufferedAmountLowMutex.RUnlock()
+	return len(fake.onDataChannelBufferedAmountLowArgsForCall)
+}
+
+func (fake *FakeHandler) OnDataChannelBufferedAmountLowCalls(stub func(livekit.DataPacket_Kind)) {
+	fake.onDataChannelBufferedAmountLowMutex.Lock()
+	defer fake.onDataChannelBufferedAmountLowMutex.Unlock()
+	fake.OnDataChannelBufferedAmountLowStub = stub
+}
+
+func (fake *FakeHandler) OnDataChannelBufferedAmountLowArgsForCall(i int) livekit.DataPacket_Kind {
+	fake.onDataChannelBufferedAmountLowMutex.RLock()
+	defer fake.onDataChannelBufferedAmountLowMutex.RUnlock()
+	argsForCall := fake.onDataChannelBufferedAmountLowArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeHandler) OnDataPacket(arg1 livekit.DataPacket_Kind, arg2 []byte) {
 	var arg2Copy []byte
 	if arg2 != nil {
@@ -551,6 +588,8 @@ func (fake *FakeHandler) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.onAnswerMutex.RLock()
 	defer fake.onAnswerMutex.RUnlock()
+	fake.onDataChannelBufferedAmountLowMutex.RLock()
+	defer fake.onDataChannelBufferedAmountLowMutex.RUnlock()
 	fake.onDataPacketMutex.RLock()
 	defer fake.onDataPacketMutex.RUnlock()
 	fake.onFailedMutex.RLock()
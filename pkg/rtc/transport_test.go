@@ -383,6 +383,119 @@ func TestNegotiationFailed(t *testing.T) {
 	transportA.Close()
 }
 
+func TestDTLSState(t *testing.T) {
+	params := TransportParams{
+		ParticipantID:       "id",
+		ParticipantIdentity: "identity",
+		Config:              &WebRTCConfig{},
+		IsOfferer:           true,
+	}
+
+	paramsA := params
+	handlerA := &transportfakes.FakeHandler{}
+	paramsA.Handler = handlerA
+	transportA, err := NewPCTransport(paramsA)
+	require.NoError(t, err)
+	_, err = transportA.pc.CreateDataChannel(ReliableDataChannel, nil)
+	require.NoError(t, err)
+
+	paramsB := params
+	handlerB := &transportfakes.FakeHandler{}
+	paramsB.Handler = handlerB
+	paramsB.IsOfferer = false
+	transportB, err := NewPCTransport(paramsB)
+	require.NoError(t, err)
+
+	require.Equal(t, webrtc.DTLSTransportStateNew, transportA.GetDTLSState())
+	require.Equal(t, webrtc.DTLSTransportStateNew, transportB.GetDTLSState())
+
+	// exchange ICE
+	handleICEExchange(t, transportA, transportB, handlerA, handlerB)
+
+	connectTransports(t, transportA, transportB, handlerA, handlerB, false, 1, 1)
+	require.Eventually(t, func() bool {
+		return transportA.GetDTLSState() == webrtc.DTLSTransportStateConnected &&
+			transportB.GetDTLSState() == webrtc.DTLSTransportStateConnected
+	}, 10*time.Second, 10*time.Millisecond, "DTLS not connected")
+
+	transportA.Close()
+	transportB.Close()
+	require.Equal(t, webrtc.DTLSTransportStateClosed, transportA.GetDTLSState())
+}
+
+func TestPendingNegotiations(t *testing.T) {
+	params := TransportParams{
+		ParticipantID:       "id",
+		ParticipantIdentity: "identity",
+		Config:              &WebRTCConfig{},
+		IsOfferer:           true,
+	}
+
+	paramsA := params
+	handlerA := &transportfakes.FakeHandler{}
+	paramsA.Handler = handlerA
+	transportA, err := NewPCTransport(paramsA)
+	require.NoError(t, err)
+	_, err = transportA.pc.CreateDataChannel(ReliableDataChannel, nil)
+	require.NoError(t, err)
+
+	paramsB := params
+	handlerB := &transportfakes.FakeHandler{}
+	paramsB.Handler = handlerB
+	paramsB.IsOfferer = false
+	transportB, err := NewPCTransport(paramsB)
+	require.NoError(t, err)
+
+	// exchange ICE
+	handleICEExchange(t, transportA, transportB, handlerA, handlerB)
+
+	connectTransports(t, transportA, transportB, handlerA, handlerB, false, 1, 1)
+	require.Empty(t, transportA.GetPendingNegotiations())
+
+	// offer waiting for answer is not sent again when negotiation is pending for too long
+	var offerCount atomic.Int32
+	var firstOffer webrtc.SessionDescription
+	handlerA.OnOfferCalls(func(sd webrtc.SessionDescription) error {
+		if offerCount.Inc() == 1 {
+			firstOffer = sd
+		} else {
+			transportB.HandleRemoteDescription(sd)
+		}
+		return nil
+	})
+	transportA.NegotiateForCause(true, transport.NegotiationCauseSubscriptionChange)
+	require.Eventually(t, func() bool {
+		return offerCount.Load() == 1
+	}, 10*time.Second, 10*time.Millisecond, "transportA offer not sent")
+
+	pending := transportA.GetPendingNegotiations()
+	require.Len(t, pending, 1)
+	require.Equal(t, transport.NegotiationCauseSubscriptionChange, pending[0].Cause)
+	require.True(t, pending[0].InFlight)
+
+	// queued while waiting for answer, negotiated after the answer
+	transportA.NegotiateForCause(true, transport.NegotiationCauseSubscriptionChange)
+	require.Eventually(t, func() bool {
+		return len(transportA.GetPendingNegotiations()) == 2
+	}, 10*time.Second, 10*time.Millisecond, "negotiation not queued")
+
+	time.Sleep(negotiationPendingTimeout + time.Second)
+	require.Equal(t, int32(1), offerCount.Load())
+	for _, pn := range transportA.GetPendingNegotiations() {
+		require.False(t, pn.Retried)
+	}
+
+	// late delivery of the offer completes both negotiations
+	transportB.HandleRemoteDescription(firstOffer)
+	require.Eventually(t, func() bool {
+		return len(transportA.GetPendingNegotiations()) == 0
+	}, 10*time.Second, 10*time.Millisecond, "pending negotiations not completed")
+	require.Equal(t, int32(2), offerCount.Load())
+
+	transportA.Close()
+	transportB.Close()
+}
+
 func TestFilteringCandidates(t *testing.T) {
 	params := TransportParams{
 		ParticipantID:       "id",
@@ -57,6 +57,11 @@ func (h TransportManagerTransportHandler) OnFailed(isShortLived bool) {
 	h.Handler.OnFailed(isShortLived)
 }
 
+func (h TransportManagerTransportHandler) OnFullyEstablished() {
+	h.t.handleFullyEstablished()
+	h.Handler.OnFullyEstablished()
+}
+
 type TransportManagerPublisherTransportHandler struct {
 	TransportManagerTransportHandler
 }
@@ -84,7 +89,9 @@ type TransportManagerParams struct {
 	AllowUDPUnstableFallback     bool
 	TURNSEnabled                 bool
 	AllowPlayoutDelay            bool
+	AllowPlayoutDelayAudio       bool
 	DataChannelMaxBufferedAmount uint64
+	DataChannelLowBufferedAmount uint64
 	Logger                       logger.Logger
 	PublisherHandler             transport.Handler
 	SubscriberHandler            transport.Handler
@@ -108,6 +115,8 @@ type TransportManager struct {
 	lastPublisherAnswer          atomic.Value
 	lastPublisherOffer           atomic.Value
 	iceConfig                    *livekit.ICEConfig
+	// ICE config in effect when a transport was last fully established
+	lastGoodICEConfig *livekit.ICEConfig
 
 	mediaLossProxy       *MediaLossProxy
 	udpLossUnstableCount uint32
@@ -128,19 +137,20 @@ func NewTransportManager(params TransportManagerParams) (*TransportManager, erro
 	t.mediaLossProxy.OnMediaLossUpdate(t.onMediaLossUpdate)
 
 	publisher, err := NewPCTransport(TransportParams{
-		ParticipantID:           params.SID,
-		ParticipantIdentity:     params.Identity,
-		ProtocolVersion:         params.ProtocolVersion,
-		Config:                  params.Config,
-		Twcc:                    params.Twcc,
-		DirectionConfig:         params.Config.Publisher,
-		CongestionControlConfig: params.CongestionControlConfig,
-		EnabledCodecs:           params.EnabledPublishCodecs,
-		Logger:                  LoggerWithPCTarget(params.Logger, livekit.SignalTarget_PUBLISHER),
-		SimTracks:               params.SimTracks,
-		ClientInfo:              params.ClientInfo,
-		Transport:               livekit.SignalTarget_PUBLISHER,
-		Handler:                 TransportManagerPublisherTransportHandler{TransportManagerTransportHandler{params.PublisherHandler, t}},
+		ParticipantID:                params.SID,
+		ParticipantIdentity:          params.Identity,
+		ProtocolVersion:              params.ProtocolVersion,
+		Config:                       params.Config,
+		Twcc:                         params.Twcc,
+		DirectionConfig:              params.Config.Publisher,
+		CongestionControlConfig:      params.CongestionControlConfig,
+		EnabledCodecs:                params.EnabledPublishCodecs,
+		Logger:                       LoggerWithPCTarget(params.Logger, livekit.SignalTarget_PUBLISHER),
+		SimTracks:                    params.SimTracks,
+		ClientInfo:                   params.ClientInfo,
+		Transport:                    livekit.SignalTarget_PUBLISHER,
+		DataChannelLowBufferedAmount: params.DataChannelLowBufferedAmount,
+		Handler:                      TransportManagerPublisherTransportHandler{TransportManagerTransportHandler{params.PublisherHandler, t}},
 	})
 	if err != nil {
 		return nil, err
@@ -160,7 +170,9 @@ func NewTransportManager(params TransportManagerParams) (*TransportManager, erro
 		IsOfferer:                    true,
 		IsSendSide:                   true,
 		AllowPlayoutDelay:            params.AllowPlayoutDelay,
+		AllowPlayoutDelayAudio:       params.AllowPlayoutDelayAudio,
 		DataChannelMaxBufferedAmount: params.DataChannelMaxBufferedAmount,
+		DataChannelLowBufferedAmount: params.DataChannelLowBufferedAmount,
 		Transport:                    livekit.SignalTarget_SUBSCRIBER,
 		Handler:                      TransportManagerTransportHandler{params.SubscriberHandler, t},
 	})
@@ -195,6 +207,10 @@ func (t *TransportManager) IsPublisherEstablished() bool {
 	return t.publisher.IsEstablished()
 }
 
+func (t *TransportManager) IsPublisherConnected() bool {
+	return t.publisher.IsConnected()
+}
+
 func (t *TransportManager) GetPublisherMid(rtpReceiver *webrtc.RTPReceiver) string {
 	return t.publisher.GetMid(rtpReceiver)
 }
@@ -244,6 +260,10 @@ func (t *TransportManager) SendDataPacket(kind livekit.DataPacket_Kind, encoded
 	return t.getTransport(true).SendDataPacket(kind, encoded)
 }
 
+func (t *TransportManager) DataChannelBufferedAmount(kind livekit.DataPacket_Kind) uint64 {
+	return t.getTransport(true).DataChannelBufferedAmount(kind)
+}
+
 func (t *TransportManager) createDataChannelsForSubscriber(pendingDataChannels []*livekit.DataChannelInfo) error {
 	var (
 		reliableID, lossyID       uint16
@@ -345,6 +365,7 @@ func (t *TransportManager) HandleOffer(offer webrtc.SessionDescription, shouldPe
 	if shouldPend {
 		t.pendingOfferPublisher = &offer
 		t.lock.Unlock()
+		t.publisher.QueueNegotiation(transport.NegotiationCauseMigration)
 		return
 	}
 	t.lock.Unlock()
@@ -381,8 +402,8 @@ func (t *TransportManager) AddICECandidate(candidate webrtc.ICECandidateInit, ta
 	}
 }
 
-func (t *TransportManager) NegotiateSubscriber(force bool) {
-	t.subscriber.Negotiate(force)
+func (t *TransportManager) NegotiateSubscriber(force bool, cause transport.NegotiationCause) {
+	t.subscriber.NegotiateForCause(force, cause)
 }
 
 func (t *TransportManager) HandleClientReconnect(reason livekit.ReconnectReason) {
@@ -427,6 +448,25 @@ func (t *TransportManager) OnICEConfigChanged(f func(iceConfig *livekit.ICEConfi
 	t.lock.Unlock()
 }
 
+func (t *TransportManager) GetICEConfig() *livekit.ICEConfig {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return proto.Clone(t.iceConfig).(*livekit.ICEConfig)
+}
+
+// GetLastGoodICEConfig returns the ICE config in effect when a transport was last fully established,
+// nil if no transport has been fully established yet. It is not cleared by a failure or
+// a fallback to another candidate type, so a reconnect can start from it.
+func (t *TransportManager) GetLastGoodICEConfig() *livekit.ICEConfig {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.lastGoodICEConfig == nil {
+		return nil
+	}
+	return proto.Clone(t.lastGoodICEConfig).(*livekit.ICEConfig)
+}
+
 func (t *TransportManager) SetICEConfig(iceConfig *livekit.ICEConfig) {
 	if iceConfig != nil {
 		t.configureICE(iceConfig, true)
@@ -484,6 +524,28 @@ func (t *TransportManager) GetICEConnectionDetails() []*types.ICEConnectionDetai
 	return details
 }
 
+func (t *TransportManager) GetConnectionState(target livekit.SignalTarget) webrtc.PeerConnectionState {
+	if target == livekit.SignalTarget_SUBSCRIBER {
+		return t.subscriber.GetConnectionState()
+	}
+	return t.publisher.GetConnectionState()
+}
+
+func (t *TransportManager) GetDTLSState(target livekit.SignalTarget) webrtc.DTLSTransportState {
+	if target == livekit.SignalTarget_SUBSCRIBER {
+		return t.subscriber.GetDTLSState()
+	}
+	return t.publisher.GetDTLSState()
+}
+
+// GetPendingNegotiations returns causes of negotiation of a transport which have not completed yet
+func (t *TransportManager) GetPendingNegotiations(target livekit.SignalTarget) []PendingNegotiation {
+	if target == livekit.SignalTarget_SUBSCRIBER {
+		return t.subscriber.GetPendingNegotiations()
+	}
+	return t.publisher.GetPendingNegotiations()
+}
+
 func (t *TransportManager) getTransport(isPrimary bool) *PCTransport {
 	pcTransport := t.publisher
 	if (isPrimary && t.params.SubscriberAsPrimary) || (!isPrimary && !t.params.SubscriberAsPrimary) {
@@ -578,6 +640,18 @@ func (t *TransportManager) handleConnectionFailed(isShortLived bool) {
 	}, false)
 }
 
+func (t *TransportManager) handleFullyEstablished() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if proto.Equal(t.lastGoodICEConfig, t.iceConfig) {
+		return
+	}
+
+	t.params.Logger.Debugw("transport established, remembering ICE config", "iceConfig", t.iceConfig)
+	t.lastGoodICEConfig = proto.Clone(t.iceConfig).(*livekit.ICEConfig)
+}
+
 func (t *TransportManager) SetMigrateInfo(previousOffer, previousAnswer *webrtc.SessionDescription, dataChannels []*livekit.DataChannelInfo) {
 	t.lock.Lock()
 	t.pendingDataChannelsPublisher = make([]*livekit.DataChannelInfo, 0, len(dataChannels))
@@ -600,6 +674,23 @@ func (t *TransportManager) SetMigrateInfo(previousOffer, previousAnswer *webrtc.
 	t.subscriber.SetPreviousSdp(previousOffer, previousAnswer)
 }
 
+// GetMigrateInfo is the inverse of SetMigrateInfo, returns current subscriber offer/answer
+// and data channels of both transports so that state can be restored on another node
+func (t *TransportManager) GetMigrateInfo() (previousOffer, previousAnswer *webrtc.SessionDescription, dataChannels []*livekit.DataChannelInfo) {
+	// server is always the offerer on subscriber transport
+	previousOffer, previousAnswer = t.subscriber.GetCurrentSdp()
+
+	for _, dci := range t.publisher.GetDataChannelInfos() {
+		dci.Target = livekit.SignalTarget_PUBLISHER
+		dataChannels = append(dataChannels, dci)
+	}
+	for _, dci := range t.subscriber.GetDataChannelInfos() {
+		dci.Target = livekit.SignalTarget_SUBSCRIBER
+		dataChannels = append(dataChannels, dci)
+	}
+	return
+}
+
 func (t *TransportManager) ProcessPendingPublisherDataChannels() {
 	t.lock.Lock()
 	pendingDataChannels := t.pendingDataChannelsPublisher
@@ -691,6 +782,13 @@ func (t *TransportManager) UpdateMediaRTT(rtt uint32) {
 	t.lock.Unlock()
 }
 
+// GetRTT returns last signaling RTT and smoothed media RTT
+func (t *TransportManager) GetRTT() (signalingRTT uint32, mediaRTT uint32) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.signalingRTT, t.udpRTT
+}
+
 func (t *TransportManager) UpdateLastSeenSignal() {
 	t.lock.Lock()
 	t.lastSignalAt = time.Now()
@@ -726,6 +824,20 @@ func (t *TransportManager) SetSubscriberChannelCapacity(channelCapacity int64) {
 	t.subscriber.SetChannelCapacityOfStreamAllocator(channelCapacity)
 }
 
+func (t *TransportManager) SeedSubscriberChannelCapacity(channelCapacity int64) {
+	t.subscriber.SeedChannelCapacityOfStreamAllocator(channelCapacity)
+}
+
+func (t *TransportManager) GetSubscriberReceivedEstimate() int64 {
+	return t.subscriber.GetReceivedEstimateOfStreamAllocator()
+}
+
+// IsSubscriberCongested returns true when the subscriber stream allocator cannot
+// give all subscribed tracks their optimal allocation
+func (t *TransportManager) IsSubscriberCongested() bool {
+	return t.subscriber.IsStreamAllocatorDeficient()
+}
+
 func (t *TransportManager) hasRecentSignalLocked() bool {
 	return time.Since(t.lastSignalAt) < PingTimeoutSeconds*time.Second
 }
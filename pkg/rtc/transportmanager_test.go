@@ -0,0 +1,92 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/transport/transportfakes"
+)
+
+func newTransportManagerForTest(t *testing.T) (*TransportManager, TransportManagerTransportHandler) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	conf.RTC.TCPPort = 0
+	rtcConf, err := NewWebRTCConfig(conf)
+	require.NoError(t, err)
+
+	handler := &transportfakes.FakeHandler{}
+	tm, err := NewTransportManager(TransportManagerParams{
+		Identity:          "identity",
+		SID:               "id",
+		Config:            rtcConf,
+		AllowTCPFallback:  true,
+		TURNSEnabled:      true,
+		PublisherHandler:  handler,
+		SubscriberHandler: handler,
+	})
+	require.NoError(t, err)
+	t.Cleanup(tm.Close)
+
+	return tm, TransportManagerTransportHandler{handler, tm}
+}
+
+func TestLastGoodICEConfig(t *testing.T) {
+	t.Run("not established", func(t *testing.T) {
+		tm, h := newTransportManagerForTest(t)
+		require.Nil(t, tm.GetLastGoodICEConfig())
+
+		tm.UpdateLastSeenSignal()
+		h.OnFailed(true)
+		require.Nil(t, tm.GetLastGoodICEConfig())
+	})
+
+	t.Run("remembered across failure", func(t *testing.T) {
+		tm, h := newTransportManagerForTest(t)
+
+		tm.SetICEConfig(&livekit.ICEConfig{})
+		h.OnFullyEstablished()
+		established := &livekit.ICEConfig{}
+		require.True(t, proto.Equal(established, tm.GetLastGoodICEConfig()))
+
+		// short lived connection falls back to TURN/TLS, last good config is retained
+		tm.UpdateLastSeenSignal()
+		h.OnFailed(true)
+		require.Equal(t, livekit.ICECandidateType_ICT_TLS, tm.GetICEConfig().PreferenceSubscriber)
+		require.True(t, proto.Equal(established, tm.GetLastGoodICEConfig()))
+
+		// establishing with fallback config updates last good config
+		h.OnFullyEstablished()
+		require.Equal(t, livekit.ICECandidateType_ICT_TLS, tm.GetLastGoodICEConfig().PreferenceSubscriber)
+		require.Equal(t, livekit.ICECandidateType_ICT_TLS, tm.GetLastGoodICEConfig().PreferencePublisher)
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		tm, h := newTransportManagerForTest(t)
+
+		tm.SetICEConfig(&livekit.ICEConfig{PreferenceSubscriber: livekit.ICECandidateType_ICT_TCP})
+		h.OnFullyEstablished()
+
+		lastGood := tm.GetLastGoodICEConfig()
+		lastGood.PreferenceSubscriber = livekit.ICECandidateType_ICT_TLS
+		require.Equal(t, livekit.ICECandidateType_ICT_TCP, tm.GetLastGoodICEConfig().PreferenceSubscriber)
+	})
+}
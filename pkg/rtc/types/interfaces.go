@@ -26,6 +26,7 @@ import (
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
@@ -78,6 +79,87 @@ type SubscribedCodecQuality struct {
 
 // ---------------------------------------------
 
+// TrackVisibility is a client report of how a subscribed video track is rendered
+type TrackVisibility struct {
+	// fraction of the video tile visible in viewport, 0 means not visible, nil when not reported
+	VisibleFraction *float32
+	// rendered tile size
+	Width  uint32
+	Height uint32
+}
+
+// IsVisible returns false only if the track has been reported as not visible,
+// unknown visibility is treated as visible.
+func (v *TrackVisibility) IsVisible() bool {
+	return v == nil || v.VisibleFraction == nil || *v.VisibleFraction > 0
+}
+
+func (v *TrackVisibility) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	visibleFraction := "unknown"
+	if v.VisibleFraction != nil {
+		visibleFraction = fmt.Sprintf("%.2f", *v.VisibleFraction)
+	}
+	return fmt.Sprintf("TrackVisibility{visibleFraction: %s, size: %dx%d}", visibleFraction, v.Width, v.Height)
+}
+
+func (v *TrackVisibility) Equal(other *TrackVisibility) bool {
+	if v == nil || other == nil {
+		return v == other
+	}
+
+	if (v.VisibleFraction == nil) != (other.VisibleFraction == nil) ||
+		(v.VisibleFraction != nil && *v.VisibleFraction != *other.VisibleFraction) {
+		return false
+	}
+	return v.Width == other.Width && v.Height == other.Height
+}
+
+func (v *TrackVisibility) Clone() *TrackVisibility {
+	if v == nil {
+		return nil
+	}
+
+	clone := *v
+	if v.VisibleFraction != nil {
+		visibleFraction := *v.VisibleFraction
+		clone.VisibleFraction = &visibleFraction
+	}
+	return &clone
+}
+
+// ---------------------------------------------
+
+// TrackBitrate is the current bitrate of a single published or subscribed track
+type TrackBitrate struct {
+	Kind       livekit.TrackType
+	Subscribed bool
+	Bps        int64
+	// reason video is not being forwarded, always VideoPauseReasonNone for published and audio tracks
+	PauseReason sfu.VideoPauseReason
+}
+
+// BitrateSummary aggregates current bitrate of a participant in both directions
+type BitrateSummary struct {
+	PublishBps   int64
+	SubscribeBps int64
+	Tracks       map[livekit.TrackID]TrackBitrate
+}
+
+// SubscriberLossSummary aggregates loss reported by a subscriber in RTCP receiver reports of its down tracks,
+// fractions are in the range [0, 1]
+type SubscriberLossSummary struct {
+	// average across tracks with a recent report
+	FractionLost    float32
+	MaxFractionLost float32
+	Tracks          map[livekit.TrackID]float32
+}
+
+// ---------------------------------------------
+
 type ParticipantCloseReason int
 
 const (
@@ -104,6 +186,8 @@ const (
 	ParticipantCloseReasonDataChannelError
 	ParticipantCloseReasonMigrateCodecMismatch
 	ParticipantCloseReasonSignalSourceClose
+	ParticipantCloseReasonMigrateTooManyTracks
+	ParticipantCloseReasonPublishRateExceeded
 )
 
 func (p ParticipantCloseReason) String() string {
@@ -154,6 +238,10 @@ func (p ParticipantCloseReason) String() string {
 		return "MIGRATE_CODEC_MISMATCH"
 	case ParticipantCloseReasonSignalSourceClose:
 		return "SIGNAL_SOURCE_CLOSE"
+	case ParticipantCloseReasonMigrateTooManyTracks:
+		return "MIGRATE_TOO_MANY_TRACKS"
+	case ParticipantCloseReasonPublishRateExceeded:
+		return "PUBLISH_RATE_EXCEEDED"
 	default:
 		return fmt.Sprintf("%d", int(p))
 	}
@@ -180,7 +268,7 @@ func (p ParticipantCloseReason) ToDisconnectReason() livekit.DisconnectReason {
 		return livekit.DisconnectReason_ROOM_DELETED
 	case ParticipantCloseReasonSimulateNodeFailure, ParticipantCloseReasonSimulateServerLeave:
 		return livekit.DisconnectReason_SERVER_SHUTDOWN
-	case ParticipantCloseReasonNegotiateFailed, ParticipantCloseReasonPublicationError, ParticipantCloseReasonSubscriptionError, ParticipantCloseReasonDataChannelError, ParticipantCloseReasonMigrateCodecMismatch:
+	case ParticipantCloseReasonNegotiateFailed, ParticipantCloseReasonPublicationError, ParticipantCloseReasonSubscriptionError, ParticipantCloseReasonDataChannelError, ParticipantCloseReasonMigrateCodecMismatch, ParticipantCloseReasonMigrateTooManyTracks, ParticipantCloseReasonPublishRateExceeded:
 		return livekit.DisconnectReason_STATE_MISMATCH
 	case ParticipantCloseReasonSignalSourceClose:
 		return livekit.DisconnectReason_SIGNAL_CLOSE
@@ -239,6 +327,49 @@ func (s SignallingCloseReason) String() string {
 
 // ---------------------------------------------
 
+type AdminAction int
+
+const (
+	AdminActionMuteTrack AdminAction = iota
+	AdminActionUnmuteTrack
+	AdminActionUpdatePermission
+	AdminActionRemoveTrack
+	AdminActionRemoveParticipant
+)
+
+func (a AdminAction) String() string {
+	switch a {
+	case AdminActionMuteTrack:
+		return "MUTE_TRACK"
+	case AdminActionUnmuteTrack:
+		return "UNMUTE_TRACK"
+	case AdminActionUpdatePermission:
+		return "UPDATE_PERMISSION"
+	case AdminActionRemoveTrack:
+		return "REMOVE_TRACK"
+	case AdminActionRemoveParticipant:
+		return "REMOVE_PARTICIPANT"
+	default:
+		return fmt.Sprintf("%d", int(a))
+	}
+}
+
+// AdminActionOptions describes the origin of an action applied through the server API.
+// A nil value means the action did not originate from an admin.
+type AdminActionOptions struct {
+	// identity (or API key when identity is not available) of the requester
+	Actor string
+}
+
+type AdminAuditEntry struct {
+	At      time.Time
+	Action  AdminAction
+	TrackID livekit.TrackID
+	Actor   string
+}
+
+// ---------------------------------------------
+
 //counterfeiter:generate . Participant
 type Participant interface {
 	ID() livekit.ParticipantID
@@ -256,6 +387,8 @@ type Participant interface {
 	SetMetadata(metadata string)
 
 	IsPublisher() bool
+	// true if at least one published track is unmuted and receiving media
+	HasActiveMedia() bool
 	GetPublishedTrack(trackID livekit.TrackID) MediaTrack
 	GetPublishedTracks() []MediaTrack
 	RemovePublishedTrack(track MediaTrack, willBeResumed bool, shouldClose bool)
@@ -315,7 +448,12 @@ type LocalParticipant interface {
 	GetClientInfo() *livekit.ClientInfo
 	GetClientConfiguration() *livekit.ClientConfiguration
 	GetBufferFactory() *buffer.Factory
-	GetPlayoutDelayConfig() *livekit.PlayoutDelay
+	// playout delay of subscribed tracks from given source, nil if playout delay is not applied to the source
+	GetPlayoutDelayConfig(source livekit.TrackSource) *livekit.PlayoutDelay
+	GetAllocationPreference() sfu.AllocationPreference
+	// minimum interval between key frame requests on behalf of subscriber, 0 if not throttled
+	SetSubscriberPLIThrottle(min time.Duration)
+	GetSubscriberPLIThrottle() time.Duration
 	GetPendingTrack(trackID livekit.TrackID) *livekit.TrackInfo
 	GetICEConnectionDetails() []*ICEConnectionDetails
 	HasConnected() bool
@@ -328,7 +466,7 @@ type LocalParticipant interface {
 
 	// permissions
 	ClaimGrants() *auth.ClaimGrants
-	SetPermission(permission *livekit.ParticipantPermission) bool
+	SetPermission(permission *livekit.ParticipantPermission, adminOpts *AdminActionOptions) bool
 	CanPublishSource(source livekit.TrackSource) bool
 	CanSubscribe() bool
 	CanPublishData() bool
@@ -337,10 +475,17 @@ type LocalParticipant interface {
 	AddICECandidate(candidate webrtc.ICECandidateInit, target livekit.SignalTarget)
 	HandleOffer(sdp webrtc.SessionDescription)
 	AddTrack(req *livekit.AddTrackRequest)
-	SetTrackMuted(trackID livekit.TrackID, muted bool, fromAdmin bool) *livekit.TrackInfo
+	SetTrackMuted(trackID livekit.TrackID, muted bool, adminOpts *AdminActionOptions) *livekit.TrackInfo
+	SetAllTracksMuted(muted bool, adminOpts *AdminActionOptions) []*livekit.TrackInfo
+	PauseTrackForwarding(trackID livekit.TrackID) error
+	ResumeTrackForwarding(trackID livekit.TrackID) error
+	SetTrackPaused(trackID livekit.TrackID, paused bool) error
+	HandleTrackPauseRequest(trackID livekit.TrackID, paused bool)
+	SetMaxSpatialLayerForSource(source livekit.TrackSource, layer int32) map[livekit.TrackID]int32
 
 	HandleAnswer(sdp webrtc.SessionDescription)
 	Negotiate(force bool)
+	ForceSubscriberRenegotiation() error
 	ICERestart(iceConfig *livekit.ICEConfig)
 	AddTrackToSubscriber(trackLocal webrtc.TrackLocal, params AddTrackParams) (*webrtc.RTPSender, *webrtc.RTPTransceiver, error)
 	AddTransceiverFromTrackToSubscriber(trackLocal webrtc.TrackLocal, params AddTrackParams) (*webrtc.RTPSender, *webrtc.RTPTransceiver, error)
@@ -352,7 +497,13 @@ type LocalParticipant interface {
 	SubscribeToTrack(trackID livekit.TrackID)
 	UnsubscribeFromTrack(trackID livekit.TrackID)
 	UpdateSubscribedTrackSettings(trackID livekit.TrackID, settings *livekit.UpdateTrackSettings)
+	SetTrackVisibility(trackID livekit.TrackID, visibility *TrackVisibility)
+	SetTrackPlayoutDelay(trackID livekit.TrackID, delay *livekit.PlayoutDelay)
+	SetSubscriberAudioOnly(enabled bool)
+	HandleSubscriberAudioOnlyRequest(enabled bool)
 	GetSubscribedTracks() []SubscribedTrack
+	RequestKeyFrame(trackID livekit.TrackID) error
+	GetVideoAllocation(trackID livekit.TrackID) (*sfu.VideoAllocation, bool)
 	VerifySubscribeParticipantInfo(pID livekit.ParticipantID, version uint32)
 	// WaitUntilSubscribed waits until all subscriptions have been settled, or if the timeout
 	// has been reached. If the timeout expires, it will return an error.
@@ -361,65 +512,116 @@ type LocalParticipant interface {
 	// returns list of participant identities that the current participant is subscribed to
 	GetSubscribedParticipants() []livekit.ParticipantID
 	IsSubscribedTo(sid livekit.ParticipantID) bool
+	// tracks wanted by the participant which could not be subscribed to due to subscription limits
+	GetDeniedSubscriptions() []livekit.TrackID
+	// update maximum number of subscribed tracks per kind, 0 is unlimited
+	UpdateSubscriptionLimits(audio, video int32)
 
 	GetConnectionQuality() *livekit.ConnectionQualityInfo
+	GetBitrateSummary() *BitrateSummary
+	GetSubscriberReportedLoss() *SubscriberLossSummary
 
 	// server sent messages
 	SendJoinResponse(joinResponse *livekit.JoinResponse) error
 	SendParticipantUpdate(participants []*livekit.ParticipantInfo) error
 	SendSpeakerUpdate(speakers []*livekit.SpeakerInfo, force bool) error
 	SendDataPacket(kind livekit.DataPacket_Kind, encoded []byte) error
+	DataChannelBufferedAmount(kind livekit.DataPacket_Kind) uint64
+	IsDataChannelBackpressured(kind livekit.DataPacket_Kind) bool
+	// reliable data not acknowledged by the client when a full reconnect was issued, for the next session
+	UnacknowledgedReliableData() [][]byte
 	SendRoomUpdate(room *livekit.Room) error
 	SendConnectionQualityUpdate(update *livekit.ConnectionQualityUpdate) error
 	SubscriptionPermissionUpdate(publisherID livekit.ParticipantID, trackID livekit.TrackID, allowed bool)
 	SendRefreshToken(token string) error
 	HandleReconnectAndSendResponse(reconnectReason livekit.ReconnectReason, reconnectResponse *livekit.ReconnectResponse) error
 	IssueFullReconnect(reason ParticipantCloseReason)
+	GetReconnectCount() uint32
+	ResetReconnectCount()
+	SetCloseAdminOptions(adminOpts *AdminActionOptions)
+	GetAdminAuditLog() []AdminAuditEntry
 
 	// callbacks
 	OnStateChange(func(p LocalParticipant, state livekit.ParticipantInfo_State))
 	OnMigrateStateChange(func(p LocalParticipant, migrateState MigrateState))
+	// OnMigrationTimeout - migration did not complete in time, subscriber peer connection is about to be closed
+	OnMigrationTimeout(func(p LocalParticipant))
 	// OnTrackPublished - remote added a track
 	OnTrackPublished(func(LocalParticipant, MediaTrack))
 	// OnTrackUpdated - one of its publishedTracks changed in status
 	OnTrackUpdated(callback func(LocalParticipant, MediaTrack))
+	// OnTrackMuteChanged - mute state of one of its published or pending tracks flipped
+	OnTrackMuteChanged(callback func(LocalParticipant, MediaTrack, bool))
 	// OnTrackUnpublished - a track was unpublished
 	OnTrackUnpublished(callback func(LocalParticipant, MediaTrack))
 	// OnParticipantUpdate - metadata or permission is updated
 	OnParticipantUpdate(callback func(LocalParticipant))
 	OnDataPacket(callback func(LocalParticipant, livekit.DataPacket_Kind, *livekit.DataPacket))
+	OnDataChannelBufferedAmountLow(callback func(LocalParticipant, livekit.DataPacket_Kind))
+	OnAvailableLayersChanged(callback func(LocalParticipant, livekit.TrackID, []int32))
 	OnSubscribeStatusChanged(fn func(publisherID livekit.ParticipantID, subscribed bool))
 	OnClose(callback func(LocalParticipant))
 	OnClaimsChanged(callback func(LocalParticipant))
+	OnConnectionQualityChanged(callback func(LocalParticipant, *livekit.ConnectionQualityInfo))
 	OnTrafficLoad(callback func(trafficLoad *TrafficLoad))
 
 	HandleReceiverReport(dt *sfu.DownTrack, report *rtcp.ReceiverReport)
+	// key frame requested by subscriber of a subscribed track, used to detect media it cannot decode
+	HandleDecodeFeedback(trackID livekit.TrackID)
 
 	// session migration
 	MaybeStartMigration(force bool, onStart func()) bool
 	NotifyMigration()
 	SetMigrateState(s MigrateState)
 	MigrateState() MigrateState
-	SetMigrateInfo(previousOffer, previousAnswer *webrtc.SessionDescription, mediaTracks []*livekit.TrackPublishedResponse, dataChannels []*livekit.DataChannelInfo)
+	// manual levers for a stalled migration
+	AbortMigration(reason ParticipantCloseReason) error
+	ForceCompleteMigration() error
+	SetMigrateInfo(
+		previousOffer, previousAnswer *webrtc.SessionDescription,
+		mediaTracks []*livekit.TrackPublishedResponse,
+		dataChannels []*livekit.DataChannelInfo,
+		capabilities map[string]bool,
+	)
 
 	UpdateMediaRTT(rtt uint32)
+	// recent distinct media RTT samples, oldest first
+	GetRTTHistory() []RTTSample
 	UpdateSignalingRTT(rtt uint32)
+	// signaling round trip time in milliseconds, from join to first answer and as reported by client pings
+	GetSignalingRTT() uint32
 
 	CacheDownTrack(trackID livekit.TrackID, rtpTransceiver *webrtc.RTPTransceiver, downTrackState sfu.DownTrackState)
 	UncacheDownTrack(rtpTransceiver *webrtc.RTPTransceiver)
 	GetCachedDownTrack(trackID livekit.TrackID) (*webrtc.RTPTransceiver, sfu.DownTrackState)
+	GetResumedDownTrack(trackID livekit.TrackID) (sfu.DownTrackState, bool)
+	ClearResumedDownTrack(trackID livekit.TrackID)
+	// resume state in wire format, imported by the node participant migrates to when draining
+	MarshalResumeState() ([]byte, error)
 
 	SetICEConfig(iceConfig *livekit.ICEConfig)
+	// ICE config in effect when a transport was last fully established, nil if never established
+	GetLastGoodICEConfig() *livekit.ICEConfig
 	OnICEConfigChanged(callback func(participant LocalParticipant, iceConfig *livekit.ICEConfig))
 
 	UpdateSubscribedQuality(nodeID livekit.NodeID, trackID livekit.TrackID, maxQualities []SubscribedCodecQuality) error
 	UpdateMediaLoss(nodeID livekit.NodeID, trackID livekit.TrackID, fractionalLoss uint32) error
+	// per spatial layer packet distribution across temporal layers of a published track
+	GetTemporalLayerDistribution(trackID livekit.TrackID) ([]buffer.TemporalLayerDistribution, error)
 
 	// down stream bandwidth management
 	SetSubscriberAllowPause(allowPause bool)
 	SetSubscriberChannelCapacity(channelCapacity int64)
+	// seeds subscriber bandwidth estimation with a downlink estimate from the client, in bps
+	SetBandwidthHint(hint int64)
 
 	GetPacer() pacer.Pacer
+	// rate at which subscriber pacer is sending, in bps
+	GetPacerSendRate() int64
+	// custom subscriber pacing, queued packets are not dropped on change
+	SetPacerBurstLimit(bytes int)
+	SetPacerInterval(d time.Duration)
+	GetPacerSettings() PacerSettings
 
 	GetTrafficLoad() *TrafficLoad
 }
@@ -461,6 +663,9 @@ type MediaTrack interface {
 
 	IsMuted() bool
 	SetMuted(muted bool)
+	// paused track is not forwarded to subscribers, but stays published and unmuted
+	IsPaused() bool
+	SetPaused(paused bool)
 
 	IsSimulcast() bool
 
@@ -485,6 +690,8 @@ type MediaTrack interface {
 
 	// returns temporal layer that's appropriate for fps
 	GetTemporalLayerForSpatialFps(spatial int32, fps uint32, mime string) int32
+	// returns false when temporal layer cannot be selected for codec, subscriptions get the base layer
+	SupportsTemporalLayers(mime string) bool
 
 	Receivers() []sfu.TrackReceiver
 	ClearAllReceivers(willBeResumed bool)
@@ -503,8 +710,20 @@ type LocalMediaTrack interface {
 
 	GetConnectionScoreAndQuality() (float32, livekit.ConnectionQuality)
 	GetTrackStats() *livekit.RTPStats
+	// bitrate received over the last connection quality interval, in bps
+	GetBitrate() int64
 
 	SetRTT(rtt uint32)
+	SetPLIThrottleConfig(pliThrottleConfig config.PLIThrottleConfig)
+	// time of the last packet received from publisher, zero if none
+	LastPacketAt() time.Time
+
+	// moderated track is published but not forwarded to subscribers
+	SetModerated(moderated bool)
+	IsModerated() bool
+	// caps spatial layer forwarded to all subscribers, returns highest layer which can be forwarded
+	SetMaxSubscriberSpatialLayer(layer int32) int32
+	GetMaxSubscriberSpatialLayer() int32
 
 	NotifySubscriberNodeMaxQuality(nodeID livekit.NodeID, qualities []SubscribedCodecQuality)
 	NotifySubscriberNodeMediaLoss(nodeID livekit.NodeID, fractionalLoss uint8)
@@ -528,10 +747,21 @@ type SubscribedTrack interface {
 	RTPSender() *webrtc.RTPSender
 	IsMuted() bool
 	SetPublisherMuted(muted bool)
+	// pauses forwarding to this subscriber until cleared, irrespective of available bandwidth
+	SetModerated(moderated bool)
+	// holds a new subscription paused until subscriber sends settings for it
+	SetStartPaused(startPaused bool)
 	UpdateSubscriberSettings(settings *livekit.UpdateTrackSettings, isImmediate bool)
+	SetVisibility(visibility *TrackVisibility)
+	// overrides playout delay limits of the subscription, nil restores subscriber default
+	SetPlayoutDelay(delay *livekit.PlayoutDelay)
+	SetMaxBitrate(maxBitrate int64)
+	SetMaxSpatialLayerCap(layer int32)
 	// selects appropriate video layer according to subscriber preferences
 	UpdateVideoLayer()
 	NeedsNegotiation() bool
+	// takes a key frame request of subscriber, returns true once subscriber is not decoding media which is forwarded
+	UpdateDecodeFeedback() bool
 }
 
 type ChangeNotifier interface {
@@ -91,3 +91,54 @@ func (v ProtocolVersion) SupportsIdentityBasedReconnection() bool {
 func (v ProtocolVersion) SupportsRegionsInLeaveRequest() bool {
 	return v > 12
 }
+
+// SupportsSubscriberAudioOnly - client can request to receive only audio of subscribed tracks,
+// by sending track settings without track sids
+func (v ProtocolVersion) SupportsSubscriberAudioOnly() bool {
+	return v > 13
+}
+
+// SupportsTrackPause - client can pause a published track without muting it,
+// by sending video layers of the track without layers
+func (v ProtocolVersion) SupportsTrackPause() bool {
+	return v > 13
+}
+
+// SupportsDecodeFeedback - client is told with a codec unsupported subscription error when it keeps
+// requesting key frames of a subscribed track while key frames are forwarded
+func (v ProtocolVersion) SupportsDecodeFeedback() bool {
+	return v > 13
+}
+
+// keys of Capabilities which are carried over on migration
+const (
+	CapabilitySubscriberAsPrimary   = "SubscriberAsPrimary"
+	CapabilityUnpublish             = "Unpublish"
+	CapabilityConnectionQualityLost = "ConnectionQualityLost"
+)
+
+// Capabilities lists protocol features supported by the version, for diagnostics
+func (v ProtocolVersion) Capabilities() map[string]bool {
+	return map[string]bool{
+		"PackedStreamId":            v.SupportsPackedStreamId(),
+		"Protobuf":                  v.SupportsProtobuf(),
+		"HandlesDataPackets":        v.HandlesDataPackets(),
+		"SubscriberAsPrimary":       v.SubscriberAsPrimary(),
+		"SpeakerChanged":            v.SupportsSpeakerChanged(),
+		"TransceiverReuse":          v.SupportsTransceiverReuse(),
+		"ConnectionQuality":         v.SupportsConnectionQuality(),
+		"SessionMigrate":            v.SupportsSessionMigrate(),
+		"ICELite":                   v.SupportsICELite(),
+		"Unpublish":                 v.SupportsUnpublish(),
+		"FastStart":                 v.SupportFastStart(),
+		"HandlesDisconnectedUpdate": v.SupportHandlesDisconnectedUpdate(),
+		"SyncStreamID":              v.SupportSyncStreamID(),
+		"ConnectionQualityLost":     v.SupportsConnectionQualityLost(),
+		"AsyncRoomID":               v.SupportsAsyncRoomID(),
+		"IdentityBasedReconnection": v.SupportsIdentityBasedReconnection(),
+		"RegionsInLeaveRequest":     v.SupportsRegionsInLeaveRequest(),
+		"SubscriberAudioOnly":       v.SupportsSubscriberAudioOnly(),
+		"TrackPause":                v.SupportsTrackPause(),
+		"DecodeFeedback":            v.SupportsDecodeFeedback(),
+	}
+}
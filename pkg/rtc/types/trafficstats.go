@@ -36,8 +36,28 @@ type TrafficTypeStats struct {
 	TrafficStats *TrafficStats
 }
 
+// PacerSettings are subscriber pacing settings of a participant, zero values mean default pacing
+type PacerSettings struct {
+	Interval   time.Duration
+	BurstLimit int
+}
+
+func (p PacerSettings) IsCustom() bool {
+	return p.Interval != 0 || p.BurstLimit != 0
+}
+
+// RTTSample is a media round trip time measurement, in milliseconds
+type RTTSample struct {
+	At  time.Time
+	RTT uint32
+}
+
 type TrafficLoad struct {
 	TrafficTypeStats []*TrafficTypeStats
+	// bytes queued on data channels to participant at the time of report
+	DataChannelBufferedAmount uint64
+	// subscriber pacing at the time of report
+	PacerSettings PacerSettings
 }
 
 func RTPStatsDiffToTrafficStats(before, after *livekit.RTPStats) *TrafficStats {
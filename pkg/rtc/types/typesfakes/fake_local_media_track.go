@@ -3,7 +3,9 @@ package typesfakes
 
 import (
 	"sync"
+	"time"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/protocol/livekit"
@@ -60,6 +62,16 @@ type FakeLocalMediaTrack struct {
 		result1 float64
 		result2 bool
 	}
+	GetBitrateStub        func() int64
+	getBitrateMutex       sync.RWMutex
+	getBitrateArgsForCall []struct {
+	}
+	getBitrateReturns struct {
+		result1 int64
+	}
+	getBitrateReturnsOnCall map[int]struct {
+		result1 int64
+	}
 	GetConnectionScoreAndQualityStub        func() (float32, livekit.ConnectionQuality)
 	getConnectionScoreAndQualityMutex       sync.RWMutex
 	getConnectionScoreAndQualityArgsForCall []struct {
@@ -72,6 +84,16 @@ type FakeLocalMediaTrack struct {
 		result1 float32
 		result2 livekit.ConnectionQuality
 	}
+	GetMaxSubscriberSpatialLayerStub        func() int32
+	getMaxSubscriberSpatialLayerMutex       sync.RWMutex
+	getMaxSubscriberSpatialLayerArgsForCall []struct {
+	}
+	getMaxSubscriberSpatialLayerReturns struct {
+		result1 int32
+	}
+	getMaxSubscriberSpatialLayerReturnsOnCall map[int]struct {
+		result1 int32
+	}
 	GetNumSubscribersStub        func() int
 	getNumSubscribersMutex       sync.RWMutex
 	getNumSubscribersArgsForCall []struct {
@@ -148,6 +170,16 @@ type FakeLocalMediaTrack struct {
 	isEncryptedReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsModeratedStub        func() bool
+	isModeratedMutex       sync.RWMutex
+	isModeratedArgsForCall []struct {
+	}
+	isModeratedReturns struct {
+		result1 bool
+	}
+	isModeratedReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsMutedStub        func() bool
 	isMutedMutex       sync.RWMutex
 	isMutedArgsForCall []struct {
@@ -168,6 +200,16 @@ type FakeLocalMediaTrack struct {
 	isOpenReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsPausedStub        func() bool
+	isPausedMutex       sync.RWMutex
+	isPausedArgsForCall []struct {
+	}
+	isPausedReturns struct {
+		result1 bool
+	}
+	isPausedReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsSimulcastStub        func() bool
 	isSimulcastMutex       sync.RWMutex
 	isSimulcastArgsForCall []struct {
@@ -199,6 +241,16 @@ type FakeLocalMediaTrack struct {
 	kindReturnsOnCall map[int]struct {
 		result1 livekit.TrackType
 	}
+	LastPacketAtStub        func() time.Time
+	lastPacketAtMutex       sync.RWMutex
+	lastPacketAtArgsForCall []struct {
+	}
+	lastPacketAtReturns struct {
+		result1 time.Time
+	}
+	lastPacketAtReturnsOnCall map[int]struct {
+		result1 time.Time
+	}
 	NameStub        func() string
 	nameMutex       sync.RWMutex
 	nameArgsForCall []struct {
@@ -282,11 +334,37 @@ type FakeLocalMediaTrack struct {
 	revokeDisallowedSubscribersReturnsOnCall map[int]struct {
 		result1 []livekit.ParticipantIdentity
 	}
+	SetMaxSubscriberSpatialLayerStub        func(int32) int32
+	setMaxSubscriberSpatialLayerMutex       sync.RWMutex
+	setMaxSubscriberSpatialLayerArgsForCall []struct {
+		arg1 int32
+	}
+	setMaxSubscriberSpatialLayerReturns struct {
+		result1 int32
+	}
+	setMaxSubscriberSpatialLayerReturnsOnCall map[int]struct {
+		result1 int32
+	}
+	SetModeratedStub        func(bool)
+	setModeratedMutex       sync.RWMutex
+	setModeratedArgsForCall []struct {
+		arg1 bool
+	}
 	SetMutedStub        func(bool)
 	setMutedMutex       sync.RWMutex
 	setMutedArgsForCall []struct {
 		arg1 bool
 	}
+	SetPLIThrottleConfigStub        func(config.PLIThrottleConfig)
+	setPLIThrottleConfigMutex       sync.RWMutex
+	setPLIThrottleConfigArgsForCall []struct {
+		arg1 config.PLIThrottleConfig
+	}
+	SetPausedStub        func(bool)
+	setPausedMutex       sync.RWMutex
+	setPausedArgsForCall []struct {
+		arg1 bool
+	}
 	SetRTTStub        func(uint32)
 	setRTTMutex       sync.RWMutex
 	setRTTArgsForCall []struct {
@@ -322,6 +400,17 @@ type FakeLocalMediaTrack struct {
 	streamReturnsOnCall map[int]struct {
 		result1 string
 	}
+	SupportsTemporalLayersStub        func(string) bool
+	supportsTemporalLayersMutex       sync.RWMutex
+	supportsTemporalLayersArgsForCall []struct {
+		arg1 string
+	}
+	supportsTemporalLayersReturns struct {
+		result1 bool
+	}
+	supportsTemporalLayersReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ToProtoStub        func() *livekit.TrackInfo
 	toProtoMutex       sync.RWMutex
 	toProtoArgsForCall []struct {
@@ -620,6 +709,59 @@ func (fake *FakeLocalMediaTrack) GetAudioLevelReturnsOnCall(i int, result1 float
 	}{result1, result2}
 }
 
+func (fake *FakeLocalMediaTrack) GetBitrate() int64 {
+	fake.getBitrateMutex.Lock()
+	ret, specificReturn := fake.getBitrateReturnsOnCall[len(fake.getBitrateArgsForCall)]
+	fake.getBitrateArgsForCall = append(fake.getBitrateArgsForCall, struct {
+	}{})
+	stub := fake.GetBitrateStub
+	fakeReturns := fake.getBitrateReturns
+	fake.recordInvocation("GetBitrate", []interface{}{})
+	fake.getBitrateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) GetBitrateCallCount() int {
+	fake.getBitrateMutex.RLock()
+	defer fake.getBitrateMutex.RUnlock()
+	return len(fake.getBitrateArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) GetBitrateCalls(stub func() int64) {
+	fake.getBitrateMutex.Lock()
+	defer fake.getBitrateMutex.Unlock()
+	fake.GetBitrateStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) GetBitrateReturns(result1 int64) {
+	fake.getBitrateMutex.Lock()
+	defer fake.getBitrateMutex.Unlock()
+	fake.GetBitrateStub = nil
+	fake.getBitrateReturns = struct {
+		result1 int64
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) GetBitrateReturnsOnCall(i int, result1 int64) {
+	fake.getBitrateMutex.Lock()
+	defer fake.getBitrateMutex.Unlock()
+	fake.GetBitrateStub = nil
+	if fake.getBitrateReturnsOnCall == nil {
+		fake.getBitrateReturnsOnCall = make(map[int]struct {
+			result1 int64
+		})
+	}
+	fake.getBitrateReturnsOnCall[i] = struct {
+		result1 int64
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) GetConnectionScoreAndQuality() (float32, livekit.ConnectionQuality) {
 	fake.getConnectionScoreAndQualityMutex.Lock()
 	ret, specificReturn := fake.getConnectionScoreAndQualityReturnsOnCall[len(fake.getConnectionScoreAndQualityArgsForCall)]
@@ -676,6 +818,59 @@ func (fake *FakeLocalMediaTrack) GetConnectionScoreAndQualityReturnsOnCall(i int
 	}{result1, result2}
 }
 
+func (fake *FakeLocalMediaTrack) GetMaxSubscriberSpatialLayer() int32 {
+	fake.getMaxSubscriberSpatialLayerMutex.Lock()
+	ret, specificReturn := fake.getMaxSubscriberSpatialLayerReturnsOnCall[len(fake.getMaxSubscriberSpatialLayerArgsForCall)]
+	fake.getMaxSubscriberSpatialLayerArgsForCall = append(fake.getMaxSubscriberSpatialLayerArgsForCall, struct {
+	}{})
+	stub := fake.GetMaxSubscriberSpatialLayerStub
+	fakeReturns := fake.getMaxSubscriberSpatialLayerReturns
+	fake.recordInvocation("GetMaxSubscriberSpatialLayer", []interface{}{})
+	fake.getMaxSubscriberSpatialLayerMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) GetMaxSubscriberSpatialLayerCallCount() int {
+	fake.getMaxSubscriberSpatialLayerMutex.RLock()
+	defer fake.getMaxSubscriberSpatialLayerMutex.RUnlock()
+	return len(fake.getMaxSubscriberSpatialLayerArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) GetMaxSubscriberSpatialLayerCalls(stub func() int32) {
+	fake.getMaxSubscriberSpatialLayerMutex.Lock()
+	defer fake.getMaxSubscriberSpatialLayerMutex.Unlock()
+	fake.GetMaxSubscriberSpatialLayerStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) GetMaxSubscriberSpatialLayerReturns(result1 int32) {
+	fake.getMaxSubscriberSpatialLayerMutex.Lock()
+	defer fake.getMaxSubscriberSpatialLayerMutex.Unlock()
+	fake.GetMaxSubscriberSpatialLayerStub = nil
+	fake.getMaxSubscriberSpatialLayerReturns = struct {
+		result1 int32
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) GetMaxSubscriberSpatialLayerReturnsOnCall(i int, result1 int32) {
+	fake.getMaxSubscriberSpatialLayerMutex.Lock()
+	defer fake.getMaxSubscriberSpatialLayerMutex.Unlock()
+	fake.GetMaxSubscriberSpatialLayerStub = nil
+	if fake.getMaxSubscriberSpatialLayerReturnsOnCall == nil {
+		fake.getMaxSubscriberSpatialLayerReturnsOnCall = make(map[int]struct {
+			result1 int32
+		})
+	}
+	fake.getMaxSubscriberSpatialLayerReturnsOnCall[i] = struct {
+		result1 int32
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) GetNumSubscribers() int {
 	fake.getNumSubscribersMutex.Lock()
 	ret, specificReturn := fake.getNumSubscribersReturnsOnCall[len(fake.getNumSubscribersArgsForCall)]
@@ -1074,6 +1269,59 @@ func (fake *FakeLocalMediaTrack) IsEncryptedReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) IsModerated() bool {
+	fake.isModeratedMutex.Lock()
+	ret, specificReturn := fake.isModeratedReturnsOnCall[len(fake.isModeratedArgsForCall)]
+	fake.isModeratedArgsForCall = append(fake.isModeratedArgsForCall, struct {
+	}{})
+	stub := fake.IsModeratedStub
+	fakeReturns := fake.isModeratedReturns
+	fake.recordInvocation("IsModerated", []interface{}{})
+	fake.isModeratedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) IsModeratedCallCount() int {
+	fake.isModeratedMutex.RLock()
+	defer fake.isModeratedMutex.RUnlock()
+	return len(fake.isModeratedArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) IsModeratedCalls(stub func() bool) {
+	fake.isModeratedMutex.Lock()
+	defer fake.isModeratedMutex.Unlock()
+	fake.IsModeratedStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) IsModeratedReturns(result1 bool) {
+	fake.isModeratedMutex.Lock()
+	defer fake.isModeratedMutex.Unlock()
+	fake.IsModeratedStub = nil
+	fake.isModeratedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) IsModeratedReturnsOnCall(i int, result1 bool) {
+	fake.isModeratedMutex.Lock()
+	defer fake.isModeratedMutex.Unlock()
+	fake.IsModeratedStub = nil
+	if fake.isModeratedReturnsOnCall == nil {
+		fake.isModeratedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isModeratedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) IsMuted() bool {
 	fake.isMutedMutex.Lock()
 	ret, specificReturn := fake.isMutedReturnsOnCall[len(fake.isMutedArgsForCall)]
@@ -1180,6 +1428,59 @@ func (fake *FakeLocalMediaTrack) IsOpenReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) IsPaused() bool {
+	fake.isPausedMutex.Lock()
+	ret, specificReturn := fake.isPausedReturnsOnCall[len(fake.isPausedArgsForCall)]
+	fake.isPausedArgsForCall = append(fake.isPausedArgsForCall, struct {
+	}{})
+	stub := fake.IsPausedStub
+	fakeReturns := fake.isPausedReturns
+	fake.recordInvocation("IsPaused", []interface{}{})
+	fake.isPausedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) IsPausedCallCount() int {
+	fake.isPausedMutex.RLock()
+	defer fake.isPausedMutex.RUnlock()
+	return len(fake.isPausedArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) IsPausedCalls(stub func() bool) {
+	fake.isPausedMutex.Lock()
+	defer fake.isPausedMutex.Unlock()
+	fake.IsPausedStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) IsPausedReturns(result1 bool) {
+	fake.isPausedMutex.Lock()
+	defer fake.isPausedMutex.Unlock()
+	fake.IsPausedStub = nil
+	fake.isPausedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) IsPausedReturnsOnCall(i int, result1 bool) {
+	fake.isPausedMutex.Lock()
+	defer fake.isPausedMutex.Unlock()
+	fake.IsPausedStub = nil
+	if fake.isPausedReturnsOnCall == nil {
+		fake.isPausedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isPausedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) IsSimulcast() bool {
 	fake.isSimulcastMutex.Lock()
 	ret, specificReturn := fake.isSimulcastReturnsOnCall[len(fake.isSimulcastArgsForCall)]
@@ -1347,6 +1648,59 @@ func (fake *FakeLocalMediaTrack) KindReturnsOnCall(i int, result1 livekit.TrackT
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) LastPacketAt() time.Time {
+	fake.lastPacketAtMutex.Lock()
+	ret, specificReturn := fake.lastPacketAtReturnsOnCall[len(fake.lastPacketAtArgsForCall)]
+	fake.lastPacketAtArgsForCall = append(fake.lastPacketAtArgsForCall, struct {
+	}{})
+	stub := fake.LastPacketAtStub
+	fakeReturns := fake.lastPacketAtReturns
+	fake.recordInvocation("LastPacketAt", []interface{}{})
+	fake.lastPacketAtMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketAtCallCount() int {
+	fake.lastPacketAtMutex.RLock()
+	defer fake.lastPacketAtMutex.RUnlock()
+	return len(fake.lastPacketAtArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketAtCalls(stub func() time.Time) {
+	fake.lastPacketAtMutex.Lock()
+	defer fake.lastPacketAtMutex.Unlock()
+	fake.LastPacketAtStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketAtReturns(result1 time.Time) {
+	fake.lastPacketAtMutex.Lock()
+	defer fake.lastPacketAtMutex.Unlock()
+	fake.LastPacketAtStub = nil
+	fake.lastPacketAtReturns = struct {
+		result1 time.Time
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketAtReturnsOnCall(i int, result1 time.Time) {
+	fake.lastPacketAtMutex.Lock()
+	defer fake.lastPacketAtMutex.Unlock()
+	fake.LastPacketAtStub = nil
+	if fake.lastPacketAtReturnsOnCall == nil {
+		fake.lastPacketAtReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+		})
+	}
+	fake.lastPacketAtReturnsOnCall[i] = struct {
+		result1 time.Time
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) Name() string {
 	fake.nameMutex.Lock()
 	ret, specificReturn := fake.nameReturnsOnCall[len(fake.nameArgsForCall)]
@@ -1806,6 +2160,99 @@ func (fake *FakeLocalMediaTrack) RevokeDisallowedSubscribersReturnsOnCall(i int,
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) SetMaxSubscriberSpatialLayer(arg1 int32) int32 {
+	fake.setMaxSubscriberSpatialLayerMutex.Lock()
+	ret, specificReturn := fake.setMaxSubscriberSpatialLayerReturnsOnCall[len(fake.setMaxSubscriberSpatialLayerArgsForCall)]
+	fake.setMaxSubscriberSpatialLayerArgsForCall = append(fake.setMaxSubscriberSpatialLayerArgsForCall, struct {
+		arg1 int32
+	}{arg1})
+	stub := fake.SetMaxSubscriberSpatialLayerStub
+	fakeReturns := fake.setMaxSubscriberSpatialLayerReturns
+	fake.recordInvocation("SetMaxSubscriberSpatialLayer", []interface{}{arg1})
+	fake.setMaxSubscriberSpatialLayerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) SetMaxSubscriberSpatialLayerCallCount() int {
+	fake.setMaxSubscriberSpatialLayerMutex.RLock()
+	defer fake.setMaxSubscriberSpatialLayerMutex.RUnlock()
+	return len(fake.setMaxSubscriberSpatialLayerArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) SetMaxSubscriberSpatialLayerCalls(stub func(int32) int32) {
+	fake.setMaxSubscriberSpatialLayerMutex.Lock()
+	defer fake.setMaxSubscriberSpatialLayerMutex.Unlock()
+	fake.SetMaxSubscriberSpatialLayerStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) SetMaxSubscriberSpatialLayerArgsForCall(i int) int32 {
+	fake.setMaxSubscriberSpatialLayerMutex.RLock()
+	defer fake.setMaxSubscriberSpatialLayerMutex.RUnlock()
+	argsForCall := fake.setMaxSubscriberSpatialLayerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalMediaTrack) SetMaxSubscriberSpatialLayerReturns(result1 int32) {
+	fake.setMaxSubscriberSpatialLayerMutex.Lock()
+	defer fake.setMaxSubscriberSpatialLayerMutex.Unlock()
+	fake.SetMaxSubscriberSpatialLayerStub = nil
+	fake.setMaxSubscriberSpatialLayerReturns = struct {
+		result1 int32
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) SetMaxSubscriberSpatialLayerReturnsOnCall(i int, result1 int32) {
+	fake.setMaxSubscriberSpatialLayerMutex.Lock()
+	defer fake.setMaxSubscriberSpatialLayerMutex.Unlock()
+	fake.SetMaxSubscriberSpatialLayerStub = nil
+	if fake.setMaxSubscriberSpatialLayerReturnsOnCall == nil {
+		fake.setMaxSubscriberSpatialLayerReturnsOnCall = make(map[int]struct {
+			result1 int32
+		})
+	}
+	fake.setMaxSubscriberSpatialLayerReturnsOnCall[i] = struct {
+		result1 int32
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) SetModerated(arg1 bool) {
+	fake.setModeratedMutex.Lock()
+	fake.setModeratedArgsForCall = append(fake.setModeratedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetModeratedStub
+	fake.recordInvocation("SetModerated", []interface{}{arg1})
+	fake.setModeratedMutex.Unlock()
+	if stub != nil {
+		fake.SetModeratedStub(arg1)
+	}
+}
+
+func (fake *FakeLocalMediaTrack) SetModeratedCallCount() int {
+	fake.setModeratedMutex.RLock()
+	defer fake.setModeratedMutex.RUnlock()
+	return len(fake.setModeratedArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) SetModeratedCalls(stub func(bool)) {
+	fake.setModeratedMutex.Lock()
+	defer fake.setModeratedMutex.Unlock()
+	fake.SetModeratedStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) SetModeratedArgsForCall(i int) bool {
+	fake.setModeratedMutex.RLock()
+	defer fake.setModeratedMutex.RUnlock()
+	argsForCall := fake.setModeratedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalMediaTrack) SetMuted(arg1 bool) {
 	fake.setMutedMutex.Lock()
 	fake.setMutedArgsForCall = append(fake.setMutedArgsForCall, struct {
@@ -1838,6 +2285,70 @@ func (fake *FakeLocalMediaTrack) SetMutedArgsForCall(i int) bool {
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalMediaTrack) SetPLIThrottleConfig(arg1 config.PLIThrottleConfig) {
+	fake.setPLIThrottleConfigMutex.Lock()
+	fake.setPLIThrottleConfigArgsForCall = append(fake.setPLIThrottleConfigArgsForCall, struct {
+		arg1 config.PLIThrottleConfig
+	}{arg1})
+	stub := fake.SetPLIThrottleConfigStub
+	fake.recordInvocation("SetPLIThrottleConfig", []interface{}{arg1})
+	fake.setPLIThrottleConfigMutex.Unlock()
+	if stub != nil {
+		fake.SetPLIThrottleConfigStub(arg1)
+	}
+}
+
+func (fake *FakeLocalMediaTrack) SetPLIThrottleConfigCallCount() int {
+	fake.setPLIThrottleConfigMutex.RLock()
+	defer fake.setPLIThrottleConfigMutex.RUnlock()
+	return len(fake.setPLIThrottleConfigArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) SetPLIThrottleConfigCalls(stub func(config.PLIThrottleConfig)) {
+	fake.setPLIThrottleConfigMutex.Lock()
+	defer fake.setPLIThrottleConfigMutex.Unlock()
+	fake.SetPLIThrottleConfigStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) SetPLIThrottleConfigArgsForCall(i int) config.PLIThrottleConfig {
+	fake.setPLIThrottleConfigMutex.RLock()
+	defer fake.setPLIThrottleConfigMutex.RUnlock()
+	argsForCall := fake.setPLIThrottleConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalMediaTrack) SetPaused(arg1 bool) {
+	fake.setPausedMutex.Lock()
+	fake.setPausedArgsForCall = append(fake.setPausedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetPausedStub
+	fake.recordInvocation("SetPaused", []interface{}{arg1})
+	fake.setPausedMutex.Unlock()
+	if stub != nil {
+		fake.SetPausedStub(arg1)
+	}
+}
+
+func (fake *FakeLocalMediaTrack) SetPausedCallCount() int {
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
+	return len(fake.setPausedArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) SetPausedCalls(stub func(bool)) {
+	fake.setPausedMutex.Lock()
+	defer fake.setPausedMutex.Unlock()
+	fake.SetPausedStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) SetPausedArgsForCall(i int) bool {
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
+	argsForCall := fake.setPausedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalMediaTrack) SetRTT(arg1 uint32) {
 	fake.setRTTMutex.Lock()
 	fake.setRTTArgsForCall = append(fake.setRTTArgsForCall, struct {
@@ -2029,6 +2540,67 @@ func (fake *FakeLocalMediaTrack) StreamReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) SupportsTemporalLayers(arg1 string) bool {
+	fake.supportsTemporalLayersMutex.Lock()
+	ret, specificReturn := fake.supportsTemporalLayersReturnsOnCall[len(fake.supportsTemporalLayersArgsForCall)]
+	fake.supportsTemporalLayersArgsForCall = append(fake.supportsTemporalLayersArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SupportsTemporalLayersStub
+	fakeReturns := fake.supportsTemporalLayersReturns
+	fake.recordInvocation("SupportsTemporalLayers", []interface{}{arg1})
+	fake.supportsTemporalLayersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) SupportsTemporalLayersCallCount() int {
+	fake.supportsTemporalLayersMutex.RLock()
+	defer fake.supportsTemporalLayersMutex.RUnlock()
+	return len(fake.supportsTemporalLayersArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) SupportsTemporalLayersCalls(stub func(string) bool) {
+	fake.supportsTemporalLayersMutex.Lock()
+	defer fake.supportsTemporalLayersMutex.Unlock()
+	fake.SupportsTemporalLayersStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) SupportsTemporalLayersArgsForCall(i int) string {
+	fake.supportsTemporalLayersMutex.RLock()
+	defer fake.supportsTemporalLayersMutex.RUnlock()
+	argsForCall := fake.supportsTemporalLayersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalMediaTrack) SupportsTemporalLayersReturns(result1 bool) {
+	fake.supportsTemporalLayersMutex.Lock()
+	defer fake.supportsTemporalLayersMutex.Unlock()
+	fake.SupportsTemporalLayersStub = nil
+	fake.supportsTemporalLayersReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) SupportsTemporalLayersReturnsOnCall(i int, result1 bool) {
+	fake.supportsTemporalLayersMutex.Lock()
+	defer fake.supportsTemporalLayersMutex.Unlock()
+	fake.SupportsTemporalLayersStub = nil
+	if fake.supportsTemporalLayersReturnsOnCall == nil {
+		fake.supportsTemporalLayersReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.supportsTemporalLayersReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) ToProto() *livekit.TrackInfo {
 	fake.toProtoMutex.Lock()
 	ret, specificReturn := fake.toProtoReturnsOnCall[len(fake.toProtoArgsForCall)]
@@ -2193,8 +2765,12 @@ func (fake *FakeLocalMediaTrack) Invocations() map[string][][]interface{} {
 	defer fake.getAllSubscribersMutex.RUnlock()
 	fake.getAudioLevelMutex.RLock()
 	defer fake.getAudioLevelMutex.RUnlock()
+	fake.getBitrateMutex.RLock()
+	defer fake.getBitrateMutex.RUnlock()
 	fake.getConnectionScoreAndQualityMutex.RLock()
 	defer fake.getConnectionScoreAndQualityMutex.RUnlock()
+	fake.getMaxSubscriberSpatialLayerMutex.RLock()
+	defer fake.getMaxSubscriberSpatialLayerMutex.RUnlock()
 	fake.getNumSubscribersMutex.RLock()
 	defer fake.getNumSubscribersMutex.RUnlock()
 	fake.getQualityForDimensionMutex.RLock()
@@ -2209,16 +2785,22 @@ func (fake *FakeLocalMediaTrack) Invocations() map[string][][]interface{} {
 	defer fake.iDMutex.RUnlock()
 	fake.isEncryptedMutex.RLock()
 	defer fake.isEncryptedMutex.RUnlock()
+	fake.isModeratedMutex.RLock()
+	defer fake.isModeratedMutex.RUnlock()
 	fake.isMutedMutex.RLock()
 	defer fake.isMutedMutex.RUnlock()
 	fake.isOpenMutex.RLock()
 	defer fake.isOpenMutex.RUnlock()
+	fake.isPausedMutex.RLock()
+	defer fake.isPausedMutex.RUnlock()
 	fake.isSimulcastMutex.RLock()
 	defer fake.isSimulcastMutex.RUnlock()
 	fake.isSubscriberMutex.RLock()
 	defer fake.isSubscriberMutex.RUnlock()
 	fake.kindMutex.RLock()
 	defer fake.kindMutex.RUnlock()
+	fake.lastPacketAtMutex.RLock()
+	defer fake.lastPacketAtMutex.RUnlock()
 	fake.nameMutex.RLock()
 	defer fake.nameMutex.RUnlock()
 	fake.notifySubscriberNodeMaxQualityMutex.RLock()
@@ -2239,8 +2821,16 @@ func (fake *FakeLocalMediaTrack) Invocations() map[string][][]interface{} {
 	defer fake.restartMutex.RUnlock()
 	fake.revokeDisallowedSubscribersMutex.RLock()
 	defer fake.revokeDisallowedSubscribersMutex.RUnlock()
+	fake.setMaxSubscriberSpatialLayerMutex.RLock()
+	defer fake.setMaxSubscriberSpatialLayerMutex.RUnlock()
+	fake.setModeratedMutex.RLock()
+	defer fake.setModeratedMutex.RUnlock()
 	fake.setMutedMutex.RLock()
 	defer fake.setMutedMutex.RUnlock()
+	fake.setPLIThrottleConfigMutex.RLock()
+	defer fake.setPLIThrottleConfigMutex.RUnlock()
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
 	fake.setRTTMutex.RLock()
 	defer fake.setRTTMutex.RUnlock()
 	fake.signalCidMutex.RLock()
@@ -2249,6 +2839,8 @@ func (fake *FakeLocalMediaTrack) Invocations() map[string][][]interface{} {
 	defer fake.sourceMutex.RUnlock()
 	fake.streamMutex.RLock()
 	defer fake.streamMutex.RUnlock()
+	fake.supportsTemporalLayersMutex.RLock()
+	defer fake.supportsTemporalLayersMutex.RUnlock()
 	fake.toProtoMutex.RLock()
 	defer fake.toProtoMutex.RUnlock()
 	fake.updateAudioTrackMutex.RLock()
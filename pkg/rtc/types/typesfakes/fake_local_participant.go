@@ -19,6 +19,17 @@ import (
 )
 
 type FakeLocalParticipant struct {
+	AbortMigrationStub        func(types.ParticipantCloseReason) error
+	abortMigrationMutex       sync.RWMutex
+	abortMigrationArgsForCall []struct {
+		arg1 types.ParticipantCloseReason
+	}
+	abortMigrationReturns struct {
+		result1 error
+	}
+	abortMigrationReturnsOnCall map[int]struct {
+		result1 error
+	}
 	AddICECandidateStub        func(webrtc.ICECandidateInit, livekit.SignalTarget)
 	addICECandidateMutex       sync.RWMutex
 	addICECandidateArgsForCall []struct {
@@ -120,6 +131,11 @@ type FakeLocalParticipant struct {
 	claimGrantsReturnsOnCall map[int]struct {
 		result1 *auth.ClaimGrants
 	}
+	ClearResumedDownTrackStub        func(livekit.TrackID)
+	clearResumedDownTrackMutex       sync.RWMutex
+	clearResumedDownTrackArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
 	CloseStub        func(bool, types.ParticipantCloseReason, bool) error
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
@@ -158,6 +174,17 @@ type FakeLocalParticipant struct {
 	connectedAtReturnsOnCall map[int]struct {
 		result1 time.Time
 	}
+	DataChannelBufferedAmountStub        func(livekit.DataPacket_Kind) uint64
+	dataChannelBufferedAmountMutex       sync.RWMutex
+	dataChannelBufferedAmountArgsForCall []struct {
+		arg1 livekit.DataPacket_Kind
+	}
+	dataChannelBufferedAmountReturns struct {
+		result1 uint64
+	}
+	dataChannelBufferedAmountReturnsOnCall map[int]struct {
+		result1 uint64
+	}
 	DebugInfoStub        func() map[string]interface{}
 	debugInfoMutex       sync.RWMutex
 	debugInfoArgsForCall []struct {
@@ -178,6 +205,26 @@ type FakeLocalParticipant struct {
 	disconnectedReturnsOnCall map[int]struct {
 		result1 <-chan struct{}
 	}
+	ForceCompleteMigrationStub        func() error
+	forceCompleteMigrationMutex       sync.RWMutex
+	forceCompleteMigrationArgsForCall []struct {
+	}
+	forceCompleteMigrationReturns struct {
+		result1 error
+	}
+	forceCompleteMigrationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ForceSubscriberRenegotiationStub        func() error
+	forceSubscriberRenegotiationMutex       sync.RWMutex
+	forceSubscriberRenegotiationArgsForCall []struct {
+	}
+	forceSubscriberRenegotiationReturns struct {
+		result1 error
+	}
+	forceSubscriberRenegotiationReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetAdaptiveStreamStub        func() bool
 	getAdaptiveStreamMutex       sync.RWMutex
 	getAdaptiveStreamArgsForCall []struct {
@@ -188,6 +235,26 @@ type FakeLocalParticipant struct {
 	getAdaptiveStreamReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	GetAdminAuditLogStub        func() []types.AdminAuditEntry
+	getAdminAuditLogMutex       sync.RWMutex
+	getAdminAuditLogArgsForCall []struct {
+	}
+	getAdminAuditLogReturns struct {
+		result1 []types.AdminAuditEntry
+	}
+	getAdminAuditLogReturnsOnCall map[int]struct {
+		result1 []types.AdminAuditEntry
+	}
+	GetAllocationPreferenceStub        func() sfu.AllocationPreference
+	getAllocationPreferenceMutex       sync.RWMutex
+	getAllocationPreferenceArgsForCall []struct {
+	}
+	getAllocationPreferenceReturns struct {
+		result1 sfu.AllocationPreference
+	}
+	getAllocationPreferenceReturnsOnCall map[int]struct {
+		result1 sfu.AllocationPreference
+	}
 	GetAudioLevelStub        func() (float64, bool)
 	getAudioLevelMutex       sync.RWMutex
 	getAudioLevelArgsForCall []struct {
@@ -200,6 +267,16 @@ type FakeLocalParticipant struct {
 		result1 float64
 		result2 bool
 	}
+	GetBitrateSummaryStub        func() *types.BitrateSummary
+	getBitrateSummaryMutex       sync.RWMutex
+	getBitrateSummaryArgsForCall []struct {
+	}
+	getBitrateSummaryReturns struct {
+		result1 *types.BitrateSummary
+	}
+	getBitrateSummaryReturnsOnCall map[int]struct {
+		result1 *types.BitrateSummary
+	}
 	GetBufferFactoryStub        func() *buffer.Factory
 	getBufferFactoryMutex       sync.RWMutex
 	getBufferFactoryArgsForCall []struct {
@@ -253,6 +330,16 @@ type FakeLocalParticipant struct {
 	getConnectionQualityReturnsOnCall map[int]struct {
 		result1 *livekit.ConnectionQualityInfo
 	}
+	GetDeniedSubscriptionsStub        func() []livekit.TrackID
+	getDeniedSubscriptionsMutex       sync.RWMutex
+	getDeniedSubscriptionsArgsForCall []struct {
+	}
+	getDeniedSubscriptionsReturns struct {
+		result1 []livekit.TrackID
+	}
+	getDeniedSubscriptionsReturnsOnCall map[int]struct {
+		result1 []livekit.TrackID
+	}
 	GetICEConnectionDetailsStub        func() []*types.ICEConnectionDetails
 	getICEConnectionDetailsMutex       sync.RWMutex
 	getICEConnectionDetailsArgsForCall []struct {
@@ -263,6 +350,16 @@ type FakeLocalParticipant struct {
 	getICEConnectionDetailsReturnsOnCall map[int]struct {
 		result1 []*types.ICEConnectionDetails
 	}
+	GetLastGoodICEConfigStub        func() *livekit.ICEConfig
+	getLastGoodICEConfigMutex       sync.RWMutex
+	getLastGoodICEConfigArgsForCall []struct {
+	}
+	getLastGoodICEConfigReturns struct {
+		result1 *livekit.ICEConfig
+	}
+	getLastGoodICEConfigReturnsOnCall map[int]struct {
+		result1 *livekit.ICEConfig
+	}
 	GetLoggerStub        func() logger.Logger
 	getLoggerMutex       sync.RWMutex
 	getLoggerArgsForCall []struct {
@@ -283,6 +380,26 @@ type FakeLocalParticipant struct {
 	getPacerReturnsOnCall map[int]struct {
 		result1 pacer.Pacer
 	}
+	GetPacerSendRateStub        func() int64
+	getPacerSendRateMutex       sync.RWMutex
+	getPacerSendRateArgsForCall []struct {
+	}
+	getPacerSendRateReturns struct {
+		result1 int64
+	}
+	getPacerSendRateReturnsOnCall map[int]struct {
+		result1 int64
+	}
+	GetPacerSettingsStub        func() types.PacerSettings
+	getPacerSettingsMutex       sync.RWMutex
+	getPacerSettingsArgsForCall []struct {
+	}
+	getPacerSettingsReturns struct {
+		result1 types.PacerSettings
+	}
+	getPacerSettingsReturnsOnCall map[int]struct {
+		result1 types.PacerSettings
+	}
 	GetPendingTrackStub        func(livekit.TrackID) *livekit.TrackInfo
 	getPendingTrackMutex       sync.RWMutex
 	getPendingTrackArgsForCall []struct {
@@ -294,9 +411,10 @@ type FakeLocalParticipant struct {
 	getPendingTrackReturnsOnCall map[int]struct {
 		result1 *livekit.TrackInfo
 	}
-	GetPlayoutDelayConfigStub        func() *livekit.PlayoutDelay
+	GetPlayoutDelayConfigStub        func(livekit.TrackSource) *livekit.PlayoutDelay
 	getPlayoutDelayConfigMutex       sync.RWMutex
 	getPlayoutDelayConfigArgsForCall []struct {
+		arg1 livekit.TrackSource
 	}
 	getPlayoutDelayConfigReturns struct {
 		result1 *livekit.PlayoutDelay
@@ -325,6 +443,49 @@ type FakeLocalParticipant struct {
 	getPublishedTracksReturnsOnCall map[int]struct {
 		result1 []types.MediaTrack
 	}
+	GetRTTHistoryStub        func() []types.RTTSample
+	getRTTHistoryMutex       sync.RWMutex
+	getRTTHistoryArgsForCall []struct {
+	}
+	getRTTHistoryReturns struct {
+		result1 []types.RTTSample
+	}
+	getRTTHistoryReturnsOnCall map[int]struct {
+		result1 []types.RTTSample
+	}
+	GetReconnectCountStub        func() uint32
+	getReconnectCountMutex       sync.RWMutex
+	getReconnectCountArgsForCall []struct {
+	}
+	getReconnectCountReturns struct {
+		result1 uint32
+	}
+	getReconnectCountReturnsOnCall map[int]struct {
+		result1 uint32
+	}
+	GetResumedDownTrackStub        func(livekit.TrackID) (sfu.DownTrackState, bool)
+	getResumedDownTrackMutex       sync.RWMutex
+	getResumedDownTrackArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	getResumedDownTrackReturns struct {
+		result1 sfu.DownTrackState
+		result2 bool
+	}
+	getResumedDownTrackReturnsOnCall map[int]struct {
+		result1 sfu.DownTrackState
+		result2 bool
+	}
+	GetSignalingRTTStub        func() uint32
+	getSignalingRTTMutex       sync.RWMutex
+	getSignalingRTTArgsForCall []struct {
+	}
+	getSignalingRTTReturns struct {
+		result1 uint32
+	}
+	getSignalingRTTReturnsOnCall map[int]struct {
+		result1 uint32
+	}
 	GetSubscribedParticipantsStub        func() []livekit.ParticipantID
 	getSubscribedParticipantsMutex       sync.RWMutex
 	getSubscribedParticipantsArgsForCall []struct {
@@ -345,6 +506,39 @@ type FakeLocalParticipant struct {
 	getSubscribedTracksReturnsOnCall map[int]struct {
 		result1 []types.SubscribedTrack
 	}
+	GetSubscriberPLIThrottleStub        func() time.Duration
+	getSubscriberPLIThrottleMutex       sync.RWMutex
+	getSubscriberPLIThrottleArgsForCall []struct {
+	}
+	getSubscriberPLIThrottleReturns struct {
+		result1 time.Duration
+	}
+	getSubscriberPLIThrottleReturnsOnCall map[int]struct {
+		result1 time.Duration
+	}
+	GetSubscriberReportedLossStub        func() *types.SubscriberLossSummary
+	getSubscriberReportedLossMutex       sync.RWMutex
+	getSubscriberReportedLossArgsForCall []struct {
+	}
+	getSubscriberReportedLossReturns struct {
+		result1 *types.SubscriberLossSummary
+	}
+	getSubscriberReportedLossReturnsOnCall map[int]struct {
+		result1 *types.SubscriberLossSummary
+	}
+	GetTemporalLayerDistributionStub        func(livekit.TrackID) ([]buffer.TemporalLayerDistribution, error)
+	getTemporalLayerDistributionMutex       sync.RWMutex
+	getTemporalLayerDistributionArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	getTemporalLayerDistributionReturns struct {
+		result1 []buffer.TemporalLayerDistribution
+		result2 error
+	}
+	getTemporalLayerDistributionReturnsOnCall map[int]struct {
+		result1 []buffer.TemporalLayerDistribution
+		result2 error
+	}
 	GetTrafficLoadStub        func() *types.TrafficLoad
 	getTrafficLoadMutex       sync.RWMutex
 	getTrafficLoadArgsForCall []struct {
@@ -365,11 +559,29 @@ type FakeLocalParticipant struct {
 	getTrailerReturnsOnCall map[int]struct {
 		result1 []byte
 	}
+	GetVideoAllocationStub        func(livekit.TrackID) (*sfu.VideoAllocation, bool)
+	getVideoAllocationMutex       sync.RWMutex
+	getVideoAllocationArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	getVideoAllocationReturns struct {
+		result1 *sfu.VideoAllocation
+		result2 bool
+	}
+	getVideoAllocationReturnsOnCall map[int]struct {
+		result1 *sfu.VideoAllocation
+		result2 bool
+	}
 	HandleAnswerStub        func(webrtc.SessionDescription)
 	handleAnswerMutex       sync.RWMutex
 	handleAnswerArgsForCall []struct {
 		arg1 webrtc.SessionDescription
 	}
+	HandleDecodeFeedbackStub        func(livekit.TrackID)
+	handleDecodeFeedbackMutex       sync.RWMutex
+	handleDecodeFeedbackArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
 	HandleOfferStub        func(webrtc.SessionDescription)
 	handleOfferMutex       sync.RWMutex
 	handleOfferArgsForCall []struct {
@@ -397,6 +609,27 @@ type FakeLocalParticipant struct {
 	handleSignalSourceCloseMutex       sync.RWMutex
 	handleSignalSourceCloseArgsForCall []struct {
 	}
+	HandleSubscriberAudioOnlyRequestStub        func(bool)
+	handleSubscriberAudioOnlyRequestMutex       sync.RWMutex
+	handleSubscriberAudioOnlyRequestArgsForCall []struct {
+		arg1 bool
+	}
+	HandleTrackPauseRequestStub        func(livekit.TrackID, bool)
+	handleTrackPauseRequestMutex       sync.RWMutex
+	handleTrackPauseRequestArgsForCall []struct {
+		arg1 livekit.TrackID
+		arg2 bool
+	}
+	HasActiveMediaStub        func() bool
+	hasActiveMediaMutex       sync.RWMutex
+	hasActiveMediaArgsForCall []struct {
+	}
+	hasActiveMediaReturns struct {
+		result1 bool
+	}
+	hasActiveMediaReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	HasConnectedStub        func() bool
 	hasConnectedMutex       sync.RWMutex
 	hasConnectedArgsForCall []struct {
@@ -464,6 +697,17 @@ type FakeLocalParticipant struct {
 	isClosedReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsDataChannelBackpressuredStub        func(livekit.DataPacket_Kind) bool
+	isDataChannelBackpressuredMutex       sync.RWMutex
+	isDataChannelBackpressuredArgsForCall []struct {
+		arg1 livekit.DataPacket_Kind
+	}
+	isDataChannelBackpressuredReturns struct {
+		result1 bool
+	}
+	isDataChannelBackpressuredReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsDependentStub        func() bool
 	isDependentMutex       sync.RWMutex
 	isDependentArgsForCall []struct {
@@ -550,6 +794,18 @@ type FakeLocalParticipant struct {
 	kindReturnsOnCall map[int]struct {
 		result1 livekit.ParticipantInfo_Kind
 	}
+	MarshalResumeStateStub        func() ([]byte, error)
+	marshalResumeStateMutex       sync.RWMutex
+	marshalResumeStateArgsForCall []struct {
+	}
+	marshalResumeStateReturns struct {
+		result1 []byte
+		result2 error
+	}
+	marshalResumeStateReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	MaybeStartMigrationStub        func(bool, func()) bool
 	maybeStartMigrationMutex       sync.RWMutex
 	maybeStartMigrationArgsForCall []struct {
@@ -581,6 +837,11 @@ type FakeLocalParticipant struct {
 	notifyMigrationMutex       sync.RWMutex
 	notifyMigrationArgsForCall []struct {
 	}
+	OnAvailableLayersChangedStub        func(func(types.LocalParticipant, livekit.TrackID, []int32))
+	onAvailableLayersChangedMutex       sync.RWMutex
+	onAvailableLayersChangedArgsForCall []struct {
+		arg1 func(types.LocalParticipant, livekit.TrackID, []int32)
+	}
 	OnClaimsChangedStub        func(func(types.LocalParticipant))
 	onClaimsChangedMutex       sync.RWMutex
 	onClaimsChangedArgsForCall []struct {
@@ -591,6 +852,16 @@ type FakeLocalParticipant struct {
 	onCloseArgsForCall []struct {
 		arg1 func(types.LocalParticipant)
 	}
+	OnConnectionQualityChangedStub        func(func(types.LocalParticipant, *livekit.ConnectionQualityInfo))
+	onConnectionQualityChangedMutex       sync.RWMutex
+	onConnectionQualityChangedArgsForCall []struct {
+		arg1 func(types.LocalParticipant, *livekit.ConnectionQualityInfo)
+	}
+	OnDataChannelBufferedAmountLowStub        func(func(types.LocalParticipant, livekit.DataPacket_Kind))
+	onDataChannelBufferedAmountLowMutex       sync.RWMutex
+	onDataChannelBufferedAmountLowArgsForCall []struct {
+		arg1 func(types.LocalParticipant, livekit.DataPacket_Kind)
+	}
 	OnDataPacketStub        func(func(types.LocalParticipant, livekit.DataPacket_Kind, *livekit.DataPacket))
 	onDataPacketMutex       sync.RWMutex
 	onDataPacketArgsForCall []struct {
@@ -606,6 +877,11 @@ type FakeLocalParticipant struct {
 	onMigrateStateChangeArgsForCall []struct {
 		arg1 func(p types.LocalParticipant, migrateState types.MigrateState)
 	}
+	OnMigrationTimeoutStub        func(func(p types.LocalParticipant))
+	onMigrationTimeoutMutex       sync.RWMutex
+	onMigrationTimeoutArgsForCall []struct {
+		arg1 func(p types.LocalParticipant)
+	}
 	OnParticipantUpdateStub        func(func(types.LocalParticipant))
 	onParticipantUpdateMutex       sync.RWMutex
 	onParticipantUpdateArgsForCall []struct {
@@ -621,6 +897,11 @@ type FakeLocalParticipant struct {
 	onSubscribeStatusChangedArgsForCall []struct {
 		arg1 func(publisherID livekit.ParticipantID, subscribed bool)
 	}
+	OnTrackMuteChangedStub        func(func(types.LocalParticipant, types.MediaTrack, bool))
+	onTrackMuteChangedMutex       sync.RWMutex
+	onTrackMuteChangedArgsForCall []struct {
+		arg1 func(types.LocalParticipant, types.MediaTrack, bool)
+	}
 	OnTrackPublishedStub        func(func(types.LocalParticipant, types.MediaTrack))
 	onTrackPublishedMutex       sync.RWMutex
 	onTrackPublishedArgsForCall []struct {
@@ -641,6 +922,17 @@ type FakeLocalParticipant struct {
 	onTrafficLoadArgsForCall []struct {
 		arg1 func(trafficLoad *types.TrafficLoad)
 	}
+	PauseTrackForwardingStub        func(livekit.TrackID) error
+	pauseTrackForwardingMutex       sync.RWMutex
+	pauseTrackForwardingArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	pauseTrackForwardingReturns struct {
+		result1 error
+	}
+	pauseTrackForwardingReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ProtocolVersionStub        func() types.ProtocolVersion
 	protocolVersionMutex       sync.RWMutex
 	protocolVersionArgsForCall []struct {
@@ -669,6 +961,32 @@ type FakeLocalParticipant struct {
 	removeTrackFromSubscriberReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RequestKeyFrameStub        func(livekit.TrackID) error
+	requestKeyFrameMutex       sync.RWMutex
+	requestKeyFrameArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	requestKeyFrameReturns struct {
+		result1 error
+	}
+	requestKeyFrameReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResetReconnectCountStub        func()
+	resetReconnectCountMutex       sync.RWMutex
+	resetReconnectCountArgsForCall []struct {
+	}
+	ResumeTrackForwardingStub        func(livekit.TrackID) error
+	resumeTrackForwardingMutex       sync.RWMutex
+	resumeTrackForwardingArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	resumeTrackForwardingReturns struct {
+		result1 error
+	}
+	resumeTrackForwardingReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SendConnectionQualityUpdateStub        func(*livekit.ConnectionQualityUpdate) error
 	sendConnectionQualityUpdateMutex       sync.RWMutex
 	sendConnectionQualityUpdateArgsForCall []struct {
@@ -748,23 +1066,58 @@ type FakeLocalParticipant struct {
 	sendSpeakerUpdateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetAllTracksMutedStub        func(bool, *types.AdminActionOptions) []*livekit.TrackInfo
+	setAllTracksMutedMutex       sync.RWMutex
+	setAllTracksMutedArgsForCall []struct {
+		arg1 bool
+		arg2 *types.AdminActionOptions
+	}
+	setAllTracksMutedReturns struct {
+		result1 []*livekit.TrackInfo
+	}
+	setAllTracksMutedReturnsOnCall map[int]struct {
+		result1 []*livekit.TrackInfo
+	}
+	SetBandwidthHintStub        func(int64)
+	setBandwidthHintMutex       sync.RWMutex
+	setBandwidthHintArgsForCall []struct {
+		arg1 int64
+	}
+	SetCloseAdminOptionsStub        func(*types.AdminActionOptions)
+	setCloseAdminOptionsMutex       sync.RWMutex
+	setCloseAdminOptionsArgsForCall []struct {
+		arg1 *types.AdminActionOptions
+	}
 	SetICEConfigStub        func(*livekit.ICEConfig)
 	setICEConfigMutex       sync.RWMutex
 	setICEConfigArgsForCall []struct {
 		arg1 *livekit.ICEConfig
 	}
+	SetMaxSpatialLayerForSourceStub        func(livekit.TrackSource, int32) map[livekit.TrackID]int32
+	setMaxSpatialLayerForSourceMutex       sync.RWMutex
+	setMaxSpatialLayerForSourceArgsForCall []struct {
+		arg1 livekit.TrackSource
+		arg2 int32
+	}
+	setMaxSpatialLayerForSourceReturns struct {
+		result1 map[livekit.TrackID]int32
+	}
+	setMaxSpatialLayerForSourceReturnsOnCall map[int]struct {
+		result1 map[livekit.TrackID]int32
+	}
 	SetMetadataStub        func(string)
 	setMetadataMutex       sync.RWMutex
 	setMetadataArgsForCall []struct {
 		arg1 string
 	}
-	SetMigrateInfoStub        func(*webrtc.SessionDescription, *webrtc.SessionDescription, []*livekit.TrackPublishedResponse, []*livekit.DataChannelInfo)
+	SetMigrateInfoStub        func(*webrtc.SessionDescription, *webrtc.SessionDescription, []*livekit.TrackPublishedResponse, []*livekit.DataChannelInfo, map[string]bool)
 	setMigrateInfoMutex       sync.RWMutex
 	setMigrateInfoArgsForCall []struct {
 		arg1 *webrtc.SessionDescription
 		arg2 *webrtc.SessionDescription
 		arg3 []*livekit.TrackPublishedResponse
 		arg4 []*livekit.DataChannelInfo
+		arg5 map[string]bool
 	}
 	SetMigrateStateStub        func(types.MigrateState)
 	setMigrateStateMutex       sync.RWMutex
@@ -776,10 +1129,21 @@ type FakeLocalParticipant struct {
 	setNameArgsForCall []struct {
 		arg1 string
 	}
-	SetPermissionStub        func(*livekit.ParticipantPermission) bool
+	SetPacerBurstLimitStub        func(int)
+	setPacerBurstLimitMutex       sync.RWMutex
+	setPacerBurstLimitArgsForCall []struct {
+		arg1 int
+	}
+	SetPacerIntervalStub        func(time.Duration)
+	setPacerIntervalMutex       sync.RWMutex
+	setPacerIntervalArgsForCall []struct {
+		arg1 time.Duration
+	}
+	SetPermissionStub        func(*livekit.ParticipantPermission, *types.AdminActionOptions) bool
 	setPermissionMutex       sync.RWMutex
 	setPermissionArgsForCall []struct {
 		arg1 *livekit.ParticipantPermission
+		arg2 *types.AdminActionOptions
 	}
 	setPermissionReturns struct {
 		result1 bool
@@ -802,17 +1166,27 @@ type FakeLocalParticipant struct {
 	setSubscriberAllowPauseArgsForCall []struct {
 		arg1 bool
 	}
+	SetSubscriberAudioOnlyStub        func(bool)
+	setSubscriberAudioOnlyMutex       sync.RWMutex
+	setSubscriberAudioOnlyArgsForCall []struct {
+		arg1 bool
+	}
 	SetSubscriberChannelCapacityStub        func(int64)
 	setSubscriberChannelCapacityMutex       sync.RWMutex
 	setSubscriberChannelCapacityArgsForCall []struct {
 		arg1 int64
 	}
-	SetTrackMutedStub        func(livekit.TrackID, bool, bool) *livekit.TrackInfo
+	SetSubscriberPLIThrottleStub        func(time.Duration)
+	setSubscriberPLIThrottleMutex       sync.RWMutex
+	setSubscriberPLIThrottleArgsForCall []struct {
+		arg1 time.Duration
+	}
+	SetTrackMutedStub        func(livekit.TrackID, bool, *types.AdminActionOptions) *livekit.TrackInfo
 	setTrackMutedMutex       sync.RWMutex
 	setTrackMutedArgsForCall []struct {
 		arg1 livekit.TrackID
 		arg2 bool
-		arg3 bool
+		arg3 *types.AdminActionOptions
 	}
 	setTrackMutedReturns struct {
 		result1 *livekit.TrackInfo
@@ -820,6 +1194,30 @@ type FakeLocalParticipant struct {
 	setTrackMutedReturnsOnCall map[int]struct {
 		result1 *livekit.TrackInfo
 	}
+	SetTrackPausedStub        func(livekit.TrackID, bool) error
+	setTrackPausedMutex       sync.RWMutex
+	setTrackPausedArgsForCall []struct {
+		arg1 livekit.TrackID
+		arg2 bool
+	}
+	setTrackPausedReturns struct {
+		result1 error
+	}
+	setTrackPausedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetTrackPlayoutDelayStub        func(livekit.TrackID, *livekit.PlayoutDelay)
+	setTrackPlayoutDelayMutex       sync.RWMutex
+	setTrackPlayoutDelayArgsForCall []struct {
+		arg1 livekit.TrackID
+		arg2 *livekit.PlayoutDelay
+	}
+	SetTrackVisibilityStub        func(livekit.TrackID, *types.TrackVisibility)
+	setTrackVisibilityMutex       sync.RWMutex
+	setTrackVisibilityArgsForCall []struct {
+		arg1 livekit.TrackID
+		arg2 *types.TrackVisibility
+	}
 	StateStub        func() livekit.ParticipantInfo_State
 	stateMutex       sync.RWMutex
 	stateArgsForCall []struct {
@@ -906,6 +1304,16 @@ type FakeLocalParticipant struct {
 		result1 *livekit.ParticipantInfo
 		result2 utils.TimedVersion
 	}
+	UnacknowledgedReliableDataStub        func() [][]byte
+	unacknowledgedReliableDataMutex       sync.RWMutex
+	unacknowledgedReliableDataArgsForCall []struct {
+	}
+	unacknowledgedReliableDataReturns struct {
+		result1 [][]byte
+	}
+	unacknowledgedReliableDataReturnsOnCall map[int]struct {
+		result1 [][]byte
+	}
 	UncacheDownTrackStub        func(*webrtc.RTPTransceiver)
 	uncacheDownTrackMutex       sync.RWMutex
 	uncacheDownTrackArgsForCall []struct {
@@ -973,6 +1381,12 @@ type FakeLocalParticipant struct {
 		arg1 livekit.TrackID
 		arg2 *livekit.UpdateTrackSettings
 	}
+	UpdateSubscriptionLimitsStub        func(int32, int32)
+	updateSubscriptionLimitsMutex       sync.RWMutex
+	updateSubscriptionLimitsArgsForCall []struct {
+		arg1 int32
+		arg2 int32
+	}
 	UpdateSubscriptionPermissionStub        func(*livekit.SubscriptionPermission, utils.TimedVersion, func(participantID livekit.ParticipantID) types.LocalParticipant) error
 	updateSubscriptionPermissionMutex       sync.RWMutex
 	updateSubscriptionPermissionArgsForCall []struct {
@@ -1029,6 +1443,67 @@ type FakeLocalParticipant struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeLocalParticipant) AbortMigration(arg1 types.ParticipantCloseReason) error {
+	fake.abortMigrationMutex.Lock()
+	ret, specificReturn := fake.abortMigrationReturnsOnCall[len(fake.abortMigrationArgsForCall)]
+	fake.abortMigrationArgsForCall = append(fake.abortMigrationArgsForCall, struct {
+		arg1 types.ParticipantCloseReason
+	}{arg1})
+	stub := fake.AbortMigrationStub
+	fakeReturns := fake.abortMigrationReturns
+	fake.recordInvocation("AbortMigration", []interface{}{arg1})
+	fake.abortMigrationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) AbortMigrationCallCount() int {
+	fake.abortMigrationMutex.RLock()
+	defer fake.abortMigrationMutex.RUnlock()
+	return len(fake.abortMigrationArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) AbortMigrationCalls(stub func(types.ParticipantCloseReason) error) {
+	fake.abortMigrationMutex.Lock()
+	defer fake.abortMigrationMutex.Unlock()
+	fake.AbortMigrationStub = stub
+}
+
+func (fake *FakeLocalParticipant) AbortMigrationArgsForCall(i int) types.ParticipantCloseReason {
+	fake.abortMigrationMutex.RLock()
+	defer fake.abortMigrationMutex.RUnlock()
+	argsForCall := fake.abortMigrationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) AbortMigrationReturns(result1 error) {
+	fake.abortMigrationMutex.Lock()
+	defer fake.abortMigrationMutex.Unlock()
+	fake.AbortMigrationStub = nil
+	fake.abortMigrationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) AbortMigrationReturnsOnCall(i int, result1 error) {
+	fake.abortMigrationMutex.Lock()
+	defer fake.abortMigrationMutex.Unlock()
+	fake.AbortMigrationStub = nil
+	if fake.abortMigrationReturnsOnCall == nil {
+		fake.abortMigrationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.abortMigrationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) AddICECandidate(arg1 webrtc.ICECandidateInit, arg2 livekit.SignalTarget) {
 	fake.addICECandidateMutex.Lock()
 	fake.addICECandidateArgsForCall = append(fake.addICECandidateArgsForCall, struct {
@@ -1537,6 +2012,38 @@ func (fake *FakeLocalParticipant) ClaimGrantsReturnsOnCall(i int, result1 *auth.
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) ClearResumedDownTrack(arg1 livekit.TrackID) {
+	fake.clearResumedDownTrackMutex.Lock()
+	fake.clearResumedDownTrackArgsForCall = append(fake.clearResumedDownTrackArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.ClearResumedDownTrackStub
+	fake.recordInvocation("ClearResumedDownTrack", []interface{}{arg1})
+	fake.clearResumedDownTrackMutex.Unlock()
+	if stub != nil {
+		fake.ClearResumedDownTrackStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) ClearResumedDownTrackCallCount() int {
+	fake.clearResumedDownTrackMutex.RLock()
+	defer fake.clearResumedDownTrackMutex.RUnlock()
+	return len(fake.clearResumedDownTrackArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) ClearResumedDownTrackCalls(stub func(livekit.TrackID)) {
+	fake.clearResumedDownTrackMutex.Lock()
+	defer fake.clearResumedDownTrackMutex.Unlock()
+	fake.ClearResumedDownTrackStub = stub
+}
+
+func (fake *FakeLocalParticipant) ClearResumedDownTrackArgsForCall(i int) livekit.TrackID {
+	fake.clearResumedDownTrackMutex.RLock()
+	defer fake.clearResumedDownTrackMutex.RUnlock()
+	argsForCall := fake.clearResumedDownTrackArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) Close(arg1 bool, arg2 types.ParticipantCloseReason, arg3 bool) error {
 	fake.closeMutex.Lock()
 	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
@@ -1738,6 +2245,67 @@ func (fake *FakeLocalParticipant) ConnectedAtReturnsOnCall(i int, result1 time.T
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) DataChannelBufferedAmount(arg1 livekit.DataPacket_Kind) uint64 {
+	fake.dataChannelBufferedAmountMutex.Lock()
+	ret, specificReturn := fake.dataChannelBufferedAmountReturnsOnCall[len(fake.dataChannelBufferedAmountArgsForCall)]
+	fake.dataChannelBufferedAmountArgsForCall = append(fake.dataChannelBufferedAmountArgsForCall, struct {
+		arg1 livekit.DataPacket_Kind
+	}{arg1})
+	stub := fake.DataChannelBufferedAmountStub
+	fakeReturns := fake.dataChannelBufferedAmountReturns
+	fake.recordInvocation("DataChannelBufferedAmount", []interface{}{arg1})
+	fake.dataChannelBufferedAmountMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) DataChannelBufferedAmountCallCount() int {
+	fake.dataChannelBufferedAmountMutex.RLock()
+	defer fake.dataChannelBufferedAmountMutex.RUnlock()
+	return len(fake.dataChannelBufferedAmountArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) DataChannelBufferedAmountCalls(stub func(livekit.DataPacket_Kind) uint64) {
+	fake.dataChannelBufferedAmountMutex.Lock()
+	defer fake.dataChannelBufferedAmountMutex.Unlock()
+	fake.DataChannelBufferedAmountStub = stub
+}
+
+func (fake *FakeLocalParticipant) DataChannelBufferedAmountArgsForCall(i int) livekit.DataPacket_Kind {
+	fake.dataChannelBufferedAmountMutex.RLock()
+	defer fake.dataChannelBufferedAmountMutex.RUnlock()
+	argsForCall := fake.dataChannelBufferedAmountArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) DataChannelBufferedAmountReturns(result1 uint64) {
+	fake.dataChannelBufferedAmountMutex.Lock()
+	defer fake.dataChannelBufferedAmountMutex.Unlock()
+	fake.DataChannelBufferedAmountStub = nil
+	fake.dataChannelBufferedAmountReturns = struct {
+		result1 uint64
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) DataChannelBufferedAmountReturnsOnCall(i int, result1 uint64) {
+	fake.dataChannelBufferedAmountMutex.Lock()
+	defer fake.dataChannelBufferedAmountMutex.Unlock()
+	fake.DataChannelBufferedAmountStub = nil
+	if fake.dataChannelBufferedAmountReturnsOnCall == nil {
+		fake.dataChannelBufferedAmountReturnsOnCall = make(map[int]struct {
+			result1 uint64
+		})
+	}
+	fake.dataChannelBufferedAmountReturnsOnCall[i] = struct {
+		result1 uint64
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) DebugInfo() map[string]interface{} {
 	fake.debugInfoMutex.Lock()
 	ret, specificReturn := fake.debugInfoReturnsOnCall[len(fake.debugInfoArgsForCall)]
@@ -1844,15 +2412,15 @@ func (fake *FakeLocalParticipant) DisconnectedReturnsOnCall(i int, result1 <-cha
 	}{result1}
 }
 
-func (fake *FakeLocalParticipant) GetAdaptiveStream() bool {
-	fake.getAdaptiveStreamMutex.Lock()
-	ret, specificReturn := fake.getAdaptiveStreamReturnsOnCall[len(fake.getAdaptiveStreamArgsForCall)]
-	fake.getAdaptiveStreamArgsForCall = append(fake.getAdaptiveStreamArgsForCall, struct {
+func (fake *FakeLocalParticipant) ForceCompleteMigration() error {
+	fake.forceCompleteMigrationMutex.Lock()
+	ret, specificReturn := fake.forceCompleteMigrationReturnsOnCall[len(fake.forceCompleteMigrationArgsForCall)]
+	fake.forceCompleteMigrationArgsForCall = append(fake.forceCompleteMigrationArgsForCall, struct {
 	}{})
-	stub := fake.GetAdaptiveStreamStub
-	fakeReturns := fake.getAdaptiveStreamReturns
-	fake.recordInvocation("GetAdaptiveStream", []interface{}{})
-	fake.getAdaptiveStreamMutex.Unlock()
+	stub := fake.ForceCompleteMigrationStub
+	fakeReturns := fake.forceCompleteMigrationReturns
+	fake.recordInvocation("ForceCompleteMigration", []interface{}{})
+	fake.forceCompleteMigrationMutex.Unlock()
 	if stub != nil {
 		return stub()
 	}
@@ -1862,41 +2430,253 @@ func (fake *FakeLocalParticipant) GetAdaptiveStream() bool {
 	return fakeReturns.result1
 }
 
-func (fake *FakeLocalParticipant) GetAdaptiveStreamCallCount() int {
-	fake.getAdaptiveStreamMutex.RLock()
-	defer fake.getAdaptiveStreamMutex.RUnlock()
-	return len(fake.getAdaptiveStreamArgsForCall)
+func (fake *FakeLocalParticipant) ForceCompleteMigrationCallCount() int {
+	fake.forceCompleteMigrationMutex.RLock()
+	defer fake.forceCompleteMigrationMutex.RUnlock()
+	return len(fake.forceCompleteMigrationArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) GetAdaptiveStreamCalls(stub func() bool) {
-	fake.getAdaptiveStreamMutex.Lock()
-	defer fake.getAdaptiveStreamMutex.Unlock()
-	fake.GetAdaptiveStreamStub = stub
+func (fake *FakeLocalParticipant) ForceCompleteMigrationCalls(stub func() error) {
+	fake.forceCompleteMigrationMutex.Lock()
+	defer fake.forceCompleteMigrationMutex.Unlock()
+	fake.ForceCompleteMigrationStub = stub
 }
 
-func (fake *FakeLocalParticipant) GetAdaptiveStreamReturns(result1 bool) {
-	fake.getAdaptiveStreamMutex.Lock()
-	defer fake.getAdaptiveStreamMutex.Unlock()
-	fake.GetAdaptiveStreamStub = nil
-	fake.getAdaptiveStreamReturns = struct {
-		result1 bool
+func (fake *FakeLocalParticipant) ForceCompleteMigrationReturns(result1 error) {
+	fake.forceCompleteMigrationMutex.Lock()
+	defer fake.forceCompleteMigrationMutex.Unlock()
+	fake.ForceCompleteMigrationStub = nil
+	fake.forceCompleteMigrationReturns = struct {
+		result1 error
 	}{result1}
 }
 
-func (fake *FakeLocalParticipant) GetAdaptiveStreamReturnsOnCall(i int, result1 bool) {
-	fake.getAdaptiveStreamMutex.Lock()
-	defer fake.getAdaptiveStreamMutex.Unlock()
-	fake.GetAdaptiveStreamStub = nil
-	if fake.getAdaptiveStreamReturnsOnCall == nil {
-		fake.getAdaptiveStreamReturnsOnCall = make(map[int]struct {
-			result1 bool
+func (fake *FakeLocalParticipant) ForceCompleteMigrationReturnsOnCall(i int, result1 error) {
+	fake.forceCompleteMigrationMutex.Lock()
+	defer fake.forceCompleteMigrationMutex.Unlock()
+	fake.ForceCompleteMigrationStub = nil
+	if fake.forceCompleteMigrationReturnsOnCall == nil {
+		fake.forceCompleteMigrationReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.getAdaptiveStreamReturnsOnCall[i] = struct {
+	fake.forceCompleteMigrationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) ForceSubscriberRenegotiation() error {
+	fake.forceSubscriberRenegotiationMutex.Lock()
+	ret, specificReturn := fake.forceSubscriberRenegotiationReturnsOnCall[len(fake.forceSubscriberRenegotiationArgsForCall)]
+	fake.forceSubscriberRenegotiationArgsForCall = append(fake.forceSubscriberRenegotiationArgsForCall, struct {
+	}{})
+	stub := fake.ForceSubscriberRenegotiationStub
+	fakeReturns := fake.forceSubscriberRenegotiationReturns
+	fake.recordInvocation("ForceSubscriberRenegotiation", []interface{}{})
+	fake.forceSubscriberRenegotiationMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) ForceSubscriberRenegotiationCallCount() int {
+	fake.forceSubscriberRenegotiationMutex.RLock()
+	defer fake.forceSubscriberRenegotiationMutex.RUnlock()
+	return len(fake.forceSubscriberRenegotiationArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) ForceSubscriberRenegotiationCalls(stub func() error) {
+	fake.forceSubscriberRenegotiationMutex.Lock()
+	defer fake.forceSubscriberRenegotiationMutex.Unlock()
+	fake.ForceSubscriberRenegotiationStub = stub
+}
+
+func (fake *FakeLocalParticipant) ForceSubscriberRenegotiationReturns(result1 error) {
+	fake.forceSubscriberRenegotiationMutex.Lock()
+	defer fake.forceSubscriberRenegotiationMutex.Unlock()
+	fake.ForceSubscriberRenegotiationStub = nil
+	fake.forceSubscriberRenegotiationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) ForceSubscriberRenegotiationReturnsOnCall(i int, result1 error) {
+	fake.forceSubscriberRenegotiationMutex.Lock()
+	defer fake.forceSubscriberRenegotiationMutex.Unlock()
+	fake.ForceSubscriberRenegotiationStub = nil
+	if fake.forceSubscriberRenegotiationReturnsOnCall == nil {
+		fake.forceSubscriberRenegotiationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.forceSubscriberRenegotiationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetAdaptiveStream() bool {
+	fake.getAdaptiveStreamMutex.Lock()
+	ret, specificReturn := fake.getAdaptiveStreamReturnsOnCall[len(fake.getAdaptiveStreamArgsForCall)]
+	fake.getAdaptiveStreamArgsForCall = append(fake.getAdaptiveStreamArgsForCall, struct {
+	}{})
+	stub := fake.GetAdaptiveStreamStub
+	fakeReturns := fake.getAdaptiveStreamReturns
+	fake.recordInvocation("GetAdaptiveStream", []interface{}{})
+	fake.getAdaptiveStreamMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetAdaptiveStreamCallCount() int {
+	fake.getAdaptiveStreamMutex.RLock()
+	defer fake.getAdaptiveStreamMutex.RUnlock()
+	return len(fake.getAdaptiveStreamArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetAdaptiveStreamCalls(stub func() bool) {
+	fake.getAdaptiveStreamMutex.Lock()
+	defer fake.getAdaptiveStreamMutex.Unlock()
+	fake.GetAdaptiveStreamStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetAdaptiveStreamReturns(result1 bool) {
+	fake.getAdaptiveStreamMutex.Lock()
+	defer fake.getAdaptiveStreamMutex.Unlock()
+	fake.GetAdaptiveStreamStub = nil
+	fake.getAdaptiveStreamReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetAdaptiveStreamReturnsOnCall(i int, result1 bool) {
+	fake.getAdaptiveStreamMutex.Lock()
+	defer fake.getAdaptiveStreamMutex.Unlock()
+	fake.GetAdaptiveStreamStub = nil
+	if fake.getAdaptiveStreamReturnsOnCall == nil {
+		fake.getAdaptiveStreamReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.getAdaptiveStreamReturnsOnCall[i] = struct {
 		result1 bool
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetAdminAuditLog() []types.AdminAuditEntry {
+	fake.getAdminAuditLogMutex.Lock()
+	ret, specificReturn := fake.getAdminAuditLogReturnsOnCall[len(fake.getAdminAuditLogArgsForCall)]
+	fake.getAdminAuditLogArgsForCall = append(fake.getAdminAuditLogArgsForCall, struct {
+	}{})
+	stub := fake.GetAdminAuditLogStub
+	fakeReturns := fake.getAdminAuditLogReturns
+	fake.recordInvocation("GetAdminAuditLog", []interface{}{})
+	fake.getAdminAuditLogMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetAdminAuditLogCallCount() int {
+	fake.getAdminAuditLogMutex.RLock()
+	defer fake.getAdminAuditLogMutex.RUnlock()
+	return len(fake.getAdminAuditLogArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetAdminAuditLogCalls(stub func() []types.AdminAuditEntry) {
+	fake.getAdminAuditLogMutex.Lock()
+	defer fake.getAdminAuditLogMutex.Unlock()
+	fake.GetAdminAuditLogStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetAdminAuditLogReturns(result1 []types.AdminAuditEntry) {
+	fake.getAdminAuditLogMutex.Lock()
+	defer fake.getAdminAuditLogMutex.Unlock()
+	fake.GetAdminAuditLogStub = nil
+	fake.getAdminAuditLogReturns = struct {
+		result1 []types.AdminAuditEntry
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetAdminAuditLogReturnsOnCall(i int, result1 []types.AdminAuditEntry) {
+	fake.getAdminAuditLogMutex.Lock()
+	defer fake.getAdminAuditLogMutex.Unlock()
+	fake.GetAdminAuditLogStub = nil
+	if fake.getAdminAuditLogReturnsOnCall == nil {
+		fake.getAdminAuditLogReturnsOnCall = make(map[int]struct {
+			result1 []types.AdminAuditEntry
+		})
+	}
+	fake.getAdminAuditLogReturnsOnCall[i] = struct {
+		result1 []types.AdminAuditEntry
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetAllocationPreference() sfu.AllocationPreference {
+	fake.getAllocationPreferenceMutex.Lock()
+	ret, specificReturn := fake.getAllocationPreferenceReturnsOnCall[len(fake.getAllocationPreferenceArgsForCall)]
+	fake.getAllocationPreferenceArgsForCall = append(fake.getAllocationPreferenceArgsForCall, struct {
+	}{})
+	stub := fake.GetAllocationPreferenceStub
+	fakeReturns := fake.getAllocationPreferenceReturns
+	fake.recordInvocation("GetAllocationPreference", []interface{}{})
+	fake.getAllocationPreferenceMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetAllocationPreferenceCallCount() int {
+	fake.getAllocationPreferenceMutex.RLock()
+	defer fake.getAllocationPreferenceMutex.RUnlock()
+	return len(fake.getAllocationPreferenceArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetAllocationPreferenceCalls(stub func() sfu.AllocationPreference) {
+	fake.getAllocationPreferenceMutex.Lock()
+	defer fake.getAllocationPreferenceMutex.Unlock()
+	fake.GetAllocationPreferenceStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetAllocationPreferenceReturns(result1 sfu.AllocationPreference) {
+	fake.getAllocationPreferenceMutex.Lock()
+	defer fake.getAllocationPreferenceMutex.Unlock()
+	fake.GetAllocationPreferenceStub = nil
+	fake.getAllocationPreferenceReturns = struct {
+		result1 sfu.AllocationPreference
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetAllocationPreferenceReturnsOnCall(i int, result1 sfu.AllocationPreference) {
+	fake.getAllocationPreferenceMutex.Lock()
+	defer fake.getAllocationPreferenceMutex.Unlock()
+	fake.GetAllocationPreferenceStub = nil
+	if fake.getAllocationPreferenceReturnsOnCall == nil {
+		fake.getAllocationPreferenceReturnsOnCall = make(map[int]struct {
+			result1 sfu.AllocationPreference
+		})
+	}
+	fake.getAllocationPreferenceReturnsOnCall[i] = struct {
+		result1 sfu.AllocationPreference
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetAudioLevel() (float64, bool) {
 	fake.getAudioLevelMutex.Lock()
 	ret, specificReturn := fake.getAudioLevelReturnsOnCall[len(fake.getAudioLevelArgsForCall)]
@@ -1953,6 +2733,59 @@ func (fake *FakeLocalParticipant) GetAudioLevelReturnsOnCall(i int, result1 floa
 	}{result1, result2}
 }
 
+func (fake *FakeLocalParticipant) GetBitrateSummary() *types.BitrateSummary {
+	fake.getBitrateSummaryMutex.Lock()
+	ret, specificReturn := fake.getBitrateSummaryReturnsOnCall[len(fake.getBitrateSummaryArgsForCall)]
+	fake.getBitrateSummaryArgsForCall = append(fake.getBitrateSummaryArgsForCall, struct {
+	}{})
+	stub := fake.GetBitrateSummaryStub
+	fakeReturns := fake.getBitrateSummaryReturns
+	fake.recordInvocation("GetBitrateSummary", []interface{}{})
+	fake.getBitrateSummaryMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetBitrateSummaryCallCount() int {
+	fake.getBitrateSummaryMutex.RLock()
+	defer fake.getBitrateSummaryMutex.RUnlock()
+	return len(fake.getBitrateSummaryArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetBitrateSummaryCalls(stub func() *types.BitrateSummary) {
+	fake.getBitrateSummaryMutex.Lock()
+	defer fake.getBitrateSummaryMutex.Unlock()
+	fake.GetBitrateSummaryStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetBitrateSummaryReturns(result1 *types.BitrateSummary) {
+	fake.getBitrateSummaryMutex.Lock()
+	defer fake.getBitrateSummaryMutex.Unlock()
+	fake.GetBitrateSummaryStub = nil
+	fake.getBitrateSummaryReturns = struct {
+		result1 *types.BitrateSummary
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetBitrateSummaryReturnsOnCall(i int, result1 *types.BitrateSummary) {
+	fake.getBitrateSummaryMutex.Lock()
+	defer fake.getBitrateSummaryMutex.Unlock()
+	fake.GetBitrateSummaryStub = nil
+	if fake.getBitrateSummaryReturnsOnCall == nil {
+		fake.getBitrateSummaryReturnsOnCall = make(map[int]struct {
+			result1 *types.BitrateSummary
+		})
+	}
+	fake.getBitrateSummaryReturnsOnCall[i] = struct {
+		result1 *types.BitrateSummary
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetBufferFactory() *buffer.Factory {
 	fake.getBufferFactoryMutex.Lock()
 	ret, specificReturn := fake.getBufferFactoryReturnsOnCall[len(fake.getBufferFactoryArgsForCall)]
@@ -2229,6 +3062,59 @@ func (fake *FakeLocalParticipant) GetConnectionQualityReturnsOnCall(i int, resul
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetDeniedSubscriptions() []livekit.TrackID {
+	fake.getDeniedSubscriptionsMutex.Lock()
+	ret, specificReturn := fake.getDeniedSubscriptionsReturnsOnCall[len(fake.getDeniedSubscriptionsArgsForCall)]
+	fake.getDeniedSubscriptionsArgsForCall = append(fake.getDeniedSubscriptionsArgsForCall, struct {
+	}{})
+	stub := fake.GetDeniedSubscriptionsStub
+	fakeReturns := fake.getDeniedSubscriptionsReturns
+	fake.recordInvocation("GetDeniedSubscriptions", []interface{}{})
+	fake.getDeniedSubscriptionsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetDeniedSubscriptionsCallCount() int {
+	fake.getDeniedSubscriptionsMutex.RLock()
+	defer fake.getDeniedSubscriptionsMutex.RUnlock()
+	return len(fake.getDeniedSubscriptionsArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetDeniedSubscriptionsCalls(stub func() []livekit.TrackID) {
+	fake.getDeniedSubscriptionsMutex.Lock()
+	defer fake.getDeniedSubscriptionsMutex.Unlock()
+	fake.GetDeniedSubscriptionsStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetDeniedSubscriptionsReturns(result1 []livekit.TrackID) {
+	fake.getDeniedSubscriptionsMutex.Lock()
+	defer fake.getDeniedSubscriptionsMutex.Unlock()
+	fake.GetDeniedSubscriptionsStub = nil
+	fake.getDeniedSubscriptionsReturns = struct {
+		result1 []livekit.TrackID
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetDeniedSubscriptionsReturnsOnCall(i int, result1 []livekit.TrackID) {
+	fake.getDeniedSubscriptionsMutex.Lock()
+	defer fake.getDeniedSubscriptionsMutex.Unlock()
+	fake.GetDeniedSubscriptionsStub = nil
+	if fake.getDeniedSubscriptionsReturnsOnCall == nil {
+		fake.getDeniedSubscriptionsReturnsOnCall = make(map[int]struct {
+			result1 []livekit.TrackID
+		})
+	}
+	fake.getDeniedSubscriptionsReturnsOnCall[i] = struct {
+		result1 []livekit.TrackID
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetICEConnectionDetails() []*types.ICEConnectionDetails {
 	fake.getICEConnectionDetailsMutex.Lock()
 	ret, specificReturn := fake.getICEConnectionDetailsReturnsOnCall[len(fake.getICEConnectionDetailsArgsForCall)]
@@ -2282,6 +3168,59 @@ func (fake *FakeLocalParticipant) GetICEConnectionDetailsReturnsOnCall(i int, re
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetLastGoodICEConfig() *livekit.ICEConfig {
+	fake.getLastGoodICEConfigMutex.Lock()
+	ret, specificReturn := fake.getLastGoodICEConfigReturnsOnCall[len(fake.getLastGoodICEConfigArgsForCall)]
+	fake.getLastGoodICEConfigArgsForCall = append(fake.getLastGoodICEConfigArgsForCall, struct {
+	}{})
+	stub := fake.GetLastGoodICEConfigStub
+	fakeReturns := fake.getLastGoodICEConfigReturns
+	fake.recordInvocation("GetLastGoodICEConfig", []interface{}{})
+	fake.getLastGoodICEConfigMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetLastGoodICEConfigCallCount() int {
+	fake.getLastGoodICEConfigMutex.RLock()
+	defer fake.getLastGoodICEConfigMutex.RUnlock()
+	return len(fake.getLastGoodICEConfigArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetLastGoodICEConfigCalls(stub func() *livekit.ICEConfig) {
+	fake.getLastGoodICEConfigMutex.Lock()
+	defer fake.getLastGoodICEConfigMutex.Unlock()
+	fake.GetLastGoodICEConfigStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetLastGoodICEConfigReturns(result1 *livekit.ICEConfig) {
+	fake.getLastGoodICEConfigMutex.Lock()
+	defer fake.getLastGoodICEConfigMutex.Unlock()
+	fake.GetLastGoodICEConfigStub = nil
+	fake.getLastGoodICEConfigReturns = struct {
+		result1 *livekit.ICEConfig
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetLastGoodICEConfigReturnsOnCall(i int, result1 *livekit.ICEConfig) {
+	fake.getLastGoodICEConfigMutex.Lock()
+	defer fake.getLastGoodICEConfigMutex.Unlock()
+	fake.GetLastGoodICEConfigStub = nil
+	if fake.getLastGoodICEConfigReturnsOnCall == nil {
+		fake.getLastGoodICEConfigReturnsOnCall = make(map[int]struct {
+			result1 *livekit.ICEConfig
+		})
+	}
+	fake.getLastGoodICEConfigReturnsOnCall[i] = struct {
+		result1 *livekit.ICEConfig
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetLogger() logger.Logger {
 	fake.getLoggerMutex.Lock()
 	ret, specificReturn := fake.getLoggerReturnsOnCall[len(fake.getLoggerArgsForCall)]
@@ -2388,6 +3327,112 @@ func (fake *FakeLocalParticipant) GetPacerReturnsOnCall(i int, result1 pacer.Pac
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetPacerSendRate() int64 {
+	fake.getPacerSendRateMutex.Lock()
+	ret, specificReturn := fake.getPacerSendRateReturnsOnCall[len(fake.getPacerSendRateArgsForCall)]
+	fake.getPacerSendRateArgsForCall = append(fake.getPacerSendRateArgsForCall, struct {
+	}{})
+	stub := fake.GetPacerSendRateStub
+	fakeReturns := fake.getPacerSendRateReturns
+	fake.recordInvocation("GetPacerSendRate", []interface{}{})
+	fake.getPacerSendRateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetPacerSendRateCallCount() int {
+	fake.getPacerSendRateMutex.RLock()
+	defer fake.getPacerSendRateMutex.RUnlock()
+	return len(fake.getPacerSendRateArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetPacerSendRateCalls(stub func() int64) {
+	fake.getPacerSendRateMutex.Lock()
+	defer fake.getPacerSendRateMutex.Unlock()
+	fake.GetPacerSendRateStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetPacerSendRateReturns(result1 int64) {
+	fake.getPacerSendRateMutex.Lock()
+	defer fake.getPacerSendRateMutex.Unlock()
+	fake.GetPacerSendRateStub = nil
+	fake.getPacerSendRateReturns = struct {
+		result1 int64
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetPacerSendRateReturnsOnCall(i int, result1 int64) {
+	fake.getPacerSendRateMutex.Lock()
+	defer fake.getPacerSendRateMutex.Unlock()
+	fake.GetPacerSendRateStub = nil
+	if fake.getPacerSendRateReturnsOnCall == nil {
+		fake.getPacerSendRateReturnsOnCall = make(map[int]struct {
+			result1 int64
+		})
+	}
+	fake.getPacerSendRateReturnsOnCall[i] = struct {
+		result1 int64
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetPacerSettings() types.PacerSettings {
+	fake.getPacerSettingsMutex.Lock()
+	ret, specificReturn := fake.getPacerSettingsReturnsOnCall[len(fake.getPacerSettingsArgsForCall)]
+	fake.getPacerSettingsArgsForCall = append(fake.getPacerSettingsArgsForCall, struct {
+	}{})
+	stub := fake.GetPacerSettingsStub
+	fakeReturns := fake.getPacerSettingsReturns
+	fake.recordInvocation("GetPacerSettings", []interface{}{})
+	fake.getPacerSettingsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetPacerSettingsCallCount() int {
+	fake.getPacerSettingsMutex.RLock()
+	defer fake.getPacerSettingsMutex.RUnlock()
+	return len(fake.getPacerSettingsArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetPacerSettingsCalls(stub func() types.PacerSettings) {
+	fake.getPacerSettingsMutex.Lock()
+	defer fake.getPacerSettingsMutex.Unlock()
+	fake.GetPacerSettingsStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetPacerSettingsReturns(result1 types.PacerSettings) {
+	fake.getPacerSettingsMutex.Lock()
+	defer fake.getPacerSettingsMutex.Unlock()
+	fake.GetPacerSettingsStub = nil
+	fake.getPacerSettingsReturns = struct {
+		result1 types.PacerSettings
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetPacerSettingsReturnsOnCall(i int, result1 types.PacerSettings) {
+	fake.getPacerSettingsMutex.Lock()
+	defer fake.getPacerSettingsMutex.Unlock()
+	fake.GetPacerSettingsStub = nil
+	if fake.getPacerSettingsReturnsOnCall == nil {
+		fake.getPacerSettingsReturnsOnCall = make(map[int]struct {
+			result1 types.PacerSettings
+		})
+	}
+	fake.getPacerSettingsReturnsOnCall[i] = struct {
+		result1 types.PacerSettings
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetPendingTrack(arg1 livekit.TrackID) *livekit.TrackInfo {
 	fake.getPendingTrackMutex.Lock()
 	ret, specificReturn := fake.getPendingTrackReturnsOnCall[len(fake.getPendingTrackArgsForCall)]
@@ -2449,17 +3494,18 @@ func (fake *FakeLocalParticipant) GetPendingTrackReturnsOnCall(i int, result1 *l
 	}{result1}
 }
 
-func (fake *FakeLocalParticipant) GetPlayoutDelayConfig() *livekit.PlayoutDelay {
+func (fake *FakeLocalParticipant) GetPlayoutDelayConfig(arg1 livekit.TrackSource) *livekit.PlayoutDelay {
 	fake.getPlayoutDelayConfigMutex.Lock()
 	ret, specificReturn := fake.getPlayoutDelayConfigReturnsOnCall[len(fake.getPlayoutDelayConfigArgsForCall)]
 	fake.getPlayoutDelayConfigArgsForCall = append(fake.getPlayoutDelayConfigArgsForCall, struct {
-	}{})
+		arg1 livekit.TrackSource
+	}{arg1})
 	stub := fake.GetPlayoutDelayConfigStub
 	fakeReturns := fake.getPlayoutDelayConfigReturns
-	fake.recordInvocation("GetPlayoutDelayConfig", []interface{}{})
+	fake.recordInvocation("GetPlayoutDelayConfig", []interface{}{arg1})
 	fake.getPlayoutDelayConfigMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -2473,12 +3519,19 @@ func (fake *FakeLocalParticipant) GetPlayoutDelayConfigCallCount() int {
 	return len(fake.getPlayoutDelayConfigArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) GetPlayoutDelayConfigCalls(stub func() *livekit.PlayoutDelay) {
+func (fake *FakeLocalParticipant) GetPlayoutDelayConfigCalls(stub func(livekit.TrackSource) *livekit.PlayoutDelay) {
 	fake.getPlayoutDelayConfigMutex.Lock()
 	defer fake.getPlayoutDelayConfigMutex.Unlock()
 	fake.GetPlayoutDelayConfigStub = stub
 }
 
+func (fake *FakeLocalParticipant) GetPlayoutDelayConfigArgsForCall(i int) livekit.TrackSource {
+	fake.getPlayoutDelayConfigMutex.RLock()
+	defer fake.getPlayoutDelayConfigMutex.RUnlock()
+	argsForCall := fake.getPlayoutDelayConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) GetPlayoutDelayConfigReturns(result1 *livekit.PlayoutDelay) {
 	fake.getPlayoutDelayConfigMutex.Lock()
 	defer fake.getPlayoutDelayConfigMutex.Unlock()
@@ -2616,6 +3669,229 @@ func (fake *FakeLocalParticipant) GetPublishedTracksReturnsOnCall(i int, result1
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetRTTHistory() []types.RTTSample {
+	fake.getRTTHistoryMutex.Lock()
+	ret, specificReturn := fake.getRTTHistoryReturnsOnCall[len(fake.getRTTHistoryArgsForCall)]
+	fake.getRTTHistoryArgsForCall = append(fake.getRTTHistoryArgsForCall, struct {
+	}{})
+	stub := fake.GetRTTHistoryStub
+	fakeReturns := fake.getRTTHistoryReturns
+	fake.recordInvocation("GetRTTHistory", []interface{}{})
+	fake.getRTTHistoryMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetRTTHistoryCallCount() int {
+	fake.getRTTHistoryMutex.RLock()
+	defer fake.getRTTHistoryMutex.RUnlock()
+	return len(fake.getRTTHistoryArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetRTTHistoryCalls(stub func() []types.RTTSample) {
+	fake.getRTTHistoryMutex.Lock()
+	defer fake.getRTTHistoryMutex.Unlock()
+	fake.GetRTTHistoryStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetRTTHistoryReturns(result1 []types.RTTSample) {
+	fake.getRTTHistoryMutex.Lock()
+	defer fake.getRTTHistoryMutex.Unlock()
+	fake.GetRTTHistoryStub = nil
+	fake.getRTTHistoryReturns = struct {
+		result1 []types.RTTSample
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetRTTHistoryReturnsOnCall(i int, result1 []types.RTTSample) {
+	fake.getRTTHistoryMutex.Lock()
+	defer fake.getRTTHistoryMutex.Unlock()
+	fake.GetRTTHistoryStub = nil
+	if fake.getRTTHistoryReturnsOnCall == nil {
+		fake.getRTTHistoryReturnsOnCall = make(map[int]struct {
+			result1 []types.RTTSample
+		})
+	}
+	fake.getRTTHistoryReturnsOnCall[i] = struct {
+		result1 []types.RTTSample
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetReconnectCount() uint32 {
+	fake.getReconnectCountMutex.Lock()
+	ret, specificReturn := fake.getReconnectCountReturnsOnCall[len(fake.getReconnectCountArgsForCall)]
+	fake.getReconnectCountArgsForCall = append(fake.getReconnectCountArgsForCall, struct {
+	}{})
+	stub := fake.GetReconnectCountStub
+	fakeReturns := fake.getReconnectCountReturns
+	fake.recordInvocation("GetReconnectCount", []interface{}{})
+	fake.getReconnectCountMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetReconnectCountCallCount() int {
+	fake.getReconnectCountMutex.RLock()
+	defer fake.getReconnectCountMutex.RUnlock()
+	return len(fake.getReconnectCountArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetReconnectCountCalls(stub func() uint32) {
+	fake.getReconnectCountMutex.Lock()
+	defer fake.getReconnectCountMutex.Unlock()
+	fake.GetReconnectCountStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetReconnectCountReturns(result1 uint32) {
+	fake.getReconnectCountMutex.Lock()
+	defer fake.getReconnectCountMutex.Unlock()
+	fake.GetReconnectCountStub = nil
+	fake.getReconnectCountReturns = struct {
+		result1 uint32
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetReconnectCountReturnsOnCall(i int, result1 uint32) {
+	fake.getReconnectCountMutex.Lock()
+	defer fake.getReconnectCountMutex.Unlock()
+	fake.GetReconnectCountStub = nil
+	if fake.getReconnectCountReturnsOnCall == nil {
+		fake.getReconnectCountReturnsOnCall = make(map[int]struct {
+			result1 uint32
+		})
+	}
+	fake.getReconnectCountReturnsOnCall[i] = struct {
+		result1 uint32
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetResumedDownTrack(arg1 livekit.TrackID) (sfu.DownTrackState, bool) {
+	fake.getResumedDownTrackMutex.Lock()
+	ret, specificReturn := fake.getResumedDownTrackReturnsOnCall[len(fake.getResumedDownTrackArgsForCall)]
+	fake.getResumedDownTrackArgsForCall = append(fake.getResumedDownTrackArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.GetResumedDownTrackStub
+	fakeReturns := fake.getResumedDownTrackReturns
+	fake.recordInvocation("GetResumedDownTrack", []interface{}{arg1})
+	fake.getResumedDownTrackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLocalParticipant) GetResumedDownTrackCallCount() int {
+	fake.getResumedDownTrackMutex.RLock()
+	defer fake.getResumedDownTrackMutex.RUnlock()
+	return len(fake.getResumedDownTrackArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetResumedDownTrackCalls(stub func(livekit.TrackID) (sfu.DownTrackState, bool)) {
+	fake.getResumedDownTrackMutex.Lock()
+	defer fake.getResumedDownTrackMutex.Unlock()
+	fake.GetResumedDownTrackStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetResumedDownTrackArgsForCall(i int) livekit.TrackID {
+	fake.getResumedDownTrackMutex.RLock()
+	defer fake.getResumedDownTrackMutex.RUnlock()
+	argsForCall := fake.getResumedDownTrackArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) GetResumedDownTrackReturns(result1 sfu.DownTrackState, result2 bool) {
+	fake.getResumedDownTrackMutex.Lock()
+	defer fake.getResumedDownTrackMutex.Unlock()
+	fake.GetResumedDownTrackStub = nil
+	fake.getResumedDownTrackReturns = struct {
+		result1 sfu.DownTrackState
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeLocalParticipant) GetResumedDownTrackReturnsOnCall(i int, result1 sfu.DownTrackState, result2 bool) {
+	fake.getResumedDownTrackMutex.Lock()
+	defer fake.getResumedDownTrackMutex.Unlock()
+	fake.GetResumedDownTrackStub = nil
+	if fake.getResumedDownTrackReturnsOnCall == nil {
+		fake.getResumedDownTrackReturnsOnCall = make(map[int]struct {
+			result1 sfu.DownTrackState
+			result2 bool
+		})
+	}
+	fake.getResumedDownTrackReturnsOnCall[i] = struct {
+		result1 sfu.DownTrackState
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeLocalParticipant) GetSignalingRTT() uint32 {
+	fake.getSignalingRTTMutex.Lock()
+	ret, specificReturn := fake.getSignalingRTTReturnsOnCall[len(fake.getSignalingRTTArgsForCall)]
+	fake.getSignalingRTTArgsForCall = append(fake.getSignalingRTTArgsForCall, struct {
+	}{})
+	stub := fake.GetSignalingRTTStub
+	fakeReturns := fake.getSignalingRTTReturns
+	fake.recordInvocation("GetSignalingRTT", []interface{}{})
+	fake.getSignalingRTTMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetSignalingRTTCallCount() int {
+	fake.getSignalingRTTMutex.RLock()
+	defer fake.getSignalingRTTMutex.RUnlock()
+	return len(fake.getSignalingRTTArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetSignalingRTTCalls(stub func() uint32) {
+	fake.getSignalingRTTMutex.Lock()
+	defer fake.getSignalingRTTMutex.Unlock()
+	fake.GetSignalingRTTStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetSignalingRTTReturns(result1 uint32) {
+	fake.getSignalingRTTMutex.Lock()
+	defer fake.getSignalingRTTMutex.Unlock()
+	fake.GetSignalingRTTStub = nil
+	fake.getSignalingRTTReturns = struct {
+		result1 uint32
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetSignalingRTTReturnsOnCall(i int, result1 uint32) {
+	fake.getSignalingRTTMutex.Lock()
+	defer fake.getSignalingRTTMutex.Unlock()
+	fake.GetSignalingRTTStub = nil
+	if fake.getSignalingRTTReturnsOnCall == nil {
+		fake.getSignalingRTTReturnsOnCall = make(map[int]struct {
+			result1 uint32
+		})
+	}
+	fake.getSignalingRTTReturnsOnCall[i] = struct {
+		result1 uint32
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetSubscribedParticipants() []livekit.ParticipantID {
 	fake.getSubscribedParticipantsMutex.Lock()
 	ret, specificReturn := fake.getSubscribedParticipantsReturnsOnCall[len(fake.getSubscribedParticipantsArgsForCall)]
@@ -2722,6 +3998,176 @@ func (fake *FakeLocalParticipant) GetSubscribedTracksReturnsOnCall(i int, result
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetSubscriberPLIThrottle() time.Duration {
+	fake.getSubscriberPLIThrottleMutex.Lock()
+	ret, specificReturn := fake.getSubscriberPLIThrottleReturnsOnCall[len(fake.getSubscriberPLIThrottleArgsForCall)]
+	fake.getSubscriberPLIThrottleArgsForCall = append(fake.getSubscriberPLIThrottleArgsForCall, struct {
+	}{})
+	stub := fake.GetSubscriberPLIThrottleStub
+	fakeReturns := fake.getSubscriberPLIThrottleReturns
+	fake.recordInvocation("GetSubscriberPLIThrottle", []interface{}{})
+	fake.getSubscriberPLIThrottleMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberPLIThrottleCallCount() int {
+	fake.getSubscriberPLIThrottleMutex.RLock()
+	defer fake.getSubscriberPLIThrottleMutex.RUnlock()
+	return len(fake.getSubscriberPLIThrottleArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberPLIThrottleCalls(stub func() time.Duration) {
+	fake.getSubscriberPLIThrottleMutex.Lock()
+	defer fake.getSubscriberPLIThrottleMutex.Unlock()
+	fake.GetSubscriberPLIThrottleStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberPLIThrottleReturns(result1 time.Duration) {
+	fake.getSubscriberPLIThrottleMutex.Lock()
+	defer fake.getSubscriberPLIThrottleMutex.Unlock()
+	fake.GetSubscriberPLIThrottleStub = nil
+	fake.getSubscriberPLIThrottleReturns = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberPLIThrottleReturnsOnCall(i int, result1 time.Duration) {
+	fake.getSubscriberPLIThrottleMutex.Lock()
+	defer fake.getSubscriberPLIThrottleMutex.Unlock()
+	fake.GetSubscriberPLIThrottleStub = nil
+	if fake.getSubscriberPLIThrottleReturnsOnCall == nil {
+		fake.getSubscriberPLIThrottleReturnsOnCall = make(map[int]struct {
+			result1 time.Duration
+		})
+	}
+	fake.getSubscriberPLIThrottleReturnsOnCall[i] = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberReportedLoss() *types.SubscriberLossSummary {
+	fake.getSubscriberReportedLossMutex.Lock()
+	ret, specificReturn := fake.getSubscriberReportedLossReturnsOnCall[len(fake.getSubscriberReportedLossArgsForCall)]
+	fake.getSubscriberReportedLossArgsForCall = append(fake.getSubscriberReportedLossArgsForCall, struct {
+	}{})
+	stub := fake.GetSubscriberReportedLossStub
+	fakeReturns := fake.getSubscriberReportedLossReturns
+	fake.recordInvocation("GetSubscriberReportedLoss", []interface{}{})
+	fake.getSubscriberReportedLossMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberReportedLossCallCount() int {
+	fake.getSubscriberReportedLossMutex.RLock()
+	defer fake.getSubscriberReportedLossMutex.RUnlock()
+	return len(fake.getSubscriberReportedLossArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberReportedLossCalls(stub func() *types.SubscriberLossSummary) {
+	fake.getSubscriberReportedLossMutex.Lock()
+	defer fake.getSubscriberReportedLossMutex.Unlock()
+	fake.GetSubscriberReportedLossStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberReportedLossReturns(result1 *types.SubscriberLossSummary) {
+	fake.getSubscriberReportedLossMutex.Lock()
+	defer fake.getSubscriberReportedLossMutex.Unlock()
+	fake.GetSubscriberReportedLossStub = nil
+	fake.getSubscriberReportedLossReturns = struct {
+		result1 *types.SubscriberLossSummary
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetSubscriberReportedLossReturnsOnCall(i int, result1 *types.SubscriberLossSummary) {
+	fake.getSubscriberReportedLossMutex.Lock()
+	defer fake.getSubscriberReportedLossMutex.Unlock()
+	fake.GetSubscriberReportedLossStub = nil
+	if fake.getSubscriberReportedLossReturnsOnCall == nil {
+		fake.getSubscriberReportedLossReturnsOnCall = make(map[int]struct {
+			result1 *types.SubscriberLossSummary
+		})
+	}
+	fake.getSubscriberReportedLossReturnsOnCall[i] = struct {
+		result1 *types.SubscriberLossSummary
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetTemporalLayerDistribution(arg1 livekit.TrackID) ([]buffer.TemporalLayerDistribution, error) {
+	fake.getTemporalLayerDistributionMutex.Lock()
+	ret, specificReturn := fake.getTemporalLayerDistributionReturnsOnCall[len(fake.getTemporalLayerDistributionArgsForCall)]
+	fake.getTemporalLayerDistributionArgsForCall = append(fake.getTemporalLayerDistributionArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.GetTemporalLayerDistributionStub
+	fakeReturns := fake.getTemporalLayerDistributionReturns
+	fake.recordInvocation("GetTemporalLayerDistribution", []interface{}{arg1})
+	fake.getTemporalLayerDistributionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLocalParticipant) GetTemporalLayerDistributionCallCount() int {
+	fake.getTemporalLayerDistributionMutex.RLock()
+	defer fake.getTemporalLayerDistributionMutex.RUnlock()
+	return len(fake.getTemporalLayerDistributionArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetTemporalLayerDistributionCalls(stub func(livekit.TrackID) ([]buffer.TemporalLayerDistribution, error)) {
+	fake.getTemporalLayerDistributionMutex.Lock()
+	defer fake.getTemporalLayerDistributionMutex.Unlock()
+	fake.GetTemporalLayerDistributionStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetTemporalLayerDistributionArgsForCall(i int) livekit.TrackID {
+	fake.getTemporalLayerDistributionMutex.RLock()
+	defer fake.getTemporalLayerDistributionMutex.RUnlock()
+	argsForCall := fake.getTemporalLayerDistributionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) GetTemporalLayerDistributionReturns(result1 []buffer.TemporalLayerDistribution, result2 error) {
+	fake.getTemporalLayerDistributionMutex.Lock()
+	defer fake.getTemporalLayerDistributionMutex.Unlock()
+	fake.GetTemporalLayerDistributionStub = nil
+	fake.getTemporalLayerDistributionReturns = struct {
+		result1 []buffer.TemporalLayerDistribution
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLocalParticipant) GetTemporalLayerDistributionReturnsOnCall(i int, result1 []buffer.TemporalLayerDistribution, result2 error) {
+	fake.getTemporalLayerDistributionMutex.Lock()
+	defer fake.getTemporalLayerDistributionMutex.Unlock()
+	fake.GetTemporalLayerDistributionStub = nil
+	if fake.getTemporalLayerDistributionReturnsOnCall == nil {
+		fake.getTemporalLayerDistributionReturnsOnCall = make(map[int]struct {
+			result1 []buffer.TemporalLayerDistribution
+			result2 error
+		})
+	}
+	fake.getTemporalLayerDistributionReturnsOnCall[i] = struct {
+		result1 []buffer.TemporalLayerDistribution
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeLocalParticipant) GetTrafficLoad() *types.TrafficLoad {
 	fake.getTrafficLoadMutex.Lock()
 	ret, specificReturn := fake.getTrafficLoadReturnsOnCall[len(fake.getTrafficLoadArgsForCall)]
@@ -2828,6 +4274,70 @@ func (fake *FakeLocalParticipant) GetTrailerReturnsOnCall(i int, result1 []byte)
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetVideoAllocation(arg1 livekit.TrackID) (*sfu.VideoAllocation, bool) {
+	fake.getVideoAllocationMutex.Lock()
+	ret, specificReturn := fake.getVideoAllocationReturnsOnCall[len(fake.getVideoAllocationArgsForCall)]
+	fake.getVideoAllocationArgsForCall = append(fake.getVideoAllocationArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.GetVideoAllocationStub
+	fakeReturns := fake.getVideoAllocationReturns
+	fake.recordInvocation("GetVideoAllocation", []interface{}{arg1})
+	fake.getVideoAllocationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLocalParticipant) GetVideoAllocationCallCount() int {
+	fake.getVideoAllocationMutex.RLock()
+	defer fake.getVideoAllocationMutex.RUnlock()
+	return len(fake.getVideoAllocationArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetVideoAllocationCalls(stub func(livekit.TrackID) (*sfu.VideoAllocation, bool)) {
+	fake.getVideoAllocationMutex.Lock()
+	defer fake.getVideoAllocationMutex.Unlock()
+	fake.GetVideoAllocationStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetVideoAllocationArgsForCall(i int) livekit.TrackID {
+	fake.getVideoAllocationMutex.RLock()
+	defer fake.getVideoAllocationMutex.RUnlock()
+	argsForCall := fake.getVideoAllocationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) GetVideoAllocationReturns(result1 *sfu.VideoAllocation, result2 bool) {
+	fake.getVideoAllocationMutex.Lock()
+	defer fake.getVideoAllocationMutex.Unlock()
+	fake.GetVideoAllocationStub = nil
+	fake.getVideoAllocationReturns = struct {
+		result1 *sfu.VideoAllocation
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeLocalParticipant) GetVideoAllocationReturnsOnCall(i int, result1 *sfu.VideoAllocation, result2 bool) {
+	fake.getVideoAllocationMutex.Lock()
+	defer fake.getVideoAllocationMutex.Unlock()
+	fake.GetVideoAllocationStub = nil
+	if fake.getVideoAllocationReturnsOnCall == nil {
+		fake.getVideoAllocationReturnsOnCall = make(map[int]struct {
+			result1 *sfu.VideoAllocation
+			result2 bool
+		})
+	}
+	fake.getVideoAllocationReturnsOnCall[i] = struct {
+		result1 *sfu.VideoAllocation
+		result2 bool
+	}{result1, result2}
+}
+
 func (fake *FakeLocalParticipant) HandleAnswer(arg1 webrtc.SessionDescription) {
 	fake.handleAnswerMutex.Lock()
 	fake.handleAnswerArgsForCall = append(fake.handleAnswerArgsForCall, struct {
@@ -2860,6 +4370,38 @@ func (fake *FakeLocalParticipant) HandleAnswerArgsForCall(i int) webrtc.SessionD
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) HandleDecodeFeedback(arg1 livekit.TrackID) {
+	fake.handleDecodeFeedbackMutex.Lock()
+	fake.handleDecodeFeedbackArgsForCall = append(fake.handleDecodeFeedbackArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.HandleDecodeFeedbackStub
+	fake.recordInvocation("HandleDecodeFeedback", []interface{}{arg1})
+	fake.handleDecodeFeedbackMutex.Unlock()
+	if stub != nil {
+		fake.HandleDecodeFeedbackStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) HandleDecodeFeedbackCallCount() int {
+	fake.handleDecodeFeedbackMutex.RLock()
+	defer fake.handleDecodeFeedbackMutex.RUnlock()
+	return len(fake.handleDecodeFeedbackArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) HandleDecodeFeedbackCalls(stub func(livekit.TrackID)) {
+	fake.handleDecodeFeedbackMutex.Lock()
+	defer fake.handleDecodeFeedbackMutex.Unlock()
+	fake.HandleDecodeFeedbackStub = stub
+}
+
+func (fake *FakeLocalParticipant) HandleDecodeFeedbackArgsForCall(i int) livekit.TrackID {
+	fake.handleDecodeFeedbackMutex.RLock()
+	defer fake.handleDecodeFeedbackMutex.RUnlock()
+	argsForCall := fake.handleDecodeFeedbackArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) HandleOffer(arg1 webrtc.SessionDescription) {
 	fake.handleOfferMutex.Lock()
 	fake.handleOfferArgsForCall = append(fake.handleOfferArgsForCall, struct {
@@ -2999,16 +4541,134 @@ func (fake *FakeLocalParticipant) HandleSignalSourceClose() {
 	}
 }
 
-func (fake *FakeLocalParticipant) HandleSignalSourceCloseCallCount() int {
-	fake.handleSignalSourceCloseMutex.RLock()
-	defer fake.handleSignalSourceCloseMutex.RUnlock()
-	return len(fake.handleSignalSourceCloseArgsForCall)
+func (fake *FakeLocalParticipant) HandleSignalSourceCloseCallCount() int {
+	fake.handleSignalSourceCloseMutex.RLock()
+	defer fake.handleSignalSourceCloseMutex.RUnlock()
+	return len(fake.handleSignalSourceCloseArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) HandleSignalSourceCloseCalls(stub func()) {
+	fake.handleSignalSourceCloseMutex.Lock()
+	defer fake.handleSignalSourceCloseMutex.Unlock()
+	fake.HandleSignalSourceCloseStub = stub
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAudioOnlyRequest(arg1 bool) {
+	fake.handleSubscriberAudioOnlyRequestMutex.Lock()
+	fake.handleSubscriberAudioOnlyRequestArgsForCall = append(fake.handleSubscriberAudioOnlyRequestArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.HandleSubscriberAudioOnlyRequestStub
+	fake.recordInvocation("HandleSubscriberAudioOnlyRequest", []interface{}{arg1})
+	fake.handleSubscriberAudioOnlyRequestMutex.Unlock()
+	if stub != nil {
+		fake.HandleSubscriberAudioOnlyRequestStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAudioOnlyRequestCallCount() int {
+	fake.handleSubscriberAudioOnlyRequestMutex.RLock()
+	defer fake.handleSubscriberAudioOnlyRequestMutex.RUnlock()
+	return len(fake.handleSubscriberAudioOnlyRequestArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAudioOnlyRequestCalls(stub func(bool)) {
+	fake.handleSubscriberAudioOnlyRequestMutex.Lock()
+	defer fake.handleSubscriberAudioOnlyRequestMutex.Unlock()
+	fake.HandleSubscriberAudioOnlyRequestStub = stub
+}
+
+func (fake *FakeLocalParticipant) HandleSubscriberAudioOnlyRequestArgsForCall(i int) bool {
+	fake.handleSubscriberAudioOnlyRequestMutex.RLock()
+	defer fake.handleSubscriberAudioOnlyRequestMutex.RUnlock()
+	argsForCall := fake.handleSubscriberAudioOnlyRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) HandleTrackPauseRequest(arg1 livekit.TrackID, arg2 bool) {
+	fake.handleTrackPauseRequestMutex.Lock()
+	fake.handleTrackPauseRequestArgsForCall = append(fake.handleTrackPauseRequestArgsForCall, struct {
+		arg1 livekit.TrackID
+		arg2 bool
+	}{arg1, arg2})
+	stub := fake.HandleTrackPauseRequestStub
+	fake.recordInvocation("HandleTrackPauseRequest", []interface{}{arg1, arg2})
+	fake.handleTrackPauseRequestMutex.Unlock()
+	if stub != nil {
+		fake.HandleTrackPauseRequestStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeLocalParticipant) HandleTrackPauseRequestCallCount() int {
+	fake.handleTrackPauseRequestMutex.RLock()
+	defer fake.handleTrackPauseRequestMutex.RUnlock()
+	return len(fake.handleTrackPauseRequestArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) HandleTrackPauseRequestCalls(stub func(livekit.TrackID, bool)) {
+	fake.handleTrackPauseRequestMutex.Lock()
+	defer fake.handleTrackPauseRequestMutex.Unlock()
+	fake.HandleTrackPauseRequestStub = stub
+}
+
+func (fake *FakeLocalParticipant) HandleTrackPauseRequestArgsForCall(i int) (livekit.TrackID, bool) {
+	fake.handleTrackPauseRequestMutex.RLock()
+	defer fake.handleTrackPauseRequestMutex.RUnlock()
+	argsForCall := fake.handleTrackPauseRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLocalParticipant) HasActiveMedia() bool {
+	fake.hasActiveMediaMutex.Lock()
+	ret, specificReturn := fake.hasActiveMediaReturnsOnCall[len(fake.hasActiveMediaArgsForCall)]
+	fake.hasActiveMediaArgsForCall = append(fake.hasActiveMediaArgsForCall, struct {
+	}{})
+	stub := fake.HasActiveMediaStub
+	fakeReturns := fake.hasActiveMediaReturns
+	fake.recordInvocation("HasActiveMedia", []interface{}{})
+	fake.hasActiveMediaMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) HasActiveMediaCallCount() int {
+	fake.hasActiveMediaMutex.RLock()
+	defer fake.hasActiveMediaMutex.RUnlock()
+	return len(fake.hasActiveMediaArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) HandleSignalSourceCloseCalls(stub func()) {
-	fake.handleSignalSourceCloseMutex.Lock()
-	defer fake.handleSignalSourceCloseMutex.Unlock()
-	fake.HandleSignalSourceCloseStub = stub
+func (fake *FakeLocalParticipant) HasActiveMediaCalls(stub func() bool) {
+	fake.hasActiveMediaMutex.Lock()
+	defer fake.hasActiveMediaMutex.Unlock()
+	fake.HasActiveMediaStub = stub
+}
+
+func (fake *FakeLocalParticipant) HasActiveMediaReturns(result1 bool) {
+	fake.hasActiveMediaMutex.Lock()
+	defer fake.hasActiveMediaMutex.Unlock()
+	fake.HasActiveMediaStub = nil
+	fake.hasActiveMediaReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) HasActiveMediaReturnsOnCall(i int, result1 bool) {
+	fake.hasActiveMediaMutex.Lock()
+	defer fake.hasActiveMediaMutex.Unlock()
+	fake.HasActiveMediaStub = nil
+	if fake.hasActiveMediaReturnsOnCall == nil {
+		fake.hasActiveMediaReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.hasActiveMediaReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
 }
 
 func (fake *FakeLocalParticipant) HasConnected() bool {
@@ -3370,6 +5030,67 @@ func (fake *FakeLocalParticipant) IsClosedReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) IsDataChannelBackpressured(arg1 livekit.DataPacket_Kind) bool {
+	fake.isDataChannelBackpressuredMutex.Lock()
+	ret, specificReturn := fake.isDataChannelBackpressuredReturnsOnCall[len(fake.isDataChannelBackpressuredArgsForCall)]
+	fake.isDataChannelBackpressuredArgsForCall = append(fake.isDataChannelBackpressuredArgsForCall, struct {
+		arg1 livekit.DataPacket_Kind
+	}{arg1})
+	stub := fake.IsDataChannelBackpressuredStub
+	fakeReturns := fake.isDataChannelBackpressuredReturns
+	fake.recordInvocation("IsDataChannelBackpressured", []interface{}{arg1})
+	fake.isDataChannelBackpressuredMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) IsDataChannelBackpressuredCallCount() int {
+	fake.isDataChannelBackpressuredMutex.RLock()
+	defer fake.isDataChannelBackpressuredMutex.RUnlock()
+	return len(fake.isDataChannelBackpressuredArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) IsDataChannelBackpressuredCalls(stub func(livekit.DataPacket_Kind) bool) {
+	fake.isDataChannelBackpressuredMutex.Lock()
+	defer fake.isDataChannelBackpressuredMutex.Unlock()
+	fake.IsDataChannelBackpressuredStub = stub
+}
+
+func (fake *FakeLocalParticipant) IsDataChannelBackpressuredArgsForCall(i int) livekit.DataPacket_Kind {
+	fake.isDataChannelBackpressuredMutex.RLock()
+	defer fake.isDataChannelBackpressuredMutex.RUnlock()
+	argsForCall := fake.isDataChannelBackpressuredArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) IsDataChannelBackpressuredReturns(result1 bool) {
+	fake.isDataChannelBackpressuredMutex.Lock()
+	defer fake.isDataChannelBackpressuredMutex.Unlock()
+	fake.IsDataChannelBackpressuredStub = nil
+	fake.isDataChannelBackpressuredReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) IsDataChannelBackpressuredReturnsOnCall(i int, result1 bool) {
+	fake.isDataChannelBackpressuredMutex.Lock()
+	defer fake.isDataChannelBackpressuredMutex.Unlock()
+	fake.IsDataChannelBackpressuredStub = nil
+	if fake.isDataChannelBackpressuredReturnsOnCall == nil {
+		fake.isDataChannelBackpressuredReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isDataChannelBackpressuredReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) IsDependent() bool {
 	fake.isDependentMutex.Lock()
 	ret, specificReturn := fake.isDependentReturnsOnCall[len(fake.isDependentArgsForCall)]
@@ -3834,6 +5555,62 @@ func (fake *FakeLocalParticipant) KindReturnsOnCall(i int, result1 livekit.Parti
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) MarshalResumeState() ([]byte, error) {
+	fake.marshalResumeStateMutex.Lock()
+	ret, specificReturn := fake.marshalResumeStateReturnsOnCall[len(fake.marshalResumeStateArgsForCall)]
+	fake.marshalResumeStateArgsForCall = append(fake.marshalResumeStateArgsForCall, struct {
+	}{})
+	stub := fake.MarshalResumeStateStub
+	fakeReturns := fake.marshalResumeStateReturns
+	fake.recordInvocation("MarshalResumeState", []interface{}{})
+	fake.marshalResumeStateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLocalParticipant) MarshalResumeStateCallCount() int {
+	fake.marshalResumeStateMutex.RLock()
+	defer fake.marshalResumeStateMutex.RUnlock()
+	return len(fake.marshalResumeStateArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) MarshalResumeStateCalls(stub func() ([]byte, error)) {
+	fake.marshalResumeStateMutex.Lock()
+	defer fake.marshalResumeStateMutex.Unlock()
+	fake.MarshalResumeStateStub = stub
+}
+
+func (fake *FakeLocalParticipant) MarshalResumeStateReturns(result1 []byte, result2 error) {
+	fake.marshalResumeStateMutex.Lock()
+	defer fake.marshalResumeStateMutex.Unlock()
+	fake.MarshalResumeStateStub = nil
+	fake.marshalResumeStateReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLocalParticipant) MarshalResumeStateReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.marshalResumeStateMutex.Lock()
+	defer fake.marshalResumeStateMutex.Unlock()
+	fake.MarshalResumeStateStub = nil
+	if fake.marshalResumeStateReturnsOnCall == nil {
+		fake.marshalResumeStateReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.marshalResumeStateReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeLocalParticipant) MaybeStartMigration(arg1 bool, arg2 func()) bool {
 	fake.maybeStartMigrationMutex.Lock()
 	ret, specificReturn := fake.maybeStartMigrationReturnsOnCall[len(fake.maybeStartMigrationArgsForCall)]
@@ -4005,6 +5782,38 @@ func (fake *FakeLocalParticipant) NotifyMigrationCalls(stub func()) {
 	fake.NotifyMigrationStub = stub
 }
 
+func (fake *FakeLocalParticipant) OnAvailableLayersChanged(arg1 func(types.LocalParticipant, livekit.TrackID, []int32)) {
+	fake.onAvailableLayersChangedMutex.Lock()
+	fake.onAvailableLayersChangedArgsForCall = append(fake.onAvailableLayersChangedArgsForCall, struct {
+		arg1 func(types.LocalParticipant, livekit.TrackID, []int32)
+	}{arg1})
+	stub := fake.OnAvailableLayersChangedStub
+	fake.recordInvocation("OnAvailableLayersChanged", []interface{}{arg1})
+	fake.onAvailableLayersChangedMutex.Unlock()
+	if stub != nil {
+		fake.OnAvailableLayersChangedStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) OnAvailableLayersChangedCallCount() int {
+	fake.onAvailableLayersChangedMutex.RLock()
+	defer fake.onAvailableLayersChangedMutex.RUnlock()
+	return len(fake.onAvailableLayersChangedArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) OnAvailableLayersChangedCalls(stub func(func(types.LocalParticipant, livekit.TrackID, []int32))) {
+	fake.onAvailableLayersChangedMutex.Lock()
+	defer fake.onAvailableLayersChangedMutex.Unlock()
+	fake.OnAvailableLayersChangedStub = stub
+}
+
+func (fake *FakeLocalParticipant) OnAvailableLayersChangedArgsForCall(i int) func(types.LocalParticipant, livekit.TrackID, []int32) {
+	fake.onAvailableLayersChangedMutex.RLock()
+	defer fake.onAvailableLayersChangedMutex.RUnlock()
+	argsForCall := fake.onAvailableLayersChangedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) OnClaimsChanged(arg1 func(types.LocalParticipant)) {
 	fake.onClaimsChangedMutex.Lock()
 	fake.onClaimsChangedArgsForCall = append(fake.onClaimsChangedArgsForCall, struct {
@@ -4069,6 +5878,70 @@ func (fake *FakeLocalParticipant) OnCloseArgsForCall(i int) func(types.LocalPart
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) OnConnectionQualityChanged(arg1 func(types.LocalParticipant, *livekit.ConnectionQualityInfo)) {
+	fake.onConnectionQualityChangedMutex.Lock()
+	fake.onConnectionQualityChangedArgsForCall = append(fake.onConnectionQualityChangedArgsForCall, struct {
+		arg1 func(types.LocalParticipant, *livekit.ConnectionQualityInfo)
+	}{arg1})
+	stub := fake.OnConnectionQualityChangedStub
+	fake.recordInvocation("OnConnectionQualityChanged", []interface{}{arg1})
+	fake.onConnectionQualityChangedMutex.Unlock()
+	if stub != nil {
+		fake.OnConnectionQualityChangedStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) OnConnectionQualityChangedCallCount() int {
+	fake.onConnectionQualityChangedMutex.RLock()
+	defer fake.onConnectionQualityChangedMutex.RUnlock()
+	return len(fake.onConnectionQualityChangedArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) OnConnectionQualityChangedCalls(stub func(func(types.LocalParticipant, *livekit.ConnectionQualityInfo))) {
+	fake.onConnectionQualityChangedMutex.Lock()
+	defer fake.onConnectionQualityChangedMutex.Unlock()
+	fake.OnConnectionQualityChangedStub = stub
+}
+
+func (fake *FakeLocalParticipant) OnConnectionQualityChangedArgsForCall(i int) func(types.LocalParticipant, *livekit.ConnectionQualityInfo) {
+	fake.onConnectionQualityChangedMutex.RLock()
+	defer fake.onConnectionQualityChangedMutex.RUnlock()
+	argsForCall := fake.onConnectionQualityChangedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) OnDataChannelBufferedAmountLow(arg1 func(types.LocalParticipant, livekit.DataPacket_Kind)) {
+	fake.onDataChannelBufferedAmountLowMutex.Lock()
+	fake.onDataChannelBufferedAmountLowArgsForCall = append(fake.onDataChannelBufferedAmountLowArgsForCall, struct {
+		arg1 func(types.LocalParticipant, livekit.DataPacket_Kind)
+	}{arg1})
+	stub := fake.OnDataChannelBufferedAmountLowStub
+	fake.recordInvocation("OnDataChannelBufferedAmountLow", []interface{}{arg1})
+	fake.onDataChannelBufferedAmountLowMutex.Unlock()
+	if stub != nil {
+		fake.OnDataChannelBufferedAmountLowStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) OnDataChannelBufferedAmountLowCallCount() int {
+	fake.onDataChannelBufferedAmountLowMutex.RLock()
+	defer fake.onDataChannelBufferedAmountLowMutex.RUnlock()
+	return len(fake.onDataChannelBufferedAmountLowArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) OnDataChannelBufferedAmountLowCalls(stub func(func(types.LocalParticipant, livekit.DataPacket_Kind))) {
+	fake.onDataChannelBufferedAmountLowMutex.Lock()
+	defer fake.onDataChannelBufferedAmountLowMutex.Unlock()
+	fake.OnDataChannelBufferedAmountLowStub = stub
+}
+
+func (fake *FakeLocalParticipant) OnDataChannelBufferedAmountLowArgsForCall(i int) func(types.LocalParticipant, livekit.DataPacket_Kind) {
+	fake.onDataChannelBufferedAmountLowMutex.RLock()
+	defer fake.onDataChannelBufferedAmountLowMutex.RUnlock()
+	argsForCall := fake.onDataChannelBufferedAmountLowArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) OnDataPacket(arg1 func(types.LocalParticipant, livekit.DataPacket_Kind, *livekit.DataPacket)) {
 	fake.onDataPacketMutex.Lock()
 	fake.onDataPacketArgsForCall = append(fake.onDataPacketArgsForCall, struct {
@@ -4165,6 +6038,38 @@ func (fake *FakeLocalParticipant) OnMigrateStateChangeArgsForCall(i int) func(p
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) OnMigrationTimeout(arg1 func(p types.LocalParticipant)) {
+	fake.onMigrationTimeoutMutex.Lock()
+	fake.onMigrationTimeoutArgsForCall = append(fake.onMigrationTimeoutArgsForCall, struct {
+		arg1 func(p types.LocalParticipant)
+	}{arg1})
+	stub := fake.OnMigrationTimeoutStub
+	fake.recordInvocation("OnMigrationTimeout", []interface{}{arg1})
+	fake.onMigrationTimeoutMutex.Unlock()
+	if stub != nil {
+		fake.OnMigrationTimeoutStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) OnMigrationTimeoutCallCount() int {
+	fake.onMigrationTimeoutMutex.RLock()
+	defer fake.onMigrationTimeoutMutex.RUnlock()
+	return len(fake.onMigrationTimeoutArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) OnMigrationTimeoutCalls(stub func(func(p types.LocalParticipant))) {
+	fake.onMigrationTimeoutMutex.Lock()
+	defer fake.onMigrationTimeoutMutex.Unlock()
+	fake.OnMigrationTimeoutStub = stub
+}
+
+func (fake *FakeLocalParticipant) OnMigrationTimeoutArgsForCall(i int) func(p types.LocalParticipant) {
+	fake.onMigrationTimeoutMutex.RLock()
+	defer fake.onMigrationTimeoutMutex.RUnlock()
+	argsForCall := fake.onMigrationTimeoutArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) OnParticipantUpdate(arg1 func(types.LocalParticipant)) {
 	fake.onParticipantUpdateMutex.Lock()
 	fake.onParticipantUpdateArgsForCall = append(fake.onParticipantUpdateArgsForCall, struct {
@@ -4261,6 +6166,38 @@ func (fake *FakeLocalParticipant) OnSubscribeStatusChangedArgsForCall(i int) fun
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) OnTrackMuteChanged(arg1 func(types.LocalParticipant, types.MediaTrack, bool)) {
+	fake.onTrackMuteChangedMutex.Lock()
+	fake.onTrackMuteChangedArgsForCall = append(fake.onTrackMuteChangedArgsForCall, struct {
+		arg1 func(types.LocalParticipant, types.MediaTrack, bool)
+	}{arg1})
+	stub := fake.OnTrackMuteChangedStub
+	fake.recordInvocation("OnTrackMuteChanged", []interface{}{arg1})
+	fake.onTrackMuteChangedMutex.Unlock()
+	if stub != nil {
+		fake.OnTrackMuteChangedStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) OnTrackMuteChangedCallCount() int {
+	fake.onTrackMuteChangedMutex.RLock()
+	defer fake.onTrackMuteChangedMutex.RUnlock()
+	return len(fake.onTrackMuteChangedArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) OnTrackMuteChangedCalls(stub func(func(types.LocalParticipant, types.MediaTrack, bool))) {
+	fake.onTrackMuteChangedMutex.Lock()
+	defer fake.onTrackMuteChangedMutex.Unlock()
+	fake.OnTrackMuteChangedStub = stub
+}
+
+func (fake *FakeLocalParticipant) OnTrackMuteChangedArgsForCall(i int) func(types.LocalParticipant, types.MediaTrack, bool) {
+	fake.onTrackMuteChangedMutex.RLock()
+	defer fake.onTrackMuteChangedMutex.RUnlock()
+	argsForCall := fake.onTrackMuteChangedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) OnTrackPublished(arg1 func(types.LocalParticipant, types.MediaTrack)) {
 	fake.onTrackPublishedMutex.Lock()
 	fake.onTrackPublishedArgsForCall = append(fake.onTrackPublishedArgsForCall, struct {
@@ -4389,6 +6326,67 @@ func (fake *FakeLocalParticipant) OnTrafficLoadArgsForCall(i int) func(trafficLo
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) PauseTrackForwarding(arg1 livekit.TrackID) error {
+	fake.pauseTrackForwardingMutex.Lock()
+	ret, specificReturn := fake.pauseTrackForwardingReturnsOnCall[len(fake.pauseTrackForwardingArgsForCall)]
+	fake.pauseTrackForwardingArgsForCall = append(fake.pauseTrackForwardingArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.PauseTrackForwardingStub
+	fakeReturns := fake.pauseTrackForwardingReturns
+	fake.recordInvocation("PauseTrackForwarding", []interface{}{arg1})
+	fake.pauseTrackForwardingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) PauseTrackForwardingCallCount() int {
+	fake.pauseTrackForwardingMutex.RLock()
+	defer fake.pauseTrackForwardingMutex.RUnlock()
+	return len(fake.pauseTrackForwardingArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) PauseTrackForwardingCalls(stub func(livekit.TrackID) error) {
+	fake.pauseTrackForwardingMutex.Lock()
+	defer fake.pauseTrackForwardingMutex.Unlock()
+	fake.PauseTrackForwardingStub = stub
+}
+
+func (fake *FakeLocalParticipant) PauseTrackForwardingArgsForCall(i int) livekit.TrackID {
+	fake.pauseTrackForwardingMutex.RLock()
+	defer fake.pauseTrackForwardingMutex.RUnlock()
+	argsForCall := fake.pauseTrackForwardingArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) PauseTrackForwardingReturns(result1 error) {
+	fake.pauseTrackForwardingMutex.Lock()
+	defer fake.pauseTrackForwardingMutex.Unlock()
+	fake.PauseTrackForwardingStub = nil
+	fake.pauseTrackForwardingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) PauseTrackForwardingReturnsOnCall(i int, result1 error) {
+	fake.pauseTrackForwardingMutex.Lock()
+	defer fake.pauseTrackForwardingMutex.Unlock()
+	fake.PauseTrackForwardingStub = nil
+	if fake.pauseTrackForwardingReturnsOnCall == nil {
+		fake.pauseTrackForwardingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pauseTrackForwardingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) ProtocolVersion() types.ProtocolVersion {
 	fake.protocolVersionMutex.Lock()
 	ret, specificReturn := fake.protocolVersionReturnsOnCall[len(fake.protocolVersionArgsForCall)]
@@ -4482,10 +6480,156 @@ func (fake *FakeLocalParticipant) RemoveTrackFromSubscriber(arg1 *webrtc.RTPSend
 	fake.removeTrackFromSubscriberArgsForCall = append(fake.removeTrackFromSubscriberArgsForCall, struct {
 		arg1 *webrtc.RTPSender
 	}{arg1})
-	stub := fake.RemoveTrackFromSubscriberStub
-	fakeReturns := fake.removeTrackFromSubscriberReturns
-	fake.recordInvocation("RemoveTrackFromSubscriber", []interface{}{arg1})
-	fake.removeTrackFromSubscriberMutex.Unlock()
+	stub := fake.RemoveTrackFromSubscriberStub
+	fakeReturns := fake.removeTrackFromSubscriberReturns
+	fake.recordInvocation("RemoveTrackFromSubscriber", []interface{}{arg1})
+	fake.removeTrackFromSubscriberMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberCallCount() int {
+	fake.removeTrackFromSubscriberMutex.RLock()
+	defer fake.removeTrackFromSubscriberMutex.RUnlock()
+	return len(fake.removeTrackFromSubscriberArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberCalls(stub func(*webrtc.RTPSender) error) {
+	fake.removeTrackFromSubscriberMutex.Lock()
+	defer fake.removeTrackFromSubscriberMutex.Unlock()
+	fake.RemoveTrackFromSubscriberStub = stub
+}
+
+func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberArgsForCall(i int) *webrtc.RTPSender {
+	fake.removeTrackFromSubscriberMutex.RLock()
+	defer fake.removeTrackFromSubscriberMutex.RUnlock()
+	argsForCall := fake.removeTrackFromSubscriberArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberReturns(result1 error) {
+	fake.removeTrackFromSubscriberMutex.Lock()
+	defer fake.removeTrackFromSubscriberMutex.Unlock()
+	fake.RemoveTrackFromSubscriberStub = nil
+	fake.removeTrackFromSubscriberReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberReturnsOnCall(i int, result1 error) {
+	fake.removeTrackFromSubscriberMutex.Lock()
+	defer fake.removeTrackFromSubscriberMutex.Unlock()
+	fake.RemoveTrackFromSubscriberStub = nil
+	if fake.removeTrackFromSubscriberReturnsOnCall == nil {
+		fake.removeTrackFromSubscriberReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeTrackFromSubscriberReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) RequestKeyFrame(arg1 livekit.TrackID) error {
+	fake.requestKeyFrameMutex.Lock()
+	ret, specificReturn := fake.requestKeyFrameReturnsOnCall[len(fake.requestKeyFrameArgsForCall)]
+	fake.requestKeyFrameArgsForCall = append(fake.requestKeyFrameArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.RequestKeyFrameStub
+	fakeReturns := fake.requestKeyFrameReturns
+	fake.recordInvocation("RequestKeyFrame", []interface{}{arg1})
+	fake.requestKeyFrameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) RequestKeyFrameCallCount() int {
+	fake.requestKeyFrameMutex.RLock()
+	defer fake.requestKeyFrameMutex.RUnlock()
+	return len(fake.requestKeyFrameArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) RequestKeyFrameCalls(stub func(livekit.TrackID) error) {
+	fake.requestKeyFrameMutex.Lock()
+	defer fake.requestKeyFrameMutex.Unlock()
+	fake.RequestKeyFrameStub = stub
+}
+
+func (fake *FakeLocalParticipant) RequestKeyFrameArgsForCall(i int) livekit.TrackID {
+	fake.requestKeyFrameMutex.RLock()
+	defer fake.requestKeyFrameMutex.RUnlock()
+	argsForCall := fake.requestKeyFrameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) RequestKeyFrameReturns(result1 error) {
+	fake.requestKeyFrameMutex.Lock()
+	defer fake.requestKeyFrameMutex.Unlock()
+	fake.RequestKeyFrameStub = nil
+	fake.requestKeyFrameReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) RequestKeyFrameReturnsOnCall(i int, result1 error) {
+	fake.requestKeyFrameMutex.Lock()
+	defer fake.requestKeyFrameMutex.Unlock()
+	fake.RequestKeyFrameStub = nil
+	if fake.requestKeyFrameReturnsOnCall == nil {
+		fake.requestKeyFrameReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.requestKeyFrameReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) ResetReconnectCount() {
+	fake.resetReconnectCountMutex.Lock()
+	fake.resetReconnectCountArgsForCall = append(fake.resetReconnectCountArgsForCall, struct {
+	}{})
+	stub := fake.ResetReconnectCountStub
+	fake.recordInvocation("ResetReconnectCount", []interface{}{})
+	fake.resetReconnectCountMutex.Unlock()
+	if stub != nil {
+		fake.ResetReconnectCountStub()
+	}
+}
+
+func (fake *FakeLocalParticipant) ResetReconnectCountCallCount() int {
+	fake.resetReconnectCountMutex.RLock()
+	defer fake.resetReconnectCountMutex.RUnlock()
+	return len(fake.resetReconnectCountArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) ResetReconnectCountCalls(stub func()) {
+	fake.resetReconnectCountMutex.Lock()
+	defer fake.resetReconnectCountMutex.Unlock()
+	fake.ResetReconnectCountStub = stub
+}
+
+func (fake *FakeLocalParticipant) ResumeTrackForwarding(arg1 livekit.TrackID) error {
+	fake.resumeTrackForwardingMutex.Lock()
+	ret, specificReturn := fake.resumeTrackForwardingReturnsOnCall[len(fake.resumeTrackForwardingArgsForCall)]
+	fake.resumeTrackForwardingArgsForCall = append(fake.resumeTrackForwardingArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.ResumeTrackForwardingStub
+	fakeReturns := fake.resumeTrackForwardingReturns
+	fake.recordInvocation("ResumeTrackForwarding", []interface{}{arg1})
+	fake.resumeTrackForwardingMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
 	}
@@ -4495,44 +6639,44 @@ func (fake *FakeLocalParticipant) RemoveTrackFromSubscriber(arg1 *webrtc.RTPSend
 	return fakeReturns.result1
 }
 
-func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberCallCount() int {
-	fake.removeTrackFromSubscriberMutex.RLock()
-	defer fake.removeTrackFromSubscriberMutex.RUnlock()
-	return len(fake.removeTrackFromSubscriberArgsForCall)
+func (fake *FakeLocalParticipant) ResumeTrackForwardingCallCount() int {
+	fake.resumeTrackForwardingMutex.RLock()
+	defer fake.resumeTrackForwardingMutex.RUnlock()
+	return len(fake.resumeTrackForwardingArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberCalls(stub func(*webrtc.RTPSender) error) {
-	fake.removeTrackFromSubscriberMutex.Lock()
-	defer fake.removeTrackFromSubscriberMutex.Unlock()
-	fake.RemoveTrackFromSubscriberStub = stub
+func (fake *FakeLocalParticipant) ResumeTrackForwardingCalls(stub func(livekit.TrackID) error) {
+	fake.resumeTrackForwardingMutex.Lock()
+	defer fake.resumeTrackForwardingMutex.Unlock()
+	fake.ResumeTrackForwardingStub = stub
 }
 
-func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberArgsForCall(i int) *webrtc.RTPSender {
-	fake.removeTrackFromSubscriberMutex.RLock()
-	defer fake.removeTrackFromSubscriberMutex.RUnlock()
-	argsForCall := fake.removeTrackFromSubscriberArgsForCall[i]
+func (fake *FakeLocalParticipant) ResumeTrackForwardingArgsForCall(i int) livekit.TrackID {
+	fake.resumeTrackForwardingMutex.RLock()
+	defer fake.resumeTrackForwardingMutex.RUnlock()
+	argsForCall := fake.resumeTrackForwardingArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberReturns(result1 error) {
-	fake.removeTrackFromSubscriberMutex.Lock()
-	defer fake.removeTrackFromSubscriberMutex.Unlock()
-	fake.RemoveTrackFromSubscriberStub = nil
-	fake.removeTrackFromSubscriberReturns = struct {
+func (fake *FakeLocalParticipant) ResumeTrackForwardingReturns(result1 error) {
+	fake.resumeTrackForwardingMutex.Lock()
+	defer fake.resumeTrackForwardingMutex.Unlock()
+	fake.ResumeTrackForwardingStub = nil
+	fake.resumeTrackForwardingReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeLocalParticipant) RemoveTrackFromSubscriberReturnsOnCall(i int, result1 error) {
-	fake.removeTrackFromSubscriberMutex.Lock()
-	defer fake.removeTrackFromSubscriberMutex.Unlock()
-	fake.RemoveTrackFromSubscriberStub = nil
-	if fake.removeTrackFromSubscriberReturnsOnCall == nil {
-		fake.removeTrackFromSubscriberReturnsOnCall = make(map[int]struct {
+func (fake *FakeLocalParticipant) ResumeTrackForwardingReturnsOnCall(i int, result1 error) {
+	fake.resumeTrackForwardingMutex.Lock()
+	defer fake.resumeTrackForwardingMutex.Unlock()
+	fake.ResumeTrackForwardingStub = nil
+	if fake.resumeTrackForwardingReturnsOnCall == nil {
+		fake.resumeTrackForwardingReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.removeTrackFromSubscriberReturnsOnCall[i] = struct {
+	fake.resumeTrackForwardingReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
@@ -4981,6 +7125,132 @@ func (fake *FakeLocalParticipant) SendSpeakerUpdateReturnsOnCall(i int, result1
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) SetAllTracksMuted(arg1 bool, arg2 *types.AdminActionOptions) []*livekit.TrackInfo {
+	fake.setAllTracksMutedMutex.Lock()
+	ret, specificReturn := fake.setAllTracksMutedReturnsOnCall[len(fake.setAllTracksMutedArgsForCall)]
+	fake.setAllTracksMutedArgsForCall = append(fake.setAllTracksMutedArgsForCall, struct {
+		arg1 bool
+		arg2 *types.AdminActionOptions
+	}{arg1, arg2})
+	stub := fake.SetAllTracksMutedStub
+	fakeReturns := fake.setAllTracksMutedReturns
+	fake.recordInvocation("SetAllTracksMuted", []interface{}{arg1, arg2})
+	fake.setAllTracksMutedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) SetAllTracksMutedCallCount() int {
+	fake.setAllTracksMutedMutex.RLock()
+	defer fake.setAllTracksMutedMutex.RUnlock()
+	return len(fake.setAllTracksMutedArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetAllTracksMutedCalls(stub func(bool, *types.AdminActionOptions) []*livekit.TrackInfo) {
+	fake.setAllTracksMutedMutex.Lock()
+	defer fake.setAllTracksMutedMutex.Unlock()
+	fake.SetAllTracksMutedStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetAllTracksMutedArgsForCall(i int) (bool, *types.AdminActionOptions) {
+	fake.setAllTracksMutedMutex.RLock()
+	defer fake.setAllTracksMutedMutex.RUnlock()
+	argsForCall := fake.setAllTracksMutedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLocalParticipant) SetAllTracksMutedReturns(result1 []*livekit.TrackInfo) {
+	fake.setAllTracksMutedMutex.Lock()
+	defer fake.setAllTracksMutedMutex.Unlock()
+	fake.SetAllTracksMutedStub = nil
+	fake.setAllTracksMutedReturns = struct {
+		result1 []*livekit.TrackInfo
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) SetAllTracksMutedReturnsOnCall(i int, result1 []*livekit.TrackInfo) {
+	fake.setAllTracksMutedMutex.Lock()
+	defer fake.setAllTracksMutedMutex.Unlock()
+	fake.SetAllTracksMutedStub = nil
+	if fake.setAllTracksMutedReturnsOnCall == nil {
+		fake.setAllTracksMutedReturnsOnCall = make(map[int]struct {
+			result1 []*livekit.TrackInfo
+		})
+	}
+	fake.setAllTracksMutedReturnsOnCall[i] = struct {
+		result1 []*livekit.TrackInfo
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) SetBandwidthHint(arg1 int64) {
+	fake.setBandwidthHintMutex.Lock()
+	fake.setBandwidthHintArgsForCall = append(fake.setBandwidthHintArgsForCall, struct {
+		arg1 int64
+	}{arg1})
+	stub := fake.SetBandwidthHintStub
+	fake.recordInvocation("SetBandwidthHint", []interface{}{arg1})
+	fake.setBandwidthHintMutex.Unlock()
+	if stub != nil {
+		fake.SetBandwidthHintStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetBandwidthHintCallCount() int {
+	fake.setBandwidthHintMutex.RLock()
+	defer fake.setBandwidthHintMutex.RUnlock()
+	return len(fake.setBandwidthHintArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetBandwidthHintCalls(stub func(int64)) {
+	fake.setBandwidthHintMutex.Lock()
+	defer fake.setBandwidthHintMutex.Unlock()
+	fake.SetBandwidthHintStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetBandwidthHintArgsForCall(i int) int64 {
+	fake.setBandwidthHintMutex.RLock()
+	defer fake.setBandwidthHintMutex.RUnlock()
+	argsForCall := fake.setBandwidthHintArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) SetCloseAdminOptions(arg1 *types.AdminActionOptions) {
+	fake.setCloseAdminOptionsMutex.Lock()
+	fake.setCloseAdminOptionsArgsForCall = append(fake.setCloseAdminOptionsArgsForCall, struct {
+		arg1 *types.AdminActionOptions
+	}{arg1})
+	stub := fake.SetCloseAdminOptionsStub
+	fake.recordInvocation("SetCloseAdminOptions", []interface{}{arg1})
+	fake.setCloseAdminOptionsMutex.Unlock()
+	if stub != nil {
+		fake.SetCloseAdminOptionsStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetCloseAdminOptionsCallCount() int {
+	fake.setCloseAdminOptionsMutex.RLock()
+	defer fake.setCloseAdminOptionsMutex.RUnlock()
+	return len(fake.setCloseAdminOptionsArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetCloseAdminOptionsCalls(stub func(*types.AdminActionOptions)) {
+	fake.setCloseAdminOptionsMutex.Lock()
+	defer fake.setCloseAdminOptionsMutex.Unlock()
+	fake.SetCloseAdminOptionsStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetCloseAdminOptionsArgsForCall(i int) *types.AdminActionOptions {
+	fake.setCloseAdminOptionsMutex.RLock()
+	defer fake.setCloseAdminOptionsMutex.RUnlock()
+	argsForCall := fake.setCloseAdminOptionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) SetICEConfig(arg1 *livekit.ICEConfig) {
 	fake.setICEConfigMutex.Lock()
 	fake.setICEConfigArgsForCall = append(fake.setICEConfigArgsForCall, struct {
@@ -5013,6 +7283,68 @@ func (fake *FakeLocalParticipant) SetICEConfigArgsForCall(i int) *livekit.ICECon
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) SetMaxSpatialLayerForSource(arg1 livekit.TrackSource, arg2 int32) map[livekit.TrackID]int32 {
+	fake.setMaxSpatialLayerForSourceMutex.Lock()
+	ret, specificReturn := fake.setMaxSpatialLayerForSourceReturnsOnCall[len(fake.setMaxSpatialLayerForSourceArgsForCall)]
+	fake.setMaxSpatialLayerForSourceArgsForCall = append(fake.setMaxSpatialLayerForSourceArgsForCall, struct {
+		arg1 livekit.TrackSource
+		arg2 int32
+	}{arg1, arg2})
+	stub := fake.SetMaxSpatialLayerForSourceStub
+	fakeReturns := fake.setMaxSpatialLayerForSourceReturns
+	fake.recordInvocation("SetMaxSpatialLayerForSource", []interface{}{arg1, arg2})
+	fake.setMaxSpatialLayerForSourceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) SetMaxSpatialLayerForSourceCallCount() int {
+	fake.setMaxSpatialLayerForSourceMutex.RLock()
+	defer fake.setMaxSpatialLayerForSourceMutex.RUnlock()
+	return len(fake.setMaxSpatialLayerForSourceArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetMaxSpatialLayerForSourceCalls(stub func(livekit.TrackSource, int32) map[livekit.TrackID]int32) {
+	fake.setMaxSpatialLayerForSourceMutex.Lock()
+	defer fake.setMaxSpatialLayerForSourceMutex.Unlock()
+	fake.SetMaxSpatialLayerForSourceStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetMaxSpatialLayerForSourceArgsForCall(i int) (livekit.TrackSource, int32) {
+	fake.setMaxSpatialLayerForSourceMutex.RLock()
+	defer fake.setMaxSpatialLayerForSourceMutex.RUnlock()
+	argsForCall := fake.setMaxSpatialLayerForSourceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLocalParticipant) SetMaxSpatialLayerForSourceReturns(result1 map[livekit.TrackID]int32) {
+	fake.setMaxSpatialLayerForSourceMutex.Lock()
+	defer fake.setMaxSpatialLayerForSourceMutex.Unlock()
+	fake.SetMaxSpatialLayerForSourceStub = nil
+	fake.setMaxSpatialLayerForSourceReturns = struct {
+		result1 map[livekit.TrackID]int32
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) SetMaxSpatialLayerForSourceReturnsOnCall(i int, result1 map[livekit.TrackID]int32) {
+	fake.setMaxSpatialLayerForSourceMutex.Lock()
+	defer fake.setMaxSpatialLayerForSourceMutex.Unlock()
+	fake.SetMaxSpatialLayerForSourceStub = nil
+	if fake.setMaxSpatialLayerForSourceReturnsOnCall == nil {
+		fake.setMaxSpatialLayerForSourceReturnsOnCall = make(map[int]struct {
+			result1 map[livekit.TrackID]int32
+		})
+	}
+	fake.setMaxSpatialLayerForSourceReturnsOnCall[i] = struct {
+		result1 map[livekit.TrackID]int32
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) SetMetadata(arg1 string) {
 	fake.setMetadataMutex.Lock()
 	fake.setMetadataArgsForCall = append(fake.setMetadataArgsForCall, struct {
@@ -5045,7 +7377,7 @@ func (fake *FakeLocalParticipant) SetMetadataArgsForCall(i int) string {
 	return argsForCall.arg1
 }
 
-func (fake *FakeLocalParticipant) SetMigrateInfo(arg1 *webrtc.SessionDescription, arg2 *webrtc.SessionDescription, arg3 []*livekit.TrackPublishedResponse, arg4 []*livekit.DataChannelInfo) {
+func (fake *FakeLocalParticipant) SetMigrateInfo(arg1 *webrtc.SessionDescription, arg2 *webrtc.SessionDescription, arg3 []*livekit.TrackPublishedResponse, arg4 []*livekit.DataChannelInfo, arg5 map[string]bool) {
 	var arg3Copy []*livekit.TrackPublishedResponse
 	if arg3 != nil {
 		arg3Copy = make([]*livekit.TrackPublishedResponse, len(arg3))
@@ -5062,12 +7394,13 @@ func (fake *FakeLocalParticipant) SetMigrateInfo(arg1 *webrtc.SessionDescription
 		arg2 *webrtc.SessionDescription
 		arg3 []*livekit.TrackPublishedResponse
 		arg4 []*livekit.DataChannelInfo
-	}{arg1, arg2, arg3Copy, arg4Copy})
+		arg5 map[string]bool
+	}{arg1, arg2, arg3Copy, arg4Copy, arg5})
 	stub := fake.SetMigrateInfoStub
-	fake.recordInvocation("SetMigrateInfo", []interface{}{arg1, arg2, arg3Copy, arg4Copy})
+	fake.recordInvocation("SetMigrateInfo", []interface{}{arg1, arg2, arg3Copy, arg4Copy, arg5})
 	fake.setMigrateInfoMutex.Unlock()
 	if stub != nil {
-		fake.SetMigrateInfoStub(arg1, arg2, arg3, arg4)
+		fake.SetMigrateInfoStub(arg1, arg2, arg3, arg4, arg5)
 	}
 }
 
@@ -5077,17 +7410,17 @@ func (fake *FakeLocalParticipant) SetMigrateInfoCallCount() int {
 	return len(fake.setMigrateInfoArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) SetMigrateInfoCalls(stub func(*webrtc.SessionDescription, *webrtc.SessionDescription, []*livekit.TrackPublishedResponse, []*livekit.DataChannelInfo)) {
+func (fake *FakeLocalParticipant) SetMigrateInfoCalls(stub func(*webrtc.SessionDescription, *webrtc.SessionDescription, []*livekit.TrackPublishedResponse, []*livekit.DataChannelInfo, map[string]bool)) {
 	fake.setMigrateInfoMutex.Lock()
 	defer fake.setMigrateInfoMutex.Unlock()
 	fake.SetMigrateInfoStub = stub
 }
 
-func (fake *FakeLocalParticipant) SetMigrateInfoArgsForCall(i int) (*webrtc.SessionDescription, *webrtc.SessionDescription, []*livekit.TrackPublishedResponse, []*livekit.DataChannelInfo) {
+func (fake *FakeLocalParticipant) SetMigrateInfoArgsForCall(i int) (*webrtc.SessionDescription, *webrtc.SessionDescription, []*livekit.TrackPublishedResponse, []*livekit.DataChannelInfo, map[string]bool) {
 	fake.setMigrateInfoMutex.RLock()
 	defer fake.setMigrateInfoMutex.RUnlock()
 	argsForCall := fake.setMigrateInfoArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeLocalParticipant) SetMigrateState(arg1 types.MigrateState) {
@@ -5154,18 +7487,83 @@ func (fake *FakeLocalParticipant) SetNameArgsForCall(i int) string {
 	return argsForCall.arg1
 }
 
-func (fake *FakeLocalParticipant) SetPermission(arg1 *livekit.ParticipantPermission) bool {
+func (fake *FakeLocalParticipant) SetPacerBurstLimit(arg1 int) {
+	fake.setPacerBurstLimitMutex.Lock()
+	fake.setPacerBurstLimitArgsForCall = append(fake.setPacerBurstLimitArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.SetPacerBurstLimitStub
+	fake.recordInvocation("SetPacerBurstLimit", []interface{}{arg1})
+	fake.setPacerBurstLimitMutex.Unlock()
+	if stub != nil {
+		fake.SetPacerBurstLimitStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetPacerBurstLimitCallCount() int {
+	fake.setPacerBurstLimitMutex.RLock()
+	defer fake.setPacerBurstLimitMutex.RUnlock()
+	return len(fake.setPacerBurstLimitArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetPacerBurstLimitCalls(stub func(int)) {
+	fake.setPacerBurstLimitMutex.Lock()
+	defer fake.setPacerBurstLimitMutex.Unlock()
+	fake.SetPacerBurstLimitStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetPacerBurstLimitArgsForCall(i int) int {
+	fake.setPacerBurstLimitMutex.RLock()
+	defer fake.setPacerBurstLimitMutex.RUnlock()
+	argsForCall := fake.setPacerBurstLimitArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) SetPacerInterval(arg1 time.Duration) {
+	fake.setPacerIntervalMutex.Lock()
+	fake.setPacerIntervalArgsForCall = append(fake.setPacerIntervalArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.SetPacerIntervalStub
+	fake.recordInvocation("SetPacerInterval", []interface{}{arg1})
+	fake.setPacerIntervalMutex.Unlock()
+	if stub != nil {
+		fake.SetPacerIntervalStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetPacerIntervalCallCount() int {
+	fake.setPacerIntervalMutex.RLock()
+	defer fake.setPacerIntervalMutex.RUnlock()
+	return len(fake.setPacerIntervalArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetPacerIntervalCalls(stub func(time.Duration)) {
+	fake.setPacerIntervalMutex.Lock()
+	defer fake.setPacerIntervalMutex.Unlock()
+	fake.SetPacerIntervalStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetPacerIntervalArgsForCall(i int) time.Duration {
+	fake.setPacerIntervalMutex.RLock()
+	defer fake.setPacerIntervalMutex.RUnlock()
+	argsForCall := fake.setPacerIntervalArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) SetPermission(arg1 *livekit.ParticipantPermission, arg2 *types.AdminActionOptions) bool {
 	fake.setPermissionMutex.Lock()
 	ret, specificReturn := fake.setPermissionReturnsOnCall[len(fake.setPermissionArgsForCall)]
 	fake.setPermissionArgsForCall = append(fake.setPermissionArgsForCall, struct {
 		arg1 *livekit.ParticipantPermission
-	}{arg1})
+		arg2 *types.AdminActionOptions
+	}{arg1, arg2})
 	stub := fake.SetPermissionStub
 	fakeReturns := fake.setPermissionReturns
-	fake.recordInvocation("SetPermission", []interface{}{arg1})
+	fake.recordInvocation("SetPermission", []interface{}{arg1, arg2})
 	fake.setPermissionMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -5179,17 +7577,17 @@ func (fake *FakeLocalParticipant) SetPermissionCallCount() int {
 	return len(fake.setPermissionArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) SetPermissionCalls(stub func(*livekit.ParticipantPermission) bool) {
+func (fake *FakeLocalParticipant) SetPermissionCalls(stub func(*livekit.ParticipantPermission, *types.AdminActionOptions) bool) {
 	fake.setPermissionMutex.Lock()
 	defer fake.setPermissionMutex.Unlock()
 	fake.SetPermissionStub = stub
 }
 
-func (fake *FakeLocalParticipant) SetPermissionArgsForCall(i int) *livekit.ParticipantPermission {
+func (fake *FakeLocalParticipant) SetPermissionArgsForCall(i int) (*livekit.ParticipantPermission, *types.AdminActionOptions) {
 	fake.setPermissionMutex.RLock()
 	defer fake.setPermissionMutex.RUnlock()
 	argsForCall := fake.setPermissionArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeLocalParticipant) SetPermissionReturns(result1 bool) {
@@ -5311,6 +7709,38 @@ func (fake *FakeLocalParticipant) SetSubscriberAllowPauseArgsForCall(i int) bool
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) SetSubscriberAudioOnly(arg1 bool) {
+	fake.setSubscriberAudioOnlyMutex.Lock()
+	fake.setSubscriberAudioOnlyArgsForCall = append(fake.setSubscriberAudioOnlyArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetSubscriberAudioOnlyStub
+	fake.recordInvocation("SetSubscriberAudioOnly", []interface{}{arg1})
+	fake.setSubscriberAudioOnlyMutex.Unlock()
+	if stub != nil {
+		fake.SetSubscriberAudioOnlyStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetSubscriberAudioOnlyCallCount() int {
+	fake.setSubscriberAudioOnlyMutex.RLock()
+	defer fake.setSubscriberAudioOnlyMutex.RUnlock()
+	return len(fake.setSubscriberAudioOnlyArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetSubscriberAudioOnlyCalls(stub func(bool)) {
+	fake.setSubscriberAudioOnlyMutex.Lock()
+	defer fake.setSubscriberAudioOnlyMutex.Unlock()
+	fake.SetSubscriberAudioOnlyStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetSubscriberAudioOnlyArgsForCall(i int) bool {
+	fake.setSubscriberAudioOnlyMutex.RLock()
+	defer fake.setSubscriberAudioOnlyMutex.RUnlock()
+	argsForCall := fake.setSubscriberAudioOnlyArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) SetSubscriberChannelCapacity(arg1 int64) {
 	fake.setSubscriberChannelCapacityMutex.Lock()
 	fake.setSubscriberChannelCapacityArgsForCall = append(fake.setSubscriberChannelCapacityArgsForCall, struct {
@@ -5343,13 +7773,45 @@ func (fake *FakeLocalParticipant) SetSubscriberChannelCapacityArgsForCall(i int)
 	return argsForCall.arg1
 }
 
-func (fake *FakeLocalParticipant) SetTrackMuted(arg1 livekit.TrackID, arg2 bool, arg3 bool) *livekit.TrackInfo {
+func (fake *FakeLocalParticipant) SetSubscriberPLIThrottle(arg1 time.Duration) {
+	fake.setSubscriberPLIThrottleMutex.Lock()
+	fake.setSubscriberPLIThrottleArgsForCall = append(fake.setSubscriberPLIThrottleArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.SetSubscriberPLIThrottleStub
+	fake.recordInvocation("SetSubscriberPLIThrottle", []interface{}{arg1})
+	fake.setSubscriberPLIThrottleMutex.Unlock()
+	if stub != nil {
+		fake.SetSubscriberPLIThrottleStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetSubscriberPLIThrottleCallCount() int {
+	fake.setSubscriberPLIThrottleMutex.RLock()
+	defer fake.setSubscriberPLIThrottleMutex.RUnlock()
+	return len(fake.setSubscriberPLIThrottleArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetSubscriberPLIThrottleCalls(stub func(time.Duration)) {
+	fake.setSubscriberPLIThrottleMutex.Lock()
+	defer fake.setSubscriberPLIThrottleMutex.Unlock()
+	fake.SetSubscriberPLIThrottleStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetSubscriberPLIThrottleArgsForCall(i int) time.Duration {
+	fake.setSubscriberPLIThrottleMutex.RLock()
+	defer fake.setSubscriberPLIThrottleMutex.RUnlock()
+	argsForCall := fake.setSubscriberPLIThrottleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) SetTrackMuted(arg1 livekit.TrackID, arg2 bool, arg3 *types.AdminActionOptions) *livekit.TrackInfo {
 	fake.setTrackMutedMutex.Lock()
 	ret, specificReturn := fake.setTrackMutedReturnsOnCall[len(fake.setTrackMutedArgsForCall)]
 	fake.setTrackMutedArgsForCall = append(fake.setTrackMutedArgsForCall, struct {
 		arg1 livekit.TrackID
 		arg2 bool
-		arg3 bool
+		arg3 *types.AdminActionOptions
 	}{arg1, arg2, arg3})
 	stub := fake.SetTrackMutedStub
 	fakeReturns := fake.setTrackMutedReturns
@@ -5370,13 +7832,13 @@ func (fake *FakeLocalParticipant) SetTrackMutedCallCount() int {
 	return len(fake.setTrackMutedArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) SetTrackMutedCalls(stub func(livekit.TrackID, bool, bool) *livekit.TrackInfo) {
+func (fake *FakeLocalParticipant) SetTrackMutedCalls(stub func(livekit.TrackID, bool, *types.AdminActionOptions) *livekit.TrackInfo) {
 	fake.setTrackMutedMutex.Lock()
 	defer fake.setTrackMutedMutex.Unlock()
 	fake.SetTrackMutedStub = stub
 }
 
-func (fake *FakeLocalParticipant) SetTrackMutedArgsForCall(i int) (livekit.TrackID, bool, bool) {
+func (fake *FakeLocalParticipant) SetTrackMutedArgsForCall(i int) (livekit.TrackID, bool, *types.AdminActionOptions) {
 	fake.setTrackMutedMutex.RLock()
 	defer fake.setTrackMutedMutex.RUnlock()
 	argsForCall := fake.setTrackMutedArgsForCall[i]
@@ -5406,6 +7868,134 @@ func (fake *FakeLocalParticipant) SetTrackMutedReturnsOnCall(i int, result1 *liv
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) SetTrackPaused(arg1 livekit.TrackID, arg2 bool) error {
+	fake.setTrackPausedMutex.Lock()
+	ret, specificReturn := fake.setTrackPausedReturnsOnCall[len(fake.setTrackPausedArgsForCall)]
+	fake.setTrackPausedArgsForCall = append(fake.setTrackPausedArgsForCall, struct {
+		arg1 livekit.TrackID
+		arg2 bool
+	}{arg1, arg2})
+	stub := fake.SetTrackPausedStub
+	fakeReturns := fake.setTrackPausedReturns
+	fake.recordInvocation("SetTrackPaused", []interface{}{arg1, arg2})
+	fake.setTrackPausedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) SetTrackPausedCallCount() int {
+	fake.setTrackPausedMutex.RLock()
+	defer fake.setTrackPausedMutex.RUnlock()
+	return len(fake.setTrackPausedArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetTrackPausedCalls(stub func(livekit.TrackID, bool) error) {
+	fake.setTrackPausedMutex.Lock()
+	defer fake.setTrackPausedMutex.Unlock()
+	fake.SetTrackPausedStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetTrackPausedArgsForCall(i int) (livekit.TrackID, bool) {
+	fake.setTrackPausedMutex.RLock()
+	defer fake.setTrackPausedMutex.RUnlock()
+	argsForCall := fake.setTrackPausedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLocalParticipant) SetTrackPausedReturns(result1 error) {
+	fake.setTrackPausedMutex.Lock()
+	defer fake.setTrackPausedMutex.Unlock()
+	fake.SetTrackPausedStub = nil
+	fake.setTrackPausedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) SetTrackPausedReturnsOnCall(i int, result1 error) {
+	fake.setTrackPausedMutex.Lock()
+	defer fake.setTrackPausedMutex.Unlock()
+	fake.SetTrackPausedStub = nil
+	if fake.setTrackPausedReturnsOnCall == nil {
+		fake.setTrackPausedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setTrackPausedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) SetTrackPlayoutDelay(arg1 livekit.TrackID, arg2 *livekit.PlayoutDelay) {
+	fake.setTrackPlayoutDelayMutex.Lock()
+	fake.setTrackPlayoutDelayArgsForCall = append(fake.setTrackPlayoutDelayArgsForCall, struct {
+		arg1 livekit.TrackID
+		arg2 *livekit.PlayoutDelay
+	}{arg1, arg2})
+	stub := fake.SetTrackPlayoutDelayStub
+	fake.recordInvocation("SetTrackPlayoutDelay", []interface{}{arg1, arg2})
+	fake.setTrackPlayoutDelayMutex.Unlock()
+	if stub != nil {
+		fake.SetTrackPlayoutDelayStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetTrackPlayoutDelayCallCount() int {
+	fake.setTrackPlayoutDelayMutex.RLock()
+	defer fake.setTrackPlayoutDelayMutex.RUnlock()
+	return len(fake.setTrackPlayoutDelayArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetTrackPlayoutDelayCalls(stub func(livekit.TrackID, *livekit.PlayoutDelay)) {
+	fake.setTrackPlayoutDelayMutex.Lock()
+	defer fake.setTrackPlayoutDelayMutex.Unlock()
+	fake.SetTrackPlayoutDelayStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetTrackPlayoutDelayArgsForCall(i int) (livekit.TrackID, *livekit.PlayoutDelay) {
+	fake.setTrackPlayoutDelayMutex.RLock()
+	defer fake.setTrackPlayoutDelayMutex.RUnlock()
+	argsForCall := fake.setTrackPlayoutDelayArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLocalParticipant) SetTrackVisibility(arg1 livekit.TrackID, arg2 *types.TrackVisibility) {
+	fake.setTrackVisibilityMutex.Lock()
+	fake.setTrackVisibilityArgsForCall = append(fake.setTrackVisibilityArgsForCall, struct {
+		arg1 livekit.TrackID
+		arg2 *types.TrackVisibility
+	}{arg1, arg2})
+	stub := fake.SetTrackVisibilityStub
+	fake.recordInvocation("SetTrackVisibility", []interface{}{arg1, arg2})
+	fake.setTrackVisibilityMutex.Unlock()
+	if stub != nil {
+		fake.SetTrackVisibilityStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetTrackVisibilityCallCount() int {
+	fake.setTrackVisibilityMutex.RLock()
+	defer fake.setTrackVisibilityMutex.RUnlock()
+	return len(fake.setTrackVisibilityArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetTrackVisibilityCalls(stub func(livekit.TrackID, *types.TrackVisibility)) {
+	fake.setTrackVisibilityMutex.Lock()
+	defer fake.setTrackVisibilityMutex.Unlock()
+	fake.SetTrackVisibilityStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetTrackVisibilityArgsForCall(i int) (livekit.TrackID, *types.TrackVisibility) {
+	fake.setTrackVisibilityMutex.RLock()
+	defer fake.setTrackVisibilityMutex.RUnlock()
+	argsForCall := fake.setTrackVisibilityArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeLocalParticipant) State() livekit.ParticipantInfo_State {
 	fake.stateMutex.Lock()
 	ret, specificReturn := fake.stateReturnsOnCall[len(fake.stateArgsForCall)]
@@ -5849,6 +8439,59 @@ func (fake *FakeLocalParticipant) ToProtoWithVersionReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeLocalParticipant) UnacknowledgedReliableData() [][]byte {
+	fake.unacknowledgedReliableDataMutex.Lock()
+	ret, specificReturn := fake.unacknowledgedReliableDataReturnsOnCall[len(fake.unacknowledgedReliableDataArgsForCall)]
+	fake.unacknowledgedReliableDataArgsForCall = append(fake.unacknowledgedReliableDataArgsForCall, struct {
+	}{})
+	stub := fake.UnacknowledgedReliableDataStub
+	fakeReturns := fake.unacknowledgedReliableDataReturns
+	fake.recordInvocation("UnacknowledgedReliableData", []interface{}{})
+	fake.unacknowledgedReliableDataMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) UnacknowledgedReliableDataCallCount() int {
+	fake.unacknowledgedReliableDataMutex.RLock()
+	defer fake.unacknowledgedReliableDataMutex.RUnlock()
+	return len(fake.unacknowledgedReliableDataArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) UnacknowledgedReliableDataCalls(stub func() [][]byte) {
+	fake.unacknowledgedReliableDataMutex.Lock()
+	defer fake.unacknowledgedReliableDataMutex.Unlock()
+	fake.UnacknowledgedReliableDataStub = stub
+}
+
+func (fake *FakeLocalParticipant) UnacknowledgedReliableDataReturns(result1 [][]byte) {
+	fake.unacknowledgedReliableDataMutex.Lock()
+	defer fake.unacknowledgedReliableDataMutex.Unlock()
+	fake.UnacknowledgedReliableDataStub = nil
+	fake.unacknowledgedReliableDataReturns = struct {
+		result1 [][]byte
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) UnacknowledgedReliableDataReturnsOnCall(i int, result1 [][]byte) {
+	fake.unacknowledgedReliableDataMutex.Lock()
+	defer fake.unacknowledgedReliableDataMutex.Unlock()
+	fake.UnacknowledgedReliableDataStub = nil
+	if fake.unacknowledgedReliableDataReturnsOnCall == nil {
+		fake.unacknowledgedReliableDataReturnsOnCall = make(map[int]struct {
+			result1 [][]byte
+		})
+	}
+	fake.unacknowledgedReliableDataReturnsOnCall[i] = struct {
+		result1 [][]byte
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) UncacheDownTrack(arg1 *webrtc.RTPTransceiver) {
 	fake.uncacheDownTrackMutex.Lock()
 	fake.uncacheDownTrackArgsForCall = append(fake.uncacheDownTrackArgsForCall, struct {
@@ -6226,6 +8869,39 @@ func (fake *FakeLocalParticipant) UpdateSubscribedTrackSettingsArgsForCall(i int
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeLocalParticipant) UpdateSubscriptionLimits(arg1 int32, arg2 int32) {
+	fake.updateSubscriptionLimitsMutex.Lock()
+	fake.updateSubscriptionLimitsArgsForCall = append(fake.updateSubscriptionLimitsArgsForCall, struct {
+		arg1 int32
+		arg2 int32
+	}{arg1, arg2})
+	stub := fake.UpdateSubscriptionLimitsStub
+	fake.recordInvocation("UpdateSubscriptionLimits", []interface{}{arg1, arg2})
+	fake.updateSubscriptionLimitsMutex.Unlock()
+	if stub != nil {
+		fake.UpdateSubscriptionLimitsStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionLimitsCallCount() int {
+	fake.updateSubscriptionLimitsMutex.RLock()
+	defer fake.updateSubscriptionLimitsMutex.RUnlock()
+	return len(fake.updateSubscriptionLimitsArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionLimitsCalls(stub func(int32, int32)) {
+	fake.updateSubscriptionLimitsMutex.Lock()
+	defer fake.updateSubscriptionLimitsMutex.Unlock()
+	fake.UpdateSubscriptionLimitsStub = stub
+}
+
+func (fake *FakeLocalParticipant) UpdateSubscriptionLimitsArgsForCall(i int) (int32, int32) {
+	fake.updateSubscriptionLimitsMutex.RLock()
+	defer fake.updateSubscriptionLimitsMutex.RUnlock()
+	argsForCall := fake.updateSubscriptionLimitsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeLocalParticipant) UpdateSubscriptionPermission(arg1 *livekit.SubscriptionPermission, arg2 utils.TimedVersion, arg3 func(participantID livekit.ParticipantID) types.LocalParticipant) error {
 	fake.updateSubscriptionPermissionMutex.Lock()
 	ret, specificReturn := fake.updateSubscriptionPermissionReturnsOnCall[len(fake.updateSubscriptionPermissionArgsForCall)]
@@ -6513,6 +9189,8 @@ func (fake *FakeLocalParticipant) WriteSubscriberRTCPReturnsOnCall(i int, result
 func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.abortMigrationMutex.RLock()
+	defer fake.abortMigrationMutex.RUnlock()
 	fake.addICECandidateMutex.RLock()
 	defer fake.addICECandidateMutex.RUnlock()
 	fake.addTrackMutex.RLock()
@@ -6533,6 +9211,8 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.canSubscribeMutex.RUnlock()
 	fake.claimGrantsMutex.RLock()
 	defer fake.claimGrantsMutex.RUnlock()
+	fake.clearResumedDownTrackMutex.RLock()
+	defer fake.clearResumedDownTrackMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
 	fake.closeReasonMutex.RLock()
@@ -6541,14 +9221,26 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.closeSignalConnectionMutex.RUnlock()
 	fake.connectedAtMutex.RLock()
 	defer fake.connectedAtMutex.RUnlock()
+	fake.dataChannelBufferedAmountMutex.RLock()
+	defer fake.dataChannelBufferedAmountMutex.RUnlock()
 	fake.debugInfoMutex.RLock()
 	defer fake.debugInfoMutex.RUnlock()
 	fake.disconnectedMutex.RLock()
 	defer fake.disconnectedMutex.RUnlock()
+	fake.forceCompleteMigrationMutex.RLock()
+	defer fake.forceCompleteMigrationMutex.RUnlock()
+	fake.forceSubscriberRenegotiationMutex.RLock()
+	defer fake.forceSubscriberRenegotiationMutex.RUnlock()
 	fake.getAdaptiveStreamMutex.RLock()
 	defer fake.getAdaptiveStreamMutex.RUnlock()
+	fake.getAdminAuditLogMutex.RLock()
+	defer fake.getAdminAuditLogMutex.RUnlock()
+	fake.getAllocationPreferenceMutex.RLock()
+	defer fake.getAllocationPreferenceMutex.RUnlock()
 	fake.getAudioLevelMutex.RLock()
 	defer fake.getAudioLevelMutex.RUnlock()
+	fake.getBitrateSummaryMutex.RLock()
+	defer fake.getBitrateSummaryMutex.RUnlock()
 	fake.getBufferFactoryMutex.RLock()
 	defer fake.getBufferFactoryMutex.RUnlock()
 	fake.getCachedDownTrackMutex.RLock()
@@ -6559,12 +9251,20 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.getClientInfoMutex.RUnlock()
 	fake.getConnectionQualityMutex.RLock()
 	defer fake.getConnectionQualityMutex.RUnlock()
+	fake.getDeniedSubscriptionsMutex.RLock()
+	defer fake.getDeniedSubscriptionsMutex.RUnlock()
 	fake.getICEConnectionDetailsMutex.RLock()
 	defer fake.getICEConnectionDetailsMutex.RUnlock()
+	fake.getLastGoodICEConfigMutex.RLock()
+	defer fake.getLastGoodICEConfigMutex.RUnlock()
 	fake.getLoggerMutex.RLock()
 	defer fake.getLoggerMutex.RUnlock()
 	fake.getPacerMutex.RLock()
 	defer fake.getPacerMutex.RUnlock()
+	fake.getPacerSendRateMutex.RLock()
+	defer fake.getPacerSendRateMutex.RUnlock()
+	fake.getPacerSettingsMutex.RLock()
+	defer fake.getPacerSettingsMutex.RUnlock()
 	fake.getPendingTrackMutex.RLock()
 	defer fake.getPendingTrackMutex.RUnlock()
 	fake.getPlayoutDelayConfigMutex.RLock()
@@ -6573,16 +9273,34 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.getPublishedTrackMutex.RUnlock()
 	fake.getPublishedTracksMutex.RLock()
 	defer fake.getPublishedTracksMutex.RUnlock()
+	fake.getRTTHistoryMutex.RLock()
+	defer fake.getRTTHistoryMutex.RUnlock()
+	fake.getReconnectCountMutex.RLock()
+	defer fake.getReconnectCountMutex.RUnlock()
+	fake.getResumedDownTrackMutex.RLock()
+	defer fake.getResumedDownTrackMutex.RUnlock()
+	fake.getSignalingRTTMutex.RLock()
+	defer fake.getSignalingRTTMutex.RUnlock()
 	fake.getSubscribedParticipantsMutex.RLock()
 	defer fake.getSubscribedParticipantsMutex.RUnlock()
 	fake.getSubscribedTracksMutex.RLock()
 	defer fake.getSubscribedTracksMutex.RUnlock()
+	fake.getSubscriberPLIThrottleMutex.RLock()
+	defer fake.getSubscriberPLIThrottleMutex.RUnlock()
+	fake.getSubscriberReportedLossMutex.RLock()
+	defer fake.getSubscriberReportedLossMutex.RUnlock()
+	fake.getTemporalLayerDistributionMutex.RLock()
+	defer fake.getTemporalLayerDistributionMutex.RUnlock()
 	fake.getTrafficLoadMutex.RLock()
 	defer fake.getTrafficLoadMutex.RUnlock()
 	fake.getTrailerMutex.RLock()
 	defer fake.getTrailerMutex.RUnlock()
+	fake.getVideoAllocationMutex.RLock()
+	defer fake.getVideoAllocationMutex.RUnlock()
 	fake.handleAnswerMutex.RLock()
 	defer fake.handleAnswerMutex.RUnlock()
+	fake.handleDecodeFeedbackMutex.RLock()
+	defer fake.handleDecodeFeedbackMutex.RUnlock()
 	fake.handleOfferMutex.RLock()
 	defer fake.handleOfferMutex.RUnlock()
 	fake.handleReceiverReportMutex.RLock()
@@ -6591,6 +9309,12 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.handleReconnectAndSendResponseMutex.RUnlock()
 	fake.handleSignalSourceCloseMutex.RLock()
 	defer fake.handleSignalSourceCloseMutex.RUnlock()
+	fake.handleSubscriberAudioOnlyRequestMutex.RLock()
+	defer fake.handleSubscriberAudioOnlyRequestMutex.RUnlock()
+	fake.handleTrackPauseRequestMutex.RLock()
+	defer fake.handleTrackPauseRequestMutex.RUnlock()
+	fake.hasActiveMediaMutex.RLock()
+	defer fake.hasActiveMediaMutex.RUnlock()
 	fake.hasConnectedMutex.RLock()
 	defer fake.hasConnectedMutex.RUnlock()
 	fake.hasPermissionMutex.RLock()
@@ -6605,6 +9329,8 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.identityMutex.RUnlock()
 	fake.isClosedMutex.RLock()
 	defer fake.isClosedMutex.RUnlock()
+	fake.isDataChannelBackpressuredMutex.RLock()
+	defer fake.isDataChannelBackpressuredMutex.RUnlock()
 	fake.isDependentMutex.RLock()
 	defer fake.isDependentMutex.RUnlock()
 	fake.isDisconnectedMutex.RLock()
@@ -6623,6 +9349,8 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.issueFullReconnectMutex.RUnlock()
 	fake.kindMutex.RLock()
 	defer fake.kindMutex.RUnlock()
+	fake.marshalResumeStateMutex.RLock()
+	defer fake.marshalResumeStateMutex.RUnlock()
 	fake.maybeStartMigrationMutex.RLock()
 	defer fake.maybeStartMigrationMutex.RUnlock()
 	fake.migrateStateMutex.RLock()
@@ -6631,22 +9359,32 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.negotiateMutex.RUnlock()
 	fake.notifyMigrationMutex.RLock()
 	defer fake.notifyMigrationMutex.RUnlock()
+	fake.onAvailableLayersChangedMutex.RLock()
+	defer fake.onAvailableLayersChangedMutex.RUnlock()
 	fake.onClaimsChangedMutex.RLock()
 	defer fake.onClaimsChangedMutex.RUnlock()
 	fake.onCloseMutex.RLock()
 	defer fake.onCloseMutex.RUnlock()
+	fake.onConnectionQualityChangedMutex.RLock()
+	defer fake.onConnectionQualityChangedMutex.RUnlock()
+	fake.onDataChannelBufferedAmountLowMutex.RLock()
+	defer fake.onDataChannelBufferedAmountLowMutex.RUnlock()
 	fake.onDataPacketMutex.RLock()
 	defer fake.onDataPacketMutex.RUnlock()
 	fake.onICEConfigChangedMutex.RLock()
 	defer fake.onICEConfigChangedMutex.RUnlock()
 	fake.onMigrateStateChangeMutex.RLock()
 	defer fake.onMigrateStateChangeMutex.RUnlock()
+	fake.onMigrationTimeoutMutex.RLock()
+	defer fake.onMigrationTimeoutMutex.RUnlock()
 	fake.onParticipantUpdateMutex.RLock()
 	defer fake.onParticipantUpdateMutex.RUnlock()
 	fake.onStateChangeMutex.RLock()
 	defer fake.onStateChangeMutex.RUnlock()
 	fake.onSubscribeStatusChangedMutex.RLock()
 	defer fake.onSubscribeStatusChangedMutex.RUnlock()
+	fake.onTrackMuteChangedMutex.RLock()
+	defer fake.onTrackMuteChangedMutex.RUnlock()
 	fake.onTrackPublishedMutex.RLock()
 	defer fake.onTrackPublishedMutex.RUnlock()
 	fake.onTrackUnpublishedMutex.RLock()
@@ -6655,12 +9393,20 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.onTrackUpdatedMutex.RUnlock()
 	fake.onTrafficLoadMutex.RLock()
 	defer fake.onTrafficLoadMutex.RUnlock()
+	fake.pauseTrackForwardingMutex.RLock()
+	defer fake.pauseTrackForwardingMutex.RUnlock()
 	fake.protocolVersionMutex.RLock()
 	defer fake.protocolVersionMutex.RUnlock()
 	fake.removePublishedTrackMutex.RLock()
 	defer fake.removePublishedTrackMutex.RUnlock()
 	fake.removeTrackFromSubscriberMutex.RLock()
 	defer fake.removeTrackFromSubscriberMutex.RUnlock()
+	fake.requestKeyFrameMutex.RLock()
+	defer fake.requestKeyFrameMutex.RUnlock()
+	fake.resetReconnectCountMutex.RLock()
+	defer fake.resetReconnectCountMutex.RUnlock()
+	fake.resumeTrackForwardingMutex.RLock()
+	defer fake.resumeTrackForwardingMutex.RUnlock()
 	fake.sendConnectionQualityUpdateMutex.RLock()
 	defer fake.sendConnectionQualityUpdateMutex.RUnlock()
 	fake.sendDataPacketMutex.RLock()
@@ -6675,8 +9421,16 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.sendRoomUpdateMutex.RUnlock()
 	fake.sendSpeakerUpdateMutex.RLock()
 	defer fake.sendSpeakerUpdateMutex.RUnlock()
+	fake.setAllTracksMutedMutex.RLock()
+	defer fake.setAllTracksMutedMutex.RUnlock()
+	fake.setBandwidthHintMutex.RLock()
+	defer fake.setBandwidthHintMutex.RUnlock()
+	fake.setCloseAdminOptionsMutex.RLock()
+	defer fake.setCloseAdminOptionsMutex.RUnlock()
 	fake.setICEConfigMutex.RLock()
 	defer fake.setICEConfigMutex.RUnlock()
+	fake.setMaxSpatialLayerForSourceMutex.RLock()
+	defer fake.setMaxSpatialLayerForSourceMutex.RUnlock()
 	fake.setMetadataMutex.RLock()
 	defer fake.setMetadataMutex.RUnlock()
 	fake.setMigrateInfoMutex.RLock()
@@ -6685,6 +9439,10 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.setMigrateStateMutex.RUnlock()
 	fake.setNameMutex.RLock()
 	defer fake.setNameMutex.RUnlock()
+	fake.setPacerBurstLimitMutex.RLock()
+	defer fake.setPacerBurstLimitMutex.RUnlock()
+	fake.setPacerIntervalMutex.RLock()
+	defer fake.setPacerIntervalMutex.RUnlock()
 	fake.setPermissionMutex.RLock()
 	defer fake.setPermissionMutex.RUnlock()
 	fake.setResponseSinkMutex.RLock()
@@ -6693,10 +9451,20 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.setSignalSourceValidMutex.RUnlock()
 	fake.setSubscriberAllowPauseMutex.RLock()
 	defer fake.setSubscriberAllowPauseMutex.RUnlock()
+	fake.setSubscriberAudioOnlyMutex.RLock()
+	defer fake.setSubscriberAudioOnlyMutex.RUnlock()
 	fake.setSubscriberChannelCapacityMutex.RLock()
 	defer fake.setSubscriberChannelCapacityMutex.RUnlock()
+	fake.setSubscriberPLIThrottleMutex.RLock()
+	defer fake.setSubscriberPLIThrottleMutex.RUnlock()
 	fake.setTrackMutedMutex.RLock()
 	defer fake.setTrackMutedMutex.RUnlock()
+	fake.setTrackPausedMutex.RLock()
+	defer fake.setTrackPausedMutex.RUnlock()
+	fake.setTrackPlayoutDelayMutex.RLock()
+	defer fake.setTrackPlayoutDelayMutex.RUnlock()
+	fake.setTrackVisibilityMutex.RLock()
+	defer fake.setTrackVisibilityMutex.RUnlock()
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
 	fake.subscribeToTrackMutex.RLock()
@@ -6715,6 +9483,8 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.toProtoMutex.RUnlock()
 	fake.toProtoWithVersionMutex.RLock()
 	defer fake.toProtoWithVersionMutex.RUnlock()
+	fake.unacknowledgedReliableDataMutex.RLock()
+	defer fake.unacknowledgedReliableDataMutex.RUnlock()
 	fake.uncacheDownTrackMutex.RLock()
 	defer fake.uncacheDownTrackMutex.RUnlock()
 	fake.unsubscribeFromTrackMutex.RLock()
@@ -6733,6 +9503,8 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.updateSubscribedQualityMutex.RUnlock()
 	fake.updateSubscribedTrackSettingsMutex.RLock()
 	defer fake.updateSubscribedTrackSettingsMutex.RUnlock()
+	fake.updateSubscriptionLimitsMutex.RLock()
+	defer fake.updateSubscriptionLimitsMutex.RUnlock()
 	fake.updateSubscriptionPermissionMutex.RLock()
 	defer fake.updateSubscriptionPermissionMutex.RUnlock()
 	fake.updateVideoTrackMutex.RLock()
@@ -135,6 +135,16 @@ type FakeMediaTrack struct {
 	isOpenReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsPausedStub        func() bool
+	isPausedMutex       sync.RWMutex
+	isPausedArgsForCall []struct {
+	}
+	isPausedReturns struct {
+		result1 bool
+	}
+	isPausedReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsSimulcastStub        func() bool
 	isSimulcastMutex       sync.RWMutex
 	isSimulcastArgsForCall []struct {
@@ -238,6 +248,11 @@ type FakeMediaTrack struct {
 	setMutedArgsForCall []struct {
 		arg1 bool
 	}
+	SetPausedStub        func(bool)
+	setPausedMutex       sync.RWMutex
+	setPausedArgsForCall []struct {
+		arg1 bool
+	}
 	SourceStub        func() livekit.TrackSource
 	sourceMutex       sync.RWMutex
 	sourceArgsForCall []struct {
@@ -258,6 +273,17 @@ type FakeMediaTrack struct {
 	streamReturnsOnCall map[int]struct {
 		result1 string
 	}
+	SupportsTemporalLayersStub        func(string) bool
+	supportsTemporalLayersMutex       sync.RWMutex
+	supportsTemporalLayersArgsForCall []struct {
+		arg1 string
+	}
+	supportsTemporalLayersReturns struct {
+		result1 bool
+	}
+	supportsTemporalLayersReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ToProtoStub        func() *livekit.TrackInfo
 	toProtoMutex       sync.RWMutex
 	toProtoArgsForCall []struct {
@@ -946,6 +972,59 @@ func (fake *FakeMediaTrack) IsOpenReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeMediaTrack) IsPaused() bool {
+	fake.isPausedMutex.Lock()
+	ret, specificReturn := fake.isPausedReturnsOnCall[len(fake.isPausedArgsForCall)]
+	fake.isPausedArgsForCall = append(fake.isPausedArgsForCall, struct {
+	}{})
+	stub := fake.IsPausedStub
+	fakeReturns := fake.isPausedReturns
+	fake.recordInvocation("IsPaused", []interface{}{})
+	fake.isPausedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMediaTrack) IsPausedCallCount() int {
+	fake.isPausedMutex.RLock()
+	defer fake.isPausedMutex.RUnlock()
+	return len(fake.isPausedArgsForCall)
+}
+
+func (fake *FakeMediaTrack) IsPausedCalls(stub func() bool) {
+	fake.isPausedMutex.Lock()
+	defer fake.isPausedMutex.Unlock()
+	fake.IsPausedStub = stub
+}
+
+func (fake *FakeMediaTrack) IsPausedReturns(result1 bool) {
+	fake.isPausedMutex.Lock()
+	defer fake.isPausedMutex.Unlock()
+	fake.IsPausedStub = nil
+	fake.isPausedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeMediaTrack) IsPausedReturnsOnCall(i int, result1 bool) {
+	fake.isPausedMutex.Lock()
+	defer fake.isPausedMutex.Unlock()
+	fake.IsPausedStub = nil
+	if fake.isPausedReturnsOnCall == nil {
+		fake.isPausedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isPausedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeMediaTrack) IsSimulcast() bool {
 	fake.isSimulcastMutex.Lock()
 	ret, specificReturn := fake.isSimulcastReturnsOnCall[len(fake.isSimulcastArgsForCall)]
@@ -1509,6 +1588,38 @@ func (fake *FakeMediaTrack) SetMutedArgsForCall(i int) bool {
 	return argsForCall.arg1
 }
 
+func (fake *FakeMediaTrack) SetPaused(arg1 bool) {
+	fake.setPausedMutex.Lock()
+	fake.setPausedArgsForCall = append(fake.setPausedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetPausedStub
+	fake.recordInvocation("SetPaused", []interface{}{arg1})
+	fake.setPausedMutex.Unlock()
+	if stub != nil {
+		fake.SetPausedStub(arg1)
+	}
+}
+
+func (fake *FakeMediaTrack) SetPausedCallCount() int {
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
+	return len(fake.setPausedArgsForCall)
+}
+
+func (fake *FakeMediaTrack) SetPausedCalls(stub func(bool)) {
+	fake.setPausedMutex.Lock()
+	defer fake.setPausedMutex.Unlock()
+	fake.SetPausedStub = stub
+}
+
+func (fake *FakeMediaTrack) SetPausedArgsForCall(i int) bool {
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
+	argsForCall := fake.setPausedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeMediaTrack) Source() livekit.TrackSource {
 	fake.sourceMutex.Lock()
 	ret, specificReturn := fake.sourceReturnsOnCall[len(fake.sourceArgsForCall)]
@@ -1615,6 +1726,67 @@ func (fake *FakeMediaTrack) StreamReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeMediaTrack) SupportsTemporalLayers(arg1 string) bool {
+	fake.supportsTemporalLayersMutex.Lock()
+	ret, specificReturn := fake.supportsTemporalLayersReturnsOnCall[len(fake.supportsTemporalLayersArgsForCall)]
+	fake.supportsTemporalLayersArgsForCall = append(fake.supportsTemporalLayersArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SupportsTemporalLayersStub
+	fakeReturns := fake.supportsTemporalLayersReturns
+	fake.recordInvocation("SupportsTemporalLayers", []interface{}{arg1})
+	fake.supportsTemporalLayersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMediaTrack) SupportsTemporalLayersCallCount() int {
+	fake.supportsTemporalLayersMutex.RLock()
+	defer fake.supportsTemporalLayersMutex.RUnlock()
+	return len(fake.supportsTemporalLayersArgsForCall)
+}
+
+func (fake *FakeMediaTrack) SupportsTemporalLayersCalls(stub func(string) bool) {
+	fake.supportsTemporalLayersMutex.Lock()
+	defer fake.supportsTemporalLayersMutex.Unlock()
+	fake.SupportsTemporalLayersStub = stub
+}
+
+func (fake *FakeMediaTrack) SupportsTemporalLayersArgsForCall(i int) string {
+	fake.supportsTemporalLayersMutex.RLock()
+	defer fake.supportsTemporalLayersMutex.RUnlock()
+	argsForCall := fake.supportsTemporalLayersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeMediaTrack) SupportsTemporalLayersReturns(result1 bool) {
+	fake.supportsTemporalLayersMutex.Lock()
+	defer fake.supportsTemporalLayersMutex.Unlock()
+	fake.SupportsTemporalLayersStub = nil
+	fake.supportsTemporalLayersReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeMediaTrack) SupportsTemporalLayersReturnsOnCall(i int, result1 bool) {
+	fake.supportsTemporalLayersMutex.Lock()
+	defer fake.supportsTemporalLayersMutex.Unlock()
+	fake.SupportsTemporalLayersStub = nil
+	if fake.supportsTemporalLayersReturnsOnCall == nil {
+		fake.supportsTemporalLayersReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.supportsTemporalLayersReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeMediaTrack) ToProto() *livekit.TrackInfo {
 	fake.toProtoMutex.Lock()
 	ret, specificReturn := fake.toProtoReturnsOnCall[len(fake.toProtoArgsForCall)]
@@ -1793,6 +1965,8 @@ func (fake *FakeMediaTrack) Invocations() map[string][][]interface{} {
 	defer fake.isMutedMutex.RUnlock()
 	fake.isOpenMutex.RLock()
 	defer fake.isOpenMutex.RUnlock()
+	fake.isPausedMutex.RLock()
+	defer fake.isPausedMutex.RUnlock()
 	fake.isSimulcastMutex.RLock()
 	defer fake.isSimulcastMutex.RUnlock()
 	fake.isSubscriberMutex.RLock()
@@ -1815,10 +1989,14 @@ func (fake *FakeMediaTrack) Invocations() map[string][][]interface{} {
 	defer fake.revokeDisallowedSubscribersMutex.RUnlock()
 	fake.setMutedMutex.RLock()
 	defer fake.setMutedMutex.RUnlock()
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
 	fake.sourceMutex.RLock()
 	defer fake.sourceMutex.RUnlock()
 	fake.streamMutex.RLock()
 	defer fake.streamMutex.RUnlock()
+	fake.supportsTemporalLayersMutex.RLock()
+	defer fake.supportsTemporalLayersMutex.RUnlock()
 	fake.toProtoMutex.RLock()
 	defer fake.toProtoMutex.RUnlock()
 	fake.updateAudioTrackMutex.RLock()
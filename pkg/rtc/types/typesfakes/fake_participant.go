@@ -86,6 +86,16 @@ type FakeParticipant struct {
 	getPublishedTracksReturnsOnCall map[int]struct {
 		result1 []types.MediaTrack
 	}
+	HasActiveMediaStub        func() bool
+	hasActiveMediaMutex       sync.RWMutex
+	hasActiveMediaArgsForCall []struct {
+	}
+	hasActiveMediaReturns struct {
+		result1 bool
+	}
+	hasActiveMediaReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	HasPermissionStub        func(livekit.TrackID, livekit.ParticipantIdentity) bool
 	hasPermissionMutex       sync.RWMutex
 	hasPermissionArgsForCall []struct {
@@ -648,6 +658,59 @@ func (fake *FakeParticipant) GetPublishedTracksReturnsOnCall(i int, result1 []ty
 	}{result1}
 }
 
+func (fake *FakeParticipant) HasActiveMedia() bool {
+	fake.hasActiveMediaMutex.Lock()
+	ret, specificReturn := fake.hasActiveMediaReturnsOnCall[len(fake.hasActiveMediaArgsForCall)]
+	fake.hasActiveMediaArgsForCall = append(fake.hasActiveMediaArgsForCall, struct {
+	}{})
+	stub := fake.HasActiveMediaStub
+	fakeReturns := fake.hasActiveMediaReturns
+	fake.recordInvocation("HasActiveMedia", []interface{}{})
+	fake.hasActiveMediaMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeParticipant) HasActiveMediaCallCount() int {
+	fake.hasActiveMediaMutex.RLock()
+	defer fake.hasActiveMediaMutex.RUnlock()
+	return len(fake.hasActiveMediaArgsForCall)
+}
+
+func (fake *FakeParticipant) HasActiveMediaCalls(stub func() bool) {
+	fake.hasActiveMediaMutex.Lock()
+	defer fake.hasActiveMediaMutex.Unlock()
+	fake.HasActiveMediaStub = stub
+}
+
+func (fake *FakeParticipant) HasActiveMediaReturns(result1 bool) {
+	fake.hasActiveMediaMutex.Lock()
+	defer fake.hasActiveMediaMutex.Unlock()
+	fake.HasActiveMediaStub = nil
+	fake.hasActiveMediaReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeParticipant) HasActiveMediaReturnsOnCall(i int, result1 bool) {
+	fake.hasActiveMediaMutex.Lock()
+	defer fake.hasActiveMediaMutex.Unlock()
+	fake.HasActiveMediaStub = nil
+	if fake.hasActiveMediaReturnsOnCall == nil {
+		fake.hasActiveMediaReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.hasActiveMediaReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeParticipant) HasPermission(arg1 livekit.TrackID, arg2 livekit.ParticipantIdentity) bool {
 	fake.hasPermissionMutex.Lock()
 	ret, specificReturn := fake.hasPermissionReturnsOnCall[len(fake.hasPermissionArgsForCall)]
@@ -1543,6 +1606,8 @@ func (fake *FakeParticipant) Invocations() map[string][][]interface{} {
 	defer fake.getPublishedTrackMutex.RUnlock()
 	fake.getPublishedTracksMutex.RLock()
 	defer fake.getPublishedTracksMutex.RUnlock()
+	fake.hasActiveMediaMutex.RLock()
+	defer fake.hasActiveMediaMutex.RUnlock()
 	fake.hasPermissionMutex.RLock()
 	defer fake.hasPermissionMutex.RUnlock()
 	fake.hiddenMutex.RLock()
@@ -126,11 +126,41 @@ type FakeSubscribedTrack struct {
 	rTPSenderReturnsOnCall map[int]struct {
 		result1 *webrtc.RTPSender
 	}
+	SetMaxBitrateStub        func(int64)
+	setMaxBitrateMutex       sync.RWMutex
+	setMaxBitrateArgsForCall []struct {
+		arg1 int64
+	}
+	SetMaxSpatialLayerCapStub        func(int32)
+	setMaxSpatialLayerCapMutex       sync.RWMutex
+	setMaxSpatialLayerCapArgsForCall []struct {
+		arg1 int32
+	}
+	SetModeratedStub        func(bool)
+	setModeratedMutex       sync.RWMutex
+	setModeratedArgsForCall []struct {
+		arg1 bool
+	}
+	SetPlayoutDelayStub        func(*livekit.PlayoutDelay)
+	setPlayoutDelayMutex       sync.RWMutex
+	setPlayoutDelayArgsForCall []struct {
+		arg1 *livekit.PlayoutDelay
+	}
 	SetPublisherMutedStub        func(bool)
 	setPublisherMutedMutex       sync.RWMutex
 	setPublisherMutedArgsForCall []struct {
 		arg1 bool
 	}
+	SetStartPausedStub        func(bool)
+	setStartPausedMutex       sync.RWMutex
+	setStartPausedArgsForCall []struct {
+		arg1 bool
+	}
+	SetVisibilityStub        func(*types.TrackVisibility)
+	setVisibilityMutex       sync.RWMutex
+	setVisibilityArgsForCall []struct {
+		arg1 *types.TrackVisibility
+	}
 	SubscriberStub        func() types.LocalParticipant
 	subscriberMutex       sync.RWMutex
 	subscriberArgsForCall []struct {
@@ -161,6 +191,16 @@ type FakeSubscribedTrack struct {
 	subscriberIdentityReturnsOnCall map[int]struct {
 		result1 livekit.ParticipantIdentity
 	}
+	UpdateDecodeFeedbackStub        func() bool
+	updateDecodeFeedbackMutex       sync.RWMutex
+	updateDecodeFeedbackArgsForCall []struct {
+	}
+	updateDecodeFeedbackReturns struct {
+		result1 bool
+	}
+	updateDecodeFeedbackReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	UpdateSubscriberSettingsStub        func(*livekit.UpdateTrackSettings, bool)
 	updateSubscriberSettingsMutex       sync.RWMutex
 	updateSubscriberSettingsArgsForCall []struct {
@@ -801,6 +841,134 @@ func (fake *FakeSubscribedTrack) RTPSenderReturnsOnCall(i int, result1 *webrtc.R
 	}{result1}
 }
 
+func (fake *FakeSubscribedTrack) SetMaxBitrate(arg1 int64) {
+	fake.setMaxBitrateMutex.Lock()
+	fake.setMaxBitrateArgsForCall = append(fake.setMaxBitrateArgsForCall, struct {
+		arg1 int64
+	}{arg1})
+	stub := fake.SetMaxBitrateStub
+	fake.recordInvocation("SetMaxBitrate", []interface{}{arg1})
+	fake.setMaxBitrateMutex.Unlock()
+	if stub != nil {
+		fake.SetMaxBitrateStub(arg1)
+	}
+}
+
+func (fake *FakeSubscribedTrack) SetMaxBitrateCallCount() int {
+	fake.setMaxBitrateMutex.RLock()
+	defer fake.setMaxBitrateMutex.RUnlock()
+	return len(fake.setMaxBitrateArgsForCall)
+}
+
+func (fake *FakeSubscribedTrack) SetMaxBitrateCalls(stub func(int64)) {
+	fake.setMaxBitrateMutex.Lock()
+	defer fake.setMaxBitrateMutex.Unlock()
+	fake.SetMaxBitrateStub = stub
+}
+
+func (fake *FakeSubscribedTrack) SetMaxBitrateArgsForCall(i int) int64 {
+	fake.setMaxBitrateMutex.RLock()
+	defer fake.setMaxBitrateMutex.RUnlock()
+	argsForCall := fake.setMaxBitrateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSubscribedTrack) SetMaxSpatialLayerCap(arg1 int32) {
+	fake.setMaxSpatialLayerCapMutex.Lock()
+	fake.setMaxSpatialLayerCapArgsForCall = append(fake.setMaxSpatialLayerCapArgsForCall, struct {
+		arg1 int32
+	}{arg1})
+	stub := fake.SetMaxSpatialLayerCapStub
+	fake.recordInvocation("SetMaxSpatialLayerCap", []interface{}{arg1})
+	fake.setMaxSpatialLayerCapMutex.Unlock()
+	if stub != nil {
+		fake.SetMaxSpatialLayerCapStub(arg1)
+	}
+}
+
+func (fake *FakeSubscribedTrack) SetMaxSpatialLayerCapCallCount() int {
+	fake.setMaxSpatialLayerCapMutex.RLock()
+	defer fake.setMaxSpatialLayerCapMutex.RUnlock()
+	return len(fake.setMaxSpatialLayerCapArgsForCall)
+}
+
+func (fake *FakeSubscribedTrack) SetMaxSpatialLayerCapCalls(stub func(int32)) {
+	fake.setMaxSpatialLayerCapMutex.Lock()
+	defer fake.setMaxSpatialLayerCapMutex.Unlock()
+	fake.SetMaxSpatialLayerCapStub = stub
+}
+
+func (fake *FakeSubscribedTrack) SetMaxSpatialLayerCapArgsForCall(i int) int32 {
+	fake.setMaxSpatialLayerCapMutex.RLock()
+	defer fake.setMaxSpatialLayerCapMutex.RUnlock()
+	argsForCall := fake.setMaxSpatialLayerCapArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSubscribedTrack) SetModerated(arg1 bool) {
+	fake.setModeratedMutex.Lock()
+	fake.setModeratedArgsForCall = append(fake.setModeratedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetModeratedStub
+	fake.recordInvocation("SetModerated", []interface{}{arg1})
+	fake.setModeratedMutex.Unlock()
+	if stub != nil {
+		fake.SetModeratedStub(arg1)
+	}
+}
+
+func (fake *FakeSubscribedTrack) SetModeratedCallCount() int {
+	fake.setModeratedMutex.RLock()
+	defer fake.setModeratedMutex.RUnlock()
+	return len(fake.setModeratedArgsForCall)
+}
+
+func (fake *FakeSubscribedTrack) SetModeratedCalls(stub func(bool)) {
+	fake.setModeratedMutex.Lock()
+	defer fake.setModeratedMutex.Unlock()
+	fake.SetModeratedStub = stub
+}
+
+func (fake *FakeSubscribedTrack) SetModeratedArgsForCall(i int) bool {
+	fake.setModeratedMutex.RLock()
+	defer fake.setModeratedMutex.RUnlock()
+	argsForCall := fake.setModeratedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSubscribedTrack) SetPlayoutDelay(arg1 *livekit.PlayoutDelay) {
+	fake.setPlayoutDelayMutex.Lock()
+	fake.setPlayoutDelayArgsForCall = append(fake.setPlayoutDelayArgsForCall, struct {
+		arg1 *livekit.PlayoutDelay
+	}{arg1})
+	stub := fake.SetPlayoutDelayStub
+	fake.recordInvocation("SetPlayoutDelay", []interface{}{arg1})
+	fake.setPlayoutDelayMutex.Unlock()
+	if stub != nil {
+		fake.SetPlayoutDelayStub(arg1)
+	}
+}
+
+func (fake *FakeSubscribedTrack) SetPlayoutDelayCallCount() int {
+	fake.setPlayoutDelayMutex.RLock()
+	defer fake.setPlayoutDelayMutex.RUnlock()
+	return len(fake.setPlayoutDelayArgsForCall)
+}
+
+func (fake *FakeSubscribedTrack) SetPlayoutDelayCalls(stub func(*livekit.PlayoutDelay)) {
+	fake.setPlayoutDelayMutex.Lock()
+	defer fake.setPlayoutDelayMutex.Unlock()
+	fake.SetPlayoutDelayStub = stub
+}
+
+func (fake *FakeSubscribedTrack) SetPlayoutDelayArgsForCall(i int) *livekit.PlayoutDelay {
+	fake.setPlayoutDelayMutex.RLock()
+	defer fake.setPlayoutDelayMutex.RUnlock()
+	argsForCall := fake.setPlayoutDelayArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeSubscribedTrack) SetPublisherMuted(arg1 bool) {
 	fake.setPublisherMutedMutex.Lock()
 	fake.setPublisherMutedArgsForCall = append(fake.setPublisherMutedArgsForCall, struct {
@@ -833,6 +1001,70 @@ func (fake *FakeSubscribedTrack) SetPublisherMutedArgsForCall(i int) bool {
 	return argsForCall.arg1
 }
 
+func (fake *FakeSubscribedTrack) SetStartPaused(arg1 bool) {
+	fake.setStartPausedMutex.Lock()
+	fake.setStartPausedArgsForCall = append(fake.setStartPausedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetStartPausedStub
+	fake.recordInvocation("SetStartPaused", []interface{}{arg1})
+	fake.setStartPausedMutex.Unlock()
+	if stub != nil {
+		fake.SetStartPausedStub(arg1)
+	}
+}
+
+func (fake *FakeSubscribedTrack) SetStartPausedCallCount() int {
+	fake.setStartPausedMutex.RLock()
+	defer fake.setStartPausedMutex.RUnlock()
+	return len(fake.setStartPausedArgsForCall)
+}
+
+func (fake *FakeSubscribedTrack) SetStartPausedCalls(stub func(bool)) {
+	fake.setStartPausedMutex.Lock()
+	defer fake.setStartPausedMutex.Unlock()
+	fake.SetStartPausedStub = stub
+}
+
+func (fake *FakeSubscribedTrack) SetStartPausedArgsForCall(i int) bool {
+	fake.setStartPausedMutex.RLock()
+	defer fake.setStartPausedMutex.RUnlock()
+	argsForCall := fake.setStartPausedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSubscribedTrack) SetVisibility(arg1 *types.TrackVisibility) {
+	fake.setVisibilityMutex.Lock()
+	fake.setVisibilityArgsForCall = append(fake.setVisibilityArgsForCall, struct {
+		arg1 *types.TrackVisibility
+	}{arg1})
+	stub := fake.SetVisibilityStub
+	fake.recordInvocation("SetVisibility", []interface{}{arg1})
+	fake.setVisibilityMutex.Unlock()
+	if stub != nil {
+		fake.SetVisibilityStub(arg1)
+	}
+}
+
+func (fake *FakeSubscribedTrack) SetVisibilityCallCount() int {
+	fake.setVisibilityMutex.RLock()
+	defer fake.setVisibilityMutex.RUnlock()
+	return len(fake.setVisibilityArgsForCall)
+}
+
+func (fake *FakeSubscribedTrack) SetVisibilityCalls(stub func(*types.TrackVisibility)) {
+	fake.setVisibilityMutex.Lock()
+	defer fake.setVisibilityMutex.Unlock()
+	fake.SetVisibilityStub = stub
+}
+
+func (fake *FakeSubscribedTrack) SetVisibilityArgsForCall(i int) *types.TrackVisibility {
+	fake.setVisibilityMutex.RLock()
+	defer fake.setVisibilityMutex.RUnlock()
+	argsForCall := fake.setVisibilityArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeSubscribedTrack) Subscriber() types.LocalParticipant {
 	fake.subscriberMutex.Lock()
 	ret, specificReturn := fake.subscriberReturnsOnCall[len(fake.subscriberArgsForCall)]
@@ -992,6 +1224,59 @@ func (fake *FakeSubscribedTrack) SubscriberIdentityReturnsOnCall(i int, result1
 	}{result1}
 }
 
+func (fake *FakeSubscribedTrack) UpdateDecodeFeedback() bool {
+	fake.updateDecodeFeedbackMutex.Lock()
+	ret, specificReturn := fake.updateDecodeFeedbackReturnsOnCall[len(fake.updateDecodeFeedbackArgsForCall)]
+	fake.updateDecodeFeedbackArgsForCall = append(fake.updateDecodeFeedbackArgsForCall, struct {
+	}{})
+	stub := fake.UpdateDecodeFeedbackStub
+	fakeReturns := fake.updateDecodeFeedbackReturns
+	fake.recordInvocation("UpdateDecodeFeedback", []interface{}{})
+	fake.updateDecodeFeedbackMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSubscribedTrack) UpdateDecodeFeedbackCallCount() int {
+	fake.updateDecodeFeedbackMutex.RLock()
+	defer fake.updateDecodeFeedbackMutex.RUnlock()
+	return len(fake.updateDecodeFeedbackArgsForCall)
+}
+
+func (fake *FakeSubscribedTrack) UpdateDecodeFeedbackCalls(stub func() bool) {
+	fake.updateDecodeFeedbackMutex.Lock()
+	defer fake.updateDecodeFeedbackMutex.Unlock()
+	fake.UpdateDecodeFeedbackStub = stub
+}
+
+func (fake *FakeSubscribedTrack) UpdateDecodeFeedbackReturns(result1 bool) {
+	fake.updateDecodeFeedbackMutex.Lock()
+	defer fake.updateDecodeFeedbackMutex.Unlock()
+	fake.UpdateDecodeFeedbackStub = nil
+	fake.updateDecodeFeedbackReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeSubscribedTrack) UpdateDecodeFeedbackReturnsOnCall(i int, result1 bool) {
+	fake.updateDecodeFeedbackMutex.Lock()
+	defer fake.updateDecodeFeedbackMutex.Unlock()
+	fake.UpdateDecodeFeedbackStub = nil
+	if fake.updateDecodeFeedbackReturnsOnCall == nil {
+		fake.updateDecodeFeedbackReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.updateDecodeFeedbackReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeSubscribedTrack) UpdateSubscriberSettings(arg1 *livekit.UpdateTrackSettings, arg2 bool) {
 	fake.updateSubscriberSettingsMutex.Lock()
 	fake.updateSubscriberSettingsArgsForCall = append(fake.updateSubscriberSettingsArgsForCall, struct {
@@ -1078,14 +1363,28 @@ func (fake *FakeSubscribedTrack) Invocations() map[string][][]interface{} {
 	defer fake.publisherVersionMutex.RUnlock()
 	fake.rTPSenderMutex.RLock()
 	defer fake.rTPSenderMutex.RUnlock()
+	fake.setMaxBitrateMutex.RLock()
+	defer fake.setMaxBitrateMutex.RUnlock()
+	fake.setMaxSpatialLayerCapMutex.RLock()
+	defer fake.setMaxSpatialLayerCapMutex.RUnlock()
+	fake.setModeratedMutex.RLock()
+	defer fake.setModeratedMutex.RUnlock()
+	fake.setPlayoutDelayMutex.RLock()
+	defer fake.setPlayoutDelayMutex.RUnlock()
 	fake.setPublisherMutedMutex.RLock()
 	defer fake.setPublisherMutedMutex.RUnlock()
+	fake.setStartPausedMutex.RLock()
+	defer fake.setStartPausedMutex.RUnlock()
+	fake.setVisibilityMutex.RLock()
+	defer fake.setVisibilityMutex.RUnlock()
 	fake.subscriberMutex.RLock()
 	defer fake.subscriberMutex.RUnlock()
 	fake.subscriberIDMutex.RLock()
 	defer fake.subscriberIDMutex.RUnlock()
 	fake.subscriberIdentityMutex.RLock()
 	defer fake.subscriberIdentityMutex.RUnlock()
+	fake.updateDecodeFeedbackMutex.RLock()
+	defer fake.updateDecodeFeedbackMutex.RUnlock()
 	fake.updateSubscriberSettingsMutex.RLock()
 	defer fake.updateSubscriberSettingsMutex.RUnlock()
 	fake.updateVideoLayerMutex.RLock()
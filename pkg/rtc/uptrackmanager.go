@@ -16,6 +16,7 @@ package rtc
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/livekit/protocol/livekit"
@@ -116,16 +117,19 @@ func (u *UpTrackManager) OnPublishedTrackUpdated(f func(track types.MediaTrack))
 	u.onTrackUpdated = f
 }
 
-func (u *UpTrackManager) SetPublishedTrackMuted(trackID livekit.TrackID, muted bool) types.MediaTrack {
+// SetPublishedTrackMuted returns the track if published and whether its mute state changed
+func (u *UpTrackManager) SetPublishedTrackMuted(trackID livekit.TrackID, muted bool) (types.MediaTrack, bool) {
 	u.lock.RLock()
 	track := u.publishedTracks[trackID]
 	u.lock.RUnlock()
 
+	changed := false
 	if track != nil {
 		currentMuted := track.IsMuted()
 		track.SetMuted(muted)
 
 		if currentMuted != track.IsMuted() {
+			changed = true
 			u.params.Logger.Debugw("publisher mute status changed", "trackID", trackID, "muted", track.IsMuted())
 			if u.onTrackUpdated != nil {
 				u.onTrackUpdated(track)
@@ -133,6 +137,40 @@ func (u *UpTrackManager) SetPublishedTrackMuted(trackID livekit.TrackID, muted b
 		}
 	}
 
+	return track, changed
+}
+
+// SetAllPublishedTracksMuted sets mute state of all published tracks and returns the tracks whose mute state changed.
+// Published track updated callback is not fired, caller is expected to notify once for all tracks.
+func (u *UpTrackManager) SetAllPublishedTracksMuted(muted bool) []types.MediaTrack {
+	var changed []types.MediaTrack
+	for _, track := range u.GetPublishedTracks() {
+		currentMuted := track.IsMuted()
+		track.SetMuted(muted)
+
+		if currentMuted != track.IsMuted() {
+			u.params.Logger.Debugw("publisher mute status changed", "trackID", track.ID(), "muted", track.IsMuted())
+			changed = append(changed, track)
+		}
+	}
+	return changed
+}
+
+// SetPublishedTrackPaused stops or resumes forwarding of a published track without unpublishing or muting it
+func (u *UpTrackManager) SetPublishedTrackPaused(trackID livekit.TrackID, paused bool) types.MediaTrack {
+	u.lock.RLock()
+	track := u.publishedTracks[trackID]
+	u.lock.RUnlock()
+
+	if track != nil && track.IsPaused() != paused {
+		track.SetPaused(paused)
+
+		u.params.Logger.Debugw("publisher pause status changed", "trackID", trackID, "paused", paused)
+		if u.onTrackUpdated != nil {
+			u.onTrackUpdated(track)
+		}
+	}
+
 	return track
 }
 
@@ -243,7 +281,7 @@ func (u *UpTrackManager) UpdateAudioTrack(update *livekit.UpdateLocalAudioTrack)
 	track := u.GetPublishedTrack(livekit.TrackID(update.TrackSid))
 	if track == nil {
 		u.params.Logger.Warnw("could not find track", nil, "trackID", livekit.TrackID(update.TrackSid))
-		return errors.New("could not find published track")
+		return fmt.Errorf("%w: %s", ErrTrackNotFound, update.TrackSid)
 	}
 
 	track.UpdateAudioTrack(update)
@@ -258,7 +296,7 @@ func (u *UpTrackManager) UpdateVideoTrack(update *livekit.UpdateLocalVideoTrack)
 	track := u.GetPublishedTrack(livekit.TrackID(update.TrackSid))
 	if track == nil {
 		u.params.Logger.Warnw("could not find track", nil, "trackID", livekit.TrackID(update.TrackSid))
-		return errors.New("could not find published track")
+		return fmt.Errorf("%w: %s", ErrTrackNotFound, update.TrackSid)
 	}
 
 	track.UpdateVideoTrack(update)
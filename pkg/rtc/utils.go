@@ -18,16 +18,28 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v3"
 
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
+	lksdp "github.com/livekit/protocol/sdp"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
 )
 
 const (
 	trackIdSeparator = "|"
+
+	// receiver reports are sent every few seconds, older ones are from tracks the subscriber is not receiving
+	subscriberLossReportMaxAge = 10 * time.Second
 )
 
 func UnpackStreamID(packed string) (participantID livekit.ParticipantID, trackID livekit.TrackID) {
@@ -131,6 +143,132 @@ func IsEOF(err error) bool {
 	return err == io.ErrClosedPipe || err == io.EOF
 }
 
+// rtcpWriteErrorCategory classifies RTCP write errors for telemetry
+func rtcpWriteErrorCategory(err error) string {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, net.ErrClosed), errors.Is(err, io.ErrClosedPipe):
+		return "closed"
+	default:
+		return "other"
+	}
+}
+
+// receiverBitrate sums the highest temporal layer of every spatial layer of a video receiver,
+// temporal layer bitrates are cumulative and SVC spatial layers already include the lower ones.
+func receiverBitrate(receiver sfu.TrackReceiver) int64 {
+	_, brs := receiver.GetLayeredBitrate()
+
+	isSVC := sfu.IsSvcCodec(receiver.Codec().MimeType)
+	bitrate := int64(0)
+	for s := len(brs) - 1; s >= 0; s-- {
+		for t := len(brs[s]) - 1; t >= 0; t-- {
+			if brs[s][t] != 0 {
+				if isSVC {
+					return brs[s][t]
+				}
+				bitrate += brs[s][t]
+				break
+			}
+		}
+	}
+	return bitrate
+}
+
+type subscriberLossReport struct {
+	trackID      livekit.TrackID
+	fractionLost uint8
+	at           time.Time
+}
+
+func summarizeSubscriberLoss(reports []subscriberLossReport, now time.Time) *types.SubscriberLossSummary {
+	summary := &types.SubscriberLossSummary{
+		Tracks: make(map[livekit.TrackID]float32),
+	}
+
+	total := float32(0)
+	for _, r := range reports {
+		if r.at.IsZero() || now.Sub(r.at) > subscriberLossReportMaxAge {
+			continue
+		}
+
+		// fraction lost is a fixed point number with the binary point at the left edge
+		fractionLost := float32(r.fractionLost) / 256
+		summary.Tracks[r.trackID] = fractionLost
+		total += fractionLost
+		if fractionLost > summary.MaxFractionLost {
+			summary.MaxFractionLost = fractionLost
+		}
+	}
+	if len(summary.Tracks) != 0 {
+		summary.FractionLost = total / float32(len(summary.Tracks))
+	}
+	return summary
+}
+
+// midForTrackFromSDP returns mid of the media section carrying one of the given track ids (cid as signalled by client)
+// or ssrcs, matched against msid and ssrc attributes. Empty string is returned when there is no match.
+func midForTrackFromSDP(sd webrtc.SessionDescription, trackIDs []string, ssrcs []uint32) (string, error) {
+	if sd.SDP == "" {
+		return "", nil
+	}
+
+	parsed, err := sd.Unmarshal()
+	if err != nil {
+		return "", err
+	}
+
+	isTrackID := func(msid string) bool {
+		split := strings.Split(msid, " ")
+		if len(split) != 2 {
+			return false
+		}
+		for _, trackID := range trackIDs {
+			if trackID != "" && split[1] == trackID {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, m := range parsed.MediaDescriptions {
+		mid := lksdp.GetMidValue(m)
+		if mid == "" {
+			continue
+		}
+
+		for _, attr := range m.Attributes {
+			switch attr.Key {
+			case sdp.AttrKeyMsid:
+				if isTrackID(attr.Value) {
+					return mid, nil
+				}
+
+			case sdp.AttrKeySSRC:
+				// a=ssrc:<ssrc> msid:<stream id> <track id>
+				ssrcStr, attrValue, _ := strings.Cut(attr.Value, " ")
+				ssrc, err := strconv.ParseUint(ssrcStr, 10, 32)
+				if err != nil {
+					continue
+				}
+				for _, s := range ssrcs {
+					if s != 0 && uint32(ssrc) == s {
+						return mid, nil
+					}
+				}
+				if msid, ok := strings.CutPrefix(attrValue, "msid:"); ok && isTrackID(msid) {
+					return mid, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
 func Recover(l logger.Logger) any {
 	if l == nil {
 		l = logger.GetLogger()
@@ -15,11 +15,19 @@
 package rtc
 
 import (
+	"errors"
+	"io"
+	"net"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/pion/webrtc/v3"
 	"github.com/stretchr/testify/require"
 
 	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
 )
 
 func TestPackStreamId(t *testing.T) {
@@ -43,3 +51,110 @@ func TestPackDataTrackLabel(t *testing.T) {
 	require.Equal(t, trackID, tr)
 	require.Equal(t, label, l)
 }
+
+func TestRTCPWriteErrorCategory(t *testing.T) {
+	require.Equal(t, "", rtcpWriteErrorCategory(nil))
+	require.Equal(t, "timeout", rtcpWriteErrorCategory(os.ErrDeadlineExceeded))
+	require.Equal(t, "closed", rtcpWriteErrorCategory(net.ErrClosed))
+	require.Equal(t, "closed", rtcpWriteErrorCategory(io.ErrClosedPipe))
+	require.Equal(t, "other", rtcpWriteErrorCategory(errors.New("srtp failure")))
+}
+
+type bitrateTestReceiver struct {
+	sfu.TrackReceiver
+	mimeType string
+	bitrates sfu.Bitrates
+}
+
+func (r *bitrateTestReceiver) Codec() webrtc.RTPCodecParameters {
+	return webrtc.RTPCodecParameters{RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: r.mimeType}}
+}
+
+func (r *bitrateTestReceiver) GetLayeredBitrate() ([]int32, sfu.Bitrates) {
+	return nil, r.bitrates
+}
+
+func TestReceiverBitrate(t *testing.T) {
+	// simulcast layers are independent streams, highest temporal layer is cumulative
+	simulcast := &bitrateTestReceiver{mimeType: webrtc.MimeTypeVP8}
+	simulcast.bitrates[0] = [4]int64{50_000, 100_000, 150_000, 0}
+	simulcast.bitrates[1] = [4]int64{200_000, 400_000, 0, 0}
+	require.Equal(t, int64(550_000), receiverBitrate(simulcast))
+
+	// SVC spatial layers include lower layers
+	svc := &bitrateTestReceiver{mimeType: webrtc.MimeTypeVP9}
+	svc.bitrates[0] = [4]int64{100_000, 150_000, 0, 0}
+	svc.bitrates[1] = [4]int64{300_000, 450_000, 0, 0}
+	require.Equal(t, int64(450_000), receiverBitrate(svc))
+
+	require.Zero(t, receiverBitrate(&bitrateTestReceiver{mimeType: webrtc.MimeTypeVP8}))
+}
+
+func TestSummarizeSubscriberLoss(t *testing.T) {
+	now := time.Now()
+	summary := summarizeSubscriberLoss([]subscriberLossReport{
+		{trackID: "audio", fractionLost: 0, at: now.Add(-time.Second)},
+		{trackID: "video", fractionLost: 64, at: now.Add(-2 * time.Second)},
+		{trackID: "screen", fractionLost: 128, at: now},
+		// stale and missing reports are skipped
+		{trackID: "stale", fractionLost: 255, at: now.Add(-time.Minute)},
+		{trackID: "none"},
+	}, now)
+	require.Equal(t, map[livekit.TrackID]float32{"audio": 0, "video": 0.25, "screen": 0.5}, summary.Tracks)
+	require.Equal(t, float32(0.25), summary.FractionLost)
+	require.Equal(t, float32(0.5), summary.MaxFractionLost)
+
+	summary = summarizeSubscriberLoss(nil, now)
+	require.Empty(t, summary.Tracks)
+	require.Zero(t, summary.FractionLost)
+}
+
+const midTestSDP = "v=0\r\n" +
+	"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+	"s=-\r\n" +
+	"t=0 0\r\n" +
+	"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"a=mid:0\r\n" +
+	"a=msid:stream audio_cid\r\n" +
+	"a=rtpmap:111 opus/48000/2\r\n" +
+	"m=video 9 UDP/TLS/RTP/SAVPF 96\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"a=mid:1\r\n" +
+	"a=rtpmap:96 VP8/90000\r\n" +
+	"a=ssrc:1111 cname:stream\r\n" +
+	"a=ssrc:1111 msid:stream video_cid\r\n" +
+	"m=video 9 UDP/TLS/RTP/SAVPF 96\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"a=mid:2\r\n" +
+	"a=rtpmap:96 VP8/90000\r\n" +
+	"a=ssrc:2222 cname:stream\r\n"
+
+func TestMidForTrackFromSDP(t *testing.T) {
+	sd := webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: midTestSDP}
+
+	mid, err := midForTrackFromSDP(sd, []string{"audio_cid"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "0", mid)
+
+	// msid in ssrc attribute
+	mid, err = midForTrackFromSDP(sd, []string{"video_cid"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "1", mid)
+
+	// ssrc only
+	mid, err = midForTrackFromSDP(sd, []string{"unknown"}, []uint32{0, 2222})
+	require.NoError(t, err)
+	require.Equal(t, "2", mid)
+
+	mid, err = midForTrackFromSDP(sd, []string{"unknown", ""}, []uint32{3333})
+	require.NoError(t, err)
+	require.Empty(t, mid)
+
+	mid, err = midForTrackFromSDP(webrtc.SessionDescription{}, []string{"audio_cid"}, nil)
+	require.NoError(t, err)
+	require.Empty(t, mid)
+
+	_, err = midForTrackFromSDP(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: "invalid"}, []string{"audio_cid"}, nil)
+	require.Error(t, err)
+}
@@ -45,6 +45,8 @@ type WrappedReceiver struct {
 	receivers       []sfu.TrackReceiver
 	codecs          []webrtc.RTPCodecParameters
 	determinedCodec webrtc.RTPCodecCapability
+	// red is offered by encoding it from opus only up track
+	isREDEncoded bool
 }
 
 func NewWrappedReceiver(params WrappedReceiverParams) *WrappedReceiver {
@@ -54,6 +56,7 @@ func NewWrappedReceiver(params WrappedReceiverParams) *WrappedReceiver {
 	}
 
 	codecs := params.UpstreamCodecs
+	isREDEncoded := false
 	if len(codecs) == 1 {
 		if strings.EqualFold(codecs[0].MimeType, sfu.MimeTypeAudioRed) {
 			// if upstream is opus/red, then add opus to match clients that don't support red
@@ -69,16 +72,23 @@ func NewWrappedReceiver(params WrappedReceiverParams) *WrappedReceiver {
 			})
 			// prefer red codec
 			codecs[0], codecs[1] = codecs[1], codecs[0]
+			isREDEncoded = true
 		}
 	}
 
 	return &WrappedReceiver{
-		params:    params,
-		receivers: sfuReceivers,
-		codecs:    codecs,
+		params:       params,
+		receivers:    sfuReceivers,
+		codecs:       codecs,
+		isREDEncoded: isREDEncoded,
 	}
 }
 
+// IsREDEncoded returns true if red offered to subscribers is encoded from opus only up track
+func (r *WrappedReceiver) IsREDEncoded() bool {
+	return r.isREDEncoded
+}
+
 func (r *WrappedReceiver) TrackID() livekit.TrackID {
 	return r.params.TrackID
 }
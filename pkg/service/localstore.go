@@ -16,6 +16,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -90,7 +91,7 @@ func (s *LocalStore) ListRooms(_ context.Context, roomNames []livekit.RoomName)
 
 func (s *LocalStore) DeleteRoom(ctx context.Context, roomName livekit.RoomName) error {
 	room, _, err := s.LoadRoom(ctx, roomName, false)
-	if err == ErrRoomNotFound {
+	if errors.Is(err, ErrRoomNotFound) {
 		return nil
 	} else if err != nil {
 		return err
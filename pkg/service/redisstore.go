@@ -226,7 +226,7 @@ func (s *RedisStore) ListRooms(_ context.Context, roomNames []livekit.RoomName)
 
 func (s *RedisStore) DeleteRoom(ctx context.Context, roomName livekit.RoomName) error {
 	_, _, err := s.LoadRoom(ctx, roomName, false)
-	if err == ErrRoomNotFound {
+	if errors.Is(err, ErrRoomNotFound) {
 		return nil
 	}
 
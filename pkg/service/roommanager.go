@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 	"golang.org/x/exp/maps"
 
 	"github.com/livekit/livekit-server/pkg/agent"
@@ -40,6 +41,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
 	"github.com/livekit/livekit-server/version"
@@ -83,6 +85,10 @@ type RoomManager struct {
 	participantServers utils.MultitonService[rpc.ParticipantTopic]
 
 	iceConfigCache *sutils.IceConfigCache[iceConfigCacheKey]
+
+	// participants are migrating out of this node, their rooms are hosted on other nodes,
+	// so room and participant state in store and routing are left to those nodes
+	migratingOut atomic.Bool
 }
 
 func NewLocalRoomManager(
@@ -97,11 +103,15 @@ func NewLocalRoomManager(
 	versionGenerator utils.TimedVersionGenerator,
 	turnAuthHandler *TURNAuthHandler,
 	bus psrpc.MessageBus,
+	jobScheduler *sutils.JobScheduler,
+	serverClock sutils.ServerClock,
 ) (*RoomManager, error) {
 	rtcConf, err := rtc.NewWebRTCConfig(conf)
 	if err != nil {
 		return nil, err
 	}
+	rtcConf.JobScheduler = jobScheduler
+	rtcConf.ServerClock = serverClock
 
 	return &RoomManager{
 		config:            conf,
@@ -209,6 +219,40 @@ func (r *RoomManager) HasParticipants() bool {
 	return false
 }
 
+// Drain migrates participants out of this node when their resume state can be carried over.
+// Resume state of each participant is exported for the node it resumes on and rooms are released,
+// so that they are allocated to other nodes.
+func (r *RoomManager) Drain() {
+	if r.config.Room.ResumeStateExpiry <= 0 {
+		return
+	}
+
+	r.lock.RLock()
+	rooms := maps.Values(r.rooms)
+	r.lock.RUnlock()
+
+	r.migratingOut.Store(true)
+	ctx := context.Background()
+	for _, room := range rooms {
+		if err := r.router.ClearRoomState(ctx, room.Name()); err != nil {
+			room.Logger.Errorw("could not clear room state", err)
+		}
+
+		for _, p := range room.GetParticipants() {
+			state, err := p.MarshalResumeState()
+			if err != nil {
+				p.GetLogger().Warnw("could not export resume state", err)
+				continue
+			}
+			if err := r.router.StoreResumeState(ctx, room.Name(), p.Identity(), state, r.config.Room.ResumeStateExpiry); err != nil {
+				p.GetLogger().Errorw("could not store resume state", err)
+				continue
+			}
+			p.MaybeStartMigration(true, nil)
+		}
+	}
+}
+
 func (r *RoomManager) Stop() {
 	// disconnect all clients
 	r.lock.RLock()
@@ -232,6 +276,10 @@ func (r *RoomManager) Stop() {
 	}
 
 	r.iceConfigCache.Stop()
+	r.rtcConfig.JobScheduler.Stop()
+	if r.rtcConfig.Receiver.FanOut != nil {
+		r.rtcConfig.Receiver.FanOut.Stop()
+	}
 }
 
 // StartSession starts WebRTC session when a new participant is connected, takes place on RTC node
@@ -261,6 +309,7 @@ func (r *RoomManager) StartSession(
 	// since this is used for TURN server credentials, we don't want to fail the request even if there's no TURN for the session
 	apiKey, _, _ := r.getFirstKeyPair()
 
+	var resumeState *rtc.ResumeState
 	participant := room.GetParticipant(pi.Identity)
 	if participant != nil {
 		// When reconnecting, it means WS has interrupted but underlying peer connection is still ok in this state,
@@ -321,6 +370,8 @@ func (r *RoomManager) StartSession(
 				logger.Warnw("could not resume participant", err, "participant", pi.Identity)
 				return err
 			}
+			// network may have changed, re-seed estimation with what the client reports now
+			participant.SetBandwidthHint(r.config.RTC.CongestionControl.BandwidthHint(pi.Client.GetNetwork()))
 			r.telemetry.ParticipantResumed(ctx, room.ToProto(), participant.ToProto(), livekit.NodeID(r.currentNode.Id), pi.ReconnectReason)
 			go r.rtcSessionWorker(room, participant, requestSource)
 			return nil
@@ -330,6 +381,10 @@ func (r *RoomManager) StartSession(
 		participant.GetLogger().Infow("removing duplicate participant")
 		room.RemoveParticipant(participant.Identity(), participant.ID(), types.ParticipantCloseReasonDuplicateIdentity)
 	} else if pi.Reconnect {
+		// a participant migrating out of a draining node resumes here with the state exported there
+		resumeState = r.takeResumeState(ctx, roomName, pi.Identity)
+	}
+	if pi.Reconnect && resumeState == nil {
 		// send leave request if participant is trying to reconnect without keep subscribe state
 		// but missing from the room
 		var leave *livekit.LeaveRequest
@@ -370,6 +425,10 @@ func (r *RoomManager) StartSession(
 	rtcConf := *r.rtcConfig
 	rtcConf.SetBufferFactory(room.GetBufferFactory())
 	sid := livekit.ParticipantID(utils.NewGuid(utils.ParticipantPrefix))
+	if resumeState != nil && pi.ID != "" {
+		// migrating participant keeps its SID
+		sid = pi.ID
+	}
 	pLogger := rtc.LoggerWithParticipant(
 		rtc.LoggerWithRoom(logger.GetLogger(), room.Name(), room.ID()),
 		pi.Identity,
@@ -395,10 +454,48 @@ func (r *RoomManager) StartSession(
 	if r.config.RTC.ReconnectOnDataChannelError != nil {
 		reconnectOnDataChannelError = *r.config.RTC.ReconnectOnDataChannelError
 	}
+	// default pick the first track info when a migrated track is ambiguous
+	strictMigration := false
+	if r.config.RTC.StrictMigration != nil {
+		strictMigration = *r.config.RTC.StrictMigration
+	}
+	// default trust capabilities resolved before migration, so that rolling upgrades do not change behavior
+	preferMigratedCapabilities := true
+	if r.config.RTC.PreferMigratedCapabilities != nil {
+		preferMigratedCapabilities = *r.config.RTC.PreferMigratedCapabilities
+	}
 	subscriberAllowPause := r.config.RTC.CongestionControl.AllowPause
 	if pi.SubscriberAllowPause != nil {
 		subscriberAllowPause = *pi.SubscriberAllowPause
 	}
+	// validated when loading config
+	playoutDelaySources, _ := r.config.Room.PlayoutDelay.TrackSources()
+	allocationPreference := sfu.AllocationPreferenceSpatial
+	if r.config.RTC.CongestionControl.PrefersTemporalLayers(roomName) {
+		allocationPreference = sfu.AllocationPreferenceTemporal
+	}
+	isRecorder := pi.Grants.GetParticipantKind() == livekit.ParticipantInfo_EGRESS || (pi.Grants.Video != nil && pi.Grants.Video.Recorder)
+	disableSenderReports := r.config.RTC.DisableRecorderSenderReports && isRecorder
+	// recorders and other non-standard participants may never send track settings, so they do not start paused
+	var startPausedSubscriptions []livekit.TrackType
+	if r.config.RTC.StartPausedSubscriptions.Enabled && pi.Grants.GetParticipantKind() == livekit.ParticipantInfo_STANDARD && !isRecorder {
+		startPausedSubscriptions = append(startPausedSubscriptions, livekit.TrackType_VIDEO)
+		if r.config.RTC.StartPausedSubscriptions.IncludeAudio {
+			startPausedSubscriptions = append(startPausedSubscriptions, livekit.TrackType_AUDIO)
+		}
+	}
+	// full reconnects issued to a previous session of the identity, possibly on another node
+	reconnectCount, err := r.router.TakeReconnectCount(ctx, roomName, pi.Identity)
+	if err != nil {
+		pLogger.Warnw("could not take reconnect count", err)
+	}
+	// reliable data not acknowledged by a previous session of the identity which was asked to do a full reconnect
+	var pendingReliableData [][]byte
+	if r.config.RTC.ReliableDataHistoryPackets > 0 || r.config.RTC.ReliableDataHistoryBytes > 0 {
+		if pendingReliableData, err = r.router.TakeReliableData(ctx, roomName, pi.Identity); err != nil {
+			pLogger.Warnw("could not take reliable data", err)
+		}
+	}
 	participant, err = rtc.NewParticipant(rtc.ParticipantParams{
 		Identity:                pi.Identity,
 		Name:                    pi.Name,
@@ -412,6 +509,7 @@ func (r *RoomManager) StartSession(
 		Telemetry:               r.telemetry,
 		Trailer:                 room.Trailer(),
 		PLIThrottleConfig:       r.config.RTC.PLIThrottle,
+		RTCPWriteFailureConfig:  r.config.RTC.RTCPWriteFailure,
 		CongestionControlConfig: r.config.RTC.CongestionControl,
 		PublishEnabledCodecs:    protoRoom.EnabledCodecs,
 		SubscribeEnabledCodecs:  protoRoom.EnabledCodecs,
@@ -432,14 +530,49 @@ func (r *RoomManager) StartSession(
 		ReconnectOnPublicationError:  reconnectOnPublicationError,
 		ReconnectOnSubscriptionError: reconnectOnSubscriptionError,
 		ReconnectOnDataChannelError:  reconnectOnDataChannelError,
+		Migration:                    resumeState != nil,
+		StrictMigration:              strictMigration,
+		PreferMigratedCapabilities:   preferMigratedCapabilities,
 		DataChannelMaxBufferedAmount: r.config.RTC.DataChannelMaxBufferedAmount,
+		DataChannelLowBufferedAmount: r.config.RTC.DataChannelLowBufferedAmount,
+		MaxReliableDataRate:          r.config.RTC.MaxReliableDataRate,
+		QueueReliableDataAboveRate:   r.config.RTC.QueueReliableDataAboveRate,
+		ReliableDataHistoryPackets:   r.config.RTC.ReliableDataHistoryPackets,
+		ReliableDataHistoryBytes:     r.config.RTC.ReliableDataHistoryBytes,
+		PendingReliableData:          pendingReliableData,
+		RTTHistorySize:               r.config.RTC.RTTHistorySize,
 		VersionGenerator:             r.versionGenerator,
 		TrackResolver:                room.ResolveMediaTrackForSubscriber,
 		SubscriberAllowPause:         subscriberAllowPause,
+		BandwidthHint:                r.config.RTC.CongestionControl.BandwidthHint(pi.Client.GetNetwork()),
 		SubscriptionLimitAudio:       r.config.Limit.SubscriptionLimitAudio,
 		SubscriptionLimitVideo:       r.config.Limit.SubscriptionLimitVideo,
+		SubscriptionLimitScreenShare: r.config.Limit.SubscriptionLimitScreenShare,
 		PlayoutDelay:                 roomInternal.GetPlayoutDelay(),
-		SyncStreams:                  roomInternal.GetSyncStreams(),
+		PlayoutDelaySources:          playoutDelaySources,
+		MaxSubscribersPerTrack:       int(r.config.Room.MaxSubscribersPerTrack),
+		UnpublishDrainDuration:       r.config.Room.UnpublishDrainDuration,
+		MaxCachedUpdates:             int(r.config.Room.MaxCachedUpdatesPerParticipant),
+		GetUpdateBatchWindow: func() time.Duration {
+			return rtc.UpdateBatchWindow(r.config.Room.UpdateBatchMinWindow, r.config.Room.UpdateBatchMaxWindow, room.GetParticipantCount())
+		},
+		SyncStreams:              roomInternal.GetSyncStreams(),
+		DataActivityIdleWindow:   r.config.Room.DataActivityIdleWindow,
+		StartPausedSubscriptions: startPausedSubscriptions,
+		SubscribedTrackSettings:  r.config.RTC.SubscribedTrackSettings,
+		DataChannelRateLimit:     r.config.RTC.DataChannelRateLimit,
+		PublishLimit:             r.config.RTC.PublishLimit,
+		HeartbeatInterval:        r.config.RTC.HeartbeatInterval,
+		SenderReportInterval:     r.config.RTC.SenderReportInterval,
+		SenderReportBatchSize:    r.config.RTC.SenderReportBatchSize,
+		DisableSenderReports:     disableSenderReports,
+		JobScheduler:             r.rtcConfig.JobScheduler,
+		MigrationWaitDuration:    r.config.RTC.MigrationWaitDuration,
+		MaxPendingICECandidates:  r.config.RTC.MaxPendingICECandidates,
+		PreviousTrackSIDs:        room.TakePreviousTrackSIDs(pi.Identity),
+		ReconnectCount:           reconnectCount,
+		ResumeState:              resumeState,
+		AllocationPreference:     allocationPreference,
 	})
 	if err != nil {
 		return err
@@ -448,7 +581,9 @@ func (r *RoomManager) StartSession(
 
 	// join room
 	opts := rtc.ParticipantOptions{
-		AutoSubscribe: pi.AutoSubscribe,
+		AutoSubscribe:   pi.AutoSubscribe,
+		Migration:       resumeState != nil,
+		ReconnectReason: pi.ReconnectReason,
 	}
 	iceServers := r.iceServersForParticipant(apiKey, participant, iceConfig.PreferenceSubscriber == livekit.ICECandidateType_ICT_TLS)
 	if err = room.Join(participant, requestSource, &opts, iceServers); err != nil {
@@ -488,14 +623,27 @@ func (r *RoomManager) StartSession(
 	participant.OnClose(func(p types.LocalParticipant) {
 		killParticipantServer()
 
-		if err := r.roomStore.DeleteParticipant(ctx, roomName, p.Identity()); err != nil {
-			pLogger.Errorw("could not delete participant", err)
-		}
-
-		// update room store with new numParticipants
 		proto := room.ToProto()
-		persistRoomForParticipantCount(proto)
+		if !r.migratingOut.Load() {
+			if err := r.roomStore.DeleteParticipant(ctx, roomName, p.Identity()); err != nil {
+				pLogger.Errorw("could not delete participant", err)
+			}
+
+			// update room store with new numParticipants
+			persistRoomForParticipantCount(proto)
+		}
 		r.telemetry.ParticipantLeft(ctx, proto, p.ToProto(), true)
+
+		if reconnectCount := p.GetReconnectCount(); reconnectCount != 0 && r.config.Room.ReconnectCountExpiry > 0 {
+			if err := r.router.StoreReconnectCount(ctx, roomName, p.Identity(), reconnectCount, r.config.Room.ReconnectCountExpiry); err != nil {
+				pLogger.Errorw("could not store reconnect count", err)
+			}
+		}
+		if packets := p.UnacknowledgedReliableData(); len(packets) != 0 {
+			if err := r.router.StoreReliableData(ctx, roomName, p.Identity(), packets, r.config.RTC.ReliableDataHistoryExpiry); err != nil {
+				pLogger.Errorw("could not store reliable data", err)
+			}
+		}
 	})
 	participant.OnClaimsChanged(func(participant types.LocalParticipant) {
 		pLogger.Debugw("refreshing client token after claims change")
@@ -506,11 +654,37 @@ func (r *RoomManager) StartSession(
 	participant.OnICEConfigChanged(func(participant types.LocalParticipant, iceConfig *livekit.ICEConfig) {
 		r.iceConfigCache.Put(iceConfigCacheKey{roomName, participant.Identity()}, iceConfig)
 	})
+	participant.OnMigrationTimeout(func(participant types.LocalParticipant) {
+		pLogger.Infow("migration timed out, closing subscriber peer connection")
+	})
 
 	go r.rtcSessionWorker(room, participant, requestSource)
 	return nil
 }
 
+// takeResumeState returns resume state exported by a draining node for a participant migrating to this node
+func (r *RoomManager) takeResumeState(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) *rtc.ResumeState {
+	if r.config.Room.ResumeStateExpiry <= 0 {
+		return nil
+	}
+
+	data, err := r.router.TakeResumeState(ctx, roomName, identity)
+	if err != nil {
+		logger.Warnw("could not take resume state", err, "room", roomName, "participant", identity)
+		return nil
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	resumeState, err := rtc.UnmarshalResumeState(data)
+	if err != nil {
+		logger.Warnw("could not decode resume state", err, "room", roomName, "participant", identity)
+		return nil
+	}
+	return resumeState
+}
+
 // create the actual room object, to be used on RTC node
 func (r *RoomManager) getOrCreateRoom(ctx context.Context, roomName livekit.RoomName) (*rtc.Room, error) {
 	r.lock.RLock()
@@ -559,7 +733,12 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, roomName livekit.Room
 		roomInfo := newRoom.ToProto()
 		r.telemetry.RoomEnded(ctx, roomInfo)
 		prometheus.RoomEnded(time.Unix(roomInfo.CreationTime, 0))
-		if err := r.deleteRoom(ctx, roomName); err != nil {
+		if r.migratingOut.Load() {
+			// room is hosted on the nodes its participants migrated to
+			r.lock.Lock()
+			delete(r.rooms, roomName)
+			r.lock.Unlock()
+		} else if err := r.deleteRoom(ctx, roomName); err != nil {
 			newRoom.Logger.Errorw("could not delete room", err)
 		}
 
@@ -611,6 +790,32 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 		}
 	}()
 
+	// requests are handled in a separate goroutine so that they can be prioritized,
+	// drops are counted in signal request metrics
+	signalQueue := rtc.NewSignalQueue(rtc.SignalQueueParams{
+		MaxSize: r.config.Limit.SignalQueueSize,
+		OnDropped: func(req *livekit.SignalRequest) {
+			pLogger.Debugw("dropped signal request", "request", rtc.SignalRequestType(req))
+		},
+	})
+	defer signalQueue.Close()
+	processDone := make(chan struct{})
+	go func() {
+		defer close(processDone)
+		defer func() {
+			if r := rtc.Recover(pLogger); r != nil {
+				os.Exit(1)
+			}
+		}()
+
+		// more specific errors are already logged
+		// treat errors returned as fatal, stops the session
+		_ = signalQueue.Process(func(req *livekit.SignalRequest) error {
+			return rtc.HandleParticipantSignal(room, participant, req, pLogger)
+		})
+	}()
+	isDroppingSignal := false
+
 	// send first refresh for cases when client token is close to expiring
 	_ = r.refreshToken(participant)
 	tokenTicker := time.NewTicker(tokenRefreshInterval)
@@ -619,6 +824,8 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 		select {
 		case <-participant.Disconnected():
 			return
+		case <-processDone:
+			return
 		case <-tokenTicker.C:
 			// refresh token with the first API Key/secret pair
 			if err := r.refreshToken(participant); err != nil {
@@ -628,6 +835,9 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 			// In single node mode, the request source is directly tied to the signal message channel
 			// this means ICE restart isn't possible in single node mode
 			if obj == nil {
+				// handle requests received before the source closed
+				signalQueue.Drain()
+				<-processDone
 				if room.GetParticipantRequestSource(participant.Identity()) == requestSource {
 					participant.HandleSignalSourceClose()
 				}
@@ -635,10 +845,14 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 			}
 
 			req := obj.(*livekit.SignalRequest)
-			if err := rtc.HandleParticipantSignal(room, participant, req, pLogger); err != nil {
-				// more specific errors are already logged
-				// treat errors returned as fatal
-				return
+			if err := signalQueue.Enqueue(req); err != nil {
+				// logged once till requests are accepted again
+				if !isDroppingSignal {
+					pLogger.Warnw("dropping signal requests", err, "queueSize", signalQueue.Len())
+				}
+				isDroppingSignal = true
+			} else {
+				isDroppingSignal = false
 			}
 		}
 	}
@@ -670,6 +884,7 @@ func (r *RoomManager) RemoveParticipant(ctx context.Context, req *livekit.RoomPa
 	}
 
 	participant.GetLogger().Infow("removing participant")
+	participant.SetCloseAdminOptions(adminActionOptionsFromContext(ctx))
 	room.RemoveParticipant(livekit.ParticipantIdentity(req.Identity), "", types.ParticipantCloseReasonServiceRequestRemoveParticipant)
 	return &livekit.RemoveParticipantResponse{}, nil
 }
@@ -686,7 +901,7 @@ func (r *RoomManager) MutePublishedTrack(ctx context.Context, req *livekit.MuteR
 		participant.GetLogger().Errorw("cannot unmute track, remote unmute is disabled", nil)
 		return nil, ErrRemoteUnmuteNoteEnabled
 	}
-	track := participant.SetTrackMuted(livekit.TrackID(req.TrackSid), req.Muted, true)
+	track := participant.SetTrackMuted(livekit.TrackID(req.TrackSid), req.Muted, adminActionOptionsFromContext(ctx))
 	return &livekit.MuteRoomTrackResponse{Track: track}, nil
 }
 
@@ -700,7 +915,7 @@ func (r *RoomManager) UpdateParticipant(ctx context.Context, req *livekit.Update
 		"metadata", req.Metadata, "permission", req.Permission)
 	room.UpdateParticipantMetadata(participant, req.Name, req.Metadata)
 	if req.Permission != nil {
-		participant.SetPermission(req.Permission)
+		participant.SetPermission(req.Permission, adminActionOptionsFromContext(ctx))
 	}
 	return participant.ToProto(), nil
 }
@@ -717,6 +932,10 @@ func (r *RoomManager) DeleteRoom(ctx context.Context, req *livekit.DeleteRoomReq
 		}
 	} else {
 		room.Logger.Infow("deleting room")
+		adminOpts := adminActionOptionsFromContext(ctx)
+		for _, p := range room.GetParticipants() {
+			p.SetCloseAdminOptions(adminOpts)
+		}
 		room.Close(types.ParticipantCloseReasonServiceRequestDeleteRoom)
 	}
 	return &livekit.DeleteRoomResponse{}, nil
@@ -892,3 +1111,11 @@ func iceServerForStunServers(servers []string) *livekit.ICEServer {
 	}
 	return iceServer
 }
+
+func adminActionOptionsFromContext(ctx context.Context) *types.AdminActionOptions {
+	actor := GetAPIKey(ctx)
+	if grants := GetGrants(ctx); grants != nil && grants.Identity != "" {
+		actor = grants.Identity
+	}
+	return &types.AdminActionOptions{Actor: actor}
+}
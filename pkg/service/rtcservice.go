@@ -56,6 +56,8 @@ type RTCService struct {
 	parser        *uaparser.Parser
 	agentClient   agent.Client
 	telemetry     telemetry.TelemetryService
+	jobScheduler  *utils.JobScheduler
+	serverClock   utils.ServerClock
 
 	mu          sync.Mutex
 	connections map[*websocket.Conn]struct{}
@@ -69,6 +71,8 @@ func NewRTCService(
 	currentNode routing.LocalNode,
 	agentClient agent.Client,
 	telemetry telemetry.TelemetryService,
+	jobScheduler *utils.JobScheduler,
+	serverClock utils.ServerClock,
 ) *RTCService {
 	s := &RTCService{
 		router:        router,
@@ -82,6 +86,8 @@ func NewRTCService(
 		parser:        uaparser.NewFromSaved(),
 		agentClient:   agentClient,
 		telemetry:     telemetry,
+		jobScheduler:  jobScheduler,
+		serverClock:   serverClock,
 		connections:   map[*websocket.Conn]struct{}{},
 	}
 
@@ -249,7 +255,7 @@ func (s *RTCService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		pi.ID = livekit.ParticipantID(initialResponse.GetJoin().GetParticipant().GetSid())
 	}
 
-	signalStats := telemetry.NewBytesSignalStats(r.Context(), s.telemetry)
+	signalStats := telemetry.NewBytesSignalStats(r.Context(), s.telemetry, s.jobScheduler)
 	if join := initialResponse.GetJoin(); join != nil {
 		signalStats.ResolveRoom(join.GetRoom())
 		signalStats.ResolveParticipant(join.GetParticipant())
@@ -394,7 +400,7 @@ func (s *RTCService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 					// Although this field is int64, some clients (like JS) cause overflow if nanosecond granularity is used.
 					// So. use UnixMillis().
 					//
-					Pong: time.Now().UnixMilli(),
+					Pong: s.serverClock.Now().UnixMilli(),
 				},
 			})
 			if perr == nil {
@@ -405,7 +411,7 @@ func (s *RTCService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				Message: &livekit.SignalResponse_PongResp{
 					PongResp: &livekit.Pong{
 						LastPingTimestamp: m.PingReq.Timestamp,
-						Timestamp:         time.Now().UnixMilli(),
+						Timestamp:         s.serverClock.Now().UnixMilli(),
 					},
 				},
 			})
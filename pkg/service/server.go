@@ -294,8 +294,9 @@ func (s *LivekitServer) Start() error {
 }
 
 func (s *LivekitServer) Stop(force bool) {
-	// wait for all participants to exit
+	// wait for all participants to exit, or migrate out when resume state is carried over
 	s.router.Drain()
+	s.roomManager.Drain()
 	partTicker := time.NewTicker(5 * time.Second)
 	waitingForParticipants := !force && s.roomManager.HasParticipants()
 	for waitingForParticipants {
@@ -32,6 +32,8 @@ import (
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/agent"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -51,6 +53,8 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		createKeyProvider,
 		createWebhookNotifier,
 		createClientConfiguration,
+		createJobScheduler,
+		sutils.NewServerClock,
 		routing.CreateRouter,
 		getRoomConf,
 		config.DefaultAPIConfig,
@@ -219,6 +223,16 @@ func createClientConfiguration() clientconfiguration.ClientConfigurationManager
 	return clientconfiguration.NewStaticClientConfigurationManager(clientconfiguration.StaticConfigurations)
 }
 
+// createJobScheduler returns the scheduler of periodic jobs shared by all services of the node
+func createJobScheduler(conf *config.Config) *sutils.JobScheduler {
+	return sutils.NewJobScheduler(sutils.JobSchedulerParams{
+		ShedLoadThreshold: conf.Limit.PeriodicJobShedCPULoad,
+		MaxIntervalScale:  conf.Limit.PeriodicJobMaxIntervalScale,
+		GetLoad:           prometheus.GetCPULoad,
+		Logger:            logger.GetLogger(),
+	})
+}
+
 func getRoomConf(config *config.Config) config.RoomConfig {
 	return config.Room
 }
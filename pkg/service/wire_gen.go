@@ -13,6 +13,8 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	utils2 "github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -112,7 +114,9 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		return nil, err
 	}
 	sipService := NewSIPService(sipConfig, nodeID, messageBus, sipClient, sipStore, roomService, telemetryService)
-	rtcService := NewRTCService(conf, roomAllocator, objectStore, router, currentNode, client, telemetryService)
+	jobScheduler := createJobScheduler(conf)
+	serverClock := utils2.NewServerClock()
+	rtcService := NewRTCService(conf, roomAllocator, objectStore, router, currentNode, client, telemetryService, jobScheduler, serverClock)
 	agentService, err := NewAgentService(conf, currentNode, messageBus, keyProvider)
 	if err != nil {
 		return nil, err
@@ -120,7 +124,7 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	clientConfigurationManager := createClientConfiguration()
 	timedVersionGenerator := utils.NewDefaultTimedVersionGenerator()
 	turnAuthHandler := NewTURNAuthHandler(keyProvider)
-	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, telemetryService, clientConfigurationManager, client, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus)
+	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, telemetryService, clientConfigurationManager, client, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus, jobScheduler, serverClock)
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +274,16 @@ func createClientConfiguration() clientconfiguration.ClientConfigurationManager
 	return clientconfiguration.NewStaticClientConfigurationManager(clientconfiguration.StaticConfigurations)
 }
 
+// createJobScheduler returns the scheduler of periodic jobs shared by all services of the node
+func createJobScheduler(conf *config.Config) *utils2.JobScheduler {
+	return utils2.NewJobScheduler(utils2.JobSchedulerParams{
+		ShedLoadThreshold: conf.Limit.PeriodicJobShedCPULoad,
+		MaxIntervalScale:  conf.Limit.PeriodicJobMaxIntervalScale,
+		GetLoad:           prometheus.GetCPULoad,
+		Logger:            logger.GetLogger(),
+	})
+}
+
 func getRoomConf(config2 *config.Config) config.RoomConfig {
 	return config2.Room
 }
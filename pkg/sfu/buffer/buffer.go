@@ -66,6 +66,8 @@ type ExtPacket struct {
 	RawPacket            []byte
 	DependencyDescriptor *ExtDependencyDescriptor
 	AbsCaptureTimeExt    *act.AbsCaptureTime
+	// clock rate estimated from arrival times by the receiver, 0 if not estimated
+	EstimatedClockRate float64
 }
 
 // Buffer contains all packets
@@ -104,6 +106,7 @@ type Buffer struct {
 
 	pliThrottle int64
 
+	clockSkewParams      ClockSkewParams
 	rtpStats             *RTPStatsReceiver
 	rrSnapshotId         uint32
 	deltaStatsSnapshotId uint32
@@ -129,6 +132,8 @@ type Buffer struct {
 	frameRateCalculator [DefaultMaxLayerSpatial + 1]FrameRateCalculator
 	frameRateCalculated bool
 
+	temporalLayerCounters [DefaultMaxLayerSpatial + 1]*temporalLayerCounter
+
 	packetNotFoundCount   atomic.Uint32
 	packetTooOldCount     atomic.Uint32
 	extPacketTooMuchCount atomic.Uint32
@@ -137,6 +142,10 @@ type Buffer struct {
 	rtxPktBuf           []byte
 
 	absCaptureTimeExtID uint8
+
+	frameMarkingExtID uint8
+	// temporal layers of H.264 derived from payload when frame marking is not negotiated
+	h264FrameMarkingFromPayload bool
 }
 
 // NewBuffer constructs a new Buffer
@@ -155,6 +164,10 @@ func NewBuffer(ssrc uint32, maxVideoPkts, maxAudioPkts int) *Buffer {
 	return b
 }
 
+func (b *Buffer) SSRC() uint32 {
+	return b.mediaSSRC
+}
+
 func (b *Buffer) SetLogger(logger logger.Logger) {
 	b.Lock()
 	defer b.Unlock()
@@ -194,6 +207,14 @@ func (b *Buffer) SetAudioLossProxying(enable bool) {
 	b.enableAudioLossProxying = enable
 }
 
+// SetClockSkewParams should be called before Bind
+func (b *Buffer) SetClockSkewParams(clockSkewParams ClockSkewParams) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.clockSkewParams = clockSkewParams
+}
+
 func (b *Buffer) Bind(params webrtc.RTPParameters, codec webrtc.RTPCodecCapability) {
 	b.Lock()
 	defer b.Unlock()
@@ -204,6 +225,7 @@ func (b *Buffer) Bind(params webrtc.RTPParameters, codec webrtc.RTPCodecCapabili
 	b.rtpStats = NewRTPStatsReceiver(RTPStatsParams{
 		ClockRate: codec.ClockRate,
 		Logger:    b.logger,
+		ClockSkew: b.clockSkewParams,
 	})
 	b.rrSnapshotId = b.rtpStats.NewSnapshotId()
 	b.deltaStatsSnapshotId = b.rtpStats.NewSnapshotId()
@@ -242,9 +264,16 @@ func (b *Buffer) Bind(params webrtc.RTPParameters, codec webrtc.RTPCodecCapabili
 
 		case act.AbsCaptureTimeURI:
 			b.absCaptureTimeExtID = uint8(ext.ID)
+
+		case FrameMarkingURI:
+			b.frameMarkingExtID = uint8(ext.ID)
 		}
 	}
 
+	if b.mime == "video/h264" && b.frameMarkingExtID == 0 && b.ddExtID == 0 {
+		b.h264FrameMarkingFromPayload = IsH264WithoutBFrames(codec.SDPFmtpLine)
+	}
+
 	switch {
 	case strings.HasPrefix(b.mime, "audio/"):
 		b.codecType = webrtc.RTPCodecTypeAudio
@@ -252,6 +281,9 @@ func (b *Buffer) Bind(params webrtc.RTPParameters, codec webrtc.RTPCodecCapabili
 	case strings.HasPrefix(b.mime, "video/"):
 		b.codecType = webrtc.RTPCodecTypeVideo
 		b.bucket = bucket.NewBucket(InitPacketBufferSizeVideo)
+		for i := range b.temporalLayerCounters {
+			b.temporalLayerCounters[i] = newTemporalLayerCounter(temporalLayerDistributionWindow)
+		}
 		if b.frameRateCalculator[0] == nil {
 			if strings.EqualFold(codec.MimeType, webrtc.MimeTypeVP8) {
 				b.frameRateCalculator[0] = NewFrameRateCalculatorVP8(b.clockRate, b.logger)
@@ -591,6 +623,7 @@ func (b *Buffer) calc(rawPkt []byte, rtpPacket *rtp.Packet, arrivalTime time.Tim
 	}
 
 	b.doFpsCalc(ep)
+	b.doTemporalLayerCount(ep)
 }
 
 func (b *Buffer) patchExtPacket(ep *ExtPacket, buf []byte) *ExtPacket {
@@ -645,6 +678,17 @@ func (b *Buffer) doFpsCalc(ep *ExtPacket) {
 	}
 }
 
+func (b *Buffer) doTemporalLayerCount(ep *ExtPacket) {
+	spatial := ep.Spatial
+	if spatial < 0 || int(spatial) >= len(b.temporalLayerCounters) {
+		spatial = 0
+	}
+	if tc := b.temporalLayerCounters[spatial]; tc != nil {
+		// padding only packets have invalid temporal layer and are ignored
+		tc.Add(ep.Temporal, ep.Arrival)
+	}
+}
+
 func (b *Buffer) updateStreamState(p *rtp.Packet, arrivalTime time.Time) RTPFlowState {
 	flowState := b.rtpStats.Update(
 		arrivalTime,
@@ -754,6 +798,20 @@ func (b *Buffer) getExtPacket(rtpPacket *rtp.Packet, arrivalTime time.Time, flow
 
 	case "video/h264":
 		ep.KeyFrame = IsH264KeyFrame(rtpPacket.Payload)
+		if ep.DependencyDescriptor == nil && b.frameMarkingExtID != 0 {
+			if extData := rtpPacket.GetExtension(b.frameMarkingExtID); extData != nil {
+				frameMarking := FrameMarking{}
+				if err := frameMarking.Unmarshal(extData); err == nil {
+					ep.Temporal = int32(frameMarking.TID)
+					ep.Payload = frameMarking
+				}
+			}
+		} else if b.h264FrameMarkingFromPayload {
+			if frameMarking, ok := H264FrameMarking(rtpPacket.Payload, rtpPacket.Marker); ok {
+				ep.Temporal = int32(frameMarking.TID)
+				ep.Payload = frameMarking
+			}
+		}
 
 	case "video/av1":
 		ep.KeyFrame = IsAV1KeyFrame(rtpPacket.Payload)
@@ -904,9 +962,34 @@ func (b *Buffer) getRTCP() []rtcp.Packet {
 		})
 	}
 
+	if b.rtpStats != nil {
+		if xr := b.rtpStats.GetRtcpXrReport(b.mediaSSRC); xr != nil {
+			pkts = append(pkts, xr)
+		}
+	}
+
 	return pkts
 }
 
+// HandleDLRR updates round trip time from a response to a receiver reference time report sent by
+// this buffer and returns it, returns an error if the report cannot be used.
+func (b *Buffer) HandleDLRR(report rtcp.DLRRReport) (uint32, error) {
+	b.RLock()
+	rtpStats := b.rtpStats
+	b.RUnlock()
+	if rtpStats == nil {
+		return 0, errors.New("buffer not bound")
+	}
+
+	rtt, err := rtpStats.GetRttFromDLRR(report)
+	if err != nil {
+		return 0, err
+	}
+
+	b.SetRTT(rtt)
+	return rtt, nil
+}
+
 func (b *Buffer) GetPacket(buff []byte, sn uint16) (int, error) {
 	b.Lock()
 	defer b.Unlock()
@@ -1003,6 +1086,22 @@ func (b *Buffer) OnFpsChanged(f func()) {
 	b.Unlock()
 }
 
+// GetTemporalLayerDistributionForSpatial returns how media packets of a spatial layer
+// were spread across temporal layers over the recent window
+func (b *Buffer) GetTemporalLayerDistributionForSpatial(layer int32) TemporalLayerDistribution {
+	b.Lock()
+	defer b.Unlock()
+
+	if layer < 0 || int(layer) >= len(b.temporalLayerCounters) {
+		return TemporalLayerDistribution{}
+	}
+
+	if tc := b.temporalLayerCounters[layer]; tc != nil {
+		return tc.Distribution(time.Now())
+	}
+	return TemporalLayerDistribution{}
+}
+
 func (b *Buffer) GetTemporalLayerFpsForSpatial(layer int32) []float32 {
 	if int(layer) >= len(b.frameRateCalculator) {
 		return nil
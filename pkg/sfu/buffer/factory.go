@@ -24,12 +24,14 @@ import (
 type FactoryOfBufferFactory struct {
 	trackingPacketsVideo int
 	trackingPacketsAudio int
+	clockSkewParams      ClockSkewParams
 }
 
-func NewFactoryOfBufferFactory(trackingPacketsVideo int, trackingPacketsAudio int) *FactoryOfBufferFactory {
+func NewFactoryOfBufferFactory(trackingPacketsVideo int, trackingPacketsAudio int, clockSkewParams ClockSkewParams) *FactoryOfBufferFactory {
 	return &FactoryOfBufferFactory{
 		trackingPacketsVideo: trackingPacketsVideo,
 		trackingPacketsAudio: trackingPacketsAudio,
+		clockSkewParams:      clockSkewParams,
 	}
 }
 
@@ -37,6 +39,7 @@ func (f *FactoryOfBufferFactory) CreateBufferFactory() *Factory {
 	return &Factory{
 		trackingPacketsVideo: f.trackingPacketsVideo,
 		trackingPacketsAudio: f.trackingPacketsAudio,
+		clockSkewParams:      f.clockSkewParams,
 		rtpBuffers:           make(map[uint32]*Buffer),
 		rtcpReaders:          make(map[uint32]*RTCPReader),
 		rtxPair:              make(map[uint32]uint32),
@@ -47,6 +50,7 @@ type Factory struct {
 	sync.RWMutex
 	trackingPacketsVideo int
 	trackingPacketsAudio int
+	clockSkewParams      ClockSkewParams
 	rtpBuffers           map[uint32]*Buffer
 	rtcpReaders          map[uint32]*RTCPReader
 	rtxPair              map[uint32]uint32 // repair -> base
@@ -73,6 +77,7 @@ func (f *Factory) GetOrNew(packetType packetio.BufferPacketType, ssrc uint32) io
 			return reader
 		}
 		buffer := NewBuffer(ssrc, f.trackingPacketsVideo, f.trackingPacketsAudio)
+		buffer.SetClockSkewParams(f.clockSkewParams)
 		f.rtpBuffers[ssrc] = buffer
 		for repair, base := range f.rtxPair {
 			if repair == ssrc {
@@ -0,0 +1,162 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+const FrameMarkingURI = "urn:ietf:params:rtp-hdrext:framemarking"
+
+// FrameMarking is a helper to get temporal data from frame marking header extension
+/*
+	Frame Marking, non-scalable streams
+			0 1 2 3 4 5 6 7
+			+-+-+-+-+-+-+-+-+
+			|S|E|I|D|0 0 0 0|
+			+-+-+-+-+-+-+-+-+
+
+	Frame Marking, scalable streams
+			0 1 2 3 4 5 6 7
+			+-+-+-+-+-+-+-+-+
+			|S|E|I|D|B| TID |
+			+-+-+-+-+-+-+-+-+
+			|      LID      | (OPTIONAL)
+			+-+-+-+-+-+-+-+-+
+			|   TL0PICIDX   | (OPTIONAL)
+			+-+-+-+-+-+-+-+-+
+
+	S: start of frame
+	E: end of frame
+	I: independent frame, can be decoded without reference to other frames
+	D: discardable frame, not used as reference by other frames
+	B: base layer sync, frame with TID > 0 depends only on base temporal layer
+*/
+type FrameMarking struct {
+	S         bool
+	E         bool
+	I         bool
+	D         bool
+	B         bool
+	TID       uint8
+	LID       uint8
+	TL0PICIDX uint8
+}
+
+// Unmarshal parses the frame marking header extension data
+func (f *FrameMarking) Unmarshal(extData []byte) error {
+	if f == nil {
+		return errNilPacket
+	}
+
+	if len(extData) == 0 {
+		return errShortPacket
+	}
+
+	f.S = extData[0]&0x80 > 0
+	f.E = extData[0]&0x40 > 0
+	f.I = extData[0]&0x20 > 0
+	f.D = extData[0]&0x10 > 0
+	f.B = extData[0]&0x08 > 0
+	f.TID = extData[0] & 0x07
+	if len(extData) > 1 {
+		f.LID = extData[1]
+	}
+	if len(extData) > 2 {
+		f.TL0PICIDX = extData[2]
+	}
+	return nil
+}
+
+// -------------------------------------
+
+// IsH264WithoutBFrames returns true if H.264 profile signalled in fmtp line cannot carry B-frames,
+// i. e. Baseline and Constrained Baseline profiles
+func IsH264WithoutBFrames(fmtpLine string) bool {
+	for _, param := range strings.Split(fmtpLine, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(key, "profile-level-id") || len(value) != 6 {
+			continue
+		}
+
+		profileIdc, err := hex.DecodeString(value[:2])
+		return err == nil && profileIdc[0] == 66
+	}
+	return false
+}
+
+// H264FrameMarking derives frame marking of a H.264 packet from its payload, for streams which
+// do not negotiate the frame marking extension. It is valid only for streams without B-frames,
+// i. e. non-reference frames are never referenced. Non-reference frames are marked as discardable
+// temporal layer 1 frames depending only on temporal layer 0, reference frames as temporal layer 0.
+// Returns false if packet does not carry a slice.
+func H264FrameMarking(payload []byte, marker bool) (FrameMarking, bool) {
+	if len(payload) < 2 {
+		return FrameMarking{}, false
+	}
+
+	var (
+		nri      byte
+		nalType  byte
+		start    bool
+		sliceHdr []byte
+	)
+	switch naluType := payload[0] & 0x1F; {
+	case naluType >= 1 && naluType <= 23:
+		nri, nalType, start, sliceHdr = payload[0]>>5&0x03, naluType, true, payload[1:]
+
+	case naluType == 24:
+		// STAP-A, slice follows parameter sets when aggregated
+		for i := 1; i+2 < len(payload); {
+			length := int(payload[i])<<8 | int(payload[i+1])
+			i += 2
+			if length == 0 || i+length > len(payload) {
+				return FrameMarking{}, false
+			}
+
+			nal := payload[i : i+length]
+			if t := nal[0] & 0x1F; t == 1 || t == 5 {
+				nri, nalType, start, sliceHdr = nal[0]>>5&0x03, t, true, nal[1:]
+				break
+			}
+			i += length
+		}
+
+	case naluType == 28:
+		// FU-A
+		if len(payload) < 3 {
+			return FrameMarking{}, false
+		}
+		nri, nalType, start, sliceHdr = payload[0]>>5&0x03, payload[1]&0x1F, payload[1]&0x80 != 0, payload[2:]
+	}
+
+	if nalType != 1 && nalType != 5 {
+		return FrameMarking{}, false
+	}
+
+	fm := FrameMarking{
+		// first_mb_in_slice opens the slice header, 0 is coded as a single set bit in exp-Golomb
+		S: start && len(sliceHdr) > 0 && sliceHdr[0]&0x80 != 0,
+		E: marker,
+		I: nalType == 5,
+		D: nri == 0,
+	}
+	if fm.D {
+		fm.B = true
+		fm.TID = 1
+	}
+	return fm, true
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameMarkingUnmarshal(t *testing.T) {
+	fm := FrameMarking{}
+	require.Error(t, fm.Unmarshal(nil))
+
+	// non-scalable, start and end of independent frame
+	require.NoError(t, fm.Unmarshal([]byte{0xe0}))
+	require.Equal(t, FrameMarking{S: true, E: true, I: true}, fm)
+
+	// scalable, discardable base layer synced frame of temporal layer 2
+	fm = FrameMarking{}
+	require.NoError(t, fm.Unmarshal([]byte{0x9a, 0x01, 0x2a}))
+	require.Equal(t, FrameMarking{S: true, D: true, B: true, TID: 2, LID: 1, TL0PICIDX: 42}, fm)
+}
+
+func TestIsH264WithoutBFrames(t *testing.T) {
+	require.True(t, IsH264WithoutBFrames("level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f"))
+	require.True(t, IsH264WithoutBFrames("profile-level-id=42001f; packetization-mode=1"))
+	require.False(t, IsH264WithoutBFrames("level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=640032"))
+	require.False(t, IsH264WithoutBFrames("packetization-mode=1"))
+	require.False(t, IsH264WithoutBFrames("profile-level-id=zz001f"))
+	require.False(t, IsH264WithoutBFrames(""))
+}
+
+func TestH264FrameMarking(t *testing.T) {
+	// SEI is not a slice
+	_, ok := H264FrameMarking([]byte{0x06, 0x05, 0x10}, false)
+	require.False(t, ok)
+
+	// single NAL reference frame, first slice
+	fm, ok := H264FrameMarking([]byte{0x41, 0x9a, 0x01}, true)
+	require.True(t, ok)
+	require.Equal(t, FrameMarking{S: true, E: true}, fm)
+
+	// single NAL non-reference frame, not the first slice
+	fm, ok = H264FrameMarking([]byte{0x01, 0x05, 0x3e}, false)
+	require.True(t, ok)
+	require.Equal(t, FrameMarking{D: true, B: true, TID: 1}, fm)
+
+	// STAP-A with parameter sets and IDR slice
+	fm, ok = H264FrameMarking([]byte{0x78, 0x00, 0x02, 0x67, 0x42, 0x00, 0x02, 0x68, 0xce, 0x00, 0x02, 0x65, 0x88}, false)
+	require.True(t, ok)
+	require.Equal(t, FrameMarking{S: true, I: true}, fm)
+
+	// STAP-A with parameter sets only
+	_, ok = H264FrameMarking([]byte{0x78, 0x00, 0x02, 0x67, 0x42, 0x00, 0x02, 0x68, 0xce}, false)
+	require.False(t, ok)
+
+	// STAP-A with truncated NAL
+	_, ok = H264FrameMarking([]byte{0x78, 0x00, 0x08, 0x67, 0x42}, false)
+	require.False(t, ok)
+
+	// FU-A start and end fragments of non-reference frame
+	fm, ok = H264FrameMarking([]byte{0x1c, 0x81, 0x9a, 0x01}, false)
+	require.True(t, ok)
+	require.Equal(t, FrameMarking{S: true, D: true, B: true, TID: 1}, fm)
+
+	fm, ok = H264FrameMarking([]byte{0x1c, 0x41, 0xff, 0x01}, true)
+	require.True(t, ok)
+	require.Equal(t, FrameMarking{E: true, D: true, B: true, TID: 1}, fm)
+}
@@ -20,12 +20,15 @@ import (
 	"time"
 
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
+
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 const (
@@ -76,6 +79,16 @@ type RTPDeltaInfo struct {
 	Firs                 uint32
 }
 
+// RTPDeltaInfoExt adds rates and largest loss burst over the interval to RTPDeltaInfo
+type RTPDeltaInfoExt struct {
+	RTPDeltaInfo
+	Duration  time.Duration
+	Bitrate   float64
+	FrameRate float64
+	// largest number of consecutive packets lost
+	MaxGap uint32
+}
+
 type snapshot struct {
 	isValid bool
 
@@ -105,6 +118,7 @@ type snapshot struct {
 
 	maxRtt    uint32
 	maxJitter float64
+	maxGap    uint32
 }
 
 // ------------------------------------------------------------------
@@ -153,6 +167,18 @@ func (r *RTCPSenderReportData) MarshalLogObject(e zapcore.ObjectEncoder) error {
 type RTPStatsParams struct {
 	ClockRate uint32
 	Logger    logger.Logger
+
+	// receiver only
+	ClockSkew ClockSkewParams
+	// receiver only, called without holding the lock when propagation delay estimate is reset
+	// due to a sustained increase, usually a network path change
+	OnPropagationDelayReset func(old, new time.Duration)
+	// receiver only, called without holding the lock with clock rate calculated from sender reports,
+	// when clock skew is first detected and then at most once every 30 seconds while it persists
+	OnClockSkewDetected func(calculatedClockRate float64)
+
+	// sender only, clock of sender reports, system clock when not set
+	ServerClock sutils.ServerClock
 }
 
 type rtpStatsBase struct {
@@ -303,6 +329,194 @@ func (r *rtpStatsBase) seed(from *rtpStatsBase) bool {
 	return true
 }
 
+// marshalWire encodes cumulative state using field numbers [1, 50).
+// Snapshots are not encoded, they are re-initialised from start on first use after unmarshalling.
+func (r *rtpStatsBase) marshalWire(e *sutils.WireEncoder) {
+	e.Bool(1, r.initialized)
+	e.Time(2, r.startTime)
+	e.Time(3, r.firstTime)
+	e.Time(4, r.highestTime)
+	e.Uint64(5, r.lastTransit)
+	e.Uint64(6, r.lastJitterExtTimestamp)
+	e.Uint64(7, r.bytes)
+	e.Uint64(8, r.headerBytes)
+	e.Uint64(9, r.bytesDuplicate)
+	e.Uint64(10, r.headerBytesDuplicate)
+	e.Uint64(11, r.bytesPadding)
+	e.Uint64(12, r.headerBytesPadding)
+	e.Uint64(13, r.packetsDuplicate)
+	e.Uint64(14, r.packetsPadding)
+	e.Uint64(15, r.packetsOutOfOrder)
+	e.Uint64(16, r.packetsLost)
+	e.Uint64(17, uint64(r.frames))
+	e.Float64(18, r.jitter)
+	e.Float64(19, r.maxJitter)
+
+	var gapHistogram []byte
+	for _, count := range r.gapHistogram {
+		gapHistogram = protowire.AppendVarint(gapHistogram, uint64(count))
+	}
+	e.Bytes(20, gapHistogram)
+
+	e.Uint64(21, uint64(r.nacks))
+	e.Uint64(22, uint64(r.nackAcks))
+	e.Uint64(23, uint64(r.nackMisses))
+	e.Uint64(24, uint64(r.nackRepeated))
+	e.Uint64(25, uint64(r.plis))
+	e.Time(26, r.lastPli)
+	e.Uint64(27, uint64(r.layerLockPlis))
+	e.Time(28, r.lastLayerLockPli)
+	e.Uint64(29, uint64(r.firs))
+	e.Time(30, r.lastFir)
+	e.Uint64(31, uint64(r.keyFrames))
+	e.Time(32, r.lastKeyFrame)
+	e.Uint64(33, uint64(r.rtt))
+	e.Uint64(34, uint64(r.maxRtt))
+	if r.srFirst != nil {
+		e.Message(35, marshalSenderReportData(r.srFirst))
+	}
+	if r.srNewest != nil {
+		e.Message(36, marshalSenderReportData(r.srNewest))
+	}
+	e.Uint64(37, uint64(r.nextSnapshotID))
+}
+
+func (r *rtpStatsBase) unmarshalWireField(num protowire.Number, f sutils.WireField) error {
+	switch num {
+	case 1:
+		r.initialized = f.Bool()
+	case 2:
+		r.startTime = f.Time()
+	case 3:
+		r.firstTime = f.Time()
+	case 4:
+		r.highestTime = f.Time()
+	case 5:
+		r.lastTransit = f.Uint64()
+	case 6:
+		r.lastJitterExtTimestamp = f.Uint64()
+	case 7:
+		r.bytes = f.Uint64()
+	case 8:
+		r.headerBytes = f.Uint64()
+	case 9:
+		r.bytesDuplicate = f.Uint64()
+	case 10:
+		r.headerBytesDuplicate = f.Uint64()
+	case 11:
+		r.bytesPadding = f.Uint64()
+	case 12:
+		r.headerBytesPadding = f.Uint64()
+	case 13:
+		r.packetsDuplicate = f.Uint64()
+	case 14:
+		r.packetsPadding = f.Uint64()
+	case 15:
+		r.packetsOutOfOrder = f.Uint64()
+	case 16:
+		r.packetsLost = f.Uint64()
+	case 17:
+		r.frames = uint32(f.Uint64())
+	case 18:
+		r.jitter = f.Float64()
+	case 19:
+		r.maxJitter = f.Float64()
+	case 20:
+		data := f.Bytes()
+		for i := 0; i < len(r.gapHistogram) && len(data) > 0; i++ {
+			count, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return sutils.ErrWireMalformed
+			}
+			r.gapHistogram[i] = uint32(count)
+			data = data[n:]
+		}
+	case 21:
+		r.nacks = uint32(f.Uint64())
+	case 22:
+		r.nackAcks = uint32(f.Uint64())
+	case 23:
+		r.nackMisses = uint32(f.Uint64())
+	case 24:
+		r.nackRepeated = uint32(f.Uint64())
+	case 25:
+		r.plis = uint32(f.Uint64())
+	case 26:
+		r.lastPli = f.Time()
+	case 27:
+		r.layerLockPlis = uint32(f.Uint64())
+	case 28:
+		r.lastLayerLockPli = f.Time()
+	case 29:
+		r.firs = uint32(f.Uint64())
+	case 30:
+		r.lastFir = f.Time()
+	case 31:
+		r.keyFrames = uint32(f.Uint64())
+	case 32:
+		r.lastKeyFrame = f.Time()
+	case 33:
+		r.rtt = uint32(f.Uint64())
+	case 34:
+		r.maxRtt = uint32(f.Uint64())
+	case 35:
+		srFirst, err := unmarshalSenderReportData(f.Bytes())
+		if err != nil {
+			return err
+		}
+		r.srFirst = srFirst
+	case 36:
+		srNewest, err := unmarshalSenderReportData(f.Bytes())
+		if err != nil {
+			return err
+		}
+		r.srNewest = srNewest
+	case 37:
+		r.nextSnapshotID = uint32(f.Uint64())
+		if int(r.nextSnapshotID-cFirstSnapshotID) > cap(r.snapshots) {
+			r.snapshots = make([]snapshot, r.nextSnapshotID-cFirstSnapshotID)
+		}
+	}
+	return nil
+}
+
+func marshalSenderReportData(srData *RTCPSenderReportData) []byte {
+	if srData == nil {
+		return nil
+	}
+
+	e := &sutils.WireEncoder{}
+	e.Uint64(1, uint64(srData.RTPTimestamp))
+	e.Uint64(2, srData.RTPTimestampExt)
+	e.Uint64(3, uint64(srData.NTPTimestamp))
+	e.Time(4, srData.At)
+	e.Time(5, srData.AtAdjusted)
+	return e.Encoded()
+}
+
+func unmarshalSenderReportData(data []byte) (*RTCPSenderReportData, error) {
+	srData := &RTCPSenderReportData{}
+	err := sutils.DecodeWire(data, func(num protowire.Number, f sutils.WireField) error {
+		switch num {
+		case 1:
+			srData.RTPTimestamp = uint32(f.Uint64())
+		case 2:
+			srData.RTPTimestampExt = f.Uint64()
+		case 3:
+			srData.NTPTimestamp = mediatransportutil.NtpTime(f.Uint64())
+		case 4:
+			srData.At = f.Time()
+		case 5:
+			srData.AtAdjusted = f.Time()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return srData, nil
+}
+
 func (r *rtpStatsBase) SetLogger(logger logger.Logger) {
 	r.logger = logger
 }
@@ -564,7 +778,35 @@ func (r *rtpStatsBase) getTotalPacketsPrimary(extStartSN, extHighestSN uint64) u
 }
 
 func (r *rtpStatsBase) deltaInfo(snapshotID uint32, extStartSN uint64, extHighestSN uint64) *RTPDeltaInfo {
-	then, now := r.getAndResetSnapshot(snapshotID, extStartSN, extHighestSN)
+	return r.getDeltaInfo(r.getAndResetSnapshot(snapshotID, extStartSN, extHighestSN))
+}
+
+func (r *rtpStatsBase) deltaInfoExt(snapshotID uint32, extStartSN uint64, extHighestSN uint64, reset bool) *RTPDeltaInfoExt {
+	var then, now *snapshot
+	if reset {
+		then, now = r.getAndResetSnapshot(snapshotID, extStartSN, extHighestSN)
+	} else {
+		then, now = r.peekSnapshot(snapshotID, extStartSN, extHighestSN)
+	}
+
+	deltaInfo := r.getDeltaInfo(then, now)
+	if deltaInfo == nil {
+		return nil
+	}
+
+	deltaInfoExt := &RTPDeltaInfoExt{
+		RTPDeltaInfo: *deltaInfo,
+		Duration:     deltaInfo.EndTime.Sub(deltaInfo.StartTime),
+		MaxGap:       then.maxGap,
+	}
+	if elapsed := deltaInfoExt.Duration.Seconds(); elapsed > 0.0 {
+		deltaInfoExt.Bitrate = float64(deltaInfo.Bytes) * 8.0 / elapsed
+		deltaInfoExt.FrameRate = float64(deltaInfo.Frames) / elapsed
+	}
+	return deltaInfoExt
+}
+
+func (r *rtpStatsBase) getDeltaInfo(then *snapshot, now *snapshot) *RTPDeltaInfo {
 	if now == nil || then == nil {
 		return nil
 	}
@@ -845,7 +1087,18 @@ func (r *rtpStatsBase) updateJitter(ets uint64, packetTime time.Time) float64 {
 }
 
 func (r *rtpStatsBase) getAndResetSnapshot(snapshotID uint32, extStartSN uint64, extHighestSN uint64) (*snapshot, *snapshot) {
-	if !r.initialized {
+	then, now := r.peekSnapshot(snapshotID, extStartSN, extHighestSN)
+	if then == nil || now == nil {
+		return nil, nil
+	}
+
+	r.snapshots[snapshotID-cFirstSnapshotID] = *now
+	return then, now
+}
+
+// peekSnapshot returns the snapshot and a snapshot of now, without resetting the snapshot
+func (r *rtpStatsBase) peekSnapshot(snapshotID uint32, extStartSN uint64, extHighestSN uint64) (*snapshot, *snapshot) {
+	if !r.initialized || snapshotID < cFirstSnapshotID || snapshotID >= r.nextSnapshotID {
 		return nil, nil
 	}
 
@@ -858,7 +1111,6 @@ func (r *rtpStatsBase) getAndResetSnapshot(snapshotID uint32, extStartSN uint64,
 
 	// snapshot now
 	now := r.getSnapshot(time.Now(), extHighestSN+1)
-	r.snapshots[idx] = now
 	return &then, &now
 }
 
@@ -931,6 +1183,13 @@ func (r *rtpStatsBase) updateGapHistogram(gap int) {
 	} else {
 		r.gapHistogram[missing-1]++
 	}
+
+	for i := uint32(0); i < r.nextSnapshotID-cFirstSnapshotID; i++ {
+		s := &r.snapshots[i]
+		if uint32(missing) > s.maxGap {
+			s.maxGap = uint32(missing)
+		}
+	}
 }
 
 func (r *rtpStatsBase) initSnapshot(startTime time.Time, extStartSN uint64) snapshot {
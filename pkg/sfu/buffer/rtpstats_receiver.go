@@ -15,13 +15,16 @@
 package buffer
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"time"
 
 	"github.com/pion/rtcp"
 
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
+	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/livekit"
 	protoutils "github.com/livekit/protocol/utils"
 )
@@ -59,8 +62,47 @@ const (
 
 	// number of seconds the current report RTP timestamp can be off from expected RTP timestamp
 	cReportSlack = float64(60.0)
+
+	// deviation of clock rate calculated from sender reports, as a fraction of clock rate, considered clock skew
+	cClockSkewThreshold = float64(0.2)
+	// minimum interval between clock skew notifications after the first one
+	cClockSkewNotifyInterval = 30 * time.Second
+
+	// number of sent receiver reference time reports a DLRR can refer to,
+	// remote echoes the latest it received which may not be the latest sent
+	cRRTRHistorySize = 4
 )
 
+var (
+	errDLRRNoLastRR      = errors.New("no last receiver reference time report")
+	errDLRRUnknownLastRR = errors.New("unknown last receiver reference time report")
+	errDLRRDuplicate     = errors.New("duplicate DLRR")
+	errDLRRAnachronous   = errors.New("anachronous DLRR")
+)
+
+// ClockSkewParams controls handling of sender reports with RTP timestamps not advancing at the clock rate.
+// Propagation delay estimate of a publisher with a skewed clock degrades, with ResetOnPersistentClockSkew,
+// it is re-initialized after PersistentClockSkewThreshold consecutive sender reports with clock skew,
+// similar to the reset on a path change.
+type ClockSkewParams struct {
+	ResetOnPersistentClockSkew   bool
+	PersistentClockSkewThreshold int
+	// deviation of calculated clock rate as a fraction of clock rate considered clock skew, 0 uses default
+	Threshold float64
+}
+
+// PropagationDelayStats is the state of sender report propagation delay estimation,
+// all zero before the first sender report is processed
+type PropagationDelayStats struct {
+	PropagationDelay              time.Duration
+	LongTermDeltaPropagationDelay time.Duration
+	// number of sender reports with a sharp increase in propagation delay, and since when,
+	// a sustained increase is considered a path change and re-initializes the estimate to the spike
+	DeltaHighCount     int
+	DeltaHighStartTime time.Time
+	Spike              time.Duration
+}
+
 type RTPFlowState struct {
 	IsNotHandled bool
 
@@ -91,7 +133,14 @@ type RTPStatsReceiver struct {
 	propagationDelaySpike              time.Duration
 
 	clockSkewCount               int
+	clockSkewConsecutiveCount    int
+	clockSkewNotifiedAt          time.Time
 	outOfOrderSsenderReportCount int
+
+	// middle 32 bits of NTP timestamp of recently sent RTCP XR receiver reference time reports
+	rrtrSent    [cRRTRHistorySize]uint32
+	rrtrSentIdx int
+	dlrrLastRR  uint32
 }
 
 func NewRTPStatsReceiver(params RTPStatsParams) *RTPStatsReceiver {
@@ -281,8 +330,18 @@ func (r *RTPStatsReceiver) Update(
 }
 
 func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData) {
+	var notifyPropagationDelayReset, notifyClockSkew func()
 	r.lock.Lock()
-	defer r.lock.Unlock()
+	defer func() {
+		r.lock.Unlock()
+
+		if notifyClockSkew != nil {
+			notifyClockSkew()
+		}
+		if notifyPropagationDelayReset != nil {
+			notifyPropagationDelayReset()
+		}
+	}()
 
 	if srData == nil || !r.initialized {
 		return
@@ -367,8 +426,24 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 		rtpDiffSinceFirst := srDataCopy.RTPTimestampExt - r.srFirst.RTPTimestampExt
 		calculatedClockRateFromFirst := float64(rtpDiffSinceFirst) / timeSinceFirst
 
-		if (timeSinceLast > 0.2 && math.Abs(float64(r.params.ClockRate)-calculatedClockRateFromLast) > 0.2*float64(r.params.ClockRate)) ||
-			(timeSinceFirst > 0.2 && math.Abs(float64(r.params.ClockRate)-calculatedClockRateFromFirst) > 0.2*float64(r.params.ClockRate)) {
+		threshold := r.params.ClockSkew.Threshold
+		if threshold <= 0 {
+			threshold = cClockSkewThreshold
+		}
+		isSkewedFromLast := timeSinceLast > 0.2 && math.Abs(float64(r.params.ClockRate)-calculatedClockRateFromLast) > threshold*float64(r.params.ClockRate)
+		isSkewedFromFirst := timeSinceFirst > 0.2 && math.Abs(float64(r.params.ClockRate)-calculatedClockRateFromFirst) > threshold*float64(r.params.ClockRate)
+		if isSkewedFromLast || isSkewedFromFirst {
+			if onClockSkew := r.params.OnClockSkewDetected; onClockSkew != nil &&
+				(r.clockSkewNotifiedAt.IsZero() || time.Since(r.clockSkewNotifiedAt) >= cClockSkewNotifyInterval) {
+				r.clockSkewNotifiedAt = time.Now()
+				calculatedClockRate := calculatedClockRateFromFirst
+				if isSkewedFromLast {
+					calculatedClockRate = calculatedClockRateFromLast
+				}
+				notifyClockSkew = func() {
+					onClockSkew(calculatedClockRate)
+				}
+			}
 			if r.clockSkewCount%100 == 0 {
 				r.logger.Infow(
 					"received sender report, clock skew",
@@ -385,6 +460,9 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 				)
 			}
 			r.clockSkewCount++
+			r.clockSkewConsecutiveCount++
+		} else {
+			r.clockSkewConsecutiveCount = 0
 		}
 	}
 
@@ -423,6 +501,13 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 		r.srFirst = &srDataCopy
 		initPropagationDelay(propagationDelay)
 		r.logger.Debugw("initializing propagation delay", getPropagationFields()...)
+	} else if r.isClockSkewPersistent() {
+		r.logger.Infow(
+			"re-initializing propagation delay, persistent clock skew",
+			append(getPropagationFields(), "clockSkewConsecutiveCount", r.clockSkewConsecutiveCount)...,
+		)
+		initPropagationDelay(propagationDelay)
+		r.clockSkewConsecutiveCount = 0
 	} else {
 		deltaPropagationDelay = propagationDelay - r.propagationDelay
 		if deltaPropagationDelay > cPropagationDelayDeltaThresholdMin { // ignore small changes for path change consideration
@@ -442,6 +527,12 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 
 				if r.propagationDelayDeltaHighCount >= cPropagationDelayDeltaHighResetNumReports && time.Since(r.propagationDelayDeltaHighStartTime) >= cPropagationDelayDeltaHighResetWait {
 					r.logger.Debugw("re-initializing propagation delay", append(getPropagationFields(), "newPropagationDelay", r.propagationDelaySpike.String())...)
+					if onReset := r.params.OnPropagationDelayReset; onReset != nil {
+						oldPropagationDelay, newPropagationDelay := r.propagationDelay, r.propagationDelaySpike
+						notifyPropagationDelayReset = func() {
+							onReset(oldPropagationDelay, newPropagationDelay)
+						}
+					}
 					initPropagationDelay(r.propagationDelaySpike)
 				}
 			} else {
@@ -476,6 +567,13 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 	r.maybeAdjustFirstPacketTime(r.srNewest, 0, r.timestamp.GetExtendedStart())
 }
 
+func (r *RTPStatsReceiver) isClockSkewPersistent() bool {
+	clockSkew := r.params.ClockSkew
+	return clockSkew.ResetOnPersistentClockSkew &&
+		clockSkew.PersistentClockSkewThreshold > 0 &&
+		r.clockSkewConsecutiveCount >= clockSkew.PersistentClockSkewThreshold
+}
+
 func (r *RTPStatsReceiver) GetRtcpSenderReportData() *RTCPSenderReportData {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -488,6 +586,26 @@ func (r *RTPStatsReceiver) GetRtcpSenderReportData() *RTCPSenderReportData {
 	return &srNewestCopy
 }
 
+func (r *RTPStatsReceiver) GetPropagationDelay() time.Duration {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.propagationDelay
+}
+
+func (r *RTPStatsReceiver) GetPropagationDelayStats() PropagationDelayStats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return PropagationDelayStats{
+		PropagationDelay:              r.propagationDelay,
+		LongTermDeltaPropagationDelay: r.longTermDeltaPropagationDelay,
+		DeltaHighCount:                r.propagationDelayDeltaHighCount,
+		DeltaHighStartTime:            r.propagationDelayDeltaHighStartTime,
+		Spike:                         r.propagationDelaySpike,
+	}
+}
+
 func (r *RTPStatsReceiver) LastSenderReportTime() time.Time {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -557,6 +675,65 @@ func (r *RTPStatsReceiver) GetRtcpReceptionReport(ssrc uint32, proxyFracLost uin
 	}
 }
 
+// GetRtcpXrReport returns an RTCP XR with a receiver reference time report (RFC 3611 section 4.4),
+// remote responds with a DLRR block which allows measuring round trip time on a receive only path.
+func (r *RTPStatsReceiver) GetRtcpXrReport(ssrc uint32) *rtcp.ExtendedReport {
+	return r.getRtcpXrReport(ssrc, time.Now())
+}
+
+func (r *RTPStatsReceiver) getRtcpXrReport(ssrc uint32, at time.Time) *rtcp.ExtendedReport {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.initialized || !r.endTime.IsZero() {
+		return nil
+	}
+
+	ntpTime := mediatransportutil.ToNtpTime(at)
+	r.rrtrSent[r.rrtrSentIdx] = ntpMiddle32(ntpTime)
+	r.rrtrSentIdx = (r.rrtrSentIdx + 1) % cRRTRHistorySize
+
+	return &rtcp.ExtendedReport{
+		SenderSSRC: ssrc,
+		Reports: []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{
+				NTPTimestamp: uint64(ntpTime),
+			},
+		},
+	}
+}
+
+// GetRttFromDLRR returns round trip time in milliseconds from a DLRR sub-block (RFC 3611 section 4.5)
+// responding to a receiver reference time report sent by GetRtcpXrReport.
+// Each report is used once, repeats of the same report return an error.
+func (r *RTPStatsReceiver) GetRttFromDLRR(report rtcp.DLRRReport) (uint32, error) {
+	return r.getRttFromDLRR(report, time.Now())
+}
+
+func (r *RTPStatsReceiver) getRttFromDLRR(report rtcp.DLRRReport, at time.Time) (uint32, error) {
+	if report.LastRR == 0 {
+		return 0, errDLRRNoLastRR
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if report.LastRR == r.dlrrLastRR {
+		return 0, errDLRRDuplicate
+	}
+	if !slices.Contains(r.rrtrSent[:], report.LastRR) {
+		return 0, fmt.Errorf("%w, lastRR: 0x%x", errDLRRUnknownLastRR, report.LastRR)
+	}
+
+	rtt, err := rttFromDLRR(ntpMiddle32(mediatransportutil.ToNtpTime(at)), report.LastRR, report.DLRR)
+	if err != nil {
+		return 0, err
+	}
+
+	r.dlrrLastRR = report.LastRR
+	return rtt, nil
+}
+
 func (r *RTPStatsReceiver) DeltaInfo(snapshotID uint32) *RTPDeltaInfo {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -564,6 +741,24 @@ func (r *RTPStatsReceiver) DeltaInfo(snapshotID uint32) *RTPDeltaInfo {
 	return r.deltaInfo(snapshotID, r.sequenceNumber.GetExtendedStart(), r.sequenceNumber.GetExtendedHighest())
 }
 
+// DeltaInfoExt returns stats since the previous call for the snapshot, including rates and largest loss burst,
+// and resets the snapshot
+func (r *RTPStatsReceiver) DeltaInfoExt(snapshotID uint32) *RTPDeltaInfoExt {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.deltaInfoExt(snapshotID, r.sequenceNumber.GetExtendedStart(), r.sequenceNumber.GetExtendedHighest(), true)
+}
+
+// PeekDeltaInfoExt is DeltaInfoExt without resetting the snapshot,
+// so that it can be polled at a cadence different from the snapshot owner
+func (r *RTPStatsReceiver) PeekDeltaInfoExt(snapshotID uint32) *RTPDeltaInfoExt {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.deltaInfoExt(snapshotID, r.sequenceNumber.GetExtendedStart(), r.sequenceNumber.GetExtendedHighest(), false)
+}
+
 func (r *RTPStatsReceiver) String() string {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -592,3 +787,21 @@ func (r *RTPStatsReceiver) isInRange(esn uint64, ehsn uint64) bool {
 }
 
 // ----------------------------------
+
+// ----------------------------------
+
+// middle 32 bits of NTP timestamp, 16.16 fixed point seconds used in RTCP reports
+func ntpMiddle32(ntpTime mediatransportutil.NtpTime) uint32 {
+	return uint32(ntpTime >> 16)
+}
+
+// RTT calculation reference: https://datatracker.ietf.org/doc/html/rfc3611#section-4.5,
+// all arguments are in units of 1/65536 seconds
+func rttFromDLRR(nowNTP32 uint32, lastRR uint32, dlrr uint32) (uint32, error) {
+	ntpDiff := nowNTP32 - lastRR - dlrr
+	if ntpDiff > (1 << 31) {
+		return 0, fmt.Errorf("%w, now: 0x%x, lastRR: 0x%x, dlrr: 0x%x", errDLRRAnachronous, nowNTP32, lastRR, dlrr)
+	}
+
+	return uint32(math.Ceil(float64(ntpDiff) * 1000.0 / 65536.0)), nil
+}
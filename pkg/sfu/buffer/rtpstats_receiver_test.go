@@ -20,9 +20,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 
+	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/logger"
 )
 
@@ -268,3 +270,299 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 
 	r.Stop()
 }
+
+func Test_RTPStatsReceiver_ClockSkewReset(t *testing.T) {
+	clockRate := uint32(90000)
+	propagationDelayAfterSkewedReports := func(clockSkew ClockSkewParams, numSkewedReports int) time.Duration {
+		r := NewRTPStatsReceiver(RTPStatsParams{
+			ClockRate: clockRate,
+			Logger:    logger.GetLogger(),
+			ClockSkew: clockSkew,
+		})
+
+		timestamp := uint32(1000)
+		packet := getPacket(100, timestamp, 1000)
+		r.Update(time.Now(), packet.Header.SequenceNumber, packet.Header.Timestamp, false, packet.Header.MarshalSize(), len(packet.Payload), 0)
+
+		start := time.Now()
+		r.SetRtcpSenderReportData(&RTCPSenderReportData{
+			RTPTimestamp: timestamp,
+			NTPTimestamp: mediatransportutil.ToNtpTime(start),
+			At:           start.Add(20 * time.Millisecond),
+		})
+
+		// RTP timestamp advancing at half the clock rate, with a higher propagation delay
+		for i := 1; i <= numSkewedReports; i++ {
+			ntpTime := start.Add(time.Duration(i) * time.Second)
+			r.SetRtcpSenderReportData(&RTCPSenderReportData{
+				RTPTimestamp: timestamp + uint32(i)*clockRate/2,
+				NTPTimestamp: mediatransportutil.ToNtpTime(ntpTime),
+				At:           ntpTime.Add(200 * time.Millisecond),
+			})
+		}
+		return r.propagationDelay
+	}
+
+	// reset disabled, estimate adapts slowly
+	clockSkew := ClockSkewParams{PersistentClockSkewThreshold: 3}
+	require.Less(t, propagationDelayAfterSkewedReports(clockSkew, 3), 100*time.Millisecond)
+
+	// not persistent yet
+	clockSkew.ResetOnPersistentClockSkew = true
+	require.Less(t, propagationDelayAfterSkewedReports(clockSkew, 2), 100*time.Millisecond)
+
+	// re-initialized to the propagation delay of the latest report
+	require.InDelta(t, 200*time.Millisecond, propagationDelayAfterSkewedReports(clockSkew, 3), float64(time.Millisecond))
+}
+
+func Test_RTPStatsReceiver_ClockSkewDetected(t *testing.T) {
+	clockRate := uint32(90000)
+	notifiedRatesForClockRate := func(threshold float64, rtpClockRate uint32, numReports int) []float64 {
+		var notifiedRates []float64
+		r := NewRTPStatsReceiver(RTPStatsParams{
+			ClockRate: clockRate,
+			Logger:    logger.GetLogger(),
+			ClockSkew: ClockSkewParams{Threshold: threshold},
+			OnClockSkewDetected: func(calculatedClockRate float64) {
+				notifiedRates = append(notifiedRates, calculatedClockRate)
+			},
+		})
+
+		timestamp := uint32(1000)
+		packet := getPacket(100, timestamp, 1000)
+		r.Update(time.Now(), packet.Header.SequenceNumber, packet.Header.Timestamp, false, packet.Header.MarshalSize(), len(packet.Payload), 0)
+
+		start := time.Now()
+		for i := 0; i <= numReports; i++ {
+			ntpTime := start.Add(time.Duration(i) * time.Second)
+			r.SetRtcpSenderReportData(&RTCPSenderReportData{
+				RTPTimestamp: timestamp + uint32(i)*rtpClockRate,
+				NTPTimestamp: mediatransportutil.ToNtpTime(ntpTime),
+				At:           ntpTime.Add(20 * time.Millisecond),
+			})
+		}
+		return notifiedRates
+	}
+
+	// 10% off is within default threshold
+	require.Empty(t, notifiedRatesForClockRate(0, clockRate*9/10, 3))
+
+	// notified on first detection, rate limited after
+	notifiedRates := notifiedRatesForClockRate(0.05, clockRate*9/10, 3)
+	require.Len(t, notifiedRates, 1)
+	require.InDelta(t, float64(clockRate*9/10), notifiedRates[0], 1.0)
+
+	notifiedRates = notifiedRatesForClockRate(0, clockRate/2, 3)
+	require.Len(t, notifiedRates, 1)
+	require.InDelta(t, float64(clockRate/2), notifiedRates[0], 1.0)
+}
+
+func Test_RTPStatsReceiver_PropagationDelayReset(t *testing.T) {
+	clockRate := uint32(90000)
+	var r *RTPStatsReceiver
+	var resets [][2]time.Duration
+	r = NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: clockRate,
+		Logger:    logger.GetLogger(),
+		OnPropagationDelayReset: func(old, new time.Duration) {
+			// should not be holding the lock
+			require.NotNil(t, r.GetRtcpSenderReportData())
+			resets = append(resets, [2]time.Duration{old, new})
+		},
+	})
+
+	timestamp := uint32(1000)
+	packet := getPacket(100, timestamp, 1000)
+	r.Update(time.Now(), packet.Header.SequenceNumber, packet.Header.Timestamp, false, packet.Header.MarshalSize(), len(packet.Payload), 0)
+
+	start := time.Now()
+	sendReport := func(i int, propagationDelay time.Duration) {
+		ntpTime := start.Add(time.Duration(i) * time.Second)
+		r.SetRtcpSenderReportData(&RTCPSenderReportData{
+			RTPTimestamp: timestamp + uint32(i)*clockRate,
+			NTPTimestamp: mediatransportutil.ToNtpTime(ntpTime),
+			At:           ntpTime.Add(propagationDelay),
+		})
+	}
+
+	require.Zero(t, r.GetPropagationDelay())
+	require.Equal(t, PropagationDelayStats{}, r.GetPropagationDelayStats())
+
+	sendReport(0, 20*time.Millisecond)
+	require.InDelta(t, 20*time.Millisecond, r.GetPropagationDelay(), float64(time.Millisecond))
+	sendReport(1, 22*time.Millisecond)
+
+	// path change, sharp increase in propagation delay
+	sendReport(2, 120*time.Millisecond)
+	require.Empty(t, resets)
+	stats := r.GetPropagationDelayStats()
+	require.Equal(t, 1, stats.DeltaHighCount)
+	require.False(t, stats.DeltaHighStartTime.IsZero())
+	require.InDelta(t, 120*time.Millisecond, stats.Spike, float64(time.Millisecond))
+
+	// sustained long enough
+	r.propagationDelayDeltaHighStartTime = r.propagationDelayDeltaHighStartTime.Add(-cPropagationDelayDeltaHighResetWait)
+	sendReport(3, 120*time.Millisecond)
+	require.Len(t, resets, 1)
+	require.InDelta(t, 20*time.Millisecond, resets[0][0], float64(time.Millisecond))
+	require.InDelta(t, 120*time.Millisecond, resets[0][1], float64(time.Millisecond))
+	require.Equal(t, resets[0][1], r.GetPropagationDelay())
+	require.Zero(t, r.GetPropagationDelayStats().Spike)
+}
+
+func Test_RTPStatsReceiver_DeltaInfoExt(t *testing.T) {
+	clockRate := uint32(90000)
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: clockRate,
+		Logger:    logger.GetLogger(),
+	})
+	snapshotID := r.NewSnapshotId()
+
+	sequenceNumber := uint16(65530)
+	timestamp := uint32(0xffff_0000)
+	update := func(numPackets int) {
+		for i := 0; i < numPackets; i++ {
+			packet := getPacket(sequenceNumber, timestamp, 1000)
+			packet.Header.Marker = true
+			r.Update(time.Now(), packet.Header.SequenceNumber, packet.Header.Timestamp, packet.Header.Marker, packet.Header.MarshalSize(), len(packet.Payload), 0)
+			sequenceNumber++
+			timestamp += 3000
+		}
+	}
+
+	// 65530 - 65535, up to the wrap around
+	update(6)
+	time.Sleep(10 * time.Millisecond)
+
+	// peek does not reset
+	for i := 0; i < 2; i++ {
+		deltaInfo := r.PeekDeltaInfoExt(snapshotID)
+		require.NotNil(t, deltaInfo)
+		require.Equal(t, uint32(6), deltaInfo.Packets)
+		require.Equal(t, uint32(6), deltaInfo.Frames)
+		require.Equal(t, uint64(6*(12+1000)), deltaInfo.Bytes)
+		require.Equal(t, uint64(6*12), deltaInfo.HeaderBytes)
+		require.Zero(t, deltaInfo.MaxGap)
+		require.Greater(t, deltaInfo.Bitrate, 0.0)
+		require.Greater(t, deltaInfo.FrameRate, 0.0)
+	}
+
+	deltaInfo := r.DeltaInfoExt(snapshotID)
+	require.NotNil(t, deltaInfo)
+	require.Equal(t, uint32(6), deltaInfo.Packets)
+	require.Zero(t, deltaInfo.PacketsLost)
+
+	// across the wrap around with a gap of two packets: 0, 1, (lost 2, 3), 4, 5, 6
+	update(2)
+	sequenceNumber += 2
+	timestamp += 6000
+	update(3)
+
+	deltaInfo = r.PeekDeltaInfoExt(snapshotID)
+	require.NotNil(t, deltaInfo)
+	require.Equal(t, uint32(7), deltaInfo.Packets)
+	require.Equal(t, uint32(2), deltaInfo.PacketsLost)
+	require.Equal(t, uint32(5), deltaInfo.Frames)
+	require.Equal(t, uint64(5*(12+1000)), deltaInfo.Bytes)
+	require.Equal(t, uint32(2), deltaInfo.MaxGap)
+
+	deltaInfo = r.DeltaInfoExt(snapshotID)
+	require.NotNil(t, deltaInfo)
+	require.Equal(t, uint32(7), deltaInfo.Packets)
+	require.Equal(t, uint32(2), deltaInfo.MaxGap)
+
+	// reset, max gap starts over
+	update(1)
+	deltaInfo = r.DeltaInfoExt(snapshotID)
+	require.NotNil(t, deltaInfo)
+	require.Equal(t, uint32(1), deltaInfo.Packets)
+	require.Zero(t, deltaInfo.PacketsLost)
+	require.Zero(t, deltaInfo.MaxGap)
+
+	// unknown snapshot
+	require.Nil(t, r.PeekDeltaInfoExt(snapshotID+1))
+}
+
+func Test_RTPStatsReceiver_NtpMiddle32(t *testing.T) {
+	require.Equal(t, uint32(0x33445566), ntpMiddle32(mediatransportutil.NtpTime(0x1122334455667788)))
+
+	// 16.16 fixed point seconds
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := ntpMiddle32(mediatransportutil.ToNtpTime(at.Add(1500 * time.Millisecond)))
+	require.Equal(t, uint32(0x18000), later-ntpMiddle32(mediatransportutil.ToNtpTime(at)))
+
+	// 125 ms round trip with 1 second delay at remote
+	rtt, err := rttFromDLRR(0x30000+0x2000, 0x20000, 0x10000)
+	require.NoError(t, err)
+	require.Equal(t, uint32(125), rtt)
+
+	// across wrap around of middle 32 bits
+	rtt, err = rttFromDLRR(0x2000-1, 0xffff0000, 0x10000)
+	require.NoError(t, err)
+	require.Equal(t, uint32(125), rtt)
+
+	// delay at remote longer than elapsed time
+	_, err = rttFromDLRR(0x30000, 0x20000, 0x20000)
+	require.ErrorIs(t, err, errDLRRAnachronous)
+}
+
+func Test_RTPStatsReceiver_RttFromDLRR(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+
+	start := time.Now()
+
+	// not initialized
+	require.Nil(t, r.getRtcpXrReport(1234, start))
+
+	packet := getPacket(100, 1000, 1000)
+	r.Update(start, packet.Header.SequenceNumber, packet.Header.Timestamp, false, packet.Header.MarshalSize(), len(packet.Payload), 0)
+
+	xr := r.getRtcpXrReport(1234, start)
+	require.NotNil(t, xr)
+	require.Equal(t, uint32(1234), xr.SenderSSRC)
+	require.Len(t, xr.Reports, 1)
+	rrtr, ok := xr.Reports[0].(*rtcp.ReceiverReferenceTimeReportBlock)
+	require.True(t, ok)
+	require.Equal(t, uint64(mediatransportutil.ToNtpTime(start)), rrtr.NTPTimestamp)
+
+	// remote holds the report for 200 ms, response arrives 50 ms later
+	lastRR := ntpMiddle32(mediatransportutil.NtpTime(rrtr.NTPTimestamp))
+	dlrr := rtcp.DLRRReport{
+		SSRC:   1234,
+		LastRR: lastRR,
+		DLRR:   uint32(200 * 65536 / 1000),
+	}
+	rtt, err := r.getRttFromDLRR(dlrr, start.Add(250*time.Millisecond))
+	require.NoError(t, err)
+	require.InDelta(t, 50, rtt, 1)
+
+	// repeated response is not used again
+	_, err = r.getRttFromDLRR(dlrr, start.Add(300*time.Millisecond))
+	require.ErrorIs(t, err, errDLRRDuplicate)
+
+	// response to a report that was not sent
+	dlrr.LastRR = lastRR + 1
+	_, err = r.getRttFromDLRR(dlrr, start.Add(300*time.Millisecond))
+	require.ErrorIs(t, err, errDLRRUnknownLastRR)
+
+	// remote had not received any report
+	_, err = r.getRttFromDLRR(rtcp.DLRRReport{SSRC: 1234}, start.Add(300*time.Millisecond))
+	require.ErrorIs(t, err, errDLRRNoLastRR)
+
+	// response to an older report while a newer one is in flight
+	var sent []uint32
+	for i := 1; i <= 2; i++ {
+		xr = r.getRtcpXrReport(1234, start.Add(time.Duration(i)*time.Second))
+		sent = append(sent, ntpMiddle32(mediatransportutil.NtpTime(xr.Reports[0].(*rtcp.ReceiverReferenceTimeReportBlock).NTPTimestamp)))
+	}
+	rtt, err = r.getRttFromDLRR(rtcp.DLRRReport{SSRC: 1234, LastRR: sent[0]}, start.Add(1100*time.Millisecond))
+	require.NoError(t, err)
+	require.InDelta(t, 100, rtt, 1)
+
+	// stopped
+	r.Stop()
+	require.Nil(t, r.getRtcpXrReport(1234, start.Add(3*time.Second)))
+}
@@ -21,9 +21,12 @@ import (
 	"time"
 
 	"github.com/pion/rtcp"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/livekit"
+
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 const (
@@ -166,6 +169,9 @@ type RTPStatsSender struct {
 }
 
 func NewRTPStatsSender(params RTPStatsParams) *RTPStatsSender {
+	if params.ServerClock == nil {
+		params.ServerClock = sutils.NewServerClock()
+	}
 	return &RTPStatsSender{
 		rtpStatsBase:         newRTPStatsBase(params),
 		nextSenderSnapshotID: cFirstSnapshotID,
@@ -203,6 +209,62 @@ func (r *RTPStatsSender) Seed(from *RTPStatsSender) {
 	copy(r.senderSnapshots, from.senderSnapshots)
 }
 
+// MarshalBinary encodes cumulative stats and sequence number/timestamp range.
+// Transient state, i. e. sequence number info used for receiver report loss calculation and
+// the last receiver report, is not encoded. It is rebuilt from packets/reports after unmarshalling.
+func (r *RTPStatsSender) MarshalBinary() ([]byte, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	e := &sutils.WireEncoder{}
+	r.rtpStatsBase.marshalWire(e)
+
+	e.Uint64(50, r.extStartSN)
+	e.Uint64(51, r.extHighestSN)
+	e.Uint64(52, r.extHighestSNFromRR)
+	e.Uint64(53, r.extStartTS)
+	e.Uint64(54, r.extHighestTS)
+	e.Uint64(55, r.packetsLostFromRR)
+	e.Float64(56, r.jitterFromRR)
+	e.Float64(57, r.maxJitterFromRR)
+	e.Uint64(58, uint64(r.nextSenderSnapshotID))
+	return e.Encoded(), nil
+}
+
+func (r *RTPStatsSender) UnmarshalBinary(data []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return sutils.DecodeWire(data, func(num protowire.Number, f sutils.WireField) error {
+		switch num {
+		case 50:
+			r.extStartSN = f.Uint64()
+		case 51:
+			r.extHighestSN = f.Uint64()
+		case 52:
+			r.extHighestSNFromRR = f.Uint64()
+		case 53:
+			r.extStartTS = f.Uint64()
+		case 54:
+			r.extHighestTS = f.Uint64()
+		case 55:
+			r.packetsLostFromRR = f.Uint64()
+		case 56:
+			r.jitterFromRR = f.Float64()
+		case 57:
+			r.maxJitterFromRR = f.Float64()
+		case 58:
+			r.nextSenderSnapshotID = uint32(f.Uint64())
+			if int(r.nextSenderSnapshotID-cFirstSnapshotID) > cap(r.senderSnapshots) {
+				r.senderSnapshots = make([]senderSnapshot, r.nextSenderSnapshotID-cFirstSnapshotID)
+			}
+		default:
+			return r.rtpStatsBase.unmarshalWireField(num, f)
+		}
+		return nil
+	})
+}
+
 func (r *RTPStatsSender) NewSnapshotId() uint32 {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -611,6 +673,15 @@ func (r *RTPStatsSender) LastReceiverReportTime() time.Time {
 	return r.lastRRTime
 }
 
+// LastReceiverReport returns the last reception report accepted from the receiver and when it was received,
+// time is zero if none has been received
+func (r *RTPStatsSender) LastReceiverReport() (rtcp.ReceptionReport, time.Time) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.lastRR, r.lastRRTime
+}
+
 func (r *RTPStatsSender) MaybeAdjustFirstPacketTime(publisherSRData *RTCPSenderReportData, tsOffset uint64) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -645,7 +716,7 @@ func (r *RTPStatsSender) GetRtcpSenderReport(ssrc uint32, publisherSRData *RTCPS
 		return nil
 	}
 
-	timeSincePublisherSR := time.Since(publisherSRData.AtAdjusted)
+	timeSincePublisherSR := r.params.ServerClock.Now().Sub(publisherSRData.AtAdjusted)
 	now := publisherSRData.AtAdjusted.Add(timeSincePublisherSR)
 	nowNTP := mediatransportutil.ToNtpTime(now)
 	nowRTPExt := publisherSRData.RTPTimestampExt - tsOffset + uint64(timeSincePublisherSR.Nanoseconds()*int64(r.params.ClockRate)/1e9)
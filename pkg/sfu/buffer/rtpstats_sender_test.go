@@ -0,0 +1,142 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/mediatransportutil"
+	"github.com/livekit/protocol/logger"
+
+	sutils "github.com/livekit/livekit-server/pkg/utils"
+)
+
+func Test_RTPStatsSender_MarshalBinary(t *testing.T) {
+	clockRate := uint32(90000)
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate: clockRate,
+		Logger:    logger.GetLogger(),
+	})
+
+	now := time.Now()
+	for i := uint64(0); i < 10; i++ {
+		r.Update(now, 1000+i, 0xabcd00+i*3000, i%2 == 0, 12, 1000, 0)
+	}
+	r.UpdatePliAndTime(1)
+	r.NewSenderSnapshotId()
+
+	data, err := r.MarshalBinary()
+	require.NoError(t, err)
+
+	unmarshalled := NewRTPStatsSender(RTPStatsParams{
+		ClockRate: clockRate,
+		Logger:    logger.GetLogger(),
+	})
+	require.NoError(t, unmarshalled.UnmarshalBinary(data))
+	require.True(t, unmarshalled.initialized)
+	require.Equal(t, r.extStartSN, unmarshalled.extStartSN)
+	require.Equal(t, r.extHighestSN, unmarshalled.extHighestSN)
+	require.Equal(t, r.extStartTS, unmarshalled.extStartTS)
+	require.Equal(t, r.extHighestTS, unmarshalled.extHighestTS)
+	require.Equal(t, r.bytes, unmarshalled.bytes)
+	require.Equal(t, r.headerBytes, unmarshalled.headerBytes)
+	require.Equal(t, r.plis, unmarshalled.plis)
+	require.Equal(t, r.lastPli.UnixNano(), unmarshalled.lastPli.UnixNano())
+	require.Equal(t, r.nextSenderSnapshotID, unmarshalled.nextSenderSnapshotID)
+	require.Len(t, unmarshalled.senderSnapshots, len(r.senderSnapshots))
+
+	// seeding from unmarshalled stats continues from where the original left off
+	seeded := NewRTPStatsSender(RTPStatsParams{
+		ClockRate: clockRate,
+		Logger:    logger.GetLogger(),
+	})
+	seeded.Seed(unmarshalled)
+	require.Equal(t, r.extHighestSN, seeded.extHighestSN)
+	require.Equal(t, r.ToProto().Packets, seeded.ToProto().Packets)
+
+	// malformed data fails
+	require.ErrorIs(t, unmarshalled.UnmarshalBinary([]byte{0xff}), sutils.ErrWireMalformed)
+}
+
+func Test_RTPStatsSender_LastReceiverReport(t *testing.T) {
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+
+	_, at := r.LastReceiverReport()
+	require.True(t, at.IsZero())
+
+	now := time.Now()
+	for i := uint64(0); i < 10; i++ {
+		r.Update(now, 1000+i, 0xabcd00+i*3000, i%2 == 0, 12, 1000, 0)
+	}
+
+	rr := rtcp.ReceptionReport{
+		SSRC:               0x12345678,
+		FractionLost:       64,
+		TotalLost:          2,
+		LastSequenceNumber: 1009,
+	}
+	r.UpdateFromReceiverReport(rr)
+	lastRR, at := r.LastReceiverReport()
+	require.Equal(t, rr, lastRR)
+	require.False(t, at.IsZero())
+
+	// report from before the last one is ignored
+	r.UpdateFromReceiverReport(rtcp.ReceptionReport{
+		SSRC:               0x12345678,
+		FractionLost:       128,
+		LastSequenceNumber: 1005,
+	})
+	lastRR, _ = r.LastReceiverReport()
+	require.Equal(t, rr, lastRR)
+}
+
+type fixedServerClock struct {
+	now time.Time
+}
+
+func (c *fixedServerClock) Now() time.Time {
+	return c.now
+}
+
+func Test_RTPStatsSender_SenderReportServerClock(t *testing.T) {
+	start := time.Now()
+	clock := &fixedServerClock{now: start.Add(2 * time.Second)}
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate:   90000,
+		Logger:      logger.GetLogger(),
+		ServerClock: clock,
+	})
+
+	for i := uint64(0); i < 10; i++ {
+		r.Update(start, 1000+i, 0xabcd00, i == 9, 12, 1000, 0)
+	}
+
+	publisherSR := &RTCPSenderReportData{
+		RTPTimestampExt: 0xabcd00,
+		AtAdjusted:      start,
+	}
+	sr := r.GetRtcpSenderReport(0x12345678, publisherSR, 0)
+	require.NotNil(t, sr)
+	// rebased to the time of server clock, not system time
+	require.Equal(t, uint64(mediatransportutil.ToNtpTime(clock.now)), sr.NTPTime)
+	require.Equal(t, uint32(0xabcd00+2*90000), sr.RTPTime)
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"time"
+)
+
+const (
+	temporalLayerDistributionWindow = 5 * time.Second
+)
+
+// TemporalLayerDistribution is the number of media packets received per temporal layer over Duration
+type TemporalLayerDistribution struct {
+	Packets  [DefaultMaxLayerTemporal + 1]uint64
+	Duration time.Duration
+}
+
+func (t TemporalLayerDistribution) Total() uint64 {
+	total := uint64(0)
+	for _, n := range t.Packets {
+		total += n
+	}
+	return total
+}
+
+// MaxTemporal returns the highest temporal layer seen, InvalidLayerTemporal if none
+func (t TemporalLayerDistribution) MaxTemporal() int32 {
+	for i := len(t.Packets) - 1; i >= 0; i-- {
+		if t.Packets[i] != 0 {
+			return int32(i)
+		}
+	}
+	return InvalidLayerTemporal
+}
+
+// Ratios returns fraction of packets in each temporal layer up to the highest seen
+func (t TemporalLayerDistribution) Ratios() []float64 {
+	total := t.Total()
+	if total == 0 {
+		return nil
+	}
+
+	ratios := make([]float64, t.MaxTemporal()+1)
+	for i := range ratios {
+		ratios[i] = float64(t.Packets[i]) / float64(total)
+	}
+	return ratios
+}
+
+// --------------------------------------
+
+type temporalLayerBucket struct {
+	startedAt time.Time
+	packets   [DefaultMaxLayerTemporal + 1]uint64
+}
+
+// temporalLayerCounter counts packets per temporal layer over a rolling window,
+// covering between one and two windows by keeping the previous window around.
+type temporalLayerCounter struct {
+	window   time.Duration
+	current  temporalLayerBucket
+	previous temporalLayerBucket
+}
+
+func newTemporalLayerCounter(window time.Duration) *temporalLayerCounter {
+	return &temporalLayerCounter{
+		window: window,
+	}
+}
+
+func (t *temporalLayerCounter) Add(temporal int32, at time.Time) {
+	if temporal < 0 || int(temporal) >= len(t.current.packets) {
+		return
+	}
+
+	t.roll(at)
+	if t.current.startedAt.IsZero() {
+		t.current.startedAt = at
+	}
+	t.current.packets[temporal]++
+}
+
+func (t *temporalLayerCounter) Distribution(now time.Time) TemporalLayerDistribution {
+	t.roll(now)
+	if t.current.startedAt.IsZero() {
+		return TemporalLayerDistribution{}
+	}
+
+	startedAt := t.current.startedAt
+	if !t.previous.startedAt.IsZero() {
+		startedAt = t.previous.startedAt
+	}
+
+	dist := TemporalLayerDistribution{
+		Duration: now.Sub(startedAt),
+	}
+	for i := range dist.Packets {
+		dist.Packets[i] = t.previous.packets[i] + t.current.packets[i]
+	}
+	return dist
+}
+
+func (t *temporalLayerCounter) roll(now time.Time) {
+	if t.current.startedAt.IsZero() {
+		return
+	}
+
+	elapsed := now.Sub(t.current.startedAt)
+	switch {
+	case elapsed >= 2*t.window:
+		// nothing recent enough to keep
+		t.previous = temporalLayerBucket{}
+		t.current = temporalLayerBucket{}
+
+	case elapsed >= t.window:
+		t.previous = t.current
+		t.current = temporalLayerBucket{startedAt: t.previous.startedAt.Add(t.window)}
+	}
+}
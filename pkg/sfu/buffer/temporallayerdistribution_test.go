@@ -0,0 +1,115 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemporalLayerDistribution(t *testing.T) {
+	buff := NewBuffer(123, 100, 100)
+	buff.OnRtcpFeedback(func(_ []rtcp.Packet) {})
+	buff.Bind(webrtc.RTPParameters{
+		HeaderExtensions: nil,
+		Codecs:           []webrtc.RTPCodecParameters{vp8Codec},
+	}, vp8Codec.RTPCodecCapability)
+
+	// L1T3 pattern, one packet per frame
+	pattern := []uint8{0, 2, 1, 2}
+	sn := uint16(1)
+	for i := 0; i < 25; i++ {
+		for _, tid := range pattern {
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					PayloadType:    96,
+					SequenceNumber: sn,
+					Timestamp:      uint32(sn) * 3000,
+					SSRC:           123,
+				},
+				// extended control bits with T, TID, VP8 payload header
+				Payload: []byte{0x90, 0x20, tid << 6, 0x01, 0x02, 0x03},
+			}
+			sn++
+			buf, err := pkt.Marshal()
+			require.NoError(t, err)
+			_, err = buff.Write(buf)
+			require.NoError(t, err)
+		}
+	}
+
+	// padding only packet should not count
+	pkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: sn,
+			Timestamp:      uint32(sn) * 3000,
+			SSRC:           123,
+			Padding:        true,
+		},
+		PaddingSize: 10,
+	}
+	buf, err := pkt.Marshal()
+	require.NoError(t, err)
+	_, err = buff.Write(buf)
+	require.NoError(t, err)
+
+	dist := buff.GetTemporalLayerDistributionForSpatial(0)
+	require.Equal(t, [DefaultMaxLayerTemporal + 1]uint64{25, 25, 50, 0}, dist.Packets)
+	require.Equal(t, uint64(100), dist.Total())
+	require.Equal(t, int32(2), dist.MaxTemporal())
+	require.Equal(t, []float64{0.25, 0.25, 0.5}, dist.Ratios())
+
+	require.Equal(t, TemporalLayerDistribution{}, buff.GetTemporalLayerDistributionForSpatial(1))
+}
+
+func TestTemporalLayerCounter(t *testing.T) {
+	window := time.Second
+	tc := newTemporalLayerCounter(window)
+	start := time.Now()
+
+	require.Equal(t, TemporalLayerDistribution{}, tc.Distribution(start))
+
+	tc.Add(0, start)
+	tc.Add(1, start.Add(100*time.Millisecond))
+	tc.Add(InvalidLayerTemporal, start.Add(100*time.Millisecond))
+	tc.Add(DefaultMaxLayerTemporal+1, start.Add(100*time.Millisecond))
+
+	dist := tc.Distribution(start.Add(500 * time.Millisecond))
+	require.Equal(t, [DefaultMaxLayerTemporal + 1]uint64{1, 1, 0, 0}, dist.Packets)
+	require.Equal(t, 500*time.Millisecond, dist.Duration)
+
+	// previous window is kept after rolling
+	tc.Add(0, start.Add(1500*time.Millisecond))
+	dist = tc.Distribution(start.Add(1500 * time.Millisecond))
+	require.Equal(t, [DefaultMaxLayerTemporal + 1]uint64{2, 1, 0, 0}, dist.Packets)
+	require.Equal(t, 1500*time.Millisecond, dist.Duration)
+
+	// first window falls out
+	tc.Add(2, start.Add(2500*time.Millisecond))
+	dist = tc.Distribution(start.Add(2500 * time.Millisecond))
+	require.Equal(t, [DefaultMaxLayerTemporal + 1]uint64{1, 0, 1, 0}, dist.Packets)
+	require.Equal(t, 1500*time.Millisecond, dist.Duration)
+
+	// everything is stale after a long gap
+	require.Equal(t, TemporalLayerDistribution{}, tc.Distribution(start.Add(10*time.Second)))
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"time"
+)
+
+const (
+	clockRateEstimatorWindow         = 10 * time.Second
+	clockRateEstimatorMinSpan        = 5 * time.Second
+	clockRateEstimatorSampleInterval = 100 * time.Millisecond
+	// a longer gap in arrival, for example publisher muting, restarts estimation
+	clockRateEstimatorMaxGap = 2 * time.Second
+)
+
+type clockRateSample struct {
+	at    time.Time
+	extTS uint64
+}
+
+// clockRateEstimator estimates actual clock rate of a stream from the slope of
+// RTP timestamp against arrival time over a sliding window
+type clockRateEstimator struct {
+	samples []clockRateSample
+}
+
+func (c *clockRateEstimator) Reset() {
+	c.samples = c.samples[:0]
+}
+
+// Update adds a packet and returns estimated clock rate, 0 till the window spans long enough
+func (c *clockRateEstimator) Update(at time.Time, extTS uint64) float64 {
+	if n := len(c.samples); n != 0 {
+		last := c.samples[n-1]
+		switch {
+		case at.Sub(last.at) > clockRateEstimatorMaxGap:
+			c.Reset()
+		case int64(extTS-last.extTS) < 0 || at.Sub(last.at) < clockRateEstimatorSampleInterval:
+			// out of order or too close to previous sample
+			return c.estimate()
+		}
+	}
+
+	c.samples = append(c.samples, clockRateSample{at: at, extTS: extTS})
+
+	expired := 0
+	for expired < len(c.samples) && at.Sub(c.samples[expired].at) > clockRateEstimatorWindow {
+		expired++
+	}
+	if expired != 0 {
+		c.samples = append(c.samples[:0], c.samples[expired:]...)
+	}
+	return c.estimate()
+}
+
+// least squares slope of timestamp against time, smooths out arrival jitter
+func (c *clockRateEstimator) estimate() float64 {
+	if len(c.samples) < 2 {
+		return 0
+	}
+
+	first := c.samples[0]
+	if c.samples[len(c.samples)-1].at.Sub(first.at) < clockRateEstimatorMinSpan {
+		return 0
+	}
+
+	var sumX, sumY, sumXX, sumXY float64
+	for _, s := range c.samples {
+		x := s.at.Sub(first.at).Seconds()
+		y := float64(s.extTS - first.extTS)
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+	}
+	n := float64(len(c.samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
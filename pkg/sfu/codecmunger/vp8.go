@@ -18,10 +18,12 @@ import (
 	"fmt"
 
 	"github.com/elliotchance/orderedmap/v2"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/livekit/protocol/logger"
 
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/utils"
 )
 
 const (
@@ -47,6 +49,41 @@ func (v VP8State) String() string {
 		v.ExtLastPictureId, v.PictureIdUsed, v.LastTl0PicIdx, v.Tl0PicIdxUsed, v.TidUsed, v.LastKeyIdx, v.KeyIdxUsed)
 }
 
+func (v VP8State) MarshalBinary() ([]byte, error) {
+	e := &utils.WireEncoder{}
+	e.Int64(1, int64(v.ExtLastPictureId))
+	e.Bool(2, v.PictureIdUsed)
+	e.Uint64(3, uint64(v.LastTl0PicIdx))
+	e.Bool(4, v.Tl0PicIdxUsed)
+	e.Bool(5, v.TidUsed)
+	e.Uint64(6, uint64(v.LastKeyIdx))
+	e.Bool(7, v.KeyIdxUsed)
+	return e.Encoded(), nil
+}
+
+func (v *VP8State) UnmarshalBinary(data []byte) error {
+	*v = VP8State{}
+	return utils.DecodeWire(data, func(num protowire.Number, f utils.WireField) error {
+		switch num {
+		case 1:
+			v.ExtLastPictureId = int32(f.Int64())
+		case 2:
+			v.PictureIdUsed = f.Bool()
+		case 3:
+			v.LastTl0PicIdx = uint8(f.Uint64())
+		case 4:
+			v.Tl0PicIdxUsed = f.Bool()
+		case 5:
+			v.TidUsed = f.Bool()
+		case 6:
+			v.LastKeyIdx = uint8(f.Uint64())
+		case 7:
+			v.KeyIdxUsed = f.Bool()
+		}
+		return nil
+	})
+}
+
 // -----------------------------------------------------------
 
 type VP8 struct {
@@ -70,6 +70,8 @@ type ConnectionStats struct {
 	packetsSent        uint64
 	streamingStartedAt time.Time
 
+	bitrate atomic.Int64
+
 	scorer *qualityScorer
 
 	done core.Fuse
@@ -203,7 +205,24 @@ func (cs *ConnectionStats) GetScoreAndQuality() (float32, livekit.ConnectionQual
 	return cs.scorer.GetMOSAndQuality()
 }
 
+// GetBitrate returns bitrate over the last update interval, in bps
+func (cs *ConnectionStats) GetBitrate() int64 {
+	return cs.bitrate.Load()
+}
+
+func (cs *ConnectionStats) updateBitrate(agg *buffer.RTPDeltaInfo) {
+	bitrate := int64(0)
+	if agg != nil {
+		if duration := agg.EndTime.Sub(agg.StartTime); duration > 0 {
+			bitrate = int64(float64(agg.Bytes) * 8.0 / duration.Seconds())
+		}
+	}
+	cs.bitrate.Store(bitrate)
+}
+
 func (cs *ConnectionStats) updateScoreWithAggregate(agg *buffer.RTPDeltaInfo, lastRTCPAt time.Time, at time.Time) float32 {
+	cs.updateBitrate(agg)
+
 	var stat windowStat
 	if agg != nil {
 		stat.startedAt = agg.StartTime
@@ -235,6 +254,8 @@ func (cs *ConnectionStats) updateScoreFromReceiverReport(at time.Time) (float32,
 
 	streamingStartedAt := cs.updateStreamingStart(at)
 	if streamingStartedAt.IsZero() {
+		cs.updateBitrate(nil)
+
 		// not streaming, just return current score
 		mos, _ := cs.scorer.GetMOSAndQuality()
 		return mos, nil
@@ -284,6 +305,8 @@ func (cs *ConnectionStats) updateScoreAt(at time.Time) (float32, map[uint32]*buf
 
 	streams := cs.params.ReceiverProvider.GetDeltaStats()
 	if len(streams) == 0 {
+		cs.updateBitrate(nil)
+
 		mos, _ := cs.scorer.GetMOSAndQuality()
 		return mos, nil
 	}
@@ -911,3 +911,50 @@ func TestConnectionQuality(t *testing.T) {
 		}
 	})
 }
+
+func TestConnectionStatsBitrate(t *testing.T) {
+	trp := newTestReceiverProvider()
+	cs := NewConnectionStats(ConnectionStatsParams{
+		MimeType:         "audio/opus",
+		ReceiverProvider: trp,
+		Logger:           logger.GetLogger(),
+	})
+
+	duration := 5 * time.Second
+	now := time.Now()
+	cs.StartAt(&livekit.TrackInfo{Type: livekit.TrackType_AUDIO}, now)
+	require.Zero(t, cs.GetBitrate())
+
+	trp.setStreams(map[uint32]*buffer.StreamStatsWithLayers{
+		1: {
+			RTPStats: &buffer.RTPDeltaInfo{
+				StartTime: now,
+				EndTime:   now.Add(duration),
+				Packets:   250,
+				Bytes:     20_000,
+			},
+		},
+	})
+	cs.updateScoreAt(now.Add(duration))
+	require.Equal(t, int64(32_000), cs.GetBitrate())
+
+	// bitrate is of the last interval, not averaged over the session
+	now = now.Add(duration)
+	trp.setStreams(map[uint32]*buffer.StreamStatsWithLayers{
+		1: {
+			RTPStats: &buffer.RTPDeltaInfo{
+				StartTime: now,
+				EndTime:   now.Add(duration),
+				Packets:   250,
+				Bytes:     5_000,
+			},
+		},
+	})
+	cs.updateScoreAt(now.Add(duration))
+	require.Equal(t, int64(8_000), cs.GetBitrate())
+
+	// nothing received in the interval
+	trp.setStreams(nil)
+	cs.updateScoreAt(now.Add(2 * duration))
+	require.Zero(t, cs.GetBitrate())
+}
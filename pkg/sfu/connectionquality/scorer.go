@@ -550,6 +550,15 @@ func (q *qualityScorer) GetMOSAndQuality() (float32, livekit.ConnectionQuality)
 	return scoreToMOS(q.score), scoreToConnectionQuality(q.score)
 }
 
+// MaxMOSForQuality returns the highest MOS which maps to given quality
+func MaxMOSForQuality(quality livekit.ConnectionQuality) float32 {
+	if quality == livekit.ConnectionQuality_EXCELLENT {
+		return MaxMOS
+	}
+
+	return scoreToMOS(qualityTransitionScore[quality])
+}
+
 // ------------------------------------------
 
 func scoreToConnectionQuality(score float64) livekit.ConnectionQuality {
@@ -30,10 +30,12 @@ import (
 	"github.com/pion/transport/v2/packetio"
 	"github.com/pion/webrtc/v3"
 	"go.uber.org/atomic"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/livekit-server/pkg/sfu/pacer"
@@ -41,6 +43,7 @@ import (
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
 	pd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/playoutdelay"
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 // TrackSender defines an interface send media to remote peer
@@ -50,6 +53,7 @@ type TrackSender interface {
 	UpTrackMaxPublishedLayerChange(maxPublishedLayer int32)
 	UpTrackMaxTemporalLayerSeenChange(maxTemporalLayerSeen int32)
 	UpTrackBitrateReport(availableLayers []int32, bitrates Bitrates)
+	UpTrackSilenceChange(silent bool)
 	WriteRTP(p *buffer.ExtPacket, layer int32) error
 	Close()
 	IsClosed() bool
@@ -90,9 +94,11 @@ var (
 	ErrOutOfOrderSequenceNumberCacheMiss = errors.New("out-of-order sequence number not found in cache")
 	ErrPaddingOnlyPacket                 = errors.New("padding only packet that need not be forwarded")
 	ErrDuplicatePacket                   = errors.New("duplicate packet")
+	ErrSequenceNumberGapFilled           = errors.New("sequence number gap already filled")
 	ErrPaddingNotOnFrameBoundary         = errors.New("padding cannot send on non-frame boundary")
 	ErrDownTrackAlreadyBound             = errors.New("already bound")
 	ErrPayloadOverflow                   = errors.New("payload overflow")
+	ErrNoKeyFrameLayer                   = errors.New("no video layer to request key frame for")
 )
 
 var (
@@ -144,6 +150,42 @@ func (d DownTrackState) String() string {
 		d.RTPStats, d.DeltaStatsSenderSnapshotId, d.ForwarderState.String())
 }
 
+func (d DownTrackState) MarshalBinary() ([]byte, error) {
+	e := &sutils.WireEncoder{}
+	if d.RTPStats != nil {
+		rtpStats, err := d.RTPStats.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		e.Bytes(1, rtpStats)
+	}
+	e.Uint64(2, uint64(d.DeltaStatsSenderSnapshotId))
+
+	forwarderState, err := d.ForwarderState.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	e.Bytes(3, forwarderState)
+	return e.Encoded(), nil
+}
+
+func (d *DownTrackState) UnmarshalBinary(data []byte) error {
+	*d = DownTrackState{}
+	return sutils.DecodeWire(data, func(num protowire.Number, f sutils.WireField) error {
+		switch num {
+		case 1:
+			// only used as a seed, parameters are of the down track seeded from it
+			d.RTPStats = buffer.NewRTPStatsSender(buffer.RTPStatsParams{Logger: logger.GetLogger()})
+			return d.RTPStats.UnmarshalBinary(f.Bytes())
+		case 2:
+			d.DeltaStatsSenderSnapshotId = uint32(f.Uint64())
+		case 3:
+			return d.ForwarderState.UnmarshalBinary(f.Bytes())
+		}
+		return nil
+	})
+}
+
 // -------------------------------------------------------------------
 
 /* STREAM-ALLOCATOR-DATA
@@ -213,6 +255,26 @@ type DowntrackParams struct {
 	Logger            logger.Logger
 	Trailer           []byte
 	RTCPWriter        func([]rtcp.Packet) error
+	MaxAudioGapFill   int
+	// deviation of actual clock rate from codec clock rate above which timestamps are corrected, 0 disables
+	ClockRateCorrectionThreshold float64
+	// log every Nth video layer selection decision, 0 disables
+	LayerSelectionLogSampling int
+	// video muted by subscriber for less than this resumes without a key frame, 0 disables
+	FastResumeWindow     time.Duration
+	AllocationPreference AllocationPreference
+	// reference timestamp off from expected by more than this on a switch re-anchors timestamp offset, 0 disables
+	RefTSDiscontinuityThreshold time.Duration
+	// spatial layer has to be available for this long before switching up to it, 0 disables
+	LayerUpHysteresis time.Duration
+	// when set, PLIs from subscriber are handed to this instead of being sent to receiver
+	SubscriberPLIHandler func(layer int32)
+	// minimum interval between key frame requests on behalf of subscriber, 0 disables
+	KeyFrameRequestThrottle time.Duration
+	// adapts redundancy of audio/red to loss reported by subscriber, set only when red is encoded from opus
+	AdaptiveRED config.AdaptiveREDConfig
+	// clock of sender reports, system clock when not set
+	ServerClock sutils.ServerClock
 }
 
 // DownTrack implements TrackLocal, is the track used to write packets
@@ -260,6 +322,7 @@ type DownTrack struct {
 	rtpStats *buffer.RTPStatsSender
 
 	totalRepeatedNACKs atomic.Uint32
+	gapFillPackets     atomic.Uint32
 
 	blankFramesGeneration atomic.Uint32
 
@@ -279,7 +342,14 @@ type DownTrack struct {
 	bytesRetransmitted              atomic.Uint32
 	*/
 
-	playoutDelay *PlayoutDelayController
+	// playoutDelay is nil when playout delay is disabled for this down track,
+	// playoutDelayController is kept across disable/enable so that it can be re-used
+	playoutDelay           atomic.Pointer[PlayoutDelayController]
+	playoutDelayLock       sync.Mutex
+	playoutDelayController *PlayoutDelayController
+
+	// nil if redundancy of audio/red is not adapted to loss
+	redRedundancy *redRedundancyController
 
 	pacer pacer.Pacer
 
@@ -290,11 +360,14 @@ type DownTrack struct {
 	keyFrameRequesterChMu     sync.RWMutex
 	keyFrameRequesterCh       chan struct{}
 	keyFrameRequesterChClosed bool
+	// sends key frame request held back by throttle
+	keyFrameRequestFlushTimer *time.Timer
 
 	cbMu                        sync.RWMutex
 	onStatsUpdate               func(dt *DownTrack, stat *livekit.AnalyticsStat)
 	onMaxSubscribedLayerChanged func(dt *DownTrack, layer int32)
 	onRttUpdate                 func(dt *DownTrack, rtt uint32)
+	onKeyFrameRequest           func(dt *DownTrack)
 	onCloseHandler              func(willBeResumed bool)
 
 	createdAt int64
@@ -330,10 +403,26 @@ func NewDownTrack(params DowntrackParams) (*DownTrack, error) {
 		false,
 		d.getExpectedRTPTimestamp,
 	)
+	if d.kind == webrtc.RTPCodecTypeAudio {
+		d.forwarder.SetMaxAudioGapFill(params.MaxAudioGapFill)
+		if params.AdaptiveRED.Enabled {
+			d.redRedundancy = newRedRedundancyController(params.AdaptiveRED, params.Logger)
+		}
+	}
+	d.forwarder.SetClockRateCorrection(params.ClockRateCorrectionThreshold)
+	d.forwarder.SetRefTSDiscontinuityThreshold(params.RefTSDiscontinuityThreshold)
+	if d.kind == webrtc.RTPCodecTypeVideo {
+		d.forwarder.SetLayerSelectionLogSampling(params.LayerSelectionLogSampling)
+		d.forwarder.SetFastResumeWindow(params.FastResumeWindow)
+		d.forwarder.SetLayerUpHysteresis(params.LayerUpHysteresis)
+		d.forwarder.SetAllocationPreference(params.AllocationPreference)
+		d.forwarder.SetKeyFrameRequestThrottle(params.KeyFrameRequestThrottle)
+	}
 
 	d.rtpStats = buffer.NewRTPStatsSender(buffer.RTPStatsParams{
-		ClockRate: d.codec.ClockRate,
-		Logger:    params.Logger,
+		ClockRate:   d.codec.ClockRate,
+		Logger:      params.Logger,
+		ServerClock: params.ServerClock,
 	})
 	d.deltaStatsSenderSnapshotId = d.rtpStats.NewSenderSnapshotId()
 
@@ -350,12 +439,8 @@ func NewDownTrack(params DowntrackParams) (*DownTrack, error) {
 	})
 
 	if d.kind == webrtc.RTPCodecTypeVideo {
-		if delay := params.PlayoutDelayLimit; delay.GetEnabled() {
-			var err error
-			d.playoutDelay, err = NewPlayoutDelayController(delay.GetMin(), delay.GetMax(), params.Logger, d.rtpStats)
-			if err != nil {
-				return nil, err
-			}
+		if err := d.SetPlayoutDelay(nil); err != nil {
+			return nil, err
 		}
 		go d.maxLayerNotifierWorker()
 		go d.keyFrameRequester()
@@ -600,6 +685,22 @@ func (d *DownTrack) GetTransceiver() *webrtc.RTPTransceiver {
 	return d.transceiver.Load()
 }
 
+// RequestKeyFrame sends a PLI to publisher for the layer being forwarded, subject to PLI throttling of that layer
+func (d *DownTrack) RequestKeyFrame() error {
+	if d.kind != webrtc.RTPCodecTypeVideo {
+		return ErrNoKeyFrameLayer
+	}
+
+	_, layer := d.forwarder.CheckSync()
+	if layer == buffer.InvalidLayerSpatial {
+		return ErrNoKeyFrameLayer
+	}
+
+	d.params.Logger.Debugw("sending PLI on request", "layer", layer)
+	d.params.Receiver.SendPLI(layer, false)
+	return nil
+}
+
 func (d *DownTrack) postKeyFrameRequestEvent() {
 	if d.kind != webrtc.RTPCodecTypeVideo {
 		return
@@ -648,13 +749,68 @@ func (d *DownTrack) keyFrameRequester() {
 
 		locked, layer := d.forwarder.CheckSync()
 		if !locked && layer != buffer.InvalidLayerSpatial && d.writable.Load() {
-			d.params.Logger.Debugw("sending PLI for layer lock", "layer", layer)
-			d.params.Receiver.SendPLI(layer, false)
-			d.rtpStats.UpdateLayerLockPliAndTime(1)
+			cause := KeyFrameRequestCauseLayerSwitch
+			if !d.forwarder.CurrentLayer().IsValid() {
+				cause = KeyFrameRequestCauseResume
+			}
+			if d.sendKeyFrameRequest(layer, cause) {
+				d.rtpStats.UpdateLayerLockPliAndTime(1)
+			}
 		}
 	}
 }
 
+// SetKeyFrameRequestThrottle sets minimum interval between key frame requests on behalf of subscriber, 0 disables
+func (d *DownTrack) SetKeyFrameRequestThrottle(throttle time.Duration) {
+	d.forwarder.SetKeyFrameRequestThrottle(throttle)
+}
+
+// sendKeyFrameRequest sends a PLI to publisher for the layer unless held back by subscriber throttle,
+// returns true if sent. A request held back is sent when throttle expires unless a key frame arrives first.
+func (d *DownTrack) sendKeyFrameRequest(layer int32, cause KeyFrameRequestCause) bool {
+	send, wait := d.forwarder.KeyFrameRequest(cause)
+	if !send {
+		d.keyFrameRequesterChMu.Lock()
+		if !d.keyFrameRequesterChClosed && d.keyFrameRequestFlushTimer == nil {
+			d.keyFrameRequestFlushTimer = time.AfterFunc(wait, d.flushKeyFrameRequest)
+		}
+		d.keyFrameRequesterChMu.Unlock()
+		return false
+	}
+
+	d.params.Logger.Debugw("sending PLI", "layer", layer, "cause", cause)
+	d.sendPLI(layer, cause)
+	return true
+}
+
+func (d *DownTrack) sendPLI(layer int32, cause KeyFrameRequestCause) {
+	if cause == KeyFrameRequestCauseDecodeError && d.params.SubscriberPLIHandler != nil {
+		d.params.SubscriberPLIHandler(layer)
+	} else {
+		d.params.Receiver.SendPLI(layer, false)
+	}
+}
+
+func (d *DownTrack) flushKeyFrameRequest() {
+	d.keyFrameRequesterChMu.Lock()
+	d.keyFrameRequestFlushTimer = nil
+	d.keyFrameRequesterChMu.Unlock()
+
+	cause, ok := d.forwarder.FlushKeyFrameRequest()
+	if !ok || d.IsClosed() {
+		return
+	}
+
+	_, layer := d.forwarder.CheckSync()
+	if layer == buffer.InvalidLayerSpatial {
+		return
+	}
+
+	d.params.Logger.Debugw("sending held back PLI", "layer", layer, "cause", cause)
+	d.sendPLI(layer, cause)
+	d.rtpStats.UpdatePliTime()
+}
+
 func (d *DownTrack) postMaxLayerNotifierEvent(event string) {
 	if d.kind != webrtc.RTPCodecTypeVideo {
 		return
@@ -715,13 +871,23 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) error {
 	poolEntity := PacketFactory.Get().(*[]byte)
 	payload := *poolEntity
 	copy(payload, tp.codecBytes)
-	n := copy(payload[len(tp.codecBytes):], extPkt.Packet.Payload[tp.incomingHeaderSize:])
-	if n != len(extPkt.Packet.Payload[tp.incomingHeaderSize:]) {
-		d.params.Logger.Errorw("payload overflow", nil, "want", len(extPkt.Packet.Payload[tp.incomingHeaderSize:]), "have", n)
+	prefixSize := len(tp.codecBytes)
+	incomingPayload := extPkt.Packet.Payload[tp.incomingHeaderSize:]
+	if d.redRedundancy != nil && d.mime == "audio/red" && !d.redRedundancy.IsEnabled() {
+		// send primary encoding only, as a RED payload with a single block
+		if blockPT, primary, err := splitPrimaryEncodingForRED(incomingPayload); err == nil {
+			payload[prefixSize] = blockPT
+			prefixSize++
+			incomingPayload = primary
+		}
+	}
+	n := copy(payload[prefixSize:], incomingPayload)
+	if n != len(incomingPayload) {
+		d.params.Logger.Errorw("payload overflow", nil, "want", len(incomingPayload), "have", n)
 		PacketFactory.Put(poolEntity)
 		return ErrPayloadOverflow
 	}
-	payload = payload[:len(tp.codecBytes)+n]
+	payload = payload[:prefixSize+n]
 
 	hdr, err := d.getTranslatedRTPHeader(extPkt, &tp)
 	if err != nil {
@@ -730,6 +896,10 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) error {
 		return err
 	}
 
+	if len(tp.gapFill) != 0 {
+		d.writeGapFillRTP(tp.gapFill, extPkt.Arrival)
+	}
+
 	var extensions []pacer.ExtensionData
 	if tp.ddBytes != nil {
 		extensions = append(
@@ -740,8 +910,8 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) error {
 			},
 		)
 	}
-	if d.playoutDelayExtID != 0 && d.playoutDelay != nil {
-		if val := d.playoutDelay.GetDelayExtension(hdr.SequenceNumber); val != nil {
+	if playoutDelay := d.playoutDelay.Load(); d.playoutDelayExtID != 0 && playoutDelay != nil {
+		if val := playoutDelay.GetDelayExtension(hdr.SequenceNumber); val != nil {
 			extensions = append(
 				extensions,
 				pacer.ExtensionData{
@@ -940,6 +1110,33 @@ func (d *DownTrack) PubMute(pubMuted bool) {
 	d.handleMute(pubMuted, changed)
 }
 
+// UpTrackSilenceChange enables or disables media forwarding - publisher side, implicit
+func (d *DownTrack) UpTrackSilenceChange(silent bool) {
+	changed := d.forwarder.PubSilence(silent)
+	d.handleMute(silent, changed)
+}
+
+// SetModerated pauses or resumes media forwarding on behalf of publisher,
+// a moderated video track is not resumed by stream allocation
+func (d *DownTrack) SetModerated(moderated bool) {
+	changed := d.forwarder.SetModerated(moderated)
+	d.handleMute(moderated, changed)
+	if changed && !moderated {
+		// forwarder was resynced, restart at a key frame
+		d.postKeyFrameRequestEvent()
+	}
+}
+
+// SetStartPaused holds a new subscription paused until the subscriber asks for it,
+// resuming restarts forwarding at a key frame
+func (d *DownTrack) SetStartPaused(startPaused bool) {
+	changed := d.forwarder.SetStartPaused(startPaused)
+	d.handleMute(startPaused, changed)
+	if changed && !startPaused {
+		d.postKeyFrameRequestEvent()
+	}
+}
+
 func (d *DownTrack) handleMute(muted bool, changed bool) {
 	if !changed {
 		return
@@ -1065,6 +1262,10 @@ func (d *DownTrack) CloseWithFlush(flush bool) {
 	d.keyFrameRequesterChMu.Lock()
 	d.keyFrameRequesterChClosed = true
 	close(d.keyFrameRequesterCh)
+	if d.keyFrameRequestFlushTimer != nil {
+		d.keyFrameRequestFlushTimer.Stop()
+		d.keyFrameRequestFlushTimer = nil
+	}
 	d.keyFrameRequesterChMu.Unlock()
 
 	if onCloseHandler := d.getOnCloseHandler(); onCloseHandler != nil {
@@ -1074,6 +1275,39 @@ func (d *DownTrack) CloseWithFlush(flush bool) {
 	d.ClearStreamAllocatorReportInterval()
 }
 
+// SetPlayoutDelay overrides the playout delay limits of a video down track,
+// nil restores the limits the down track was created with.
+// Takes effect only if the playout delay extension is negotiated.
+func (d *DownTrack) SetPlayoutDelay(delay *livekit.PlayoutDelay) error {
+	if d.kind != webrtc.RTPCodecTypeVideo {
+		return nil
+	}
+
+	if delay == nil {
+		delay = d.params.PlayoutDelayLimit
+	}
+
+	d.playoutDelayLock.Lock()
+	defer d.playoutDelayLock.Unlock()
+
+	if !delay.GetEnabled() {
+		d.playoutDelay.Store(nil)
+		return nil
+	}
+
+	if d.playoutDelayController == nil {
+		controller, err := NewPlayoutDelayController(delay.GetMin(), delay.GetMax(), d.params.Logger, d.rtpStats)
+		if err != nil {
+			return err
+		}
+		d.playoutDelayController = controller
+	} else if err := d.playoutDelayController.SetDelayLimits(delay.GetMin(), delay.GetMax()); err != nil {
+		return err
+	}
+	d.playoutDelay.Store(d.playoutDelayController)
+	return nil
+}
+
 func (d *DownTrack) SetMaxSpatialLayer(spatialLayer int32) {
 	changed, maxLayer := d.forwarder.SetMaxSpatialLayer(spatialLayer)
 	if !changed {
@@ -1087,6 +1321,10 @@ func (d *DownTrack) SetMaxSpatialLayer(spatialLayer int32) {
 	}
 }
 
+func (d *DownTrack) SupportsTemporalLayers() bool {
+	return d.forwarder.SupportsTemporalLayers()
+}
+
 func (d *DownTrack) SetMaxTemporalLayer(temporalLayer int32) {
 	changed, maxLayer := d.forwarder.SetMaxTemporalLayer(temporalLayer)
 	if !changed {
@@ -1098,6 +1336,45 @@ func (d *DownTrack) SetMaxTemporalLayer(temporalLayer int32) {
 	}
 }
 
+// ForceTargetLayer pins forwarding to layer irrespective of stream allocation till cleared,
+// a key frame is requested for the switch
+func (d *DownTrack) ForceTargetLayer(layer buffer.VideoLayer) {
+	if !d.forwarder.ForceTargetLayer(layer) {
+		return
+	}
+
+	d.postKeyFrameRequestEvent()
+	d.postMaxLayerNotifierEvent("force-target")
+
+	if sal := d.getStreamAllocatorListener(); sal != nil {
+		sal.OnSubscribedLayerChanged(d, d.forwarder.MaxLayer())
+	}
+}
+
+// ClearForcedTargetLayer returns to automatic allocation of layers
+func (d *DownTrack) ClearForcedTargetLayer() {
+	if !d.forwarder.ClearForcedTargetLayer() {
+		return
+	}
+
+	d.postMaxLayerNotifierEvent("clear-forced-target")
+
+	if sal := d.getStreamAllocatorListener(); sal != nil {
+		sal.OnSubscribedLayerChanged(d, d.forwarder.MaxLayer())
+	}
+}
+
+// SetMaxBitrate caps bitrate of forwarded layers irrespective of channel capacity, 0 removes the cap
+func (d *DownTrack) SetMaxBitrate(maxBitrate int64) {
+	if !d.forwarder.SetMaxBitrate(maxBitrate) {
+		return
+	}
+
+	if sal := d.getStreamAllocatorListener(); sal != nil {
+		sal.OnSubscriptionChanged(d)
+	}
+}
+
 func (d *DownTrack) MaxLayer() buffer.VideoLayer {
 	return d.forwarder.MaxLayer()
 }
@@ -1112,7 +1389,9 @@ func (d *DownTrack) GetState() DownTrackState {
 }
 
 func (d *DownTrack) SeedState(state DownTrackState) {
-	d.rtpStats.Seed(state.RTPStats)
+	if state.RTPStats != nil {
+		d.rtpStats.Seed(state.RTPStats)
+	}
 	d.deltaStatsSenderSnapshotId = state.DeltaStatsSenderSnapshotId
 	d.forwarder.SeedState(state.ForwarderState)
 }
@@ -1120,6 +1399,18 @@ func (d *DownTrack) SeedState(state DownTrackState) {
 func (d *DownTrack) UpTrackLayersChange() {
 	if sal := d.getStreamAllocatorListener(); sal != nil {
 		sal.OnAvailableLayersChanged(d)
+
+		if d.params.LayerUpHysteresis != 0 {
+			// allocate again once a layer which just became available can be switched up to
+			time.AfterFunc(d.params.LayerUpHysteresis, func() {
+				if d.IsClosed() {
+					return
+				}
+				if sal := d.getStreamAllocatorListener(); sal != nil {
+					sal.OnAvailableLayersChanged(d)
+				}
+			})
+		}
 	}
 }
 
@@ -1224,6 +1515,21 @@ func (d *DownTrack) getOnRttUpdate() func(dt *DownTrack, rtt uint32) {
 	return d.onRttUpdate
 }
 
+// OnKeyFrameRequest is called when the subscriber requests a key frame with PLI or FIR
+func (d *DownTrack) OnKeyFrameRequest(fn func(dt *DownTrack)) {
+	d.cbMu.Lock()
+	defer d.cbMu.Unlock()
+
+	d.onKeyFrameRequest = fn
+}
+
+func (d *DownTrack) getOnKeyFrameRequest() func(dt *DownTrack) {
+	d.cbMu.RLock()
+	defer d.cbMu.RUnlock()
+
+	return d.onKeyFrameRequest
+}
+
 func (d *DownTrack) OnMaxLayerChanged(fn func(dt *DownTrack, layer int32)) {
 	d.cbMu.Lock()
 	defer d.cbMu.Unlock()
@@ -1242,6 +1548,15 @@ func (d *DownTrack) IsDeficient() bool {
 	return d.forwarder.IsDeficient()
 }
 
+// GetDeficientDuration returns how long the down track has been continuously deficient, zero if not deficient
+func (d *DownTrack) GetDeficientDuration() time.Duration {
+	return d.forwarder.GetDeficientDuration()
+}
+
+func (d *DownTrack) PauseReason() VideoPauseReason {
+	return d.forwarder.PauseReason()
+}
+
 func (d *DownTrack) BandwidthRequested() int64 {
 	_, brs := d.params.Receiver.GetLayeredBitrate()
 	return d.forwarder.BandwidthRequested(brs)
@@ -1252,6 +1567,11 @@ func (d *DownTrack) DistanceToDesired() float64 {
 	return d.forwarder.DistanceToDesired(al, brs)
 }
 
+func (d *DownTrack) GetAllocationSnapshot() AllocationSnapshot {
+	al, brs := d.params.Receiver.GetLayeredBitrate()
+	return d.forwarder.GetAllocationSnapshot(al, brs)
+}
+
 func (d *DownTrack) AllocateOptimal(allowOvershoot bool) VideoAllocation {
 	al, brs := d.params.Receiver.GetLayeredBitrate()
 	allocation := d.forwarder.AllocateOptimal(al, brs, allowOvershoot)
@@ -1461,6 +1781,36 @@ func (d *DownTrack) writeBlankFrameRTP(duration float32, generation uint32) chan
 	return done
 }
 
+// writeGapFillRTP sends opus silence frames in place of packets lost upstream,
+// so that the subscriber can conceal the loss without waiting for a retransmission
+func (d *DownTrack) writeGapFillRTP(snts []SnTs, arrival time.Time) {
+	for _, st := range snts {
+		hdr := rtp.Header{
+			Version:        2,
+			PayloadType:    d.payloadType,
+			SequenceNumber: uint16(st.extSequenceNumber),
+			Timestamp:      uint32(st.extTimestamp),
+			SSRC:           d.ssrc,
+			CSRC:           []uint32{},
+		}
+
+		payload, _ := d.getOpusBlankFrame(false)
+		d.sendingPacket(&hdr, len(payload), &sendPacketMetadata{
+			packetTime:        arrival,
+			extSequenceNumber: st.extSequenceNumber,
+			extTimestamp:      st.extTimestamp,
+		})
+		d.pacer.Enqueue(pacer.Packet{
+			Header:             &hdr,
+			Payload:            payload,
+			AbsSendTimeExtID:   uint8(d.absSendTimeExtID),
+			TransportWideExtID: uint8(d.transportWideExtID),
+			WriteStream:        d.writeStream,
+		})
+	}
+	d.gapFillPackets.Add(uint32(len(snts)))
+}
+
 func (d *DownTrack) maybeAddTrailer(buf []byte) int {
 	if len(buf) < len(d.params.Trailer) {
 		d.params.Logger.Warnw("trailer too big", nil, "bufLen", len(buf), "trailerLen", len(d.params.Trailer))
@@ -1544,10 +1894,10 @@ func (d *DownTrack) handleRTCP(bytes []byte) {
 		_, layer := d.forwarder.CheckSync()
 		if pliOnce {
 			if layer != buffer.InvalidLayerSpatial {
-				d.params.Logger.Debugw("sending PLI RTCP", "layer", layer)
-				d.params.Receiver.SendPLI(layer, false)
+				if d.sendKeyFrameRequest(layer, KeyFrameRequestCauseDecodeError) {
+					d.rtpStats.UpdatePliTime()
+				}
 				d.isNACKThrottled.Store(true)
-				d.rtpStats.UpdatePliTime()
 				pliOnce = false
 			}
 		}
@@ -1594,18 +1944,22 @@ func (d *DownTrack) handleRTCP(bytes []byte) {
 					rttToReport = rtt
 				}
 
+				if d.redRedundancy != nil {
+					d.redRedundancy.Update(r.FractionLost)
+				}
+
 				/* STREAM-ALLOCATOR-DATA
 				if sal := d.getStreamAllocatorListener(); sal != nil {
 					sal.OnRTCPReceiverReport(d, r)
 				}
 				*/
 
-				if d.playoutDelay != nil {
-					d.playoutDelay.OnSeqAcked(uint16(r.LastSequenceNumber))
+				if playoutDelay := d.playoutDelay.Load(); playoutDelay != nil {
+					playoutDelay.OnSeqAcked(uint16(r.LastSequenceNumber))
 					// screen share track has inaccuracy jitter due to its low frame rate and bursty traffic
 					if d.params.Source != livekit.TrackSource_SCREEN_SHARE {
 						jitterMs := uint64(r.Jitter*1e3) / uint64(d.codec.ClockRate)
-						d.playoutDelay.SetJitter(uint32(jitterMs))
+						playoutDelay.SetJitter(uint32(jitterMs))
 					}
 				}
 			}
@@ -1669,6 +2023,12 @@ func (d *DownTrack) handleRTCP(bytes []byte) {
 	d.rtpStats.UpdatePli(numPLIs)
 	d.rtpStats.UpdateFir(numFIRs)
 
+	if numPLIs != 0 || numFIRs != 0 {
+		if onKeyFrameRequest := d.getOnKeyFrameRequest(); onKeyFrameRequest != nil {
+			onKeyFrameRequest(d)
+		}
+	}
+
 	if rttToReport != 0 {
 		if d.sequencer != nil {
 			d.sequencer.setRTT(rttToReport)
@@ -1865,7 +2225,7 @@ func (d *DownTrack) DebugInfo() map[string]interface{} {
 		stats["PacketCount"] = senderReport.PacketCount
 	}
 
-	return map[string]interface{}{
+	info := map[string]interface{}{
 		"SubscriberID":        d.params.SubID,
 		"TrackID":             d.id,
 		"StreamID":            d.params.StreamID,
@@ -1874,9 +2234,25 @@ func (d *DownTrack) DebugInfo() map[string]interface{} {
 		"Bound":               d.bound.Load(),
 		"Muted":               d.forwarder.IsMuted(),
 		"PubMuted":            d.forwarder.IsPubMuted(),
+		"PubSilent":           d.forwarder.IsPubSilent(),
+		"Moderated":           d.forwarder.IsModerated(),
+		"GapFillPackets":      d.gapFillPackets.Load(),
 		"CurrentSpatialLayer": d.forwarder.CurrentLayer().Spatial,
+		"Forwarder":           d.forwarder.GetSnapshot().DebugInfo(),
 		"Stats":               stats,
 	}
+	if d.redRedundancy != nil {
+		info["REDRedundancy"] = d.redRedundancy.DebugInfo()
+	}
+	return info
+}
+
+func (d *DownTrack) GetForwarderSnapshot() ForwarderSnapshot {
+	return d.forwarder.GetSnapshot()
+}
+
+func (d *DownTrack) LastAllocation() VideoAllocation {
+	return d.forwarder.LastAllocation()
 }
 
 func (d *DownTrack) getExpectedRTPTimestamp(at time.Time) (uint64, error) {
@@ -1891,6 +2267,11 @@ func (d *DownTrack) GetTrackStats() *livekit.RTPStats {
 	return d.rtpStats.ToProto()
 }
 
+// GetBitrate returns bitrate over the last connection quality interval, in bps
+func (d *DownTrack) GetBitrate() int64 {
+	return d.connectionStats.GetBitrate()
+}
+
 func (d *DownTrack) deltaStats(ds *buffer.RTPDeltaInfo) map[uint32]*buffer.StreamStatsWithLayers {
 	if ds == nil {
 		return nil
@@ -1915,6 +2296,10 @@ func (d *DownTrack) GetLastReceiverReportTime() time.Time {
 	return d.rtpStats.LastReceiverReportTime()
 }
 
+func (d *DownTrack) GetLastReceiverReport() (rtcp.ReceptionReport, time.Time) {
+	return d.rtpStats.LastReceiverReport()
+}
+
 func (d *DownTrack) GetTotalPacketsSent() uint64 {
 	return d.rtpStats.GetTotalPacketsPrimary()
 }
@@ -0,0 +1,78 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+
+	"github.com/pion/webrtc/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/testutils"
+)
+
+type keyFrameTestReceiver struct {
+	TrackReceiver
+
+	pliLayers []int32
+}
+
+func (r *keyFrameTestReceiver) TrackID() livekit.TrackID                { return "track" }
+func (r *keyFrameTestReceiver) DeleteDownTrack(_ livekit.ParticipantID) {}
+func (r *keyFrameTestReceiver) SendPLI(layer int32, _ bool) {
+	r.pliLayers = append(r.pliLayers, layer)
+}
+
+func TestDownTrackRequestKeyFrame(t *testing.T) {
+	newDownTrack := func(t *testing.T, codec webrtc.RTPCodecCapability) (*DownTrack, *keyFrameTestReceiver) {
+		receiver := &keyFrameTestReceiver{}
+		dt, err := NewDownTrack(DowntrackParams{
+			Codecs:   []webrtc.RTPCodecParameters{{RTPCodecCapability: codec}},
+			Receiver: receiver,
+			SubID:    "sub",
+			Logger:   logger.GetLogger(),
+		})
+		require.NoError(t, err)
+		t.Cleanup(dt.Close)
+		return dt, receiver
+	}
+
+	t.Run("video", func(t *testing.T) {
+		dt, receiver := newDownTrack(t, testutils.TestVP8Codec)
+
+		// nothing allocated yet
+		require.ErrorIs(t, dt.RequestKeyFrame(), ErrNoKeyFrameLayer)
+		require.Empty(t, receiver.pliLayers)
+
+		dt.forwarder.vls.SetRequestSpatial(1)
+		require.NoError(t, dt.RequestKeyFrame())
+		require.Equal(t, []int32{1}, receiver.pliLayers)
+		// not a PLI from subscriber
+		require.True(t, dt.rtpStats.LastPli().IsZero())
+
+		dt.forwarder.vls.SetRequestSpatial(buffer.InvalidLayerSpatial)
+		require.ErrorIs(t, dt.RequestKeyFrame(), ErrNoKeyFrameLayer)
+	})
+
+	t.Run("audio", func(t *testing.T) {
+		dt, receiver := newDownTrack(t, testutils.TestOpusCodec)
+		require.ErrorIs(t, dt.RequestKeyFrame(), ErrNoKeyFrameLayer)
+		require.Empty(t, receiver.pliLayers)
+	})
+}
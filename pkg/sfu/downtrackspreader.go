@@ -20,11 +20,15 @@ import (
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 )
 
 type DownTrackSpreaderParams struct {
 	Threshold int
-	Logger    logger.Logger
+	// when set, packets are written to down tracks on fan out workers
+	FanOut *FanOut
+	Logger logger.Logger
 }
 
 type DownTrackSpreader struct {
@@ -33,12 +37,15 @@ type DownTrackSpreader struct {
 	downTrackMu      sync.RWMutex
 	downTracks       map[livekit.ParticipantID]TrackSender
 	downTracksShadow []TrackSender
+	queues           map[livekit.ParticipantID]*fanOutQueue
+	queuesShadow     []*fanOutQueue
 }
 
 func NewDownTrackSpreader(params DownTrackSpreaderParams) *DownTrackSpreader {
 	d := &DownTrackSpreader{
 		params:     params,
 		downTracks: make(map[livekit.ParticipantID]TrackSender),
+		queues:     make(map[livekit.ParticipantID]*fanOutQueue),
 	}
 
 	return d
@@ -59,6 +66,12 @@ func (d *DownTrackSpreader) ResetAndGetDownTracks() []TrackSender {
 	d.downTracks = make(map[livekit.ParticipantID]TrackSender)
 	d.downTracksShadow = nil
 
+	for _, q := range d.queuesShadow {
+		q.close()
+	}
+	d.queues = make(map[livekit.ParticipantID]*fanOutQueue)
+	d.queuesShadow = nil
+
 	return downTracks
 }
 
@@ -68,6 +81,15 @@ func (d *DownTrackSpreader) Store(ts TrackSender) {
 
 	d.downTracks[ts.SubscriberID()] = ts
 	d.shadowDownTracks()
+
+	if d.params.FanOut != nil {
+		// packets queued for a replaced down track are not carried over
+		if q := d.queues[ts.SubscriberID()]; q != nil {
+			q.close()
+		}
+		d.queues[ts.SubscriberID()] = d.params.FanOut.newQueue(ts)
+		d.shadowQueues()
+	}
 }
 
 func (d *DownTrackSpreader) Free(subscriberID livekit.ParticipantID) {
@@ -76,6 +98,12 @@ func (d *DownTrackSpreader) Free(subscriberID livekit.ParticipantID) {
 
 	delete(d.downTracks, subscriberID)
 	d.shadowDownTracks()
+
+	if q := d.queues[subscriberID]; q != nil {
+		q.close()
+		delete(d.queues, subscriberID)
+		d.shadowQueues()
+	}
 }
 
 func (d *DownTrackSpreader) HasDownTrack(subscriberID livekit.ParticipantID) bool {
@@ -99,6 +127,29 @@ func (d *DownTrackSpreader) Broadcast(writer func(TrackSender)) {
 	utils.ParallelExec(downTracks, threshold, step, writer)
 }
 
+// WriteRTP writes packet to all down tracks. With fan out, packet is queued for fan out workers
+// and written after this returns, otherwise it is written before returning.
+func (d *DownTrackSpreader) WriteRTP(pkt *buffer.ExtPacket, layer int32) {
+	if d.params.FanOut == nil {
+		d.Broadcast(func(dt TrackSender) {
+			_ = dt.WriteRTP(pkt, layer)
+		})
+		return
+	}
+
+	d.downTrackMu.RLock()
+	queues := d.queuesShadow
+	d.downTrackMu.RUnlock()
+	if len(queues) == 0 {
+		return
+	}
+
+	detached := detachExtPacket(pkt)
+	for _, q := range queues {
+		q.enqueue(detached, layer)
+	}
+}
+
 func (d *DownTrackSpreader) DownTrackCount() int {
 	d.downTrackMu.RLock()
 	defer d.downTrackMu.RUnlock()
@@ -111,3 +162,10 @@ func (d *DownTrackSpreader) shadowDownTracks() {
 		d.downTracksShadow = append(d.downTracksShadow, dt)
 	}
 }
+
+func (d *DownTrackSpreader) shadowQueues() {
+	d.queuesShadow = make([]*fanOutQueue, 0, len(d.queues))
+	for _, q := range d.queues {
+		d.queuesShadow = append(d.queuesShadow, q)
+	}
+}
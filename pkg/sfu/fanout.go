@@ -0,0 +1,223 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/gammazero/deque"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+const (
+	defaultFanOutQueueSize = 128
+	// packets written to a down track before yielding the worker to other down tracks
+	fanOutBatchSize = 16
+)
+
+type FanOutParams struct {
+	// number of workers writing to down tracks, defaults to number of CPUs
+	Workers int
+	// packets queued per down track, defaults to defaultFanOutQueueSize
+	QueueSize int
+	Logger    logger.Logger
+}
+
+// FanOut writes packets to down tracks on a bounded pool of workers, shared by all receivers of a node.
+//
+// Each down track has its own queue which is drained by at most one worker at a time,
+// so packets are written to a down track in order. A down track whose transport blocks holds up
+// one worker and its own queue, other down tracks continue on the remaining workers.
+// When the queue of a down track is full, packets for that down track are dropped
+// instead of blocking the publisher read loop.
+type FanOut struct {
+	params FanOutParams
+
+	lock      sync.Mutex
+	cond      *sync.Cond
+	ready     deque.Deque[*fanOutQueue]
+	isStopped bool
+}
+
+func NewFanOut(params FanOutParams) *FanOut {
+	if params.Workers <= 0 {
+		params.Workers = runtime.NumCPU()
+	}
+	if params.QueueSize <= 0 {
+		params.QueueSize = defaultFanOutQueueSize
+	}
+
+	f := &FanOut{
+		params: params,
+	}
+	f.cond = sync.NewCond(&f.lock)
+	f.ready.SetMinCapacity(7)
+
+	for i := 0; i < params.Workers; i++ {
+		go f.worker()
+	}
+	return f
+}
+
+func (f *FanOut) Stop() {
+	f.lock.Lock()
+	f.isStopped = true
+	f.lock.Unlock()
+
+	f.cond.Broadcast()
+}
+
+func (f *FanOut) newQueue(ts TrackSender) *fanOutQueue {
+	q := &fanOutQueue{
+		fanOut: f,
+		ts:     ts,
+	}
+	q.packets.SetMinCapacity(7)
+	return q
+}
+
+func (f *FanOut) schedule(q *fanOutQueue) {
+	f.lock.Lock()
+	if f.isStopped {
+		f.lock.Unlock()
+		return
+	}
+	f.ready.PushBack(q)
+	f.lock.Unlock()
+
+	f.cond.Signal()
+}
+
+func (f *FanOut) worker() {
+	for {
+		f.lock.Lock()
+		for f.ready.Len() == 0 && !f.isStopped {
+			f.cond.Wait()
+		}
+		if f.isStopped {
+			f.lock.Unlock()
+			return
+		}
+		q := f.ready.PopFront()
+		f.lock.Unlock()
+
+		if q.drain(fanOutBatchSize) {
+			// more pending, go to the back of the line to be fair to other down tracks
+			f.schedule(q)
+		}
+	}
+}
+
+// ------------------------------------------------
+
+type fanOutPacket struct {
+	pkt   *buffer.ExtPacket
+	layer int32
+}
+
+type fanOutQueue struct {
+	fanOut *FanOut
+	ts     TrackSender
+
+	lock sync.Mutex
+	// packets waiting to be written
+	packets deque.Deque[fanOutPacket]
+	// in ready list of fan out or being drained by a worker
+	isScheduled bool
+	isClosed    bool
+
+	dropped atomic.Uint64
+}
+
+func (q *fanOutQueue) enqueue(pkt *buffer.ExtPacket, layer int32) {
+	q.lock.Lock()
+	if q.isClosed {
+		q.lock.Unlock()
+		return
+	}
+
+	if q.packets.Len() >= q.fanOut.params.QueueSize {
+		q.lock.Unlock()
+
+		dropped := q.dropped.Inc()
+		if (dropped-1)%100 == 0 {
+			q.fanOut.params.Logger.Warnw(
+				"fan out queue full, dropping packet", nil,
+				"trackID", q.ts.ID(),
+				"subscriberID", q.ts.SubscriberID(),
+				"count", dropped,
+			)
+		}
+		return
+	}
+
+	q.packets.PushBack(fanOutPacket{pkt: pkt, layer: layer})
+	schedule := !q.isScheduled
+	q.isScheduled = true
+	q.lock.Unlock()
+
+	if schedule {
+		q.fanOut.schedule(q)
+	}
+}
+
+func (q *fanOutQueue) close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.isClosed = true
+	q.packets.Clear()
+}
+
+// drain writes up to batch queued packets, returns true if packets are still pending
+func (q *fanOutQueue) drain(batch int) bool {
+	for i := 0; i < batch; i++ {
+		q.lock.Lock()
+		if q.isClosed || q.packets.Len() == 0 {
+			q.isScheduled = false
+			q.lock.Unlock()
+			return false
+		}
+		p := q.packets.PopFront()
+		q.lock.Unlock()
+
+		_ = q.ts.WriteRTP(p.pkt, p.layer)
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.isClosed || q.packets.Len() == 0 {
+		q.isScheduled = false
+		return false
+	}
+	return true
+}
+
+// detachExtPacket copies packet data which receivers reuse once the packet has been forwarded,
+// so that the packet can be written after forwarding returns
+func detachExtPacket(ep *buffer.ExtPacket) *buffer.ExtPacket {
+	detached := *ep
+	detached.Packet = ep.Packet.Clone()
+	if ep.RawPacket != nil {
+		detached.RawPacket = append([]byte(nil), ep.RawPacket...)
+	}
+	return &detached
+}
@@ -0,0 +1,262 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+type fanOutDowntrack struct {
+	TrackSender
+	subscriberID livekit.ParticipantID
+
+	// closed to unblock writes
+	block chan struct{}
+	// simulated cost of a write
+	cost time.Duration
+	wg   *sync.WaitGroup
+
+	lock            sync.Mutex
+	sequenceNumbers []uint16
+	payloads        [][]byte
+	latencies       []time.Duration
+}
+
+func newFanOutDowntrack(idx int) *fanOutDowntrack {
+	return &fanOutDowntrack{
+		TrackSender:  &DownTrack{},
+		subscriberID: livekit.ParticipantID(fmt.Sprintf("PA_%d", idx)),
+	}
+}
+
+func (dt *fanOutDowntrack) SubscriberID() livekit.ParticipantID {
+	return dt.subscriberID
+}
+
+func (dt *fanOutDowntrack) ID() string {
+	return "TR_fanout"
+}
+
+func (dt *fanOutDowntrack) WriteRTP(p *buffer.ExtPacket, _ int32) error {
+	if dt.block != nil {
+		<-dt.block
+	}
+	for start := time.Now(); time.Since(start) < dt.cost; {
+	}
+
+	dt.lock.Lock()
+	dt.sequenceNumbers = append(dt.sequenceNumbers, p.Packet.SequenceNumber)
+	dt.payloads = append(dt.payloads, p.Packet.Payload)
+	dt.latencies = append(dt.latencies, time.Since(p.Arrival))
+	dt.lock.Unlock()
+
+	if dt.wg != nil {
+		dt.wg.Done()
+	}
+	return nil
+}
+
+func (dt *fanOutDowntrack) received() []uint16 {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+
+	return slices.Clone(dt.sequenceNumbers)
+}
+
+func newFanOutSpreader(t testing.TB, queueSize int) *DownTrackSpreader {
+	fanOut := NewFanOut(FanOutParams{
+		Workers:   4,
+		QueueSize: queueSize,
+		Logger:    logger.GetLogger(),
+	})
+	t.Cleanup(fanOut.Stop)
+
+	return NewDownTrackSpreader(DownTrackSpreaderParams{
+		FanOut: fanOut,
+		Logger: logger.GetLogger(),
+	})
+}
+
+func newFanOutTestPacket(sn uint16, payload []byte) *buffer.ExtPacket {
+	return &buffer.ExtPacket{
+		Arrival: time.Now(),
+		Packet: &rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sn},
+			Payload: payload,
+		},
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	t.Run("in order per down track", func(t *testing.T) {
+		spreader := newFanOutSpreader(t, 1000)
+
+		var downTracks []*fanOutDowntrack
+		for i := 0; i < 50; i++ {
+			dt := newFanOutDowntrack(i)
+			downTracks = append(downTracks, dt)
+			spreader.Store(dt)
+		}
+
+		var expected []uint16
+		for sn := uint16(0); sn < 200; sn++ {
+			spreader.WriteRTP(newFanOutTestPacket(sn, []byte{1}), 0)
+			expected = append(expected, sn)
+		}
+
+		for _, dt := range downTracks {
+			require.Eventually(t, func() bool { return len(dt.received()) == len(expected) }, time.Second, time.Millisecond)
+			require.Equal(t, expected, dt.received())
+		}
+	})
+
+	t.Run("blocked down track", func(t *testing.T) {
+		spreader := newFanOutSpreader(t, 10)
+
+		blocked := newFanOutDowntrack(0)
+		blocked.block = make(chan struct{})
+		spreader.Store(blocked)
+
+		var wg sync.WaitGroup
+		var downTracks []*fanOutDowntrack
+		for i := 1; i < 20; i++ {
+			dt := newFanOutDowntrack(i)
+			dt.wg = &wg
+			downTracks = append(downTracks, dt)
+			spreader.Store(dt)
+		}
+
+		// publisher is not held up by blocked down track, other down tracks get all packets
+		for sn := uint16(0); sn < 100; sn++ {
+			wg.Add(len(downTracks))
+			spreader.WriteRTP(newFanOutTestPacket(sn, []byte{1}), 0)
+			wg.Wait()
+		}
+		for _, dt := range downTracks {
+			require.Len(t, dt.received(), 100)
+		}
+
+		// packets beyond queue size are dropped for blocked down track, queued ones are written in order
+		close(blocked.block)
+		require.Eventually(t, func() bool { return len(blocked.received()) == 11 }, time.Second, time.Millisecond)
+		require.True(t, slices.IsSorted(blocked.received()))
+		require.Equal(t, uint64(89), spreader.queues[blocked.SubscriberID()].dropped.Load())
+	})
+
+	t.Run("packet data is detached", func(t *testing.T) {
+		spreader := newFanOutSpreader(t, 10)
+
+		dt := newFanOutDowntrack(0)
+		dt.block = make(chan struct{})
+		spreader.Store(dt)
+
+		// receivers reuse packet buffer after forwarding
+		payload := []byte{1, 2, 3}
+		spreader.WriteRTP(newFanOutTestPacket(1, payload), 0)
+		payload[0] = 9
+
+		close(dt.block)
+		require.Eventually(t, func() bool { return len(dt.received()) == 1 }, time.Second, time.Millisecond)
+		require.Equal(t, []byte{1, 2, 3}, dt.payloads[0])
+	})
+
+	t.Run("freed down track", func(t *testing.T) {
+		spreader := newFanOutSpreader(t, 10)
+
+		dt := newFanOutDowntrack(0)
+		dt.block = make(chan struct{})
+		spreader.Store(dt)
+
+		spreader.WriteRTP(newFanOutTestPacket(1, []byte{1}), 0)
+		spreader.WriteRTP(newFanOutTestPacket(2, []byte{1}), 0)
+		spreader.Free(dt.SubscriberID())
+		spreader.WriteRTP(newFanOutTestPacket(3, []byte{1}), 0)
+
+		// packet being written when freed completes, rest are dropped
+		close(dt.block)
+		time.Sleep(50 * time.Millisecond)
+		require.LessOrEqual(t, len(dt.received()), 1)
+	})
+}
+
+// BenchmarkFanOut compares writing a packet to subscribers on the publisher read loop (sequential below load
+// balance threshold, parallel above it) with fan out workers. Reports time publisher read loop is held up per
+// packet and distribution of latency from packet arrival to write across subscribers.
+func BenchmarkFanOut(b *testing.B) {
+	const writeCost = 10 * time.Microsecond
+
+	for _, numSubscribers := range []int{1, 10, 100, 500} {
+		for _, mode := range []string{"read-loop", "fan-out"} {
+			b.Run(fmt.Sprintf("%s/subscribers=%d", mode, numSubscribers), func(b *testing.B) {
+				var spreader *DownTrackSpreader
+				if mode == "fan-out" {
+					spreader = newFanOutSpreader(b, 128)
+				} else {
+					spreader = NewDownTrackSpreader(DownTrackSpreaderParams{
+						Threshold: 20,
+						Logger:    logger.GetLogger(),
+					})
+				}
+
+				var wg sync.WaitGroup
+				var downTracks []*fanOutDowntrack
+				for i := 0; i < numSubscribers; i++ {
+					dt := newFanOutDowntrack(i)
+					dt.cost = writeCost
+					dt.wg = &wg
+					downTracks = append(downTracks, dt)
+					spreader.Store(dt)
+				}
+
+				payload := make([]byte, 1000)
+				var readLoop time.Duration
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					wg.Add(numSubscribers)
+					start := time.Now()
+					spreader.WriteRTP(newFanOutTestPacket(uint16(i), payload), 0)
+					readLoop += time.Since(start)
+					wg.Wait()
+				}
+				b.StopTimer()
+
+				var latencies []time.Duration
+				for _, dt := range downTracks {
+					latencies = append(latencies, dt.latencies...)
+				}
+				slices.Sort(latencies)
+				percentile := func(p float64) float64 {
+					return float64(latencies[int(float64(len(latencies)-1)*p)].Microseconds())
+				}
+				b.ReportMetric(float64(readLoop.Microseconds())/float64(b.N), "read-loop-µs/op")
+				b.ReportMetric(percentile(0.5), "p50-µs")
+				b.ReportMetric(percentile(0.99), "p99-µs")
+				b.ReportMetric(percentile(1.0), "max-µs")
+			})
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -26,6 +27,7 @@ import (
 	"github.com/pion/rtp"
 	"github.com/pion/webrtc/v3"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/livekit/protocol/logger"
 
@@ -34,6 +36,7 @@ import (
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
 	"github.com/livekit/livekit-server/pkg/sfu/videolayerselector"
 	"github.com/livekit/livekit-server/pkg/sfu/videolayerselector/temporallayerselector"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 // Forwarder
@@ -47,6 +50,15 @@ const (
 	ResumeBehindHighThresholdSeconds  = float64(2.0)   // 2 seconds
 	LayerSwitchBehindThresholdSeconds = float64(0.05)  // 50ms
 	SwitchAheadThresholdSeconds       = float64(0.025) // 25ms
+
+	// smallest change of timestamp scale applied, avoids re-anchoring on estimation noise
+	cClockRateCorrectionMinChange = 0.001
+
+	// time a lost audio packet is given to arrive late or be retransmitted before it is filled
+	cAudioGapFillWait = 60 * time.Millisecond
+
+	// a subscriber asking for a key frame this soon after resuming without one could not continue decoding
+	cFastResumeCheckWindow = 2 * time.Second
 )
 
 // -------------------------------------------------------------------
@@ -57,8 +69,12 @@ const (
 	VideoPauseReasonNone VideoPauseReason = iota
 	VideoPauseReasonMuted
 	VideoPauseReasonPubMuted
+	VideoPauseReasonPubSilent
 	VideoPauseReasonFeedDry
 	VideoPauseReasonBandwidth
+	VideoPauseReasonBitrateCap
+	VideoPauseReasonModerated
+	VideoPauseReasonStartPaused
 )
 
 func (v VideoPauseReason) String() string {
@@ -69,10 +85,18 @@ func (v VideoPauseReason) String() string {
 		return "MUTED"
 	case VideoPauseReasonPubMuted:
 		return "PUB_MUTED"
+	case VideoPauseReasonPubSilent:
+		return "PUB_SILENT"
 	case VideoPauseReasonFeedDry:
 		return "FEED_DRY"
 	case VideoPauseReasonBandwidth:
 		return "BANDWIDTH"
+	case VideoPauseReasonBitrateCap:
+		return "BITRATE_CAP"
+	case VideoPauseReasonModerated:
+		return "MODERATED"
+	case VideoPauseReasonStartPaused:
+		return "START_PAUSED"
 	default:
 		return fmt.Sprintf("%d", int(v))
 	}
@@ -80,6 +104,80 @@ func (v VideoPauseReason) String() string {
 
 // -------------------------------------------------------------------
 
+// KeyFrameRequestCause is why a key frame was requested on behalf of a subscriber
+type KeyFrameRequestCause int
+
+const (
+	// switching layers while forwarding
+	KeyFrameRequestCauseLayerSwitch KeyFrameRequestCause = iota
+	// subscriber sent PLI/FIR, i. e. it could not decode
+	KeyFrameRequestCauseDecodeError
+	// forwarding starts or resumes after a pause
+	KeyFrameRequestCauseResume
+	numKeyFrameRequestCauses
+)
+
+func (k KeyFrameRequestCause) String() string {
+	switch k {
+	case KeyFrameRequestCauseLayerSwitch:
+		return "LAYER_SWITCH"
+	case KeyFrameRequestCauseDecodeError:
+		return "DECODE_ERROR"
+	case KeyFrameRequestCauseResume:
+		return "RESUME"
+	default:
+		return fmt.Sprintf("%d", int(k))
+	}
+}
+
+// -------------------------------------------------------------------
+
+// AllocationPreference decides which layers are given up first when a track
+// contributes bandwidth to other tracks under congestion
+type AllocationPreference int
+
+const (
+	// weighs bandwidth saved against cost of transition and quality, spatial switches are expensive
+	AllocationPreferenceSpatial AllocationPreference = iota
+	// temporal layers are shed before spatial layers, keeps resolution at a lower frame rate
+	AllocationPreferenceTemporal
+)
+
+func (a AllocationPreference) String() string {
+	switch a {
+	case AllocationPreferenceSpatial:
+		return "SPATIAL"
+	case AllocationPreferenceTemporal:
+		return "TEMPORAL"
+	default:
+		return fmt.Sprintf("%d", int(a))
+	}
+}
+
+// -------------------------------------------------------------------
+
+// AllocationSnapshot holds the state of a forwarder which decides allocations,
+// captured to replay allocation decisions offline
+type AllocationSnapshot struct {
+	MimeType        string
+	AvailableLayers []int32
+	Bitrates        Bitrates
+	MaxLayer        buffer.VideoLayer
+	MaxSeenLayer    buffer.VideoLayer
+	CurrentLayer    buffer.VideoLayer
+	TargetLayer     buffer.VideoLayer
+	RequestSpatial  int32
+	Muted           bool
+	PubMuted        bool
+	PubSilent       bool
+	Moderated       bool
+	MaxBitrate      int64
+	// bandwidth requested by last allocation
+	BandwidthRequested int64
+}
+
+// -------------------------------------------------------------------
+
 type VideoAllocation struct {
 	PauseReason         VideoPauseReason
 	IsDeficient         bool
@@ -140,12 +238,17 @@ var (
 type VideoAllocationProvisional struct {
 	muted           bool
 	pubMuted        bool
+	pubSilent       bool
+	moderated       bool
+	startPaused     bool
 	maxSeenLayer    buffer.VideoLayer
 	availableLayers []int32
 	bitrates        Bitrates
 	maxLayer        buffer.VideoLayer
 	currentLayer    buffer.VideoLayer
 	allocatedLayer  buffer.VideoLayer
+	// no layer is under the max bitrate set by subscriber
+	noLayerUnderMaxBitrate bool
 }
 
 // -------------------------------------------------------------------
@@ -182,6 +285,7 @@ type TranslationParams struct {
 	incomingHeaderSize int
 	codecBytes         []byte
 	marker             bool
+	gapFill            []SnTs
 }
 
 // -------------------------------------------------------------------
@@ -213,6 +317,116 @@ func (f ForwarderState) String() string {
 	)
 }
 
+func (f ForwarderState) MarshalBinary() ([]byte, error) {
+	e := &sutils.WireEncoder{}
+	e.Bool(1, f.Started)
+	e.Int64(2, int64(f.ReferenceLayerSpatial))
+	e.Time(3, f.PreStartTime)
+	e.Uint64(4, f.ExtFirstTS)
+	e.Uint64(5, f.DummyStartTSOffset)
+
+	rtpState, err := f.RTP.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	e.Bytes(6, rtpState)
+
+	switch codecState := f.Codec.(type) {
+	case codecmunger.VP8State:
+		vp8State, err := codecState.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		e.Message(7, vp8State)
+	}
+	return e.Encoded(), nil
+}
+
+func (f *ForwarderState) UnmarshalBinary(data []byte) error {
+	*f = ForwarderState{}
+	return sutils.DecodeWire(data, func(num protowire.Number, field sutils.WireField) error {
+		switch num {
+		case 1:
+			f.Started = field.Bool()
+		case 2:
+			f.ReferenceLayerSpatial = int32(field.Int64())
+		case 3:
+			f.PreStartTime = field.Time()
+		case 4:
+			f.ExtFirstTS = field.Uint64()
+		case 5:
+			f.DummyStartTSOffset = field.Uint64()
+		case 6:
+			return f.RTP.UnmarshalBinary(field.Bytes())
+		case 7:
+			vp8State := codecmunger.VP8State{}
+			if err := vp8State.UnmarshalBinary(field.Bytes()); err != nil {
+				return err
+			}
+			f.Codec = vp8State
+		}
+		return nil
+	})
+}
+
+// -------------------------------------------------------------------
+
+// ForwarderSnapshot is the forwarding state of a forwarder for observability
+type ForwarderSnapshot struct {
+	Started         bool
+	CurrentLayer    buffer.VideoLayer
+	TargetLayer     buffer.VideoLayer
+	MaxLayer        buffer.VideoLayer
+	LastAllocation  VideoAllocation
+	RefTSOffset     uint64
+	LastSSRC        uint32
+	IsSwitchPending bool
+	Moderated       bool
+	// time forwarding last switched or resumed to a layer, zero if it has not
+	LastLayerTransitionAt time.Time
+	// resumes after subscriber mute without a key frame request, and those which fell back to one
+	NumKeyFrameRequestsSaved uint32
+	NumFastResumeFallbacks   uint32
+	// key frame requests by cause, those held back by throttle and number of times held back
+	// requests were cleared by a forwarded key frame before they had to be sent
+	NumKeyFrameRequests          [numKeyFrameRequestCauses]uint32
+	NumKeyFrameRequestsThrottled uint32
+	NumKeyFrameRequestsCoalesced uint32
+	// invalid unless target layer is forced
+	ForcedTargetLayer buffer.VideoLayer
+}
+
+func (f ForwarderSnapshot) DebugInfo() map[string]interface{} {
+	info := map[string]interface{}{
+		"Started":                  f.Started,
+		"CurrentLayer":             f.CurrentLayer.String(),
+		"TargetLayer":              f.TargetLayer.String(),
+		"MaxLayer":                 f.MaxLayer.String(),
+		"LastAllocation":           f.LastAllocation.String(),
+		"PauseReason":              f.LastAllocation.PauseReason.String(),
+		"RefTSOffset":              f.RefTSOffset,
+		"LastSSRC":                 f.LastSSRC,
+		"IsSwitchPending":          f.IsSwitchPending,
+		"Moderated":                f.Moderated,
+		"NumKeyFrameRequestsSaved": f.NumKeyFrameRequestsSaved,
+		"NumFastResumeFallbacks":   f.NumFastResumeFallbacks,
+		"KeyFrameRequests": map[string]interface{}{
+			KeyFrameRequestCauseLayerSwitch.String(): f.NumKeyFrameRequests[KeyFrameRequestCauseLayerSwitch],
+			KeyFrameRequestCauseDecodeError.String(): f.NumKeyFrameRequests[KeyFrameRequestCauseDecodeError],
+			KeyFrameRequestCauseResume.String():      f.NumKeyFrameRequests[KeyFrameRequestCauseResume],
+			"Throttled":                              f.NumKeyFrameRequestsThrottled,
+			"Coalesced":                              f.NumKeyFrameRequestsCoalesced,
+		},
+	}
+	if !f.LastLayerTransitionAt.IsZero() {
+		info["LastLayerTransitionAt"] = f.LastLayerTransitionAt.String()
+	}
+	if f.ForcedTargetLayer.IsValid() {
+		info["ForcedTargetLayer"] = f.ForcedTargetLayer.String()
+	}
+	return info
+}
+
 // -------------------------------------------------------------------
 
 type refInfo struct {
@@ -230,7 +444,53 @@ type Forwarder struct {
 
 	muted                 bool
 	pubMuted              bool
+	pubSilent             bool
+	moderated             bool
+	startPaused           bool
 	resumeBehindThreshold float64
+	maxBitrate            int64
+	maxAudioGapFill       int
+
+	// deviation of actual clock rate from codec clock rate, as a fraction, above which timestamps are scaled, 0 disables
+	clockRateCorrectionThreshold float64
+
+	allocationPreference AllocationPreference
+
+	// log every Nth layer selection decision, 0 disables
+	layerSelectionLogSampling int
+	numLayerSelections        uint64
+
+	// subscriber mute shorter than this resumes at the layer forwarded before mute
+	// without requesting a key frame, 0 disables
+	fastResumeWindow         time.Duration
+	lastKeyFrameAt           time.Time
+	mutedAt                  time.Time
+	mutedCurrentLayer        buffer.VideoLayer
+	mutedTargetLayer         buffer.VideoLayer
+	awaitingFrameBoundary    bool
+	fastResumedAt            time.Time
+	numKeyFrameRequestsSaved uint32
+	numFastResumeFallbacks   uint32
+
+	// minimum interval between key frame requests on behalf of subscriber, 0 disables,
+	// requests within the interval are held back and coalesced into one
+	keyFrameRequestThrottle      time.Duration
+	lastKeyFrameRequestAt        time.Time
+	pendingKeyFrameRequest       bool
+	pendingKeyFrameRequestCause  KeyFrameRequestCause
+	numKeyFrameRequests          [numKeyFrameRequestCauses]uint32
+	numKeyFrameRequestsThrottled uint32
+	numKeyFrameRequestsCoalesced uint32
+
+	// a spatial layer above current is switched up to only after it has been available
+	// for this long, switching down is immediate, 0 disables
+	layerUpHysteresis   time.Duration
+	layerAvailableSince [buffer.DefaultMaxLayerSpatial + 1]time.Time
+
+	// target layer pinned irrespective of allocation, invalid when allocation is automatic.
+	// While forced, max layer follows forced layer and subscribed max layer is held in unforcedMaxLayer
+	forcedTargetLayer buffer.VideoLayer
+	unforcedMaxLayer  buffer.VideoLayer
 
 	started               bool
 	preStartTime          time.Time
@@ -238,16 +498,23 @@ type Forwarder struct {
 	lastSSRC              uint32
 	referenceLayerSpatial int32
 	dummyStartTSOffset    uint64
-	refInfos              [buffer.DefaultMaxLayerSpatial + 1]refInfo
-	refIsSVC              bool
+	// reference off from expected by more than this on a switch re-anchors dummyStartTSOffset, 0 disables
+	refTSDiscontinuityThreshold time.Duration
+	refInfos                    [buffer.DefaultMaxLayerSpatial + 1]refInfo
+	refIsSVC                    bool
 
 	provisional *VideoAllocationProvisional
 
-	lastAllocation VideoAllocation
+	lastAllocation        VideoAllocation
+	lastLayerTransitionAt time.Time
+	deficientSince        time.Time
 
 	rtpMunger *RTPMunger
 
 	vls videolayerselector.VideoLayerSelector
+	// temporal layers of codecs without temporal layer info in payload, marked using frame marking extension
+	// or derived from payload of streams without B-frames
+	temporalMarkingAvailable bool
 
 	codecMunger codecmunger.CodecMunger
 }
@@ -265,6 +532,8 @@ func NewForwarder(
 		getExpectedRTPTimestamp: getExpectedRTPTimestamp,
 		referenceLayerSpatial:   buffer.InvalidLayerSpatial,
 		lastAllocation:          VideoAllocationDefault,
+		mutedCurrentLayer:       buffer.InvalidLayer,
+		forcedTargetLayer:       buffer.InvalidLayer,
 		rtpMunger:               NewRTPMunger(logger),
 		vls:                     videolayerselector.NewNull(logger),
 		codecMunger:             codecmunger.NewNull(logger),
@@ -304,6 +573,125 @@ func (f *Forwarder) SetMaxTemporalLayerSeen(maxTemporalLayerSeen int32) bool {
 	return true
 }
 
+// SetMaxAudioGapFill enables filling gaps of up to maxPackets lost opus packets with silence frames
+// when they have not arrived within cAudioGapFillWait, so that the subscriber jitter buffer does not
+// stall waiting for a retransmission. 0 disables it.
+func (f *Forwarder) SetMaxAudioGapFill(maxPackets int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.maxAudioGapFill = maxPackets
+}
+
+// SetAllocationPreference sets which layers are given up first when contributing bandwidth to other tracks
+func (f *Forwarder) SetAllocationPreference(preference AllocationPreference) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.allocationPreference = preference
+}
+
+// SetKeyFrameRequestThrottle sets minimum interval between key frame requests on behalf of subscriber, 0 disables
+func (f *Forwarder) SetKeyFrameRequestThrottle(throttle time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.keyFrameRequestThrottle = throttle
+}
+
+// KeyFrameRequest counts a key frame request and returns true if it should be sent now.
+// Within throttle interval of the previous request, it is held back and wait is the time
+// after which FlushKeyFrameRequest should be called. Any number of requests held back
+// are sent as one, or not at all if a key frame is forwarded meanwhile.
+func (f *Forwarder) KeyFrameRequest(cause KeyFrameRequestCause) (bool, time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if cause >= 0 && cause < numKeyFrameRequestCauses {
+		f.numKeyFrameRequests[cause]++
+	}
+	if cause == KeyFrameRequestCauseDecodeError {
+		f.checkFastResumeFallbackLocked()
+	}
+
+	now := time.Now()
+	if !f.pendingKeyFrameRequest {
+		if elapsed := now.Sub(f.lastKeyFrameRequestAt); f.keyFrameRequestThrottle == 0 || elapsed >= f.keyFrameRequestThrottle {
+			f.lastKeyFrameRequestAt = now
+			return true, 0
+		}
+	}
+
+	f.numKeyFrameRequestsThrottled++
+	f.pendingKeyFrameRequest = true
+	f.pendingKeyFrameRequestCause = cause
+	return false, f.lastKeyFrameRequestAt.Add(f.keyFrameRequestThrottle).Sub(now)
+}
+
+// FlushKeyFrameRequest returns cause of a held back key frame request and clears it,
+// false if none is pending, either because none was held back or a key frame has cleared it
+func (f *Forwarder) FlushKeyFrameRequest() (KeyFrameRequestCause, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !f.pendingKeyFrameRequest {
+		return KeyFrameRequestCauseLayerSwitch, false
+	}
+
+	f.pendingKeyFrameRequest = false
+	f.lastKeyFrameRequestAt = time.Now()
+	return f.pendingKeyFrameRequestCause, true
+}
+
+// SetRefTSDiscontinuityThreshold enables re-anchoring of timestamp offset calculated at start
+// when reference timestamp on a switch is off from expected by more than threshold,
+// for example when publisher restarts encoder. 0 disables it.
+func (f *Forwarder) SetRefTSDiscontinuityThreshold(threshold time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.refTSDiscontinuityThreshold = threshold
+}
+
+// SetFastResumeWindow enables resuming video after a subscriber mute shorter than window
+// at the layer forwarded before mute, from the next frame boundary instead of a key frame.
+// If the subscriber asks for a key frame soon after, the resume is counted as a fallback. 0 disables it.
+func (f *Forwarder) SetFastResumeWindow(window time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.fastResumeWindow = window
+}
+
+// SetLayerUpHysteresis holds off switching up to a spatial layer till it has been available for hysteresis,
+// so that a flapping publisher layer does not cause repeated switches. Switching down is immediate. 0 disables it.
+func (f *Forwarder) SetLayerUpHysteresis(hysteresis time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.layerUpHysteresis = hysteresis
+}
+
+// SetLayerSelectionLogSampling enables debug logging of every Nth video layer selection decision,
+// for debugging layer switches without logging each packet. 0 disables it.
+func (f *Forwarder) SetLayerSelectionLogSampling(every int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.layerSelectionLogSampling = every
+	f.numLayerSelections = 0
+}
+
+// SetClockRateCorrection enables rewriting of outgoing RTP timestamps when actual clock rate of the stream,
+// estimated from arrival times by the receiver, deviates from codec clock rate by more than threshold (as a fraction of clock rate).
+// Meant for streams bridged from gateways which advertise a wrong clock rate. 0 disables it.
+func (f *Forwarder) SetClockRateCorrection(threshold float64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.clockRateCorrectionThreshold = threshold
+}
+
 func (f *Forwarder) DetermineCodec(codec webrtc.RTPCodecCapability, extensions []webrtc.RTPHeaderExtensionParameter) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
@@ -313,14 +701,17 @@ func (f *Forwarder) DetermineCodec(codec webrtc.RTPCodecCapability, extensions [
 	}
 	f.codec = codec
 
-	ddAvailable := func(exts []webrtc.RTPHeaderExtensionParameter) bool {
+	extAvailable := func(exts []webrtc.RTPHeaderExtensionParameter, uri string) bool {
 		for _, ext := range exts {
-			if ext.URI == dd.ExtensionURI {
+			if ext.URI == uri {
 				return true
 			}
 		}
 		return false
 	}
+	ddAvailable := func(exts []webrtc.RTPHeaderExtensionParameter) bool {
+		return extAvailable(exts, dd.ExtensionURI)
+	}
 
 	switch strings.ToLower(codec.MimeType) {
 	case "video/vp8":
@@ -338,6 +729,13 @@ func (f *Forwarder) DetermineCodec(codec webrtc.RTPCodecCapability, extensions [
 		} else {
 			f.vls = videolayerselector.NewSimulcast(f.logger)
 		}
+		// temporal layers of H.264 can be selected only when marked by the publisher,
+		// or when non-reference frames can be dropped as the stream does not have B-frames
+		if extAvailable(extensions, buffer.FrameMarkingURI) ||
+			(!ddAvailable(extensions) && buffer.IsH264WithoutBFrames(codec.SDPFmtpLine)) {
+			f.temporalMarkingAvailable = true
+			f.vls.SetTemporalLayerSelector(temporallayerselector.NewH264(f.logger))
+		}
 
 	case "video/vp9":
 		// DD-TODO : we only enable dd layer selector for av1/vp9 now, in the future we can enable it for vp8 too
@@ -396,6 +794,37 @@ func (f *Forwarder) GetState() ForwarderState {
 	}
 }
 
+// GetSnapshot returns current forwarding state, it is cheap enough to be polled for debugging
+func (f *Forwarder) GetSnapshot() ForwarderSnapshot {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	_, refTSOffset, _ := f.getSenderReportParamsLocked()
+	currentLayer := f.vls.GetCurrent()
+	targetLayer := f.vls.GetTarget()
+	return ForwarderSnapshot{
+		Started:               f.started,
+		CurrentLayer:          currentLayer,
+		TargetLayer:           targetLayer,
+		MaxLayer:              f.vls.GetMax(),
+		LastAllocation:        f.lastAllocation,
+		RefTSOffset:           refTSOffset,
+		LastSSRC:              f.lastSSRC,
+		IsSwitchPending:       f.kind == webrtc.RTPCodecTypeVideo && targetLayer.IsValid() && currentLayer != targetLayer,
+		Moderated:             f.moderated,
+		LastLayerTransitionAt: f.lastLayerTransitionAt,
+
+		NumKeyFrameRequestsSaved: f.numKeyFrameRequestsSaved,
+		NumFastResumeFallbacks:   f.numFastResumeFallbacks,
+
+		NumKeyFrameRequests:          f.numKeyFrameRequests,
+		NumKeyFrameRequestsThrottled: f.numKeyFrameRequestsThrottled,
+		NumKeyFrameRequestsCoalesced: f.numKeyFrameRequestsCoalesced,
+
+		ForcedTargetLayer: f.forcedTargetLayer,
+	}
+}
+
 func (f *Forwarder) SeedState(state ForwarderState) {
 	if !state.Started {
 		return
@@ -436,7 +865,10 @@ func (f *Forwarder) Mute(muted bool, isSubscribeMutable bool) bool {
 	// The work around here to ignore mute does ignore an intentional mute.
 	// It could result in some bandwidth consumed for stream without visibility in
 	// the case of intentional mute.
-	if muted && !isSubscribeMutable {
+	//
+	// A moderated or start paused track is also reported as paused, but not due to congestion,
+	// so mute is applied to avoid forwarding an invisible stream when the pause is cleared.
+	if muted && !isSubscribeMutable && !f.moderated && !f.startPaused {
 		f.logger.Debugw("ignoring forwarder mute, paused due to congestion")
 		return false
 	}
@@ -446,7 +878,10 @@ func (f *Forwarder) Mute(muted bool, isSubscribeMutable bool) bool {
 
 	// resync when muted so that sequence numbers do not jump on unmute
 	if muted {
+		f.prepareFastResumeLocked()
 		f.resyncLocked()
+	} else {
+		f.maybeFastResumeLocked()
 	}
 
 	return true
@@ -472,6 +907,7 @@ func (f *Forwarder) PubMute(pubMuted bool) bool {
 
 	// resync when pub muted so that sequence numbers do not jump on unmute
 	if pubMuted {
+		f.clearFastResumeLocked()
 		f.resyncLocked()
 	}
 	return true
@@ -484,11 +920,161 @@ func (f *Forwarder) IsPubMuted() bool {
 	return f.pubMuted
 }
 
+// PubSilence applies an implicit publisher mute when the up stream has gone silent
+// without the publisher signalling a mute. It is tracked separately from explicit
+// publisher mute so that one does not undo the other.
+func (f *Forwarder) PubSilence(pubSilent bool) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.pubSilent == pubSilent {
+		return false
+	}
+
+	f.logger.Debugw("setting forwarder pub silence", "silent", pubSilent)
+	f.pubSilent = pubSilent
+
+	// resync when silenced so that sequence numbers do not jump on resume
+	if pubSilent {
+		f.clearFastResumeLocked()
+		f.resyncLocked()
+	}
+	return true
+}
+
+func (f *Forwarder) IsPubSilent() bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.pubSilent
+}
+
+// SetModerated pauses forwarding on behalf of the publisher, for example when a moderator
+// stops a track from reaching subscribers without unpublishing it. Unlike a bandwidth pause,
+// allocation does not resume a moderated track.
+func (f *Forwarder) SetModerated(moderated bool) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.moderated == moderated {
+		return false
+	}
+
+	f.logger.Debugw("setting forwarder moderated", "moderated", moderated)
+	f.moderated = moderated
+
+	// resync on both transitions so that forwarding restarts cleanly at a key frame
+	f.clearFastResumeLocked()
+	f.resyncLocked()
+	return true
+}
+
+func (f *Forwarder) IsModerated() bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.moderated
+}
+
+// SetStartPaused holds a new subscription paused until the subscriber asks for it.
+// Like moderation, allocation does not resume a start paused track.
+func (f *Forwarder) SetStartPaused(startPaused bool) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.startPaused == startPaused {
+		return false
+	}
+
+	f.logger.Debugw("setting forwarder start paused", "startPaused", startPaused)
+	f.startPaused = startPaused
+
+	f.clearFastResumeLocked()
+	f.resyncLocked()
+	return true
+}
+
+func (f *Forwarder) IsStartPaused() bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.startPaused
+}
+
+// remembers the layer being forwarded when subscriber mutes, to resume at it without a key frame
+func (f *Forwarder) prepareFastResumeLocked() {
+	f.clearFastResumeLocked()
+	if f.kind != webrtc.RTPCodecTypeVideo || f.fastResumeWindow == 0 || f.lastKeyFrameAt.IsZero() {
+		return
+	}
+
+	if current := f.vls.GetCurrent(); current.IsValid() {
+		f.mutedAt = time.Now()
+		f.mutedCurrentLayer = current
+		f.mutedTargetLayer = f.vls.GetTarget()
+	}
+}
+
+func (f *Forwarder) clearFastResumeLocked() {
+	f.mutedCurrentLayer = buffer.InvalidLayer
+	f.awaitingFrameBoundary = false
+}
+
+// resumes at the layer forwarded before subscriber mute when unmuted within the window
+// and the target layer has not changed, it waits for the target to be allocated after unmute
+func (f *Forwarder) maybeFastResumeLocked() {
+	if !f.mutedCurrentLayer.IsValid() || f.muted || f.isPubMutedLocked() {
+		return
+	}
+
+	target := f.vls.GetTarget()
+	if !target.IsValid() {
+		return
+	}
+
+	mutedCurrentLayer := f.mutedCurrentLayer
+	f.mutedCurrentLayer = buffer.InvalidLayer
+
+	pausedFor := time.Since(f.mutedAt)
+	if pausedFor > f.fastResumeWindow || target != f.mutedTargetLayer || f.vls.GetCurrent().IsValid() {
+		return
+	}
+
+	f.vls.SetCurrent(mutedCurrentLayer)
+	f.awaitingFrameBoundary = true
+	f.fastResumedAt = time.Now()
+	f.numKeyFrameRequestsSaved++
+	f.logger.Debugw("resuming without key frame", "layer", mutedCurrentLayer, "pausedFor", pausedFor)
+}
+
+// a subscriber waiting for a key frame shortly after resuming without one indicates that
+// its decoder could not continue from its references, the key frame was not saved after all
+func (f *Forwarder) checkFastResumeFallbackLocked() {
+	if f.fastResumedAt.IsZero() {
+		return
+	}
+
+	sinceResume := time.Since(f.fastResumedAt)
+	f.fastResumedAt = time.Time{}
+	if sinceResume > cFastResumeCheckWindow {
+		return
+	}
+
+	f.logger.Infow("falling back to key frame after resume", "sinceResume", sinceResume)
+	f.numFastResumeFallbacks++
+	f.numKeyFrameRequestsSaved--
+	f.clearFastResumeLocked()
+}
+
+func (f *Forwarder) isPubMutedLocked() bool {
+	return f.pubMuted || f.pubSilent || f.moderated || f.startPaused
+}
+
 func (f *Forwarder) IsAnyMuted() bool {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 
-	return f.muted || f.pubMuted
+	return f.muted || f.isPubMutedLocked()
 }
 
 func (f *Forwarder) SetMaxSpatialLayer(spatialLayer int32) (bool, buffer.VideoLayer) {
@@ -499,6 +1085,12 @@ func (f *Forwarder) SetMaxSpatialLayer(spatialLayer int32) (bool, buffer.VideoLa
 		return false, buffer.InvalidLayer
 	}
 
+	if f.forcedTargetLayer.IsValid() {
+		// applied when forced target layer is cleared
+		f.unforcedMaxLayer.Spatial = spatialLayer
+		return false, f.vls.GetMax()
+	}
+
 	existingMax := f.vls.GetMax()
 	if spatialLayer == existingMax.Spatial {
 		return false, existingMax
@@ -517,6 +1109,12 @@ func (f *Forwarder) SetMaxTemporalLayer(temporalLayer int32) (bool, buffer.Video
 		return false, buffer.InvalidLayer
 	}
 
+	if f.forcedTargetLayer.IsValid() {
+		// applied when forced target layer is cleared
+		f.unforcedMaxLayer.Temporal = temporalLayer
+		return false, f.vls.GetMax()
+	}
+
 	existingMax := f.vls.GetMax()
 	if temporalLayer == existingMax.Temporal {
 		return false, existingMax
@@ -527,6 +1125,85 @@ func (f *Forwarder) SetMaxTemporalLayer(temporalLayer int32) (bool, buffer.Video
 	return true, f.vls.GetMax()
 }
 
+// ForceTargetLayer pins target to layer, allocations leave it alone except to pause when the layer is not available.
+// Max layer is raised/lowered to the forced layer so that it is not dialed back. Returns true if forced layer changed.
+func (f *Forwarder) ForceTargetLayer(layer buffer.VideoLayer) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.kind == webrtc.RTPCodecTypeAudio || !layer.IsValid() || layer == f.forcedTargetLayer {
+		return false
+	}
+
+	f.logger.Debugw("forcing target layer", "layer", layer, "previous", f.forcedTargetLayer)
+	if !f.forcedTargetLayer.IsValid() {
+		f.unforcedMaxLayer = f.vls.GetMax()
+	}
+	f.forcedTargetLayer = layer
+	f.vls.SetMax(layer)
+
+	// switch right away, CheckSync reports not locked till the forced layer is reached
+	f.setTargetLayer(layer, layer.Spatial)
+	return true
+}
+
+// ClearForcedTargetLayer returns to automatic allocation with subscribed max layer, returns true if target layer was forced
+func (f *Forwarder) ClearForcedTargetLayer() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !f.forcedTargetLayer.IsValid() {
+		return false
+	}
+
+	f.logger.Debugw("clearing forced target layer", "layer", f.forcedTargetLayer, "maxLayer", f.unforcedMaxLayer)
+	f.forcedTargetLayer = buffer.InvalidLayer
+	f.vls.SetMax(f.unforcedMaxLayer)
+	return true
+}
+
+func (f *Forwarder) ForcedTargetLayer() buffer.VideoLayer {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.forcedTargetLayer
+}
+
+// SetMaxBitrate limits layers to those with a bitrate not exceeding maxBitrate, 0 removes the limit.
+// It is applied on top of max layers, including when overshooting.
+func (f *Forwarder) SetMaxBitrate(maxBitrate int64) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.kind == webrtc.RTPCodecTypeAudio || maxBitrate == f.maxBitrate {
+		return false
+	}
+
+	f.logger.Debugw("setting max bitrate", "maxBitrate", maxBitrate)
+	f.maxBitrate = maxBitrate
+	return true
+}
+
+func (f *Forwarder) MaxBitrate() int64 {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.maxBitrate
+}
+
+// SupportsTemporalLayers returns false when temporal layer of forwarded codec cannot be selected,
+// target temporal layer is then always the base layer irrespective of max temporal layer
+func (f *Forwarder) SupportsTemporalLayers() bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.supportsTemporalLayersLocked()
+}
+
+func (f *Forwarder) supportsTemporalLayersLocked() bool {
+	return CodecSupportsTemporalLayers(f.codec.MimeType) || f.temporalMarkingAvailable
+}
+
 func (f *Forwarder) MaxLayer() buffer.VideoLayer {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
@@ -619,6 +1296,10 @@ func (f *Forwarder) GetSenderReportParams() (int32, uint64, *buffer.RTCPSenderRe
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 
+	return f.getSenderReportParamsLocked()
+}
+
+func (f *Forwarder) getSenderReportParamsLocked() (int32, uint64, *buffer.RTCPSenderReportData) {
 	if f.kind == webrtc.RTPCodecTypeAudio {
 		return 0, f.refInfos[0].tsOffset, f.refInfos[0].senderReport
 	}
@@ -649,6 +1330,17 @@ func (f *Forwarder) IsDeficient() bool {
 	return f.isDeficientLocked()
 }
 
+// GetDeficientDuration returns how long the forwarder has been continuously deficient, zero if not deficient
+func (f *Forwarder) GetDeficientDuration() time.Duration {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if !f.isDeficientLocked() || f.deficientSince.IsZero() {
+		return 0
+	}
+	return time.Since(f.deficientSince)
+}
+
 func (f *Forwarder) PauseReason() VideoPauseReason {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
@@ -656,6 +1348,63 @@ func (f *Forwarder) PauseReason() VideoPauseReason {
 	return f.lastAllocation.PauseReason
 }
 
+// LastAllocation returns the last allocation decision of stream allocator
+func (f *Forwarder) LastAllocation() VideoAllocation {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.lastAllocation
+}
+
+func (f *Forwarder) GetAllocationSnapshot(availableLayers []int32, brs Bitrates) AllocationSnapshot {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return AllocationSnapshot{
+		MimeType:           f.codec.MimeType,
+		AvailableLayers:    slices.Clone(availableLayers),
+		Bitrates:           brs,
+		MaxLayer:           f.vls.GetMax(),
+		MaxSeenLayer:       f.vls.GetMaxSeen(),
+		CurrentLayer:       f.vls.GetCurrent(),
+		TargetLayer:        f.vls.GetTarget(),
+		RequestSpatial:     f.vls.GetRequestSpatial(),
+		Muted:              f.muted,
+		PubMuted:           f.pubMuted,
+		PubSilent:          f.pubSilent,
+		Moderated:          f.moderated,
+		MaxBitrate:         f.maxBitrate,
+		BandwidthRequested: f.lastAllocation.BandwidthRequested,
+	}
+}
+
+// NewForwarderFromAllocationSnapshot returns a video forwarder restored to the allocation state of the snapshot.
+// It is meant for replaying allocation decisions and cannot forward packets.
+func NewForwarderFromAllocationSnapshot(snapshot AllocationSnapshot, logger logger.Logger) *Forwarder {
+	f := NewForwarder(webrtc.RTPCodecTypeVideo, logger, true, nil)
+
+	var extensions []webrtc.RTPHeaderExtensionParameter
+	switch strings.ToLower(snapshot.MimeType) {
+	case "video/vp9", "video/av1":
+		// assume scalable streams are described by dependency descriptor
+		extensions = append(extensions, webrtc.RTPHeaderExtensionParameter{URI: dd.ExtensionURI})
+	}
+	f.DetermineCodec(webrtc.RTPCodecCapability{MimeType: snapshot.MimeType, ClockRate: 90000}, extensions)
+
+	f.vls.SetMax(snapshot.MaxLayer)
+	f.vls.SetMaxSeen(snapshot.MaxSeenLayer)
+	f.vls.SetCurrent(snapshot.CurrentLayer)
+	f.vls.SetTarget(snapshot.TargetLayer)
+	f.vls.SetRequestSpatial(snapshot.RequestSpatial)
+	f.muted = snapshot.Muted
+	f.pubMuted = snapshot.PubMuted
+	f.pubSilent = snapshot.PubSilent
+	f.moderated = snapshot.Moderated
+	f.maxBitrate = snapshot.MaxBitrate
+	f.lastAllocation.BandwidthRequested = snapshot.BandwidthRequested
+	return f
+}
+
 func (f *Forwarder) BandwidthRequested(brs Bitrates) int64 {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
@@ -669,7 +1418,7 @@ func (f *Forwarder) DistanceToDesired(availableLayers []int32, brs Bitrates) flo
 
 	return getDistanceToDesired(
 		f.muted,
-		f.pubMuted,
+		f.isPubMutedLocked(),
 		f.vls.GetMaxSeen(),
 		availableLayers,
 		brs,
@@ -682,7 +1431,8 @@ func (f *Forwarder) GetOptimalBandwidthNeeded(brs Bitrates) int64 {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 
-	return getOptimalBandwidthNeeded(f.muted, f.pubMuted, f.vls.GetMaxSeen().Spatial, brs, f.vls.GetMax())
+	brs, maxLayer, _ := f.applyMaxBitrateLocked(brs, f.vls.GetMax())
+	return getOptimalBandwidthNeeded(f.muted, f.isPubMutedLocked(), f.vls.GetMaxSeen().Spatial, brs, maxLayer)
 }
 
 func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allowOvershoot bool) VideoAllocation {
@@ -694,9 +1444,18 @@ func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allow
 	}
 
 	maxLayer := f.vls.GetMax()
+	brs, bitrateCappedMaxLayer, isBitrateCapped := f.applyMaxBitrateLocked(brs, maxLayer)
+	if isBitrateCapped {
+		// overshoot picks layers above max layer without checking bitrate
+		allowOvershoot = false
+		if bitrateCappedMaxLayer.IsValid() {
+			maxLayer = bitrateCappedMaxLayer
+		}
+	}
 	maxSeenLayer := f.vls.GetMaxSeen()
 	currentLayer := f.vls.GetCurrent()
 	requestSpatial := f.vls.GetRequestSpatial()
+	f.updateLayerAvailabilityLocked(availableLayers)
 	alloc := VideoAllocation{
 		PauseReason:         VideoPauseReasonNone,
 		Bitrates:            brs,
@@ -704,7 +1463,7 @@ func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allow
 		RequestLayerSpatial: requestSpatial,
 		MaxLayer:            maxLayer,
 	}
-	optimalBandwidthNeeded := getOptimalBandwidthNeeded(f.muted, f.pubMuted, maxSeenLayer.Spatial, brs, maxLayer)
+	optimalBandwidthNeeded := getOptimalBandwidthNeeded(f.muted, f.isPubMutedLocked(), maxSeenLayer.Spatial, brs, maxLayer)
 	if optimalBandwidthNeeded == 0 {
 		alloc.PauseReason = VideoPauseReasonFeedDry
 	}
@@ -732,6 +1491,12 @@ func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allow
 	}
 
 	switch {
+	case f.moderated:
+		alloc.PauseReason = VideoPauseReasonModerated
+
+	case f.startPaused:
+		alloc.PauseReason = VideoPauseReasonStartPaused
+
 	case !maxLayer.IsValid() || maxSeenLayer.Spatial == buffer.InvalidLayerSpatial:
 		// nothing to do when max layers are not valid OR max published layer is invalid
 
@@ -741,6 +1506,15 @@ func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allow
 	case f.pubMuted:
 		alloc.PauseReason = VideoPauseReasonPubMuted
 
+	case f.pubSilent:
+		alloc.PauseReason = VideoPauseReasonPubSilent
+
+	case isBitrateCapped && !bitrateCappedMaxLayer.IsValid():
+		alloc.PauseReason = VideoPauseReasonBitrateCap
+
+	case f.forcedTargetLayer.IsValid():
+		f.allocateForcedLocked(&alloc, availableLayers)
+
 	default:
 		// lots of different events could end up here
 		//   1. Publisher side layer resuming/stopping
@@ -758,6 +1532,9 @@ func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allow
 		highestAvailableLayer := buffer.InvalidLayerSpatial
 		requestLayerSpatial := buffer.InvalidLayerSpatial
 		for _, al := range availableLayers {
+			if !f.isLayerUpAllowedLocked(al, currentLayer.Spatial) {
+				continue
+			}
 			if al > requestLayerSpatial && al <= maxLayerSpatialLimit {
 				requestLayerSpatial = al
 			}
@@ -769,7 +1546,7 @@ func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allow
 			requestLayerSpatial = highestAvailableLayer
 		}
 
-		if currentLayer.IsValid() {
+		if currentLayer.IsValid() && (!isBitrateCapped || currentLayer.Spatial <= maxLayer.Spatial) {
 			if (requestLayerSpatial == requestSpatial && currentLayer.Spatial == requestSpatial) || requestLayerSpatial == buffer.InvalidLayerSpatial {
 				// 1. current is locked to desired, stay there
 				// OR
@@ -810,7 +1587,7 @@ func (f *Forwarder) AllocateOptimal(availableLayers []int32, brs Bitrates, allow
 	alloc.BandwidthDelta = alloc.BandwidthRequested - getBandwidthNeeded(brs, f.vls.GetTarget(), f.lastAllocation.BandwidthRequested)
 	alloc.DistanceToDesired = getDistanceToDesired(
 		f.muted,
-		f.pubMuted,
+		f.isPubMutedLocked(),
 		f.vls.GetMaxSeen(),
 		availableLayers,
 		brs,
@@ -825,14 +1602,23 @@ func (f *Forwarder) ProvisionalAllocatePrepare(availableLayers []int32, bitrates
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
+	bitrates, maxLayer, isBitrateCapped := f.applyMaxBitrateLocked(bitrates, f.vls.GetMax())
+	noLayerUnderMaxBitrate := isBitrateCapped && !maxLayer.IsValid()
+	if noLayerUnderMaxBitrate {
+		maxLayer = f.vls.GetMax()
+	}
 	f.provisional = &VideoAllocationProvisional{
-		allocatedLayer: buffer.InvalidLayer,
-		muted:          f.muted,
-		pubMuted:       f.pubMuted,
-		maxSeenLayer:   f.vls.GetMaxSeen(),
-		bitrates:       bitrates,
-		maxLayer:       f.vls.GetMax(),
-		currentLayer:   f.vls.GetCurrent(),
+		allocatedLayer:         buffer.InvalidLayer,
+		muted:                  f.muted,
+		pubMuted:               f.pubMuted,
+		pubSilent:              f.pubSilent,
+		moderated:              f.moderated,
+		startPaused:            f.startPaused,
+		maxSeenLayer:           f.vls.GetMaxSeen(),
+		bitrates:               bitrates,
+		maxLayer:               maxLayer,
+		currentLayer:           f.vls.GetCurrent(),
+		noLayerUnderMaxBitrate: noLayerUnderMaxBitrate,
 	}
 
 	f.provisional.availableLayers = make([]int32, len(availableLayers))
@@ -852,6 +1638,9 @@ func (f *Forwarder) ProvisionalAllocate(availableChannelCapacity int64, layer bu
 
 	if f.provisional.muted ||
 		f.provisional.pubMuted ||
+		f.provisional.pubSilent ||
+		f.provisional.moderated ||
+		f.provisional.startPaused ||
 		f.provisional.maxSeenLayer.Spatial == buffer.InvalidLayerSpatial ||
 		!f.provisional.maxLayer.IsValid() ||
 		((!allowOvershoot || !f.vls.IsOvershootOkay()) && layer.GreaterThan(f.provisional.maxLayer)) {
@@ -914,7 +1703,7 @@ func (f *Forwarder) ProvisionalAllocateGetCooperativeTransition(allowOvershoot b
 	defer f.lock.Unlock()
 
 	existingTargetLayer := f.vls.GetTarget()
-	if f.provisional.muted || f.provisional.pubMuted {
+	if f.provisional.muted || f.provisional.pubMuted || f.provisional.pubSilent || f.provisional.moderated || f.provisional.startPaused {
 		f.provisional.allocatedLayer = buffer.InvalidLayer
 		return VideoTransition{
 			From:           existingTargetLayer,
@@ -1040,12 +1829,13 @@ func (f *Forwarder) ProvisionalAllocateGetBestWeightedTransition() (VideoTransit
 	//      Cost has two components
 	//        a. Transition cost: Spatial layer switch is expensive due to key frame requirement, but temporal layer switch is free.
 	//        b. Quality cost: The farther away from desired layers, the higher the quality cost.
+	//      With temporal preference, spatial layer is not lowered till temporal layers of target spatial layer are exhausted.
 	//
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
 	targetLayer := f.vls.GetTarget()
-	if f.provisional.muted || f.provisional.pubMuted {
+	if f.provisional.muted || f.provisional.pubMuted || f.provisional.pubSilent || f.provisional.moderated || f.provisional.startPaused {
 		f.provisional.allocatedLayer = buffer.InvalidLayer
 		return VideoTransition{
 			From:           targetLayer,
@@ -1085,7 +1875,11 @@ func (f *Forwarder) ProvisionalAllocateGetBestWeightedTransition() (VideoTransit
 	bestLayer := buffer.InvalidLayer
 	bestBandwidthDelta := int64(0)
 	bestValue := float32(0)
-	for s := int32(0); s <= targetLayer.Spatial; s++ {
+	minSpatial := int32(0)
+	if f.allocationPreference == AllocationPreferenceTemporal && targetLayer.Temporal > 0 {
+		minSpatial = targetLayer.Spatial
+	}
+	for s := minSpatial; s <= targetLayer.Spatial; s++ {
 		for t := int32(0); t <= targetLayer.Temporal; t++ {
 			if s == targetLayer.Spatial && t == targetLayer.Temporal {
 				break
@@ -1127,7 +1921,7 @@ func (f *Forwarder) ProvisionalAllocateCommit() VideoAllocation {
 
 	optimalBandwidthNeeded := getOptimalBandwidthNeeded(
 		f.provisional.muted,
-		f.provisional.pubMuted,
+		f.provisional.pubMuted || f.provisional.pubSilent || f.provisional.moderated || f.provisional.startPaused,
 		f.provisional.maxSeenLayer.Spatial,
 		f.provisional.bitrates,
 		f.provisional.maxLayer,
@@ -1142,7 +1936,7 @@ func (f *Forwarder) ProvisionalAllocateCommit() VideoAllocation {
 		MaxLayer:            f.provisional.maxLayer,
 		DistanceToDesired: getDistanceToDesired(
 			f.provisional.muted,
-			f.provisional.pubMuted,
+			f.provisional.pubMuted || f.provisional.pubSilent || f.provisional.moderated || f.provisional.startPaused,
 			f.provisional.maxSeenLayer,
 			f.provisional.availableLayers,
 			f.provisional.bitrates,
@@ -1152,12 +1946,31 @@ func (f *Forwarder) ProvisionalAllocateCommit() VideoAllocation {
 	}
 
 	switch {
+	case f.provisional.moderated:
+		alloc.PauseReason = VideoPauseReasonModerated
+
+	case f.provisional.startPaused:
+		alloc.PauseReason = VideoPauseReasonStartPaused
+
 	case f.provisional.muted:
 		alloc.PauseReason = VideoPauseReasonMuted
 
 	case f.provisional.pubMuted:
 		alloc.PauseReason = VideoPauseReasonPubMuted
 
+	case f.provisional.pubSilent:
+		alloc.PauseReason = VideoPauseReasonPubSilent
+
+	case f.provisional.noLayerUnderMaxBitrate:
+		alloc.PauseReason = VideoPauseReasonBitrateCap
+		alloc.TargetLayer = buffer.InvalidLayer
+		alloc.RequestLayerSpatial = buffer.InvalidLayerSpatial
+
+	case f.forcedTargetLayer.IsValid():
+		f.allocateForcedLocked(&alloc, f.provisional.availableLayers)
+		alloc.BandwidthRequested = getBandwidthNeeded(f.provisional.bitrates, alloc.TargetLayer, 0)
+		alloc.BandwidthDelta = alloc.BandwidthRequested - getBandwidthNeeded(f.provisional.bitrates, f.vls.GetTarget(), f.lastAllocation.BandwidthRequested)
+
 	case optimalBandwidthNeeded == 0:
 		if f.provisional.allocatedLayer.IsValid() {
 			// overshoot
@@ -1183,7 +1996,7 @@ func (f *Forwarder) ProvisionalAllocateCommit() VideoAllocation {
 		if f.provisional.allocatedLayer.GreaterThan(f.provisional.maxLayer) ||
 			alloc.BandwidthRequested >= getOptimalBandwidthNeeded(
 				f.provisional.muted,
-				f.provisional.pubMuted,
+				f.provisional.pubMuted || f.provisional.pubSilent || f.provisional.moderated || f.provisional.startPaused,
 				f.provisional.maxSeenLayer.Spatial,
 				f.provisional.bitrates,
 				f.provisional.maxLayer,
@@ -1220,9 +2033,18 @@ func (f *Forwarder) AllocateNextHigher(availableChannelCapacity int64, available
 		return f.lastAllocation, false
 	}
 
-	maxLayer := f.vls.GetMax()
+	// moderated or start paused track stays paused irrespective of available bandwidth, forced layer is not changed
+	if f.moderated || f.startPaused || f.forcedTargetLayer.IsValid() {
+		return f.lastAllocation, false
+	}
+
+	brs, maxLayer, isBitrateCapped := f.applyMaxBitrateLocked(brs, f.vls.GetMax())
+	if isBitrateCapped && !maxLayer.IsValid() {
+		return f.lastAllocation, false
+	}
 	maxSeenLayer := f.vls.GetMaxSeen()
-	optimalBandwidthNeeded := getOptimalBandwidthNeeded(f.muted, f.pubMuted, maxSeenLayer.Spatial, brs, maxLayer)
+	optimalBandwidthNeeded := getOptimalBandwidthNeeded(f.muted, f.isPubMutedLocked(), maxSeenLayer.Spatial, brs, maxLayer)
+	f.updateLayerAvailabilityLocked(availableLayers)
 
 	alreadyAllocated := int64(0)
 	if targetLayer.IsValid() {
@@ -1234,6 +2056,9 @@ func (f *Forwarder) AllocateNextHigher(availableChannelCapacity int64, available
 		minTemporal, maxTemporal int32,
 	) (bool, VideoAllocation, bool) {
 		for s := minSpatial; s <= maxSpatial; s++ {
+			if !f.isLayerUpAllowedLocked(s, targetLayer.Spatial) {
+				continue
+			}
 			for t := minTemporal; t <= maxTemporal; t++ {
 				bandwidthRequested := brs[s][t]
 				if bandwidthRequested == 0 {
@@ -1257,7 +2082,7 @@ func (f *Forwarder) AllocateNextHigher(availableChannelCapacity int64, available
 					MaxLayer:            maxLayer,
 					DistanceToDesired: getDistanceToDesired(
 						f.muted,
-						f.pubMuted,
+						f.isPubMutedLocked(),
 						maxSeenLayer,
 						availableLayers,
 						brs,
@@ -1332,6 +2157,15 @@ func (f *Forwarder) GetNextHigherTransition(brs Bitrates, allowOvershoot bool) (
 		return VideoTransition{}, false
 	}
 
+	if f.moderated || f.startPaused || f.forcedTargetLayer.IsValid() {
+		return VideoTransition{}, false
+	}
+
+	brs, maxLayer, isBitrateCapped := f.applyMaxBitrateLocked(brs, f.vls.GetMax())
+	if isBitrateCapped && !maxLayer.IsValid() {
+		return VideoTransition{}, false
+	}
+
 	alreadyAllocated := int64(0)
 	if targetLayer.IsValid() {
 		alreadyAllocated = brs[targetLayer.Spatial][targetLayer.Temporal]
@@ -1369,7 +2203,6 @@ func (f *Forwarder) GetNextHigherTransition(brs Bitrates, allowOvershoot bool) (
 	isAvailable := false
 
 	// try moving temporal layer up in currently streaming spatial layer
-	maxLayer := f.vls.GetMax()
 	if targetLayer.IsValid() {
 		done, transition, isAvailable = findNextHigher(
 			targetLayer.Spatial, targetLayer.Spatial,
@@ -1406,9 +2239,13 @@ func (f *Forwarder) Pause(availableLayers []int32, brs Bitrates) VideoAllocation
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
-	maxLayer := f.vls.GetMax()
+	brs, maxLayer, isBitrateCapped := f.applyMaxBitrateLocked(brs, f.vls.GetMax())
+	noLayerUnderMaxBitrate := isBitrateCapped && !maxLayer.IsValid()
+	if noLayerUnderMaxBitrate {
+		maxLayer = f.vls.GetMax()
+	}
 	maxSeenLayer := f.vls.GetMaxSeen()
-	optimalBandwidthNeeded := getOptimalBandwidthNeeded(f.muted, f.pubMuted, maxSeenLayer.Spatial, brs, maxLayer)
+	optimalBandwidthNeeded := getOptimalBandwidthNeeded(f.muted, f.isPubMutedLocked(), maxSeenLayer.Spatial, brs, maxLayer)
 	alloc := VideoAllocation{
 		BandwidthRequested:  0,
 		BandwidthDelta:      0 - getBandwidthNeeded(brs, f.vls.GetTarget(), f.lastAllocation.BandwidthRequested),
@@ -1419,7 +2256,7 @@ func (f *Forwarder) Pause(availableLayers []int32, brs Bitrates) VideoAllocation
 		MaxLayer:            maxLayer,
 		DistanceToDesired: getDistanceToDesired(
 			f.muted,
-			f.pubMuted,
+			f.isPubMutedLocked(),
 			maxSeenLayer,
 			availableLayers,
 			brs,
@@ -1429,12 +2266,30 @@ func (f *Forwarder) Pause(availableLayers []int32, brs Bitrates) VideoAllocation
 	}
 
 	switch {
+	case f.moderated:
+		alloc.PauseReason = VideoPauseReasonModerated
+
+	case f.startPaused:
+		alloc.PauseReason = VideoPauseReasonStartPaused
+
 	case f.muted:
 		alloc.PauseReason = VideoPauseReasonMuted
 
 	case f.pubMuted:
 		alloc.PauseReason = VideoPauseReasonPubMuted
 
+	case f.pubSilent:
+		alloc.PauseReason = VideoPauseReasonPubSilent
+
+	case noLayerUnderMaxBitrate:
+		alloc.PauseReason = VideoPauseReasonBitrateCap
+
+	case f.forcedTargetLayer.IsValid():
+		// forced layer is not paused for lack of bandwidth
+		f.allocateForcedLocked(&alloc, availableLayers)
+		alloc.BandwidthRequested = getBandwidthNeeded(brs, alloc.TargetLayer, 0)
+		alloc.BandwidthDelta = alloc.BandwidthRequested - getBandwidthNeeded(brs, f.vls.GetTarget(), f.lastAllocation.BandwidthRequested)
+
 	case optimalBandwidthNeeded == 0:
 		alloc.PauseReason = VideoPauseReasonFeedDry
 
@@ -1447,9 +2302,42 @@ func (f *Forwarder) Pause(availableLayers []int32, brs Bitrates) VideoAllocation
 	return f.updateAllocation(alloc, "pause")
 }
 
+// applyMaxBitrateLocked removes layers with bitrate above max bitrate and limits max layer to the highest
+// remaining layer. Returned max layer is invalid if no layer is under max bitrate. Layers are not limited
+// till bitrates are known.
+func (f *Forwarder) applyMaxBitrateLocked(brs Bitrates, maxLayer buffer.VideoLayer) (Bitrates, buffer.VideoLayer, bool) {
+	if f.maxBitrate <= 0 || !maxLayer.IsValid() {
+		return brs, maxLayer, false
+	}
+
+	isKnown := false
+	for s := range brs {
+		for t := range brs[s] {
+			if brs[s][t] > f.maxBitrate {
+				brs[s][t] = 0
+				isKnown = true
+			} else if brs[s][t] != 0 {
+				isKnown = true
+			}
+		}
+	}
+	if !isKnown {
+		return brs, maxLayer, false
+	}
+
+	for s := maxLayer.Spatial; s >= 0; s-- {
+		for t := maxLayer.Temporal; t >= 0; t-- {
+			if brs[s][t] != 0 {
+				return brs, buffer.VideoLayer{Spatial: s, Temporal: t}, true
+			}
+		}
+	}
+	return brs, buffer.InvalidLayer, true
+}
+
 func (f *Forwarder) updateAllocation(alloc VideoAllocation, reason string) VideoAllocation {
 	// restrict target temporal to 0 if codec does not support temporal layers
-	if alloc.TargetLayer.IsValid() && strings.ToLower(f.codec.MimeType) == "video/h264" {
+	if alloc.TargetLayer.IsValid() && !f.supportsTemporalLayersLocked() {
 		alloc.TargetLayer.Temporal = 0
 	}
 
@@ -1459,16 +2347,37 @@ func (f *Forwarder) updateAllocation(alloc VideoAllocation, reason string) Video
 		alloc.RequestLayerSpatial != f.lastAllocation.RequestLayerSpatial {
 		f.logger.Debugw(fmt.Sprintf("stream allocation: %s", reason), "allocation", &alloc)
 	}
+	if alloc.IsDeficient && !f.lastAllocation.IsDeficient {
+		f.deficientSince = time.Now()
+	} else if !alloc.IsDeficient {
+		f.deficientSince = time.Time{}
+	}
 	f.lastAllocation = alloc
 
 	f.setTargetLayer(f.lastAllocation.TargetLayer, f.lastAllocation.RequestLayerSpatial)
 	if !f.vls.GetTarget().IsValid() {
 		f.resyncLocked()
+	} else {
+		f.maybeFastResumeLocked()
 	}
 
 	return f.lastAllocation
 }
 
+// allocateForcedLocked targets forced layer when its spatial layer is available, pauses otherwise
+func (f *Forwarder) allocateForcedLocked(alloc *VideoAllocation, availableLayers []int32) {
+	if !slices.Contains(availableLayers, f.forcedTargetLayer.Spatial) {
+		alloc.PauseReason = VideoPauseReasonFeedDry
+		alloc.TargetLayer = buffer.InvalidLayer
+		alloc.RequestLayerSpatial = buffer.InvalidLayerSpatial
+		return
+	}
+
+	alloc.PauseReason = VideoPauseReasonNone
+	alloc.TargetLayer = f.forcedTargetLayer
+	alloc.RequestLayerSpatial = f.forcedTargetLayer.Spatial
+}
+
 func (f *Forwarder) setTargetLayer(targetLayer buffer.VideoLayer, requestLayerSpatial int32) {
 	f.vls.SetTarget(targetLayer)
 	if targetLayer.IsValid() {
@@ -1488,11 +2397,47 @@ func (f *Forwarder) Resync() {
 func (f *Forwarder) resyncLocked() {
 	f.vls.SetCurrent(buffer.InvalidLayer)
 	f.lastSSRC = 0
-	if f.pubMuted {
+	f.layerAvailableSince = [buffer.DefaultMaxLayerSpatial + 1]time.Time{}
+	if f.isPubMutedLocked() {
 		f.resumeBehindThreshold = ResumeBehindThresholdSeconds
 	}
 }
 
+// updateLayerAvailabilityLocked tracks since when each spatial layer has been available,
+// a layer which is not available anymore has to become stable again
+func (f *Forwarder) updateLayerAvailabilityLocked(availableLayers []int32) {
+	var isAvailable [buffer.DefaultMaxLayerSpatial + 1]bool
+	for _, al := range availableLayers {
+		if al >= 0 && al <= buffer.DefaultMaxLayerSpatial {
+			isAvailable[al] = true
+		}
+	}
+
+	now := time.Now()
+	for layer := range f.layerAvailableSince {
+		switch {
+		case !isAvailable[layer]:
+			f.layerAvailableSince[layer] = time.Time{}
+		case f.layerAvailableSince[layer].IsZero():
+			f.layerAvailableSince[layer] = now
+		}
+	}
+}
+
+// isLayerUpAllowedLocked returns false when switching up from fromSpatial to layer
+// has to wait for layer to be available for long enough
+func (f *Forwarder) isLayerUpAllowedLocked(layer int32, fromSpatial int32) bool {
+	if f.layerUpHysteresis == 0 ||
+		fromSpatial == buffer.InvalidLayerSpatial ||
+		layer <= fromSpatial ||
+		layer > buffer.DefaultMaxLayerSpatial {
+		return true
+	}
+
+	since := f.layerAvailableSince[layer]
+	return !since.IsZero() && time.Since(since) >= f.layerUpHysteresis
+}
+
 func (f *Forwarder) CheckSync() (bool, int32) {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
@@ -1534,7 +2479,7 @@ func (f *Forwarder) GetTranslationParams(extPkt *buffer.ExtPacket, layer int32)
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
-	if f.muted || f.pubMuted {
+	if f.muted || f.isPubMutedLocked() {
 		return TranslationParams{
 			shouldDrop: true,
 		}, nil
@@ -1665,12 +2610,15 @@ func (f *Forwarder) processSourceSwitch(extPkt *buffer.ExtPacket, layer int32) e
 		extRefTS -= (1 << 32)
 	}
 
+	hasExpectedTS := false
 	if f.getExpectedRTPTimestamp != nil {
 		tsExt, err := f.getExpectedRTPTimestamp(switchingAt)
 		if err == nil {
 			extExpectedTS = tsExt
+			hasExpectedTS = true
 		} else {
 			if !f.preStartTime.IsZero() {
+				hasExpectedTS = true
 				timeSinceFirst := time.Since(f.preStartTime)
 				rtpDiff := uint64(timeSinceFirst.Nanoseconds() * int64(f.codec.ClockRate) / 1e9)
 				extExpectedTS = f.extFirstTS + rtpDiff
@@ -1693,6 +2641,26 @@ func (f *Forwarder) processSourceSwitch(extPkt *buffer.ExtPacket, layer int32) e
 		}
 	}
 
+	if hasExpectedTS && f.dummyStartTSOffset != 0 && f.refTSDiscontinuityThreshold > 0 {
+		// dummyStartTSOffset is calculated once, a large jump in incoming timestamps,
+		// like on an encoder restart, makes it stale. Re-anchor to expected timestamp.
+		diffSeconds := float64(int64(extRefTS-extExpectedTS)) / float64(f.codec.ClockRate)
+		if math.Abs(diffSeconds) > f.refTSDiscontinuityThreshold.Seconds() {
+			dummyStartTSOffset := f.dummyStartTSOffset + extExpectedTS - extRefTS
+			f.logger.Warnw(
+				"reference timestamp discontinuity, re-anchoring dummyStartTSOffset", nil,
+				"layer", layer,
+				"extExpectedTS", extExpectedTS,
+				"extRefTS", extRefTS,
+				"diffSeconds", diffSeconds,
+				"dummyStartTSOffset", f.dummyStartTSOffset,
+				"newDummyStartTSOffset", dummyStartTSOffset,
+			)
+			f.dummyStartTSOffset = dummyStartTSOffset
+			extRefTS = extExpectedTS
+		}
+	}
+
 	var extNextTS uint64
 	if f.lastSSRC == 0 {
 		// If resuming (e. g. on unmute), keep next timestamp close to expected timestamp.
@@ -1805,11 +2773,12 @@ func (f *Forwarder) getTranslationParamsCommon(extPkt *buffer.ExtPacket, layer i
 		f.logger.Debugw("switching feed", "from", f.lastSSRC, "to", extPkt.Packet.SSRC)
 		f.lastSSRC = extPkt.Packet.SSRC
 	}
+	f.updateClockRateCorrection(extPkt)
 
 	tpRTP, err := f.rtpMunger.UpdateAndGetSnTs(extPkt, tp.marker)
 	if err != nil {
 		tp.shouldDrop = true
-		if err == ErrPaddingOnlyPacket || err == ErrDuplicatePacket || err == ErrOutOfOrderSequenceNumberCacheMiss {
+		if err == ErrPaddingOnlyPacket || err == ErrDuplicatePacket || err == ErrOutOfOrderSequenceNumberCacheMiss || err == ErrSequenceNumberGapFilled {
 			return nil
 		}
 		return err
@@ -1824,6 +2793,38 @@ func (f *Forwarder) getTranslationParamsCommon(extPkt *buffer.ExtPacket, layer i
 	return nil
 }
 
+// should be called with lock held
+func (f *Forwarder) updateClockRateCorrection(extPkt *buffer.ExtPacket) {
+	if f.clockRateCorrectionThreshold == 0 || f.codec.ClockRate == 0 {
+		return
+	}
+
+	clockRate := extPkt.EstimatedClockRate
+	if clockRate <= 0 {
+		return
+	}
+
+	scale := float64(1)
+	if math.Abs(clockRate/float64(f.codec.ClockRate)-1) > f.clockRateCorrectionThreshold {
+		scale = float64(f.codec.ClockRate) / clockRate
+	}
+	currentScale := f.rtpMunger.GetTimestampScale()
+	if math.Abs(scale-currentScale) < cClockRateCorrectionMinChange {
+		return
+	}
+
+	if scale == 1 || currentScale == 1 {
+		f.logger.Infow(
+			"clock rate correction",
+			"enabled", scale != 1,
+			"clockRate", f.codec.ClockRate,
+			"estimatedClockRate", clockRate,
+			"scale", scale,
+		)
+	}
+	f.rtpMunger.SetTimestampScale(scale, extPkt)
+}
+
 // should be called with lock held
 func (f *Forwarder) getTranslationParamsAudio(extPkt *buffer.ExtPacket, layer int32) (TranslationParams, error) {
 	tp := TranslationParams{}
@@ -1831,6 +2832,13 @@ func (f *Forwarder) getTranslationParamsAudio(extPkt *buffer.ExtPacket, layer in
 		tp.shouldDrop = true
 		return tp, err
 	}
+
+	if !tp.shouldDrop && f.maxAudioGapFill > 0 && strings.EqualFold(f.codec.MimeType, webrtc.MimeTypeOpus) {
+		if tp.rtp.snOrdering == SequenceNumberOrderingGap {
+			f.rtpMunger.AddGapFillCandidates(f.maxAudioGapFill, extPkt.Arrival)
+		}
+		tp.gapFill = f.rtpMunger.UpdateAndGetGapFillSnTs(extPkt.Arrival, cAudioGapFillWait)
+	}
 	return tp, nil
 }
 
@@ -1843,7 +2851,20 @@ func (f *Forwarder) getTranslationParamsVideo(extPkt *buffer.ExtPacket, layer in
 		return tp, nil
 	}
 
+	if f.awaitingFrameBoundary {
+		// resumed without a key frame, continue from the start of a frame
+		if !extPkt.KeyFrame {
+			if extPkt.Packet.Marker && (layer == f.vls.GetCurrent().Spatial || f.refIsSVC) {
+				f.awaitingFrameBoundary = false
+			}
+			tp.shouldDrop = true
+			return tp, nil
+		}
+		f.awaitingFrameBoundary = false
+	}
+
 	result := f.vls.Select(extPkt, layer)
+	f.maybeLogLayerSelection(extPkt, layer, result)
 	if !result.IsSelected {
 		tp.shouldDrop = true
 		if f.started && result.IsRelevant {
@@ -1858,6 +2879,17 @@ func (f *Forwarder) getTranslationParamsVideo(extPkt *buffer.ExtPacket, layer in
 	}
 	tp.isResuming = result.IsResuming
 	tp.isSwitching = result.IsSwitching
+	if result.IsResuming || result.IsSwitching {
+		f.lastLayerTransitionAt = time.Now()
+	}
+	if extPkt.KeyFrame && f.fastResumeWindow != 0 {
+		f.lastKeyFrameAt = time.Now()
+	}
+	if extPkt.KeyFrame && f.pendingKeyFrameRequest {
+		// held back request is satisfied
+		f.pendingKeyFrameRequest = false
+		f.numKeyFrameRequestsCoalesced++
+	}
 	tp.ddBytes = result.DependencyDescriptorExtension
 	tp.marker = result.RTPMarker
 
@@ -1892,9 +2924,53 @@ func (f *Forwarder) getTranslationParamsVideo(extPkt *buffer.ExtPacket, layer in
 	return tp, nil
 }
 
+// should be called with lock held
+func (f *Forwarder) maybeLogLayerSelection(extPkt *buffer.ExtPacket, layer int32, result videolayerselector.VideoLayerSelectorResult) {
+	if f.layerSelectionLogSampling <= 0 {
+		return
+	}
+
+	numLayerSelections := f.numLayerSelections
+	f.numLayerSelections++
+	if numLayerSelections%uint64(f.layerSelectionLogSampling) != 0 {
+		return
+	}
+
+	var reason string
+	switch {
+	case result.IsResuming:
+		reason = "resuming"
+	case result.IsSwitching:
+		reason = "switching"
+	case result.IsSelected:
+		reason = "selected"
+	case result.IsRelevant:
+		reason = "not selected"
+	default:
+		reason = "not relevant"
+	}
+	f.logger.Debugw(
+		"layer selection",
+		"reason", reason,
+		"layer", layer,
+		"extSequenceNumber", extPkt.ExtSequenceNumber,
+		"keyFrame", extPkt.KeyFrame,
+		"current", f.vls.GetCurrent(),
+		"target", f.vls.GetTarget(),
+		"max", f.vls.GetMax(),
+		"numLayerSelections", numLayerSelections+1,
+	)
+}
+
 func (f *Forwarder) translateCodecHeader(extPkt *buffer.ExtPacket, tp *TranslationParams) error {
 	// codec specific forwarding check and any needed packet munging
 	tl := f.vls.SelectTemporal(extPkt)
+	if f.temporalMarkingAvailable && extPkt.Temporal > tl {
+		// frame marked with temporal layer above selected one, update sequence number offset to prevent holes
+		tp.shouldDrop = true
+		f.rtpMunger.PacketDropped(extPkt)
+		return nil
+	}
 	inputSize, codecBytes, err := f.codecMunger.UpdateAndGet(
 		extPkt,
 		tp.rtp.snOrdering == SequenceNumberOrderingOutOfOrder,
@@ -1949,6 +3025,15 @@ func (f *Forwarder) maybeStart() {
 	)
 }
 
+// IsOnFrameBoundary returns true when the last forwarded packet ended a frame,
+// padding sent at that point does not break a frame for the decoder
+func (f *Forwarder) IsOnFrameBoundary() bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.rtpMunger.IsOnFrameBoundary()
+}
+
 func (f *Forwarder) GetSnTsForPadding(num int, forceMarker bool) ([]SnTs, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
@@ -15,14 +15,22 @@
 package sfu
 
 import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
 	"github.com/pion/webrtc/v3"
 	"github.com/stretchr/testify/require"
 
 	"github.com/livekit/protocol/logger"
 
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/codecmunger"
 	"github.com/livekit/livekit-server/pkg/sfu/testutils"
 )
 
@@ -57,6 +65,25 @@ func TestForwarderMute(t *testing.T) {
 	require.False(t, f.IsMuted())
 }
 
+func TestForwarderPubSilence(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	require.False(t, f.IsPubSilent())
+	require.False(t, f.PubSilence(false)) // no change in silence state
+
+	require.True(t, f.PubSilence(true))
+	require.True(t, f.IsPubSilent())
+	require.False(t, f.IsPubMuted())
+	require.True(t, f.IsAnyMuted())
+
+	// explicit publisher mute/unmute should not clear implicit silence
+	require.True(t, f.PubMute(true))
+	require.True(t, f.PubMute(false))
+	require.True(t, f.IsAnyMuted())
+
+	require.True(t, f.PubSilence(false))
+	require.False(t, f.IsAnyMuted())
+}
+
 func TestForwarderLayersAudio(t *testing.T) {
 	f := newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
 
@@ -123,6 +150,286 @@ func TestForwarderLayersVideo(t *testing.T) {
 	require.Equal(t, expectedLayers, f.MaxLayer())
 }
 
+func TestForwarderSupportsTemporalLayers(t *testing.T) {
+	bitrates := Bitrates{
+		{2, 3, 0, 0},
+		{4, 5, 0, 0},
+		{6, 7, 0, 0},
+	}
+	targetLayer := func(f *Forwarder) buffer.VideoLayer {
+		f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+		f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+		f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+		f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+		return f.AllocateOptimal(nil, bitrates, true).TargetLayer
+	}
+
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	require.True(t, f.SupportsTemporalLayers())
+	require.Equal(t, buffer.VideoLayer{Spatial: 2, Temporal: buffer.DefaultMaxLayerTemporal}, targetLayer(f))
+
+	f = newForwarder(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeH264, ClockRate: 90000}, webrtc.RTPCodecTypeVideo)
+	require.False(t, f.SupportsTemporalLayers())
+	require.Equal(t, buffer.VideoLayer{Spatial: 2, Temporal: 0}, targetLayer(f))
+
+	// H.264 with frame marking
+	f = NewForwarder(webrtc.RTPCodecTypeVideo, logger.GetLogger(), true, nil)
+	f.DetermineCodec(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeH264, ClockRate: 90000},
+		[]webrtc.RTPHeaderExtensionParameter{{URI: buffer.FrameMarkingURI, ID: 5}},
+	)
+	require.True(t, f.SupportsTemporalLayers())
+	require.Equal(t, buffer.VideoLayer{Spatial: 2, Temporal: buffer.DefaultMaxLayerTemporal}, targetLayer(f))
+}
+
+func TestForwarderH264TemporalLayerSelection(t *testing.T) {
+	bitrates := Bitrates{
+		{2, 3, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}
+
+	f := NewForwarder(webrtc.RTPCodecTypeVideo, logger.GetLogger(), true, nil)
+	f.DetermineCodec(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeH264, ClockRate: 90000},
+		[]webrtc.RTPHeaderExtensionParameter{{URI: buffer.FrameMarkingURI, ID: 5}},
+	)
+	f.SetMaxSpatialLayer(0)
+	f.SetMaxTemporalLayer(0)
+	f.SetMaxPublishedLayer(0)
+	f.SetMaxTemporalLayerSeen(1)
+	f.AllocateOptimal(nil, bitrates, true)
+
+	sn := uint16(23333)
+	forward := func(fm buffer.FrameMarking, keyFrame bool) bool {
+		extPkt, _ := testutils.GetTestExtPacket(&testutils.TestExtPacketParams{
+			SetMarker:      fm.E,
+			IsKeyFrame:     keyFrame,
+			SequenceNumber: sn,
+			Timestamp:      0xabcdef + uint32(sn)*3000,
+			SSRC:           0x12345678,
+			PayloadSize:    20,
+			VideoLayer:     buffer.VideoLayer{Spatial: 0, Temporal: int32(fm.TID)},
+		})
+		extPkt.Payload = fm
+		sn++
+
+		tp, err := f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+		return !tp.shouldDrop
+	}
+
+	// only base layer is forwarded when target temporal layer is 0
+	require.True(t, forward(buffer.FrameMarking{S: true, E: true, I: true, TID: 0}, true))
+	require.False(t, forward(buffer.FrameMarking{S: true, E: true, D: true, B: true, TID: 1}, false))
+	require.True(t, forward(buffer.FrameMarking{S: true, E: true, TID: 0}, false))
+
+	// switch up happens only on a frame synced to base layer
+	f.SetMaxTemporalLayer(1)
+	require.Equal(t, buffer.VideoLayer{Spatial: 0, Temporal: 1}, f.AllocateOptimal(nil, bitrates, true).TargetLayer)
+	require.False(t, forward(buffer.FrameMarking{S: true, E: true, D: true, TID: 1}, false))
+	require.True(t, forward(buffer.FrameMarking{S: true, E: true, TID: 0}, false))
+	require.True(t, forward(buffer.FrameMarking{S: true, E: true, D: true, B: true, TID: 1}, false))
+	require.True(t, forward(buffer.FrameMarking{S: true, E: true, D: true, TID: 1}, false))
+
+	// switch down at end of frame
+	f.SetMaxTemporalLayer(0)
+	f.AllocateOptimal(nil, bitrates, true)
+	require.True(t, forward(buffer.FrameMarking{S: true, E: true, TID: 0}, false))
+	require.False(t, forward(buffer.FrameMarking{S: true, E: true, D: true, B: true, TID: 1}, false))
+}
+
+// loads packets of testdata/h264_simulcast_l1t3.txt by rid of simulcast layer
+func loadH264SimulcastFixture(t *testing.T) map[string][][]byte {
+	data, err := os.ReadFile("testdata/h264_simulcast_l1t3.txt")
+	require.NoError(t, err)
+
+	packets := make(map[string][][]byte)
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rid, packetHex, found := strings.Cut(line, " ")
+		require.True(t, found)
+		packet, err := hex.DecodeString(packetHex)
+		require.NoError(t, err)
+		packets[rid] = append(packets[rid], packet)
+	}
+	return packets
+}
+
+func TestForwarderH264TemporalLayerFixture(t *testing.T) {
+	fixture := loadH264SimulcastFixture(t)
+	h264Codec := webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{
+			MimeType:    webrtc.MimeTypeH264,
+			ClockRate:   90000,
+			SDPFmtpLine: "level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f",
+		},
+		PayloadType: 102,
+	}
+	// frame marking extension id used in fixture
+	const frameMarkingExtID = 5
+
+	type frame struct {
+		tid       int32
+		keyFrame  bool
+		packets   int
+		forwarded int
+	}
+
+	// max temporal layer from frame on
+	maxTemporalLayers := []struct {
+		fromFrame int
+		layer     int32
+	}{
+		{0, 2},
+		{12, 0},
+		{24, 1},
+		{36, 2},
+	}
+
+	testCases := []struct {
+		name       string
+		extensions []webrtc.RTPHeaderExtensionParameter
+		// highest temporal layer seen by the receiver
+		maxSeen int32
+		// highest temporal layer of fixture which is dropped when forwarding temporal layer 0
+		droppedAtBase int32
+	}{
+		{
+			name:          "frame marking",
+			extensions:    []webrtc.RTPHeaderExtensionParameter{{URI: buffer.FrameMarkingURI, ID: frameMarkingExtID}},
+			maxSeen:       2,
+			droppedAtBase: 1,
+		},
+		{
+			// only non-reference frames, temporal layer 2 of fixture, can be told apart
+			name:          "derived from payload",
+			maxSeen:       1,
+			droppedAtBase: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, rid := range []string{"q", "h", "f"} {
+			t.Run(tc.name+"/"+rid, func(t *testing.T) {
+				packets := fixture[rid]
+				require.NotEmpty(t, packets)
+
+				var first rtp.Packet
+				require.NoError(t, first.Unmarshal(packets[0]))
+
+				buff := buffer.NewBuffer(first.SSRC, 100, 100)
+				buff.OnRtcpFeedback(func(_ []rtcp.Packet) {})
+				buff.Bind(webrtc.RTPParameters{
+					HeaderExtensions: tc.extensions,
+					Codecs:           []webrtc.RTPCodecParameters{h264Codec},
+				}, h264Codec.RTPCodecCapability)
+				defer buff.Close()
+
+				f := NewForwarder(webrtc.RTPCodecTypeVideo, logger.GetLogger(), true, nil)
+				f.DetermineCodec(h264Codec.RTPCodecCapability, tc.extensions)
+				require.True(t, f.SupportsTemporalLayers())
+				f.SetMaxSpatialLayer(0)
+				f.SetMaxPublishedLayer(0)
+				f.SetMaxTemporalLayerSeen(tc.maxSeen)
+				bitrates := Bitrates{
+					{1, 2, 3, 0},
+					{0, 0, 0, 0},
+					{0, 0, 0, 0},
+				}
+
+				var (
+					frames      []*frame
+					lastTS      uint32
+					lastOutSN   uint64
+					readBuf     = make([]byte, 1500)
+					nextTLIndex = 0
+				)
+				for _, packet := range packets {
+					var rtpPacket rtp.Packet
+					require.NoError(t, rtpPacket.Unmarshal(packet))
+
+					// frame marking of fixture is the ground truth of frame structure
+					var fm buffer.FrameMarking
+					require.NoError(t, fm.Unmarshal(rtpPacket.GetExtension(frameMarkingExtID)))
+					if len(frames) == 0 || rtpPacket.Timestamp != lastTS {
+						frames = append(frames, &frame{tid: int32(fm.TID), keyFrame: fm.I})
+						lastTS = rtpPacket.Timestamp
+
+						if nextTLIndex < len(maxTemporalLayers) && maxTemporalLayers[nextTLIndex].fromFrame == len(frames)-1 {
+							f.SetMaxTemporalLayer(maxTemporalLayers[nextTLIndex].layer)
+							f.AllocateOptimal(nil, bitrates, true)
+							nextTLIndex++
+						}
+					}
+					fr := frames[len(frames)-1]
+					fr.packets++
+
+					_, err := buff.Write(packet)
+					require.NoError(t, err)
+					extPkt, err := buff.ReadExtended(readBuf)
+					require.NoError(t, err)
+
+					tp, err := f.GetTranslationParams(extPkt, 0)
+					require.NoError(t, err)
+					if tp.shouldDrop {
+						continue
+					}
+
+					fr.forwarded++
+					if lastOutSN != 0 {
+						// dropped packets do not leave holes
+						require.Equal(t, lastOutSN+1, tp.rtp.extSequenceNumber)
+					}
+					lastOutSN = tp.rtp.extSequenceNumber
+				}
+
+				isForwarded := func(idx int) bool {
+					fr := frames[idx]
+					// frames are forwarded or dropped as a whole
+					require.True(t, fr.forwarded == 0 || fr.forwarded == fr.packets, "partially forwarded frame %d", idx)
+					return fr.forwarded != 0
+				}
+
+				numDropped := 0
+				for idx, fr := range frames {
+					if !isForwarded(idx) {
+						numDropped++
+						continue
+					}
+					if fr.keyFrame {
+						continue
+					}
+
+					// forwarded frame has its reference forwarded
+					ref := idx - 1
+					for ; ref >= 0; ref-- {
+						if (fr.tid == 0 && frames[ref].tid == 0) || frames[ref].tid < fr.tid {
+							break
+						}
+					}
+					require.GreaterOrEqual(t, ref, 0)
+					require.True(t, isForwarded(ref), "frame %d forwarded without reference frame %d", idx, ref)
+				}
+				require.NotZero(t, numDropped)
+
+				// while forwarding base layer, switch down completes at end of first frame
+				for idx := maxTemporalLayers[1].fromFrame + 1; idx < maxTemporalLayers[2].fromFrame; idx++ {
+					require.Equal(t, frames[idx].tid < tc.droppedAtBase, isForwarded(idx), "frame %d", idx)
+				}
+
+				// all layers are forwarded again once switched up
+				for idx := len(frames) - 8; idx < len(frames); idx++ {
+					require.True(t, isForwarded(idx), "frame %d", idx)
+				}
+			})
+		}
+	}
+}
+
 func TestForwarderAllocateOptimal(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 
@@ -391,6 +698,74 @@ func TestForwarderAllocateOptimal(t *testing.T) {
 	require.Equal(t, expectedResult, f.lastAllocation)
 }
 
+func TestForwarderAllocateMaxBitrate(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+
+	require.True(t, f.SetMaxBitrate(7))
+	require.False(t, f.SetMaxBitrate(7))
+	require.Equal(t, int64(7), f.MaxBitrate())
+
+	// optimal should be limited to highest layer under max bitrate
+	require.Equal(t, int64(7), f.GetOptimalBandwidthNeeded(bitrates))
+	result := f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonNone, result.PauseReason)
+	require.Equal(t, buffer.VideoLayer{Spatial: 1, Temporal: 2}, result.TargetLayer)
+	require.Equal(t, int32(1), result.RequestLayerSpatial)
+	require.Equal(t, int64(7), result.BandwidthRequested)
+
+	// overshoot should not go above max bitrate
+	f.SetMaxSpatialLayer(0)
+	f.SetMaxTemporalLayer(0)
+	result = f.AllocateOptimal([]int32{1, 2}, Bitrates{{0, 0, 0, 0}, {5, 6, 7, 8}, {9, 10, 11, 12}}, true)
+	require.Equal(t, VideoPauseReasonBitrateCap, result.PauseReason)
+	require.Equal(t, buffer.InvalidLayer, result.TargetLayer)
+	require.Zero(t, result.BandwidthRequested)
+
+	// provisional allocation should not allocate above max bitrate
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.ProvisionalAllocatePrepare(nil, bitrates)
+	isCandidate, usedBitrate := f.ProvisionalAllocate(bitrates[2][3], buffer.VideoLayer{Spatial: 2, Temporal: 3}, true, false)
+	require.False(t, isCandidate)
+	require.Zero(t, usedBitrate)
+	isCandidate, usedBitrate = f.ProvisionalAllocate(bitrates[2][3], buffer.VideoLayer{Spatial: 1, Temporal: 1}, true, false)
+	require.True(t, isCandidate)
+	require.Equal(t, bitrates[1][1], usedBitrate)
+	result = f.ProvisionalAllocateCommit()
+	require.Equal(t, VideoPauseReasonNone, result.PauseReason)
+	require.Equal(t, buffer.VideoLayer{Spatial: 1, Temporal: 1}, result.TargetLayer)
+	require.Equal(t, bitrates[1][2], result.BandwidthNeeded)
+
+	// no layer under max bitrate should pause
+	require.True(t, f.SetMaxBitrate(1))
+	result = f.AllocateOptimal(nil, Bitrates{{2, 3, 4, 5}, {6, 7, 8, 9}, {10, 11, 12, 13}}, true)
+	require.Equal(t, VideoPauseReasonBitrateCap, result.PauseReason)
+	require.Equal(t, buffer.InvalidLayer, result.TargetLayer)
+	require.Equal(t, buffer.InvalidLayer, f.TargetLayer())
+
+	f.ProvisionalAllocatePrepare(nil, Bitrates{{2, 3, 4, 5}, {6, 7, 8, 9}, {10, 11, 12, 13}})
+	result = f.ProvisionalAllocateCommit()
+	require.Equal(t, VideoPauseReasonBitrateCap, result.PauseReason)
+	require.Equal(t, buffer.InvalidLayer, result.TargetLayer)
+
+	// removing max bitrate should allow all layers
+	require.True(t, f.SetMaxBitrate(0))
+	result = f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonNone, result.PauseReason)
+	require.Equal(t, buffer.DefaultMaxLayer, result.TargetLayer)
+	require.Equal(t, bitrates[2][3], result.BandwidthRequested)
+}
+
 func TestForwarderProvisionalAllocate(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
@@ -611,6 +986,46 @@ func TestForwarderProvisionalAllocate(t *testing.T) {
 	require.Equal(t, buffer.InvalidLayer, f.CurrentLayer())
 }
 
+func TestForwarderDeficientDuration(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	require.Zero(t, f.GetDeficientDuration())
+
+	// allocating below desired layer makes it deficient
+	f.ProvisionalAllocatePrepare(nil, bitrates)
+	f.ProvisionalAllocate(bitrates[1][2], buffer.VideoLayer{Spatial: 1, Temporal: 2}, true, false)
+	require.True(t, f.ProvisionalAllocateCommit().IsDeficient)
+
+	time.Sleep(20 * time.Millisecond)
+	deficientDuration := f.GetDeficientDuration()
+	require.GreaterOrEqual(t, deficientDuration, 20*time.Millisecond)
+
+	// staying deficient at a different layer should not reset the duration
+	f.ProvisionalAllocatePrepare(nil, bitrates)
+	f.ProvisionalAllocate(bitrates[0][0], buffer.VideoLayer{Spatial: 0, Temporal: 0}, true, false)
+	require.True(t, f.ProvisionalAllocateCommit().IsDeficient)
+	require.GreaterOrEqual(t, f.GetDeficientDuration(), deficientDuration)
+
+	// allocating optimal layer ends deficiency
+	require.False(t, f.AllocateOptimal(nil, bitrates, true).IsDeficient)
+	require.Zero(t, f.GetDeficientDuration())
+
+	// becoming deficient again starts a new duration
+	f.ProvisionalAllocatePrepare(nil, bitrates)
+	f.ProvisionalAllocate(bitrates[0][0], buffer.VideoLayer{Spatial: 0, Temporal: 0}, true, false)
+	require.True(t, f.ProvisionalAllocateCommit().IsDeficient)
+	require.Less(t, f.GetDeficientDuration(), deficientDuration)
+}
+
 func TestForwarderProvisionalAllocateMute(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
@@ -886,6 +1301,44 @@ func TestForwarderProvisionalAllocateGetBestWeightedTransition(t *testing.T) {
 	require.Equal(t, bitrates, brs)
 }
 
+func TestForwarderBestWeightedTransitionAllocationPreference(t *testing.T) {
+	availableLayers := []int32{0, 1, 2}
+	// higher temporal layers of top spatial layer save little
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{290, 295, 300, 400},
+	}
+
+	getTransition := func(preference AllocationPreference) VideoTransition {
+		f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+		f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+		f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+		f.SetAllocationPreference(preference)
+
+		f.ProvisionalAllocatePrepare(availableLayers, bitrates)
+		f.vls.SetTarget(buffer.VideoLayer{Spatial: 2, Temporal: 2})
+		f.lastAllocation.BandwidthRequested = bitrates[2][2]
+
+		transition, _, _ := f.ProvisionalAllocateGetBestWeightedTransition()
+		return transition
+	}
+
+	// default weighting gives up spatial layer
+	require.Equal(t, VideoTransition{
+		From:           buffer.VideoLayer{Spatial: 2, Temporal: 2},
+		To:             buffer.VideoLayer{Spatial: 1, Temporal: 2},
+		BandwidthDelta: -293,
+	}, getTransition(AllocationPreferenceSpatial))
+
+	// temporal layers are shed first
+	require.Equal(t, VideoTransition{
+		From:           buffer.VideoLayer{Spatial: 2, Temporal: 2},
+		To:             buffer.VideoLayer{Spatial: 2, Temporal: 0},
+		BandwidthDelta: -10,
+	}, getTransition(AllocationPreferenceTemporal))
+}
+
 func TestForwarderAllocateNextHigher(t *testing.T) {
 	f := newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
 	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
@@ -1112,6 +1565,191 @@ func TestForwarderAllocateNextHigher(t *testing.T) {
 	require.True(t, boosted)
 }
 
+func TestForwarderLayerUpHysteresis(t *testing.T) {
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	allLayers := []int32{0, 1, 2}
+	withoutTop := []int32{0, 1}
+
+	newHysteresisForwarder := func(hysteresis time.Duration) *Forwarder {
+		f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+		f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+		f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+		f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+		f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+		f.SetLayerUpHysteresis(hysteresis)
+		return f
+	}
+	// simulates passage of time for layer availability
+	elapse := func(f *Forwarder, d time.Duration) {
+		for layer, since := range f.layerAvailableSince {
+			if !since.IsZero() {
+				f.layerAvailableSince[layer] = since.Add(-d)
+			}
+		}
+	}
+	// top layer flaps every 2 seconds, forwarder is assumed to latch on to target immediately
+	countTargetChanges := func(f *Forwarder, numFlaps int) int {
+		f.vls.SetCurrent(buffer.VideoLayer{Spatial: 1, Temporal: 3})
+		f.vls.SetRequestSpatial(1)
+		f.AllocateOptimal(withoutTop, bitrates, false)
+
+		numChanges := 0
+		lastTarget := f.TargetLayer()
+		for i := 0; i < numFlaps; i++ {
+			availableLayers := allLayers
+			if i%2 == 1 {
+				availableLayers = withoutTop
+			}
+			elapse(f, 2*time.Second)
+
+			alloc := f.AllocateOptimal(availableLayers, bitrates, false)
+			if alloc.TargetLayer != lastTarget {
+				numChanges++
+				lastTarget = alloc.TargetLayer
+			}
+			f.vls.SetCurrent(alloc.TargetLayer)
+		}
+		return numChanges
+	}
+
+	t.Run("flapping layer", func(t *testing.T) {
+		// without hysteresis, target chases the flapping layer
+		require.Equal(t, 20, countTargetChanges(newHysteresisForwarder(0), 20))
+
+		// flaps are shorter than hysteresis, does not switch up
+		f := newHysteresisForwarder(3 * time.Second)
+		require.Zero(t, countTargetChanges(f, 20))
+		require.Equal(t, int32(1), f.TargetLayer().Spatial)
+	})
+
+	t.Run("stable layer", func(t *testing.T) {
+		f := newHysteresisForwarder(3 * time.Second)
+		f.vls.SetCurrent(buffer.VideoLayer{Spatial: 1, Temporal: 3})
+		f.vls.SetRequestSpatial(1)
+
+		alloc := f.AllocateOptimal(allLayers, bitrates, false)
+		require.Equal(t, int32(1), alloc.TargetLayer.Spatial)
+
+		elapse(f, 3*time.Second)
+		alloc = f.AllocateOptimal(allLayers, bitrates, false)
+		require.Equal(t, buffer.VideoLayer{Spatial: 2, Temporal: 3}, alloc.TargetLayer)
+		f.vls.SetCurrent(alloc.TargetLayer)
+
+		// switching down is immediate
+		alloc = f.AllocateOptimal(withoutTop, bitrates, false)
+		require.Equal(t, buffer.VideoLayer{Spatial: 1, Temporal: 3}, alloc.TargetLayer)
+	})
+
+	t.Run("next higher", func(t *testing.T) {
+		f := newHysteresisForwarder(3 * time.Second)
+		f.vls.SetCurrent(buffer.VideoLayer{Spatial: 1, Temporal: 3})
+		f.vls.SetTarget(buffer.VideoLayer{Spatial: 1, Temporal: 3})
+		f.lastAllocation.IsDeficient = true
+
+		_, boosted := f.AllocateNextHigher(100_000_000, allLayers, bitrates, false)
+		require.False(t, boosted)
+
+		elapse(f, 3*time.Second)
+		alloc, boosted := f.AllocateNextHigher(100_000_000, allLayers, bitrates, false)
+		require.True(t, boosted)
+		require.Equal(t, buffer.VideoLayer{Spatial: 2, Temporal: 0}, alloc.TargetLayer)
+	})
+
+	t.Run("reset on resync and pub mute", func(t *testing.T) {
+		f := newHysteresisForwarder(3 * time.Second)
+		f.AllocateOptimal(allLayers, bitrates, false)
+		require.False(t, f.layerAvailableSince[2].IsZero())
+
+		f.Resync()
+		require.True(t, f.layerAvailableSince[2].IsZero())
+
+		f.AllocateOptimal(allLayers, bitrates, false)
+		require.True(t, f.PubMute(true))
+		require.True(t, f.layerAvailableSince[2].IsZero())
+	})
+}
+
+func TestForwarderKeyFrameRequestThrottle(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+	f.AllocateOptimal([]int32{0}, Bitrates{{1, 2, 3, 4}}, false)
+
+	// not throttled by default
+	for i := 0; i < 3; i++ {
+		send, _ := f.KeyFrameRequest(KeyFrameRequestCauseLayerSwitch)
+		require.True(t, send)
+	}
+
+	throttle := 50 * time.Millisecond
+	f.SetKeyFrameRequestThrottle(throttle)
+	time.Sleep(throttle)
+
+	send, _ := f.KeyFrameRequest(KeyFrameRequestCauseResume)
+	require.True(t, send)
+
+	// requests within throttle are held back and coalesced
+	send, wait := f.KeyFrameRequest(KeyFrameRequestCauseDecodeError)
+	require.False(t, send)
+	require.Greater(t, wait, time.Duration(0))
+	require.LessOrEqual(t, wait, throttle)
+	send, _ = f.KeyFrameRequest(KeyFrameRequestCauseDecodeError)
+	require.False(t, send)
+
+	cause, ok := f.FlushKeyFrameRequest()
+	require.True(t, ok)
+	require.Equal(t, KeyFrameRequestCauseDecodeError, cause)
+	_, ok = f.FlushKeyFrameRequest()
+	require.False(t, ok)
+
+	// a key frame clears request held back
+	send, _ = f.KeyFrameRequest(KeyFrameRequestCauseLayerSwitch)
+	require.False(t, send)
+	extPkt, _ := testutils.GetTestExtPacketVP8(&testutils.TestExtPacketParams{
+		SetMarker:      true,
+		IsKeyFrame:     true,
+		SequenceNumber: 23333,
+		Timestamp:      0xabcdef,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}, &buffer.VP8{
+		FirstByte:  25,
+		I:          true,
+		M:          true,
+		PictureID:  13467,
+		L:          true,
+		TL0PICIDX:  233,
+		T:          true,
+		TID:        0,
+		Y:          true,
+		K:          true,
+		KEYIDX:     23,
+		HeaderSize: 6,
+		IsKeyFrame: true,
+	})
+	tp, err := f.GetTranslationParams(extPkt, 0)
+	require.NoError(t, err)
+	require.False(t, tp.shouldDrop)
+	_, ok = f.FlushKeyFrameRequest()
+	require.False(t, ok)
+
+	snapshot := f.GetSnapshot()
+	require.Equal(t, [numKeyFrameRequestCauses]uint32{4, 2, 1}, snapshot.NumKeyFrameRequests)
+	require.EqualValues(t, 3, snapshot.NumKeyFrameRequestsThrottled)
+	require.EqualValues(t, 1, snapshot.NumKeyFrameRequestsCoalesced)
+
+	// throttle expires
+	time.Sleep(throttle)
+	send, _ = f.KeyFrameRequest(KeyFrameRequestCauseLayerSwitch)
+	require.True(t, send)
+}
+
 func TestForwarderPause(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
@@ -1148,6 +1786,260 @@ func TestForwarderPause(t *testing.T) {
 	require.Equal(t, buffer.InvalidLayer, f.TargetLayer())
 }
 
+func TestForwarderModerated(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+
+	require.True(t, f.SetModerated(true))
+	require.False(t, f.SetModerated(true))
+	require.True(t, f.IsModerated())
+	require.Zero(t, f.GetOptimalBandwidthNeeded(bitrates))
+
+	result := f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonModerated, result.PauseReason)
+	require.Equal(t, buffer.InvalidLayer, result.TargetLayer)
+
+	// bandwidth should not resume a moderated track
+	f.ProvisionalAllocatePrepare(nil, bitrates)
+	isCandidate, usedBitrate := f.ProvisionalAllocate(bitrates[2][3], buffer.DefaultMaxLayer, true, true)
+	require.False(t, isCandidate)
+	require.Zero(t, usedBitrate)
+	result = f.ProvisionalAllocateCommit()
+	require.Equal(t, VideoPauseReasonModerated, result.PauseReason)
+	require.Equal(t, buffer.InvalidLayer, result.TargetLayer)
+
+	_, boosted := f.AllocateNextHigher(100_000_000, nil, bitrates, true)
+	require.False(t, boosted)
+	_, ok := f.GetNextHigherTransition(bitrates, true)
+	require.False(t, ok)
+
+	result = f.Pause(nil, bitrates)
+	require.Equal(t, VideoPauseReasonModerated, result.PauseReason)
+	require.False(t, result.IsDeficient)
+
+	// packets should be dropped
+	tp, err := f.GetTranslationParams(&buffer.ExtPacket{VideoLayer: buffer.VideoLayer{Spatial: 0, Temporal: 0}}, 0)
+	require.NoError(t, err)
+	require.True(t, tp.shouldDrop)
+
+	// moderation should survive mute transitions
+	require.True(t, f.PubMute(true))
+	require.True(t, f.PubMute(false))
+	result = f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonModerated, result.PauseReason)
+	require.True(t, f.GetSnapshot().Moderated)
+
+	// paused by moderation and not congestion, so subscriber mute should apply
+	require.True(t, f.Mute(true, false))
+	require.True(t, f.Mute(false, false))
+
+	// clearing should allocate normally
+	require.True(t, f.SetModerated(false))
+	result = f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonNone, result.PauseReason)
+	require.Equal(t, buffer.DefaultMaxLayer, result.TargetLayer)
+}
+
+func TestForwarderStartPaused(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+
+	require.True(t, f.SetStartPaused(true))
+	require.False(t, f.SetStartPaused(true))
+	require.True(t, f.IsStartPaused())
+	require.Zero(t, f.GetOptimalBandwidthNeeded(bitrates))
+
+	result := f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonStartPaused, result.PauseReason)
+	require.Equal(t, buffer.InvalidLayer, result.TargetLayer)
+
+	// bandwidth should not resume a start paused track
+	f.ProvisionalAllocatePrepare(nil, bitrates)
+	isCandidate, usedBitrate := f.ProvisionalAllocate(bitrates[2][3], buffer.DefaultMaxLayer, true, true)
+	require.False(t, isCandidate)
+	require.Zero(t, usedBitrate)
+	result = f.ProvisionalAllocateCommit()
+	require.Equal(t, VideoPauseReasonStartPaused, result.PauseReason)
+
+	_, boosted := f.AllocateNextHigher(100_000_000, nil, bitrates, true)
+	require.False(t, boosted)
+
+	params := &testutils.TestExtPacketParams{
+		SequenceNumber: 23333,
+		Timestamp:      0xabcdef,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+		SetMarker:      true,
+	}
+	vp8 := &buffer.VP8{
+		FirstByte:  25,
+		I:          true,
+		M:          true,
+		PictureID:  13467,
+		L:          true,
+		TL0PICIDX:  233,
+		T:          true,
+		TID:        0,
+		Y:          true,
+		K:          true,
+		KEYIDX:     23,
+		HeaderSize: 6,
+		IsKeyFrame: true,
+	}
+	keyFrame, _ := testutils.GetTestExtPacketVP8(params, vp8)
+
+	// key frames are not forwarded while paused
+	tp, err := f.GetTranslationParams(keyFrame, 0)
+	require.NoError(t, err)
+	require.True(t, tp.shouldDrop)
+
+	// paused at start and not due to congestion, so subscriber mute should apply
+	require.True(t, f.Mute(true, false))
+	result = f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonStartPaused, result.PauseReason)
+
+	// resuming with the track disabled leaves it muted
+	require.True(t, f.SetStartPaused(false))
+	result = f.AllocateOptimal(nil, bitrates, true)
+	require.Equal(t, VideoPauseReasonMuted, result.PauseReason)
+
+	// resuming enabled forwards from the first key frame after resume,
+	// so resume latency is bounded by the key frame requested on resume
+	require.True(t, f.Mute(false, false))
+	result = f.AllocateOptimal([]int32{0}, bitrates, true)
+	require.Equal(t, VideoPauseReasonNone, result.PauseReason)
+	require.True(t, result.TargetLayer.IsValid())
+
+	tp, err = f.GetTranslationParams(keyFrame, result.TargetLayer.Spatial)
+	require.NoError(t, err)
+	require.False(t, tp.shouldDrop)
+	require.True(t, tp.isSwitching)
+	require.True(t, tp.isResuming)
+
+	fa := newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
+	audioPacket, _ := testutils.GetTestExtPacket(&testutils.TestExtPacketParams{
+		SequenceNumber: 23333,
+		Timestamp:      0xabcdef,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	})
+	require.True(t, fa.SetStartPaused(true))
+	tp, err = fa.GetTranslationParams(audioPacket, 0)
+	require.NoError(t, err)
+	require.True(t, tp.shouldDrop)
+
+	// audio resumes on the next packet
+	require.True(t, fa.SetStartPaused(false))
+	tp, err = fa.GetTranslationParams(audioPacket, 0)
+	require.NoError(t, err)
+	require.False(t, tp.shouldDrop)
+}
+
+func TestForwarderForceTargetLayer(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	availableLayers := []int32{0, 1, 2}
+	forced := buffer.VideoLayer{Spatial: 1, Temporal: 1}
+
+	fa := newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
+	require.False(t, fa.ForceTargetLayer(forced))
+	require.False(t, f.ForceTargetLayer(buffer.InvalidLayer))
+
+	require.True(t, f.ForceTargetLayer(forced))
+	require.False(t, f.ForceTargetLayer(forced))
+	require.Equal(t, forced, f.ForcedTargetLayer())
+	require.Equal(t, forced, f.TargetLayer())
+	require.Equal(t, forced, f.MaxLayer())
+	require.Equal(t, forced, f.GetSnapshot().ForcedTargetLayer)
+
+	// not locked till forced layer is reached, so key frame is requested for it
+	locked, layer := f.CheckSync()
+	require.False(t, locked)
+	require.Equal(t, int32(1), layer)
+
+	// subscribed max layer is held till forced layer is cleared
+	changed, _ := f.SetMaxSpatialLayer(0)
+	require.False(t, changed)
+	require.Equal(t, forced, f.MaxLayer())
+
+	// allocations stay at forced layer
+	result := f.AllocateOptimal(availableLayers, bitrates, true)
+	require.Equal(t, VideoPauseReasonNone, result.PauseReason)
+	require.Equal(t, forced, result.TargetLayer)
+	require.Equal(t, int32(1), result.RequestLayerSpatial)
+	require.Equal(t, bitrates[1][1], result.BandwidthRequested)
+
+	_, boosted := f.AllocateNextHigher(100_000_000, availableLayers, bitrates, true)
+	require.False(t, boosted)
+	_, ok := f.GetNextHigherTransition(bitrates, true)
+	require.False(t, ok)
+
+	f.ProvisionalAllocatePrepare(availableLayers, bitrates)
+	f.ProvisionalAllocate(bitrates[0][0], buffer.VideoLayer{Spatial: 0, Temporal: 0}, true, false)
+	result = f.ProvisionalAllocateCommit()
+	require.Equal(t, forced, result.TargetLayer)
+	require.Equal(t, bitrates[1][1], result.BandwidthRequested)
+	require.False(t, result.IsDeficient)
+
+	result = f.Pause(availableLayers, bitrates)
+	require.Equal(t, VideoPauseReasonNone, result.PauseReason)
+	require.Equal(t, forced, result.TargetLayer)
+	require.False(t, result.IsDeficient)
+
+	// paused when forced layer is not available
+	result = f.AllocateOptimal([]int32{0}, bitrates, true)
+	require.Equal(t, VideoPauseReasonFeedDry, result.PauseReason)
+	require.Equal(t, buffer.InvalidLayer, result.TargetLayer)
+	require.Equal(t, buffer.InvalidLayer, f.TargetLayer())
+
+	result = f.AllocateOptimal(availableLayers, bitrates, true)
+	require.Equal(t, forced, result.TargetLayer)
+
+	// muted is honoured
+	f.Mute(true, true)
+	result = f.AllocateOptimal(availableLayers, bitrates, true)
+	require.Equal(t, VideoPauseReasonMuted, result.PauseReason)
+	f.Mute(false, true)
+
+	// cleared goes back to subscribed max layer
+	require.True(t, f.ClearForcedTargetLayer())
+	require.False(t, f.ClearForcedTargetLayer())
+	require.Equal(t, buffer.InvalidLayer, f.ForcedTargetLayer())
+	require.Equal(t, buffer.VideoLayer{Spatial: 0, Temporal: buffer.DefaultMaxLayerTemporal}, f.MaxLayer())
+
+	f.ProvisionalAllocatePrepare(availableLayers, bitrates)
+	f.ProvisionalAllocate(bitrates[0][0], buffer.VideoLayer{Spatial: 0, Temporal: 0}, true, false)
+	result = f.ProvisionalAllocateCommit()
+	require.Equal(t, buffer.VideoLayer{Spatial: 0, Temporal: 0}, result.TargetLayer)
+}
+
 func TestForwarderPauseMute(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
@@ -1182,6 +2074,167 @@ func TestForwarderPauseMute(t *testing.T) {
 	require.Equal(t, buffer.InvalidLayer, f.TargetLayer())
 }
 
+func TestForwarderPausePubSilent(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+	f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+	f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+
+	f.ProvisionalAllocatePrepare(nil, bitrates)
+	f.ProvisionalAllocate(bitrates[2][3], buffer.VideoLayer{Spatial: 0, Temporal: 0}, true, true)
+	// should have set target at (0, 0)
+	f.ProvisionalAllocateCommit()
+
+	f.PubSilence(true)
+	expectedResult := VideoAllocation{
+		PauseReason:         VideoPauseReasonPubSilent,
+		BandwidthRequested:  0,
+		BandwidthDelta:      0 - bitrates[0][0],
+		Bitrates:            bitrates,
+		TargetLayer:         buffer.InvalidLayer,
+		RequestLayerSpatial: buffer.InvalidLayerSpatial,
+		MaxLayer:            buffer.DefaultMaxLayer,
+		DistanceToDesired:   0,
+	}
+	result := f.Pause(nil, bitrates)
+	require.Equal(t, expectedResult, result)
+	require.Equal(t, expectedResult, f.lastAllocation)
+	require.Equal(t, buffer.InvalidLayer, f.TargetLayer())
+
+	// explicit publisher mute takes precedence
+	f.PubMute(true)
+	result = f.Pause(nil, bitrates)
+	require.Equal(t, VideoPauseReasonPubMuted, result.PauseReason)
+}
+
+func TestForwarderFastResume(t *testing.T) {
+	bitrates := Bitrates{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	vp8 := &buffer.VP8{
+		FirstByte:  25,
+		I:          true,
+		M:          true,
+		PictureID:  13467,
+		L:          true,
+		TL0PICIDX:  233,
+		T:          true,
+		TID:        0,
+		Y:          true,
+		K:          true,
+		KEYIDX:     23,
+		HeaderSize: 6,
+		IsKeyFrame: true,
+	}
+	sn := uint16(23333)
+	forward := func(t *testing.T, f *Forwarder, keyFrame bool, marker bool) bool {
+		vp8.IsKeyFrame = keyFrame
+		vp8.PictureID++
+		extPkt, _ := testutils.GetTestExtPacketVP8(&testutils.TestExtPacketParams{
+			SetMarker:      marker,
+			IsKeyFrame:     keyFrame,
+			SequenceNumber: sn,
+			Timestamp:      0xabcdef + uint32(sn)*3000,
+			SSRC:           0x12345678,
+			PayloadSize:    20,
+		}, vp8)
+		sn++
+		tp, err := f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+		return !tp.shouldDrop
+	}
+	setup := func(t *testing.T, window time.Duration) *Forwarder {
+		f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+		f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+		f.SetMaxTemporalLayer(buffer.DefaultMaxLayerTemporal)
+		f.SetMaxPublishedLayer(buffer.DefaultMaxLayerSpatial)
+		f.SetMaxTemporalLayerSeen(buffer.DefaultMaxLayerTemporal)
+		f.SetFastResumeWindow(window)
+
+		f.AllocateOptimal([]int32{0}, bitrates, false)
+		require.True(t, forward(t, f, true, true))
+		locked, _ := f.CheckSync()
+		require.True(t, locked)
+
+		// hidden and shown again, allocation follows mute changes
+		f.Mute(true, true)
+		f.AllocateOptimal([]int32{0}, bitrates, false)
+		require.Equal(t, buffer.InvalidLayer, f.CurrentLayer())
+		return f
+	}
+
+	t.Run("resumes without key frame", func(t *testing.T) {
+		f := setup(t, time.Second)
+		f.Mute(false, true)
+		f.AllocateOptimal([]int32{0}, bitrates, false)
+
+		locked, _ := f.CheckSync()
+		require.True(t, locked)
+		require.Equal(t, uint32(1), f.GetSnapshot().NumKeyFrameRequestsSaved)
+
+		// continues from the start of the next frame
+		require.False(t, forward(t, f, false, false))
+		require.False(t, forward(t, f, false, true))
+		require.True(t, forward(t, f, false, false))
+
+		// not counted as a fallback for other causes of key frame requests
+		f.KeyFrameRequest(KeyFrameRequestCauseLayerSwitch)
+		require.Zero(t, f.GetSnapshot().NumFastResumeFallbacks)
+
+		// subscriber waiting for a key frame falls back
+		f.KeyFrameRequest(KeyFrameRequestCauseDecodeError)
+		snapshot := f.GetSnapshot()
+		require.Equal(t, uint32(1), snapshot.NumFastResumeFallbacks)
+		require.Zero(t, snapshot.NumKeyFrameRequestsSaved)
+
+		// counted once
+		f.KeyFrameRequest(KeyFrameRequestCauseDecodeError)
+		require.Equal(t, uint32(1), f.GetSnapshot().NumFastResumeFallbacks)
+	})
+
+	t.Run("key frame request after check window", func(t *testing.T) {
+		f := setup(t, time.Second)
+		f.Mute(false, true)
+		f.AllocateOptimal([]int32{0}, bitrates, false)
+		f.fastResumedAt = time.Now().Add(-cFastResumeCheckWindow - time.Millisecond)
+
+		f.KeyFrameRequest(KeyFrameRequestCauseDecodeError)
+		snapshot := f.GetSnapshot()
+		require.Zero(t, snapshot.NumFastResumeFallbacks)
+		require.Equal(t, uint32(1), snapshot.NumKeyFrameRequestsSaved)
+	})
+
+	t.Run("key frame needed after window", func(t *testing.T) {
+		f := setup(t, 10*time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		f.Mute(false, true)
+		f.AllocateOptimal([]int32{0}, bitrates, false)
+
+		locked, _ := f.CheckSync()
+		require.False(t, locked)
+		require.Zero(t, f.GetSnapshot().NumKeyFrameRequestsSaved)
+		f.KeyFrameRequest(KeyFrameRequestCauseDecodeError)
+		require.Zero(t, f.GetSnapshot().NumFastResumeFallbacks)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		f := setup(t, 0)
+		f.Mute(false, true)
+		f.AllocateOptimal([]int32{0}, bitrates, false)
+
+		locked, _ := f.CheckSync()
+		require.False(t, locked)
+	})
+}
+
 func TestForwarderGetTranslationParamsMuted(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 	f.Mute(true, true)
@@ -1367,6 +2420,301 @@ func TestForwarderGetTranslationParamsAudio(t *testing.T) {
 	require.Equal(t, f.lastSSRC, params.SSRC)
 }
 
+func TestForwarderAudioGapFill(t *testing.T) {
+	f := newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
+	f.SetMaxAudioGapFill(2)
+	now := time.Now()
+
+	getTranslationParams := func(sn uint16, ts uint32, arrival time.Time) TranslationParams {
+		params := &testutils.TestExtPacketParams{
+			SequenceNumber: sn,
+			Timestamp:      ts,
+			SSRC:           0x12345678,
+			PayloadSize:    20,
+		}
+		extPkt, _ := testutils.GetTestExtPacket(params)
+		extPkt.Arrival = arrival
+		tp, err := f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+		return tp
+	}
+
+	getTranslationParams(23333, 0xabcdef, now)
+
+	// two lost packets are not filled right away
+	expectedTP := TranslationParams{
+		rtp: TranslationParamsRTP{
+			snOrdering:        SequenceNumberOrderingGap,
+			extSequenceNumber: 23336,
+			extTimestamp:      0xabcdef + 3*960,
+		},
+	}
+	require.Equal(t, expectedTP, getTranslationParams(23336, 0xabcdef+3*960, now))
+
+	// late arrival within wait is forwarded
+	actualTP := getTranslationParams(23334, 0xabcdef+960, now.Add(cAudioGapFillWait/2))
+	require.False(t, actualTP.shouldDrop)
+	require.Equal(t, SequenceNumberOrderingOutOfOrder, actualTP.rtp.snOrdering)
+	require.Equal(t, uint64(23334), actualTP.rtp.extSequenceNumber)
+	require.Empty(t, actualTP.gapFill)
+
+	// packet still missing after wait is filled with the next forwarded packet
+	expectedTP = TranslationParams{
+		rtp: TranslationParamsRTP{
+			snOrdering:        SequenceNumberOrderingContiguous,
+			extSequenceNumber: 23337,
+			extTimestamp:      0xabcdef + 4*960,
+		},
+		gapFill: []SnTs{
+			{extSequenceNumber: 23335, extTimestamp: 0xabcdef + 2*960},
+		},
+	}
+	require.Equal(t, expectedTP, getTranslationParams(23337, 0xabcdef+4*960, now.Add(cAudioGapFillWait)))
+
+	// late arrival of a filled packet is dropped
+	actualTP = getTranslationParams(23335, 0xabcdef+2*960, now.Add(cAudioGapFillWait))
+	require.True(t, actualTP.shouldDrop)
+
+	// gap larger than max is not filled
+	actualTP = getTranslationParams(23341, 0xabcdef+8*960, now.Add(cAudioGapFillWait))
+	require.Equal(t, SequenceNumberOrderingGap, actualTP.rtp.snOrdering)
+	actualTP = getTranslationParams(23342, 0xabcdef+9*960, now.Add(2*cAudioGapFillWait))
+	require.Empty(t, actualTP.gapFill)
+}
+
+func TestForwarderClockRateCorrection(t *testing.T) {
+	// sends frames at 30 fps for given duration with timestamps advancing at clockRate,
+	// returns outgoing timestamp of each frame
+	forward := func(f *Forwarder, clockRate uint32, duration time.Duration) []uint64 {
+		f.vls.SetTarget(buffer.VideoLayer{Spatial: 0, Temporal: 0})
+		// clock rate is estimated by the receiver
+		estimator := &clockRateEstimator{}
+
+		start := time.Now()
+		var timestamps []uint64
+		for i := 0; time.Duration(i)*time.Second/30 < duration; i++ {
+			params := &testutils.TestExtPacketParams{
+				SetMarker:      true,
+				IsKeyFrame:     true,
+				SequenceNumber: uint16(23333 + i),
+				Timestamp:      uint32(0xabcdef + i*int(clockRate)/30),
+				SSRC:           0x12345678,
+				PayloadSize:    20,
+				ArrivalTime:    start.Add(time.Duration(i) * time.Second / 30),
+			}
+			vp8 := &buffer.VP8{
+				FirstByte:  25,
+				I:          true,
+				M:          true,
+				PictureID:  uint16(13467 + i),
+				HeaderSize: 6,
+				IsKeyFrame: true,
+			}
+			extPkt, _ := testutils.GetTestExtPacketVP8(params, vp8)
+			extPkt.EstimatedClockRate = estimator.Update(extPkt.Arrival, extPkt.ExtTimestamp)
+			tp, err := f.GetTranslationParams(extPkt, 0)
+			require.NoError(t, err)
+			require.False(t, tp.shouldDrop)
+			timestamps = append(timestamps, tp.rtp.extTimestamp)
+		}
+		return timestamps
+	}
+
+	t.Run("corrects stream with wrong clock rate", func(t *testing.T) {
+		f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+		f.SetClockRateCorrection(0.01)
+
+		// 88.2 kHz stream advertised as 90 kHz
+		timestamps := forward(f, 88200, 10*time.Second)
+		require.InDelta(t, 90000.0/88200.0, f.rtpMunger.GetTimestampScale(), 0.001)
+
+		// frames after correction advance at codec clock rate, timestamps stay monotonic
+		last := len(timestamps) - 1
+		require.InDelta(t, 90000, float64(timestamps[last]-timestamps[last-30]), 90)
+		for i := 1; i < len(timestamps); i++ {
+			require.Greater(t, timestamps[i], timestamps[i-1])
+		}
+	})
+
+	t.Run("does not correct stream within threshold", func(t *testing.T) {
+		f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+		f.SetClockRateCorrection(0.01)
+
+		timestamps := forward(f, 90000, 10*time.Second)
+		require.Equal(t, float64(1), f.rtpMunger.GetTimestampScale())
+		last := len(timestamps) - 1
+		require.Equal(t, uint64(90000), timestamps[last]-timestamps[last-30])
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+
+		timestamps := forward(f, 88200, 10*time.Second)
+		require.Equal(t, float64(1), f.rtpMunger.GetTimestampScale())
+		last := len(timestamps) - 1
+		require.Equal(t, uint64(88200), timestamps[last]-timestamps[last-30])
+	})
+}
+
+type layerSelectionLogger struct {
+	logger.Logger
+	reasons []string
+}
+
+func (l *layerSelectionLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	if msg != "layer selection" {
+		return
+	}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if keysAndValues[i] == "reason" {
+			l.reasons = append(l.reasons, keysAndValues[i+1].(string))
+		}
+	}
+}
+
+func TestForwarderLayerSelectionLogSampling(t *testing.T) {
+	forward := func(f *Forwarder, num int) {
+		f.vls.SetTarget(buffer.VideoLayer{Spatial: 0, Temporal: 0})
+		for i := 0; i < num; i++ {
+			params := &testutils.TestExtPacketParams{
+				SetMarker:      true,
+				SequenceNumber: uint16(23333 + i),
+				Timestamp:      uint32(0xabcdef + i*3000),
+				SSRC:           0x12345678,
+				PayloadSize:    20,
+			}
+			vp8 := &buffer.VP8{
+				FirstByte:  25,
+				I:          true,
+				M:          true,
+				PictureID:  uint16(13467 + i),
+				HeaderSize: 6,
+				// first few packets are dropped while waiting for a key frame
+				IsKeyFrame: i >= 5,
+			}
+			extPkt, _ := testutils.GetTestExtPacketVP8(params, vp8)
+			_, err := f.GetTranslationParams(extPkt, 0)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("logs sampled decisions", func(t *testing.T) {
+		l := &layerSelectionLogger{Logger: logger.GetLogger()}
+		f := NewForwarder(webrtc.RTPCodecTypeVideo, l, true, nil)
+		f.DetermineCodec(testutils.TestVP8Codec, nil)
+		f.SetLayerSelectionLogSampling(5)
+
+		forward(f, 100)
+		require.Len(t, l.reasons, 20)
+		require.Equal(t, "not relevant", l.reasons[0])
+		require.Equal(t, "resuming", l.reasons[1])
+		require.Equal(t, "selected", l.reasons[2])
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		l := &layerSelectionLogger{Logger: logger.GetLogger()}
+		f := NewForwarder(webrtc.RTPCodecTypeVideo, l, true, nil)
+		f.DetermineCodec(testutils.TestVP8Codec, nil)
+
+		forward(f, 100)
+		require.Empty(t, l.reasons)
+	})
+}
+
+func TestForwarderSeedState(t *testing.T) {
+	f := newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
+
+	// not started, nothing to export
+	require.False(t, f.GetState().Started)
+
+	for sn := uint16(23333); sn < 23336; sn++ {
+		params := &testutils.TestExtPacketParams{
+			SequenceNumber: sn,
+			Timestamp:      0xabcdef + uint32(sn-23333)*960,
+			SSRC:           0x12345678,
+			PayloadSize:    20,
+		}
+		extPkt, _ := testutils.GetTestExtPacket(params)
+		_, err := f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+	}
+
+	state := f.GetState()
+	require.True(t, state.Started)
+	require.Equal(t, uint64(23335), state.RTP.ExtLastSN)
+
+	// state survives a round trip through wire format
+	data, err := state.MarshalBinary()
+	require.NoError(t, err)
+	var unmarshalled ForwarderState
+	require.NoError(t, unmarshalled.UnmarshalBinary(data))
+	require.Equal(t, state, unmarshalled)
+
+	// a new forwarder seeded with exported state should continue sequence numbers
+	// even if the incoming stream is different
+	fResumed := newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
+	fResumed.SeedState(unmarshalled)
+
+	params := &testutils.TestExtPacketParams{
+		SequenceNumber: 100,
+		Timestamp:      0x1234,
+		SSRC:           0x87654321,
+		PayloadSize:    20,
+	}
+	extPkt, _ := testutils.GetTestExtPacket(params)
+	actualTP, err := fResumed.GetTranslationParams(extPkt, 0)
+	require.NoError(t, err)
+	require.False(t, actualTP.shouldDrop)
+	require.Equal(t, SequenceNumberOrderingContiguous, actualTP.rtp.snOrdering)
+	require.Equal(t, state.RTP.ExtLastSN+1, actualTP.rtp.extSequenceNumber)
+	require.Greater(t, actualTP.rtp.extTimestamp, state.RTP.ExtLastTS)
+
+	params.SequenceNumber = 101
+	params.Timestamp += 960
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	actualTP, err = fResumed.GetTranslationParams(extPkt, 0)
+	require.NoError(t, err)
+	require.Equal(t, state.RTP.ExtLastSN+2, actualTP.rtp.extSequenceNumber)
+}
+
+func TestForwarderStateMarshalBinary(t *testing.T) {
+	state := ForwarderState{
+		Started:               true,
+		ReferenceLayerSpatial: 2,
+		ExtFirstTS:            0xabcdef,
+		DummyStartTSOffset:    1234,
+		RTP: RTPMungerState{
+			ExtLastSN:       23333,
+			ExtSecondLastSN: 23332,
+			ExtLastTS:       0xabcdef,
+			ExtSecondLastTS: 0xabcdef - 3000,
+			LastMarker:      true,
+		},
+		Codec: codecmunger.VP8State{
+			ExtLastPictureId: 13467,
+			PictureIdUsed:    true,
+			LastTl0PicIdx:    233,
+			Tl0PicIdxUsed:    true,
+			TidUsed:          true,
+			LastKeyIdx:       23,
+			KeyIdxUsed:       true,
+		},
+	}
+
+	data, err := state.MarshalBinary()
+	require.NoError(t, err)
+	var unmarshalled ForwarderState
+	require.NoError(t, unmarshalled.UnmarshalBinary(data))
+	require.Equal(t, state, unmarshalled)
+
+	// codec state is preserved even if all fields are default
+	state.Codec = codecmunger.VP8State{}
+	data, err = state.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, unmarshalled.UnmarshalBinary(data))
+	require.Equal(t, state, unmarshalled)
+}
+
 func TestForwarderGetTranslationParamsVideo(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 
@@ -1784,6 +3132,115 @@ func TestForwarderGetTranslationParamsVideo(t *testing.T) {
 	require.Equal(t, f.lastSSRC, params.SSRC)
 }
 
+func TestForwarderGetSnapshot(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+
+	snapshot := f.GetSnapshot()
+	require.False(t, snapshot.Started)
+	require.False(t, snapshot.IsSwitchPending)
+	require.True(t, snapshot.LastLayerTransitionAt.IsZero())
+
+	f.vls.SetMax(buffer.VideoLayer{Spatial: 2, Temporal: 3})
+	f.vls.SetTarget(buffer.VideoLayer{Spatial: 0, Temporal: 0})
+	f.lastAllocation = VideoAllocation{PauseReason: VideoPauseReasonNone, BandwidthRequested: 100_000}
+
+	// target without forwarding is a pending switch
+	snapshot = f.GetSnapshot()
+	require.True(t, snapshot.IsSwitchPending)
+	require.Equal(t, buffer.VideoLayer{Spatial: 0, Temporal: 0}, snapshot.TargetLayer)
+	require.Equal(t, buffer.VideoLayer{Spatial: 2, Temporal: 3}, snapshot.MaxLayer)
+	require.Equal(t, int64(100_000), snapshot.LastAllocation.BandwidthRequested)
+
+	params := &testutils.TestExtPacketParams{
+		SequenceNumber: 23333,
+		Timestamp:      0xabcdef,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+		SetMarker:      true,
+	}
+	vp8 := &buffer.VP8{
+		FirstByte:  25,
+		I:          true,
+		M:          true,
+		PictureID:  13467,
+		L:          true,
+		TL0PICIDX:  233,
+		T:          true,
+		TID:        0,
+		Y:          true,
+		K:          true,
+		KEYIDX:     23,
+		HeaderSize: 6,
+		IsKeyFrame: true,
+	}
+	extPkt, _ := testutils.GetTestExtPacketVP8(params, vp8)
+	tp, err := f.GetTranslationParams(extPkt, 0)
+	require.NoError(t, err)
+	require.False(t, tp.shouldDrop)
+
+	snapshot = f.GetSnapshot()
+	require.True(t, snapshot.Started)
+	require.Equal(t, uint32(0x12345678), snapshot.LastSSRC)
+	require.Equal(t, buffer.VideoLayer{Spatial: 0, Temporal: 0}, snapshot.CurrentLayer)
+	require.False(t, snapshot.IsSwitchPending)
+	require.False(t, snapshot.LastLayerTransitionAt.IsZero())
+
+	info := snapshot.DebugInfo()
+	require.Equal(t, VideoPauseReasonNone.String(), info["PauseReason"])
+	require.Equal(t, uint32(0x12345678), info["LastSSRC"])
+}
+
+func TestForwarderIsOnFrameBoundary(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.vls.SetTarget(buffer.VideoLayer{
+		Spatial:  0,
+		Temporal: 1,
+	})
+	f.vls.SetCurrent(buffer.InvalidLayer)
+
+	vp8 := &buffer.VP8{
+		FirstByte:  25,
+		I:          true,
+		M:          true,
+		PictureID:  13467,
+		L:          true,
+		TL0PICIDX:  233,
+		T:          true,
+		TID:        0,
+		Y:          true,
+		K:          true,
+		KEYIDX:     23,
+		HeaderSize: 6,
+		IsKeyFrame: true,
+	}
+	forward := func(sn uint16, ts uint32, marker bool) {
+		extPkt, _ := testutils.GetTestExtPacketVP8(&testutils.TestExtPacketParams{
+			SetMarker:      marker,
+			SequenceNumber: sn,
+			Timestamp:      ts,
+			SSRC:           0x12345678,
+			PayloadSize:    20,
+		}, vp8)
+		tp, err := f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+		require.False(t, tp.shouldDrop)
+	}
+
+	// first packet of a frame
+	forward(23333, 0xabcdef, false)
+	require.False(t, f.IsOnFrameBoundary())
+
+	// last packet of the frame
+	forward(23334, 0xabcdef, true)
+	require.True(t, f.IsOnFrameBoundary())
+
+	// next frame starts
+	vp8.IsKeyFrame = false
+	vp8.PictureID++
+	forward(23335, 0xabcdef+3000, false)
+	require.False(t, f.IsOnFrameBoundary())
+}
+
 func TestForwarderGetSnTsForPadding(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 
@@ -2007,3 +3464,69 @@ func TestForwarderGetPaddingVP8(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, marshalledVP8, buf)
 }
+
+func TestForwarderRefTSDiscontinuity(t *testing.T) {
+	clockRate := uint64(testutils.TestVP8Codec.ClockRate)
+	vp8 := &buffer.VP8{
+		FirstByte:  25,
+		S:          true,
+		I:          true,
+		M:          true,
+		PictureID:  13467,
+		L:          true,
+		TL0PICIDX:  233,
+		T:          true,
+		TID:        0,
+		Y:          true,
+		K:          true,
+		KEYIDX:     23,
+		HeaderSize: 6,
+		IsKeyFrame: true,
+	}
+
+	// returns timestamp jump across a publisher timestamp discontinuity of jumpSeconds
+	forwardAcrossDiscontinuity := func(threshold time.Duration, jumpSeconds uint32) uint64 {
+		f := NewForwarder(webrtc.RTPCodecTypeVideo, logger.GetLogger(), false, func(time.Time) (uint64, error) {
+			return 0, errors.New("no sender report")
+		})
+		f.DetermineCodec(testutils.TestVP8Codec, nil)
+		f.SetRefTSDiscontinuityThreshold(threshold)
+
+		// start with dummy forwarding, timestamps are anchored to expected timestamp on first packet
+		f.maybeStart()
+		f.preStartTime = time.Now().Add(-time.Second)
+		f.vls.SetTarget(buffer.VideoLayer{Spatial: 0, Temporal: 1})
+		f.vls.SetCurrent(buffer.InvalidLayer)
+
+		params := &testutils.TestExtPacketParams{
+			SequenceNumber: 23333,
+			Timestamp:      0xabcdef,
+			SSRC:           0x12345678,
+			PayloadSize:    20,
+		}
+		extPkt, _ := testutils.GetTestExtPacketVP8(params, vp8)
+		tp, err := f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+		require.False(t, tp.shouldDrop)
+		firstTS := tp.rtp.extTimestamp
+
+		// publisher timestamps jump, for example after an encoder restart, and forwarding resumes
+		f.Resync()
+		params.SequenceNumber++
+		params.Timestamp += jumpSeconds * uint32(clockRate)
+		extPkt, _ = testutils.GetTestExtPacketVP8(params, vp8)
+		tp, err = f.GetTranslationParams(extPkt, 0)
+		require.NoError(t, err)
+		require.False(t, tp.shouldDrop)
+		return tp.rtp.extTimestamp - firstTS
+	}
+
+	// stale offset carries the jump through
+	require.Greater(t, forwardAcrossDiscontinuity(0, 100), 99*clockRate)
+
+	// re-anchored to expected timestamp
+	require.Less(t, forwardAcrossDiscontinuity(5*time.Second, 100), clockRate)
+
+	// jump within threshold is kept
+	require.Greater(t, forwardAcrossDiscontinuity(5*time.Second, 3), 2*clockRate)
+}
@@ -21,12 +21,22 @@ import (
 
 	"github.com/livekit/protocol/logger"
 	"github.com/pion/rtp"
+	"go.uber.org/atomic"
+)
+
+const (
+	sendRateWindow = time.Second
 )
 
 type Base struct {
 	logger logger.Logger
 
 	packetTime *PacketTime
+
+	// updated on every packet, window start is in unix nanoseconds
+	sendRateWindowStart atomic.Int64
+	sendRateWindowBytes atomic.Int64
+	sendRate            atomic.Int64
 }
 
 func NewBase(logger logger.Logger) *Base {
@@ -42,14 +52,13 @@ func (b *Base) SetInterval(_interval time.Duration) {
 func (b *Base) SetBitrate(_bitrate int) {
 }
 
+func (b *Base) SetBurstLimit(_bytes int) {
+}
+
 func (b *Base) SendPacket(p *Packet) (int, error) {
-	defer func() {
-		if p.Pool != nil && p.PoolEntity != nil {
-			p.Pool.Put(p.PoolEntity)
-		}
-	}()
+	defer p.free()
 
-	_, err := b.writeRTPHeaderExtensions(p)
+	sendingAt, err := b.writeRTPHeaderExtensions(p)
 	if err != nil {
 		b.logger.Errorw("writing rtp header extensions err", err)
 		return 0, err
@@ -64,9 +73,37 @@ func (b *Base) SendPacket(p *Packet) (int, error) {
 		return 0, err
 	}
 
+	b.updateSendRate(sendingAt, written)
 	return written, nil
 }
 
+// GetSendRate returns send rate of the last completed window,
+// a window which is overdue means packets stopped flowing and the rate is 0
+func (b *Base) GetSendRate() int64 {
+	windowStart := b.sendRateWindowStart.Load()
+	if windowStart == 0 || time.Since(time.Unix(0, windowStart)) > 2*sendRateWindow {
+		return 0
+	}
+	return b.sendRate.Load()
+}
+
+func (b *Base) updateSendRate(at time.Time, written int) {
+	now := at.UnixNano()
+	b.sendRateWindowBytes.Add(int64(written))
+
+	windowStart := b.sendRateWindowStart.Load()
+	if windowStart == 0 {
+		b.sendRateWindowStart.CompareAndSwap(0, now)
+		return
+	}
+
+	// only the writer which moves the window computes the rate, bytes racing with it count towards the next window
+	elapsed := time.Duration(now - windowStart)
+	if elapsed >= sendRateWindow && b.sendRateWindowStart.CompareAndSwap(windowStart, now) {
+		b.sendRate.Store(int64(float64(b.sendRateWindowBytes.Swap(0)*8) / elapsed.Seconds()))
+	}
+}
+
 // writes RTP header extensions of track
 func (b *Base) writeRTPHeaderExtensions(p *Packet) (time.Time, error) {
 	// clear out extensions that may have been in the forwarded header
@@ -0,0 +1,245 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pacer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gammazero/deque"
+	"github.com/livekit/protocol/logger"
+	"go.uber.org/atomic"
+)
+
+const (
+	defaultBurstInterval = 5 * time.Millisecond
+
+	// oldest packets are dropped when queue is full, a subscriber this far behind cannot be caught up by pacing
+	maxBurstQueueLength = 1024
+)
+
+// Burst sends packets as they are enqueued till pacing is configured, without taking a lock or running a worker.
+// With pacing, queued packets are sent every interval, at most burst limit bytes per interval.
+// A burst limit of 0 allows sending everything queued in an interval.
+// Pacing can be changed at any time, queued packets are sent in order under the new pacing.
+type Burst struct {
+	*Base
+
+	logger logger.Logger
+
+	// set while pacing or while packets queued under pacing are draining
+	isPacing  atomic.Bool
+	isStopped atomic.Bool
+
+	lock            sync.Mutex
+	packets         deque.Deque[Packet]
+	interval        time.Duration
+	burstLimit      int
+	wake            chan struct{}
+	isWorkerRunning bool
+	numDropped      int
+}
+
+func NewBurst(logger logger.Logger) *Burst {
+	b := &Burst{
+		Base:   NewBase(logger),
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+	b.packets.SetMinCapacity(9)
+	return b
+}
+
+func (b *Burst) SetInterval(interval time.Duration) {
+	b.lock.Lock()
+	b.interval = max(interval, 0)
+	b.maybeStartPacingLocked()
+	b.lock.Unlock()
+
+	b.notify()
+}
+
+func (b *Burst) SetBurstLimit(bytes int) {
+	b.lock.Lock()
+	b.burstLimit = max(bytes, 0)
+	b.maybeStartPacingLocked()
+	b.lock.Unlock()
+
+	b.notify()
+}
+
+func (b *Burst) Stop() {
+	b.lock.Lock()
+	if b.isStopped.Swap(true) {
+		b.lock.Unlock()
+		return
+	}
+
+	close(b.wake)
+	for b.packets.Len() != 0 {
+		p := b.packets.PopFront()
+		p.free()
+	}
+	b.lock.Unlock()
+}
+
+func (b *Burst) Enqueue(p Packet) {
+	if !b.isPacing.Load() && !b.isStopped.Load() {
+		b.Base.SendPacket(&p)
+		return
+	}
+
+	b.lock.Lock()
+	if b.isStopped.Load() {
+		b.lock.Unlock()
+		p.free()
+		return
+	}
+
+	// pacing turned off and queue drained while waiting for lock
+	if !b.isPacing.Load() {
+		b.lock.Unlock()
+		b.Base.SendPacket(&p)
+		return
+	}
+
+	if b.packets.Len() >= maxBurstQueueLength {
+		dropped := b.packets.PopFront()
+		dropped.free()
+
+		b.numDropped++
+		if b.numDropped%maxBurstQueueLength == 1 {
+			b.logger.Infow("pacer queue full, dropping oldest packets", "numDropped", b.numDropped)
+		}
+	}
+	b.packets.PushBack(p)
+	b.lock.Unlock()
+}
+
+func (b *Burst) notify() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.isStopped.Load() {
+		return
+	}
+
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *Burst) maybeStartPacingLocked() {
+	if b.isStopped.Load() || b.pacingIntervalLocked() == 0 {
+		return
+	}
+
+	b.isPacing.Store(true)
+	if !b.isWorkerRunning {
+		b.isWorkerRunning = true
+		go b.sendWorker()
+	}
+}
+
+func (b *Burst) pacingIntervalLocked() time.Duration {
+	if b.interval == 0 && b.burstLimit != 0 {
+		return defaultBurstInterval
+	}
+	return b.interval
+}
+
+func (b *Burst) sendWorker() {
+	timer := time.NewTimer(0)
+	<-timer.C
+
+	overage := 0
+	for {
+		b.lock.Lock()
+		if b.isStopped.Load() {
+			b.lock.Unlock()
+			return
+		}
+		interval := b.pacingIntervalLocked()
+		b.lock.Unlock()
+
+		if interval == 0 {
+			// pacing turned off, flush what was queued while pacing and go back to sending directly
+			overage = 0
+			b.send(0, 0)
+
+			b.lock.Lock()
+			if b.packets.Len() == 0 && b.pacingIntervalLocked() == 0 {
+				b.isPacing.Store(false)
+				b.isWorkerRunning = false
+				b.lock.Unlock()
+				return
+			}
+			b.lock.Unlock()
+			continue
+		}
+
+		timer.Reset(interval)
+		select {
+		case <-timer.C:
+		case _, ok := <-b.wake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			if !ok {
+				return
+			}
+			// re-read pacing
+			continue
+		}
+
+		b.lock.Lock()
+		burstLimit := b.burstLimit
+		b.lock.Unlock()
+
+		overage = b.send(burstLimit, overage)
+	}
+}
+
+// send sends queued packets within burst limit adjusted for overage of previous burst,
+// returns the overage of this burst
+func (b *Burst) send(burstLimit int, overage int) int {
+	toSendBytes := burstLimit - overage
+	if burstLimit != 0 && toSendBytes <= 0 {
+		return -toSendBytes
+	}
+
+	for {
+		b.lock.Lock()
+		if b.isStopped.Load() || b.packets.Len() == 0 {
+			b.lock.Unlock()
+			return 0
+		}
+		p := b.packets.PopFront()
+		b.lock.Unlock()
+
+		written, _ := b.Base.SendPacket(&p)
+		if burstLimit == 0 {
+			continue
+		}
+
+		toSendBytes -= written
+		if toSendBytes <= 0 {
+			return -toSendBytes
+		}
+	}
+}
+
+// ------------------------------------------------
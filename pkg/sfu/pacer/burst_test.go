@@ -0,0 +1,141 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pacer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+)
+
+type testWriteStream struct {
+	lock            sync.Mutex
+	sequenceNumbers []uint16
+}
+
+func (t *testWriteStream) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.sequenceNumbers = append(t.sequenceNumbers, header.SequenceNumber)
+	return header.MarshalSize() + len(payload), nil
+}
+
+func (t *testWriteStream) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (t *testWriteStream) written() []uint16 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]uint16{}, t.sequenceNumbers...)
+}
+
+func testPacket(ws *testWriteStream, sn uint16) Packet {
+	return Packet{
+		Header:      &rtp.Header{Version: 2, SequenceNumber: sn},
+		Payload:     make([]byte, 100),
+		WriteStream: ws,
+	}
+}
+
+func TestBurst(t *testing.T) {
+	t.Run("sends directly without pacing", func(t *testing.T) {
+		b := NewBurst(logger.GetLogger())
+		defer b.Stop()
+
+		ws := &testWriteStream{}
+		b.Enqueue(testPacket(ws, 1))
+		b.Enqueue(testPacket(ws, 2))
+		require.Equal(t, []uint16{1, 2}, ws.written())
+	})
+
+	t.Run("pacing changes keep queued packets", func(t *testing.T) {
+		b := NewBurst(logger.GetLogger())
+		defer b.Stop()
+
+		// one packet per interval
+		b.SetInterval(time.Hour)
+		b.SetBurstLimit(1)
+
+		ws := &testWriteStream{}
+		for sn := uint16(1); sn <= 5; sn++ {
+			b.Enqueue(testPacket(ws, sn))
+		}
+		require.Empty(t, ws.written())
+
+		// faster pacing drains queued packets
+		b.SetInterval(time.Millisecond)
+		require.Eventually(t, func() bool { return len(ws.written()) == 5 }, time.Second, time.Millisecond)
+
+		// back to default pacing, queued packets are flushed before new ones are sent
+		b.SetInterval(time.Hour)
+		b.Enqueue(testPacket(ws, 6))
+		b.Enqueue(testPacket(ws, 7))
+		b.SetInterval(0)
+		b.SetBurstLimit(0)
+		require.Eventually(t, func() bool { return len(ws.written()) == 7 }, time.Second, time.Millisecond)
+
+		b.Enqueue(testPacket(ws, 8))
+		require.Equal(t, []uint16{1, 2, 3, 4, 5, 6, 7, 8}, ws.written())
+	})
+
+	t.Run("drops oldest when queue is full", func(t *testing.T) {
+		b := NewBurst(logger.GetLogger())
+		defer b.Stop()
+
+		b.SetInterval(time.Hour)
+
+		ws := &testWriteStream{}
+		for sn := uint16(1); sn <= maxBurstQueueLength+2; sn++ {
+			b.Enqueue(testPacket(ws, sn))
+		}
+
+		b.SetInterval(0)
+		require.Eventually(t, func() bool { return len(ws.written()) == maxBurstQueueLength }, time.Second, time.Millisecond)
+		require.Equal(t, uint16(3), ws.written()[0])
+	})
+
+	t.Run("drops after stop", func(t *testing.T) {
+		b := NewBurst(logger.GetLogger())
+		b.Stop()
+
+		ws := &testWriteStream{}
+		b.Enqueue(testPacket(ws, 1))
+		require.Empty(t, ws.written())
+	})
+
+	t.Run("stop releases queued packets", func(t *testing.T) {
+		b := NewBurst(logger.GetLogger())
+		b.SetInterval(time.Hour)
+
+		ws := &testWriteStream{}
+		for sn := uint16(1); sn <= 3; sn++ {
+			b.Enqueue(testPacket(ws, sn))
+		}
+		b.Stop()
+
+		b.lock.Lock()
+		require.Zero(t, b.packets.Len())
+		b.lock.Unlock()
+		require.Empty(t, ws.written())
+	})
+}
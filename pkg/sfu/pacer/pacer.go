@@ -38,12 +38,24 @@ type Packet struct {
 	PoolEntity         *[]byte
 }
 
+// free returns buffer of packet to its pool, packet should not be used after
+func (p *Packet) free() {
+	if p.Pool != nil && p.PoolEntity != nil {
+		p.Pool.Put(p.PoolEntity)
+	}
+}
+
 type Pacer interface {
 	Enqueue(p Packet)
 	Stop()
 
 	SetInterval(interval time.Duration)
 	SetBitrate(bitrate int)
+	// maximum bytes sent in an interval, 0 for no limit
+	SetBurstLimit(bytes int)
+
+	// rate at which packets are being sent, in bps
+	GetSendRate() int64
 }
 
 // ------------------------------------------------
@@ -66,12 +66,7 @@ type PlayoutDelayController struct {
 }
 
 func NewPlayoutDelayController(minDelay, maxDelay uint32, logger logger.Logger, rtpStats *buffer.RTPStatsSender) (*PlayoutDelayController, error) {
-	if maxDelay == 0 && minDelay > 0 {
-		maxDelay = pd.MaxPlayoutDelayDefault
-	}
-	if maxDelay > pd.PlayoutDelayMaxValue {
-		maxDelay = pd.PlayoutDelayMaxValue
-	}
+	maxDelay = normalizeMaxDelay(minDelay, maxDelay)
 	c := &PlayoutDelayController{
 		currentDelay: minDelay,
 		minDelay:     minDelay,
@@ -83,6 +78,27 @@ func NewPlayoutDelayController(minDelay, maxDelay uint32, logger logger.Logger,
 	return c, c.createExtData()
 }
 
+// SetDelayLimits changes the range the delay is adapted in, clamping the current delay into it.
+func (c *PlayoutDelayController) SetDelayLimits(minDelay, maxDelay uint32) error {
+	maxDelay = normalizeMaxDelay(minDelay, maxDelay)
+
+	c.lock.Lock()
+	if c.minDelay == minDelay && c.maxDelay == maxDelay {
+		c.lock.Unlock()
+		return nil
+	}
+	c.minDelay = minDelay
+	c.maxDelay = maxDelay
+	if c.currentDelay < minDelay {
+		c.currentDelay = minDelay
+	}
+	if c.currentDelay > maxDelay {
+		c.currentDelay = maxDelay
+	}
+	c.lock.Unlock()
+	return c.createExtData()
+}
+
 func (c *PlayoutDelayController) SetJitter(jitter uint32) {
 	deltaInfo := c.rtpStats.DeltaInfoSender(c.snapshotID)
 	var nackPercent uint32
@@ -166,3 +182,13 @@ func (c *PlayoutDelayController) createExtData() error {
 	}
 	return err
 }
+
+func normalizeMaxDelay(minDelay, maxDelay uint32) uint32 {
+	if maxDelay == 0 && minDelay > 0 {
+		maxDelay = pd.MaxPlayoutDelayDefault
+	}
+	if maxDelay > pd.PlayoutDelayMaxValue {
+		maxDelay = pd.PlayoutDelayMaxValue
+	}
+	return maxDelay
+}
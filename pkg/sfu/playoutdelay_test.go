@@ -71,6 +71,28 @@ func TestPlayoutDelay(t *testing.T) {
 	playoutDelayEqual(t, ext, 120, 120)
 }
 
+func TestPlayoutDelaySetLimits(t *testing.T) {
+	stats := buffer.NewRTPStatsSender(buffer.RTPStatsParams{ClockRate: 900000, Logger: logger.GetLogger()})
+	c, err := NewPlayoutDelayController(100, 120, logger.GetLogger(), stats)
+	require.NoError(t, err)
+
+	c.GetDelayExtension(100)
+	c.OnSeqAcked(100)
+	require.Nil(t, c.GetDelayExtension(101))
+
+	// same limits, nothing new to send
+	require.NoError(t, c.SetDelayLimits(100, 120))
+	require.Nil(t, c.GetDelayExtension(102))
+
+	// current delay clamped into new range and re-sent
+	require.NoError(t, c.SetDelayLimits(200, 400))
+	playoutDelayEqual(t, c.GetDelayExtension(103), 200, 400)
+	c.OnSeqAcked(103)
+
+	require.NoError(t, c.SetDelayLimits(50, 80))
+	playoutDelayEqual(t, c.GetDelayExtension(104), 80, 80)
+}
+
 func playoutDelayEqual(t *testing.T, data []byte, min, max uint16) {
 	var delay pd.PlayOutDelay
 	require.NoError(t, delay.Unmarshal(data))
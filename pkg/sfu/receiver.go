@@ -116,6 +116,7 @@ type WebRTCReceiver struct {
 	rtt      uint32
 
 	lbThreshold int
+	fanOut      *FanOut
 
 	streamTrackerManager *StreamTrackerManager
 
@@ -123,12 +124,24 @@ type WebRTCReceiver struct {
 
 	connectionStats *connectionquality.ConnectionStats
 
-	onStatsUpdate    func(w *WebRTCReceiver, stat *livekit.AnalyticsStat)
-	onMaxLayerChange func(maxLayer int32)
+	onStatsUpdate           func(w *WebRTCReceiver, stat *livekit.AnalyticsStat)
+	onMaxLayerChange        func(maxLayer int32)
+	onAvailableLayersChange func(availableLayers []int32)
+	onKeyFrame              func(layer int32)
 
 	primaryReceiver atomic.Pointer[RedPrimaryReceiver]
 	redReceiver     atomic.Pointer[RedReceiver]
 	redPktWriter    func(pkt *buffer.ExtPacket, spatialLayer int32)
+
+	silenceTimeout     time.Duration
+	isTransportHealthy func() bool
+	lastPacketAt       [buffer.DefaultMaxLayerSpatial + 1]atomic.Int64
+	silenceCheckFrom   atomic.Int64
+	// silenceLock serialises silence changes with down track additions
+	silenceLock sync.Mutex
+	isSilent    atomic.Bool
+
+	clockRateEstimation bool
 }
 
 // SVC-TODO: Have to use more conditions to differentiate between
@@ -149,6 +162,11 @@ func IsRedCodec(mime string) bool {
 	return strings.HasSuffix(strings.ToLower(mime), "red")
 }
 
+// CodecSupportsTemporalLayers returns false for codecs forwarded without temporal layer selection
+func CodecSupportsTemporalLayers(mime string) bool {
+	return strings.ToLower(mime) != "video/h264"
+}
+
 type ReceiverOpts func(w *WebRTCReceiver) *WebRTCReceiver
 
 // WithPliThrottleConfig indicates minimum time(ms) between sending PLIs
@@ -187,6 +205,37 @@ func WithLoadBalanceThreshold(downTracks int) ReceiverOpts {
 	}
 }
 
+// WithFanOut writes packets to down tracks on workers of the given fan out,
+// instead of on the goroutine reading packets from the publisher.
+// Load balance threshold does not apply to packet writes when fan out is used.
+func WithFanOut(fanOut *FanOut) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.fanOut = fanOut
+		return w
+	}
+}
+
+// WithSilenceDetection enables declaring the up track silent when none of the expected layers
+// receive packets for the given timeout while the transport is healthy.
+// Silent up track is implicitly publisher muted on all down tracks till packets resume.
+// Set to 0 (disabled) by default.
+func WithSilenceDetection(timeout time.Duration, isTransportHealthy func() bool) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.silenceTimeout = timeout
+		w.isTransportHealthy = isTransportHealthy
+		return w
+	}
+}
+
+// WithClockRateEstimation estimates actual clock rate of each layer from packet arrival times,
+// down tracks use it to correct timestamps of streams which advertise a wrong clock rate.
+func WithClockRateEstimation(enabled bool) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.clockRateEstimation = enabled
+		return w
+	}
+}
+
 // NewWebRTCReceiver creates a new webrtc track receiver
 func NewWebRTCReceiver(
 	receiver *webrtc.RTPReceiver,
@@ -216,6 +265,7 @@ func NewWebRTCReceiver(
 
 	w.downTrackSpreader = NewDownTrackSpreader(DownTrackSpreaderParams{
 		Threshold: w.lbThreshold,
+		FanOut:    w.fanOut,
 		Logger:    logger,
 	})
 
@@ -244,6 +294,11 @@ func NewWebRTCReceiver(
 		}
 	}
 
+	if w.kind == webrtc.RTPCodecTypeVideo && w.silenceTimeout != 0 {
+		w.silenceCheckFrom.Store(time.Now().UnixNano())
+		go w.silenceDetector()
+	}
+
 	return w
 }
 
@@ -273,10 +328,43 @@ func (w *WebRTCReceiver) getOnMaxLayerChange() func(maxLayer int32) {
 	return w.onMaxLayerChange
 }
 
+// OnAvailableLayersChange sets a callback invoked with live spatial layers when a layer starts or stops
+func (w *WebRTCReceiver) OnAvailableLayersChange(fn func(availableLayers []int32)) {
+	w.bufferMu.Lock()
+	w.onAvailableLayersChange = fn
+	w.bufferMu.Unlock()
+}
+
+func (w *WebRTCReceiver) getOnAvailableLayersChange() func(availableLayers []int32) {
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	return w.onAvailableLayersChange
+}
+
+// OnKeyFrame sets a callback invoked with spatial layer of key frame packets as they are forwarded
+func (w *WebRTCReceiver) OnKeyFrame(fn func(layer int32)) {
+	w.bufferMu.Lock()
+	w.onKeyFrame = fn
+	w.bufferMu.Unlock()
+}
+
+func (w *WebRTCReceiver) getOnKeyFrame() func(layer int32) {
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	return w.onKeyFrame
+}
+
 func (w *WebRTCReceiver) GetConnectionScoreAndQuality() (float32, livekit.ConnectionQuality) {
 	return w.connectionStats.GetScoreAndQuality()
 }
 
+// GetBitrate returns bitrate received over the last connection quality interval, in bps
+func (w *WebRTCReceiver) GetBitrate() int64 {
+	return w.connectionStats.GetBitrate()
+}
+
 func (w *WebRTCReceiver) IsClosed() bool {
 	return w.closed.Load()
 }
@@ -301,6 +389,24 @@ func (w *WebRTCReceiver) SetRTT(rtt uint32) {
 	}
 }
 
+// SetPLIThrottleConfig updates the PLI throttle of existing layers and of layers added later.
+// Buffers read the throttle on every PLI, so the change applies to the next PLI request.
+func (w *WebRTCReceiver) SetPLIThrottleConfig(pliThrottleConfig config.PLIThrottleConfig) {
+	w.bufferMu.Lock()
+	defer w.bufferMu.Unlock()
+
+	w.pliThrottleConfig = pliThrottleConfig
+	for layer, buff := range w.buffers {
+		if buff == nil {
+			continue
+		}
+
+		if duration := PLIThrottleForLayer(pliThrottleConfig, int32(layer)); duration != 0 {
+			buff.SetPLIThrottle(duration.Nanoseconds())
+		}
+	}
+}
+
 func (w *WebRTCReceiver) StreamID() string {
 	return w.streamID
 }
@@ -328,9 +434,10 @@ func (w *WebRTCReceiver) Kind() webrtc.RTPCodecType {
 	return w.kind
 }
 
-func (w *WebRTCReceiver) AddUpTrack(track *webrtc.TrackRemote, buff *buffer.Buffer) error {
+// AddUpTrack adds a layer, or switches an existing layer with the same rid to the SSRC of the track, returning true in that case
+func (w *WebRTCReceiver) AddUpTrack(track *webrtc.TrackRemote, buff *buffer.Buffer) (bool, error) {
 	if w.closed.Load() {
-		return ErrReceiverClosed
+		return false, ErrReceiverClosed
 	}
 
 	layer := int32(0)
@@ -353,25 +460,18 @@ func (w *WebRTCReceiver) AddUpTrack(track *webrtc.TrackRemote, buff *buffer.Buff
 		})
 	})
 
-	var duration time.Duration
-	switch layer {
-	case 2:
-		duration = w.pliThrottleConfig.HighQuality
-	case 1:
-		duration = w.pliThrottleConfig.MidQuality
-	case 0:
-		duration = w.pliThrottleConfig.LowQuality
-	default:
-		duration = w.pliThrottleConfig.MidQuality
-	}
-	if duration != 0 {
-		buff.SetPLIThrottle(duration.Nanoseconds())
-	}
-
 	w.bufferMu.Lock()
+	var reboundBuff *buffer.Buffer
 	if w.upTracks[layer] != nil {
-		w.bufferMu.Unlock()
-		return ErrDuplicateLayer
+		reboundBuff = w.getReboundBufferLocked(layer, track, buff)
+		if reboundBuff == nil {
+			w.bufferMu.Unlock()
+			return false, ErrDuplicateLayer
+		}
+	}
+	// applied under lock so that a concurrent SetPLIThrottleConfig is not missed
+	if duration := PLIThrottleForLayer(w.pliThrottleConfig, layer); duration != 0 {
+		buff.SetPLIThrottle(duration.Nanoseconds())
 	}
 	w.upTracks[layer] = track
 	w.buffers[layer] = buff
@@ -381,12 +481,47 @@ func (w *WebRTCReceiver) AddUpTrack(track *webrtc.TrackRemote, buff *buffer.Buff
 	buff.SetRTT(rtt)
 	buff.SetPaused(w.streamTrackerManager.IsPaused())
 
+	if reboundBuff != nil {
+		w.logger.Infow(
+			"rebinding layer to new SSRC",
+			"layer", layer,
+			"rid", track.RID(),
+			"oldSSRC", reboundBuff.SSRC(),
+			"newSSRC", buff.SSRC(),
+		)
+
+		// stats of the old SSRC should not carry over
+		if tracker := w.streamTrackerManager.GetTracker(layer); tracker != nil {
+			tracker.Reset()
+		}
+
+		// forwarding of the layer continues with the new buffer once the old one is closed
+		reboundBuff.Close()
+		return true, nil
+	}
+
 	if w.Kind() == webrtc.RTPCodecTypeVideo && w.useTrackers {
 		w.streamTrackerManager.AddTracker(layer)
 	}
 
 	go w.forwardRTP(layer)
-	return nil
+	return false, nil
+}
+
+// getReboundBufferLocked returns the buffer of a layer which can be switched to a new SSRC.
+// After an ICE restart, some browsers re-create simulcast encodings with new SSRCs, but the same rids.
+func (w *WebRTCReceiver) getReboundBufferLocked(layer int32, track *webrtc.TrackRemote, buff *buffer.Buffer) *buffer.Buffer {
+	rid := w.upTracks[layer].RID()
+	if rid == "" || rid != track.RID() {
+		return nil
+	}
+
+	oldBuff := w.buffers[layer]
+	if oldBuff == nil || oldBuff.SSRC() == buff.SSRC() {
+		return nil
+	}
+
+	return oldBuff
 }
 
 // SetUpTrackPaused indicates upstream will not be sending any data.
@@ -406,6 +541,11 @@ func (w *WebRTCReceiver) SetUpTrackPaused(paused bool) {
 	w.bufferMu.RUnlock()
 
 	w.connectionStats.UpdateMute(paused)
+
+	if !paused {
+		// give publisher a full timeout to restart after unmute
+		w.silenceCheckFrom.Store(time.Now().UnixNano())
+	}
 }
 
 func (w *WebRTCReceiver) AddDownTrack(track TrackSender) error {
@@ -421,7 +561,13 @@ func (w *WebRTCReceiver) AddDownTrack(track TrackSender) error {
 	track.UpTrackMaxPublishedLayerChange(w.streamTrackerManager.GetMaxPublishedLayer())
 	track.UpTrackMaxTemporalLayerSeenChange(w.streamTrackerManager.GetMaxTemporalLayerSeen())
 
+	w.silenceLock.Lock()
 	w.downTrackSpreader.Store(track)
+	if w.isSilent.Load() {
+		track.UpTrackSilenceChange(true)
+	}
+	w.silenceLock.Unlock()
+
 	w.logger.Debugw("downtrack added", "subscriberID", track.SubscriberID())
 	return nil
 }
@@ -449,7 +595,11 @@ func (w *WebRTCReceiver) notifyMaxExpectedLayer(layer int32) {
 }
 
 func (w *WebRTCReceiver) SetMaxExpectedSpatialLayer(layer int32) {
-	w.streamTrackerManager.SetMaxExpectedSpatialLayer(layer)
+	prev := w.streamTrackerManager.SetMaxExpectedSpatialLayer(layer)
+	if layer > prev {
+		// layer(s) expected to (re)start, give publisher a full timeout to start them
+		w.silenceCheckFrom.Store(time.Now().UnixNano())
+	}
 	w.notifyMaxExpectedLayer(layer)
 
 	if layer == buffer.InvalidLayerSpatial {
@@ -467,6 +617,10 @@ func (w *WebRTCReceiver) OnAvailableLayersChanged() {
 	})
 
 	w.connectionStats.AddLayerTransition(w.streamTrackerManager.DistanceToDesired())
+
+	if onAvailableLayersChange := w.getOnAvailableLayersChange(); onAvailableLayersChange != nil {
+		onAvailableLayersChange(w.streamTrackerManager.GetAvailableLayers())
+	}
 }
 
 // StreamTrackerManagerListener.OnBitrateAvailabilityChanged
@@ -670,6 +824,12 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 	pktBuf := make([]byte, bucket.MaxPktSize)
 	tracker := w.streamTrackerManager.GetTracker(layer)
 
+	// estimated once per layer and shared by all down tracks
+	var estimator *clockRateEstimator
+	if w.clockRateEstimation {
+		estimator = &clockRateEstimator{}
+	}
+
 	defer func() {
 		w.closeOnce.Do(func() {
 			w.closed.Store(true)
@@ -695,9 +855,22 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 		w.bufferMu.RUnlock()
 		pkt, err := buf.ReadExtended(pktBuf)
 		if err == io.EOF {
+			w.bufferMu.RLock()
+			rebound := w.buffers[layer] != buf
+			w.bufferMu.RUnlock()
+			if rebound {
+				if estimator != nil {
+					estimator.Reset()
+				}
+				continue
+			}
 			return
 		}
 
+		if estimator != nil {
+			pkt.EstimatedClockRate = estimator.Update(pkt.Arrival, pkt.ExtTimestamp)
+		}
+
 		spatialTracker := tracker
 		spatialLayer := layer
 		if pkt.Spatial >= 0 {
@@ -709,14 +882,21 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 			}
 		}
 
-		w.downTrackSpreader.Broadcast(func(dt TrackSender) {
-			_ = dt.WriteRTP(pkt, spatialLayer)
-		})
+		// un-silence before forwarding so that this packet is not dropped
+		w.updateLayerActivity(spatialLayer)
+
+		w.downTrackSpreader.WriteRTP(pkt, spatialLayer)
 
 		if redPktWriter != nil {
 			redPktWriter(pkt, spatialLayer)
 		}
 
+		if pkt.KeyFrame {
+			if onKeyFrame := w.getOnKeyFrame(); onKeyFrame != nil {
+				onKeyFrame(spatialLayer)
+			}
+		}
+
 		if spatialTracker != nil {
 			spatialTracker.Observe(
 				pkt.Temporal,
@@ -730,6 +910,106 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 	}
 }
 
+func (w *WebRTCReceiver) updateLayerActivity(spatialLayer int32) {
+	if spatialLayer < 0 || int(spatialLayer) >= len(w.lastPacketAt) {
+		return
+	}
+
+	w.lastPacketAt[spatialLayer].Store(time.Now().UnixNano())
+	if w.silenceTimeout != 0 && w.isSilent.Load() {
+		w.setSilent(false)
+	}
+}
+
+func (w *WebRTCReceiver) silenceDetector() {
+	ticker := time.NewTicker(w.silenceTimeout / 2)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if w.closed.Load() {
+			return
+		}
+
+		w.checkSilence()
+	}
+}
+
+func (w *WebRTCReceiver) checkSilence() {
+	if w.isSilent.Load() {
+		// packets resuming un-silences
+		return
+	}
+
+	if w.streamTrackerManager.IsPaused() {
+		// publisher has signalled mute, nothing is expected
+		return
+	}
+
+	if w.isTransportHealthy != nil && !w.isTransportHealthy() {
+		// lack of packets could be due to transport issues, not a publisher side stop
+		return
+	}
+
+	//
+	// Dynacast could have stopped some or all layers.
+	// Only layers up to max subscribed quality are expected to flow.
+	// If none are expected, silence is a dynacast pause and not a publisher side stop.
+	//
+	maxExpectedLayer := w.streamTrackerManager.GetMaxExpectedSpatialLayer()
+	if w.isSVC && maxExpectedLayer != buffer.InvalidLayerSpatial {
+		// SVC layers arrive in a single stream
+		maxExpectedLayer = buffer.DefaultMaxLayerSpatial
+	}
+	if maxExpectedLayer == buffer.InvalidLayerSpatial {
+		return
+	}
+
+	lastActivity := w.silenceCheckFrom.Load()
+	for layer := int32(0); layer <= maxExpectedLayer && int(layer) < len(w.lastPacketAt); layer++ {
+		if at := w.lastPacketAt[layer].Load(); at > lastActivity {
+			lastActivity = at
+		}
+	}
+
+	if time.Since(time.Unix(0, lastActivity)) < w.silenceTimeout {
+		return
+	}
+
+	w.setSilent(true)
+}
+
+func (w *WebRTCReceiver) setSilent(silent bool) {
+	w.silenceLock.Lock()
+	defer w.silenceLock.Unlock()
+
+	if !w.isSilent.CompareAndSwap(!silent, silent) {
+		return
+	}
+
+	w.logger.Infow("up track silence changed", "silent", silent, "timeout", w.silenceTimeout)
+	w.downTrackSpreader.Broadcast(func(dt TrackSender) {
+		dt.UpTrackSilenceChange(silent)
+	})
+}
+
+func (w *WebRTCReceiver) IsSilent() bool {
+	return w.isSilent.Load()
+}
+
+// LastPacketAt returns time of the last packet received on any layer, zero if none has been received
+func (w *WebRTCReceiver) LastPacketAt() time.Time {
+	var last int64
+	for i := range w.lastPacketAt {
+		if at := w.lastPacketAt[i].Load(); at > last {
+			last = at
+		}
+	}
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
 // closeTracks close all tracks from Receiver
 func (w *WebRTCReceiver) closeTracks() {
 	w.connectionStats.Close()
@@ -750,6 +1030,7 @@ func (w *WebRTCReceiver) DebugInfo() map[string]interface{} {
 	info := map[string]interface{}{
 		"SVC":       w.isSVC,
 		"Simulcast": isSimulcast,
+		"Silent":    w.isSilent.Load(),
 	}
 
 	w.bufferMu.RLock()
@@ -778,6 +1059,7 @@ func (w *WebRTCReceiver) GetPrimaryReceiverForRed() TrackReceiver {
 	if w.primaryReceiver.Load() == nil {
 		pr := NewRedPrimaryReceiver(w, DownTrackSpreaderParams{
 			Threshold: w.lbThreshold,
+			FanOut:    w.fanOut,
 			Logger:    w.logger,
 		})
 		if w.primaryReceiver.CompareAndSwap(nil, pr) {
@@ -797,6 +1079,7 @@ func (w *WebRTCReceiver) GetRedReceiver() TrackReceiver {
 	if w.redReceiver.Load() == nil {
 		pr := NewRedReceiver(w, DownTrackSpreaderParams{
 			Threshold: w.lbThreshold,
+			FanOut:    w.fanOut,
 			Logger:    w.logger,
 		})
 		if w.redReceiver.CompareAndSwap(nil, pr) {
@@ -821,6 +1104,25 @@ func (w *WebRTCReceiver) GetTemporalLayerFpsForSpatial(layer int32) []float32 {
 	return b.GetTemporalLayerFpsForSpatial(layer)
 }
 
+// GetTemporalLayerDistribution returns, indexed by spatial layer, how packets received from
+// publisher were spread across temporal layers over the recent window
+func (w *WebRTCReceiver) GetTemporalLayerDistribution() []buffer.TemporalLayerDistribution {
+	dists := make([]buffer.TemporalLayerDistribution, buffer.DefaultMaxLayerSpatial+1)
+	for layer := range dists {
+		b := w.getBuffer(int32(layer))
+		if b == nil {
+			continue
+		}
+
+		if !w.isSVC {
+			dists[layer] = b.GetTemporalLayerDistributionForSpatial(0)
+		} else {
+			dists[layer] = b.GetTemporalLayerDistributionForSpatial(int32(layer))
+		}
+	}
+	return dists
+}
+
 // closes all track senders in parallel, returns when all are closed
 func closeTrackSenders(senders []TrackSender) {
 	wg := sync.WaitGroup{}
@@ -834,3 +1136,20 @@ func closeTrackSenders(senders []TrackSender) {
 	}
 	wg.Wait()
 }
+
+// -----------------------------------------------------------
+
+// PLIThrottleForLayer returns the throttle for a spatial layer, a single layer
+// track (for example, screen share published without simulcast) is on layer 0
+func PLIThrottleForLayer(pliThrottleConfig config.PLIThrottleConfig, layer int32) time.Duration {
+	switch layer {
+	case 2:
+		return pliThrottleConfig.HighQuality
+	case 1:
+		return pliThrottleConfig.MidQuality
+	case 0:
+		return pliThrottleConfig.LowQuality
+	default:
+		return pliThrottleConfig.MidQuality
+	}
+}
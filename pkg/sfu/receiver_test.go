@@ -21,10 +21,20 @@ import (
 	"runtime"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gammazero/workerpool"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
 )
 
 func TestWebRTCReceiver_OnCloseHandler(t *testing.T) {
@@ -52,6 +62,240 @@ func TestWebRTCReceiver_OnCloseHandler(t *testing.T) {
 	}
 }
 
+type silenceDowntrack struct {
+	TrackSender
+	subscriberID livekit.ParticipantID
+	silent       []bool
+}
+
+func (dt *silenceDowntrack) SubscriberID() livekit.ParticipantID       { return dt.subscriberID }
+func (dt *silenceDowntrack) TrackInfoAvailable()                       {}
+func (dt *silenceDowntrack) UpTrackMaxPublishedLayerChange(_ int32)    {}
+func (dt *silenceDowntrack) UpTrackMaxTemporalLayerSeenChange(_ int32) {}
+func (dt *silenceDowntrack) UpTrackSilenceChange(silent bool)          { dt.silent = append(dt.silent, silent) }
+func (dt *silenceDowntrack) isSilent() bool {
+	return len(dt.silent) != 0 && dt.silent[len(dt.silent)-1]
+}
+
+func newReceiverForSilenceTest(t *testing.T, isTransportHealthy func() bool) *WebRTCReceiver {
+	ti := &livekit.TrackInfo{
+		Type: livekit.TrackType_VIDEO,
+		Layers: []*livekit.VideoLayer{
+			{Quality: livekit.VideoQuality_LOW},
+			{Quality: livekit.VideoQuality_MEDIUM},
+			{Quality: livekit.VideoQuality_HIGH},
+		},
+	}
+	w := &WebRTCReceiver{
+		kind:               webrtc.RTPCodecTypeVideo,
+		logger:             logger.GetLogger(),
+		silenceTimeout:     100 * time.Millisecond,
+		isTransportHealthy: isTransportHealthy,
+		streamTrackerManager: NewStreamTrackerManager(
+			logger.GetLogger(), ti, false, 90000, config.StreamTrackersConfig{},
+		),
+		downTrackSpreader: NewDownTrackSpreader(DownTrackSpreaderParams{
+			Logger: logger.GetLogger(),
+		}),
+	}
+	w.trackInfo.Store(ti)
+	t.Cleanup(w.streamTrackerManager.Close)
+
+	// pretend the silence check window started well before the timeout
+	w.silenceCheckFrom.Store(time.Now().Add(-time.Second).UnixNano())
+	return w
+}
+
+func TestReceiverSilenceDetection(t *testing.T) {
+	t.Run("silent after timeout", func(t *testing.T) {
+		w := newReceiverForSilenceTest(t, nil)
+		dt := &silenceDowntrack{subscriberID: "sub1"}
+		require.NoError(t, w.AddDownTrack(dt))
+
+		w.checkSilence()
+		require.True(t, w.IsSilent())
+		require.True(t, dt.isSilent())
+	})
+
+	t.Run("recent packets are not silent", func(t *testing.T) {
+		w := newReceiverForSilenceTest(t, nil)
+		w.updateLayerActivity(0)
+
+		w.checkSilence()
+		require.False(t, w.IsSilent())
+	})
+
+	t.Run("unhealthy transport skips check", func(t *testing.T) {
+		w := newReceiverForSilenceTest(t, func() bool { return false })
+
+		w.checkSilence()
+		require.False(t, w.IsSilent())
+	})
+
+	t.Run("dynacast paused layers are not silent", func(t *testing.T) {
+		w := newReceiverForSilenceTest(t, nil)
+		w.streamTrackerManager.SetMaxExpectedSpatialLayer(buffer.InvalidLayerSpatial)
+
+		w.checkSilence()
+		require.False(t, w.IsSilent())
+	})
+
+	t.Run("publisher mute skips check", func(t *testing.T) {
+		w := newReceiverForSilenceTest(t, nil)
+		w.streamTrackerManager.SetPaused(true)
+
+		w.checkSilence()
+		require.False(t, w.IsSilent())
+	})
+
+	t.Run("packets resuming un-silences", func(t *testing.T) {
+		w := newReceiverForSilenceTest(t, nil)
+		dt := &silenceDowntrack{subscriberID: "sub1"}
+		require.NoError(t, w.AddDownTrack(dt))
+
+		w.checkSilence()
+		require.True(t, dt.isSilent())
+
+		w.updateLayerActivity(1)
+		require.False(t, w.IsSilent())
+		require.False(t, dt.isSilent())
+	})
+
+	t.Run("down track added while silent", func(t *testing.T) {
+		w := newReceiverForSilenceTest(t, nil)
+		w.checkSilence()
+		require.True(t, w.IsSilent())
+
+		dt := &silenceDowntrack{subscriberID: "sub1"}
+		require.NoError(t, w.AddDownTrack(dt))
+		require.True(t, dt.isSilent())
+
+		w.updateLayerActivity(0)
+		require.False(t, dt.isSilent())
+	})
+}
+
+func TestReceiverLastPacketAt(t *testing.T) {
+	w := newReceiverForSilenceTest(t, nil)
+	// tracked even when silence detection is disabled
+	w.silenceTimeout = 0
+	require.True(t, w.LastPacketAt().IsZero())
+
+	before := time.Now()
+	w.updateLayerActivity(1)
+	require.False(t, w.LastPacketAt().Before(before))
+
+	// invalid layers are ignored
+	last := w.LastPacketAt()
+	w.updateLayerActivity(-1)
+	require.Equal(t, last, w.LastPacketAt())
+}
+
+func TestReceiverAvailableLayersChange(t *testing.T) {
+	w := newReceiverForSilenceTest(t, nil)
+	w.connectionStats = connectionquality.NewConnectionStats(connectionquality.ConnectionStatsParams{
+		MimeType:         webrtc.MimeTypeVP8,
+		ReceiverProvider: w,
+		Logger:           logger.GetLogger(),
+	})
+	w.streamTrackerManager.SetListener(w)
+
+	var changes [][]int32
+	w.OnAvailableLayersChange(func(availableLayers []int32) {
+		changes = append(changes, availableLayers)
+	})
+
+	w.streamTrackerManager.addAvailableLayer(1)
+	w.streamTrackerManager.addAvailableLayer(0)
+	// already available, no change
+	w.streamTrackerManager.addAvailableLayer(1)
+	w.streamTrackerManager.addAvailableLayer(2)
+	w.streamTrackerManager.removeAvailableLayer(1)
+	w.streamTrackerManager.removeAvailableLayer(0)
+	w.streamTrackerManager.removeAvailableLayer(2)
+
+	require.Equal(t, [][]int32{{1}, {0, 1}, {0, 1, 2}, {0, 2}, {2}, {}}, changes)
+	require.Empty(t, w.streamTrackerManager.GetAvailableLayers())
+}
+
+type rebindDowntrack struct {
+	silenceDowntrack
+	ssrcs chan uint32
+}
+
+func (dt *rebindDowntrack) WriteRTP(p *buffer.ExtPacket, _ int32) error {
+	dt.ssrcs <- p.Packet.SSRC
+	return nil
+}
+
+func (dt *rebindDowntrack) Close() {}
+
+func TestReceiverLayerRebind(t *testing.T) {
+	opusCodec := webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000},
+		PayloadType:        111,
+	}
+	newBuffer := func(ssrc uint32) *buffer.Buffer {
+		buff := buffer.NewBuffer(ssrc, 100, 100)
+		buff.Bind(webrtc.RTPParameters{Codecs: []webrtc.RTPCodecParameters{opusCodec}}, opusCodec.RTPCodecCapability)
+		return buff
+	}
+	writePacket := func(buff *buffer.Buffer, sn uint16) {
+		pkt := rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    uint8(opusCodec.PayloadType),
+				SequenceNumber: sn,
+				Timestamp:      uint32(sn) * 960,
+				SSRC:           buff.SSRC(),
+			},
+			Payload: []byte{1, 2, 3},
+		}
+		b, err := pkt.Marshal()
+		require.NoError(t, err)
+		_, err = buff.Write(b)
+		require.NoError(t, err)
+	}
+
+	w := newReceiverForSilenceTest(t, nil)
+	w.connectionStats = connectionquality.NewConnectionStats(connectionquality.ConnectionStatsParams{
+		ReceiverProvider: w,
+		Logger:           logger.GetLogger(),
+	})
+	var closed atomic.Bool
+	w.OnCloseHandler(func() {
+		closed.Store(true)
+	})
+
+	dt := &rebindDowntrack{
+		silenceDowntrack: silenceDowntrack{subscriberID: "sub1"},
+		ssrcs:            make(chan uint32, 10),
+	}
+	require.NoError(t, w.AddDownTrack(dt))
+
+	oldBuff := newBuffer(1000)
+	w.buffers[0] = oldBuff
+	go w.forwardRTP(0)
+
+	writePacket(oldBuff, 1)
+	require.Equal(t, uint32(1000), <-dt.ssrcs)
+
+	// publisher re-creates the layer with a new SSRC mid-stream
+	newBuff := newBuffer(2000)
+	w.bufferMu.Lock()
+	w.buffers[0] = newBuff
+	w.bufferMu.Unlock()
+	oldBuff.Close()
+
+	writePacket(newBuff, 100)
+	require.Equal(t, uint32(2000), <-dt.ssrcs)
+	require.False(t, closed.Load())
+
+	// closing the current buffer stops forwarding
+	newBuff.Close()
+	require.Eventually(t, closed.Load, time.Second, 10*time.Millisecond)
+}
+
 func BenchmarkWriteRTP(b *testing.B) {
 	cases := []int{1, 2, 5, 10, 100, 250, 500}
 	workers := runtime.NumCPU()
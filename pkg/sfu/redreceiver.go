@@ -73,9 +73,7 @@ func (r *RedReceiver) ForwardRTP(pkt *buffer.ExtPacket, spatialLayer int32) {
 
 	// not modify the ExtPacket.RawPacket here for performance since it is not used by the DownTrack,
 	// otherwise it should be set to the correct value (marshal the primary rtp packet)
-	r.downTrackSpreader.Broadcast(func(dt TrackSender) {
-		_ = dt.WriteRTP(&pPkt, spatialLayer)
-	})
+	r.downTrackSpreader.WriteRTP(&pPkt, spatialLayer)
 }
 
 func (r *RedReceiver) AddDownTrack(track TrackSender) error {
@@ -0,0 +1,120 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// redRedundancyController decides whether redundant encodings of RED are sent to a subscriber,
+// based on loss reported by the subscriber in RTCP receiver reports. Redundancy starts off
+// and is switched only when loss stays beyond a threshold for a number of consecutive reports.
+type redRedundancyController struct {
+	config config.AdaptiveREDConfig
+	logger logger.Logger
+
+	lock        sync.Mutex
+	enabled     bool
+	numReports  int
+	numSwitches int
+}
+
+func newRedRedundancyController(conf config.AdaptiveREDConfig, logger logger.Logger) *redRedundancyController {
+	return &redRedundancyController{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Update takes fraction lost of a receiver report and returns true if redundancy was switched
+func (r *redRedundancyController) Update(fractionLost uint8) bool {
+	lossPercentage := float64(fractionLost) * 100.0 / 256.0
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var isBeyondThreshold bool
+	if r.enabled {
+		isBeyondThreshold = lossPercentage <= r.config.OffLossPercentage
+	} else {
+		isBeyondThreshold = lossPercentage >= r.config.OnLossPercentage
+	}
+	if !isBeyondThreshold {
+		r.numReports = 0
+		return false
+	}
+
+	r.numReports++
+	if r.numReports < r.config.MinReports {
+		return false
+	}
+
+	r.enabled = !r.enabled
+	r.numReports = 0
+	r.numSwitches++
+	r.logger.Debugw("switching RED redundancy", "enabled", r.enabled, "lossPercentage", lossPercentage)
+	return true
+}
+
+func (r *redRedundancyController) IsEnabled() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.enabled
+}
+
+func (r *redRedundancyController) DebugInfo() map[string]interface{} {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return map[string]interface{}{
+		"Enabled":     r.enabled,
+		"NumSwitches": r.numSwitches,
+	}
+}
+
+// --------------------------------------
+
+// splitPrimaryEncodingForRED returns block payload type and data of primary encoding of a RED payload,
+// so that it can be sent as a RED payload with a single block, i. e. without redundancy.
+func splitPrimaryEncodingForRED(payload []byte) (uint8, []byte, error) {
+	var blockLength int
+	for {
+		if len(payload) < 1 {
+			return 0, nil, ErrIncompleteRedHeader
+		}
+
+		if payload[0]&0x80 == 0 {
+			// last block header is for primary encoding
+			blockPT := payload[0]
+			payload = payload[1:]
+			if len(payload) < blockLength {
+				return 0, nil, ErrIncompleteRedBlock
+			}
+			return blockPT, payload[blockLength:], nil
+		}
+
+		if len(payload) < 4 {
+			return 0, nil, ErrIncompleteRedHeader
+		}
+		blockLength += int(binary.BigEndian.Uint16(payload[2:]) & 0x03FF)
+		payload = payload[4:]
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestRedRedundancyController(t *testing.T) {
+	r := newRedRedundancyController(config.AdaptiveREDConfig{
+		Enabled:           true,
+		OnLossPercentage:  5,
+		OffLossPercentage: 2,
+		MinReports:        3,
+	}, logger.GetLogger())
+	require.False(t, r.IsEnabled())
+
+	// 10% loss, 26/256
+	lossy := uint8(26)
+	// 3% loss, between thresholds
+	moderate := uint8(8)
+
+	// loss has to be sustained
+	require.False(t, r.Update(lossy))
+	require.False(t, r.Update(lossy))
+	require.False(t, r.Update(moderate))
+	require.False(t, r.Update(lossy))
+	require.False(t, r.Update(lossy))
+	require.False(t, r.IsEnabled())
+	require.True(t, r.Update(lossy))
+	require.True(t, r.IsEnabled())
+
+	// stays on while loss is above off threshold
+	for i := 0; i < 5; i++ {
+		require.False(t, r.Update(moderate))
+	}
+	require.True(t, r.IsEnabled())
+
+	require.False(t, r.Update(0))
+	require.False(t, r.Update(0))
+	require.True(t, r.Update(0))
+	require.False(t, r.IsEnabled())
+	require.Equal(t, 2, r.DebugInfo()["NumSwitches"])
+}
+
+func TestSplitPrimaryEncodingForRED(t *testing.T) {
+	header := rtp.Header{SequenceNumber: 65530, Timestamp: (1 << 32) - 3*960, PayloadType: 63}
+	pkts := generatePkts(header, 10, 960)
+	redPkts := generateRedPkts(t, pkts, maxRedCount)
+
+	for i, redPkt := range redPkts {
+		blockPT, primary, err := splitPrimaryEncodingForRED(redPkt.Payload)
+		require.NoError(t, err)
+		require.Equal(t, uint8(opusPT), blockPT)
+		require.Equal(t, pkts[i].Payload, primary)
+
+		// primary only payload is a valid RED payload with a single block
+		primaryOnly := *redPkt
+		primaryOnly.Payload = append([]byte{blockPT}, primary...)
+		pktsFromRed, err := extractPktsFromRed(&primaryOnly, 0xFF)
+		require.NoError(t, err)
+		require.Len(t, pktsFromRed, 1)
+		verifyEncodingEqual(t, pktsFromRed[0], pkts[i])
+	}
+
+	_, _, err := splitPrimaryEncodingForRED(nil)
+	require.ErrorIs(t, err, ErrIncompleteRedHeader)
+	_, _, err = splitPrimaryEncodingForRED(redPkts[5].Payload[:3])
+	require.ErrorIs(t, err, ErrIncompleteRedHeader)
+	_, _, err = splitPrimaryEncodingForRED(redPkts[5].Payload[:9])
+	require.ErrorIs(t, err, ErrIncompleteRedBlock)
+}
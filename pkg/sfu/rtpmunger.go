@@ -16,11 +16,17 @@ package sfu
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/livekit/protocol/logger"
 
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 // RTPMunger
@@ -35,6 +41,10 @@ const (
 
 const (
 	RtxGateWindow = 2000
+
+	// number of lost packets waiting to be filled and of filled incoming sequence numbers
+	// remembered to drop late arrivals
+	maxGapFillPackets = 64
 )
 
 type TranslationParamsRTP struct {
@@ -48,6 +58,13 @@ type SnTs struct {
 	extTimestamp      uint64
 }
 
+// lost packet which is filled if it does not arrive in time
+type gapFillCandidate struct {
+	extIncomingSN uint64
+	snTs          SnTs
+	lostAt        time.Time
+}
+
 // ----------------------------------------------------------------------
 
 type RTPMungerState struct {
@@ -68,6 +85,38 @@ func (r RTPMungerState) String() string {
 	)
 }
 
+func (r RTPMungerState) MarshalBinary() ([]byte, error) {
+	e := &sutils.WireEncoder{}
+	e.Uint64(1, r.ExtLastSN)
+	e.Uint64(2, r.ExtSecondLastSN)
+	e.Uint64(3, r.ExtLastTS)
+	e.Uint64(4, r.ExtSecondLastTS)
+	e.Bool(5, r.LastMarker)
+	e.Bool(6, r.SecondLastMarker)
+	return e.Encoded(), nil
+}
+
+func (r *RTPMungerState) UnmarshalBinary(data []byte) error {
+	*r = RTPMungerState{}
+	return sutils.DecodeWire(data, func(num protowire.Number, f sutils.WireField) error {
+		switch num {
+		case 1:
+			r.ExtLastSN = f.Uint64()
+		case 2:
+			r.ExtSecondLastSN = f.Uint64()
+		case 3:
+			r.ExtLastTS = f.Uint64()
+		case 4:
+			r.ExtSecondLastTS = f.Uint64()
+		case 5:
+			r.LastMarker = f.Bool()
+		case 6:
+			r.SecondLastMarker = f.Bool()
+		}
+		return nil
+	})
+}
+
 // ----------------------------------------------------------------------
 
 type RTPMunger struct {
@@ -84,17 +133,27 @@ type RTPMunger struct {
 	extSecondLastTS uint64
 	tsOffset        uint64
 
+	// when not 1, outgoing timestamps are scaled relative to an incoming/outgoing reference pair
+	// instead of applying tsOffset
+	tsScale       float64
+	tsScaleRefIn  uint64
+	tsScaleRefOut uint64
+
 	lastMarker       bool
 	secondLastMarker bool
 
 	extRtxGateSn      uint64
 	isInRtxGateRegion bool
+
+	gapFillCandidates []gapFillCandidate
+	gapFilled         []uint64
 }
 
 func NewRTPMunger(logger logger.Logger) *RTPMunger {
 	return &RTPMunger{
 		logger:     logger,
 		snRangeMap: utils.NewRangeMap[uint64, uint64](100),
+		tsScale:    1,
 	}
 }
 
@@ -107,6 +166,7 @@ func (r *RTPMunger) DebugInfo() map[string]interface{} {
 		"ExtLastTS":            r.extLastTS,
 		"ExtSecondLastTS":      r.extSecondLastTS,
 		"TSOffset":             r.tsOffset,
+		"TSScale":              r.tsScale,
 		"LastMarker":           r.lastMarker,
 		"SecondLastMarker":     r.secondLastMarker,
 	}
@@ -127,6 +187,29 @@ func (r *RTPMunger) GetTSOffset() uint64 {
 	return r.tsOffset
 }
 
+func (r *RTPMunger) GetTimestampScale() float64 {
+	return r.tsScale
+}
+
+// SetTimestampScale scales outgoing timestamp increments, i. e. to correct a stream whose actual clock rate
+// does not match the codec clock rate. Outgoing timestamps stay continuous, the given packet is munged
+// to the same timestamp before and after the change. A scale of 1 turns off scaling.
+func (r *RTPMunger) SetTimestampScale(scale float64, extPkt *buffer.ExtPacket) {
+	extMungedTS := r.mungeTS(extPkt.ExtTimestamp)
+	r.tsScale = scale
+	r.tsScaleRefIn = extPkt.ExtTimestamp
+	r.tsScaleRefOut = extMungedTS
+	r.tsOffset = extPkt.ExtTimestamp - extMungedTS
+}
+
+func (r *RTPMunger) mungeTS(extTS uint64) uint64 {
+	if r.tsScale == 1 {
+		return extTS - r.tsOffset
+	}
+
+	return r.tsScaleRefOut + uint64(int64(math.Round(float64(int64(extTS-r.tsScaleRefIn))*r.tsScale)))
+}
+
 func (r *RTPMunger) SeedLast(state RTPMungerState) {
 	r.extLastSN = state.ExtLastSN
 	r.extSecondLastSN = state.ExtSecondLastSN
@@ -147,6 +230,11 @@ func (r *RTPMunger) SetLastSnTs(extPkt *buffer.ExtPacket) {
 	r.extLastTS = extPkt.ExtTimestamp
 	r.extSecondLastTS = extPkt.ExtTimestamp
 	r.tsOffset = 0
+	r.tsScaleRefIn = extPkt.ExtTimestamp
+	r.tsScaleRefOut = extPkt.ExtTimestamp
+
+	r.gapFillCandidates = r.gapFillCandidates[:0]
+	r.gapFilled = r.gapFilled[:0]
 }
 
 func (r *RTPMunger) UpdateSnTsOffsets(extPkt *buffer.ExtPacket, snAdjust uint64, tsAdjust uint64) {
@@ -156,6 +244,11 @@ func (r *RTPMunger) UpdateSnTsOffsets(extPkt *buffer.ExtPacket, snAdjust uint64,
 	r.updateSnOffset()
 
 	r.tsOffset = extPkt.ExtTimestamp - r.extLastTS - tsAdjust
+	r.tsScaleRefIn = extPkt.ExtTimestamp
+	r.tsScaleRefOut = r.extLastTS + tsAdjust
+
+	r.gapFillCandidates = r.gapFillCandidates[:0]
+	r.gapFilled = r.gapFilled[:0]
 }
 
 func (r *RTPMunger) PacketDropped(extPkt *buffer.ExtPacket) {
@@ -197,7 +290,7 @@ func (r *RTPMunger) UpdateAndGetSnTs(extPkt *buffer.ExtPacket, marker bool) (Tra
 		}
 
 		extMungedSN := extPkt.ExtSequenceNumber - r.snOffset
-		extMungedTS := extPkt.ExtTimestamp - r.tsOffset
+		extMungedTS := r.mungeTS(extPkt.ExtTimestamp)
 
 		r.extSecondLastSN = r.extLastSN
 		r.extLastSN = extMungedSN
@@ -223,6 +316,17 @@ func (r *RTPMunger) UpdateAndGetSnTs(extPkt *buffer.ExtPacket, marker bool) (Tra
 	}
 
 	if diff < 0 {
+		if slices.Contains(r.gapFilled, extPkt.ExtSequenceNumber) {
+			// outgoing sequence number already used by a gap fill packet
+			return TranslationParamsRTP{
+				snOrdering: SequenceNumberOrderingOutOfOrder,
+			}, ErrSequenceNumberGapFilled
+		}
+		// arrived in time, not filled
+		r.gapFillCandidates = slices.DeleteFunc(r.gapFillCandidates, func(c gapFillCandidate) bool {
+			return c.extIncomingSN == extPkt.ExtSequenceNumber
+		})
+
 		// out-of-order, look up sequence number offset cache
 		snOffset, err := r.snRangeMap.GetValue(extPkt.ExtSequenceNumber)
 		if err != nil {
@@ -250,7 +354,7 @@ func (r *RTPMunger) UpdateAndGetSnTs(extPkt *buffer.ExtPacket, marker bool) (Tra
 		return TranslationParamsRTP{
 			snOrdering:        SequenceNumberOrderingOutOfOrder,
 			extSequenceNumber: extSequenceNumber,
-			extTimestamp:      extPkt.ExtTimestamp - r.tsOffset,
+			extTimestamp:      r.mungeTS(extPkt.ExtTimestamp),
 		}, nil
 	}
 
@@ -275,6 +379,57 @@ func (r *RTPMunger) UpdateAndGetSnTs(extPkt *buffer.ExtPacket, marker bool) (Tra
 	}, ErrDuplicatePacket
 }
 
+// AddGapFillCandidates records packets missing before the last packet, to be called right after
+// UpdateAndGetSnTs reports a gap. Gaps larger than maxPackets are not filled.
+func (r *RTPMunger) AddGapFillCandidates(maxPackets int, at time.Time) {
+	num := r.extLastSN - r.extSecondLastSN - 1
+	if num == 0 || num > uint64(maxPackets) || int64(r.extLastTS-r.extSecondLastTS) <= 0 {
+		return
+	}
+
+	tsStep := (r.extLastTS - r.extSecondLastTS) / (num + 1)
+	if tsStep == 0 {
+		return
+	}
+
+	for i := uint64(1); i <= num; i++ {
+		r.gapFillCandidates = append(r.gapFillCandidates, gapFillCandidate{
+			extIncomingSN: r.extHighestIncomingSN - num - 1 + i,
+			snTs: SnTs{
+				extSequenceNumber: r.extSecondLastSN + i,
+				extTimestamp:      r.extSecondLastTS + i*tsStep,
+			},
+			lostAt: at,
+		})
+	}
+	if len(r.gapFillCandidates) > maxGapFillPackets {
+		r.gapFillCandidates = r.gapFillCandidates[len(r.gapFillCandidates)-maxGapFillPackets:]
+	}
+}
+
+// UpdateAndGetGapFillSnTs returns sequence numbers and timestamps of lost packets which have not arrived
+// within wait. Packets arriving later are dropped, as their outgoing sequence numbers are used up by
+// the fill packets, but those arriving earlier are forwarded and not filled.
+func (r *RTPMunger) UpdateAndGetGapFillSnTs(at time.Time, wait time.Duration) []SnTs {
+	var vals []SnTs
+	remaining := r.gapFillCandidates[:0]
+	for _, c := range r.gapFillCandidates {
+		if at.Sub(c.lostAt) < wait {
+			remaining = append(remaining, c)
+			continue
+		}
+
+		vals = append(vals, c.snTs)
+		r.gapFilled = append(r.gapFilled, c.extIncomingSN)
+	}
+	r.gapFillCandidates = remaining
+
+	if len(r.gapFilled) > maxGapFillPackets {
+		r.gapFilled = r.gapFilled[len(r.gapFilled)-maxGapFillPackets:]
+	}
+	return vals
+}
+
 func (r *RTPMunger) FilterRTX(nacks []uint16) []uint16 {
 	if !r.isInRtxGateRegion {
 		return nacks
@@ -341,6 +496,7 @@ func (r *RTPMunger) UpdateAndGetPaddingSnTs(num int, clockRate uint32, frameRate
 		r.extSecondLastTS = vals[len(vals)-2].extTimestamp
 	}
 	r.tsOffset -= extLastTS - r.extLastTS
+	r.tsScaleRefOut += extLastTS - r.extLastTS
 	r.extLastTS = extLastTS
 
 	if forceMarker {
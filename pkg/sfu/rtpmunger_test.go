@@ -16,6 +16,7 @@ package sfu
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -583,3 +584,202 @@ func TestIsOnFrameBoundary(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, r.IsOnFrameBoundary())
 }
+
+func TestGapFill(t *testing.T) {
+	r := newRTPMunger()
+	now := time.Now()
+	wait := 60 * time.Millisecond
+
+	params := &testutils.TestExtPacketParams{
+		SequenceNumber: 100,
+		Timestamp:      1000,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ := testutils.GetTestExtPacket(params)
+	r.SetLastSnTs(extPkt)
+
+	_, err := r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+
+	// padding only packet is dropped, introducing a sequence number offset
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 101,
+		Timestamp:      1000,
+		SSRC:           0x12345678,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	_, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.ErrorIs(t, err, ErrPaddingOnlyPacket)
+
+	// gap of two packets, not filled as it exceeds max
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 104,
+		Timestamp:      1000 + 3*960,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err := r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, SequenceNumberOrderingGap, tp.snOrdering)
+	require.Equal(t, uint64(103), tp.extSequenceNumber)
+	r.AddGapFillCandidates(1, now)
+	require.Empty(t, r.UpdateAndGetGapFillSnTs(now.Add(wait), wait))
+
+	// gap of two packets, filled using munged sequence numbers only after wait
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 107,
+		Timestamp:      1000 + 6*960,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, SequenceNumberOrderingGap, tp.snOrdering)
+	require.Equal(t, uint64(106), tp.extSequenceNumber)
+
+	r.AddGapFillCandidates(3, now)
+	require.Empty(t, r.UpdateAndGetGapFillSnTs(now, wait))
+
+	// late arrival within wait is forwarded with its own sequence number and is not filled
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 105,
+		Timestamp:      1000 + 4*960,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, SequenceNumberOrderingOutOfOrder, tp.snOrdering)
+	require.Equal(t, uint64(104), tp.extSequenceNumber)
+
+	require.Equal(t, []SnTs{
+		{extSequenceNumber: 105, extTimestamp: 1000 + 5*960},
+	}, r.UpdateAndGetGapFillSnTs(now.Add(wait), wait))
+	require.Empty(t, r.UpdateAndGetGapFillSnTs(now.Add(2*wait), wait))
+
+	// late arrival after it was filled is dropped
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 106,
+		Timestamp:      1000 + 5*960,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	_, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.ErrorIs(t, err, ErrSequenceNumberGapFilled)
+
+	// late arrival in a gap that was not filled is forwarded
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 103,
+		Timestamp:      1000 + 2*960,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, SequenceNumberOrderingOutOfOrder, tp.snOrdering)
+	require.Equal(t, uint64(102), tp.extSequenceNumber)
+
+	// next packet continues after the filled gap
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 108,
+		Timestamp:      1000 + 7*960,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, SequenceNumberOrderingContiguous, tp.snOrdering)
+	require.Equal(t, uint64(107), tp.extSequenceNumber)
+}
+
+func TestTimestampScale(t *testing.T) {
+	r := newRTPMunger()
+	require.Equal(t, float64(1), r.GetTimestampScale())
+
+	params := &testutils.TestExtPacketParams{
+		SequenceNumber: 100,
+		Timestamp:      1000,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ := testutils.GetTestExtPacket(params)
+	r.SetLastSnTs(extPkt)
+	_, err := r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+
+	// scaling is anchored at the given packet, outgoing timestamps stay continuous
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 101,
+		Timestamp:      2000,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	r.SetTimestampScale(2, extPkt)
+	require.Equal(t, float64(2), r.GetTimestampScale())
+	tp, err := r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2000), tp.extTimestamp)
+
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 103,
+		Timestamp:      2100,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+		SetMarker:      true,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2200), tp.extTimestamp)
+
+	// out of order packet from before the anchor
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 102,
+		Timestamp:      1950,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, SequenceNumberOrderingOutOfOrder, tp.snOrdering)
+	require.Equal(t, uint64(1900), tp.extTimestamp)
+
+	// padding advances outgoing timestamps of following packets
+	snts, err := r.UpdateAndGetPaddingSnTs(1, 90000, 30, true, 2200)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5200), snts[0].extTimestamp)
+
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 104,
+		Timestamp:      2200,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5400), tp.extTimestamp)
+
+	// turning off scaling keeps continuity
+	params = &testutils.TestExtPacketParams{
+		SequenceNumber: 105,
+		Timestamp:      2300,
+		SSRC:           0x12345678,
+		PayloadSize:    20,
+	}
+	extPkt, _ = testutils.GetTestExtPacket(params)
+	r.SetTimestampScale(1, extPkt)
+	tp, err = r.UpdateAndGetSnTs(extPkt, extPkt.Packet.Marker)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5600), tp.extTimestamp)
+	require.Equal(t, extPkt.ExtTimestamp-5600, r.GetTSOffset())
+}
@@ -0,0 +1,599 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamallocator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+//
+// Recording format
+//
+//   header: magic "LKSA", version byte
+//   record: type byte, uvarint payload length, payload
+//
+// Payload starts with the time of the record in microseconds since start of recording (uvarint),
+// followed by fields of the record type. Integers are varint encoded, booleans are a byte.
+// Unknown record types are skipped by the reader.
+//
+
+const (
+	recordingMagic   = "LKSA"
+	recordingVersion = 1
+)
+
+var (
+	ErrNotARecording          = errors.New("not an allocation recording")
+	ErrUnsupportedVersion     = errors.New("unsupported allocation recording version")
+	ErrMalformedRecord        = errors.New("malformed allocation record")
+	errRecordingLimitExceeded = errors.New("recording limit exceeded")
+)
+
+type RecordType uint8
+
+const (
+	RecordTypeEstimate RecordType = iota + 1
+	RecordTypeProbeStart
+	RecordTypeProbeDone
+	RecordTypeAllocateAll
+	RecordTypeAllocateTrack
+)
+
+func (r RecordType) String() string {
+	switch r {
+	case RecordTypeEstimate:
+		return "ESTIMATE"
+	case RecordTypeProbeStart:
+		return "PROBE_START"
+	case RecordTypeProbeDone:
+		return "PROBE_DONE"
+	case RecordTypeAllocateAll:
+		return "ALLOCATE_ALL"
+	case RecordTypeAllocateTrack:
+		return "ALLOCATE_TRACK"
+	default:
+		return fmt.Sprintf("%d", int(r))
+	}
+}
+
+// RecordedTrack is the allocation decision of a track along with the state it was decided from
+type RecordedTrack struct {
+	TrackID    livekit.TrackID
+	IsManaged  bool
+	Snapshot   sfu.AllocationSnapshot
+	Allocation sfu.VideoAllocation
+}
+
+type Record struct {
+	Type RecordType
+	// time since start of recording
+	At time.Duration
+
+	// RecordTypeEstimate
+	ReceivedEstimate int64
+	// RecordTypeEstimate, RecordTypeProbeDone
+	CommittedChannelCapacity int64
+
+	// RecordTypeProbeStart
+	ProbeGoalDelta        int64
+	ExpectedBandwidthUsed int64
+
+	// RecordTypeProbeDone
+	IsProbeNotFailing    bool
+	IsProbeGoalReached   bool
+	HighestProbeEstimate int64
+
+	// RecordTypeAllocateAll
+	AvailableChannelCapacity int64
+	AllowPause               bool
+	EstimateUnmanagedTracks  bool
+
+	// RecordTypeAllocateTrack
+	Reason string
+
+	// RecordTypeAllocateAll, RecordTypeAllocateTrack
+	Tracks []RecordedTrack
+}
+
+// ---------------------------------------------------------------------------
+
+type AllocationRecorderParams struct {
+	Writer io.WriteCloser
+	// recording stops after this many bytes, 0 for no limit
+	MaxBytes int64
+	// records beyond this rate are dropped, 0 for no limit
+	MaxRecordsPerSecond int
+	Logger              logger.Logger
+}
+
+// AllocationRecorder writes decisions of a stream allocator in a compact binary format.
+// Recorded allocation passes can be replayed offline with Replay.
+// All methods are safe to call on a nil recorder, which records nothing.
+type AllocationRecorder struct {
+	params AllocationRecorderParams
+
+	lock          sync.Mutex
+	writer        *bufio.Writer
+	startedAt     time.Time
+	bytesWritten  int64
+	windowStartAt time.Time
+	windowRecords int
+	numDropped    int
+	isFull        bool
+	isClosed      bool
+	buf           []byte
+}
+
+func NewAllocationRecorder(params AllocationRecorderParams) (*AllocationRecorder, error) {
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+
+	r := &AllocationRecorder{
+		params:    params,
+		writer:    bufio.NewWriter(params.Writer),
+		startedAt: time.Now(),
+	}
+
+	header := append([]byte(recordingMagic), recordingVersion)
+	if _, err := r.writer.Write(header); err != nil {
+		return nil, err
+	}
+	r.bytesWritten = int64(len(header))
+	return r, nil
+}
+
+func (r *AllocationRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.isClosed {
+		return nil
+	}
+	r.isClosed = true
+
+	if r.numDropped != 0 {
+		r.params.Logger.Infow("allocation recording dropped records", "numDropped", r.numDropped, "bytesWritten", r.bytesWritten)
+	}
+
+	err := r.writer.Flush()
+	if cerr := r.params.Writer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (r *AllocationRecorder) RecordEstimate(receivedEstimate int64, committedChannelCapacity int64) {
+	if r == nil {
+		return
+	}
+
+	r.record(RecordTypeEstimate, func(b []byte) []byte {
+		b = binary.AppendVarint(b, receivedEstimate)
+		return binary.AppendVarint(b, committedChannelCapacity)
+	})
+}
+
+func (r *AllocationRecorder) RecordProbeStart(probeGoalDelta int64, expectedBandwidthUsage int64) {
+	if r == nil {
+		return
+	}
+
+	r.record(RecordTypeProbeStart, func(b []byte) []byte {
+		b = binary.AppendVarint(b, probeGoalDelta)
+		return binary.AppendVarint(b, expectedBandwidthUsage)
+	})
+}
+
+func (r *AllocationRecorder) RecordProbeDone(isNotFailing bool, isGoalReached bool, highestEstimate int64, committedChannelCapacity int64) {
+	if r == nil {
+		return
+	}
+
+	r.record(RecordTypeProbeDone, func(b []byte) []byte {
+		b = appendBool(b, isNotFailing)
+		b = appendBool(b, isGoalReached)
+		b = binary.AppendVarint(b, highestEstimate)
+		return binary.AppendVarint(b, committedChannelCapacity)
+	})
+}
+
+func (r *AllocationRecorder) RecordAllocateAll(availableChannelCapacity int64, allowPause bool, estimateUnmanagedTracks bool, tracks []RecordedTrack) {
+	if r == nil {
+		return
+	}
+
+	r.record(RecordTypeAllocateAll, func(b []byte) []byte {
+		b = binary.AppendVarint(b, availableChannelCapacity)
+		b = appendBool(b, allowPause)
+		b = appendBool(b, estimateUnmanagedTracks)
+		return appendRecordedTracks(b, tracks)
+	})
+}
+
+func (r *AllocationRecorder) RecordAllocateTrack(reason string, track RecordedTrack) {
+	if r == nil {
+		return
+	}
+
+	r.record(RecordTypeAllocateTrack, func(b []byte) []byte {
+		b = appendString(b, reason)
+		return appendRecordedTracks(b, []RecordedTrack{track})
+	})
+}
+
+func (r *AllocationRecorder) record(recordType RecordType, appendPayload func(b []byte) []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.admitLocked(); err != nil {
+		r.numDropped++
+		return
+	}
+
+	now := time.Now()
+	payload := binary.AppendUvarint(r.buf[:0], uint64(now.Sub(r.startedAt).Microseconds()))
+	payload = appendPayload(payload)
+	r.buf = payload
+
+	var header []byte
+	header = append(header, byte(recordType))
+	header = binary.AppendUvarint(header, uint64(len(payload)))
+
+	size := int64(len(header) + len(payload))
+	if r.params.MaxBytes > 0 && r.bytesWritten+size > r.params.MaxBytes {
+		r.params.Logger.Infow("allocation recording reached size limit", "maxBytes", r.params.MaxBytes)
+		r.isFull = true
+		r.numDropped++
+		return
+	}
+
+	if _, err := r.writer.Write(header); err == nil {
+		_, err = r.writer.Write(payload)
+		if err != nil {
+			r.params.Logger.Warnw("could not write allocation record", err)
+			r.isFull = true
+		}
+	} else {
+		r.params.Logger.Warnw("could not write allocation record", err)
+		r.isFull = true
+	}
+	r.bytesWritten += size
+	r.windowRecords++
+}
+
+func (r *AllocationRecorder) admitLocked() error {
+	if r.isClosed || r.isFull {
+		return errRecordingLimitExceeded
+	}
+
+	if r.params.MaxRecordsPerSecond > 0 {
+		now := time.Now()
+		if now.Sub(r.windowStartAt) >= time.Second {
+			r.windowStartAt = now
+			r.windowRecords = 0
+		}
+		if r.windowRecords >= r.params.MaxRecordsPerSecond {
+			return errRecordingLimitExceeded
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+
+// AllocationRecordReader reads records written by AllocationRecorder
+type AllocationRecordReader struct {
+	reader *bufio.Reader
+}
+
+func NewAllocationRecordReader(reader io.Reader) (*AllocationRecordReader, error) {
+	br := bufio.NewReader(reader)
+
+	header := make([]byte, len(recordingMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, ErrNotARecording
+	}
+	if string(header[:len(recordingMagic)]) != recordingMagic {
+		return nil, ErrNotARecording
+	}
+	if header[len(recordingMagic)] != recordingVersion {
+		return nil, ErrUnsupportedVersion
+	}
+
+	return &AllocationRecordReader{reader: br}, nil
+}
+
+// Next returns the next record, io.EOF at end of recording and io.ErrUnexpectedEOF if the last record is truncated
+func (a *AllocationRecordReader) Next() (*Record, error) {
+	for {
+		recordType, err := a.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		length, err := binary.ReadUvarint(a.reader)
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(a.reader, payload); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		switch RecordType(recordType) {
+		case RecordTypeEstimate, RecordTypeProbeStart, RecordTypeProbeDone, RecordTypeAllocateAll, RecordTypeAllocateTrack:
+			return decodeRecord(RecordType(recordType), payload)
+
+		default:
+			// written by a newer version, skip
+			continue
+		}
+	}
+}
+
+func decodeRecord(recordType RecordType, payload []byte) (*Record, error) {
+	d := &decoder{reader: bytes.NewReader(payload)}
+
+	record := &Record{
+		Type: recordType,
+		At:   time.Duration(d.uvarint()) * time.Microsecond,
+	}
+	switch recordType {
+	case RecordTypeEstimate:
+		record.ReceivedEstimate = d.varint()
+		record.CommittedChannelCapacity = d.varint()
+
+	case RecordTypeProbeStart:
+		record.ProbeGoalDelta = d.varint()
+		record.ExpectedBandwidthUsed = d.varint()
+
+	case RecordTypeProbeDone:
+		record.IsProbeNotFailing = d.bool()
+		record.IsProbeGoalReached = d.bool()
+		record.HighestProbeEstimate = d.varint()
+		record.CommittedChannelCapacity = d.varint()
+
+	case RecordTypeAllocateAll:
+		record.AvailableChannelCapacity = d.varint()
+		record.AllowPause = d.bool()
+		record.EstimateUnmanagedTracks = d.bool()
+		record.Tracks = d.recordedTracks()
+
+	case RecordTypeAllocateTrack:
+		record.Reason = d.string()
+		record.Tracks = d.recordedTracks()
+	}
+
+	if d.err != nil {
+		return nil, ErrMalformedRecord
+	}
+	return record, nil
+}
+
+// ---------------------------------------------------------------------------
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+func appendString(b []byte, v string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendLayer(b []byte, layer buffer.VideoLayer) []byte {
+	b = binary.AppendVarint(b, int64(layer.Spatial))
+	return binary.AppendVarint(b, int64(layer.Temporal))
+}
+
+func appendBitrates(b []byte, brs sfu.Bitrates) []byte {
+	for s := range brs {
+		for t := range brs[s] {
+			b = binary.AppendVarint(b, brs[s][t])
+		}
+	}
+	return b
+}
+
+func appendRecordedTracks(b []byte, tracks []RecordedTrack) []byte {
+	b = binary.AppendUvarint(b, uint64(len(tracks)))
+	for _, track := range tracks {
+		b = appendString(b, string(track.TrackID))
+		b = appendBool(b, track.IsManaged)
+
+		snapshot := &track.Snapshot
+		b = appendString(b, snapshot.MimeType)
+		b = binary.AppendUvarint(b, uint64(len(snapshot.AvailableLayers)))
+		for _, layer := range snapshot.AvailableLayers {
+			b = binary.AppendVarint(b, int64(layer))
+		}
+		b = appendBitrates(b, snapshot.Bitrates)
+		b = appendLayer(b, snapshot.MaxLayer)
+		b = appendLayer(b, snapshot.MaxSeenLayer)
+		b = appendLayer(b, snapshot.CurrentLayer)
+		b = appendLayer(b, snapshot.TargetLayer)
+		b = binary.AppendVarint(b, int64(snapshot.RequestSpatial))
+		b = appendBool(b, snapshot.Muted)
+		b = appendBool(b, snapshot.PubMuted)
+		b = appendBool(b, snapshot.PubSilent)
+		b = appendBool(b, snapshot.Moderated)
+		b = binary.AppendVarint(b, snapshot.MaxBitrate)
+		b = binary.AppendVarint(b, snapshot.BandwidthRequested)
+
+		allocation := &track.Allocation
+		b = binary.AppendUvarint(b, uint64(allocation.PauseReason))
+		b = appendBool(b, allocation.IsDeficient)
+		b = binary.AppendVarint(b, allocation.BandwidthRequested)
+		b = binary.AppendVarint(b, allocation.BandwidthDelta)
+		b = binary.AppendVarint(b, allocation.BandwidthNeeded)
+		b = appendBitrates(b, allocation.Bitrates)
+		b = appendLayer(b, allocation.TargetLayer)
+		b = binary.AppendVarint(b, int64(allocation.RequestLayerSpatial))
+		b = appendLayer(b, allocation.MaxLayer)
+		b = binary.AppendUvarint(b, math.Float64bits(allocation.DistanceToDesired))
+	}
+	return b
+}
+
+type decoder struct {
+	reader *bytes.Reader
+	err    error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, err := binary.ReadUvarint(d.reader)
+	if err != nil {
+		d.err = err
+	}
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, err := binary.ReadVarint(d.reader)
+	if err != nil {
+		d.err = err
+	}
+	return v
+}
+
+func (d *decoder) bool() bool {
+	if d.err != nil {
+		return false
+	}
+
+	v, err := d.reader.ReadByte()
+	if err != nil {
+		d.err = err
+	}
+	return v != 0
+}
+
+func (d *decoder) string() string {
+	length := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if length > uint64(d.reader.Len()) {
+		d.err = ErrMalformedRecord
+		return ""
+	}
+
+	v := make([]byte, length)
+	if _, err := io.ReadFull(d.reader, v); err != nil {
+		d.err = err
+	}
+	return string(v)
+}
+
+func (d *decoder) layer() buffer.VideoLayer {
+	return buffer.VideoLayer{
+		Spatial:  int32(d.varint()),
+		Temporal: int32(d.varint()),
+	}
+}
+
+func (d *decoder) bitrates() sfu.Bitrates {
+	var brs sfu.Bitrates
+	for s := range brs {
+		for t := range brs[s] {
+			brs[s][t] = d.varint()
+		}
+	}
+	return brs
+}
+
+func (d *decoder) recordedTracks() []RecordedTrack {
+	numTracks := d.uvarint()
+	if d.err != nil || numTracks > uint64(d.reader.Len()) {
+		d.err = ErrMalformedRecord
+		return nil
+	}
+
+	tracks := make([]RecordedTrack, 0, numTracks)
+	for i := uint64(0); i < numTracks && d.err == nil; i++ {
+		track := RecordedTrack{
+			TrackID:   livekit.TrackID(d.string()),
+			IsManaged: d.bool(),
+		}
+
+		snapshot := &track.Snapshot
+		snapshot.MimeType = d.string()
+		numAvailableLayers := d.uvarint()
+		if numAvailableLayers > uint64(d.reader.Len()) {
+			d.err = ErrMalformedRecord
+			return nil
+		}
+		for j := uint64(0); j < numAvailableLayers; j++ {
+			snapshot.AvailableLayers = append(snapshot.AvailableLayers, int32(d.varint()))
+		}
+		snapshot.Bitrates = d.bitrates()
+		snapshot.MaxLayer = d.layer()
+		snapshot.MaxSeenLayer = d.layer()
+		snapshot.CurrentLayer = d.layer()
+		snapshot.TargetLayer = d.layer()
+		snapshot.RequestSpatial = int32(d.varint())
+		snapshot.Muted = d.bool()
+		snapshot.PubMuted = d.bool()
+		snapshot.PubSilent = d.bool()
+		snapshot.Moderated = d.bool()
+		snapshot.MaxBitrate = d.varint()
+		snapshot.BandwidthRequested = d.varint()
+
+		allocation := &track.Allocation
+		allocation.PauseReason = sfu.VideoPauseReason(d.uvarint())
+		allocation.IsDeficient = d.bool()
+		allocation.BandwidthRequested = d.varint()
+		allocation.BandwidthDelta = d.varint()
+		allocation.BandwidthNeeded = d.varint()
+		allocation.Bitrates = d.bitrates()
+		allocation.TargetLayer = d.layer()
+		allocation.RequestLayerSpatial = int32(d.varint())
+		allocation.MaxLayer = d.layer()
+		allocation.DistanceToDesired = math.Float64frombits(d.uvarint())
+
+		tracks = append(tracks, track)
+	}
+	return tracks
+}
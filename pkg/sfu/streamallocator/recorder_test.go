@@ -0,0 +1,237 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamallocator
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+type recordingBuffer struct {
+	bytes.Buffer
+}
+
+func (r *recordingBuffer) Close() error {
+	return nil
+}
+
+func newTestRecorder(t *testing.T, maxBytes int64, maxRecordsPerSecond int) (*AllocationRecorder, *recordingBuffer) {
+	buf := &recordingBuffer{}
+	recorder, err := NewAllocationRecorder(AllocationRecorderParams{
+		Writer:              buf,
+		MaxBytes:            maxBytes,
+		MaxRecordsPerSecond: maxRecordsPerSecond,
+	})
+	require.NoError(t, err)
+	return recorder, buf
+}
+
+func testSnapshot() sfu.AllocationSnapshot {
+	return sfu.AllocationSnapshot{
+		MimeType:        "video/VP8",
+		AvailableLayers: []int32{0, 1, 2},
+		Bitrates: sfu.Bitrates{
+			{100_000, 150_000, 200_000, 0},
+			{300_000, 450_000, 600_000, 0},
+			{1_000_000, 1_500_000, 2_000_000, 0},
+		},
+		MaxLayer:       buffer.VideoLayer{Spatial: 2, Temporal: 2},
+		MaxSeenLayer:   buffer.VideoLayer{Spatial: 2, Temporal: 2},
+		CurrentLayer:   buffer.InvalidLayer,
+		TargetLayer:    buffer.InvalidLayer,
+		RequestSpatial: buffer.InvalidLayerSpatial,
+	}
+}
+
+func readAll(t *testing.T, r io.Reader) []*Record {
+	reader, err := NewAllocationRecordReader(r)
+	require.NoError(t, err)
+
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestAllocationRecorder(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		recorder, buf := newTestRecorder(t, 0, 0)
+
+		track := RecordedTrack{
+			TrackID:   "TR_video",
+			IsManaged: true,
+			Snapshot:  testSnapshot(),
+			Allocation: sfu.VideoAllocation{
+				PauseReason:         sfu.VideoPauseReasonNone,
+				IsDeficient:         true,
+				BandwidthRequested:  300_000,
+				BandwidthDelta:      -1_700_000,
+				BandwidthNeeded:     2_000_000,
+				Bitrates:            testSnapshot().Bitrates,
+				TargetLayer:         buffer.VideoLayer{Spatial: 1, Temporal: 0},
+				RequestLayerSpatial: 1,
+				MaxLayer:            buffer.VideoLayer{Spatial: 2, Temporal: 2},
+				DistanceToDesired:   2.5,
+			},
+		}
+		recorder.RecordEstimate(1_000_000, 900_000)
+		recorder.RecordProbeStart(500_000, 800_000)
+		recorder.RecordProbeDone(true, false, 1_200_000, 1_200_000)
+		recorder.RecordAllocateAll(1_200_000, true, true, []RecordedTrack{track})
+		recorder.RecordAllocateTrack("optimal", track)
+		require.NoError(t, recorder.Close())
+
+		records := readAll(t, buf)
+		require.Len(t, records, 5)
+
+		require.Equal(t, RecordTypeEstimate, records[0].Type)
+		require.Equal(t, int64(1_000_000), records[0].ReceivedEstimate)
+		require.Equal(t, int64(900_000), records[0].CommittedChannelCapacity)
+
+		require.Equal(t, RecordTypeProbeStart, records[1].Type)
+		require.Equal(t, int64(500_000), records[1].ProbeGoalDelta)
+		require.Equal(t, int64(800_000), records[1].ExpectedBandwidthUsed)
+
+		require.Equal(t, RecordTypeProbeDone, records[2].Type)
+		require.True(t, records[2].IsProbeNotFailing)
+		require.False(t, records[2].IsProbeGoalReached)
+		require.Equal(t, int64(1_200_000), records[2].HighestProbeEstimate)
+
+		require.Equal(t, RecordTypeAllocateAll, records[3].Type)
+		require.Equal(t, int64(1_200_000), records[3].AvailableChannelCapacity)
+		require.True(t, records[3].AllowPause)
+		require.True(t, records[3].EstimateUnmanagedTracks)
+		require.Equal(t, []RecordedTrack{track}, records[3].Tracks)
+
+		require.Equal(t, RecordTypeAllocateTrack, records[4].Type)
+		require.Equal(t, "optimal", records[4].Reason)
+		require.Equal(t, []RecordedTrack{track}, records[4].Tracks)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		recorder, buf := newTestRecorder(t, 64, 0)
+		for i := 0; i < 100; i++ {
+			recorder.RecordEstimate(int64(i)*1_000_000, 0)
+		}
+		require.NoError(t, recorder.Close())
+
+		require.LessOrEqual(t, buf.Len(), 64)
+		records := readAll(t, bytes.NewReader(buf.Bytes()))
+		require.NotEmpty(t, records)
+		require.Less(t, len(records), 100)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		recorder, buf := newTestRecorder(t, 0, 10)
+		for i := 0; i < 100; i++ {
+			recorder.RecordEstimate(int64(i), 0)
+		}
+		require.NoError(t, recorder.Close())
+
+		require.Len(t, readAll(t, buf), 10)
+	})
+
+	t.Run("nil recorder", func(t *testing.T) {
+		var recorder *AllocationRecorder
+		recorder.RecordEstimate(1_000_000, 0)
+		require.NoError(t, recorder.Close())
+	})
+
+	t.Run("not a recording", func(t *testing.T) {
+		_, err := NewAllocationRecordReader(bytes.NewReader([]byte("garbage")))
+		require.ErrorIs(t, err, ErrNotARecording)
+	})
+}
+
+func TestReplay(t *testing.T) {
+	// decide an allocation pass with current logic and record it
+	var recordedTracks []RecordedTrack
+	var tracks []*replayTrack
+	for _, trackID := range []string{"TR_a", "TR_b"} {
+		recorded := RecordedTrack{
+			TrackID:   livekit.TrackID(trackID),
+			IsManaged: true,
+			Snapshot:  testSnapshot(),
+		}
+		recordedTracks = append(recordedTracks, recorded)
+		tracks = append(tracks, newReplayTrack(recorded, logger.GetLogger()))
+	}
+	allocations := allocateAll(tracks, 1_000_000, true, true)
+	for idx := range recordedTracks {
+		recordedTracks[idx].Allocation = allocations[idx]
+	}
+	// constrained, both tracks should be streaming at a lower layer
+	require.Equal(t, buffer.VideoLayer{Spatial: 1, Temporal: 1}, allocations[0].TargetLayer)
+	require.Equal(t, buffer.VideoLayer{Spatial: 1, Temporal: 1}, allocations[1].TargetLayer)
+
+	t.Run("unchanged", func(t *testing.T) {
+		recorder, buf := newTestRecorder(t, 0, 0)
+		recorder.RecordEstimate(1_000_000, 1_000_000)
+		recorder.RecordAllocateAll(1_000_000, true, true, recordedTracks)
+		recorder.RecordAllocateTrack("boost", recordedTracks[0])
+		require.NoError(t, recorder.Close())
+
+		result, err := Replay(buf, logger.GetLogger())
+		require.NoError(t, err)
+		require.Equal(t, 1, result.NumEstimates)
+		require.Equal(t, 1, result.NumNotReplayed)
+		require.Len(t, result.Allocations, 1)
+		require.Len(t, result.Allocations[0].Tracks, 2)
+		require.Zero(t, result.NumChanged())
+		require.False(t, result.IsTruncated)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		changedTracks := make([]RecordedTrack, len(recordedTracks))
+		copy(changedTracks, recordedTracks)
+		changedTracks[1].Allocation.TargetLayer = buffer.VideoLayer{Spatial: 2, Temporal: 2}
+
+		recorder, buf := newTestRecorder(t, 0, 0)
+		recorder.RecordAllocateAll(1_000_000, true, true, changedTracks)
+		require.NoError(t, recorder.Close())
+
+		result, err := Replay(buf, logger.GetLogger())
+		require.NoError(t, err)
+		require.Equal(t, 1, result.NumChanged())
+		require.False(t, result.Allocations[0].Tracks[0].IsChanged())
+		require.True(t, result.Allocations[0].Tracks[1].IsChanged())
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		recorder, buf := newTestRecorder(t, 0, 0)
+		recorder.RecordAllocateAll(1_000_000, true, true, recordedTracks)
+		recorder.RecordAllocateAll(1_000_000, true, true, recordedTracks)
+		require.NoError(t, recorder.Close())
+
+		result, err := Replay(bytes.NewReader(buf.Bytes()[:buf.Len()-10]), logger.GetLogger())
+		require.NoError(t, err)
+		require.True(t, result.IsTruncated)
+		require.Len(t, result.Allocations, 1)
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamallocator
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+type ReplayedTrack struct {
+	TrackID  livekit.TrackID
+	Recorded sfu.VideoAllocation
+	Replayed sfu.VideoAllocation
+}
+
+// IsChanged returns true if current allocation logic decides differently from the recording
+func (r *ReplayedTrack) IsChanged() bool {
+	return r.Recorded.PauseReason != r.Replayed.PauseReason ||
+		r.Recorded.TargetLayer != r.Replayed.TargetLayer ||
+		r.Recorded.BandwidthRequested != r.Replayed.BandwidthRequested
+}
+
+type ReplayedAllocation struct {
+	At                       time.Duration
+	Type                     RecordType
+	AvailableChannelCapacity int64
+	Tracks                   []ReplayedTrack
+}
+
+type ReplayResult struct {
+	NumEstimates int
+	NumProbes    int
+	// track allocations which depend on provisional state of other tracks cannot be replayed in isolation
+	NumNotReplayed int
+	Allocations    []ReplayedAllocation
+	// recording ended in a partially written record
+	IsTruncated bool
+}
+
+func (r *ReplayResult) NumChanged() int {
+	numChanged := 0
+	for _, allocation := range r.Allocations {
+		for idx := range allocation.Tracks {
+			if allocation.Tracks[idx].IsChanged() {
+				numChanged++
+			}
+		}
+	}
+	return numChanged
+}
+
+// Replay re-runs recorded allocation passes and optimal track allocations with current allocation logic.
+// Each allocation is replayed against forwarders restored from the state captured when the decision was made,
+// so that decisions can be compared independent of how earlier decisions changed.
+func Replay(reader io.Reader, logger logger.Logger) (*ReplayResult, error) {
+	recordReader, err := NewAllocationRecordReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{}
+	for {
+		record, err := recordReader.Next()
+		if err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				result.IsTruncated = true
+				return result, nil
+			}
+			if errors.Is(err, io.EOF) {
+				return result, nil
+			}
+			return result, err
+		}
+
+		switch record.Type {
+		case RecordTypeEstimate:
+			result.NumEstimates++
+
+		case RecordTypeProbeStart:
+			result.NumProbes++
+
+		case RecordTypeAllocateAll:
+			result.Allocations = append(result.Allocations, replayAllocateAll(record, logger))
+
+		case RecordTypeAllocateTrack:
+			if record.Reason != "optimal" || len(record.Tracks) != 1 {
+				result.NumNotReplayed++
+				continue
+			}
+
+			recorded := record.Tracks[0]
+			track := newReplayTrack(recorded, logger)
+			result.Allocations = append(result.Allocations, ReplayedAllocation{
+				At:   record.At,
+				Type: record.Type,
+				Tracks: []ReplayedTrack{
+					{
+						TrackID:  recorded.TrackID,
+						Recorded: recorded.Allocation,
+						Replayed: track.AllocateOptimal(FlagAllowOvershootWhileOptimal),
+					},
+				},
+			})
+		}
+	}
+}
+
+func replayAllocateAll(record *Record, logger logger.Logger) ReplayedAllocation {
+	tracks := make([]*replayTrack, 0, len(record.Tracks))
+	for _, recorded := range record.Tracks {
+		tracks = append(tracks, newReplayTrack(recorded, logger))
+	}
+
+	allocations := allocateAll(tracks, record.AvailableChannelCapacity, record.AllowPause, record.EstimateUnmanagedTracks)
+
+	replayed := ReplayedAllocation{
+		At:                       record.At,
+		Type:                     record.Type,
+		AvailableChannelCapacity: record.AvailableChannelCapacity,
+		Tracks:                   make([]ReplayedTrack, 0, len(record.Tracks)),
+	}
+	for idx, recorded := range record.Tracks {
+		replayed.Tracks = append(replayed.Tracks, ReplayedTrack{
+			TrackID:  recorded.TrackID,
+			Recorded: recorded.Allocation,
+			Replayed: allocations[idx],
+		})
+	}
+	return replayed
+}
+
+// ---------------------------------------------------------------------------
+
+type replayTrack struct {
+	isManaged bool
+	snapshot  sfu.AllocationSnapshot
+	forwarder *sfu.Forwarder
+}
+
+func newReplayTrack(recorded RecordedTrack, logger logger.Logger) *replayTrack {
+	return &replayTrack{
+		isManaged: recorded.IsManaged,
+		snapshot:  recorded.Snapshot,
+		forwarder: sfu.NewForwarderFromAllocationSnapshot(recorded.Snapshot, logger),
+	}
+}
+
+func (r *replayTrack) IsManaged() bool {
+	return r.isManaged
+}
+
+func (r *replayTrack) AllocateOptimal(allowOvershoot bool) sfu.VideoAllocation {
+	return r.forwarder.AllocateOptimal(r.snapshot.AvailableLayers, r.snapshot.Bitrates, allowOvershoot)
+}
+
+func (r *replayTrack) Pause() sfu.VideoAllocation {
+	return r.forwarder.Pause(r.snapshot.AvailableLayers, r.snapshot.Bitrates)
+}
+
+func (r *replayTrack) ProvisionalAllocatePrepare() {
+	r.forwarder.ProvisionalAllocatePrepare(r.snapshot.AvailableLayers, r.snapshot.Bitrates)
+}
+
+func (r *replayTrack) ProvisionalAllocate(availableChannelCapacity int64, layer buffer.VideoLayer, allowPause bool, allowOvershoot bool) (bool, int64) {
+	return r.forwarder.ProvisionalAllocate(availableChannelCapacity, layer, allowPause, allowOvershoot)
+}
+
+func (r *replayTrack) ProvisionalAllocateCommit() sfu.VideoAllocation {
+	return r.forwarder.ProvisionalAllocateCommit()
+}
@@ -85,6 +85,8 @@ const (
 	streamAllocatorSignalResume
 	streamAllocatorSignalSetAllowPause
 	streamAllocatorSignalSetChannelCapacity
+	streamAllocatorSignalAllocateDirtyTracks
+	streamAllocatorSignalSeedChannelCapacity
 	// STREAM-ALLOCATOR-DATA streamAllocatorSignalNACK
 	// STREAM-ALLOCATOR-DATA streamAllocatorSignalRTCPReceiverReport
 )
@@ -111,6 +113,10 @@ func (s streamAllocatorSignal) String() string {
 		return "SET_ALLOW_PAUSE"
 	case streamAllocatorSignalSetChannelCapacity:
 		return "SET_CHANNEL_CAPACITY"
+	case streamAllocatorSignalAllocateDirtyTracks:
+		return "ALLOCATE_DIRTY_TRACKS"
+	case streamAllocatorSignalSeedChannelCapacity:
+		return "SEED_CHANNEL_CAPACITY"
 		/* STREAM-ALLOCATOR-DATA
 		case streamAllocatorSignalNACK:
 			return "NACK"
@@ -139,7 +145,9 @@ func (e Event) String() string {
 
 type StreamAllocatorParams struct {
 	Config config.CongestionControlConfig
-	Logger logger.Logger
+	// records allocation decisions when set, closed when the stream allocator is stopped
+	Recorder *AllocationRecorder
+	Logger   logger.Logger
 }
 
 type StreamAllocator struct {
@@ -154,6 +162,8 @@ type StreamAllocator struct {
 	lastReceivedEstimate      int64
 	committedChannelCapacity  int64
 	overriddenChannelCapacity int64
+	// mirrors lastReceivedEstimate for readers outside the event loop
+	receivedEstimate atomic.Int64
 
 	probeController *ProbeController
 
@@ -165,9 +175,13 @@ type StreamAllocator struct {
 	videoTracksMu        sync.RWMutex
 	videoTracks          map[livekit.TrackID]*Track
 	isAllocateAllPending bool
-	rembTrackingSSRC     uint32
+	// a debounced allocation of dirty tracks is scheduled
+	isAllocateDirtyPending bool
+	rembTrackingSSRC       uint32
 
 	state streamAllocatorState
+	// mirrors state for readers outside the event loop
+	isDeficient atomic.Bool
 
 	eventsQueue *utils.TypedOpsQueue[Event]
 
@@ -216,6 +230,10 @@ func (s *StreamAllocator) Stop() {
 	// wait for eventsQueue to be done
 	<-s.eventsQueue.Stop()
 	s.probeController.StopProbe()
+
+	if err := s.params.Recorder.Close(); err != nil {
+		s.params.Logger.Warnw("could not close allocation recorder", err)
+	}
 }
 
 func (s *StreamAllocator) OnStreamStateChange(f func(update *StreamStateUpdate) error) {
@@ -305,11 +323,32 @@ func (s *StreamAllocator) SetChannelCapacity(channelCapacity int64) {
 	})
 }
 
+// SeedChannelCapacity uses an externally provided estimate, for example a hint from the client,
+// as the starting channel capacity till the bandwidth estimator converges.
+// Unlike SetChannelCapacity, the seed is replaced by estimates on congestion or a successful probe.
+func (s *StreamAllocator) SeedChannelCapacity(channelCapacity int64) {
+	s.postEvent(Event{
+		Signal: streamAllocatorSignalSeedChannelCapacity,
+		Data:   channelCapacity,
+	})
+}
+
+// GetReceivedEstimate returns the last estimate received from the bandwidth estimator, 0 if none yet
+func (s *StreamAllocator) GetReceivedEstimate() int64 {
+	return s.receivedEstimate.Load()
+}
+
 func (s *StreamAllocator) resetState() {
 	s.channelObserver = s.newChannelObserverNonProbe()
 	s.probeController.Reset()
 
 	s.state = streamAllocatorStateStable
+	s.isDeficient.Store(false)
+}
+
+// IsDeficient returns true when some managed tracks are not getting their optimal allocation
+func (s *StreamAllocator) IsDeficient() bool {
+	return s.isDeficient.Load()
 }
 
 // called when a new REMB is received (receive side bandwidth estimation)
@@ -444,10 +483,7 @@ func (s *StreamAllocator) OnSubscribedLayerChanged(downTrack *sfu.DownTrack, lay
 	s.videoTracksMu.Unlock()
 
 	if shouldPost {
-		s.postEvent(Event{
-			Signal:  streamAllocatorSignalAllocateTrack,
-			TrackID: livekit.TrackID(downTrack.ID()),
-		})
+		s.postEventAllocateTrack(livekit.TrackID(downTrack.ID()))
 	}
 }
 
@@ -552,9 +588,32 @@ func (s *StreamAllocator) maybePostEventAllocateTrack(downTrack *sfu.DownTrack)
 	s.videoTracksMu.Unlock()
 
 	if shouldPost {
+		s.postEventAllocateTrack(livekit.TrackID(downTrack.ID()))
+	}
+}
+
+// postEventAllocateTrack allocates a dirty track, if debouncing is enabled,
+// all tracks which become dirty within the interval are allocated once when it expires
+func (s *StreamAllocator) postEventAllocateTrack(trackID livekit.TrackID) {
+	interval := s.params.Config.AllocationDebounceInterval
+	if interval <= 0 {
 		s.postEvent(Event{
 			Signal:  streamAllocatorSignalAllocateTrack,
-			TrackID: livekit.TrackID(downTrack.ID()),
+			TrackID: trackID,
+		})
+		return
+	}
+
+	s.videoTracksMu.Lock()
+	shouldSchedule := !s.isAllocateDirtyPending
+	s.isAllocateDirtyPending = true
+	s.videoTracksMu.Unlock()
+
+	if shouldSchedule {
+		time.AfterFunc(interval, func() {
+			s.postEvent(Event{
+				Signal: streamAllocatorSignalAllocateDirtyTracks,
+			})
 		})
 	}
 }
@@ -599,6 +658,10 @@ func (s *StreamAllocator) postEvent(event Event) {
 			event.handleSignalSetAllowPause(event)
 		case streamAllocatorSignalSetChannelCapacity:
 			event.handleSignalSetChannelCapacity(event)
+		case streamAllocatorSignalAllocateDirtyTracks:
+			event.handleSignalAllocateDirtyTracks(event)
+		case streamAllocatorSignalSeedChannelCapacity:
+			event.handleSignalSeedChannelCapacity(event)
 			/* STREAM-ALLOCATOR-DATA
 			case streamAllocatorSignalNACK:
 				event.s.handleSignalNACK(event)
@@ -622,6 +685,22 @@ func (s *StreamAllocator) handleSignalAllocateTrack(event Event) {
 	}
 }
 
+func (s *StreamAllocator) handleSignalAllocateDirtyTracks(Event) {
+	var tracks []*Track
+	s.videoTracksMu.Lock()
+	s.isAllocateDirtyPending = false
+	for _, track := range s.videoTracks {
+		if track.SetDirty(false) {
+			tracks = append(tracks, track)
+		}
+	}
+	s.videoTracksMu.Unlock()
+
+	for _, track := range tracks {
+		s.allocateTrack(track)
+	}
+}
+
 func (s *StreamAllocator) handleSignalAllocateAllTracks(Event) {
 	s.videoTracksMu.Lock()
 	s.isAllocateAllPending = false
@@ -639,6 +718,7 @@ func (s *StreamAllocator) handleSignalAdjustState(Event) {
 func (s *StreamAllocator) handleSignalEstimate(event Event) {
 	receivedEstimate, _ := event.Data.(int64)
 	s.lastReceivedEstimate = receivedEstimate
+	s.receivedEstimate.Store(receivedEstimate)
 	// s.monitorRate(receivedEstimate)
 
 	// while probing, maintain estimate separately to enable keeping current committed estimate if probe fails
@@ -647,6 +727,8 @@ func (s *StreamAllocator) handleSignalEstimate(event Event) {
 	} else {
 		s.handleNewEstimateInNonProbe()
 	}
+
+	s.params.Recorder.RecordEstimate(receivedEstimate, s.committedChannelCapacity)
 }
 
 func (s *StreamAllocator) handleSignalPeriodicPing(Event) {
@@ -722,6 +804,23 @@ func (s *StreamAllocator) handleSignalSetChannelCapacity(event Event) {
 	}
 }
 
+func (s *StreamAllocator) handleSignalSeedChannelCapacity(event Event) {
+	seed := event.Data.(int64)
+	s.params.Logger.Infow(
+		"stream allocator: seeding channel capacity",
+		"old(bps)", s.committedChannelCapacity,
+		"seed(bps)", seed,
+	)
+	s.committedChannelCapacity = seed
+
+	// start trend from seed, so that a lower estimate shows up as congestion
+	s.channelObserver = s.newChannelObserverNonProbe()
+	s.channelObserver.SeedEstimate(seed)
+	s.probeController.Reset()
+
+	s.allocateAllTracks()
+}
+
 /* STREAM-ALLOCATOR-DATA
 func (s *StreamAllocator) handleSignalNACK(event Event) {
 	nackInfos := event.Data.([]sfu.NackInfo)
@@ -755,6 +854,7 @@ func (s *StreamAllocator) setState(state streamAllocatorState) {
 
 	s.params.Logger.Infow("stream allocator: state change", "from", s.state, "to", state)
 	s.state = state
+	s.isDeficient.Store(state == streamAllocatorStateDeficient)
 
 	// reset probe to enforce a delay after state change before probing
 	s.probeController.Reset()
@@ -857,10 +957,13 @@ func (s *StreamAllocator) allocateTrack(track *Track) {
 	// abort any probe that may be running when a track specific change needs allocation
 	s.probeController.AbortProbe()
 
+	snapshot := s.getAllocationSnapshot(track)
+
 	// if not deficient, free pass allocate track
 	if !s.params.Config.Enabled || s.state == streamAllocatorStateStable || !track.IsManaged() {
 		update := NewStreamStateUpdate()
 		allocation := track.AllocateOptimal(FlagAllowOvershootWhileOptimal)
+		s.recordTrackAllocation("optimal", track, snapshot, allocation)
 		updateStreamStateChange(track, allocation, update)
 		s.maybeSendUpdate(update)
 		return
@@ -891,6 +994,7 @@ func (s *StreamAllocator) allocateTrack(track *Track) {
 	// downgrade, giving back bits
 	if transition.From.GreaterThan(transition.To) {
 		allocation := track.ProvisionalAllocateCommit()
+		s.recordTrackAllocation("cooperative", track, snapshot, allocation)
 
 		update := NewStreamStateUpdate()
 		updateStreamStateChange(track, allocation, update)
@@ -949,6 +1053,7 @@ func (s *StreamAllocator) allocateTrack(track *Track) {
 				// found layer that can fit in available headroom, take it if it is better than existing
 				update := NewStreamStateUpdate()
 				allocation := track.ProvisionalAllocateCommit()
+				s.recordTrackAllocation("headroom", track, snapshot, allocation)
 				updateStreamStateChange(track, allocation, update)
 				s.maybeSendUpdate(update)
 			}
@@ -966,7 +1071,14 @@ func (s *StreamAllocator) allocateTrack(track *Track) {
 	var contributingTracks []*Track
 
 	minDistanceSorted := s.getMinDistanceSorted(track)
+	var contributingSnapshots map[*Track]sfu.AllocationSnapshot
+	if s.params.Recorder != nil {
+		contributingSnapshots = make(map[*Track]sfu.AllocationSnapshot, len(minDistanceSorted))
+	}
 	for _, t := range minDistanceSorted {
+		if contributingSnapshots != nil {
+			contributingSnapshots[t] = t.GetAllocationSnapshot()
+		}
 		t.ProvisionalAllocatePrepare()
 	}
 
@@ -987,6 +1099,7 @@ func (s *StreamAllocator) allocateTrack(track *Track) {
 		// commit the tracks that contributed
 		for _, t := range contributingTracks {
 			allocation := t.ProvisionalAllocateCommit()
+			s.recordTrackAllocation("contribute", t, contributingSnapshots[t], allocation)
 			updateStreamStateChange(t, allocation, update)
 		}
 
@@ -996,10 +1109,12 @@ func (s *StreamAllocator) allocateTrack(track *Track) {
 	// commit the track that needs change if enough could be acquired or pause not allowed
 	if !s.allowPause || bandwidthAcquired >= transition.BandwidthDelta {
 		allocation := track.ProvisionalAllocateCommit()
+		s.recordTrackAllocation("redistribute", track, snapshot, allocation)
 		updateStreamStateChange(track, allocation, update)
 	} else {
 		// explicitly pause to ensure stream state update happens if a track coming out of mute cannot be allocated
 		allocation := track.Pause()
+		s.recordTrackAllocation("pause", track, snapshot, allocation)
 		updateStreamStateChange(track, allocation, update)
 	}
 
@@ -1033,12 +1148,14 @@ func (s *StreamAllocator) onProbeDone(isNotFailing bool, isGoalReached bool) {
 		"channel", channelObserverString,
 	)
 	if !isNotFailing {
+		s.params.Recorder.RecordProbeDone(isNotFailing, isGoalReached, highestEstimateInProbe, s.committedChannelCapacity)
 		return
 	}
 
 	if highestEstimateInProbe > s.committedChannelCapacity {
 		s.committedChannelCapacity = highestEstimateInProbe
 	}
+	s.params.Recorder.RecordProbeDone(isNotFailing, isGoalReached, highestEstimateInProbe, s.committedChannelCapacity)
 
 	s.maybeBoostDeficientTracks()
 }
@@ -1055,6 +1172,7 @@ func (s *StreamAllocator) maybeBoostDeficientTracks() {
 boost_loop:
 	for {
 		for idx, track := range sortedTracks {
+			snapshot := s.getAllocationSnapshot(track)
 			allocation, boosted := track.AllocateNextHigher(availableChannelCapacity, FlagAllowOvershootInCatchup)
 			if !boosted {
 				if idx == len(sortedTracks)-1 {
@@ -1064,6 +1182,7 @@ boost_loop:
 				continue
 			}
 
+			s.recordTrackAllocation("boost", track, snapshot, allocation)
 			updateStreamStateChange(track, allocation, update)
 
 			availableChannelCapacity -= allocation.BandwidthDelta
@@ -1102,74 +1221,134 @@ func (s *StreamAllocator) allocateAllTracks() {
 	//
 	// If there is not enough bandwidth even for the lowest layer, tracks at lower priorities will be paused.
 	//
+	availableChannelCapacity := s.getAvailableChannelCapacity(true)
+
+	// unmanaged tracks first followed by managed tracks in the order they should get allocation
+	var tracks []*Track
+	for _, track := range s.getTracks() {
+		if !track.IsManaged() {
+			tracks = append(tracks, track)
+		}
+	}
+	tracks = append(tracks, s.getSorted()...)
+
+	var recordedTracks []RecordedTrack
+	if s.params.Recorder != nil {
+		recordedTracks = make([]RecordedTrack, 0, len(tracks))
+		for _, track := range tracks {
+			recordedTracks = append(recordedTracks, RecordedTrack{
+				TrackID:   track.ID(),
+				IsManaged: track.IsManaged(),
+				Snapshot:  track.GetAllocationSnapshot(),
+			})
+		}
+	}
+
+	estimateUnmanagedTracks := !s.params.Config.DisableEstimationUnmanagedTracks
+	allocations := allocateAll(tracks, availableChannelCapacity, s.allowPause, estimateUnmanagedTracks)
+
 	update := NewStreamStateUpdate()
+	for idx, track := range tracks {
+		updateStreamStateChange(track, allocations[idx], update)
+	}
 
-	availableChannelCapacity := s.getAvailableChannelCapacity(true)
+	if recordedTracks != nil {
+		for idx := range recordedTracks {
+			recordedTracks[idx].Allocation = allocations[idx]
+		}
+		s.params.Recorder.RecordAllocateAll(availableChannelCapacity, s.allowPause, estimateUnmanagedTracks, recordedTracks)
+	}
+
+	s.maybeSendUpdate(update)
+
+	s.adjustState()
+}
+
+// allocationTrack is what allocateAll needs of a track, implemented by Track and by tracks restored for replay
+type allocationTrack interface {
+	IsManaged() bool
+	AllocateOptimal(allowOvershoot bool) sfu.VideoAllocation
+	Pause() sfu.VideoAllocation
+	ProvisionalAllocatePrepare()
+	ProvisionalAllocate(availableChannelCapacity int64, layer buffer.VideoLayer, allowPause bool, allowOvershoot bool) (bool, int64)
+	ProvisionalAllocateCommit() sfu.VideoAllocation
+}
+
+// allocateAll distributes available channel capacity among tracks. Managed tracks are expected
+// in the order they should get allocation. Returns allocation of each track, in order of tracks.
+func allocateAll[T allocationTrack](
+	tracks []T,
+	availableChannelCapacity int64,
+	allowPause bool,
+	estimateUnmanagedTracks bool,
+) []sfu.VideoAllocation {
+	allocations := make([]sfu.VideoAllocation, len(tracks))
 
 	//
 	// This pass is to find out if there is any leftover channel capacity after allocating exempt tracks.
 	// Exempt tracks are given optimal allocation (i. e. no bandwidth constraint) so that they do not fail allocation.
 	//
-	videoTracks := s.getTracks()
-	for _, track := range videoTracks {
+	for idx, track := range tracks {
 		if track.IsManaged() {
 			continue
 		}
 
-		allocation := track.AllocateOptimal(FlagAllowOvershootExemptTrackWhileDeficient)
-		updateStreamStateChange(track, allocation, update)
+		allocations[idx] = track.AllocateOptimal(FlagAllowOvershootExemptTrackWhileDeficient)
 
 		// STREAM-ALLOCATOR-TODO: optimistic allocation before bitrate is available will return 0. How to account for that?
-		if !s.params.Config.DisableEstimationUnmanagedTracks {
-			availableChannelCapacity -= allocation.BandwidthRequested
+		if estimateUnmanagedTracks {
+			availableChannelCapacity -= allocations[idx].BandwidthRequested
 		}
 	}
 
 	if availableChannelCapacity < 0 {
 		availableChannelCapacity = 0
 	}
-	if availableChannelCapacity == 0 && s.allowPause {
+	if availableChannelCapacity == 0 && allowPause {
 		// nothing left for managed tracks, pause them all
-		for _, track := range videoTracks {
+		for idx, track := range tracks {
 			if !track.IsManaged() {
 				continue
 			}
 
-			allocation := track.Pause()
-			updateStreamStateChange(track, allocation, update)
+			allocations[idx] = track.Pause()
 		}
-	} else {
-		sorted := s.getSorted()
-		for _, track := range sorted {
+		return allocations
+	}
+
+	for _, track := range tracks {
+		if track.IsManaged() {
 			track.ProvisionalAllocatePrepare()
 		}
+	}
 
-		for spatial := int32(0); spatial <= buffer.DefaultMaxLayerSpatial; spatial++ {
-			for temporal := int32(0); temporal <= buffer.DefaultMaxLayerTemporal; temporal++ {
-				layer := buffer.VideoLayer{
-					Spatial:  spatial,
-					Temporal: temporal,
+	for spatial := int32(0); spatial <= buffer.DefaultMaxLayerSpatial; spatial++ {
+		for temporal := int32(0); temporal <= buffer.DefaultMaxLayerTemporal; temporal++ {
+			layer := buffer.VideoLayer{
+				Spatial:  spatial,
+				Temporal: temporal,
+			}
+
+			for _, track := range tracks {
+				if !track.IsManaged() {
+					continue
 				}
 
-				for _, track := range sorted {
-					_, usedChannelCapacity := track.ProvisionalAllocate(availableChannelCapacity, layer, s.allowPause, FlagAllowOvershootWhileDeficient)
-					availableChannelCapacity -= usedChannelCapacity
-					if availableChannelCapacity < 0 {
-						availableChannelCapacity = 0
-					}
+				_, usedChannelCapacity := track.ProvisionalAllocate(availableChannelCapacity, layer, allowPause, FlagAllowOvershootWhileDeficient)
+				availableChannelCapacity -= usedChannelCapacity
+				if availableChannelCapacity < 0 {
+					availableChannelCapacity = 0
 				}
 			}
 		}
+	}
 
-		for _, track := range sorted {
-			allocation := track.ProvisionalAllocateCommit()
-			updateStreamStateChange(track, allocation, update)
+	for idx, track := range tracks {
+		if track.IsManaged() {
+			allocations[idx] = track.ProvisionalAllocateCommit()
 		}
 	}
-
-	s.maybeSendUpdate(update)
-
-	s.adjustState()
+	return allocations
 }
 
 func (s *StreamAllocator) maybeSendUpdate(update *StreamStateUpdate) {
@@ -1192,6 +1371,27 @@ func (s *StreamAllocator) maybeSendUpdate(update *StreamStateUpdate) {
 	}
 }
 
+func (s *StreamAllocator) getAllocationSnapshot(track *Track) sfu.AllocationSnapshot {
+	if s.params.Recorder == nil {
+		return sfu.AllocationSnapshot{}
+	}
+
+	return track.GetAllocationSnapshot()
+}
+
+func (s *StreamAllocator) recordTrackAllocation(reason string, track *Track, snapshot sfu.AllocationSnapshot, allocation sfu.VideoAllocation) {
+	if s.params.Recorder == nil {
+		return
+	}
+
+	s.params.Recorder.RecordAllocateTrack(reason, RecordedTrack{
+		TrackID:    track.ID(),
+		IsManaged:  track.IsManaged(),
+		Snapshot:   snapshot,
+		Allocation: allocation,
+	})
+}
+
 func (s *StreamAllocator) getAvailableChannelCapacity(allowOverride bool) int64 {
 	availableChannelCapacity := s.committedChannelCapacity
 	if s.params.Config.MinChannelCapacity > availableChannelCapacity {
@@ -1292,6 +1492,8 @@ func (s *StreamAllocator) initProbe(probeGoalDeltaBps int64) {
 	s.channelObserver = s.newChannelObserverProbe()
 	s.channelObserver.SeedEstimate(s.lastReceivedEstimate)
 
+	s.params.Recorder.RecordProbeStart(probeGoalDeltaBps, expectedBandwidthUsage)
+
 	s.params.Logger.Debugw(
 		"stream allocator: starting probe",
 		"probeClusterId", probeClusterId,
@@ -1472,7 +1674,7 @@ func updateStreamStateChange(track *Track, allocation sfu.VideoAllocation, updat
 		streamState = StreamStateInactive
 		updated = track.SetStreamState(streamState)
 
-	case sfu.VideoPauseReasonBandwidth:
+	case sfu.VideoPauseReasonPubSilent, sfu.VideoPauseReasonBandwidth, sfu.VideoPauseReasonModerated, sfu.VideoPauseReasonStartPaused:
 		streamState = StreamStatePaused
 		updated = track.SetStreamState(streamState)
 	}
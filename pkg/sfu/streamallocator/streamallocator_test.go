@@ -0,0 +1,147 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamallocator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+// allocationTestReceiver is the minimal receiver needed to allocate a down track
+type allocationTestReceiver struct {
+	sfu.TrackReceiver
+}
+
+func (r *allocationTestReceiver) TrackID() livekit.TrackID                { return "video" }
+func (r *allocationTestReceiver) DeleteDownTrack(_ livekit.ParticipantID) {}
+func (r *allocationTestReceiver) SendPLI(_ int32, _ bool)                 {}
+func (r *allocationTestReceiver) GetLayeredBitrate() ([]int32, sfu.Bitrates) {
+	return nil, sfu.Bitrates{}
+}
+
+func newDownTrackForAllocationTest(t *testing.T) *sfu.DownTrack {
+	dt, err := sfu.NewDownTrack(sfu.DowntrackParams{
+		Codecs: []webrtc.RTPCodecParameters{
+			{RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8, ClockRate: 90000}},
+		},
+		Receiver: &allocationTestReceiver{},
+		SubID:    "sub",
+		Logger:   logger.GetLogger(),
+	})
+	require.NoError(t, err)
+	t.Cleanup(dt.Close)
+
+	return dt
+}
+
+func TestAllocationDebounce(t *testing.T) {
+	t.Run("track changes are coalesced", func(t *testing.T) {
+		s := NewStreamAllocator(StreamAllocatorParams{
+			Config: config.CongestionControlConfig{
+				AllocationDebounceInterval: 100 * time.Millisecond,
+			},
+			Logger: logger.GetLogger(),
+		})
+		s.Start()
+		defer s.Stop()
+
+		dt := newDownTrackForAllocationTest(t)
+		s.AddTrack(dt, AddTrackParams{Source: livekit.TrackSource_CAMERA, IsSimulcast: true})
+		dt.SetModerated(true)
+		for i := 0; i < 20; i++ {
+			s.OnAvailableLayersChanged(dt)
+			s.OnBitrateAvailabilityChanged(dt)
+			s.OnSubscriptionChanged(dt)
+		}
+
+		// not allocated within the interval
+		require.NotEqual(t, sfu.VideoPauseReasonModerated, dt.PauseReason())
+		require.Eventually(t, func() bool {
+			return dt.PauseReason() == sfu.VideoPauseReasonModerated
+		}, time.Second, 10*time.Millisecond)
+
+		// changes after the allocation are allocated again, with the latest state of the track
+		dt.SetModerated(false)
+		dt.SetStartPaused(true)
+		require.Equal(t, sfu.VideoPauseReasonModerated, dt.PauseReason())
+		require.Eventually(t, func() bool {
+			return dt.PauseReason() == sfu.VideoPauseReasonStartPaused
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("channel capacity change is not delayed", func(t *testing.T) {
+		s := NewStreamAllocator(StreamAllocatorParams{
+			Config: config.CongestionControlConfig{
+				Enabled:                    true,
+				AllocationDebounceInterval: time.Hour,
+			},
+			Logger: logger.GetLogger(),
+		})
+		s.Start()
+		defer s.Stop()
+
+		dt := newDownTrackForAllocationTest(t)
+		s.AddTrack(dt, AddTrackParams{Source: livekit.TrackSource_CAMERA, IsSimulcast: true})
+		dt.SetModerated(true)
+		s.SetChannelCapacity(1_000_000)
+
+		require.Eventually(t, func() bool {
+			return dt.PauseReason() == sfu.VideoPauseReasonModerated
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestSeedChannelCapacity(t *testing.T) {
+	s := NewStreamAllocator(StreamAllocatorParams{
+		Config: config.CongestionControlConfig{
+			Enabled:                    true,
+			AllocationDebounceInterval: time.Hour,
+		},
+		Logger: logger.GetLogger(),
+	})
+	s.Start()
+	defer s.Stop()
+
+	dt := newDownTrackForAllocationTest(t)
+	s.AddTrack(dt, AddTrackParams{Source: livekit.TrackSource_CAMERA, IsSimulcast: true})
+	dt.SetModerated(true)
+	require.Zero(t, s.GetReceivedEstimate())
+
+	// seeding allocates against the seed right away
+	s.SeedChannelCapacity(1_000_000)
+	require.Eventually(t, func() bool {
+		return dt.PauseReason() == sfu.VideoPauseReasonModerated
+	}, time.Second, 10*time.Millisecond)
+
+	// seed is not an estimate
+	require.Zero(t, s.GetReceivedEstimate())
+
+	s.postEvent(Event{
+		Signal: streamAllocatorSignalEstimate,
+		Data:   int64(500_000),
+	})
+	require.Eventually(t, func() bool {
+		return s.GetReceivedEstimate() == 500_000
+	}, time.Second, 10*time.Millisecond)
+}
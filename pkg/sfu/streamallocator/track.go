@@ -154,6 +154,10 @@ func (t *Track) WritePaddingRTP(bytesToSend int) int {
 	return t.downTrack.WritePaddingRTP(bytesToSend, false, false)
 }
 
+func (t *Track) GetAllocationSnapshot() sfu.AllocationSnapshot {
+	return t.downTrack.GetAllocationSnapshot()
+}
+
 func (t *Track) AllocateOptimal(allowOvershoot bool) sfu.VideoAllocation {
 	return t.downTrack.AllocateOptimal(allowOvershoot)
 }
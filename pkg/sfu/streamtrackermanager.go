@@ -346,6 +346,13 @@ func (s *StreamTrackerManager) SetMaxExpectedSpatialLayer(layer int32) int32 {
 	return prev
 }
 
+func (s *StreamTrackerManager) GetMaxExpectedSpatialLayer() int32 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.maxExpectedLayer
+}
+
 func (s *StreamTrackerManager) DistanceToDesired() float64 {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -540,6 +547,16 @@ func (s *StreamTrackerManager) maxExpectedLayerFromTrackInfo() {
 	}
 }
 
+// GetAvailableLayers returns spatial layers currently live, in ascending order
+func (s *StreamTrackerManager) GetAvailableLayers() []int32 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	availableLayers := make([]int32, len(s.availableLayers))
+	copy(availableLayers, s.availableLayers)
+	return availableLayers
+}
+
 func (s *StreamTrackerManager) GetMaxTemporalLayerSeen() int32 {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
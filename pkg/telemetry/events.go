@@ -16,8 +16,10 @@ package telemetry
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/pion/webrtc/v3"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
@@ -302,6 +304,61 @@ func (t *telemetryService) TrackSubscribeFailed(
 	})
 }
 
+// ParticipantRTCPWriteFailed is recorded as a metric labelled by the error category,
+// analytics events do not have a type for transport errors.
+func (t *telemetryService) ParticipantRTCPWriteFailed(
+	ctx context.Context,
+	participantID livekit.ParticipantID,
+	target livekit.SignalTarget,
+	category string,
+) {
+	t.enqueue(func() {
+		prometheus.IncrementRTCPWriteFailureStreak(strings.ToLower(target.String()), category)
+	})
+}
+
+// ParticipantHeartbeat is a compact snapshot of participant state sent periodically
+type ParticipantHeartbeat struct {
+	State               livekit.ParticipantInfo_State
+	MigrateState        string
+	PublisherState      webrtc.PeerConnectionState
+	SubscriberState     webrtc.PeerConnectionState
+	NumPublishedTracks  int
+	NumSubscribedTracks int
+	RTT                 uint32
+	Congested           bool
+}
+
+// ParticipantHeartbeat is logged as a single line, analytics events do not have a type for it.
+func (t *telemetryService) ParticipantHeartbeat(
+	ctx context.Context,
+	participantID livekit.ParticipantID,
+	identity livekit.ParticipantIdentity,
+	heartbeat *ParticipantHeartbeat,
+) {
+	t.enqueue(func() {
+		var roomID, roomName string
+		if room := t.getRoomDetails(participantID); room != nil {
+			roomID, roomName = room.Sid, room.Name
+		}
+		logger.Infow(
+			"participant heartbeat",
+			"room", roomName,
+			"roomID", roomID,
+			"participant", identity,
+			"pID", participantID,
+			"state", heartbeat.State,
+			"migrateState", heartbeat.MigrateState,
+			"publisherState", heartbeat.PublisherState.String(),
+			"subscriberState", heartbeat.SubscriberState.String(),
+			"numPublished", heartbeat.NumPublishedTracks,
+			"numSubscribed", heartbeat.NumSubscribedTracks,
+			"rtt", heartbeat.RTT,
+			"congested", heartbeat.Congested,
+		)
+	})
+}
+
 func (t *telemetryService) TrackUnsubscribed(
 	ctx context.Context,
 	participantID livekit.ParticipantID,
@@ -0,0 +1,45 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promBandwidthHintRatio prometheus.Histogram
+)
+
+func initBandwidthStats(nodeID string, nodeType livekit.NodeType) {
+	promBandwidthHintRatio = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "bandwidth_hint",
+		Name:        "estimate_ratio",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Buckets:     []float64{0.1, 0.25, 0.5, 0.75, 0.9, 1.1, 1.5, 2, 4, 10},
+	})
+
+	prometheus.MustRegister(promBandwidthHintRatio)
+}
+
+// RecordBandwidthHintDivergence records ratio of bandwidth estimate to the client provided hint it was seeded with
+func RecordBandwidthHintDivergence(hint int64, estimate int64) {
+	if hint <= 0 {
+		return
+	}
+	promBandwidthHintRatio.Observe(float64(estimate) / float64(hint))
+}
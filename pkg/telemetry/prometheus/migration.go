@@ -0,0 +1,69 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promMigrationCounter  *prometheus.CounterVec
+	promMigrationDuration prometheus.Histogram
+)
+
+func initMigrationStats(nodeID string, nodeType livekit.NodeType) {
+	promMigrationCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "participant_migration",
+		Name:        "total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, []string{"outcome"})
+
+	promMigrationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "participant_migration",
+		Name:        "duration_seconds",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Buckets:     []float64{0.1, 0.25, 0.5, 1, 2, 3, 5, 10, 20, 30},
+	})
+
+	prometheus.MustRegister(promMigrationCounter)
+	prometheus.MustRegister(promMigrationDuration)
+}
+
+// RecordMigrationStarted records a participant migrating out of this node
+func RecordMigrationStarted() {
+	promMigrationCounter.WithLabelValues("started").Add(1)
+}
+
+// RecordMigrationCompleted records a participant that migrated into this node reaching migration complete
+func RecordMigrationCompleted(d time.Duration) {
+	promMigrationCounter.WithLabelValues("completed").Add(1)
+	promMigrationDuration.Observe(d.Seconds())
+}
+
+// RecordMigrationTimedOut records subscriber peer connection closed by migration timer
+func RecordMigrationTimedOut() {
+	promMigrationCounter.WithLabelValues("timed_out").Add(1)
+}
+
+// RecordMigrationFailed records a migration which required a full reconnect
+func RecordMigrationFailed() {
+	promMigrationCounter.WithLabelValues("failed").Add(1)
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promNegotiations *prometheus.CounterVec
+)
+
+func initNegotiationStats(nodeID string, nodeType livekit.NodeType) {
+	promNegotiations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "negotiation",
+		Name:        "total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, []string{"transport", "cause", "outcome"})
+
+	prometheus.MustRegister(promNegotiations)
+}
+
+// RecordNegotiation counts pending negotiations of a transport by cause, once they reach an outcome
+func RecordNegotiation(transport livekit.SignalTarget, cause string, outcome string) {
+	promNegotiations.WithLabelValues(transport.String(), cause, outcome).Inc()
+}
@@ -112,8 +112,12 @@ func Init(nodeID string, nodeType livekit.NodeType) error {
 
 	initPacketStats(nodeID, nodeType)
 	initRoomStats(nodeID, nodeType)
+	initSignalStats(nodeID, nodeType)
+	initNegotiationStats(nodeID, nodeType)
 	rpc.InitPSRPCStats(prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()})
 	initQualityStats(nodeID, nodeType)
+	initBandwidthStats(nodeID, nodeType)
+	initMigrationStats(nodeID, nodeType)
 
 	var err error
 	cpuStats, err = hwstats.NewCPUStats(nil)
@@ -124,10 +128,10 @@ func Init(nodeID string, nodeType livekit.NodeType) error {
 	return nil
 }
 
-func GetUpdatedNodeStats(prev *livekit.NodeStats, prevAverage *livekit.NodeStats) (*livekit.NodeStats, bool, error) {
-	loadAvg, err := getLoadAvg()
-	if err != nil {
-		return nil, false, err
+// GetCPULoad returns CPU load of the node as a fraction, 0 if not available
+func GetCPULoad() float64 {
+	if cpuStats == nil {
+		return 0
 	}
 
 	var cpuLoad float64
@@ -135,6 +139,16 @@ func GetUpdatedNodeStats(prev *livekit.NodeStats, prevAverage *livekit.NodeStats
 	if cpuIdle > 0 {
 		cpuLoad = 1 - (cpuIdle / cpuStats.NumCPU())
 	}
+	return cpuLoad
+}
+
+func GetUpdatedNodeStats(prev *livekit.NodeStats, prevAverage *livekit.NodeStats) (*livekit.NodeStats, bool, error) {
+	loadAvg, err := getLoadAvg()
+	if err != nil {
+		return nil, false, err
+	}
+
+	cpuLoad := GetCPULoad()
 
 	// On MacOS, get "\"vm_stat\": executable file not found in $PATH" although it is in /usr/bin
 	// So, do not error out. Use the information if it is available.
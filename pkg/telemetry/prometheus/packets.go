@@ -57,6 +57,8 @@ var (
 	promParticipantJoin *prometheus.CounterVec
 	promConnections     *prometheus.GaugeVec
 
+	promRTCPWriteFailureStreak *prometheus.CounterVec
+
 	promPacketTotalIncomingInitial    prometheus.Counter
 	promPacketTotalIncomingRetransmit prometheus.Counter
 	promPacketTotalOutgoingInitial    prometheus.Counter
@@ -140,6 +142,13 @@ func initPacketStats(nodeID string, nodeType livekit.NodeType) {
 		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
 	}, []string{"kind"})
 
+	promRTCPWriteFailureStreak = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "rtcp_write_failure_streak",
+		Name:        "total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, []string{"target", "category"})
+
 	prometheus.MustRegister(promPacketTotal)
 	prometheus.MustRegister(promPacketBytes)
 	prometheus.MustRegister(promNackTotal)
@@ -151,6 +160,7 @@ func initPacketStats(nodeID string, nodeType livekit.NodeType) {
 	prometheus.MustRegister(promRTT)
 	prometheus.MustRegister(promParticipantJoin)
 	prometheus.MustRegister(promConnections)
+	prometheus.MustRegister(promRTCPWriteFailureStreak)
 
 	promPacketTotalIncomingInitial = promPacketTotal.WithLabelValues(string(Incoming), transmissionInitial)
 	promPacketTotalIncomingRetransmit = promPacketTotal.WithLabelValues(string(Incoming), transmissionRetransmit)
@@ -280,3 +290,7 @@ func AddConnection(direction Direction) {
 func SubConnection(direction Direction) {
 	promConnections.WithLabelValues(string(direction)).Sub(1)
 }
+
+func IncrementRTCPWriteFailureStreak(target string, category string) {
+	promRTCPWriteFailureStreak.WithLabelValues(target, category).Add(1)
+}
@@ -45,6 +45,9 @@ var (
 	promTrackPublishCounter    *prometheus.CounterVec
 	promTrackSubscribeCounter  *prometheus.CounterVec
 	promSessionStartTime       *prometheus.HistogramVec
+
+	promTrackSubscribers               prometheus.Gauge
+	promTrackSubscriberLimitRejections prometheus.Counter
 )
 
 func initRoomStats(nodeID string, nodeType livekit.NodeType) {
@@ -100,6 +103,18 @@ func initRoomStats(nodeID string, nodeType livekit.NodeType) {
 		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
 		Buckets:     prometheus.ExponentialBucketsRange(100, 10000, 15),
 	}, []string{"protocol_version"})
+	promTrackSubscribers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "track",
+		Name:        "limited_subscribers",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	})
+	promTrackSubscriberLimitRejections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "track",
+		Name:        "subscriber_limit_rejections",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	})
 
 	prometheus.MustRegister(promRoomCurrent)
 	prometheus.MustRegister(promRoomDuration)
@@ -109,6 +124,8 @@ func initRoomStats(nodeID string, nodeType livekit.NodeType) {
 	prometheus.MustRegister(promTrackPublishCounter)
 	prometheus.MustRegister(promTrackSubscribeCounter)
 	prometheus.MustRegister(promSessionStartTime)
+	prometheus.MustRegister(promTrackSubscribers)
+	prometheus.MustRegister(promTrackSubscriberLimitRejections)
 }
 
 func RoomStarted() {
@@ -183,6 +200,15 @@ func RecordTrackSubscribeFailure(err error, isUserError bool) {
 	}
 }
 
+// AddTrackSubscribers adds to the number of subscribers of tracks with a subscriber limit
+func AddTrackSubscribers(delta int) {
+	promTrackSubscribers.Add(float64(delta))
+}
+
+func RecordTrackSubscriberLimitRejection() {
+	promTrackSubscriberLimitRejections.Inc()
+}
+
 func RecordSessionStartTime(protocolVersion int, d time.Duration) {
 	promSessionStartTime.WithLabelValues(strconv.Itoa(protocolVersion)).Observe(float64(d.Milliseconds()))
 }
@@ -0,0 +1,69 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promSignalQueueDepth     prometheus.Gauge
+	promSignalRequestLatency *prometheus.HistogramVec
+	promSignalRequestDropped *prometheus.CounterVec
+)
+
+func initSignalStats(nodeID string, nodeType livekit.NodeType) {
+	promSignalQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "signal_queue",
+		Name:        "depth",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	})
+	promSignalRequestLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "signal_request",
+		Name:        "latency_ms",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Buckets:     []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000},
+	}, []string{"type"})
+	promSignalRequestDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "signal_request",
+		Name:        "dropped_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, []string{"type"})
+
+	prometheus.MustRegister(promSignalQueueDepth)
+	prometheus.MustRegister(promSignalRequestLatency)
+	prometheus.MustRegister(promSignalRequestDropped)
+}
+
+// AddSignalQueueDepth tracks number of signal requests queued across participants
+func AddSignalQueueDepth(delta int) {
+	promSignalQueueDepth.Add(float64(delta))
+}
+
+// RecordSignalRequestHandled records time from a request being queued to it being handled
+func RecordSignalRequestHandled(requestType string, latency time.Duration) {
+	promSignalRequestLatency.WithLabelValues(requestType).Observe(float64(latency.Milliseconds()))
+}
+
+func RecordSignalRequestDropped(requestType string) {
+	promSignalRequestDropped.WithLabelValues(requestType).Inc()
+}
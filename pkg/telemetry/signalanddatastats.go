@@ -24,6 +24,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/livekit/livekit-server/pkg/config"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/utils"
 )
@@ -43,6 +44,12 @@ type TrafficTotals struct {
 	SendMessages uint32
 	RecvBytes    uint64
 	RecvMessages uint32
+	// received, but dropped without processing
+	RecvDroppedBytes    uint64
+	RecvDroppedMessages uint32
+	// received, but not forwarded by policy
+	RecvFilteredBytes    uint64
+	RecvFilteredMessages uint32
 }
 
 // --------------------------------
@@ -55,21 +62,36 @@ type BytesTrackStats struct {
 	sendMessages, recvMessages           atomic.Uint32
 	totalSendBytes, totalRecvBytes       atomic.Uint64
 	totalSendMessages, totalRecvMessages atomic.Uint32
+	recvDroppedMessages                  atomic.Uint32
+	totalRecvDroppedBytes                atomic.Uint64
+	totalRecvDroppedMessages             atomic.Uint32
+	totalRecvFilteredBytes               atomic.Uint64
+	totalRecvFilteredMessages            atomic.Uint32
+	lastActivityAt                       atomic.Int64
 	telemetry                            TelemetryService
+	jobScheduler                         *sutils.JobScheduler
+	reportJob                            *sutils.ScheduledJob
 	done                                 core.Fuse
 }
 
-func NewBytesTrackStats(trackID livekit.TrackID, pID livekit.ParticipantID, telemetry TelemetryService) *BytesTrackStats {
+func NewBytesTrackStats(
+	trackID livekit.TrackID,
+	pID livekit.ParticipantID,
+	telemetry TelemetryService,
+	jobScheduler *sutils.JobScheduler,
+) *BytesTrackStats {
 	s := &BytesTrackStats{
-		trackID:   trackID,
-		pID:       pID,
-		telemetry: telemetry,
+		trackID:      trackID,
+		pID:          pID,
+		telemetry:    telemetry,
+		jobScheduler: jobScheduler,
 	}
-	go s.reporter()
+	s.startReporting()
 	return s
 }
 
 func (s *BytesTrackStats) AddBytes(bytes uint64, isSend bool) {
+	s.lastActivityAt.Store(time.Now().UnixNano())
 	if isSend {
 		s.send.Add(bytes)
 		s.sendMessages.Inc()
@@ -83,18 +105,43 @@ func (s *BytesTrackStats) AddBytes(bytes uint64, isSend bool) {
 	}
 }
 
+// AddDroppedBytes records a received message which was dropped, it should also be added with AddBytes
+func (s *BytesTrackStats) AddDroppedBytes(bytes uint64) {
+	s.recvDroppedMessages.Inc()
+	s.totalRecvDroppedBytes.Add(bytes)
+	s.totalRecvDroppedMessages.Inc()
+}
+
+// AddFilteredBytes records a received message which was not forwarded by policy, it should also be added with AddBytes
+func (s *BytesTrackStats) AddFilteredBytes(bytes uint64) {
+	s.totalRecvFilteredBytes.Add(bytes)
+	s.totalRecvFilteredMessages.Inc()
+}
+
 func (s *BytesTrackStats) GetTrafficTotals() *TrafficTotals {
 	return &TrafficTotals{
-		At:           time.Now(),
-		SendBytes:    s.totalSendBytes.Load(),
-		SendMessages: s.totalSendMessages.Load(),
-		RecvBytes:    s.totalRecvBytes.Load(),
-		RecvMessages: s.totalRecvMessages.Load(),
+		At:                   time.Now(),
+		SendBytes:            s.totalSendBytes.Load(),
+		SendMessages:         s.totalSendMessages.Load(),
+		RecvBytes:            s.totalRecvBytes.Load(),
+		RecvMessages:         s.totalRecvMessages.Load(),
+		RecvDroppedBytes:     s.totalRecvDroppedBytes.Load(),
+		RecvDroppedMessages:  s.totalRecvDroppedMessages.Load(),
+		RecvFilteredBytes:    s.totalRecvFilteredBytes.Load(),
+		RecvFilteredMessages: s.totalRecvFilteredMessages.Load(),
 	}
 }
 
+// LastActivityAt returns time of last send or receive, zero if there has not been any
+func (s *BytesTrackStats) LastActivityAt() time.Time {
+	if at := s.lastActivityAt.Load(); at != 0 {
+		return time.Unix(0, at)
+	}
+	return time.Time{}
+}
+
 func (s *BytesTrackStats) Stop() {
-	s.done.Break()
+	s.done.Once(s.stopReporting)
 }
 
 func (s *BytesTrackStats) report() {
@@ -104,6 +151,7 @@ func (s *BytesTrackStats) report() {
 				{
 					PrimaryBytes:   recv,
 					PrimaryPackets: s.recvMessages.Swap(0),
+					PacketsLost:    s.recvDroppedMessages.Swap(0),
 				},
 			},
 		})
@@ -121,21 +169,18 @@ func (s *BytesTrackStats) report() {
 	}
 }
 
-func (s *BytesTrackStats) reporter() {
-	ticker := time.NewTicker(config.TelemetryNonMediaStatsUpdateInterval)
-	defer func() {
-		ticker.Stop()
-		s.report()
-	}()
+func (s *BytesTrackStats) startReporting() {
+	s.reportJob = s.jobScheduler.Schedule(
+		string(s.trackID),
+		config.TelemetryNonMediaStatsUpdateInterval,
+		true,
+		s.report,
+	)
+}
 
-	for {
-		select {
-		case <-s.done.Watch():
-			return
-		case <-ticker.C:
-			s.report()
-		}
-	}
+func (s *BytesTrackStats) stopReporting() {
+	s.reportJob.Stop()
+	s.report()
 }
 
 // -----------------------------------------------------------------------
@@ -149,10 +194,11 @@ type BytesSignalStats struct {
 	pi *livekit.ParticipantInfo
 }
 
-func NewBytesSignalStats(ctx context.Context, telemetry TelemetryService) *BytesSignalStats {
+func NewBytesSignalStats(ctx context.Context, telemetry TelemetryService, jobScheduler *sutils.JobScheduler) *BytesSignalStats {
 	return &BytesSignalStats{
 		BytesTrackStats: BytesTrackStats{
-			telemetry: telemetry,
+			telemetry:    telemetry,
+			jobScheduler: jobScheduler,
 		},
 		ctx: ctx,
 	}
@@ -182,8 +228,22 @@ func (s *BytesSignalStats) ResolveParticipant(pi *livekit.ParticipantInfo) {
 	}
 }
 
+func (s *BytesSignalStats) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done.Once(func() {
+		if s.reportJob == nil {
+			return
+		}
+
+		s.stopReporting()
+		s.telemetry.ParticipantLeft(s.ctx, s.ri, s.pi, false)
+	})
+}
+
 func (s *BytesSignalStats) maybeStart() {
-	if s.ri == nil || s.pi == nil {
+	if s.ri == nil || s.pi == nil || s.done.IsBroken() {
 		return
 	}
 
@@ -191,12 +251,7 @@ func (s *BytesSignalStats) maybeStart() {
 	s.trackID = BytesTrackIDForParticipantID(BytesTrackTypeSignal, s.pID)
 
 	s.telemetry.ParticipantJoined(s.ctx, s.ri, s.pi, nil, nil, false)
-	go s.reporter()
-}
-
-func (s *BytesSignalStats) reporter() {
-	s.BytesTrackStats.reporter()
-	s.telemetry.ParticipantLeft(s.ctx, s.ri, s.pi, false)
+	s.startReporting()
 }
 
 // -----------------------------------------------------------------------
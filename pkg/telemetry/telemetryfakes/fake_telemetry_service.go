@@ -83,6 +83,14 @@ type FakeTelemetryService struct {
 		arg4 *livekit.AnalyticsClientMeta
 		arg5 bool
 	}
+	ParticipantHeartbeatStub        func(context.Context, livekit.ParticipantID, livekit.ParticipantIdentity, *telemetry.ParticipantHeartbeat)
+	participantHeartbeatMutex       sync.RWMutex
+	participantHeartbeatArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.ParticipantID
+		arg3 livekit.ParticipantIdentity
+		arg4 *telemetry.ParticipantHeartbeat
+	}
 	ParticipantJoinedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta, bool)
 	participantJoinedMutex       sync.RWMutex
 	participantJoinedArgsForCall []struct {
@@ -101,6 +109,14 @@ type FakeTelemetryService struct {
 		arg3 *livekit.ParticipantInfo
 		arg4 bool
 	}
+	ParticipantRTCPWriteFailedStub        func(context.Context, livekit.ParticipantID, livekit.SignalTarget, string)
+	participantRTCPWriteFailedMutex       sync.RWMutex
+	participantRTCPWriteFailedArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.ParticipantID
+		arg3 livekit.SignalTarget
+		arg4 string
+	}
 	ParticipantResumedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, livekit.NodeID, livekit.ReconnectReason)
 	participantResumedMutex       sync.RWMutex
 	participantResumedArgsForCall []struct {
@@ -647,6 +663,41 @@ func (fake *FakeTelemetryService) ParticipantActiveArgsForCall(i int) (context.C
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
+func (fake *FakeTelemetryService) ParticipantHeartbeat(arg1 context.Context, arg2 livekit.ParticipantID, arg3 livekit.ParticipantIdentity, arg4 *telemetry.ParticipantHeartbeat) {
+	fake.participantHeartbeatMutex.Lock()
+	fake.participantHeartbeatArgsForCall = append(fake.participantHeartbeatArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.ParticipantID
+		arg3 livekit.ParticipantIdentity
+		arg4 *telemetry.ParticipantHeartbeat
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ParticipantHeartbeatStub
+	fake.recordInvocation("ParticipantHeartbeat", []interface{}{arg1, arg2, arg3, arg4})
+	fake.participantHeartbeatMutex.Unlock()
+	if stub != nil {
+		fake.ParticipantHeartbeatStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeTelemetryService) ParticipantHeartbeatCallCount() int {
+	fake.participantHeartbeatMutex.RLock()
+	defer fake.participantHeartbeatMutex.RUnlock()
+	return len(fake.participantHeartbeatArgsForCall)
+}
+
+func (fake *FakeTelemetryService) ParticipantHeartbeatCalls(stub func(context.Context, livekit.ParticipantID, livekit.ParticipantIdentity, *telemetry.ParticipantHeartbeat)) {
+	fake.participantHeartbeatMutex.Lock()
+	defer fake.participantHeartbeatMutex.Unlock()
+	fake.ParticipantHeartbeatStub = stub
+}
+
+func (fake *FakeTelemetryService) ParticipantHeartbeatArgsForCall(i int) (context.Context, livekit.ParticipantID, livekit.ParticipantIdentity, *telemetry.ParticipantHeartbeat) {
+	fake.participantHeartbeatMutex.RLock()
+	defer fake.participantHeartbeatMutex.RUnlock()
+	argsForCall := fake.participantHeartbeatArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
 func (fake *FakeTelemetryService) ParticipantJoined(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 *livekit.ClientInfo, arg5 *livekit.AnalyticsClientMeta, arg6 bool) {
 	fake.participantJoinedMutex.Lock()
 	fake.participantJoinedArgsForCall = append(fake.participantJoinedArgsForCall, struct {
@@ -719,6 +770,41 @@ func (fake *FakeTelemetryService) ParticipantLeftArgsForCall(i int) (context.Con
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
+func (fake *FakeTelemetryService) ParticipantRTCPWriteFailed(arg1 context.Context, arg2 livekit.ParticipantID, arg3 livekit.SignalTarget, arg4 string) {
+	fake.participantRTCPWriteFailedMutex.Lock()
+	fake.participantRTCPWriteFailedArgsForCall = append(fake.participantRTCPWriteFailedArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.ParticipantID
+		arg3 livekit.SignalTarget
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ParticipantRTCPWriteFailedStub
+	fake.recordInvocation("ParticipantRTCPWriteFailed", []interface{}{arg1, arg2, arg3, arg4})
+	fake.participantRTCPWriteFailedMutex.Unlock()
+	if stub != nil {
+		fake.ParticipantRTCPWriteFailedStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeTelemetryService) ParticipantRTCPWriteFailedCallCount() int {
+	fake.participantRTCPWriteFailedMutex.RLock()
+	defer fake.participantRTCPWriteFailedMutex.RUnlock()
+	return len(fake.participantRTCPWriteFailedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) ParticipantRTCPWriteFailedCalls(stub func(context.Context, livekit.ParticipantID, livekit.SignalTarget, string)) {
+	fake.participantRTCPWriteFailedMutex.Lock()
+	defer fake.participantRTCPWriteFailedMutex.Unlock()
+	fake.ParticipantRTCPWriteFailedStub = stub
+}
+
+func (fake *FakeTelemetryService) ParticipantRTCPWriteFailedArgsForCall(i int) (context.Context, livekit.ParticipantID, livekit.SignalTarget, string) {
+	fake.participantRTCPWriteFailedMutex.RLock()
+	defer fake.participantRTCPWriteFailedMutex.RUnlock()
+	argsForCall := fake.participantRTCPWriteFailedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
 func (fake *FakeTelemetryService) ParticipantResumed(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 livekit.NodeID, arg5 livekit.ReconnectReason) {
 	fake.participantResumedMutex.Lock()
 	fake.participantResumedArgsForCall = append(fake.participantResumedArgsForCall, struct {
@@ -1443,10 +1529,14 @@ func (fake *FakeTelemetryService) Invocations() map[string][][]interface{} {
 	defer fake.notifyEventMutex.RUnlock()
 	fake.participantActiveMutex.RLock()
 	defer fake.participantActiveMutex.RUnlock()
+	fake.participantHeartbeatMutex.RLock()
+	defer fake.participantHeartbeatMutex.RUnlock()
 	fake.participantJoinedMutex.RLock()
 	defer fake.participantJoinedMutex.RUnlock()
 	fake.participantLeftMutex.RLock()
 	defer fake.participantLeftMutex.RUnlock()
+	fake.participantRTCPWriteFailedMutex.RLock()
+	defer fake.participantRTCPWriteFailedMutex.RUnlock()
 	fake.participantResumedMutex.RLock()
 	defer fake.participantResumedMutex.RUnlock()
 	fake.roomEndedMutex.RLock()
@@ -64,6 +64,10 @@ type TelemetryService interface {
 	TrackPublishedUpdate(ctx context.Context, participantID livekit.ParticipantID, track *livekit.TrackInfo)
 	// TrackMaxSubscribedVideoQuality - publisher is notified of the max quality subscribers desire
 	TrackMaxSubscribedVideoQuality(ctx context.Context, participantID livekit.ParticipantID, track *livekit.TrackInfo, mime string, maxQuality livekit.VideoQuality)
+	// ParticipantRTCPWriteFailed - RTCP writes on a participant transport have been failing persistently
+	ParticipantRTCPWriteFailed(ctx context.Context, participantID livekit.ParticipantID, target livekit.SignalTarget, category string)
+	// ParticipantHeartbeat - periodic snapshot of participant state, when enabled
+	ParticipantHeartbeat(ctx context.Context, participantID livekit.ParticipantID, identity livekit.ParticipantIdentity, heartbeat *ParticipantHeartbeat)
 	TrackPublishRTPStats(ctx context.Context, participantID livekit.ParticipantID, trackID livekit.TrackID, mimeType string, layer int, stats *livekit.RTPStats)
 	TrackSubscribeRTPStats(ctx context.Context, participantID livekit.ParticipantID, trackID livekit.TrackID, mimeType string, stats *livekit.RTPStats)
 	EgressStarted(ctx context.Context, info *livekit.EgressInfo)
@@ -0,0 +1,304 @@
+/*
+ * Copyright 2024 LiveKit, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"container/heap"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/logger"
+)
+
+const (
+	defaultLoadCheckInterval = time.Second
+)
+
+type JobSchedulerParams struct {
+	// load (0.0 - 1.0) above which intervals of sheddable jobs are lengthened, 0 disables shedding
+	ShedLoadThreshold float64
+	// factor by which intervals of sheddable jobs are lengthened at full load
+	MaxIntervalScale float64
+	// how often load is checked, defaults to defaultLoadCheckInterval
+	LoadCheckInterval time.Duration
+	// maximum number of jobs running at the same time, defaults to number of CPUs
+	MaxConcurrentJobs int
+	GetLoad           func() float64
+	Logger            logger.Logger
+}
+
+// JobScheduler runs periodic jobs of a node.
+//
+// A single worker sleeps till the next job is due and hands due jobs to a bounded
+// number of goroutines, so that a slow job does not delay other jobs. A job is
+// scheduled again only after its run completes, runs of a job never overlap. The
+// worker exits when there are no jobs and is restarted by the next Schedule.
+//
+// Runs of a job are offset by a phase derived from its key so that jobs registered
+// with the same interval do not all fire together. Under load, intervals of sheddable
+// jobs are lengthened, jobs on the media path should be registered as not sheddable.
+type JobScheduler struct {
+	params JobSchedulerParams
+
+	intervalScale atomic.Float64
+
+	lock    sync.Mutex
+	jobs    jobHeap
+	running bool
+	wake    chan struct{}
+	slots   chan struct{}
+	stopped core.Fuse
+}
+
+func NewJobScheduler(params JobSchedulerParams) *JobScheduler {
+	if params.LoadCheckInterval == 0 {
+		params.LoadCheckInterval = defaultLoadCheckInterval
+	}
+	if params.MaxConcurrentJobs <= 0 {
+		params.MaxConcurrentJobs = runtime.NumCPU()
+	}
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+
+	s := &JobScheduler{
+		params: params,
+		wake:   make(chan struct{}, 1),
+		slots:  make(chan struct{}, params.MaxConcurrentJobs),
+	}
+	s.intervalScale.Store(1.0)
+
+	if params.GetLoad != nil && params.ShedLoadThreshold > 0 && params.MaxIntervalScale > 1.0 {
+		s.Schedule("load-check", params.LoadCheckInterval, false, s.checkLoad)
+	}
+	return s
+}
+
+func (s *JobScheduler) Stop() {
+	s.stopped.Break()
+}
+
+// Schedule runs fn every interval till the returned job is stopped
+func (s *JobScheduler) Schedule(key string, interval time.Duration, sheddable bool, fn func()) *ScheduledJob {
+	j := &ScheduledJob{
+		key:       key,
+		interval:  interval,
+		sheddable: sheddable,
+		fn:        fn,
+		next:      time.Now().Add(PhaseOffset(key, interval)),
+	}
+	s.push(j)
+	return j
+}
+
+func (s *JobScheduler) IntervalScale() float64 {
+	return s.intervalScale.Load()
+}
+
+func (s *JobScheduler) push(j *ScheduledJob) {
+	s.lock.Lock()
+	heap.Push(&s.jobs, j)
+	if !s.running {
+		s.running = true
+		s.lock.Unlock()
+
+		go s.worker()
+		return
+	}
+	s.lock.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// popDue returns the jobs which are due and how long to wait for the next one, stopped jobs are dropped
+func (s *JobScheduler) popDue(now time.Time) ([]*ScheduledJob, time.Duration, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var due []*ScheduledJob
+	for len(s.jobs) > 0 {
+		j := s.jobs[0]
+		if j.stopped.IsBroken() {
+			heap.Pop(&s.jobs)
+			continue
+		}
+		if j.next.After(now) {
+			return due, j.next.Sub(now), true
+		}
+		due = append(due, heap.Pop(&s.jobs).(*ScheduledJob))
+	}
+
+	if len(due) == 0 {
+		s.running = false
+		return nil, 0, false
+	}
+	return due, 0, true
+}
+
+func (s *JobScheduler) worker() {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		if s.stopped.IsBroken() {
+			s.lock.Lock()
+			s.running = false
+			s.lock.Unlock()
+			return
+		}
+
+		due, wait, ok := s.popDue(time.Now())
+		if !ok {
+			return
+		}
+
+		for _, j := range due {
+			if j.stopped.IsBroken() {
+				continue
+			}
+
+			// wait for a free slot, due jobs are delayed only when all slots are busy
+			select {
+			case s.slots <- struct{}{}:
+				go s.run(j)
+			case <-s.stopped.Watch():
+			}
+		}
+		if len(due) != 0 {
+			continue
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-s.stopped.Watch():
+		case <-s.wake:
+		case <-timer.C:
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+	}
+}
+
+func (s *JobScheduler) run(j *ScheduledJob) {
+	j.fn()
+	<-s.slots
+
+	interval := j.interval
+	if j.sheddable {
+		interval = time.Duration(float64(interval) * s.intervalScale.Load())
+	}
+	// keep phase, but do not try to catch up on runs missed due to a slow job
+	j.next = j.next.Add(interval)
+	if now := time.Now(); j.next.Before(now) {
+		j.next = now
+	}
+	s.push(j)
+}
+
+func (s *JobScheduler) checkLoad() {
+	load := s.params.GetLoad()
+	scale := IntervalScaleForLoad(load, s.params.ShedLoadThreshold, s.params.MaxIntervalScale)
+	prevScale := s.intervalScale.Swap(scale)
+	if prevScale == 1.0 && scale > 1.0 {
+		s.params.Logger.Infow("shedding periodic jobs", "load", load, "intervalScale", scale)
+	} else if prevScale > 1.0 && scale == 1.0 {
+		s.params.Logger.Infow("stopped shedding periodic jobs", "load", load)
+	}
+}
+
+// ------------------------------------------------
+
+type ScheduledJob struct {
+	key       string
+	interval  time.Duration
+	sheddable bool
+	fn        func()
+
+	// only accessed by the scheduler
+	next time.Time
+
+	stopped core.Fuse
+}
+
+func (j *ScheduledJob) Stop() {
+	if j == nil {
+		return
+	}
+
+	j.stopped.Break()
+}
+
+// ------------------------------------------------
+
+type jobHeap []*ScheduledJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool { return h[i].next.Before(h[j].next) }
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x any) {
+	*h = append(*h, x.(*ScheduledJob))
+}
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return j
+}
+
+// ------------------------------------------------
+
+// PhaseOffset returns a deterministic offset within interval for the given key
+func PhaseOffset(key string, interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
+
+// IntervalScaleForLoad scales linearly from 1.0 at threshold to maxScale at full load
+func IntervalScaleForLoad(load float64, threshold float64, maxScale float64) float64 {
+	if threshold <= 0 || threshold >= 1.0 || load <= threshold {
+		return 1.0
+	}
+	if load >= 1.0 {
+		return maxScale
+	}
+
+	return 1.0 + (maxScale-1.0)*(load-threshold)/(1.0-threshold)
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2024 LiveKit, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/utils"
+)
+
+func TestPhaseOffset(t *testing.T) {
+	interval := 5 * time.Second
+	offset := utils.PhaseOffset("PA_participant", interval)
+	require.Equal(t, offset, utils.PhaseOffset("PA_participant", interval))
+	require.GreaterOrEqual(t, offset, time.Duration(0))
+	require.Less(t, offset, interval)
+
+	require.Equal(t, time.Duration(0), utils.PhaseOffset("PA_participant", 0))
+}
+
+func TestIntervalScaleForLoad(t *testing.T) {
+	require.Equal(t, 1.0, utils.IntervalScaleForLoad(0.5, 0.8, 4))
+	require.Equal(t, 1.0, utils.IntervalScaleForLoad(0.8, 0.8, 4))
+	require.InDelta(t, 2.5, utils.IntervalScaleForLoad(0.9, 0.8, 4), 0.001)
+	require.Equal(t, 4.0, utils.IntervalScaleForLoad(1.0, 0.8, 4))
+
+	// shedding disabled
+	require.Equal(t, 1.0, utils.IntervalScaleForLoad(1.0, 0, 4))
+}
+
+func TestJobScheduler(t *testing.T) {
+	t.Run("runs till stopped", func(t *testing.T) {
+		s := utils.NewJobScheduler(utils.JobSchedulerParams{})
+		defer s.Stop()
+
+		var runs atomic.Int32
+		job := s.Schedule("job", 10*time.Millisecond, true, func() {
+			runs.Add(1)
+		})
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+		job.Stop()
+		time.Sleep(20 * time.Millisecond)
+		stoppedAt := runs.Load()
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, stoppedAt, runs.Load())
+	})
+
+	t.Run("runs jobs independently", func(t *testing.T) {
+		s := utils.NewJobScheduler(utils.JobSchedulerParams{})
+		defer s.Stop()
+
+		var fastRuns, slowRuns atomic.Int32
+		fast := s.Schedule("fast", 5*time.Millisecond, true, func() {
+			fastRuns.Add(1)
+		})
+		slow := s.Schedule("slow", 50*time.Millisecond, true, func() {
+			slowRuns.Add(1)
+		})
+		defer slow.Stop()
+
+		require.Eventually(t, func() bool { return slowRuns.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		require.Greater(t, fastRuns.Load(), slowRuns.Load())
+
+		fast.Stop()
+		time.Sleep(10 * time.Millisecond)
+		stoppedAt := fastRuns.Load()
+		slowAt := slowRuns.Load()
+		require.Eventually(t, func() bool { return slowRuns.Load() > slowAt }, time.Second, 5*time.Millisecond)
+		require.Equal(t, stoppedAt, fastRuns.Load())
+
+		// scheduling after all jobs have stopped restarts the worker
+		slow.Stop()
+		var runs atomic.Int32
+		job := s.Schedule("again", 5*time.Millisecond, true, func() {
+			runs.Add(1)
+		})
+		defer job.Stop()
+		require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("slow job does not delay others", func(t *testing.T) {
+		s := utils.NewJobScheduler(utils.JobSchedulerParams{MaxConcurrentJobs: 2})
+		defer s.Stop()
+
+		release := make(chan struct{})
+		var slowRuns, concurrentRuns, maxConcurrentRuns atomic.Int32
+		slow := s.Schedule("slow", time.Millisecond, true, func() {
+			if n := concurrentRuns.Add(1); n > maxConcurrentRuns.Load() {
+				maxConcurrentRuns.Store(n)
+			}
+			slowRuns.Add(1)
+			<-release
+			concurrentRuns.Add(-1)
+		})
+		defer slow.Stop()
+
+		var fastRuns atomic.Int32
+		fast := s.Schedule("fast", 5*time.Millisecond, true, func() {
+			fastRuns.Add(1)
+		})
+		defer fast.Stop()
+
+		require.Eventually(t, func() bool { return fastRuns.Load() >= 3 }, time.Second, 5*time.Millisecond)
+		// runs of a job do not overlap
+		require.Equal(t, int32(1), slowRuns.Load())
+		require.Equal(t, int32(1), maxConcurrentRuns.Load())
+
+		close(release)
+		require.Eventually(t, func() bool { return slowRuns.Load() >= 3 }, time.Second, 5*time.Millisecond)
+		require.Equal(t, int32(1), maxConcurrentRuns.Load())
+	})
+
+	t.Run("sheds under load", func(t *testing.T) {
+		var load atomic.Value
+		load.Store(0.95)
+		s := utils.NewJobScheduler(utils.JobSchedulerParams{
+			ShedLoadThreshold: 0.8,
+			MaxIntervalScale:  4,
+			LoadCheckInterval: 5 * time.Millisecond,
+			GetLoad:           func() float64 { return load.Load().(float64) },
+		})
+		defer s.Stop()
+
+		require.Eventually(t, func() bool { return s.IntervalScale() > 1.0 }, time.Second, 5*time.Millisecond)
+
+		load.Store(0.5)
+		require.Eventually(t, func() bool { return s.IntervalScale() == 1.0 }, time.Second, 5*time.Millisecond)
+	})
+}
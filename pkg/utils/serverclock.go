@@ -0,0 +1,35 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "time"
+
+// ServerClock is the time source of timestamps sent to clients. One clock is created per node and
+// shared by sender reports sent to subscribers and signal pong responses, so that clients relating
+// media and signalling to server time use the same timeline.
+type ServerClock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+// NewServerClock returns a server clock backed by system time
+func NewServerClock() ServerClock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var ErrWireMalformed = errors.New("malformed wire data")
+
+// WireEncoder encodes fields in protobuf wire format, for internal state which
+// does not have a protocol message. Zero values are omitted like proto3 scalars.
+type WireEncoder struct {
+	b []byte
+}
+
+func (e *WireEncoder) Uint64(num protowire.Number, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.b = protowire.AppendTag(e.b, num, protowire.VarintType)
+	e.b = protowire.AppendVarint(e.b, v)
+}
+
+func (e *WireEncoder) Int64(num protowire.Number, v int64) {
+	if v == 0 {
+		return
+	}
+	e.b = protowire.AppendTag(e.b, num, protowire.VarintType)
+	e.b = protowire.AppendVarint(e.b, protowire.EncodeZigZag(v))
+}
+
+func (e *WireEncoder) Bool(num protowire.Number, v bool) {
+	if !v {
+		return
+	}
+	e.b = protowire.AppendTag(e.b, num, protowire.VarintType)
+	e.b = protowire.AppendVarint(e.b, protowire.EncodeBool(v))
+}
+
+func (e *WireEncoder) Float64(num protowire.Number, v float64) {
+	if v == 0 {
+		return
+	}
+	e.b = protowire.AppendTag(e.b, num, protowire.Fixed64Type)
+	e.b = protowire.AppendFixed64(e.b, math.Float64bits(v))
+}
+
+// Time encodes as nanoseconds since epoch, zero time is omitted
+func (e *WireEncoder) Time(num protowire.Number, v time.Time) {
+	if v.IsZero() {
+		return
+	}
+	e.Int64(num, v.UnixNano())
+}
+
+func (e *WireEncoder) Bytes(num protowire.Number, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	e.b = protowire.AppendTag(e.b, num, protowire.BytesType)
+	e.b = protowire.AppendBytes(e.b, v)
+}
+
+func (e *WireEncoder) String(num protowire.Number, v string) {
+	e.Bytes(num, []byte(v))
+}
+
+// Message encodes a nested message, unlike Bytes, it is present even when empty
+func (e *WireEncoder) Message(num protowire.Number, v []byte) {
+	e.b = protowire.AppendTag(e.b, num, protowire.BytesType)
+	e.b = protowire.AppendBytes(e.b, v)
+}
+
+func (e *WireEncoder) Encoded() []byte {
+	return e.b
+}
+
+// ---------------------------------------------
+
+type WireField struct {
+	typ   protowire.Type
+	value uint64
+	bytes []byte
+}
+
+func (f WireField) Uint64() uint64 {
+	return f.value
+}
+
+func (f WireField) Int64() int64 {
+	return protowire.DecodeZigZag(f.value)
+}
+
+func (f WireField) Bool() bool {
+	return protowire.DecodeBool(f.value)
+}
+
+func (f WireField) Float64() float64 {
+	return math.Float64frombits(f.value)
+}
+
+func (f WireField) Time() time.Time {
+	return time.Unix(0, f.Int64())
+}
+
+func (f WireField) Bytes() []byte {
+	return f.bytes
+}
+
+func (f WireField) String() string {
+	return string(f.bytes)
+}
+
+// DecodeWire calls fn for every field encoded with WireEncoder (or any protobuf encoder).
+// Groups and fixed32 fields are skipped so that unknown fields do not fail decoding.
+func DecodeWire(data []byte, fn func(num protowire.Number, field WireField) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return ErrWireMalformed
+		}
+		data = data[n:]
+
+		field := WireField{typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			field.value, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed64Type:
+			field.value, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n >= 0 {
+				data = data[n:]
+				continue
+			}
+		}
+		if n < 0 {
+			return ErrWireMalformed
+		}
+		data = data[n:]
+
+		if err := fn(num, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}